@@ -37,21 +37,15 @@ func NewClient(uri string) Client {
 }
 
 func (c *client) Readiness(ctx context.Context, tags []string, options ...rpc.Option) (*APIReply, error) {
-	res := &APIReply{}
-	err := c.requester.SendRequest(ctx, "health.readiness", &APIArgs{Tags: tags}, res, options...)
-	return res, err
+	return readinessMethod.Call(ctx, c.requester, &APIArgs{Tags: tags}, options...)
 }
 
 func (c *client) Health(ctx context.Context, tags []string, options ...rpc.Option) (*APIReply, error) {
-	res := &APIReply{}
-	err := c.requester.SendRequest(ctx, "health.health", &APIArgs{Tags: tags}, res, options...)
-	return res, err
+	return healthMethod.Call(ctx, c.requester, &APIArgs{Tags: tags}, options...)
 }
 
 func (c *client) Liveness(ctx context.Context, tags []string, options ...rpc.Option) (*APIReply, error) {
-	res := &APIReply{}
-	err := c.requester.SendRequest(ctx, "health.liveness", &APIArgs{Tags: tags}, res, options...)
-	return res, err
+	return livenessMethod.Call(ctx, c.requester, &APIArgs{Tags: tags}, options...)
 }
 
 // AwaitReady polls the node every [freq] until the node reports ready.
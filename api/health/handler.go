@@ -40,7 +40,7 @@ func NewGetAndPostHandler(log logging.Logger, reporter Reporter) (http.Handler,
 			log:    log,
 			health: reporter,
 		},
-		"health",
+		serviceName,
 	)
 	return handler, err
 }
@@ -9,6 +9,22 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+const serviceName = "health"
+
+// The methods of [Service], which [client] calls.
+var (
+	readinessMethod = rpc.Method[APIArgs, APIReply]{Name: serviceName + ".readiness"}
+	healthMethod    = rpc.Method[APIArgs, APIReply]{Name: serviceName + ".health"}
+	livenessMethod  = rpc.Method[APIArgs, APIReply]{Name: serviceName + ".liveness"}
+
+	methods = []rpc.Describer{
+		readinessMethod,
+		healthMethod,
+		livenessMethod,
+	}
 )
 
 type Service struct {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
 func TestServiceResponses(t *testing.T) {
@@ -223,3 +224,7 @@ func TestServiceTagResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceMethods(t *testing.T) {
+	require.NoError(t, rpc.VerifyService(serviceName, &Service{}, methods...))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -31,6 +32,7 @@ import (
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -57,6 +59,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/metervm"
 	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 	"github.com/ava-labs/avalanchego/vms/proposervm"
+	"github.com/ava-labs/avalanchego/vms/proposervm/scheduler"
 	"github.com/ava-labs/avalanchego/vms/tracedvm"
 
 	timetracker "github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -166,6 +169,13 @@ type chain struct {
 type ChainConfig struct {
 	Config  []byte
 	Upgrade []byte
+	// Gossip is the JSON encoded [common.GossipStrategyConfig] of the chain.
+	// If empty, the network samples the peers that gossip is pushed to.
+	Gossip []byte
+	// Sampling is the JSON encoded [smeng.AdaptiveSamplingConfig] of the
+	// chain. It's only used if [ManagerConfig.ConsensusAdaptiveSamplingEnabled]
+	// is true. If empty, every query samples K validators.
+	Sampling []byte
 }
 
 type ManagerConfig struct {
@@ -209,6 +219,12 @@ type ManagerConfig struct {
 
 	AcceptedFrontierGossipFrequency time.Duration
 	ConsensusAppConcurrency         int
+	// Max time to wait, when a chain stops, for the App messages being
+	// handled to finish before cancelling them.
+	ConsensusAppDrainTimeout time.Duration
+	// Experimental: enables the adaptive sampling of the chains that configure
+	// it in their [ChainConfig.Sampling].
+	ConsensusAdaptiveSamplingEnabled bool
 
 	// Max Time to spend fetching a container and its
 	// ancestors when responding to a GetAncestors
@@ -218,6 +234,12 @@ type ManagerConfig struct {
 	// This node will only consider the first [AncestorsMaxContainersReceived]
 	// containers in an ancestors message it receives.
 	BootstrapAncestorsMaxContainersReceived int
+	// Limits on the container requests that the bootstrapping chains of a
+	// subnet have outstanding at once.
+	BootstrapFetchSchedulerConfig common.FetchSchedulerConfig
+	// Limits the requests outstanding to each peer across all chains. Shared
+	// with the router, which releases requests once they're cleared.
+	RequestBudget common.RequestBudget
 
 	ApricotPhase4Time            time.Time
 	ApricotPhase4MinPChainHeight uint64
@@ -255,6 +277,12 @@ type manager struct {
 	// Key: Subnet's ID
 	// Value: Subnet description
 	subnets map[ids.ID]subnets.Subnet
+	// Key: Subnet's ID
+	// Value: Coordinates the requests of the Subnet's bootstrapping chains
+	fetchSchedulers map[ids.ID]common.FetchScheduler
+	// Key: Subnet's ID
+	// Value: Staggers the block building of the Subnet's snowman++ chains
+	buildCoordinators map[ids.ID]*scheduler.Coordinator
 
 	chainsLock sync.Mutex
 	// Key: Chain's ID
@@ -273,6 +301,8 @@ func New(config *ManagerConfig) Manager {
 		stakingSigner:          config.StakingTLSCert.PrivateKey.(crypto.Signer),
 		stakingCert:            staking.CertificateFromX509(config.StakingTLSCert.Leaf),
 		subnets:                make(map[ids.ID]subnets.Subnet),
+		fetchSchedulers:        make(map[ids.ID]common.FetchScheduler),
+		buildCoordinators:      make(map[ids.ID]*scheduler.Coordinator),
 		chains:                 make(map[ids.ID]handler.Handler),
 		chainsQueue:            buffer.NewUnboundedBlockingDeque[ChainParameters](initialQueueSize),
 		unblockChainCreatorCh:  make(chan struct{}),
@@ -580,6 +610,48 @@ func (m *manager) AddRegistrant(r Registrant) {
 	m.registrants = append(m.registrants, r)
 }
 
+// getFetchScheduler returns the fetch scheduler shared by the chains of
+// [subnetID], creating it if this is the first chain of the subnet. Returns
+// nil if there is no request budget to pick peers with.
+func (m *manager) getFetchScheduler(subnetID ids.ID) (common.FetchScheduler, error) {
+	if m.RequestBudget == nil {
+		return nil, nil
+	}
+
+	m.subnetsLock.Lock()
+	defer m.subnetsLock.Unlock()
+
+	if fetchScheduler, ok := m.fetchSchedulers[subnetID]; ok {
+		return fetchScheduler, nil
+	}
+	fetchScheduler, err := common.NewFetchScheduler(m.BootstrapFetchSchedulerConfig, m.RequestBudget)
+	if err != nil {
+		return nil, err
+	}
+	m.fetchSchedulers[subnetID] = fetchScheduler
+	return fetchScheduler, nil
+}
+
+// getBuildCoordinator returns the build coordinator shared by the snowman++
+// chains of [subnetID], creating it if this is the first chain of the subnet.
+// Returns nil if the subnet doesn't stagger block building.
+func (m *manager) getBuildCoordinator(subnetID ids.ID) *scheduler.Coordinator {
+	subnetCfg, ok := m.SubnetConfigs[subnetID]
+	if !ok || subnetCfg.ProposerBuildStagger == 0 {
+		return nil
+	}
+
+	m.subnetsLock.Lock()
+	defer m.subnetsLock.Unlock()
+
+	if coordinator, ok := m.buildCoordinators[subnetID]; ok {
+		return coordinator
+	}
+	coordinator := scheduler.NewCoordinator(subnetCfg.ProposerBuildStagger)
+	m.buildCoordinators[subnetID] = coordinator
+	return coordinator
+}
+
 // Create a DAG-based blockchain that uses Avalanche
 func (m *manager) createAvalancheChain(
 	ctx *snow.ConsensusContext,
@@ -621,6 +693,15 @@ func (m *manager) createAvalancheChain(
 		return nil, err
 	}
 
+	chainConfig, err := m.getChainConfig(ctx.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching chain config: %w", err)
+	}
+	gossipStrategy, err := newGossipStrategy(chainConfig.Gossip)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create gossip strategy: %w", err)
+	}
+
 	// Passes messages from the avalanche engines to the network
 	avalancheMessageSender, err := sender.New(
 		ctx,
@@ -628,6 +709,8 @@ func (m *manager) createAvalancheChain(
 		m.Net,
 		m.ManagerConfig.Router,
 		m.TimeoutManager,
+		m.RequestBudget,
+		gossipStrategy,
 		p2p.EngineType_ENGINE_TYPE_AVALANCHE,
 		sb,
 	)
@@ -656,6 +739,8 @@ func (m *manager) createAvalancheChain(
 		m.Net,
 		m.ManagerConfig.Router,
 		m.TimeoutManager,
+		m.RequestBudget,
+		gossipStrategy,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
 		sb,
 	)
@@ -677,11 +762,6 @@ func (m *manager) createAvalancheChain(
 		return nil, fmt.Errorf("problem initializing event dispatcher: %w", err)
 	}
 
-	chainConfig, err := m.getChainConfig(ctx.ChainID)
-	if err != nil {
-		return nil, fmt.Errorf("error while fetching chain config: %w", err)
-	}
-
 	dagVM := vm
 	if m.MeterVMEnabled {
 		dagVM = metervm.NewVertexVM(dagVM)
@@ -777,6 +857,7 @@ func (m *manager) createAvalancheChain(
 		numHistoricalBlocks,
 		m.stakingSigner,
 		m.stakingCert,
+		m.getBuildCoordinator(ctx.SubnetID),
 	)
 
 	if m.MeterVMEnabled {
@@ -828,6 +909,7 @@ func (m *manager) createAvalancheChain(
 		msgChan,
 		m.AcceptedFrontierGossipFrequency,
 		m.ConsensusAppConcurrency,
+		m.ConsensusAppDrainTimeout,
 		m.ResourceTracker,
 		validators.UnhandledSubnetConnector, // avalanche chains don't use subnet connector
 		sb,
@@ -841,6 +923,11 @@ func (m *manager) createAvalancheChain(
 	startupTracker := tracker.NewStartup(connectedBeacons, (3*bootstrapWeight+3)/4)
 	vdrs.RegisterCallbackListener(ctx.SubnetID, startupTracker)
 
+	fetchScheduler, err := m.getFetchScheduler(ctx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating fetch scheduler: %w", err)
+	}
+
 	snowmanCommonCfg := common.Config{
 		Ctx:                            ctx,
 		Beacons:                        vdrs,
@@ -850,6 +937,7 @@ func (m *manager) createAvalancheChain(
 		Sender:                         snowmanMessageSender,
 		BootstrapTracker:               sb,
 		Timer:                          h,
+		FetchScheduler:                 fetchScheduler,
 		RetryBootstrap:                 m.RetryBootstrap,
 		RetryBootstrapWarnFrequency:    m.RetryBootstrapWarnFrequency,
 		MaxTimeGetAncestors:            m.BootstrapMaxTimeGetAncestors,
@@ -867,16 +955,22 @@ func (m *manager) createAvalancheChain(
 		snowmanConsensus = smcon.Trace(snowmanConsensus, m.Tracer)
 	}
 
+	adaptiveSampling, err := m.newAdaptiveSamplingConfig(chainConfig.Sampling, consensusParams)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create adaptive sampling config: %w", err)
+	}
+
 	// Create engine, bootstrapper and state-syncer in this order,
 	// to make sure start callbacks are duly initialized
 	snowmanEngineConfig := smeng.Config{
-		Ctx:           snowmanCommonCfg.Ctx,
-		AllGetsServer: snowGetHandler,
-		VM:            vmWrappingProposerVM,
-		Sender:        snowmanCommonCfg.Sender,
-		Validators:    vdrs,
-		Params:        consensusParams,
-		Consensus:     snowmanConsensus,
+		Ctx:              snowmanCommonCfg.Ctx,
+		AllGetsServer:    snowGetHandler,
+		VM:               vmWrappingProposerVM,
+		Sender:           snowmanCommonCfg.Sender,
+		Validators:       vdrs,
+		Params:           consensusParams,
+		Consensus:        snowmanConsensus,
+		AdaptiveSampling: adaptiveSampling,
 	}
 	snowmanEngine, err := smeng.New(snowmanEngineConfig)
 	if err != nil {
@@ -1016,6 +1110,15 @@ func (m *manager) createSnowmanChain(
 		return nil, err
 	}
 
+	chainConfig, err := m.getChainConfig(ctx.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching chain config: %w", err)
+	}
+	gossipStrategy, err := newGossipStrategy(chainConfig.Gossip)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create gossip strategy: %w", err)
+	}
+
 	// Passes messages from the consensus engine to the network
 	messageSender, err := sender.New(
 		ctx,
@@ -1023,6 +1126,8 @@ func (m *manager) createSnowmanChain(
 		m.Net,
 		m.ManagerConfig.Router,
 		m.TimeoutManager,
+		m.RequestBudget,
+		gossipStrategy,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
 		sb,
 	)
@@ -1093,11 +1198,6 @@ func (m *manager) createSnowmanChain(
 	}
 
 	// Initialize the ProposerVM and the vm wrapped inside it
-	chainConfig, err := m.getChainConfig(ctx.ChainID)
-	if err != nil {
-		return nil, fmt.Errorf("error while fetching chain config: %w", err)
-	}
-
 	var (
 		minBlockDelay       = proposervm.DefaultMinBlockDelay
 		numHistoricalBlocks = proposervm.DefaultNumHistoricalBlocks
@@ -1126,6 +1226,7 @@ func (m *manager) createSnowmanChain(
 		numHistoricalBlocks,
 		m.stakingSigner,
 		m.stakingCert,
+		m.getBuildCoordinator(ctx.SubnetID),
 	)
 
 	if m.MeterVMEnabled {
@@ -1177,6 +1278,7 @@ func (m *manager) createSnowmanChain(
 		msgChan,
 		m.AcceptedFrontierGossipFrequency,
 		m.ConsensusAppConcurrency,
+		m.ConsensusAppDrainTimeout,
 		m.ResourceTracker,
 		subnetConnector,
 		sb,
@@ -1190,6 +1292,11 @@ func (m *manager) createSnowmanChain(
 	startupTracker := tracker.NewStartup(connectedBeacons, (3*bootstrapWeight+3)/4)
 	beacons.RegisterCallbackListener(ctx.SubnetID, startupTracker)
 
+	fetchScheduler, err := m.getFetchScheduler(ctx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating fetch scheduler: %w", err)
+	}
+
 	commonCfg := common.Config{
 		Ctx:                            ctx,
 		Beacons:                        beacons,
@@ -1199,6 +1306,7 @@ func (m *manager) createSnowmanChain(
 		Sender:                         messageSender,
 		BootstrapTracker:               sb,
 		Timer:                          h,
+		FetchScheduler:                 fetchScheduler,
 		RetryBootstrap:                 m.RetryBootstrap,
 		RetryBootstrapWarnFrequency:    m.RetryBootstrapWarnFrequency,
 		MaxTimeGetAncestors:            m.BootstrapMaxTimeGetAncestors,
@@ -1217,17 +1325,23 @@ func (m *manager) createSnowmanChain(
 		consensus = smcon.Trace(consensus, m.Tracer)
 	}
 
+	adaptiveSampling, err := m.newAdaptiveSamplingConfig(chainConfig.Sampling, consensusParams)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create adaptive sampling config: %w", err)
+	}
+
 	// Create engine, bootstrapper and state-syncer in this order,
 	// to make sure start callbacks are duly initialized
 	engineConfig := smeng.Config{
-		Ctx:           commonCfg.Ctx,
-		AllGetsServer: snowGetHandler,
-		VM:            vm,
-		Sender:        commonCfg.Sender,
-		Validators:    vdrs,
-		Params:        consensusParams,
-		Consensus:     consensus,
-		PartialSync:   m.PartialSyncPrimaryNetwork && commonCfg.Ctx.ChainID == constants.PlatformChainID,
+		Ctx:              commonCfg.Ctx,
+		AllGetsServer:    snowGetHandler,
+		VM:               vm,
+		Sender:           commonCfg.Sender,
+		Validators:       vdrs,
+		Params:           consensusParams,
+		Consensus:        consensus,
+		PartialSync:      m.PartialSyncPrimaryNetwork && commonCfg.Ctx.ChainID == constants.PlatformChainID,
+		AdaptiveSampling: adaptiveSampling,
 	}
 	engine, err := smeng.New(engineConfig)
 	if err != nil {
@@ -1438,6 +1552,40 @@ func (m *manager) notifyRegistrants(name string, ctx *snow.ConsensusContext, vm
 	}
 }
 
+// newGossipStrategy returns the gossip strategy configured by [configBytes],
+// whose fields default to [common.DefaultGossipStrategyConfig], or nil if
+// [configBytes] is empty.
+func newGossipStrategy(configBytes []byte) (common.GossipStrategy, error) {
+	if len(configBytes) == 0 {
+		return nil, nil
+	}
+
+	config := common.DefaultGossipStrategyConfig
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return nil, err
+	}
+	return common.NewGossipStrategy(config)
+}
+
+// newAdaptiveSamplingConfig returns the adaptive sampling config of a chain
+// with [params] configured by [configBytes], whose fields default to
+// [smeng.DefaultAdaptiveSamplingConfig], or nil if adaptive sampling isn't
+// enabled or [configBytes] is empty.
+func (m *manager) newAdaptiveSamplingConfig(configBytes []byte, params snowball.Parameters) (*smeng.AdaptiveSamplingConfig, error) {
+	if !m.ConsensusAdaptiveSamplingEnabled || len(configBytes) == 0 {
+		return nil, nil
+	}
+
+	config := smeng.DefaultAdaptiveSamplingConfig
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Verify(params); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
 // getChainConfig returns value of a entry by looking at ID key and alias key
 // it first searches ID key, then falls back to it's corresponding primary alias
 func (m *manager) getChainConfig(id ids.ID) (ChainConfig, error) {
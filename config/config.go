@@ -25,12 +25,14 @@ import (
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ipcs"
+	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -52,13 +54,17 @@ import (
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/proposervm"
+
+	platformconfig "github.com/ava-labs/avalanchego/vms/platformvm/config"
 )
 
 const (
-	chainConfigFileName  = "config"
-	chainUpgradeFileName = "upgrade"
-	subnetConfigFileExt  = ".json"
-	ipResolutionTimeout  = 30 * time.Second
+	chainConfigFileName   = "config"
+	chainUpgradeFileName  = "upgrade"
+	chainGossipFileName   = "gossip"
+	chainSamplingFileName = "sampling"
+	subnetConfigFileExt   = ".json"
+	ipResolutionTimeout   = 30 * time.Second
 
 	ipcDeprecationMsg      = "IPC API is deprecated"
 	keystoreDeprecationMsg = "keystore API is deprecated"
@@ -85,6 +91,8 @@ var (
 	errStakeMaxConsumptionTooLarge            = fmt.Errorf("max stake consumption must be less than or equal to %d", reward.PercentDenominator)
 	errStakeMaxConsumptionBelowMin            = errors.New("stake max consumption can't be less than min stake consumption")
 	errStakeMintingPeriodBelowMin             = errors.New("stake minting period can't be less than max stake duration")
+	errInvalidValidatorChurnPeriod            = errors.New("validator churn period must be >= 0")
+	errMaxValidatorChurnTooLarge              = fmt.Errorf("max validator churn must be less than or equal to %d", reward.PercentDenominator)
 	errCannotTrackPrimaryNetwork              = errors.New("cannot track primary network")
 	errStakingKeyContentUnset                 = fmt.Errorf("%s key not set but %s set", StakingTLSKeyContentKey, StakingCertContentKey)
 	errStakingCertContentUnset                = fmt.Errorf("%s key set but %s not set", StakingTLSKeyContentKey, StakingCertContentKey)
@@ -94,6 +102,8 @@ var (
 	errCannotReadDirectory                    = errors.New("cannot read directory")
 	errUnmarshalling                          = errors.New("unmarshalling failed")
 	errFileDoesNotExist                       = errors.New("file does not exist")
+	errUnknownResponseType                    = errors.New("unknown response type")
+	errInvalidOpTimeout                       = errors.New("invalid op timeout")
 )
 
 func getConsensusConfig(v *viper.Viper) snowball.Parameters {
@@ -172,6 +182,14 @@ func getAPIAuthConfig(v *viper.Viper) (node.APIAuthConfig, error) {
 	return config, nil
 }
 
+func getValidatorsGRPCConfig(v *viper.Viper) node.ValidatorsGRPCConfig {
+	return node.ValidatorsGRPCConfig{
+		ValidatorsGRPCEnabled: v.GetBool(ValidatorsGRPCEnabledKey),
+		ValidatorsGRPCHost:    v.GetString(ValidatorsGRPCHostKey),
+		ValidatorsGRPCPort:    uint16(v.GetUint(ValidatorsGRPCPortKey)),
+	}
+}
+
 func getIPCConfig(v *viper.Viper) node.IPCConfig {
 	config := node.IPCConfig{
 		IPCAPIEnabled: v.GetBool(IpcAPIEnabledKey),
@@ -302,6 +320,69 @@ func getAdaptiveTimeoutConfig(v *viper.Viper) (timer.AdaptiveTimeoutConfig, erro
 	return config, nil
 }
 
+// getOpTimeoutConfigs returns the timeout configs of the response types whose
+// minimum or maximum timeout is overridden. The other fields of each config are
+// copied from [defaultConfig]. The initial timeout is clamped to be within the
+// overridden bounds.
+func getOpTimeoutConfigs(v *viper.Viper, defaultConfig timer.AdaptiveTimeoutConfig) (map[message.Op]timer.AdaptiveTimeoutConfig, error) {
+	responseOps := make(map[string]message.Op, len(message.FailedToResponseOps))
+	for _, op := range message.FailedToResponseOps {
+		responseOps[op.String()] = op
+	}
+
+	configs := make(map[message.Op]timer.AdaptiveTimeoutConfig)
+	getConfig := func(key, opName string) (timer.AdaptiveTimeoutConfig, message.Op, error) {
+		op, ok := responseOps[opName]
+		if !ok {
+			return timer.AdaptiveTimeoutConfig{}, 0, fmt.Errorf("%w %q in %q", errUnknownResponseType, opName, key)
+		}
+		config, ok := configs[op]
+		if !ok {
+			config = defaultConfig
+		}
+		return config, op, nil
+	}
+	for opName, timeoutStr := range v.GetStringMapString(NetworkOpMinimumTimeoutsKey) {
+		config, op, err := getConfig(NetworkOpMinimumTimeoutsKey, opName)
+		if err != nil {
+			return nil, err
+		}
+		config.MinimumTimeout, err = time.ParseDuration(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %q for %q: %w", NetworkOpMinimumTimeoutsKey, opName, err)
+		}
+		configs[op] = config
+	}
+	for opName, timeoutStr := range v.GetStringMapString(NetworkOpMaximumTimeoutsKey) {
+		config, op, err := getConfig(NetworkOpMaximumTimeoutsKey, opName)
+		if err != nil {
+			return nil, err
+		}
+		config.MaximumTimeout, err = time.ParseDuration(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %q for %q: %w", NetworkOpMaximumTimeoutsKey, opName, err)
+		}
+		configs[op] = config
+	}
+
+	for op, config := range configs {
+		switch {
+		case config.MinimumTimeout < 1:
+			return nil, fmt.Errorf("%w: %q for %q must be positive", errInvalidOpTimeout, NetworkOpMinimumTimeoutsKey, op)
+		case config.MinimumTimeout > config.MaximumTimeout:
+			return nil, fmt.Errorf("%w: %q for %q must be >= %q", errInvalidOpTimeout, NetworkOpMaximumTimeoutsKey, op, NetworkOpMinimumTimeoutsKey)
+		}
+		if config.InitialTimeout < config.MinimumTimeout {
+			config.InitialTimeout = config.MinimumTimeout
+		}
+		if config.InitialTimeout > config.MaximumTimeout {
+			config.InitialTimeout = config.MaximumTimeout
+		}
+		configs[op] = config
+	}
+	return configs, nil
+}
+
 func getGossipConfig(v *viper.Viper) subnets.GossipConfig {
 	return subnets.GossipConfig{
 		AcceptedFrontierValidatorSize:    uint(v.GetUint32(ConsensusGossipAcceptedFrontierValidatorSizeKey)),
@@ -527,6 +608,12 @@ func getBootstrapConfig(v *viper.Viper, networkID uint32) (node.BootstrapConfig,
 		BootstrapMaxTimeGetAncestors:            v.GetDuration(BootstrapMaxTimeGetAncestorsKey),
 		BootstrapAncestorsMaxContainersSent:     int(v.GetUint(BootstrapAncestorsMaxContainersSentKey)),
 		BootstrapAncestorsMaxContainersReceived: int(v.GetUint(BootstrapAncestorsMaxContainersReceivedKey)),
+		BootstrapFetchSchedulerConfig: common.FetchSchedulerConfig{
+			MaxOutstandingRequests: int(v.GetUint(BootstrapMaxOutstandingRequestsKey)),
+		},
+	}
+	if err := config.BootstrapFetchSchedulerConfig.Verify(); err != nil {
+		return node.BootstrapConfig{}, fmt.Errorf("invalid bootstrap fetch scheduler config: %w", err)
 	}
 
 	// TODO: Add a "BootstrappersKey" flag to more clearly enforce ID and IP
@@ -829,6 +916,12 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 		config.RewardConfig.MintingPeriod = v.GetDuration(StakeMintingPeriodKey)
 		config.RewardConfig.SupplyCap = v.GetUint64(StakeSupplyCapKey)
 		config.MinDelegationFee = v.GetUint32(MinDelegatorFeeKey)
+		config.ValidatorChurnPeriod = v.GetDuration(ValidatorChurnPeriodKey)
+		config.MaxValidatorChurn = v.GetUint64(MaxValidatorChurnKey)
+		config.SubnetValidatorChurn, err = getSubnetValidatorChurn(v)
+		if err != nil {
+			return node.StakingConfig{}, err
+		}
 		switch {
 		case config.UptimeRequirement < 0 || config.UptimeRequirement > 1:
 			return node.StakingConfig{}, errInvalidUptimeRequirement
@@ -846,6 +939,10 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 			return node.StakingConfig{}, errStakeMaxConsumptionBelowMin
 		case config.RewardConfig.MintingPeriod < config.MaxStakeDuration:
 			return node.StakingConfig{}, errStakeMintingPeriodBelowMin
+		case config.ValidatorChurnPeriod < 0:
+			return node.StakingConfig{}, errInvalidValidatorChurnPeriod
+		case config.MaxValidatorChurn > reward.PercentDenominator:
+			return node.StakingConfig{}, errMaxValidatorChurnTooLarge
 		}
 	} else {
 		config.StakingConfig = genesis.GetStakingConfig(networkID)
@@ -853,6 +950,34 @@ func getStakingConfig(v *viper.Viper, networkID uint32) (node.StakingConfig, err
 	return config, nil
 }
 
+// getSubnetValidatorChurn returns the churn limits of the subnets that are
+// configured to not use the default limit.
+func getSubnetValidatorChurn(v *viper.Viper) (map[ids.ID]platformconfig.ValidatorChurnConfig, error) {
+	if !v.IsSet(SubnetValidatorChurnContentKey) {
+		return nil, nil
+	}
+
+	contentB64 := v.GetString(SubnetValidatorChurnContentKey)
+	content, err := base64.StdEncoding.DecodeString(contentB64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode base64 content: %w", err)
+	}
+
+	var churnConfigs map[ids.ID]platformconfig.ValidatorChurnConfig
+	if err := json.Unmarshal(content, &churnConfigs); err != nil {
+		return nil, fmt.Errorf("could not unmarshal JSON: %w", err)
+	}
+	for subnetID, churnConfig := range churnConfigs {
+		switch {
+		case churnConfig.Period < 0:
+			return nil, fmt.Errorf("%w for subnet %s", errInvalidValidatorChurnPeriod, subnetID)
+		case churnConfig.MaxChurn > reward.PercentDenominator:
+			return nil, fmt.Errorf("%w for subnet %s", errMaxValidatorChurnTooLarge, subnetID)
+		}
+	}
+	return churnConfigs, nil
+}
+
 func getTxFeeConfig(v *viper.Viper, networkID uint32) genesis.TxFeeConfig {
 	if networkID != constants.MainnetID && networkID != constants.FujiID {
 		return genesis.TxFeeConfig{
@@ -1081,9 +1206,23 @@ func readChainConfigPath(chainConfigPath string) (map[string]chains.ChainConfig,
 			return chainConfigMap, err
 		}
 
+		// chainconfigdir/chainId/gossip.*
+		gossipData, err := storage.ReadFileWithName(chainDir, chainGossipFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+
+		// chainconfigdir/chainId/sampling.*
+		samplingData, err := storage.ReadFileWithName(chainDir, chainSamplingFileName)
+		if err != nil {
+			return chainConfigMap, err
+		}
+
 		chainConfigMap[dirInfo.Name()] = chains.ChainConfig{
-			Config:  configData,
-			Upgrade: upgradeData,
+			Config:   configData,
+			Upgrade:  upgradeData,
+			Gossip:   gossipData,
+			Sampling: samplingData,
 		}
 	}
 	return chainConfigMap, nil
@@ -1331,6 +1470,11 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 	if nodeConfig.ConsensusAppConcurrency <= 0 {
 		return node.Config{}, fmt.Errorf("%s must be > 0", ConsensusAppConcurrencyKey)
 	}
+	nodeConfig.ConsensusAppDrainTimeout = v.GetDuration(ConsensusAppDrainTimeoutKey)
+	if nodeConfig.ConsensusAppDrainTimeout < 0 {
+		return node.Config{}, fmt.Errorf("%q must be >= 0", ConsensusAppDrainTimeoutKey)
+	}
+	nodeConfig.ConsensusAdaptiveSamplingEnabled = v.GetBool(ConsensusAdaptiveSamplingEnabledKey)
 
 	nodeConfig.UseCurrentHeight = v.GetBool(ProposerVMUseCurrentHeightKey)
 
@@ -1376,6 +1520,9 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Validators gRPC
+	nodeConfig.ValidatorsGRPCConfig = getValidatorsGRPCConfig(v)
+
 	// Health
 	nodeConfig.HealthCheckFreq = v.GetDuration(HealthCheckFreqKey)
 	if nodeConfig.HealthCheckFreq < 0 {
@@ -1389,6 +1536,12 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 
 	// Router
 	nodeConfig.ConsensusRouter = &router.ChainRouter{}
+	nodeConfig.RequestBudgetConfig = common.RequestBudgetConfig{
+		MaxOutstandingRequestsPerPeer: int(v.GetUint(ConsensusMaxOutstandingRequestsPerPeerKey)),
+	}
+	if err := nodeConfig.RequestBudgetConfig.Verify(); err != nil {
+		return node.Config{}, fmt.Errorf("invalid request budget config: %w", err)
+	}
 	nodeConfig.RouterHealthConfig, err = getRouterHealthConfig(v, healthCheckAveragerHalflife)
 	if err != nil {
 		return node.Config{}, err
@@ -1402,6 +1555,10 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.OpTimeoutConfigs, err = getOpTimeoutConfigs(v, nodeConfig.AdaptiveTimeoutConfig)
+	if err != nil {
+		return node.Config{}, err
+	}
 
 	// Network Config
 	nodeConfig.NetworkConfig, err = getNetworkConfig(
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -19,15 +20,21 @@ import (
 
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/timer"
+
+	platformconfig "github.com/ava-labs/avalanchego/vms/platformvm/config"
 )
 
 func TestGetChainConfigsFromFiles(t *testing.T) {
 	tests := map[string]struct {
-		configs  map[string]string
-		upgrades map[string]string
-		expected map[string]chains.ChainConfig
+		configs   map[string]string
+		upgrades  map[string]string
+		gossips   map[string]string
+		samplings map[string]string
+		expected  map[string]chains.ChainConfig
 	}{
 		"no chain configs": {
 			configs:  map[string]string{},
@@ -61,6 +68,20 @@ func TestGetChainConfigsFromFiles(t *testing.T) {
 				return m
 			}(),
 		},
+		"gossip strategy": {
+			configs: map[string]string{"C": "hello"},
+			gossips: map[string]string{"C": `{"strategy":"push-pull"}`},
+			expected: map[string]chains.ChainConfig{
+				"C": {Config: []byte("hello"), Upgrade: []byte(nil), Gossip: []byte(`{"strategy":"push-pull"}`)},
+			},
+		},
+		"adaptive sampling": {
+			configs:   map[string]string{"C": "hello"},
+			samplings: map[string]string{"C": `{"minK":10}`},
+			expected: map[string]chains.ChainConfig{
+				"C": {Config: []byte("hello"), Upgrade: []byte(nil), Sampling: []byte(`{"minK":10}`)},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -79,6 +100,14 @@ func TestGetChainConfigsFromFiles(t *testing.T) {
 				chainDir := filepath.Join(chainsDir, key)
 				setupFile(t, chainDir, chainUpgradeFileName+".ex", value)
 			}
+			for key, value := range test.gossips {
+				chainDir := filepath.Join(chainsDir, key)
+				setupFile(t, chainDir, chainGossipFileName+".ex", value)
+			}
+			for key, value := range test.samplings {
+				chainDir := filepath.Join(chainsDir, key)
+				setupFile(t, chainDir, chainSamplingFileName+".ex", value)
+			}
 
 			v := setupViper(configFile)
 
@@ -555,6 +584,141 @@ func TestGetSubnetConfigsFromFlags(t *testing.T) {
 	}
 }
 
+func TestGetSubnetValidatorChurn(t *testing.T) {
+	subnetID, err := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		givenJSON   string
+		expected    map[ids.ID]platformconfig.ValidatorChurnConfig
+		expectedErr error
+	}{
+		"no configs": {
+			givenJSON: `{}`,
+			expected:  map[ids.ID]platformconfig.ValidatorChurnConfig{},
+		},
+		"correct config": {
+			givenJSON: `{
+				"2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i": {
+					"period": 3600000000000,
+					"maxChurn": 200000
+				}
+			}`,
+			expected: map[ids.ID]platformconfig.ValidatorChurnConfig{
+				subnetID: {
+					Period:   time.Hour,
+					MaxChurn: 200_000,
+				},
+			},
+		},
+		"negative period": {
+			givenJSON: `{
+				"2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i": {
+					"period": -1
+				}
+			}`,
+			expectedErr: errInvalidValidatorChurnPeriod,
+		},
+		"max churn too large": {
+			givenJSON: `{
+				"2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i": {
+					"maxChurn": 1000001
+				}
+			}`,
+			expectedErr: errMaxValidatorChurnTooLarge,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			v := setupViperFlags()
+			v.Set(SubnetValidatorChurnContentKey, base64.StdEncoding.EncodeToString([]byte(test.givenJSON)))
+
+			churnConfigs, err := getSubnetValidatorChurn(v)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.Equal(test.expected, churnConfigs)
+		})
+	}
+}
+
+func TestGetOpTimeoutConfigs(t *testing.T) {
+	defaultConfig := timer.AdaptiveTimeoutConfig{
+		InitialTimeout:     5 * time.Second,
+		MinimumTimeout:     2 * time.Second,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutHalflife:    5 * time.Minute,
+		TimeoutCoefficient: 2,
+	}
+
+	tests := map[string]struct {
+		minimums    map[string]string
+		maximums    map[string]string
+		expected    map[message.Op]timer.AdaptiveTimeoutConfig
+		expectedErr error
+	}{
+		"no overrides": {
+			expected: map[message.Op]timer.AdaptiveTimeoutConfig{},
+		},
+		"overrides": {
+			minimums: map[string]string{
+				"app_response": "10s",
+			},
+			maximums: map[string]string{
+				"app_response": "30s",
+				"chits":        "3s",
+			},
+			expected: map[message.Op]timer.AdaptiveTimeoutConfig{
+				message.AppResponseOp: {
+					InitialTimeout:     10 * time.Second,
+					MinimumTimeout:     10 * time.Second,
+					MaximumTimeout:     30 * time.Second,
+					TimeoutHalflife:    5 * time.Minute,
+					TimeoutCoefficient: 2,
+				},
+				message.ChitsOp: {
+					InitialTimeout:     3 * time.Second,
+					MinimumTimeout:     2 * time.Second,
+					MaximumTimeout:     3 * time.Second,
+					TimeoutHalflife:    5 * time.Minute,
+					TimeoutCoefficient: 2,
+				},
+			},
+		},
+		"request type": {
+			minimums: map[string]string{
+				"app_request": "10s",
+			},
+			expectedErr: errUnknownResponseType,
+		},
+		"minimum above maximum": {
+			minimums: map[string]string{
+				"put": "20s",
+			},
+			expectedErr: errInvalidOpTimeout,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			v := setupViperFlags()
+			v.Set(NetworkOpMinimumTimeoutsKey, test.minimums)
+			v.Set(NetworkOpMaximumTimeoutsKey, test.maximums)
+
+			configs, err := getOpTimeoutConfigs(v, defaultConfig)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.Equal(test.expected, configs)
+		})
+	}
+}
+
 // setups config json file and writes content
 func setupConfigJSON(t *testing.T, rootPath string, value string) string {
 	configFilePath := filepath.Join(rootPath, "config.json")
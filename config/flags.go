@@ -26,8 +26,9 @@ import (
 )
 
 const (
-	DefaultHTTPPort    = 9650
-	DefaultStakingPort = 9651
+	DefaultHTTPPort           = 9650
+	DefaultStakingPort        = 9651
+	DefaultValidatorsGRPCPort = 9652
 
 	AvalancheGoDataDirVar    = "AVALANCHEGO_DATA_DIR"
 	defaultUnexpandedDataDir = "$" + AvalancheGoDataDirVar
@@ -142,6 +143,8 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(NetworkInitialTimeoutKey, constants.DefaultNetworkInitialTimeout, "Initial timeout value of the adaptive timeout manager")
 	fs.Duration(NetworkMinimumTimeoutKey, constants.DefaultNetworkMinimumTimeout, "Minimum timeout value of the adaptive timeout manager")
 	fs.Duration(NetworkMaximumTimeoutKey, constants.DefaultNetworkMaximumTimeout, "Maximum timeout value of the adaptive timeout manager")
+	fs.StringToString(NetworkOpMinimumTimeoutsKey, map[string]string{}, fmt.Sprintf("Response type --> minimum timeout value of requests expecting that type of response (e.g. put=1s,app_response=5s). Response types that aren't specified use %q", NetworkMinimumTimeoutKey))
+	fs.StringToString(NetworkOpMaximumTimeoutsKey, map[string]string{}, fmt.Sprintf("Response type --> maximum timeout value of requests expecting that type of response (e.g. chits=2s,app_response=30s). Response types that aren't specified use %q", NetworkMaximumTimeoutKey))
 	fs.Duration(NetworkMaximumInboundTimeoutKey, constants.DefaultNetworkMaximumInboundTimeout, "Maximum timeout value of an inbound message. Defines duration within which an incoming message must be fulfilled. Incoming messages containing deadline higher than this value will be overridden with this value.")
 	fs.Duration(NetworkTimeoutHalflifeKey, constants.DefaultNetworkTimeoutHalflife, "Halflife of average network response time. Higher value --> network timeout is less volatile. Can't be 0")
 	fs.Float64(NetworkTimeoutCoefficientKey, constants.DefaultNetworkTimeoutCoefficient, "Multiplied by average network response time to get the network timeout. Must be >= 1")
@@ -178,7 +181,10 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	// Router
 	fs.Duration(ConsensusAcceptedFrontierGossipFrequencyKey, constants.DefaultAcceptedFrontierGossipFrequency, "Frequency of gossiping accepted frontiers")
 	fs.Uint(ConsensusAppConcurrencyKey, constants.DefaultConsensusAppConcurrency, "Maximum number of goroutines to use when handling App messages on a chain")
+	fs.Duration(ConsensusAppDrainTimeoutKey, constants.DefaultConsensusAppDrainTimeout, "Maximum amount of time to wait for App messages being handled by a chain to finish when the chain stops, before cancelling them")
+	fs.Bool(ConsensusAdaptiveSamplingEnabledKey, false, "(Experimental) If true, chains with a sampling config query fewer validators for blocks that have accumulated a strong preference")
 	fs.Duration(ConsensusShutdownTimeoutKey, constants.DefaultConsensusShutdownTimeout, "Timeout before killing an unresponsive chain")
+	fs.Uint(ConsensusMaxOutstandingRequestsPerPeerKey, constants.DefaultConsensusMaxOutstandingRequestsPerPeer, "Maximum number of requests, across all chains, that may be awaiting a response from a peer. Requests beyond this limit fail immediately")
 	fs.Uint(ConsensusGossipAcceptedFrontierValidatorSizeKey, constants.DefaultConsensusGossipAcceptedFrontierValidatorSize, "Number of validators to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipAcceptedFrontierNonValidatorSizeKey, constants.DefaultConsensusGossipAcceptedFrontierNonValidatorSize, "Number of non-validators to gossip to when gossiping accepted frontier")
 	fs.Uint(ConsensusGossipAcceptedFrontierPeerSizeKey, constants.DefaultConsensusGossipAcceptedFrontierPeerSize, "Number of peers to gossip to when gossiping accepted frontier")
@@ -220,6 +226,9 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(HTTPReadHeaderTimeoutKey, 30*time.Second, fmt.Sprintf("Maximum duration to read request headers. The connection's read deadline is reset after reading the headers. If %s is zero, the value of %s is used. If both are zero, there is no timeout.", HTTPReadHeaderTimeoutKey, HTTPReadTimeoutKey))
 	fs.Duration(HTTPWriteTimeoutKey, 30*time.Second, "Maximum duration before timing out writes of the response. It is reset whenever a new request's header is read. A zero or negative value means there will be no timeout.")
 	fs.Duration(HTTPIdleTimeoutKey, 120*time.Second, fmt.Sprintf("Maximum duration to wait for the next request when keep-alives are enabled. If %s is zero, the value of %s is used. If both are zero, there is no timeout.", HTTPIdleTimeoutKey, HTTPReadTimeoutKey))
+	fs.Bool(ValidatorsGRPCEnabledKey, false, "If true, this node serves the primary network validator set over gRPC")
+	fs.String(ValidatorsGRPCHostKey, "127.0.0.1", "Address of the validators gRPC server. If the address is empty or a literal unspecified IP address, the server will bind on all available unicast and anycast IP addresses of the local system")
+	fs.Uint(ValidatorsGRPCPortKey, DefaultValidatorsGRPCPort, "Port of the validators gRPC server. If the port is 0 a port number is automatically chosen")
 	fs.Bool(APIAuthRequiredKey, false, "Require authorization token to call HTTP APIs")
 	fs.String(APIAuthPasswordFileKey, "",
 		fmt.Sprintf("Password file used to initially create/validate API authorization tokens. Ignored if %s is specified. Leading and trailing whitespace is removed from the password. Can be changed via API call",
@@ -280,6 +289,10 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Uint64(StakeMinConsumptionRateKey, genesis.LocalParams.RewardConfig.MinConsumptionRate, "Minimum consumption rate of the remaining tokens to mint in the staking function")
 	fs.Duration(StakeMintingPeriodKey, genesis.LocalParams.RewardConfig.MintingPeriod, "Consumption period of the staking function")
 	fs.Uint64(StakeSupplyCapKey, genesis.LocalParams.RewardConfig.SupplyCap, "Supply cap of the staking function")
+	// Validator Churn Limits
+	fs.Duration(ValidatorChurnPeriodKey, genesis.LocalParams.ValidatorChurnPeriod, "Length of the periods that the churn of each subnet's validator set is limited over. If 0, the churn isn't limited. Ignored on Mainnet and Fuji, where the churn isn't limited")
+	fs.Uint64(MaxValidatorChurnKey, genesis.LocalParams.MaxValidatorChurn, "Maximum fraction, in the range [0, 1000000], of a subnet's stake weight that can enter or exit its validator set in a single churn period. Ignored on Mainnet and Fuji")
+	fs.String(SubnetValidatorChurnContentKey, "", fmt.Sprintf("Specifies base64 encoded JSON that maps subnet IDs to the churn limits of their validator sets, used instead of %s and %s. Ignored on Mainnet and Fuji", ValidatorChurnPeriodKey, MaxValidatorChurnKey))
 	// Subnets
 	fs.String(TrackSubnetsKey, "", "List of subnets for the node to track. A node tracking a subnet will track the uptimes of the subnet validators and attempt to sync all the chains in the subnet. Before validating a subnet, a node should be tracking the subnet to avoid impacting their subnet validation uptime")
 
@@ -297,6 +310,7 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(BootstrapMaxTimeGetAncestorsKey, 50*time.Millisecond, "Max Time to spend fetching a container and its ancestors when responding to a GetAncestors")
 	fs.Uint(BootstrapAncestorsMaxContainersSentKey, 2000, "Max number of containers in an Ancestors message sent by this node")
 	fs.Uint(BootstrapAncestorsMaxContainersReceivedKey, 2000, "This node reads at most this many containers from an incoming Ancestors message")
+	fs.Uint(BootstrapMaxOutstandingRequestsKey, 64, "Max number of container requests that the bootstrapping chains of a subnet have outstanding at once")
 
 	// Consensus
 	fs.Int(SnowSampleSizeKey, snowball.DefaultParameters.K, "Number of nodes to query for each network poll")
@@ -33,6 +33,9 @@ const (
 	StakeMinConsumptionRateKey                         = "stake-min-consumption-rate"
 	StakeMintingPeriodKey                              = "stake-minting-period"
 	StakeSupplyCapKey                                  = "stake-supply-cap"
+	ValidatorChurnPeriodKey                            = "validator-churn-period"
+	MaxValidatorChurnKey                               = "max-validator-churn"
+	SubnetValidatorChurnContentKey                     = "subnet-validator-churn-content"
 	DBTypeKey                                          = "db-type"
 	DBPathKey                                          = "db-dir"
 	DBConfigFileKey                                    = "db-config-file"
@@ -55,6 +58,9 @@ const (
 	HTTPReadHeaderTimeoutKey                           = "http-read-header-timeout"
 	HTTPWriteTimeoutKey                                = "http-write-timeout"
 	HTTPIdleTimeoutKey                                 = "http-idle-timeout"
+	ValidatorsGRPCEnabledKey                           = "validators-grpc-enabled"
+	ValidatorsGRPCHostKey                              = "validators-grpc-host"
+	ValidatorsGRPCPortKey                              = "validators-grpc-port"
 	APIAuthRequiredKey                                 = "api-auth-required"
 	APIAuthPasswordKey                                 = "api-auth-password"
 	APIAuthPasswordFileKey                             = "api-auth-password-file"
@@ -77,6 +83,8 @@ const (
 	NetworkInitialTimeoutKey                           = "network-initial-timeout"
 	NetworkMinimumTimeoutKey                           = "network-minimum-timeout"
 	NetworkMaximumTimeoutKey                           = "network-maximum-timeout"
+	NetworkOpMinimumTimeoutsKey                        = "network-op-minimum-timeouts"
+	NetworkOpMaximumTimeoutsKey                        = "network-op-maximum-timeouts"
 	NetworkMaximumInboundTimeoutKey                    = "network-maximum-inbound-timeout"
 	NetworkTimeoutHalflifeKey                          = "network-timeout-halflife"
 	NetworkTimeoutCoefficientKey                       = "network-timeout-coefficient"
@@ -144,6 +152,8 @@ const (
 	MeterVMsEnabledKey                                 = "meter-vms-enabled"
 	ConsensusAcceptedFrontierGossipFrequencyKey        = "consensus-accepted-frontier-gossip-frequency"
 	ConsensusAppConcurrencyKey                         = "consensus-app-concurrency"
+	ConsensusAppDrainTimeoutKey                        = "consensus-app-drain-timeout"
+	ConsensusAdaptiveSamplingEnabledKey                = "consensus-adaptive-sampling-enabled"
 	ConsensusGossipAcceptedFrontierValidatorSizeKey    = "consensus-accepted-frontier-gossip-validator-size"
 	ConsensusGossipAcceptedFrontierNonValidatorSizeKey = "consensus-accepted-frontier-gossip-non-validator-size"
 	ConsensusGossipAcceptedFrontierPeerSizeKey         = "consensus-accepted-frontier-gossip-peer-size"
@@ -154,6 +164,7 @@ const (
 	AppGossipNonValidatorSizeKey                       = "consensus-app-gossip-non-validator-size"
 	AppGossipPeerSizeKey                               = "consensus-app-gossip-peer-size"
 	ConsensusShutdownTimeoutKey                        = "consensus-shutdown-timeout"
+	ConsensusMaxOutstandingRequestsPerPeerKey          = "consensus-max-outstanding-requests-per-peer"
 	ProposerVMUseCurrentHeightKey                      = "proposervm-use-current-height"
 	FdLimitKey                                         = "fd-limit"
 	IndexEnabledKey                                    = "index-enabled"
@@ -169,6 +180,7 @@ const (
 	BootstrapMaxTimeGetAncestorsKey                    = "bootstrap-max-time-get-ancestors"
 	BootstrapAncestorsMaxContainersSentKey             = "bootstrap-ancestors-max-containers-sent"
 	BootstrapAncestorsMaxContainersReceivedKey         = "bootstrap-ancestors-max-containers-received"
+	BootstrapMaxOutstandingRequestsKey                 = "bootstrap-max-outstanding-requests"
 	ChainDataDirKey                                    = "chain-data-dir"
 	ChainConfigDirKey                                  = "chain-config-dir"
 	ChainConfigContentKey                              = "chain-config-content"
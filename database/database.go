@@ -84,6 +84,36 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// RangeDeleter is implemented by data stores that can delete a range of keys
+// atomically without iterating over them.
+type RangeDeleter interface {
+	// DeleteRange removes all keys in [start, limit) from the data store.
+	//
+	// A nil start is treated as a key before all keys in the DB.
+	// And a nil limit is treated as a key after all keys in the DB.
+	//
+	// Note: [start] and [limit] are safe to modify and read after calling
+	// DeleteRange.
+	DeleteRange(start []byte, limit []byte) error
+}
+
+// Capabilities describes the optional operations that a data store supports.
+type Capabilities struct {
+	// RangeDelete is true if the data store supports [RangeDeleter].
+	RangeDelete bool `json:"rangeDelete"`
+}
+
+// CapabilityReporter is implemented by data stores that report which optional
+// operations they support.
+//
+// A data store that wraps another data store may implement an optional
+// interface regardless of whether the wrapped data store supports it. It
+// reports the operations that it can forward, so callers should check
+// [GetCapabilities] rather than asserting the optional interfaces directly.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
@@ -3,10 +3,167 @@
 
 package database
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
 
 // common errors
 var (
-	ErrClosed   = errors.New("closed")
-	ErrNotFound = errors.New("not found")
+	ErrClosed       = errors.New("closed")
+	ErrNotFound     = errors.New("not found")
+	ErrNotSupported = errors.New("not supported")
 )
+
+// Causes of errors that may not happen again if the operation is retried.
+var transientCauses = []error{
+	context.DeadlineExceeded,
+	os.ErrDeadlineExceeded,
+	syscall.EAGAIN,
+	syscall.EBUSY,
+	syscall.EINTR,
+	syscall.EMFILE,
+	syscall.ENFILE,
+	syscall.ENOMEM,
+	syscall.ENOSPC,
+	syscall.ETIMEDOUT,
+}
+
+// Error is an unexpected failure of a database backend, along with the
+// operation that failed.
+//
+// [ErrClosed] and [ErrNotFound] are never wrapped in an Error, so that they
+// can still be compared against directly.
+type Error struct {
+	// Backend is the name of the database backend, such as "leveldb".
+	Backend string
+	// Op is the operation that failed, such as "get" or "write batch".
+	Op string
+	// Key is the key the operation was on, if it was on a single key.
+	Key []byte
+	// Start and Limit bound the range of keys the operation was on, if it was
+	// on a range. A nil bound is unbounded.
+	Start []byte
+	Limit []byte
+	// Prefix is the prefix of the keys the operation was on, if any.
+	Prefix []byte
+	// Transient is true if the operation may succeed if it's retried.
+	Transient bool
+	Err       error
+}
+
+// Error describes the failure without the keys, since they may hold sensitive
+// data. Keys are only described by their length.
+func (e *Error) Error() string {
+	sb := strings.Builder{}
+	sb.WriteString(e.Backend)
+	sb.WriteString(": ")
+	sb.WriteString(e.Op)
+	if e.Key != nil {
+		sb.WriteString(" key ")
+		sb.WriteString(redactKey(e.Key))
+	}
+	if e.Start != nil || e.Limit != nil {
+		sb.WriteString(" range [")
+		sb.WriteString(redactBound(e.Start))
+		sb.WriteString(", ")
+		sb.WriteString(redactBound(e.Limit))
+		sb.WriteString(")")
+	}
+	if e.Prefix != nil {
+		sb.WriteString(" prefix ")
+		sb.WriteString(redactKey(e.Prefix))
+	}
+	sb.WriteString(": ")
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func redactKey(key []byte) string {
+	return fmt.Sprintf("<%d bytes>", len(key))
+}
+
+func redactBound(bound []byte) string {
+	if bound == nil {
+		return "unbounded"
+	}
+	return redactKey(bound)
+}
+
+// NewError returns [err] wrapped in an [Error] of operation [op] of
+// [backend]. If [err] is nil, [ErrClosed], [ErrNotFound] or already an
+// [Error], it's returned as is.
+func NewError(backend, op string, err error) error {
+	return wrapError(&Error{
+		Backend: backend,
+		Op:      op,
+	}, err)
+}
+
+// NewKeyError is the same as [NewError] for an operation on [key].
+func NewKeyError(backend, op string, key []byte, err error) error {
+	return wrapError(&Error{
+		Backend: backend,
+		Op:      op,
+		Key:     key,
+	}, err)
+}
+
+// NewRangeError is the same as [NewError] for an operation on the keys in
+// [start, limit).
+func NewRangeError(backend, op string, start, limit []byte, err error) error {
+	return wrapError(&Error{
+		Backend: backend,
+		Op:      op,
+		Start:   start,
+		Limit:   limit,
+	}, err)
+}
+
+// NewIteratorError is the same as [NewError] for an iterator over the keys
+// with [prefix], starting at [start].
+func NewIteratorError(backend string, start, prefix []byte, err error) error {
+	return wrapError(&Error{
+		Backend: backend,
+		Op:      "iterate",
+		Start:   start,
+		Prefix:  prefix,
+	}, err)
+}
+
+func wrapError(e *Error, err error) error {
+	var wrapped *Error
+	if err == nil || err == ErrClosed || err == ErrNotFound || errors.As(err, &wrapped) {
+		return err
+	}
+	e.Transient = isTransientCause(err)
+	e.Err = err
+	return e
+}
+
+// IsTransient returns true if [err] is a failure that may not happen again if
+// the operation is retried, such as a timeout or a full disk.
+func IsTransient(err error) bool {
+	var e *Error
+	if errors.As(err, &e) && e.Transient {
+		return true
+	}
+	return isTransientCause(err)
+}
+
+func isTransientCause(err error) bool {
+	for _, cause := range transientCauses {
+		if errors.Is(err, cause) {
+			return true
+		}
+	}
+	return false
+}
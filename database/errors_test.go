@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyError(t *testing.T) {
+	require := require.New(t)
+
+	key := []byte("secret key")
+	errTest := errors.New("test")
+	err := NewKeyError("testdb", "get", key, errTest)
+	require.ErrorIs(err, errTest)
+	require.NotContains(err.Error(), string(key))
+	require.Equal("testdb: get key <10 bytes>: test", err.Error())
+	require.False(IsTransient(err))
+
+	var dbErr *Error
+	require.ErrorAs(err, &dbErr)
+	require.Equal("testdb", dbErr.Backend)
+	require.Equal(key, dbErr.Key)
+
+	// Errors aren't wrapped twice.
+	require.Equal(err, NewKeyError("otherdb", "put", key, err))
+}
+
+func TestNewErrorPassthrough(t *testing.T) {
+	for _, err := range []error{nil, ErrClosed, ErrNotFound} {
+		require.Equal(t, err, NewKeyError("testdb", "get", []byte{0}, err))
+	}
+}
+
+func TestNewRangeError(t *testing.T) {
+	err := NewRangeError("testdb", "compact", nil, []byte{1, 2}, errors.New("test"))
+	require.Equal(t, "testdb: compact range [unbounded, <2 bytes>): test", err.Error())
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{err: nil, transient: false},
+		{err: ErrNotFound, transient: false},
+		{err: errors.New("test"), transient: false},
+		{err: syscall.ENOSPC, transient: true},
+		{err: fmt.Errorf("wrapped: %w", context.DeadlineExceeded), transient: true},
+		{err: NewError("testdb", "write batch", syscall.EAGAIN), transient: true},
+		{err: &Error{Backend: "testdb", Op: "get", Transient: true, Err: errors.New("test")}, transient: true},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.transient, IsTransient(test.err), test.err)
+	}
+}
@@ -29,6 +29,19 @@ var (
 	errWrongSize = errors.New("value has unexpected size")
 )
 
+// GetCapabilities returns the optional operations that [db] supports. If [db]
+// is a [CapabilityReporter], its report is returned. Otherwise, an operation
+// is supported if [db] implements its interface.
+func GetCapabilities(db interface{}) Capabilities {
+	if reporter, ok := db.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	_, rangeDelete := db.(RangeDeleter)
+	return Capabilities{
+		RangeDelete: rangeDelete,
+	}
+}
+
 func PutID(db KeyValueWriter, key []byte, val ids.ID) error {
 	return db.Put(key, val[:])
 }
@@ -195,8 +208,15 @@ func Clear(db Database, writeSize int) error {
 }
 
 // Removes all keys with the given [prefix] from [db].
-// Writes each batch when it reaches [writeSize].
+//
+// If [db] supports range deletes, as reported by [GetCapabilities], the keys
+// are removed with a single range delete. Otherwise, the keys are removed in
+// batches that are written when they reach [writeSize].
 func ClearPrefix(db Database, prefix []byte, writeSize int) error {
+	if GetCapabilities(db).RangeDelete {
+		return db.(RangeDeleter).DeleteRange(prefix, prefixToUpperBound(prefix))
+	}
+
 	b := db.NewBatch()
 	it := db.NewIteratorWithPrefix(prefix)
 	// Defer the release of the iterator inside a closure to guarantee that the
@@ -235,3 +255,17 @@ func ClearPrefix(db Database, prefix []byte, writeSize int) error {
 	}
 	return it.Error()
 }
+
+// prefixToUpperBound returns the smallest key that is greater than every key
+// with [prefix]. Returns nil if there is no such key.
+func prefixToUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xFF {
+			upperBound := make([]byte, i+1)
+			copy(upperBound, prefix)
+			upperBound[i]++
+			return upperBound
+		}
+	}
+	return nil
+}
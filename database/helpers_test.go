@@ -50,3 +50,45 @@ func TestSortednessUint32(t *testing.T) {
 	}
 	require.True(t, utils.IsSortedBytes(intBytes))
 }
+
+// rangeDeleter implements RangeDeleter.
+type rangeDeleter struct{}
+
+func (rangeDeleter) DeleteRange([]byte, []byte) error {
+	return nil
+}
+
+// unsupportedRangeDeleter implements RangeDeleter but reports that it doesn't
+// support it, like a wrapper of a data store without range deletion.
+type unsupportedRangeDeleter struct {
+	rangeDeleter
+}
+
+func (unsupportedRangeDeleter) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func TestGetCapabilities(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(Capabilities{}, GetCapabilities(struct{}{}))
+	require.Equal(Capabilities{RangeDelete: true}, GetCapabilities(rangeDeleter{}))
+
+	// The report is used instead of the implemented interfaces.
+	require.Equal(Capabilities{}, GetCapabilities(unsupportedRangeDeleter{}))
+}
+
+func TestPrefixToUpperBound(t *testing.T) {
+	tests := []struct {
+		prefix   []byte
+		expected []byte
+	}{
+		{prefix: nil, expected: nil},
+		{prefix: []byte{0x01}, expected: []byte{0x02}},
+		{prefix: []byte{0x01, 0xff}, expected: []byte{0x02}},
+		{prefix: []byte{0xff, 0xff}, expected: nil},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, prefixToUpperBound(test.prefix))
+	}
+}
@@ -66,9 +66,10 @@ const (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iter)(nil)
+	_ database.Database           = (*Database)(nil)
+	_ database.CapabilityReporter = (*Database)(nil)
+	_ database.Batch              = (*batch)(nil)
+	_ database.Iterator           = (*iter)(nil)
 
 	ErrInvalidConfig = errors.New("invalid config")
 	ErrCouldNotOpen  = errors.New("could not open")
@@ -275,23 +276,23 @@ func New(file string, configBytes []byte, log logging.Logger, namespace string,
 // Has returns if the key is set in the database
 func (db *Database) Has(key []byte) (bool, error) {
 	has, err := db.DB.Has(key, nil)
-	return has, updateError(err)
+	return has, wrapKeyError("has", key, err)
 }
 
 // Get returns the value the key maps to in the database
 func (db *Database) Get(key []byte) ([]byte, error) {
 	value, err := db.DB.Get(key, nil)
-	return value, updateError(err)
+	return value, wrapKeyError("get", key, err)
 }
 
 // Put sets the value of the provided key to the provided value
 func (db *Database) Put(key []byte, value []byte) error {
-	return updateError(db.DB.Put(key, value, nil))
+	return wrapKeyError("put", key, db.DB.Put(key, value, nil))
 }
 
 // Delete removes the key from the database
 func (db *Database) Delete(key []byte) error {
-	return updateError(db.DB.Delete(key, nil))
+	return wrapKeyError("delete", key, db.DB.Delete(key, nil))
 }
 
 // NewBatch creates a write/delete-only buffer that is atomically committed to
@@ -314,6 +315,7 @@ func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
 	return &iter{
 		db:       db,
 		Iterator: db.DB.NewIterator(&util.Range{Start: start}, nil),
+		start:    start,
 	}
 }
 
@@ -323,6 +325,7 @@ func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
 	return &iter{
 		db:       db,
 		Iterator: db.DB.NewIterator(util.BytesPrefix(prefix), nil),
+		prefix:   prefix,
 	}
 }
 
@@ -337,11 +340,18 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return &iter{
 		db:       db,
 		Iterator: db.DB.NewIterator(iterRange, nil),
+		start:    start,
+		prefix:   prefix,
 	}
 }
 
 // This comment is basically copy pasted from the underlying levelDB library:
 
+// Capabilities reports that none of the optional operations are supported.
+func (*Database) Capabilities() database.Capabilities {
+	return database.Capabilities{}
+}
+
 // Compact the underlying DB for the given key range.
 // Specifically, deleted and overwritten versions are discarded,
 // and the data is rearranged to reduce the cost of operations
@@ -352,7 +362,8 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 // And a nil limit is treated as a key after all keys in the DB.
 // Therefore if both are nil then it will compact entire DB.
 func (db *Database) Compact(start []byte, limit []byte) error {
-	return updateError(db.DB.CompactRange(util.Range{Start: start, Limit: limit}))
+	err := db.DB.CompactRange(util.Range{Start: start, Limit: limit})
+	return database.NewRangeError(Name, "compact", start, limit, updateError(err))
 }
 
 func (db *Database) Close() error {
@@ -361,7 +372,7 @@ func (db *Database) Close() error {
 		close(db.closeCh)
 	})
 	db.closeWg.Wait()
-	return updateError(db.DB.Close())
+	return wrapError("close", db.DB.Close())
 }
 
 func (db *Database) HealthCheck(context.Context) (interface{}, error) {
@@ -399,7 +410,7 @@ func (b *batch) Size() int {
 
 // Write flushes any accumulated data to disk.
 func (b *batch) Write() error {
-	return updateError(b.db.DB.Write(&b.Batch, nil))
+	return wrapError("write batch", b.db.DB.Write(&b.Batch, nil))
 }
 
 // Reset resets the batch for reuse.
@@ -446,6 +457,9 @@ type iter struct {
 	db *Database
 	iterator.Iterator
 
+	// The bounds the iterator was created with, to describe its errors.
+	start, prefix []byte
+
 	key, val []byte
 	err      error
 }
@@ -474,7 +488,7 @@ func (it *iter) Error() error {
 	if it.err != nil {
 		return it.err
 	}
-	return updateError(it.Iterator.Error())
+	return database.NewIteratorError(Name, it.start, it.prefix, updateError(it.Iterator.Error()))
 }
 
 func (it *iter) Key() []byte {
@@ -485,6 +499,18 @@ func (it *iter) Value() []byte {
 	return it.val
 }
 
+// wrapError returns [err], which was returned by operation [op], as a
+// [database.Error].
+func wrapError(op string, err error) error {
+	return database.NewError(Name, op, updateError(err))
+}
+
+// wrapKeyError returns [err], which was returned by operation [op] on [key],
+// as a [database.Error].
+func wrapKeyError(op string, key []byte, err error) error {
+	return database.NewKeyError(Name, op, key, updateError(err))
+}
+
 func updateError(err error) error {
 	switch err {
 	case leveldb.ErrClosed:
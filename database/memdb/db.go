@@ -11,6 +11,7 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
 )
 
 const (
@@ -22,9 +23,11 @@ const (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iterator)(nil)
+	_ database.Database           = (*Database)(nil)
+	_ database.RangeDeleter       = (*Database)(nil)
+	_ database.CapabilityReporter = (*Database)(nil)
+	_ database.Batch              = (*batch)(nil)
+	_ database.Iterator           = (*iterator)(nil)
 )
 
 // Database is an ephemeral key-value store that implements the Database
@@ -32,6 +35,22 @@ var (
 type Database struct {
 	lock sync.RWMutex
 	db   map[string][]byte
+
+	// The following fields are only used if the database is bounded.
+
+	// maxSize is the maximum number of bytes of keys and values to store.
+	maxSize int
+	// size is the number of bytes of keys and values currently stored.
+	size int
+	// recentlyUsed orders the keys from least to most recently used.
+	recentlyUsed linkedhashmap.LinkedHashmap[string, struct{}]
+	// onEvict is called with each key-value pair that is evicted.
+	onEvict func(key, value []byte)
+}
+
+type keyValue struct {
+	key   string
+	value []byte
 }
 
 // New returns a map with the Database interface methods implemented.
@@ -45,6 +64,23 @@ func NewWithSize(size int) *Database {
 	return &Database{db: make(map[string][]byte, size)}
 }
 
+// NewBounded returns an empty Database that stores at most [maxSize] bytes of
+// keys and values. When a write would exceed [maxSize], the least recently
+// used key-value pairs are evicted until the database fits. Get and Put mark a
+// key as used.
+//
+// If [onEvict] is non-nil, it is called with every evicted key-value pair
+// before the write that caused the eviction returns. [onEvict] is called
+// without holding the database's lock.
+func NewBounded(maxSize int, onEvict func(key, value []byte)) *Database {
+	return &Database{
+		db:           make(map[string][]byte, DefaultSize),
+		maxSize:      maxSize,
+		recentlyUsed: linkedhashmap.New[string, struct{}](),
+		onEvict:      onEvict,
+	}
+}
+
 func (db *Database) Close() error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -82,30 +118,60 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 		return nil, database.ErrClosed
 	}
 	if entry, ok := db.db[string(key)]; ok {
+		if db.isBounded() {
+			// [recentlyUsed] is safe to modify while holding the read lock.
+			db.recentlyUsed.Put(string(key), struct{}{})
+		}
 		return slices.Clone(entry), nil
 	}
 	return nil, database.ErrNotFound
 }
 
 func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.Lock()
+	if db.db == nil {
+		db.lock.Unlock()
+		return database.ErrClosed
+	}
+	db.put(string(key), slices.Clone(value))
+	evicted := db.evict()
+	db.lock.Unlock()
+
+	db.notifyEvicted(evicted)
+	return nil
+}
+
+func (db *Database) Delete(key []byte) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
 	if db.db == nil {
 		return database.ErrClosed
 	}
-	db.db[string(key)] = slices.Clone(value)
+	db.delete(string(key))
 	return nil
 }
 
-func (db *Database) Delete(key []byte) error {
+func (*Database) Capabilities() database.Capabilities {
+	return database.Capabilities{
+		RangeDelete: true,
+	}
+}
+
+func (db *Database) DeleteRange(start, limit []byte) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
 	if db.db == nil {
 		return database.ErrClosed
 	}
-	delete(db.db, string(key))
+	startStr := string(start)
+	for key := range db.db {
+		if key < startStr || (limit != nil && key >= string(limit)) {
+			continue
+		}
+		db.delete(key)
+	}
 	return nil
 }
 
@@ -172,6 +238,70 @@ func (db *Database) HealthCheck(context.Context) (interface{}, error) {
 	return nil, nil
 }
 
+func (db *Database) isBounded() bool {
+	return db.recentlyUsed != nil
+}
+
+// Assumes [db.lock] is held.
+func (db *Database) put(key string, value []byte) {
+	if !db.isBounded() {
+		db.db[key] = value
+		return
+	}
+
+	if oldValue, ok := db.db[key]; ok {
+		db.size -= len(key) + len(oldValue)
+	}
+	db.db[key] = value
+	db.size += len(key) + len(value)
+	db.recentlyUsed.Put(key, struct{}{})
+}
+
+// Assumes [db.lock] is held.
+func (db *Database) delete(key string) {
+	if !db.isBounded() {
+		delete(db.db, key)
+		return
+	}
+
+	if value, ok := db.db[key]; ok {
+		delete(db.db, key)
+		db.size -= len(key) + len(value)
+		db.recentlyUsed.Delete(key)
+	}
+}
+
+// evict removes the least recently used key-value pairs until the database
+// fits within [db.maxSize] and returns the removed pairs.
+//
+// Assumes [db.lock] is held.
+func (db *Database) evict() []keyValue {
+	if !db.isBounded() {
+		return nil
+	}
+
+	var evicted []keyValue
+	for db.size > db.maxSize {
+		key, _, _ := db.recentlyUsed.Oldest()
+		evicted = append(evicted, keyValue{
+			key:   key,
+			value: db.db[key],
+		})
+		db.delete(key)
+	}
+	return evicted
+}
+
+// Assumes [db.lock] isn't held.
+func (db *Database) notifyEvicted(evicted []keyValue) {
+	if db.onEvict == nil {
+		return
+	}
+	for _, kv := range evicted {
+		db.onEvict([]byte(kv.key), kv.value)
+	}
+}
+
 type batch struct {
 	database.BatchOps
 
@@ -180,19 +310,24 @@ type batch struct {
 
 func (b *batch) Write() error {
 	b.db.lock.Lock()
-	defer b.db.lock.Unlock()
-
 	if b.db.db == nil {
+		b.db.lock.Unlock()
 		return database.ErrClosed
 	}
 
 	for _, op := range b.Ops {
 		if op.Delete {
-			delete(b.db.db, string(op.Key))
+			b.db.delete(string(op.Key))
 		} else {
-			b.db.db[string(op.Key)] = op.Value
+			b.db.put(string(op.Key), op.Value)
 		}
 	}
+	// Evicting after the whole batch is applied means that a key written by
+	// this batch is only evicted if the batch alone exceeds the bound.
+	evicted := b.db.evict()
+	b.db.lock.Unlock()
+
+	b.db.notifyEvicted(evicted)
 	return nil
 }
 
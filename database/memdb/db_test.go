@@ -6,7 +6,10 @@ package memdb
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 func TestInterface(t *testing.T) {
@@ -15,6 +18,59 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestBoundedInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		test(t, NewBounded(units.GiB, nil))
+	}
+}
+
+func TestBoundedEviction(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		evicted       []string
+		evictedValues = make(map[string][]byte)
+	)
+	db := NewBounded(6, func(key, value []byte) {
+		evicted = append(evicted, string(key))
+		evictedValues[string(key)] = value
+	})
+
+	require.NoError(db.Put([]byte("a"), []byte("a")))
+	require.NoError(db.Put([]byte("b"), []byte("b")))
+	require.NoError(db.Put([]byte("c"), []byte("c")))
+	require.Empty(evicted)
+
+	// Reading [a] makes [b] the least recently used key.
+	_, err := db.Get([]byte("a"))
+	require.NoError(err)
+
+	require.NoError(db.Put([]byte("d"), []byte("d")))
+	require.Equal([]string{"b"}, evicted)
+	require.Equal([]byte("b"), evictedValues["b"])
+
+	has, err := db.Has([]byte("b"))
+	require.NoError(err)
+	require.False(has)
+
+	// Deleted keys no longer count towards the bound.
+	require.NoError(db.Delete([]byte("a")))
+	require.NoError(db.Put([]byte("e"), []byte("e")))
+	require.Equal([]string{"b"}, evicted)
+
+	// Pre-existing keys are evicted before the keys written by a batch.
+	batch := db.NewBatch()
+	require.NoError(batch.Put([]byte("f"), []byte("f")))
+	require.NoError(batch.Put([]byte("g"), []byte("g")))
+	require.NoError(batch.Write())
+	require.Equal([]string{"b", "c", "d"}, evicted)
+
+	// A value that can never fit is evicted immediately.
+	require.NoError(db.Put([]byte("h"), []byte("hhhhhhh")))
+	require.Equal([]string{"b", "c", "d", "e", "f", "g", "h"}, evicted)
+	require.Equal([]byte("hhhhhhh"), evictedValues["h"])
+}
+
 func FuzzKeyValue(f *testing.F) {
 	database.FuzzKeyValue(f, New())
 }
@@ -13,9 +13,11 @@ import (
 )
 
 var (
-	_ database.Database = (*Database)(nil)
-	_ database.Batch    = (*batch)(nil)
-	_ database.Iterator = (*iterator)(nil)
+	_ database.Database           = (*Database)(nil)
+	_ database.RangeDeleter       = (*Database)(nil)
+	_ database.CapabilityReporter = (*Database)(nil)
+	_ database.Batch              = (*batch)(nil)
+	_ database.Iterator           = (*iterator)(nil)
 )
 
 // Database tracks the amount of time each operation takes and how many bytes
@@ -79,6 +81,27 @@ func (db *Database) Delete(key []byte) error {
 	return err
 }
 
+// Capabilities reports the optional operations that the underlying database
+// supports, which are forwarded to it.
+func (db *Database) Capabilities() database.Capabilities {
+	return database.GetCapabilities(db.db)
+}
+
+// DeleteRange returns [database.ErrNotSupported] if the underlying database
+// doesn't support range deletes.
+func (db *Database) DeleteRange(start, limit []byte) error {
+	rangeDeleter, ok := db.db.(database.RangeDeleter)
+	if !ok || !database.GetCapabilities(db.db).RangeDelete {
+		return database.ErrNotSupported
+	}
+
+	startTime := db.clock.Time()
+	err := rangeDeleter.DeleteRange(start, limit)
+	end := db.clock.Time()
+	db.deleteRange.Observe(float64(end.Sub(startTime)))
+	return err
+}
+
 func (db *Database) NewBatch() database.Batch {
 	start := db.clock.Time()
 	b := &batch{
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/corruptabledb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 )
 
@@ -24,6 +25,33 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestCapabilities(t *testing.T) {
+	require := require.New(t)
+
+	db, err := New("", prometheus.NewRegistry(), memdb.New())
+	require.NoError(err)
+	require.Equal(
+		database.Capabilities{
+			RangeDelete: true,
+		},
+		database.GetCapabilities(db),
+	)
+
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+	require.NoError(db.DeleteRange(nil, nil))
+	has, err := db.Has([]byte{1})
+	require.NoError(err)
+	require.False(has)
+
+	// Nothing is forwarded to a database without optional operations.
+	db, err = New("", prometheus.NewRegistry(), corruptabledb.New(memdb.New()))
+	require.NoError(err)
+	require.Equal(database.Capabilities{}, database.GetCapabilities(db))
+
+	err = db.DeleteRange(nil, nil)
+	require.ErrorIs(err, database.ErrNotSupported)
+}
+
 func FuzzKeyValue(f *testing.F) {
 	baseDB := memdb.New()
 	db, err := New("", prometheus.NewRegistry(), baseDB)
@@ -39,6 +39,7 @@ type metrics struct {
 	get, getSize,
 	put, putSize,
 	delete, deleteSize,
+	deleteRange,
 	newBatch,
 	newIterator,
 	compact,
@@ -71,6 +72,7 @@ func newMetrics(namespace string, reg prometheus.Registerer) (metrics, error) {
 		putSize:     newSizeMetric(namespace, "put", reg, &errs),
 		delete:      newTimeMetric(namespace, "delete", reg, &errs),
 		deleteSize:  newSizeMetric(namespace, "delete", reg, &errs),
+		deleteRange: newTimeMetric(namespace, "delete_range", reg, &errs),
 		newBatch:    newTimeMetric(namespace, "new_batch", reg, &errs),
 		newIterator: newTimeMetric(namespace, "new_iterator", reg, &errs),
 		compact:     newTimeMetric(namespace, "compact", reg, &errs),
@@ -58,7 +58,7 @@ func (b *batch) Write() error {
 
 	if !b.written {
 		// This batch has not been written to the database yet.
-		if err := updateError(b.batch.Commit(pebble.Sync)); err != nil {
+		if err := wrapError("write batch", b.batch.Commit(pebble.Sync)); err != nil {
 			return err
 		}
 		b.written = true
@@ -71,11 +71,11 @@ func (b *batch) Write() error {
 
 	// Copy the batch.
 	if err := batchClone.Apply(b.batch, nil); err != nil {
-		return err
+		return wrapError("write batch", err)
 	}
 
 	// Commit the new batch.
-	return updateError(batchClone.Commit(pebble.Sync))
+	return wrapError("write batch", batchClone.Commit(pebble.Sync))
 }
 
 func (b *batch) Reset() {
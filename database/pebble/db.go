@@ -22,6 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/validate"
 )
 
 const (
@@ -30,10 +31,19 @@ const (
 	// pebbleByteOverHead is the number of bytes of constant overhead that
 	// should be added to a batch size per operation.
 	pebbleByteOverHead = 8
+
+	// blockSize is the size of the data blocks written by pebble. We use
+	// pebble's default.
+	blockSize = 4 * units.KiB
+
+	// maxMemTableSize is the largest memtable size supported by pebble.
+	maxMemTableSize = 4*units.GiB - 1
 )
 
 var (
-	_ database.Database = (*Database)(nil)
+	_ database.Database           = (*Database)(nil)
+	_ database.RangeDeleter       = (*Database)(nil)
+	_ database.CapabilityReporter = (*Database)(nil)
 
 	errInvalidOperation = errors.New("invalid operation")
 
@@ -76,6 +86,19 @@ type Config struct {
 	MaxConcurrentCompactions    int `json:"maxConcurrentCompactions"`
 }
 
+// Verify returns an error describing every invalid field of the config.
+func (c *Config) Verify() error {
+	v := validate.New(Name)
+	validate.AtLeast(v, "cacheSize", c.CacheSize, 0)
+	validate.AtLeast(v, "bytesPerSync", c.BytesPerSync, 0)
+	validate.AtLeast(v, "walBytesPerSync", c.WALBytesPerSync, 0)
+	validate.AtLeast(v, "memTableStopWritesThreshold", c.MemTableStopWritesThreshold, 1)
+	validate.InRange(v, "memTableSize", c.MemTableSize, blockSize, maxMemTableSize)
+	validate.AtLeast(v, "maxOpenFiles", c.MaxOpenFiles, 1)
+	validate.AtLeast(v, "maxConcurrentCompactions", c.MaxConcurrentCompactions, 1)
+	return v.Err()
+}
+
 // TODO: Add metrics
 func New(file string, configBytes []byte, log logging.Logger, _ string, _ prometheus.Registerer) (database.Database, error) {
 	cfg := DefaultConfig
@@ -84,6 +107,9 @@ func New(file string, configBytes []byte, log logging.Logger, _ string, _ promet
 			return nil, err
 		}
 	}
+	if err := cfg.Verify(); err != nil {
+		return nil, err
+	}
 
 	opts := &pebble.Options{
 		Cache:                       pebble.NewCache(int64(cfg.CacheSize)),
@@ -126,7 +152,7 @@ func (db *Database) Close() error {
 	}
 	db.openIterators.Clear()
 
-	return updateError(db.pebbleDB.Close())
+	return wrapError("close", db.pebbleDB.Close())
 }
 
 func (db *Database) HealthCheck(_ context.Context) (interface{}, error) {
@@ -152,7 +178,7 @@ func (db *Database) Has(key []byte) (bool, error) {
 		return false, nil
 	}
 	if err != nil {
-		return false, updateError(err)
+		return false, wrapKeyError("has", key, err)
 	}
 	return true, closer.Close()
 }
@@ -167,7 +193,7 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 
 	data, closer, err := db.pebbleDB.Get(key)
 	if err != nil {
-		return nil, updateError(err)
+		return nil, wrapKeyError("get", key, err)
 	}
 	return slices.Clone(data), closer.Close()
 }
@@ -180,7 +206,7 @@ func (db *Database) Put(key []byte, value []byte) error {
 		return database.ErrClosed
 	}
 
-	return updateError(db.pebbleDB.Set(key, value, pebble.Sync))
+	return wrapKeyError("put", key, db.pebbleDB.Set(key, value, pebble.Sync))
 }
 
 func (db *Database) Delete(key []byte) error {
@@ -191,7 +217,67 @@ func (db *Database) Delete(key []byte) error {
 		return database.ErrClosed
 	}
 
-	return updateError(db.pebbleDB.Delete(key, pebble.Sync))
+	return wrapKeyError("delete", key, db.pebbleDB.Delete(key, pebble.Sync))
+}
+
+func (*Database) Capabilities() database.Capabilities {
+	return database.Capabilities{
+		RangeDelete: true,
+	}
+}
+
+func (db *Database) DeleteRange(start []byte, limit []byte) error {
+	if limit == nil {
+		return db.deleteRangeToEnd(start)
+	}
+
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if pebble.DefaultComparer.Compare(start, limit) >= 0 {
+		// pebble requires [start] < [limit]
+		return nil
+	}
+	return wrapRangeError("delete range", start, limit, db.pebbleDB.DeleteRange(start, limit, pebble.Sync))
+}
+
+// deleteRangeToEnd deletes all keys at or after [start].
+//
+// The database.Database spec treats a nil limit as a key after all keys but
+// pebble requires an exclusive upper bound. The greatest key in the database
+// is used as the limit and deleted in the same batch. [lock] is held for
+// writing so that no key after it can be written before the batch is
+// committed, which would otherwise survive the deletion.
+func (db *Database) deleteRangeToEnd(start []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	it := db.pebbleDB.NewIter(&pebble.IterOptions{LowerBound: start})
+	if !it.Last() {
+		// There are no keys at or after [start].
+		return it.Close()
+	}
+	lastKey := slices.Clone(it.Key())
+	if err := it.Close(); err != nil {
+		return wrapRangeError("delete range", start, nil, err)
+	}
+
+	batch := db.pebbleDB.NewBatch()
+	if err := batch.DeleteRange(start, lastKey, pebble.Sync); err != nil {
+		return wrapRangeError("delete range", start, nil, err)
+	}
+	if err := batch.Delete(lastKey, pebble.Sync); err != nil {
+		return wrapRangeError("delete range", start, nil, err)
+	}
+	return wrapRangeError("delete range", start, nil, batch.Commit(pebble.Sync))
 }
 
 func (db *Database) Compact(start []byte, end []byte) error {
@@ -224,7 +310,7 @@ func (db *Database) Compact(start []byte, end []byte) error {
 		return nil
 	}
 
-	return updateError(db.pebbleDB.Compact(start, end, true /* parallelize */))
+	return wrapRangeError("compact", start, end, db.pebbleDB.Compact(start, end, true /* parallelize */))
 }
 
 func (db *Database) NewIterator() database.Iterator {
@@ -252,13 +338,33 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 
 	iter := &iter{
-		db:   db,
-		iter: db.pebbleDB.NewIter(keyRange(start, prefix)),
+		db:     db,
+		iter:   db.pebbleDB.NewIter(keyRange(start, prefix)),
+		start:  start,
+		prefix: prefix,
 	}
 	db.openIterators.Add(iter)
 	return iter
 }
 
+// wrapError returns [err], which was returned by operation [op], as a
+// [database.Error].
+func wrapError(op string, err error) error {
+	return database.NewError(Name, op, updateError(err))
+}
+
+// wrapKeyError returns [err], which was returned by operation [op] on [key],
+// as a [database.Error].
+func wrapKeyError(op string, key []byte, err error) error {
+	return database.NewKeyError(Name, op, key, updateError(err))
+}
+
+// wrapRangeError returns [err], which was returned by operation [op] on the
+// keys in [start, limit), as a [database.Error].
+func wrapRangeError(op string, start, limit []byte, err error) error {
+	return database.NewRangeError(Name, op, start, limit, updateError(err))
+}
+
 // Converts a pebble-specific error to its Avalanche equivalent, if applicable.
 func updateError(err error) error {
 	switch err {
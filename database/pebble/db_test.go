@@ -12,6 +12,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/validate"
 )
 
 func newDB(t testing.TB) *Database {
@@ -145,3 +146,22 @@ func TestKeyRange(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	config := DefaultConfig
+	require.NoError(config.Verify())
+
+	config.MemTableSize = blockSize - 1
+	config.MaxOpenFiles = 0
+	err := config.Verify()
+	require.ErrorIs(err, validate.ErrInvalidConfig)
+
+	var verifyErr *validate.Error
+	require.ErrorAs(err, &verifyErr)
+	require.Len(verifyErr.Errs, 2)
+
+	_, err = New(t.TempDir(), []byte(`{"memTableSize":0}`), logging.NoLog{}, "pebble", prometheus.NewRegistry())
+	require.ErrorIs(err, validate.ErrOutOfRange)
+}
@@ -31,6 +31,9 @@ type iter struct {
 	db   *Database
 	iter *pebble.Iterator
 
+	// The bounds the iterator was created with, to describe its errors.
+	start, prefix []byte
+
 	initialized bool
 	closed      bool
 	err         error
@@ -82,9 +85,9 @@ func (it *iter) Error() error {
 	defer it.lock.Unlock()
 
 	if it.err != nil || it.closed {
-		return it.err
+		return database.NewIteratorError(Name, it.start, it.prefix, it.err)
 	}
-	return updateError(it.iter.Error())
+	return database.NewIteratorError(Name, it.start, it.prefix, updateError(it.iter.Error()))
 }
 
 func (it *iter) Key() []byte {
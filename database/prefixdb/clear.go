@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package prefixdb
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const defaultClearBatchSize = units.MiB
+
+// ClearOptions configures how ClearWithOptions deletes keys when the
+// underlying database doesn't support range deletes.
+type ClearOptions struct {
+	// The number of bytes of deletes to accumulate before writing them.
+	//
+	// If 0 is specified, 1 MiB is used.
+	BatchSize int
+	// The maximum number of keys to delete per second.
+	//
+	// If 0 is specified, deletes aren't rate limited.
+	KeysPerSecond int
+	// If non-nil, OnProgress is called with the total number of keys deleted
+	// so far after each batch is written.
+	OnProgress func(deleted int)
+}
+
+// ClearWithOptions deletes all keys in [db].
+//
+// If the underlying database supports range deletes, as reported by
+// [database.GetCapabilities], all keys are deleted atomically with a single range delete. Otherwise, the keys are
+// deleted in batches, as configured by [opts], which isn't atomic. A clear
+// that was interrupted can be finished by calling ClearWithOptions again.
+//
+// Returns the error of [ctx] if it's cancelled before all keys are deleted.
+func ClearWithOptions(ctx context.Context, db *Database, opts ClearOptions) error {
+	if database.GetCapabilities(db.db).RangeDelete {
+		return db.deleteRange(db.db.(database.RangeDeleter))
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultClearBatchSize
+	}
+	var limiter *rate.Limiter
+	if opts.KeysPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.KeysPerSecond), opts.KeysPerSecond)
+	}
+
+	var (
+		deleted int
+		b       = db.NewBatch()
+		it      = db.NewIterator()
+	)
+	// Defer the release of the iterator inside a closure to guarantee that the
+	// latest, not the first, iterator is released on return.
+	defer func() {
+		it.Release()
+	}()
+
+	for it.Next() {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := b.Delete(it.Key()); err != nil {
+			return err
+		}
+		deleted++
+
+		// Avoid too much memory pressure by periodically writing to the
+		// database.
+		if b.Size() < batchSize {
+			continue
+		}
+
+		if err := b.Write(); err != nil {
+			return err
+		}
+		b.Reset()
+		if opts.OnProgress != nil {
+			opts.OnProgress(deleted)
+		}
+
+		// Reset the iterator to release references to now deleted keys.
+		if err := it.Error(); err != nil {
+			return err
+		}
+		it.Release()
+		it = db.NewIterator()
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if err := b.Write(); err != nil {
+		return err
+	}
+	if opts.OnProgress != nil && b.Size() > 0 {
+		opts.OnProgress(deleted)
+	}
+	return nil
+}
+
+// deleteRange deletes every key with [db.dbPrefix] from [rangeDeleter].
+func (db *Database) deleteRange(rangeDeleter database.RangeDeleter) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return rangeDeleter.DeleteRange(db.dbPrefix, prefixToUpperBound(db.dbPrefix))
+}
+
+// prefixToUpperBound returns the smallest key that is greater than every key
+// with [prefix]. Returns nil if there is no such key.
+func prefixToUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xFF {
+			upperBound := make([]byte, i+1)
+			copy(upperBound, prefix)
+			upperBound[i]++
+			return upperBound
+		}
+	}
+	return nil
+}
@@ -177,7 +177,13 @@ func (db *Database) Compact(start, limit []byte) error {
 	if db.closed {
 		return database.ErrClosed
 	}
-	return db.db.Compact(db.prefix(start), db.prefix(limit))
+	// A nil [limit] is after all keys in [db], which isn't the prefixed nil
+	// key.
+	prefixedLimit := prefixToUpperBound(db.dbPrefix)
+	if limit != nil {
+		prefixedLimit = db.prefix(limit)
+	}
+	return db.db.Compact(db.prefix(start), prefixedLimit)
 }
 
 func (db *Database) Close() error {
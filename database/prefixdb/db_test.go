@@ -4,8 +4,13 @@
 package prefixdb
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 )
@@ -39,3 +44,102 @@ func BenchmarkInterface(b *testing.B) {
 		}
 	}
 }
+
+// batchOnlyDB hides the range deletes of the embedded database.
+type batchOnlyDB struct {
+	database.Database
+}
+
+// compactRecorderDB records the range of the last compaction.
+type compactRecorderDB struct {
+	database.Database
+	start, limit []byte
+}
+
+func (db *compactRecorderDB) Compact(start, limit []byte) error {
+	db.start = slices.Clone(start)
+	db.limit = slices.Clone(limit)
+	return db.Database.Compact(start, limit)
+}
+
+func TestCompactRange(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := &compactRecorderDB{Database: memdb.New()}
+	db := New([]byte("hello"), baseDB)
+
+	require.NoError(db.Compact([]byte{1}, []byte{2}))
+	require.Equal(append(slices.Clone(db.dbPrefix), 1), baseDB.start)
+	require.Equal(append(slices.Clone(db.dbPrefix), 2), baseDB.limit)
+
+	// A nil limit must include every key with the prefix.
+	require.NoError(db.Compact(nil, nil))
+	require.Equal(db.dbPrefix, baseDB.start)
+	require.Equal(prefixToUpperBound(db.dbPrefix), baseDB.limit)
+	require.Negative(bytes.Compare(append(slices.Clone(db.dbPrefix), 0xFF), baseDB.limit))
+}
+
+func TestClearWithOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		db   database.Database
+	}{
+		{
+			name: "range delete",
+			db:   memdb.New(),
+		},
+		{
+			name: "batched delete",
+			db:   batchOnlyDB{Database: memdb.New()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			db := New([]byte("hello"), test.db)
+			otherDB := New([]byte("world"), test.db)
+			for i := 0; i < 10; i++ {
+				key := []byte{byte(i)}
+				require.NoError(db.Put(key, key))
+				require.NoError(otherDB.Put(key, key))
+			}
+
+			var progress []int
+			require.NoError(ClearWithOptions(context.Background(), db, ClearOptions{
+				BatchSize:     1,
+				KeysPerSecond: 1000,
+				OnProgress: func(deleted int) {
+					progress = append(progress, deleted)
+				},
+			}))
+
+			isEmpty, err := database.IsEmpty(db)
+			require.NoError(err)
+			require.True(isEmpty)
+
+			// Keys with a different prefix aren't deleted.
+			count, err := database.Count(otherDB)
+			require.NoError(err)
+			require.Equal(10, count)
+
+			if _, ok := test.db.(database.RangeDeleter); ok {
+				require.Empty(progress)
+			} else {
+				require.Equal([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, progress)
+			}
+		})
+	}
+}
+
+func TestClearWithOptionsCancelled(t *testing.T) {
+	require := require.New(t)
+
+	db := New([]byte("hello"), batchOnlyDB{Database: memdb.New()})
+	require.NoError(db.Put([]byte{0}, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ClearWithOptions(ctx, db, ClearOptions{})
+	require.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,329 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package routerdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	_ database.Database = (*Database)(nil)
+	_ database.Batch    = (*batch)(nil)
+
+	errRebalancePending = errors.New("routes can't be changed until the previous routes have been rebalanced")
+)
+
+// Database routes key ranges to different underlying databases.
+//
+// Every key is stored in exactly one underlying database, as described by the
+// current routes. The underlying databases are owned by the caller and are
+// not closed when this database is closed.
+//
+// Writes that touch multiple underlying databases, such as batches, are not
+// atomic across those databases.
+type Database struct {
+	// lock must be held to modify [closed], [current], or [previous].
+	// Rebalancing holds the lock while moving keys so that concurrent writes
+	// can't be overwritten by stale values.
+	lock   sync.RWMutex
+	closed bool
+
+	current *table
+	// Non-nil iff the routes have been changed and the keys routed by the
+	// previous routes may not have been moved yet.
+	previous *table
+	// Incremented every time the routes change.
+	generation uint64
+}
+
+// New returns a database that routes keys contained in [routes] to the
+// database of their route and all other keys to [defaultDB].
+func New(defaultDB database.Database, routes []Route) (*Database, error) {
+	current, err := newTable(defaultDB, routes)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		current: current,
+	}, nil
+}
+
+// SetRoutes replaces the routes of the database.
+//
+// Keys whose route changed remain readable from their previous database until
+// Rebalance moves them. Writes are routed using the new routes immediately.
+// The routes can't be changed again until Rebalance has completed.
+func (db *Database) SetRoutes(defaultDB database.Database, routes []Route) error {
+	next, err := newTable(defaultDB, routes)
+	if err != nil {
+		return err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	switch {
+	case db.closed:
+		return database.ErrClosed
+	case db.previous != nil:
+		return errRebalancePending
+	}
+	db.previous = db.current
+	db.current = next
+	db.generation++
+	return nil
+}
+
+// staleOwner returns the database that [key] was routed to before the routes
+// were last changed, if [key] may not have been moved out of it yet.
+// Returns nil if [key] can only be stored in [owner].
+// Assumes [db.lock] is held.
+func (db *Database) staleOwner(key []byte, owner database.Database) database.Database {
+	if db.previous == nil {
+		return nil
+	}
+	if staleOwner := db.previous.owner(key); staleOwner != owner {
+		return staleOwner
+	}
+	return nil
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+	owner := db.current.owner(key)
+	has, err := owner.Has(key)
+	if err != nil || has {
+		return has, err
+	}
+	if staleOwner := db.staleOwner(key, owner); staleOwner != nil {
+		return staleOwner.Has(key)
+	}
+	return false, nil
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	owner := db.current.owner(key)
+	value, err := owner.Get(key)
+	if err != database.ErrNotFound {
+		return value, err
+	}
+	if staleOwner := db.staleOwner(key, owner); staleOwner != nil {
+		return staleOwner.Get(key)
+	}
+	return nil, database.ErrNotFound
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	owner := db.current.owner(key)
+	if err := owner.Put(key, value); err != nil {
+		return err
+	}
+	// Remove any stale copy so that a later delete can't expose it.
+	if staleOwner := db.staleOwner(key, owner); staleOwner != nil {
+		return staleOwner.Delete(key)
+	}
+	return nil
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	owner := db.current.owner(key)
+	if err := owner.Delete(key); err != nil {
+		return err
+	}
+	if staleOwner := db.staleOwner(key, owner); staleOwner != nil {
+		return staleOwner.Delete(key)
+	}
+	return nil
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{db: db}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return &database.IteratorError{
+			Err: database.ErrClosed,
+		}
+	}
+	return newIterator(db, db.current, db.previous, start, prefix)
+}
+
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	for _, underlyingDB := range db.databases() {
+		if err := underlyingDB.Compact(start, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+	return nil
+}
+
+func (db *Database) isClosed() bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.closed
+}
+
+// HealthCheck returns the health of every underlying database.
+func (db *Database) HealthCheck(ctx context.Context) (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+
+	var (
+		underlyingDBs = db.databases()
+		healths       = make([]interface{}, len(underlyingDBs))
+		errs          wrappers.Errs
+	)
+	for i, underlyingDB := range underlyingDBs {
+		health, err := underlyingDB.HealthCheck(ctx)
+		healths[i] = health
+		errs.Add(err)
+	}
+	return healths, errs.Err
+}
+
+// databases returns every distinct database that may contain keys.
+// Assumes [db.lock] is held.
+func (db *Database) databases() []database.Database {
+	return databases(db.current, db.previous)
+}
+
+func databases(current, previous *table) []database.Database {
+	dbs := current.databases()
+	if previous == nil {
+		return dbs
+	}
+	for _, underlyingDB := range previous.databases() {
+		if !slices.Contains(dbs, underlyingDB) {
+			dbs = append(dbs, underlyingDB)
+		}
+	}
+	return dbs
+}
+
+type batch struct {
+	database.BatchOps
+
+	db *Database
+}
+
+// Write splits the batch by the underlying database each key is routed to and
+// writes the resulting batches in turn.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	var (
+		underlyingDBs []database.Database
+		batches       = make(map[database.Database]database.Batch)
+	)
+	getBatch := func(underlyingDB database.Database) database.Batch {
+		if batch, ok := batches[underlyingDB]; ok {
+			return batch
+		}
+		batch := underlyingDB.NewBatch()
+		underlyingDBs = append(underlyingDBs, underlyingDB)
+		batches[underlyingDB] = batch
+		return batch
+	}
+
+	for _, op := range b.Ops {
+		owner := b.db.current.owner(op.Key)
+		if op.Delete {
+			if err := getBatch(owner).Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := getBatch(owner).Put(op.Key, op.Value); err != nil {
+			return err
+		}
+
+		if staleOwner := b.db.staleOwner(op.Key, owner); staleOwner != nil {
+			if err := getBatch(staleOwner).Delete(op.Key); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, underlyingDB := range underlyingDBs {
+		if err := batches[underlyingDB].Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *batch) Inner() database.Batch {
+	return b
+}
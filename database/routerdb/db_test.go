@@ -0,0 +1,288 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package routerdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newTestDB(t testing.TB, routes func(a, b database.Database) []Route) *Database {
+	db, err := New(memdb.New(), routes(memdb.New(), memdb.New()))
+	require.NoError(t, err)
+	return db
+}
+
+var testRoutes = map[string]func(a, b database.Database) []Route{
+	"no routes": func(database.Database, database.Database) []Route {
+		return nil
+	},
+	"prefix route": func(a, _ database.Database) []Route {
+		return []Route{
+			NewPrefixRoute([]byte("hello"), a),
+		}
+	},
+	"split ranges": func(a, b database.Database) []Route {
+		return []Route{
+			{
+				Limit: []byte("hello2"),
+				DB:    a,
+			},
+			{
+				Start: []byte("hello2"),
+				DB:    b,
+			},
+		}
+	},
+	"shared database": func(a, _ database.Database) []Route {
+		return []Route{
+			NewPrefixRoute([]byte("a"), a),
+			NewPrefixRoute([]byte("hello1"), a),
+		}
+	},
+}
+
+func TestInterface(t *testing.T) {
+	for name, routes := range testRoutes {
+		for _, test := range database.Tests {
+			t.Run(name, func(t *testing.T) {
+				test(t, newTestDB(t, routes))
+			})
+		}
+	}
+}
+
+func FuzzKeyValue(f *testing.F) {
+	database.FuzzKeyValue(f, newTestDB(f, testRoutes["split ranges"]))
+}
+
+func FuzzNewIteratorWithPrefix(f *testing.F) {
+	database.FuzzNewIteratorWithPrefix(f, newTestDB(f, testRoutes["split ranges"]))
+}
+
+func TestNewInvalidRoutes(t *testing.T) {
+	db := memdb.New()
+	tests := []struct {
+		name        string
+		defaultDB   database.Database
+		routes      []Route
+		expectedErr error
+	}{
+		{
+			name:        "nil default database",
+			expectedErr: errNilDatabase,
+		},
+		{
+			name:      "nil route database",
+			defaultDB: db,
+			routes: []Route{
+				NewPrefixRoute([]byte("a"), nil),
+			},
+			expectedErr: errNilDatabase,
+		},
+		{
+			name:      "empty range",
+			defaultDB: db,
+			routes: []Route{
+				{
+					Start: []byte("b"),
+					Limit: []byte("a"),
+					DB:    db,
+				},
+			},
+			expectedErr: errEmptyRange,
+		},
+		{
+			name:      "overlapping ranges",
+			defaultDB: db,
+			routes: []Route{
+				NewPrefixRoute([]byte("a"), db),
+				NewPrefixRoute([]byte("ab"), db),
+			},
+			expectedErr: errOverlappingRoutes,
+		},
+		{
+			name:      "overlapping unbounded range",
+			defaultDB: db,
+			routes: []Route{
+				{
+					Start: []byte("b"),
+					DB:    db,
+				},
+				{
+					DB: db,
+				},
+			},
+			expectedErr: errOverlappingRoutes,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.defaultDB, test.routes)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestPrefixLimit(t *testing.T) {
+	tests := []struct {
+		prefix        []byte
+		expectedLimit []byte
+	}{
+		{
+			prefix:        nil,
+			expectedLimit: nil,
+		},
+		{
+			prefix:        []byte{0x01},
+			expectedLimit: []byte{0x02},
+		},
+		{
+			prefix:        []byte{0x01, 0xff},
+			expectedLimit: []byte{0x02},
+		},
+		{
+			prefix:        []byte{0xff, 0xff},
+			expectedLimit: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%x", test.prefix), func(t *testing.T) {
+			require.Equal(t, test.expectedLimit, prefixLimit(test.prefix))
+		})
+	}
+}
+
+func TestRouting(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		defaultDB = memdb.New()
+		hotDB     = memdb.New()
+		coldDB    = memdb.New()
+	)
+	db, err := New(defaultDB, []Route{
+		NewPrefixRoute([]byte("hot"), hotDB),
+		{
+			Start: []byte("cold"),
+			Limit: []byte("coldz"),
+			DB:    coldDB,
+		},
+	})
+	require.NoError(err)
+
+	require.NoError(db.Put([]byte("hot1"), []byte("1")))
+	require.NoError(db.Put([]byte("cold1"), []byte("2")))
+	require.NoError(db.Put([]byte("other"), []byte("3")))
+
+	batch := db.NewBatch()
+	require.NoError(batch.Put([]byte("hot2"), []byte("4")))
+	require.NoError(batch.Put([]byte("coldz"), []byte("5")))
+	require.NoError(batch.Write())
+
+	requireKeys(t, hotDB, "hot1", "hot2")
+	requireKeys(t, coldDB, "cold1")
+	requireKeys(t, defaultDB, "coldz", "other")
+	requireKeys(t, db, "cold1", "coldz", "hot1", "hot2", "other")
+}
+
+func TestSetRoutesAndRebalance(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		defaultDB = memdb.New()
+		hotDB     = memdb.New()
+		coldDB    = memdb.New()
+	)
+	db, err := New(defaultDB, []Route{
+		NewPrefixRoute([]byte("a"), hotDB),
+	})
+	require.NoError(err)
+
+	for i := 0; i < 2*rebalanceBatchSize; i++ {
+		key := []byte(fmt.Sprintf("a%05d", i))
+		require.NoError(db.Put(key, key))
+	}
+	require.NoError(db.Put([]byte("b"), []byte("b")))
+
+	// Move the "a" prefix from [hotDB] to [coldDB].
+	require.NoError(db.SetRoutes(defaultDB, []Route{
+		NewPrefixRoute([]byte("a"), coldDB),
+	}))
+	require.ErrorIs(db.SetRoutes(defaultDB, nil), errRebalancePending)
+
+	// Keys that haven't been moved are still readable.
+	value, err := db.Get([]byte("a00000"))
+	require.NoError(err)
+	require.Equal([]byte("a00000"), value)
+
+	// Overwritten and deleted keys are never read from the previous database.
+	require.NoError(db.Put([]byte("a00001"), []byte("new")))
+	require.NoError(db.Delete([]byte("a00002")))
+
+	has, err := db.Has([]byte("a00002"))
+	require.NoError(err)
+	require.False(has)
+
+	it := db.NewIteratorWithPrefix([]byte("a0000"))
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	require.NoError(it.Error())
+	it.Release()
+	require.Equal([]string{"a00000", "a00001", "a00003", "a00004", "a00005", "a00006", "a00007", "a00008", "a00009"}, keys)
+
+	require.NoError(db.Rebalance(context.Background()))
+
+	count, err := database.Count(hotDB)
+	require.NoError(err)
+	require.Zero(count)
+
+	count, err = database.Count(coldDB)
+	require.NoError(err)
+	require.Equal(2*rebalanceBatchSize-1, count)
+
+	value, err = db.Get([]byte("a00001"))
+	require.NoError(err)
+	require.Equal([]byte("new"), value)
+
+	_, err = db.Get([]byte("a00002"))
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// The routes can be changed again once rebalancing finished.
+	require.NoError(db.SetRoutes(defaultDB, nil))
+}
+
+func TestRebalanceCanceled(t *testing.T) {
+	require := require.New(t)
+
+	db := newTestDB(t, testRoutes["prefix route"])
+	require.NoError(db.SetRoutes(memdb.New(), nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(db.Rebalance(ctx), context.Canceled)
+	require.ErrorIs(db.SetRoutes(memdb.New(), nil), errRebalancePending)
+}
+
+func requireKeys(t *testing.T, db database.Iteratee, expectedKeys ...string) {
+	t.Helper()
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	require.NoError(t, it.Error())
+	require.Equal(t, expectedKeys, keys)
+}
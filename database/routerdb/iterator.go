@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package routerdb
+
+import (
+	"bytes"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var _ database.Iterator = (*iterator)(nil)
+
+// source is an iterator over one of the underlying databases.
+type source struct {
+	db database.Database
+	database.Iterator
+
+	// True iff [Iterator] should be advanced before its key is read.
+	stale bool
+	// True iff [Iterator] has no more keys.
+	exhausted bool
+}
+
+// iterator merges the iterators of every underlying database in key order.
+// Keys are only returned by the database they are routed to, so keys left in
+// a database by a route change are skipped.
+type iterator struct {
+	db                *Database
+	current, previous *table
+	sources           []*source
+
+	key, value []byte
+	err        error
+}
+
+func newIterator(db *Database, current, previous *table, start, prefix []byte) *iterator {
+	underlyingDBs := databases(current, previous)
+	sources := make([]*source, len(underlyingDBs))
+	for i, underlyingDB := range underlyingDBs {
+		sources[i] = &source{
+			db:       underlyingDB,
+			Iterator: underlyingDB.NewIteratorWithStartAndPrefix(start, prefix),
+			stale:    true,
+		}
+	}
+	return &iterator{
+		db:       db,
+		current:  current,
+		previous: previous,
+		sources:  sources,
+	}
+}
+
+func (it *iterator) Next() bool {
+	// Short-circuit and set an error if the underlying database has been closed.
+	if it.db.isClosed() {
+		it.key = nil
+		it.value = nil
+		it.err = database.ErrClosed
+		return false
+	}
+
+	for {
+		if !it.advance() {
+			it.key = nil
+			it.value = nil
+			return false
+		}
+
+		// Find all the sources positioned at the smallest key.
+		var (
+			key     []byte
+			matches []*source
+		)
+		for _, source := range it.sources {
+			if source.exhausted {
+				continue
+			}
+			sourceKey := source.Key()
+			switch cmp := bytes.Compare(sourceKey, key); {
+			case len(matches) == 0 || cmp < 0:
+				key = sourceKey
+				matches = append(matches[:0], source)
+			case cmp == 0:
+				matches = append(matches, source)
+			}
+		}
+		if len(matches) == 0 {
+			it.key = nil
+			it.value = nil
+			return false
+		}
+
+		// Every source positioned at [key] is consumed. Prefer the value
+		// stored in the database [key] is currently routed to.
+		var chosen *source
+		owner := it.current.owner(key)
+		for _, source := range matches {
+			source.stale = true
+			switch {
+			case source.db == owner:
+				chosen = source
+			case chosen == nil && it.previous != nil && source.db == it.previous.owner(key):
+				chosen = source
+			}
+		}
+		if chosen != nil {
+			it.key = chosen.Key()
+			it.value = chosen.Value()
+			return true
+		}
+	}
+}
+
+// advance moves every source whose key has been consumed to its next key.
+// Returns false if a source reported an error.
+func (it *iterator) advance() bool {
+	for _, source := range it.sources {
+		if !source.stale {
+			continue
+		}
+		source.stale = false
+		source.exhausted = !source.Iterator.Next()
+		if !source.exhausted {
+			continue
+		}
+		if err := source.Iterator.Error(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	return true
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	for _, source := range it.sources {
+		if err := source.Iterator.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+func (it *iterator) Value() []byte {
+	return it.value
+}
+
+func (it *iterator) Release() {
+	it.key = nil
+	it.value = nil
+	for _, source := range it.sources {
+		source.Iterator.Release()
+	}
+}
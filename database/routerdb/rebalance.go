@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package routerdb
+
+import (
+	"context"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+// The maximum number of keys inspected while holding the lock during Rebalance.
+const rebalanceBatchSize = 1024
+
+// Rebalance moves every key that isn't stored in the database it is routed to
+// into that database. Once every key has been moved, the routes may be changed
+// again.
+//
+// Rebalance moves keys in small batches so that other operations can continue
+// while it runs.
+func (db *Database) Rebalance(ctx context.Context) error {
+	db.lock.RLock()
+	var (
+		closed        = db.closed
+		generation    = db.generation
+		underlyingDBs = db.databases()
+	)
+	db.lock.RUnlock()
+
+	if closed {
+		return database.ErrClosed
+	}
+
+	for _, underlyingDB := range underlyingDBs {
+		var (
+			start []byte
+			done  bool
+		)
+		for !done {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var err error
+			start, done, err = db.rebalanceBatch(underlyingDB, start)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	// If the routes changed while rebalancing, keys may have been routed to a
+	// database that was already rebalanced.
+	if db.generation == generation {
+		db.previous = nil
+	}
+	return nil
+}
+
+// rebalanceBatch inspects up to [rebalanceBatchSize] keys in [source], that are
+// at least [start], and moves the keys that aren't routed to [source].
+// Returns the key to continue rebalancing [source] from and true if [source]
+// was exhausted.
+func (db *Database) rebalanceBatch(source database.Database, start []byte) ([]byte, bool, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, false, database.ErrClosed
+	}
+
+	var (
+		it      = source.NewIteratorWithStart(start)
+		deletes = source.NewBatch()
+		moves   = make(map[database.Database]database.Batch)
+		scanned int
+		next    []byte
+	)
+	defer it.Release()
+
+	for scanned < rebalanceBatchSize && it.Next() {
+		key := it.Key()
+		scanned++
+		next = key
+
+		owner := db.current.owner(key)
+		if owner == source {
+			continue
+		}
+
+		// A value written to [owner] is always newer than the value left in
+		// [source].
+		has, err := owner.Has(key)
+		if err != nil {
+			return nil, false, err
+		}
+		if !has {
+			batch, ok := moves[owner]
+			if !ok {
+				batch = owner.NewBatch()
+				moves[owner] = batch
+			}
+			if err := batch.Put(key, it.Value()); err != nil {
+				return nil, false, err
+			}
+		}
+		if err := deletes.Delete(key); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, false, err
+	}
+
+	// Values must be written to their new database before they are deleted
+	// from [source] so they are never unreadable.
+	for _, batch := range moves {
+		if err := batch.Write(); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := deletes.Write(); err != nil {
+		return nil, false, err
+	}
+
+	if scanned < rebalanceBatchSize {
+		return nil, true, nil
+	}
+	// Continue from the smallest key that is greater than [next].
+	return append(slices.Clone(next), 0), false, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package routerdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var (
+	errNilDatabase       = errors.New("nil database")
+	errEmptyRange        = errors.New("route start must be less than its limit")
+	errOverlappingRoutes = errors.New("overlapping routes")
+)
+
+// Route sends all keys in the range [Start, Limit) to DB.
+type Route struct {
+	// Start is the inclusive lower bound of the routed keys.
+	// A nil Start has no lower bound.
+	Start []byte
+	// Limit is the exclusive upper bound of the routed keys.
+	// A nil Limit has no upper bound.
+	Limit []byte
+	// DB stores every key in the route.
+	DB database.Database
+}
+
+// NewPrefixRoute returns a route that sends all keys beginning with [prefix]
+// to [db].
+func NewPrefixRoute(prefix []byte, db database.Database) Route {
+	return Route{
+		Start: slices.Clone(prefix),
+		Limit: prefixLimit(prefix),
+		DB:    db,
+	}
+}
+
+// prefixLimit returns the smallest key that is greater than every key
+// beginning with [prefix], or nil if no such key exists.
+func prefixLimit(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			limit := slices.Clone(prefix[:i+1])
+			limit[i]++
+			return limit
+		}
+	}
+	return nil
+}
+
+func (r *Route) contains(key []byte) bool {
+	return bytes.Compare(r.Start, key) <= 0 &&
+		(r.Limit == nil || bytes.Compare(key, r.Limit) < 0)
+}
+
+// table maps every key to exactly one database.
+type table struct {
+	// Sorted by Start with no two routes overlapping.
+	routes []Route
+	// Stores every key that isn't contained in a route.
+	defaultDB database.Database
+}
+
+func newTable(defaultDB database.Database, routes []Route) (*table, error) {
+	if defaultDB == nil {
+		return nil, errNilDatabase
+	}
+
+	routes = slices.Clone(routes)
+	slices.SortFunc(routes, func(a, b Route) bool {
+		return bytes.Compare(a.Start, b.Start) < 0
+	})
+	for i, route := range routes {
+		if route.DB == nil {
+			return nil, fmt.Errorf("%w for route %d", errNilDatabase, i)
+		}
+		if route.Limit != nil && bytes.Compare(route.Start, route.Limit) >= 0 {
+			return nil, fmt.Errorf("%w: [%x, %x)", errEmptyRange, route.Start, route.Limit)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := routes[i-1]
+		if previous.Limit == nil || bytes.Compare(previous.Limit, route.Start) > 0 {
+			return nil, fmt.Errorf("%w: [%x, %x) and [%x, %x)",
+				errOverlappingRoutes,
+				previous.Start,
+				previous.Limit,
+				route.Start,
+				route.Limit,
+			)
+		}
+	}
+	return &table{
+		routes:    routes,
+		defaultDB: defaultDB,
+	}, nil
+}
+
+// owner returns the database that [key] is routed to.
+func (t *table) owner(key []byte) database.Database {
+	// Find the last route that starts at or before [key].
+	i, found := slices.BinarySearchFunc(t.routes, key, func(r Route, key []byte) int {
+		return bytes.Compare(r.Start, key)
+	})
+	if !found {
+		i--
+	}
+	if i >= 0 && t.routes[i].contains(key) {
+		return t.routes[i].DB
+	}
+	return t.defaultDB
+}
+
+// databases returns every distinct database referenced by [t].
+func (t *table) databases() []database.Database {
+	dbs := []database.Database{t.defaultDB}
+	for _, route := range t.routes {
+		if !slices.Contains(dbs, route.DB) {
+			dbs = append(dbs, route.DB)
+		}
+	}
+	return dbs
+}
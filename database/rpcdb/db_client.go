@@ -41,7 +41,7 @@ func (db *DatabaseClient) Has(key []byte) (bool, error) {
 		Key: key,
 	})
 	if err != nil {
-		return false, err
+		return false, rpcError(&database.Error{Op: "has", Key: key}, err)
 	}
 	return resp.Has, errEnumToError[resp.Err]
 }
@@ -52,7 +52,7 @@ func (db *DatabaseClient) Get(key []byte) ([]byte, error) {
 		Key: key,
 	})
 	if err != nil {
-		return nil, err
+		return nil, rpcError(&database.Error{Op: "get", Key: key}, err)
 	}
 	return resp.Value, errEnumToError[resp.Err]
 }
@@ -64,7 +64,7 @@ func (db *DatabaseClient) Put(key, value []byte) error {
 		Value: value,
 	})
 	if err != nil {
-		return err
+		return rpcError(&database.Error{Op: "put", Key: key}, err)
 	}
 	return errEnumToError[resp.Err]
 }
@@ -75,7 +75,7 @@ func (db *DatabaseClient) Delete(key []byte) error {
 		Key: key,
 	})
 	if err != nil {
-		return err
+		return rpcError(&database.Error{Op: "delete", Key: key}, err)
 	}
 	return errEnumToError[resp.Err]
 }
@@ -105,10 +105,10 @@ func (db *DatabaseClient) NewIteratorWithStartAndPrefix(start, prefix []byte) da
 	})
 	if err != nil {
 		return &database.IteratorError{
-			Err: err,
+			Err: rpcError(&database.Error{Op: "iterate", Start: start, Prefix: prefix}, err),
 		}
 	}
-	return newIterator(db, resp.Id)
+	return newIterator(db, resp.Id, start, prefix)
 }
 
 // Compact attempts to optimize the space utilization in the provided range
@@ -118,7 +118,7 @@ func (db *DatabaseClient) Compact(start, limit []byte) error {
 		Limit: limit,
 	})
 	if err != nil {
-		return err
+		return rpcError(&database.Error{Op: "compact", Start: start, Limit: limit}, err)
 	}
 	return errEnumToError[resp.Err]
 }
@@ -128,7 +128,7 @@ func (db *DatabaseClient) Close() error {
 	db.closed.Set(true)
 	resp, err := db.client.Close(context.Background(), &rpcdbpb.CloseRequest{})
 	if err != nil {
-		return err
+		return rpcError(&database.Error{Op: "close"}, err)
 	}
 	return errEnumToError[resp.Err]
 }
@@ -173,7 +173,7 @@ func (b *batch) Write() error {
 
 	resp, err := b.db.client.WriteBatch(context.Background(), request)
 	if err != nil {
-		return err
+		return rpcError(&database.Error{Op: "write batch"}, err)
 	}
 	return errEnumToError[resp.Err]
 }
@@ -186,6 +186,9 @@ type iterator struct {
 	db *DatabaseClient
 	id uint64
 
+	// The bounds the iterator was created with, to describe its errors.
+	start, prefix []byte
+
 	data        []*rpcdbpb.PutRequest
 	fetchedData chan []*rpcdbpb.PutRequest
 
@@ -199,10 +202,12 @@ type iterator struct {
 	onClosed chan struct{}
 }
 
-func newIterator(db *DatabaseClient, id uint64) *iterator {
+func newIterator(db *DatabaseClient, id uint64, start, prefix []byte) *iterator {
 	it := &iterator{
 		db:             db,
 		id:             id,
+		start:          start,
+		prefix:         prefix,
 		fetchedData:    make(chan []*rpcdbpb.PutRequest),
 		reqUpdateError: make(chan chan struct{}),
 		onClose:        make(chan struct{}),
@@ -222,7 +227,7 @@ func (it *iterator) fetch() {
 			Id: it.id,
 		})
 		if err != nil {
-			it.setError(err)
+			it.setError(it.rpcError(err))
 		} else {
 			it.setError(errEnumToError[resp.Err])
 		}
@@ -236,7 +241,7 @@ func (it *iterator) fetch() {
 			Id: it.id,
 		})
 		if err != nil {
-			it.setError(err)
+			it.setError(it.rpcError(err))
 			return
 		}
 
@@ -322,12 +327,18 @@ func (it *iterator) updateError() {
 		Id: it.id,
 	})
 	if err != nil {
-		it.setError(err)
+		it.setError(it.rpcError(err))
 	} else {
 		it.setError(errEnumToError[resp.Err])
 	}
 }
 
+// rpcError returns [err], the failure of a request for the iterator, as a
+// [database.Error].
+func (it *iterator) rpcError(err error) error {
+	return rpcError(&database.Error{Op: "iterate", Start: it.start, Prefix: it.prefix}, err)
+}
+
 func (it *iterator) setError(err error) {
 	if err == nil {
 		return
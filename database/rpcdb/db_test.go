@@ -9,6 +9,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/corruptabledb"
 	"github.com/ava-labs/avalanchego/database/memdb"
@@ -135,3 +138,25 @@ func TestHealthCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestRPCErrorIsTransient(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{err: status.Error(codes.Unavailable, "unavailable"), transient: true},
+		{err: status.Error(codes.DeadlineExceeded, "deadline exceeded"), transient: true},
+		{err: status.Error(codes.Internal, "internal"), transient: false},
+	}
+	for _, test := range tests {
+		require := require.New(t)
+
+		err := rpcError(&database.Error{Op: "get", Key: []byte("key")}, test.err)
+		require.ErrorIs(err, test.err)
+		require.Equal(test.transient, database.IsTransient(err))
+
+		var dbErr *database.Error
+		require.ErrorAs(err, &dbErr)
+		require.Equal(Name, dbErr.Backend)
+	}
+}
@@ -4,11 +4,17 @@
 package rpcdb
 
 import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/ava-labs/avalanchego/database"
 
 	rpcdbpb "github.com/ava-labs/avalanchego/proto/pb/rpcdb"
 )
 
+// Name is the backend name of the errors of [DatabaseClient].
+const Name = "rpcdb"
+
 var (
 	errEnumToError = map[rpcdbpb.Error]error{
 		rpcdbpb.Error_ERROR_CLOSED:    database.ErrClosed,
@@ -20,9 +26,32 @@ var (
 	}
 )
 
+// gRPC status codes of failed requests that may succeed if they're retried.
+var transientCodes = map[codes.Code]bool{
+	codes.Aborted:           true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Unavailable:       true,
+}
+
+// errorToRPCError returns the error that the server returns for [err]. The
+// database's transient errors are reported as unavailable, so that the client
+// knows they're transient.
 func errorToRPCError(err error) error {
 	if _, ok := errorToErrEnum[err]; ok {
 		return nil
 	}
+	if database.IsTransient(err) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
 	return err
 }
+
+// rpcError returns [err], the failure of a request to the server, wrapped in
+// [e], which describes the operation of the request.
+func rpcError(e *database.Error, err error) error {
+	e.Backend = Name
+	e.Transient = transientCodes[status.Code(err)] || database.IsTransient(err)
+	e.Err = err
+	return e
+}
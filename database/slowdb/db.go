@@ -0,0 +1,284 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slowdb
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+const (
+	HasOp Op = iota
+	GetOp
+	PutOp
+	DeleteOp
+	BatchWriteOp
+	IteratorNextOp
+	CompactOp
+)
+
+var (
+	_ database.Database = (*Database)(nil)
+	_ database.Batch    = (*batch)(nil)
+	_ database.Iterator = (*iterator)(nil)
+
+	// ErrInjected is returned by operations that were chosen to fail. The
+	// operation isn't performed, so retrying it may succeed.
+	ErrInjected = errors.New("injected error")
+
+	errInvalidErrorRate = errors.New("error rate must be in [0, 1]")
+)
+
+// Op is a type of database operation.
+type Op uint8
+
+func (op Op) String() string {
+	switch op {
+	case HasOp:
+		return "has"
+	case GetOp:
+		return "get"
+	case PutOp:
+		return "put"
+	case DeleteOp:
+		return "delete"
+	case BatchWriteOp:
+		return "batch_write"
+	case IteratorNextOp:
+		return "iterator_next"
+	case CompactOp:
+		return "compact"
+	default:
+		return fmt.Sprintf("Op(%d)", op)
+	}
+}
+
+// Distribution samples the latency to add to an operation.
+type Distribution func(r *rand.Rand) time.Duration
+
+// Constant returns a distribution that always adds [d].
+func Constant(d time.Duration) Distribution {
+	return func(*rand.Rand) time.Duration {
+		return d
+	}
+}
+
+// Uniform returns a distribution that adds a latency chosen uniformly from
+// [minLatency, maxLatency).
+func Uniform(minLatency, maxLatency time.Duration) Distribution {
+	return func(r *rand.Rand) time.Duration {
+		if maxLatency <= minLatency {
+			return minLatency
+		}
+		return minLatency + time.Duration(r.Int63n(int64(maxLatency-minLatency)))
+	}
+}
+
+// Normal returns a distribution that adds a normally distributed latency.
+// Negative samples are replaced with 0.
+func Normal(mean, stddev time.Duration) Distribution {
+	return func(r *rand.Rand) time.Duration {
+		d := time.Duration(r.NormFloat64()*float64(stddev)) + mean
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+}
+
+// OpConfig configures the faults injected into one type of operation.
+type OpConfig struct {
+	// Latency added before the operation is performed. If nil, no latency is
+	// added.
+	Latency Distribution
+	// ErrorRate is the probability that the operation fails with
+	// [ErrInjected] instead of being performed.
+	ErrorRate float64
+}
+
+type Config struct {
+	// Seed of the randomness used to sample latencies and errors.
+	Seed int64
+	// Op --> faults injected into operations of that type. Operations that
+	// aren't present are passed through unchanged.
+	Ops map[Op]OpConfig
+}
+
+// Database wraps a database and injects latency and transient errors into its
+// operations, to test how its users behave when the disk is slow or flaky.
+//
+// Database should only be used in tests and development.
+type Database struct {
+	database.Database
+
+	ops map[Op]OpConfig
+
+	randLock sync.Mutex
+	rand     *rand.Rand
+}
+
+func New(db database.Database, config Config) (*Database, error) {
+	for op, opConfig := range config.Ops {
+		if opConfig.ErrorRate < 0 || opConfig.ErrorRate > 1 {
+			return nil, fmt.Errorf("%w: %s has %f", errInvalidErrorRate, op, opConfig.ErrorRate)
+		}
+	}
+	return &Database{
+		Database: db,
+		ops:      config.Ops,
+		rand:     rand.New(rand.NewSource(config.Seed)), // #nosec G404
+	}, nil
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	if err := db.inject(HasOp); err != nil {
+		return false, err
+	}
+	return db.Database.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	if err := db.inject(GetOp); err != nil {
+		return nil, err
+	}
+	return db.Database.Get(key)
+}
+
+func (db *Database) Put(key []byte, value []byte) error {
+	if err := db.inject(PutOp); err != nil {
+		return err
+	}
+	return db.Database.Put(key, value)
+}
+
+func (db *Database) Delete(key []byte) error {
+	if err := db.inject(DeleteOp); err != nil {
+		return err
+	}
+	return db.Database.Delete(key)
+}
+
+func (db *Database) Compact(start []byte, limit []byte) error {
+	if err := db.inject(CompactOp); err != nil {
+		return err
+	}
+	return db.Database.Compact(start, limit)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return &iterator{
+		Iterator: db.Database.NewIterator(),
+		db:       db,
+	}
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return &iterator{
+		Iterator: db.Database.NewIteratorWithStart(start),
+		db:       db,
+	}
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return &iterator{
+		Iterator: db.Database.NewIteratorWithPrefix(prefix),
+		db:       db,
+	}
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &iterator{
+		Iterator: db.Database.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       db,
+	}
+}
+
+// inject sleeps for a latency sampled from the config of [op] and then returns
+// [ErrInjected] with the configured error rate of [op].
+func (db *Database) inject(op Op) error {
+	opConfig, ok := db.ops[op]
+	if !ok {
+		return nil
+	}
+
+	db.randLock.Lock()
+	var latency time.Duration
+	if opConfig.Latency != nil {
+		latency = opConfig.Latency(db.rand)
+	}
+	fail := opConfig.ErrorRate > 0 && db.rand.Float64() < opConfig.ErrorRate
+	db.randLock.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+	if fail {
+		return fmt.Errorf("%w: %s", ErrInjected, op)
+	}
+	return nil
+}
+
+type batch struct {
+	database.Batch
+	db *Database
+}
+
+func (b *batch) Write() error {
+	if err := b.db.inject(BatchWriteOp); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+// iterator stops with [ErrInjected] once an injected error occurs, because
+// iteration can't be resumed from the failed position.
+type iterator struct {
+	database.Iterator
+	db  *Database
+	err error
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.db.inject(IteratorNextOp); err != nil {
+		it.err = err
+		return false
+	}
+	return it.Iterator.Next()
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+func (it *iterator) Key() []byte {
+	if it.err != nil {
+		return nil
+	}
+	return it.Iterator.Key()
+}
+
+func (it *iterator) Value() []byte {
+	if it.err != nil {
+		return nil
+	}
+	return it.Iterator.Value()
+}
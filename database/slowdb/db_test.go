@@ -0,0 +1,169 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slowdb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db, err := New(memdb.New(), Config{
+			Ops: map[Op]OpConfig{
+				GetOp: {
+					Latency: Uniform(0, time.Microsecond),
+				},
+			},
+		})
+		require.NoError(t, err)
+		test(t, db)
+	}
+}
+
+func FuzzKeyValue(f *testing.F) {
+	db, err := New(memdb.New(), Config{})
+	require.NoError(f, err)
+	database.FuzzKeyValue(f, db)
+}
+
+func FuzzNewIteratorWithPrefix(f *testing.F) {
+	db, err := New(memdb.New(), Config{})
+	require.NoError(f, err)
+	database.FuzzNewIteratorWithPrefix(f, db)
+}
+
+func TestNewInvalidErrorRate(t *testing.T) {
+	_, err := New(memdb.New(), Config{
+		Ops: map[Op]OpConfig{
+			PutOp: {
+				ErrorRate: 1.5,
+			},
+		},
+	})
+	require.ErrorIs(t, err, errInvalidErrorRate)
+}
+
+func TestInjectedErrors(t *testing.T) {
+	require := require.New(t)
+
+	key := []byte("hello")
+	value := []byte("world")
+
+	baseDB := memdb.New()
+	require.NoError(baseDB.Put(key, value))
+
+	db, err := New(baseDB, Config{
+		Ops: map[Op]OpConfig{
+			GetOp: {
+				ErrorRate: 1,
+			},
+			PutOp: {
+				ErrorRate: 1,
+			},
+			BatchWriteOp: {
+				ErrorRate: 1,
+			},
+			IteratorNextOp: {
+				ErrorRate: 1,
+			},
+		},
+	})
+	require.NoError(err)
+
+	// Operations that aren't configured are passed through.
+	has, err := db.Has(key)
+	require.NoError(err)
+	require.True(has)
+
+	_, err = db.Get(key)
+	require.ErrorIs(err, ErrInjected)
+
+	// Failed operations aren't performed.
+	err = db.Put(key, []byte("other"))
+	require.ErrorIs(err, ErrInjected)
+
+	batch := db.NewBatch()
+	require.NoError(batch.Delete(key))
+	err = batch.Write()
+	require.ErrorIs(err, ErrInjected)
+
+	gotValue, err := baseDB.Get(key)
+	require.NoError(err)
+	require.Equal(value, gotValue)
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	require.False(iter.Next())
+	require.Nil(iter.Key())
+	require.Nil(iter.Value())
+	require.ErrorIs(iter.Error(), ErrInjected)
+}
+
+func TestInjectedLatency(t *testing.T) {
+	require := require.New(t)
+
+	latency := 10 * time.Millisecond
+	db, err := New(memdb.New(), Config{
+		Ops: map[Op]OpConfig{
+			PutOp: {
+				Latency: Constant(latency),
+			},
+		},
+	})
+	require.NoError(err)
+
+	start := time.Now()
+	require.NoError(db.Put([]byte("hello"), []byte("world")))
+	require.GreaterOrEqual(time.Since(start), latency)
+}
+
+func TestDistributions(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) // #nosec G404
+
+	tests := map[string]struct {
+		distribution Distribution
+		min          time.Duration
+		max          time.Duration
+	}{
+		"constant": {
+			distribution: Constant(time.Second),
+			min:          time.Second,
+			max:          time.Second,
+		},
+		"uniform": {
+			distribution: Uniform(time.Second, 2*time.Second),
+			min:          time.Second,
+			max:          2 * time.Second,
+		},
+		"empty uniform": {
+			distribution: Uniform(time.Second, time.Second),
+			min:          time.Second,
+			max:          time.Second,
+		},
+		"normal": {
+			distribution: Normal(0, time.Second),
+			min:          0,
+			max:          time.Hour,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			for i := 0; i < 1_000; i++ {
+				d := test.distribution(r)
+				require.GreaterOrEqual(d, test.min)
+				require.LessOrEqual(d, test.max)
+			}
+		})
+	}
+}
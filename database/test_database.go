@@ -56,6 +56,7 @@ var Tests = []func(t *testing.T, db Database){
 	TestClear,
 	TestAtomicClearPrefix,
 	TestClearPrefix,
+	TestDeleteRange,
 	TestModifyValueAfterPut,
 	TestModifyValueAfterBatchPut,
 	TestModifyValueAfterBatchPutReplay,
@@ -989,6 +990,51 @@ func testClear(t *testing.T, db Database, clearF func(Database) error) {
 	require.NoError(db.Close())
 }
 
+// TestDeleteRange tests to make sure that DeleteRange only removes the keys in
+// the range, if the database supports range deletes.
+func TestDeleteRange(t *testing.T, db Database) {
+	if !GetCapabilities(db).RangeDelete {
+		return
+	}
+	rangeDeleter := db.(RangeDeleter)
+
+	require := require.New(t)
+
+	keys := [][]byte{
+		{0x00},
+		{0x01},
+		{0x01, 0x00},
+		{0x02},
+		{0xff},
+	}
+	for _, key := range keys {
+		require.NoError(db.Put(key, key))
+	}
+
+	require.NoError(rangeDeleter.DeleteRange([]byte{0x01}, []byte{0x02}))
+	for i, key := range keys {
+		has, err := db.Has(key)
+		require.NoError(err)
+		require.Equal(i != 1 && i != 2, has)
+	}
+
+	// An empty range doesn't remove any keys.
+	require.NoError(rangeDeleter.DeleteRange([]byte{0x02}, []byte{0x02}))
+	count, err := Count(db)
+	require.NoError(err)
+	require.Equal(3, count)
+
+	// A nil limit removes all keys after [start].
+	require.NoError(rangeDeleter.DeleteRange([]byte{0x02}, nil))
+	count, err = Count(db)
+	require.NoError(err)
+	require.Equal(1, count)
+
+	require.NoError(db.Close())
+	err = rangeDeleter.DeleteRange(nil, nil)
+	require.ErrorIs(err, ErrClosed)
+}
+
 func TestAtomicClearPrefix(t *testing.T, db Database) {
 	testClearPrefix(t, db, func(db Database, prefix []byte) error {
 		return AtomicClearPrefix(db, db, prefix)
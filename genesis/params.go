@@ -31,6 +31,14 @@ type StakingConfig struct {
 	MaxStakeDuration time.Duration `json:"maxStakeDuration"`
 	// RewardConfig is the config for the reward function.
 	RewardConfig reward.Config `json:"rewardConfig"`
+	// ValidatorChurnPeriod is the length of the periods that the churn of
+	// each subnet's validator set is limited over. If 0, the churn isn't
+	// limited. The churn isn't limited on Mainnet and Fuji.
+	ValidatorChurnPeriod time.Duration `json:"validatorChurnPeriod"`
+	// MaxValidatorChurn, in the range [0, 1000000], is the maximum fraction of
+	// a subnet's stake weight that can enter or exit its validator set in a
+	// single ValidatorChurnPeriod.
+	MaxValidatorChurn uint64 `json:"maxValidatorChurn"`
 }
 
 type TxFeeConfig struct {
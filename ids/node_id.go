@@ -76,6 +76,11 @@ func ToNodeID(bytes []byte) (NodeID, error) {
 	return NodeID(nodeID), err
 }
 
+// NodeIDFromCert returns the ID of the node that stakes with [cert].
+//
+// The ID is the RIPEMD-160 hash of the SHA-256 hash of the certificate's DER
+// encoding, so tooling that only has the certificate can derive the same ID
+// as the node.
 func NodeIDFromCert(cert *staking.Certificate) NodeID {
 	return hashing.ComputeHash160Array(
 		hashing.ComputeHash256(cert.Raw),
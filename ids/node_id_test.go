@@ -5,10 +5,15 @@ package ids
 
 import (
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils/cb58"
 )
 
@@ -34,6 +39,33 @@ func TestNodeIDFromString(t *testing.T) {
 	require.Equal(expected, idStr)
 }
 
+func TestNodeIDFromCert(t *testing.T) {
+	// The IDs of the local network stakers, as documented in
+	// staking/local/README.md.
+	expectedNodeIDs := []string{
+		"NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg",
+		"NodeID-MFrZFVCXPv5iCn6M9K6XduxGTYp891xXZ",
+		"NodeID-NFBbbJ4qCmNaCzeW7sxErhvWqvEQMnYcN",
+		"NodeID-GWPcbFJZFfZreETSoWjPimr846mXEKCtu",
+		"NodeID-P7oB2McjBGgW2NXXWVYjV8JEDFoW9xDE5",
+	}
+	for i, expectedNodeID := range expectedNodeIDs {
+		certFile := fmt.Sprintf("staker%d.crt", i+1)
+		t.Run(certFile, func(t *testing.T) {
+			require := require.New(t)
+
+			certPEM, err := os.ReadFile(filepath.Join("..", "staking", "local", certFile))
+			require.NoError(err)
+			block, _ := pem.Decode(certPEM)
+			require.NotNil(block)
+
+			cert, err := staking.ParseCertificate(block.Bytes)
+			require.NoError(err)
+			require.Equal(expectedNodeID, NodeIDFromCert(cert).String())
+		})
+	}
+}
+
 func TestNodeIDFromStringError(t *testing.T) {
 	tests := []struct {
 		in          string
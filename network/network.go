@@ -330,6 +330,30 @@ func (n *network) Gossip(
 	return n.send(msg, peers)
 }
 
+func (n *network) GossipPeers(subnetID ids.ID, allower subnets.Allower) map[ids.NodeID]uint64 {
+	n.peersLock.RLock()
+	defer n.peersLock.RUnlock()
+
+	peers := make(map[ids.NodeID]uint64, n.connectedPeers.Len())
+	for i := 0; i < n.connectedPeers.Len(); i++ {
+		p, _ := n.connectedPeers.GetByIndex(i)
+		// Only return peers that are tracking [subnetID]
+		trackedSubnets := p.TrackedSubnets()
+		if subnetID != constants.PrimaryNetworkID && !trackedSubnets.Contains(subnetID) {
+			continue
+		}
+
+		peerID := p.ID()
+		weight := n.config.Validators.GetWeight(subnetID, peerID)
+		// check if the peer is allowed to connect to the subnet
+		if !allower.IsAllowed(peerID, weight > 0) {
+			continue
+		}
+		peers[peerID] = weight
+	}
+	return peers
+}
+
 // HealthCheck returns information about several network layer health checks.
 // 1) Information about health check results
 // 2) An error if the health check reports unhealthy
@@ -11,8 +11,10 @@ import (
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -25,6 +27,8 @@ import (
 	"github.com/ava-labs/avalanchego/utils/profiler"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
+
+	platformconfig "github.com/ava-labs/avalanchego/vms/platformvm/config"
 )
 
 type IPCConfig struct {
@@ -73,6 +77,12 @@ type APIConfig struct {
 	HealthAPIEnabled   bool `json:"healthAPIEnabled"`
 }
 
+type ValidatorsGRPCConfig struct {
+	ValidatorsGRPCEnabled bool   `json:"validatorsGRPCEnabled"`
+	ValidatorsGRPCHost    string `json:"validatorsGRPCHost"`
+	ValidatorsGRPCPort    uint16 `json:"validatorsGRPCPort"`
+}
+
 type IPConfig struct {
 	IPPort           ips.DynamicIPPort `json:"ip"`
 	IPUpdater        dynamicip.Updater `json:"-"`
@@ -99,6 +109,11 @@ type StakingConfig struct {
 	StakingKeyPath                string          `json:"stakingKeyPath"`
 	StakingCertPath               string          `json:"stakingCertPath"`
 	StakingSignerPath             string          `json:"stakingSignerPath"`
+
+	// Subnet ID --> churn limit of its validator set, used instead of the
+	// ValidatorChurnPeriod and MaxValidatorChurn of the genesis config.
+	// Always empty on Mainnet and Fuji, where the churn isn't limited.
+	SubnetValidatorChurn map[ids.ID]platformconfig.ValidatorChurnConfig `json:"subnetValidatorChurn"`
 }
 
 type StateSyncConfig struct {
@@ -123,6 +138,10 @@ type BootstrapConfig struct {
 	// containers in an ancestors message it receives.
 	BootstrapAncestorsMaxContainersReceived int `json:"bootstrapAncestorsMaxContainersReceived"`
 
+	// Limits on the container requests that the bootstrapping chains of a
+	// subnet have outstanding at once.
+	BootstrapFetchSchedulerConfig common.FetchSchedulerConfig `json:"bootstrapFetchSchedulerConfig"`
+
 	// Max time to spend fetching a container and its
 	// ancestors while responding to a GetAncestors message
 	BootstrapMaxTimeGetAncestors time.Duration `json:"bootstrapMaxTimeGetAncestors"`
@@ -143,13 +162,14 @@ type DatabaseConfig struct {
 
 // Config contains all of the configurations of an Avalanche node.
 type Config struct {
-	HTTPConfig          `json:"httpConfig"`
-	IPConfig            `json:"ipConfig"`
-	StakingConfig       `json:"stakingConfig"`
-	genesis.TxFeeConfig `json:"txFeeConfig"`
-	StateSyncConfig     `json:"stateSyncConfig"`
-	BootstrapConfig     `json:"bootstrapConfig"`
-	DatabaseConfig      `json:"databaseConfig"`
+	HTTPConfig           `json:"httpConfig"`
+	ValidatorsGRPCConfig `json:"validatorsGRPCConfig"`
+	IPConfig             `json:"ipConfig"`
+	StakingConfig        `json:"stakingConfig"`
+	genesis.TxFeeConfig  `json:"txFeeConfig"`
+	StateSyncConfig      `json:"stateSyncConfig"`
+	BootstrapConfig      `json:"bootstrapConfig"`
+	DatabaseConfig       `json:"databaseConfig"`
 
 	// Genesis information
 	GenesisBytes []byte `json:"-"`
@@ -165,6 +185,9 @@ type Config struct {
 	NetworkConfig network.Config `json:"networkConfig"`
 
 	AdaptiveTimeoutConfig timer.AdaptiveTimeoutConfig `json:"adaptiveTimeoutConfig"`
+	// Response type --> timeout config of requests expecting that type of
+	// response. Response types that aren't present use [AdaptiveTimeoutConfig].
+	OpTimeoutConfigs map[message.Op]timer.AdaptiveTimeoutConfig `json:"opTimeoutConfigs"`
 
 	BenchlistConfig benchlist.Config `json:"benchlistConfig"`
 
@@ -184,11 +207,20 @@ type Config struct {
 	ConsensusRouter          router.Router       `json:"-"`
 	RouterHealthConfig       router.HealthConfig `json:"routerHealthConfig"`
 	ConsensusShutdownTimeout time.Duration       `json:"consensusShutdownTimeout"`
+	// Limits on the requests outstanding to each peer across all chains.
+	RequestBudgetConfig common.RequestBudgetConfig `json:"requestBudgetConfig"`
 	// Gossip a container in the accepted frontier every [AcceptedFrontierGossipFrequency]
 	AcceptedFrontierGossipFrequency time.Duration `json:"consensusGossipFreq"`
 	// ConsensusAppConcurrency defines the maximum number of goroutines to
 	// handle App messages per chain.
 	ConsensusAppConcurrency int `json:"consensusAppConcurrency"`
+	// ConsensusAppDrainTimeout is the maximum time to wait, when a chain
+	// stops, for the App messages being handled to finish before their
+	// contexts are cancelled.
+	ConsensusAppDrainTimeout time.Duration `json:"consensusAppDrainTimeout"`
+	// ConsensusAdaptiveSamplingEnabled enables the adaptive sampling of the
+	// chains that configure it. Experimental.
+	ConsensusAdaptiveSamplingEnabled bool `json:"consensusAdaptiveSamplingEnabled"`
 
 	TrackedSubnets set.Set[ids.ID] `json:"trackedSubnets"`
 
@@ -25,6 +25,8 @@ import (
 
 	"go.uber.org/zap"
 
+	"google.golang.org/grpc"
+
 	coreth "github.com/ava-labs/coreth/plugin/evm"
 
 	"github.com/ava-labs/avalanchego/api/admin"
@@ -52,12 +54,14 @@ import (
 	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/snow/validators/gvalidators"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils"
@@ -81,10 +85,12 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 	"github.com/ava-labs/avalanchego/vms/propertyfx"
 	"github.com/ava-labs/avalanchego/vms/registry"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/runtime"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	ipcsapi "github.com/ava-labs/avalanchego/api/ipcs"
+	validatorspb "github.com/ava-labs/avalanchego/proto/pb/validators"
 	avmconfig "github.com/ava-labs/avalanchego/vms/avm/config"
 	platformconfig "github.com/ava-labs/avalanchego/vms/platformvm/config"
 )
@@ -134,6 +140,9 @@ type Node struct {
 	// Manages network timeouts
 	timeoutManager timeout.Manager
 
+	// Limits the requests outstanding to each peer across all chains
+	requestBudget common.RequestBudget
+
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
@@ -173,6 +182,9 @@ type Node struct {
 	// Handles HTTP API calls
 	APIServer server.Server
 
+	// Serves the primary network validator set over gRPC. Nil if disabled.
+	validatorsGRPCServer *grpc.Server
+
 	// This node's configuration
 	Config *Config
 
@@ -659,6 +671,44 @@ func (n *Node) initIndexer() error {
 	return nil
 }
 
+// initValidatorsGRPCServer starts the gRPC server that serves the primary
+// network validator set, if it is enabled.
+// Assumes [n.vdrs] and [n.chainManager] are initialized.
+func (n *Node) initValidatorsGRPCServer() error {
+	if !n.Config.ValidatorsGRPCEnabled {
+		n.Log.Info("skipping validators gRPC server initialization because it has been disabled")
+		return nil
+	}
+
+	n.Log.Info("initializing validators gRPC server")
+
+	listenAddress := net.JoinHostPort(n.Config.ValidatorsGRPCHost, strconv.FormatUint(uint64(n.Config.ValidatorsGRPCPort), 10))
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return err
+	}
+
+	// The P-chain's validator state is only available once the P-chain has
+	// been created.
+	state := &platformValidatorState{}
+	n.chainManager.AddRegistrant(state)
+
+	n.validatorsGRPCServer = grpcutils.NewServer()
+	validatorspb.RegisterValidatorsServer(n.validatorsGRPCServer, gvalidators.NewManagerServer(n.vdrs, state))
+
+	n.Log.Info("validators gRPC server listening",
+		zap.Stringer("address", listener.Addr()),
+	)
+	go n.Log.RecoverAndPanic(func() {
+		if err := n.validatorsGRPCServer.Serve(listener); err != nil {
+			n.Log.Error("validators gRPC server failed",
+				zap.Error(err),
+			)
+		}
+	})
+	return nil
+}
+
 // Initializes the Platform chain.
 // Its genesis data specifies the other chains that should be created.
 func (n *Node) initChains(genesisBytes []byte) error {
@@ -799,6 +849,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 
 	n.timeoutManager, err = timeout.NewManager(
 		&n.Config.AdaptiveTimeoutConfig,
+		n.Config.OpTimeoutConfigs,
 		n.benchlistManager,
 		"requests",
 		n.MetricsRegisterer,
@@ -808,6 +859,11 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	}
 	go n.Log.RecoverAndPanic(n.timeoutManager.Dispatch)
 
+	n.requestBudget, err = common.NewRequestBudget(n.Config.RequestBudgetConfig)
+	if err != nil {
+		return fmt.Errorf("couldn't create request budget: %w", err)
+	}
+
 	// Routes incoming messages from peers to the appropriate chain
 	err = n.Config.ConsensusRouter.Initialize(
 		n.ID,
@@ -819,6 +875,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		n.Config.TrackedSubnets,
 		n.Shutdown,
 		n.Config.RouterHealthConfig,
+		n.requestBudget,
 		"requests",
 		n.MetricsRegisterer,
 	)
@@ -852,6 +909,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		CChainID:                                cChainID,
 		CriticalChains:                          criticalChains,
 		TimeoutManager:                          n.timeoutManager,
+		RequestBudget:                           n.requestBudget,
 		Health:                                  n.health,
 		RetryBootstrap:                          n.Config.RetryBootstrap,
 		RetryBootstrapWarnFrequency:             n.Config.RetryBootstrapWarnFrequency,
@@ -862,9 +920,12 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		ChainConfigs:                            n.Config.ChainConfigs,
 		AcceptedFrontierGossipFrequency:         n.Config.AcceptedFrontierGossipFrequency,
 		ConsensusAppConcurrency:                 n.Config.ConsensusAppConcurrency,
+		ConsensusAppDrainTimeout:                n.Config.ConsensusAppDrainTimeout,
+		ConsensusAdaptiveSamplingEnabled:        n.Config.ConsensusAdaptiveSamplingEnabled,
 		BootstrapMaxTimeGetAncestors:            n.Config.BootstrapMaxTimeGetAncestors,
 		BootstrapAncestorsMaxContainersSent:     n.Config.BootstrapAncestorsMaxContainersSent,
 		BootstrapAncestorsMaxContainersReceived: n.Config.BootstrapAncestorsMaxContainersReceived,
+		BootstrapFetchSchedulerConfig:           n.Config.BootstrapFetchSchedulerConfig,
 		ApricotPhase4Time:                       version.GetApricotPhase4Time(n.Config.NetworkID),
 		ApricotPhase4MinPChainHeight:            version.GetApricotPhase4MinPChainHeight(n.Config.NetworkID),
 		ResourceTracker:                         n.resourceTracker,
@@ -926,6 +987,9 @@ func (n *Node) initVMs() error {
 				MinStakeDuration:              n.Config.MinStakeDuration,
 				MaxStakeDuration:              n.Config.MaxStakeDuration,
 				RewardConfig:                  n.Config.RewardConfig,
+				ValidatorChurnPeriod:          n.Config.ValidatorChurnPeriod,
+				MaxValidatorChurn:             n.Config.MaxValidatorChurn,
+				SubnetValidatorChurn:          n.Config.SubnetValidatorChurn,
 				ApricotPhase3Time:             version.GetApricotPhase3Time(n.Config.NetworkID),
 				ApricotPhase5Time:             version.GetApricotPhase5Time(n.Config.NetworkID),
 				BanffTime:                     version.GetBanffTime(n.Config.NetworkID),
@@ -1473,6 +1537,9 @@ func (n *Node) Initialize(
 	if err := n.initIndexer(); err != nil {
 		return fmt.Errorf("couldn't initialize indexer: %w", err)
 	}
+	if err := n.initValidatorsGRPCServer(); err != nil {
+		return fmt.Errorf("couldn't initialize validators gRPC server: %w", err)
+	}
 
 	n.health.Start(context.TODO(), n.Config.HealthCheckFreq)
 	n.initProfiler()
@@ -1542,6 +1609,9 @@ func (n *Node) shutdown() {
 			zap.Error(err),
 		)
 	}
+	if n.validatorsGRPCServer != nil {
+		n.validatorsGRPCServer.Stop()
+	}
 	if err := n.indexer.Close(); err != nil {
 		n.Log.Debug("error closing tx indexer",
 			zap.Error(err),
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var (
+	_ validators.State  = (*platformValidatorState)(nil)
+	_ chains.Registrant = (*platformValidatorState)(nil)
+
+	errPlatformChainNotCreated = errors.New("platform chain not created")
+)
+
+// platformValidatorState exposes the validator state of the P-chain once the
+// P-chain has been created.
+type platformValidatorState struct {
+	lock  sync.RWMutex
+	state validators.State
+}
+
+func (p *platformValidatorState) RegisterChain(_ string, ctx *snow.ConsensusContext, _ common.VM) {
+	if ctx.ChainID != constants.PlatformChainID {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// The P-chain's validator state isn't protected by the context lock.
+	p.state = validators.NewLockedState(&ctx.Lock, ctx.ValidatorState)
+}
+
+func (p *platformValidatorState) GetMinimumHeight(ctx context.Context) (uint64, error) {
+	state, err := p.getState()
+	if err != nil {
+		return 0, err
+	}
+	return state.GetMinimumHeight(ctx)
+}
+
+func (p *platformValidatorState) GetCurrentHeight(ctx context.Context) (uint64, error) {
+	state, err := p.getState()
+	if err != nil {
+		return 0, err
+	}
+	return state.GetCurrentHeight(ctx)
+}
+
+func (p *platformValidatorState) GetSubnetID(ctx context.Context, chainID ids.ID) (ids.ID, error) {
+	state, err := p.getState()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return state.GetSubnetID(ctx, chainID)
+}
+
+func (p *platformValidatorState) GetValidatorSet(
+	ctx context.Context,
+	height uint64,
+	subnetID ids.ID,
+) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	state, err := p.getState()
+	if err != nil {
+		return nil, err
+	}
+	return state.GetValidatorSet(ctx, height, subnetID)
+}
+
+func (p *platformValidatorState) getState() (validators.State, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.state == nil {
+		return nil, errPlatformChainNotCreated
+	}
+	return p.state, nil
+}
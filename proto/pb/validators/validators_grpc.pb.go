@@ -0,0 +1,263 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: validators/validators.proto
+
+package validators
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Validators_GetCurrentHeight_FullMethodName     = "/validators.Validators/GetCurrentHeight"
+	Validators_GetCurrentValidators_FullMethodName = "/validators.Validators/GetCurrentValidators"
+	Validators_GetValidatorsAt_FullMethodName      = "/validators.Validators/GetValidatorsAt"
+	Validators_WatchValidators_FullMethodName      = "/validators.Validators/WatchValidators"
+)
+
+// ValidatorsClient is the client API for Validators service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ValidatorsClient interface {
+	// GetCurrentHeight returns the current height of the P-chain.
+	GetCurrentHeight(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetCurrentHeightResponse, error)
+	// GetCurrentValidators returns the current primary network validators.
+	GetCurrentValidators(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetCurrentValidatorsResponse, error)
+	// GetValidatorsAt returns the primary network validators at the requested
+	// P-chain height.
+	GetValidatorsAt(ctx context.Context, in *GetValidatorsAtRequest, opts ...grpc.CallOption) (*GetValidatorsAtResponse, error)
+	// WatchValidators streams changes to the current primary network
+	// validators. The stream starts with an addition for each current
+	// validator.
+	WatchValidators(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Validators_WatchValidatorsClient, error)
+}
+
+type validatorsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewValidatorsClient(cc grpc.ClientConnInterface) ValidatorsClient {
+	return &validatorsClient{cc}
+}
+
+func (c *validatorsClient) GetCurrentHeight(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetCurrentHeightResponse, error) {
+	out := new(GetCurrentHeightResponse)
+	err := c.cc.Invoke(ctx, Validators_GetCurrentHeight_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorsClient) GetCurrentValidators(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetCurrentValidatorsResponse, error) {
+	out := new(GetCurrentValidatorsResponse)
+	err := c.cc.Invoke(ctx, Validators_GetCurrentValidators_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorsClient) GetValidatorsAt(ctx context.Context, in *GetValidatorsAtRequest, opts ...grpc.CallOption) (*GetValidatorsAtResponse, error) {
+	out := new(GetValidatorsAtResponse)
+	err := c.cc.Invoke(ctx, Validators_GetValidatorsAt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validatorsClient) WatchValidators(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Validators_WatchValidatorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Validators_ServiceDesc.Streams[0], Validators_WatchValidators_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &validatorsWatchValidatorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Validators_WatchValidatorsClient interface {
+	Recv() (*ValidatorChange, error)
+	grpc.ClientStream
+}
+
+type validatorsWatchValidatorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *validatorsWatchValidatorsClient) Recv() (*ValidatorChange, error) {
+	m := new(ValidatorChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ValidatorsServer is the server API for Validators service.
+// All implementations must embed UnimplementedValidatorsServer
+// for forward compatibility
+type ValidatorsServer interface {
+	// GetCurrentHeight returns the current height of the P-chain.
+	GetCurrentHeight(context.Context, *emptypb.Empty) (*GetCurrentHeightResponse, error)
+	// GetCurrentValidators returns the current primary network validators.
+	GetCurrentValidators(context.Context, *emptypb.Empty) (*GetCurrentValidatorsResponse, error)
+	// GetValidatorsAt returns the primary network validators at the requested
+	// P-chain height.
+	GetValidatorsAt(context.Context, *GetValidatorsAtRequest) (*GetValidatorsAtResponse, error)
+	// WatchValidators streams changes to the current primary network
+	// validators. The stream starts with an addition for each current
+	// validator.
+	WatchValidators(*emptypb.Empty, Validators_WatchValidatorsServer) error
+	mustEmbedUnimplementedValidatorsServer()
+}
+
+// UnimplementedValidatorsServer must be embedded to have forward compatible implementations.
+type UnimplementedValidatorsServer struct {
+}
+
+func (UnimplementedValidatorsServer) GetCurrentHeight(context.Context, *emptypb.Empty) (*GetCurrentHeightResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentHeight not implemented")
+}
+func (UnimplementedValidatorsServer) GetCurrentValidators(context.Context, *emptypb.Empty) (*GetCurrentValidatorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentValidators not implemented")
+}
+func (UnimplementedValidatorsServer) GetValidatorsAt(context.Context, *GetValidatorsAtRequest) (*GetValidatorsAtResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValidatorsAt not implemented")
+}
+func (UnimplementedValidatorsServer) WatchValidators(*emptypb.Empty, Validators_WatchValidatorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchValidators not implemented")
+}
+func (UnimplementedValidatorsServer) mustEmbedUnimplementedValidatorsServer() {}
+
+// UnsafeValidatorsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ValidatorsServer will
+// result in compilation errors.
+type UnsafeValidatorsServer interface {
+	mustEmbedUnimplementedValidatorsServer()
+}
+
+func RegisterValidatorsServer(s grpc.ServiceRegistrar, srv ValidatorsServer) {
+	s.RegisterService(&Validators_ServiceDesc, srv)
+}
+
+func _Validators_GetCurrentHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorsServer).GetCurrentHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Validators_GetCurrentHeight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorsServer).GetCurrentHeight(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validators_GetCurrentValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorsServer).GetCurrentValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Validators_GetCurrentValidators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorsServer).GetCurrentValidators(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validators_GetValidatorsAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValidatorsAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidatorsServer).GetValidatorsAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Validators_GetValidatorsAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidatorsServer).GetValidatorsAt(ctx, req.(*GetValidatorsAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Validators_WatchValidators_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ValidatorsServer).WatchValidators(m, &validatorsWatchValidatorsServer{stream})
+}
+
+type Validators_WatchValidatorsServer interface {
+	Send(*ValidatorChange) error
+	grpc.ServerStream
+}
+
+type validatorsWatchValidatorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *validatorsWatchValidatorsServer) Send(m *ValidatorChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Validators_ServiceDesc is the grpc.ServiceDesc for Validators service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Validators_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "validators.Validators",
+	HandlerType: (*ValidatorsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCurrentHeight",
+			Handler:    _Validators_GetCurrentHeight_Handler,
+		},
+		{
+			MethodName: "GetCurrentValidators",
+			Handler:    _Validators_GetCurrentValidators_Handler,
+		},
+		{
+			MethodName: "GetValidatorsAt",
+			Handler:    _Validators_GetValidatorsAt_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchValidators",
+			Handler:       _Validators_WatchValidators_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "validators/validators.proto",
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+type scaledEarlyTermNoTraversalFactory struct {
+	k               int
+	alphaPreference int
+	alphaConfidence int
+}
+
+// NewScaledEarlyTermNoTraversalFactory returns a factory that returns polls
+// with early termination, without doing DAG traversals, that may sample fewer
+// than [k] validators.
+//
+// The alphas of a poll that samples fewer than [k] validators are scaled to its
+// sample size, and the counts of its result are scaled back to [k], so that
+// the result can be recorded against thresholds that are relative to [k].
+func NewScaledEarlyTermNoTraversalFactory(k, alphaPreference, alphaConfidence int) Factory {
+	return &scaledEarlyTermNoTraversalFactory{
+		k:               k,
+		alphaPreference: alphaPreference,
+		alphaConfidence: alphaConfidence,
+	}
+}
+
+func (f *scaledEarlyTermNoTraversalFactory) New(vdrs bag.Bag[ids.NodeID]) Poll {
+	sampleSize := vdrs.Len()
+	if sampleSize == 0 || sampleSize >= f.k {
+		return &earlyTermNoTraversalPoll{
+			polled:          vdrs,
+			alphaPreference: f.alphaPreference,
+			alphaConfidence: f.alphaConfidence,
+		}
+	}
+	return &scaledPoll{
+		Poll: &earlyTermNoTraversalPoll{
+			polled:          vdrs,
+			alphaPreference: ScaleAlpha(f.alphaPreference, sampleSize, f.k),
+			alphaConfidence: ScaleAlpha(f.alphaConfidence, sampleSize, f.k),
+		},
+		k:          f.k,
+		sampleSize: sampleSize,
+	}
+}
+
+// ScaleAlpha returns the number of votes out of [sampleSize] that is at least
+// the same fraction of the sample as [alpha] is of [k].
+func ScaleAlpha(alpha, sampleSize, k int) int {
+	return (alpha*sampleSize + k - 1) / k
+}
+
+// scaledPoll scales the counts of the result of a poll of [sampleSize]
+// validators to [k] validators. Since the scaled alphas are rounded up and the
+// scaled counts are rounded down, a count reaches a scaled alpha if and only if
+// its scaled count reaches the alpha.
+type scaledPoll struct {
+	Poll
+
+	k          int
+	sampleSize int
+}
+
+func (p *scaledPoll) Result() bag.Bag[ids.ID] {
+	votes := p.Poll.Result()
+	var scaled bag.Bag[ids.ID]
+	for _, vote := range votes.List() {
+		scaled.AddCount(vote, votes.Count(vote)*p.k/p.sampleSize)
+	}
+	return scaled
+}
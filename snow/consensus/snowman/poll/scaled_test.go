@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+func TestScaleAlpha(t *testing.T) {
+	tests := []struct {
+		alpha      int
+		sampleSize int
+		k          int
+		expected   int
+	}{
+		{alpha: 15, sampleSize: 20, k: 20, expected: 15},
+		{alpha: 15, sampleSize: 10, k: 20, expected: 8},
+		{alpha: 14, sampleSize: 10, k: 20, expected: 7},
+		{alpha: 1, sampleSize: 1, k: 20, expected: 1},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, ScaleAlpha(test.alpha, test.sampleSize, test.k))
+	}
+}
+
+func TestScaledEarlyTermNoTraversalFullSample(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := bag.Of(vdr1, vdr2) // k = 2
+	alpha := 2
+
+	factory := NewScaledEarlyTermNoTraversalFactory(2, alpha, alpha)
+	poll := factory.New(vdrs)
+	require.IsType(&earlyTermNoTraversalPoll{}, poll)
+
+	poll.Vote(vdr1, blkID1)
+	require.False(poll.Finished())
+
+	poll.Vote(vdr2, blkID1)
+	require.True(poll.Finished())
+
+	result := poll.Result()
+	require.Equal(2, result.Count(blkID1))
+}
+
+func TestScaledEarlyTermNoTraversalReducedSample(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := bag.Of(vdr1, vdr2, vdr3) // sample size = 3
+	k := 6
+	alphaPreference := 4
+	alphaConfidence := 5
+
+	factory := NewScaledEarlyTermNoTraversalFactory(k, alphaPreference, alphaConfidence)
+	poll := factory.New(vdrs)
+
+	// The scaled alpha confidence is 3, so the poll doesn't terminate early
+	// after reaching the scaled alpha preference of 2.
+	poll.Vote(vdr1, blkID1)
+	require.False(poll.Finished())
+	poll.Vote(vdr2, blkID1)
+	require.False(poll.Finished())
+	poll.Vote(vdr3, blkID1)
+	require.True(poll.Finished())
+
+	// The counts are scaled to k.
+	result := poll.Result()
+	require.Equal(6, result.Count(blkID1))
+	require.Equal(6, result.Len())
+}
+
+func TestScaledEarlyTermNoTraversalReducedSampleSplitVote(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := bag.Of(vdr1, vdr2, vdr3) // sample size = 3
+	k := 6
+	alpha := 4
+
+	factory := NewScaledEarlyTermNoTraversalFactory(k, alpha, alpha)
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, blkID1)
+	poll.Vote(vdr2, blkID2)
+	poll.Vote(vdr3, blkID3)
+	require.True(poll.Finished())
+
+	// Each count is scaled from 1 to 2, which doesn't reach alpha.
+	result := poll.Result()
+	require.Equal(2, result.Count(blkID1))
+	require.Equal(2, result.Count(blkID2))
+	require.Equal(2, result.Count(blkID3))
+}
@@ -25,6 +25,11 @@ type Config struct {
 	BootstrapTracker BootstrapTracker
 	Timer            Timer
 
+	// Coordinates the container requests sent while bootstrapping with the
+	// other chains in the subnet. If nil, requests are sent independently of
+	// the other chains.
+	FetchScheduler FetchScheduler
+
 	// Should Bootstrap be retried
 	RetryBootstrap bool
 
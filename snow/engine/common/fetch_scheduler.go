@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ FetchScheduler = (*fetchScheduler)(nil)
+
+	errNonPositiveMaxOutstandingRequests = errors.New("max outstanding requests must be positive")
+	errNilRequestBudget                  = errors.New("nil request budget")
+)
+
+// FetchScheduler coordinates the container requests that the bootstrapping
+// chains of a subnet send to their peers, so that chains bootstrapping at the
+// same time don't overwhelm the same peers.
+//
+// The requests to each peer are limited by the [RequestBudget] shared by
+// every chain of this node, which the scheduler uses to pick peers. The
+// scheduler only limits the total number of requests of the subnet's chains
+// and splits them fairly between the chains.
+type FetchScheduler interface {
+	// Acquire reserves an outstanding request from [chainID] to the peer in
+	// [peers] with the most room left in its [RequestBudget], and returns
+	// that peer.
+	//
+	// Returns false if no request can be sent right now, either because every
+	// peer in [peers] has used its budget, too many requests are outstanding,
+	// or [chainID] already has its fair share of the outstanding requests.
+	// The caller should try again after one of its requests finishes or after
+	// a delay.
+	Acquire(chainID ids.ID, peers set.Set[ids.NodeID]) (ids.NodeID, bool)
+
+	// Release frees an outstanding request from [chainID] to [nodeID] that was
+	// reserved by Acquire. Does nothing if there is no such request.
+	Release(chainID ids.ID, nodeID ids.NodeID)
+
+	// Done frees every outstanding request of [chainID], which is no longer
+	// fetching containers. Must be called once [chainID] finishes
+	// bootstrapping or is shut down, as responses to its outstanding requests
+	// may never be handled.
+	Done(chainID ids.ID)
+}
+
+type FetchSchedulerConfig struct {
+	// The maximum number of requests outstanding across all chains.
+	MaxOutstandingRequests int `json:"maxOutstandingRequests"`
+}
+
+func (c *FetchSchedulerConfig) Verify() error {
+	if c.MaxOutstandingRequests <= 0 {
+		return errNonPositiveMaxOutstandingRequests
+	}
+	return nil
+}
+
+type fetchScheduler struct {
+	config FetchSchedulerConfig
+	budget RequestBudget
+
+	lock           sync.Mutex
+	numOutstanding int
+	// chainID -> nodeID -> number of outstanding requests from that chain to
+	// that peer
+	chainOutstanding map[ids.ID]map[ids.NodeID]int
+	// chains that were refused a request because of the limits and haven't
+	// been able to send one since
+	waiting set.Set[ids.ID]
+}
+
+func NewFetchScheduler(config FetchSchedulerConfig, budget RequestBudget) (FetchScheduler, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	if budget == nil {
+		return nil, errNilRequestBudget
+	}
+	return &fetchScheduler{
+		config:           config,
+		budget:           budget,
+		chainOutstanding: make(map[ids.ID]map[ids.NodeID]int),
+	}, nil
+}
+
+func (f *fetchScheduler) Acquire(chainID ids.ID, peers set.Set[ids.NodeID]) (ids.NodeID, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.numOutstanding >= f.config.MaxOutstandingRequests ||
+		f.numChainOutstanding(chainID) >= f.fairShare(chainID) {
+		f.waiting.Add(chainID)
+		return ids.EmptyNodeID, false
+	}
+
+	var (
+		nodeID       ids.NodeID
+		maxAvailable int
+	)
+	for peer := range peers {
+		if available := f.budget.Available(peer); available > maxAvailable {
+			nodeID = peer
+			maxAvailable = available
+		}
+	}
+	if maxAvailable == 0 {
+		f.waiting.Add(chainID)
+		return ids.EmptyNodeID, false
+	}
+
+	chainPeers, ok := f.chainOutstanding[chainID]
+	if !ok {
+		chainPeers = make(map[ids.NodeID]int)
+		f.chainOutstanding[chainID] = chainPeers
+	}
+	chainPeers[nodeID]++
+	f.numOutstanding++
+	f.waiting.Remove(chainID)
+	return nodeID, true
+}
+
+func (f *fetchScheduler) Release(chainID ids.ID, nodeID ids.NodeID) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	chainPeers := f.chainOutstanding[chainID]
+	if chainPeers[nodeID] == 0 {
+		return
+	}
+	f.release(chainID, nodeID, 1)
+}
+
+func (f *fetchScheduler) Done(chainID ids.ID) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for nodeID, outstanding := range f.chainOutstanding[chainID] {
+		f.release(chainID, nodeID, outstanding)
+	}
+	f.waiting.Remove(chainID)
+}
+
+// release frees [count] outstanding requests from [chainID] to [nodeID].
+//
+// Assumes [f.lock] is held and that there are at least [count] such requests.
+func (f *fetchScheduler) release(chainID ids.ID, nodeID ids.NodeID, count int) {
+	chainPeers := f.chainOutstanding[chainID]
+	chainPeers[nodeID] -= count
+	if chainPeers[nodeID] == 0 {
+		delete(chainPeers, nodeID)
+	}
+	if len(chainPeers) == 0 {
+		delete(f.chainOutstanding, chainID)
+	}
+	f.numOutstanding -= count
+}
+
+// numChainOutstanding returns the number of requests outstanding from
+// [chainID].
+//
+// Assumes [f.lock] is held.
+func (f *fetchScheduler) numChainOutstanding(chainID ids.ID) int {
+	var total int
+	for _, outstanding := range f.chainOutstanding[chainID] {
+		total += outstanding
+	}
+	return total
+}
+
+// fairShare returns the number of outstanding requests that [chainID] may
+// have. The outstanding requests are split evenly between the chains that are
+// fetching containers, but every chain may have at least one.
+//
+// Assumes [f.lock] is held.
+func (f *fetchScheduler) fairShare(chainID ids.ID) int {
+	numChains := len(f.chainOutstanding)
+	for waitingChainID := range f.waiting {
+		if _, ok := f.chainOutstanding[waitingChainID]; !ok {
+			numChains++
+		}
+	}
+	_, hasOutstanding := f.chainOutstanding[chainID]
+	if !hasOutstanding && !f.waiting.Contains(chainID) {
+		numChains++
+	}
+
+	share := f.config.MaxOutstandingRequests / numChains
+	if share < 1 {
+		return 1
+	}
+	return share
+}
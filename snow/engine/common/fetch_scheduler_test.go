@@ -0,0 +1,162 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+func TestFetchSchedulerConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      FetchSchedulerConfig
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			config: FetchSchedulerConfig{
+				MaxOutstandingRequests: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "no outstanding requests",
+			config: FetchSchedulerConfig{
+				MaxOutstandingRequests: 0,
+			},
+			expectedErr: errNonPositiveMaxOutstandingRequests,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Verify()
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestNewFetchSchedulerNilBudget(t *testing.T) {
+	_, err := NewFetchScheduler(FetchSchedulerConfig{
+		MaxOutstandingRequests: 1,
+	}, nil)
+	require.ErrorIs(t, err, errNilRequestBudget)
+}
+
+func TestFetchSchedulerPeerLimit(t *testing.T) {
+	require := require.New(t)
+
+	budget, err := NewRequestBudget(RequestBudgetConfig{
+		MaxOutstandingRequestsPerPeer: 1,
+	})
+	require.NoError(err)
+	s, err := NewFetchScheduler(FetchSchedulerConfig{
+		MaxOutstandingRequests: 10,
+	}, budget)
+	require.NoError(err)
+
+	var (
+		chainID0 = ids.GenerateTestID()
+		chainID1 = ids.GenerateTestID()
+		nodeID0  = ids.GenerateTestNodeID()
+		nodeID1  = ids.GenerateTestNodeID()
+		peers    = set.Of(nodeID0, nodeID1)
+	)
+
+	// The chains are spread across the peers, as each request that is sent
+	// uses the budget of its peer.
+	nodeID, ok := s.Acquire(chainID0, peers)
+	require.True(ok)
+	request0 := ids.RequestID{
+		NodeID:             nodeID,
+		SourceChainID:      chainID0,
+		DestinationChainID: chainID0,
+		RequestID:          1,
+	}
+	require.True(budget.Acquire(request0))
+
+	otherNodeID, ok := s.Acquire(chainID1, peers)
+	require.True(ok)
+	request1 := ids.RequestID{
+		NodeID:             otherNodeID,
+		SourceChainID:      chainID1,
+		DestinationChainID: chainID1,
+		RequestID:          1,
+	}
+	require.True(budget.Acquire(request1))
+	require.Equal(peers, set.Of(nodeID, otherNodeID))
+
+	// Both peers have used their budget.
+	_, ok = s.Acquire(chainID0, peers)
+	require.False(ok)
+
+	budget.Release(request1)
+	s.Release(chainID1, otherNodeID)
+	nodeID, ok = s.Acquire(chainID0, peers)
+	require.True(ok)
+	require.Equal(otherNodeID, nodeID)
+}
+
+func TestFetchSchedulerFairShare(t *testing.T) {
+	require := require.New(t)
+
+	budget, err := NewRequestBudget(RequestBudgetConfig{
+		MaxOutstandingRequestsPerPeer: 4,
+	})
+	require.NoError(err)
+	s, err := NewFetchScheduler(FetchSchedulerConfig{
+		MaxOutstandingRequests: 4,
+	}, budget)
+	require.NoError(err)
+
+	var (
+		chainID0 = ids.GenerateTestID()
+		chainID1 = ids.GenerateTestID()
+		peers    = set.Of(ids.GenerateTestNodeID())
+	)
+
+	// While it's the only chain fetching, a chain may use every request.
+	for i := 0; i < 4; i++ {
+		_, ok := s.Acquire(chainID0, peers)
+		require.True(ok)
+	}
+	_, ok := s.Acquire(chainID0, peers)
+	require.False(ok)
+
+	// Once another chain is waiting, the requests are split between them.
+	_, ok = s.Acquire(chainID1, peers)
+	require.False(ok)
+
+	nodeID := peers.List()[0]
+	s.Release(chainID0, nodeID)
+	_, ok = s.Acquire(chainID0, peers)
+	require.False(ok)
+	_, ok = s.Acquire(chainID1, peers)
+	require.True(ok)
+
+	// [chainID0] has to release down to its share before it can send another
+	// request.
+	s.Release(chainID0, nodeID)
+	_, ok = s.Acquire(chainID0, peers)
+	require.False(ok)
+	s.Release(chainID0, nodeID)
+	_, ok = s.Acquire(chainID0, peers)
+	require.True(ok)
+
+	// Releasing a request that isn't outstanding does nothing.
+	s.Release(chainID1, ids.GenerateTestNodeID())
+
+	// Once [chainID0] is done, [chainID1] may use every request.
+	s.Done(chainID0)
+	for i := 0; i < 3; i++ {
+		_, ok := s.Acquire(chainID1, peers)
+		require.True(ok)
+	}
+	_, ok = s.Acquire(chainID1, peers)
+	require.False(ok)
+}
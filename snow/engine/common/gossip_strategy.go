@@ -0,0 +1,237 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	// StakeWeightedGossipStrategy samples validators with a probability
+	// proportional to their stake, and non-validators uniformly.
+	StakeWeightedGossipStrategy = "stake-weighted"
+	// LatencyBiasedGossipStrategy picks a fraction of the validators by
+	// lowest response latency, which favors nearby validators, and samples
+	// the rest like [StakeWeightedGossipStrategy].
+	LatencyBiasedGossipStrategy = "latency-biased"
+	// PushPullGossipStrategy pushes to only a fraction of the validators,
+	// sampled like [StakeWeightedGossipStrategy], and relies on the other
+	// validators pulling what they miss, since validators fetch the
+	// containers they're queried about. Non-validators aren't queried, so they
+	// are pushed to as usual.
+	PushPullGossipStrategy = "push-pull"
+)
+
+var (
+	_ GossipStrategy = (*stakeWeightedGossip)(nil)
+	_ GossipStrategy = (*latencyBiasedGossip)(nil)
+	_ GossipStrategy = (*pushPullGossip)(nil)
+
+	DefaultGossipStrategyConfig = GossipStrategyConfig{
+		LatencyBias:  0.5,
+		PushFraction: 0.25,
+	}
+
+	errUnknownGossipStrategy = errors.New("unknown gossip strategy")
+	errInvalidLatencyBias    = errors.New("latency bias must be in the range [0, 1]")
+	errInvalidPushFraction   = errors.New("push fraction must be in the range (0, 1]")
+)
+
+// GossipPeer is a peer that gossip can be sent to.
+type GossipPeer struct {
+	NodeID ids.NodeID
+	// Weight is the stake of the peer in the chain's subnet, or 0 if the peer
+	// isn't a validator of the subnet.
+	Weight uint64
+	// Latency is the average latency of the peer's responses, or 0 if it's
+	// unknown.
+	Latency time.Duration
+}
+
+// GossipStrategy selects the peers that a chain pushes its gossip to.
+type GossipStrategy interface {
+	// Sample returns up to [numValidators] validators, [numNonValidators]
+	// non-validators, and [numPeers] other peers of either kind from [peers].
+	// The order of [peers] doesn't matter.
+	Sample(peers []GossipPeer, numValidators, numNonValidators, numPeers int) set.Set[ids.NodeID]
+}
+
+// GossipStrategyConfig selects the gossip strategy of a chain.
+type GossipStrategyConfig struct {
+	// Strategy is the name of the strategy.
+	Strategy string `json:"strategy"`
+	// LatencyBias is the fraction of the validators that
+	// [LatencyBiasedGossipStrategy] picks by lowest latency.
+	LatencyBias float64 `json:"latencyBias"`
+	// PushFraction is the fraction of the validators that
+	// [PushPullGossipStrategy] pushes to.
+	PushFraction float64 `json:"pushFraction"`
+}
+
+func (c *GossipStrategyConfig) Verify() error {
+	switch c.Strategy {
+	case StakeWeightedGossipStrategy, LatencyBiasedGossipStrategy, PushPullGossipStrategy:
+	default:
+		return fmt.Errorf("%w: %q", errUnknownGossipStrategy, c.Strategy)
+	}
+	switch {
+	case c.LatencyBias < 0 || c.LatencyBias > 1:
+		return errInvalidLatencyBias
+	case c.PushFraction <= 0 || c.PushFraction > 1:
+		return errInvalidPushFraction
+	default:
+		return nil
+	}
+}
+
+func NewGossipStrategy(config GossipStrategyConfig) (GossipStrategy, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	switch config.Strategy {
+	case LatencyBiasedGossipStrategy:
+		return &latencyBiasedGossip{bias: config.LatencyBias}, nil
+	case PushPullGossipStrategy:
+		return &pushPullGossip{pushFraction: config.PushFraction}, nil
+	default:
+		return &stakeWeightedGossip{}, nil
+	}
+}
+
+type stakeWeightedGossip struct{}
+
+func (*stakeWeightedGossip) Sample(peers []GossipPeer, numValidators, numNonValidators, numPeers int) set.Set[ids.NodeID] {
+	validators, nonValidators := splitGossipPeers(peers)
+	sampled := set.NewSet[ids.NodeID](numValidators + numNonValidators + numPeers)
+	sampleByStake(sampled, validators, numValidators)
+	sampleUniformly(sampled, nonValidators, numNonValidators)
+	sampleUniformly(sampled, peers, numPeers)
+	return sampled
+}
+
+type latencyBiasedGossip struct {
+	bias float64
+}
+
+func (g *latencyBiasedGossip) Sample(peers []GossipPeer, numValidators, numNonValidators, numPeers int) set.Set[ids.NodeID] {
+	validators, nonValidators := splitGossipPeers(peers)
+	sampled := set.NewSet[ids.NodeID](numValidators + numNonValidators + numPeers)
+
+	// Validators whose latency is unknown are considered the slowest.
+	slices.SortFunc(validators, func(a, b GossipPeer) bool {
+		switch {
+		case a.Latency == 0:
+			return false
+		case b.Latency == 0:
+			return true
+		default:
+			return a.Latency < b.Latency
+		}
+	})
+	numFastest := int(math.Ceil(g.bias * float64(numValidators)))
+	numFastest = safemath.Min(numFastest, len(validators))
+	for _, validator := range validators[:numFastest] {
+		sampled.Add(validator.NodeID)
+	}
+
+	sampleByStake(sampled, validators[numFastest:], numValidators-numFastest)
+	sampleUniformly(sampled, nonValidators, numNonValidators)
+	sampleUniformly(sampled, peers, numPeers)
+	return sampled
+}
+
+type pushPullGossip struct {
+	pushFraction float64
+}
+
+func (g *pushPullGossip) Sample(peers []GossipPeer, numValidators, numNonValidators, numPeers int) set.Set[ids.NodeID] {
+	numValidators = int(math.Ceil(g.pushFraction * float64(numValidators)))
+	return (&stakeWeightedGossip{}).Sample(peers, numValidators, numNonValidators, numPeers)
+}
+
+// splitGossipPeers returns the validators and the non-validators of [peers].
+func splitGossipPeers(peers []GossipPeer) ([]GossipPeer, []GossipPeer) {
+	var validators, nonValidators []GossipPeer
+	for _, peer := range peers {
+		if peer.Weight > 0 {
+			validators = append(validators, peer)
+		} else {
+			nonValidators = append(nonValidators, peer)
+		}
+	}
+	return validators, nonValidators
+}
+
+// sampleByStake adds up to [count] of [validators] that aren't in [sampled]
+// to [sampled], with a probability proportional to their stake.
+func sampleByStake(sampled set.Set[ids.NodeID], validators []GossipPeer, count int) {
+	candidates := unsampledGossipPeers(sampled, validators)
+	s := sampler.NewWeightedWithoutReplacement()
+	// Samples are units of stake, so several samples can be the same
+	// validator. Validators are sampled one at a time to get distinct ones.
+	for ; count > 0 && len(candidates) > 0; count-- {
+		weights := make([]uint64, len(candidates))
+		for i, candidate := range candidates {
+			weights[i] = candidate.Weight
+		}
+		// The weights can only fail to be initialized if they overflow, in
+		// which case the validators are sampled uniformly.
+		if err := s.Initialize(weights); err != nil {
+			sampleUniformly(sampled, candidates, count)
+			return
+		}
+		indices, err := s.Sample(1)
+		if err != nil {
+			sampleUniformly(sampled, candidates, count)
+			return
+		}
+
+		index := indices[0]
+		sampled.Add(candidates[index].NodeID)
+		lastIndex := len(candidates) - 1
+		candidates[index] = candidates[lastIndex]
+		candidates = candidates[:lastIndex]
+	}
+}
+
+// sampleUniformly adds up to [count] of [peers] that aren't in [sampled] to
+// [sampled], uniformly at random.
+func sampleUniformly(sampled set.Set[ids.NodeID], peers []GossipPeer, count int) {
+	candidates := unsampledGossipPeers(sampled, peers)
+	count = safemath.Min(count, len(candidates))
+	if count <= 0 {
+		return
+	}
+
+	s := sampler.NewUniform()
+	s.Initialize(uint64(len(candidates)))
+	// Sampling can't fail because [count] is at most the number of
+	// candidates.
+	indices, _ := s.Sample(count)
+	for _, index := range indices {
+		sampled.Add(candidates[index].NodeID)
+	}
+}
+
+// unsampledGossipPeers returns the peers of [peers] that aren't in [sampled].
+func unsampledGossipPeers(sampled set.Set[ids.NodeID], peers []GossipPeer) []GossipPeer {
+	candidates := make([]GossipPeer, 0, len(peers))
+	for _, peer := range peers {
+		if !sampled.Contains(peer.NodeID) {
+			candidates = append(candidates, peer)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+func TestGossipStrategyConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      GossipStrategyConfig
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			config: GossipStrategyConfig{
+				Strategy:     LatencyBiasedGossipStrategy,
+				LatencyBias:  1,
+				PushFraction: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "unknown strategy",
+			config: GossipStrategyConfig{
+				Strategy:     "uniform",
+				PushFraction: 1,
+			},
+			expectedErr: errUnknownGossipStrategy,
+		},
+		{
+			name: "latency bias too large",
+			config: GossipStrategyConfig{
+				Strategy:     LatencyBiasedGossipStrategy,
+				LatencyBias:  1.5,
+				PushFraction: 1,
+			},
+			expectedErr: errInvalidLatencyBias,
+		},
+		{
+			name: "no push fraction",
+			config: GossipStrategyConfig{
+				Strategy: PushPullGossipStrategy,
+			},
+			expectedErr: errInvalidPushFraction,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Verify()
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+// newTestGossipPeers returns [numValidators] validators, with increasing
+// latencies, followed by [numNonValidators] non-validators.
+func newTestGossipPeers(numValidators, numNonValidators int) []GossipPeer {
+	peers := make([]GossipPeer, 0, numValidators+numNonValidators)
+	for i := 0; i < numValidators; i++ {
+		peers = append(peers, GossipPeer{
+			NodeID:  ids.GenerateTestNodeID(),
+			Weight:  uint64(i + 1),
+			Latency: time.Duration(i+1) * time.Millisecond,
+		})
+	}
+	for i := 0; i < numNonValidators; i++ {
+		peers = append(peers, GossipPeer{
+			NodeID: ids.GenerateTestNodeID(),
+		})
+	}
+	return peers
+}
+
+// countGossipPeers returns the number of validators and non-validators of
+// [peers] that are in [sampled].
+func countGossipPeers(peers []GossipPeer, sampled set.Set[ids.NodeID]) (int, int) {
+	var numValidators, numNonValidators int
+	for _, peer := range peers {
+		switch {
+		case !sampled.Contains(peer.NodeID):
+		case peer.Weight > 0:
+			numValidators++
+		default:
+			numNonValidators++
+		}
+	}
+	return numValidators, numNonValidators
+}
+
+func TestGossipStrategySample(t *testing.T) {
+	tests := []struct {
+		name                     string
+		config                   GossipStrategyConfig
+		numValidators            int
+		numNonValidators         int
+		numPeers                 int
+		expectedNumValidators    int
+		expectedNumNonValidators int
+	}{
+		{
+			name: "stake-weighted",
+			config: GossipStrategyConfig{
+				Strategy:     StakeWeightedGossipStrategy,
+				PushFraction: 1,
+			},
+			numValidators:            3,
+			numNonValidators:         2,
+			expectedNumValidators:    3,
+			expectedNumNonValidators: 2,
+		},
+		{
+			name: "stake-weighted with fewer peers than requested",
+			config: GossipStrategyConfig{
+				Strategy:     StakeWeightedGossipStrategy,
+				PushFraction: 1,
+			},
+			numValidators:            20,
+			numNonValidators:         20,
+			numPeers:                 20,
+			expectedNumValidators:    10,
+			expectedNumNonValidators: 10,
+		},
+		{
+			name:                     "latency-biased",
+			config:                   DefaultGossipStrategyConfig,
+			numValidators:            4,
+			numNonValidators:         1,
+			expectedNumValidators:    4,
+			expectedNumNonValidators: 1,
+		},
+		{
+			name:                     "push-pull",
+			config:                   DefaultGossipStrategyConfig,
+			numValidators:            8,
+			numNonValidators:         3,
+			expectedNumValidators:    2,
+			expectedNumNonValidators: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			config := test.config
+			if config.Strategy == "" {
+				config.Strategy = test.name
+			}
+			strategy, err := NewGossipStrategy(config)
+			require.NoError(err)
+
+			peers := newTestGossipPeers(10, 10)
+			sampled := strategy.Sample(peers, test.numValidators, test.numNonValidators, test.numPeers)
+			numValidators, numNonValidators := countGossipPeers(peers, sampled)
+			require.Equal(test.expectedNumValidators, numValidators)
+			require.Equal(test.expectedNumNonValidators, numNonValidators)
+		})
+	}
+}
+
+func TestLatencyBiasedGossipPicksFastestValidators(t *testing.T) {
+	require := require.New(t)
+
+	strategy, err := NewGossipStrategy(GossipStrategyConfig{
+		Strategy:     LatencyBiasedGossipStrategy,
+		LatencyBias:  1,
+		PushFraction: 1,
+	})
+	require.NoError(err)
+
+	peers := newTestGossipPeers(5, 0)
+	// Validators with an unknown latency are picked last.
+	peers[0].Latency = 0
+
+	sampled := strategy.Sample(peers, 2, 0, 0)
+	require.Equal(set.Of(peers[1].NodeID, peers[2].NodeID), sampled)
+}
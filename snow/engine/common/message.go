@@ -23,6 +23,14 @@ const (
 	// StateSyncDone notifies the state syncer engine that the VM has finishing
 	// syncing the requested state summary.
 	StateSyncDone
+
+	// VMReady notifies a consensus engine that its VM, which previously
+	// reported that it wasn't ready to build blocks, is ready to build blocks
+	// again.
+	//
+	// The consensus engine must eventually attempt to build any blocks that
+	// were requested while the VM wasn't ready.
+	VMReady
 )
 
 func (msg Message) String() string {
@@ -31,6 +39,8 @@ func (msg Message) String() string {
 		return "Pending Transactions"
 	case StateSyncDone:
 		return "State Sync Done"
+	case VMReady:
+		return "VM Ready"
 	default:
 		return fmt.Sprintf("Unknown Message: %d", msg)
 	}
@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ RequestBudget = (*requestBudget)(nil)
+
+	errNonPositiveMaxOutstandingRequestsPerPeer = errors.New("max outstanding requests per peer must be positive")
+)
+
+// RequestBudget limits the number of requests that are outstanding to each
+// peer from every chain of this node. Bootstrapping fetches, state sync and
+// consensus queries, and app requests, such as sync proofs and pull gossip,
+// share the budget of a peer, so that catching up aggressively doesn't get
+// this node throttled by its peers.
+type RequestBudget interface {
+	// Acquire reserves an outstanding request to [requestID.NodeID] for
+	// [requestID].
+	//
+	// Returns false if the peer already has the maximum number of outstanding
+	// requests, in which case the request shouldn't be sent.
+	Acquire(requestID ids.RequestID) bool
+
+	// Release frees the outstanding request reserved for [requestID] by
+	// Acquire. Does nothing if there is no such request.
+	Release(requestID ids.RequestID)
+
+	// Available returns the number of requests that can currently be sent to
+	// [nodeID].
+	Available(nodeID ids.NodeID) int
+}
+
+type RequestBudgetConfig struct {
+	// The maximum number of requests outstanding to a single peer across all
+	// chains.
+	MaxOutstandingRequestsPerPeer int `json:"maxOutstandingRequestsPerPeer"`
+}
+
+func (c *RequestBudgetConfig) Verify() error {
+	if c.MaxOutstandingRequestsPerPeer <= 0 {
+		return errNonPositiveMaxOutstandingRequestsPerPeer
+	}
+	return nil
+}
+
+type requestBudget struct {
+	config RequestBudgetConfig
+
+	lock sync.Mutex
+	// nodeID -> number of outstanding requests to that peer
+	peerOutstanding map[ids.NodeID]int
+	// requests that were reserved by Acquire and haven't been released
+	outstanding set.Set[ids.RequestID]
+}
+
+func NewRequestBudget(config RequestBudgetConfig) (RequestBudget, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return &requestBudget{
+		config:          config,
+		peerOutstanding: make(map[ids.NodeID]int),
+	}, nil
+}
+
+func (b *requestBudget) Acquire(requestID ids.RequestID) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.outstanding.Contains(requestID) {
+		return true
+	}
+	if b.peerOutstanding[requestID.NodeID] >= b.config.MaxOutstandingRequestsPerPeer {
+		return false
+	}
+
+	b.outstanding.Add(requestID)
+	b.peerOutstanding[requestID.NodeID]++
+	return true
+}
+
+func (b *requestBudget) Release(requestID ids.RequestID) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.outstanding.Contains(requestID) {
+		return
+	}
+
+	b.outstanding.Remove(requestID)
+	b.peerOutstanding[requestID.NodeID]--
+	if b.peerOutstanding[requestID.NodeID] == 0 {
+		delete(b.peerOutstanding, requestID.NodeID)
+	}
+}
+
+func (b *requestBudget) Available(nodeID ids.NodeID) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.config.MaxOutstandingRequestsPerPeer - b.peerOutstanding[nodeID]
+}
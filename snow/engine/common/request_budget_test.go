@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+)
+
+func TestRequestBudgetConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      RequestBudgetConfig
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			config: RequestBudgetConfig{
+				MaxOutstandingRequestsPerPeer: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "no outstanding requests per peer",
+			config: RequestBudgetConfig{
+				MaxOutstandingRequestsPerPeer: 0,
+			},
+			expectedErr: errNonPositiveMaxOutstandingRequestsPerPeer,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Verify()
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestRequestBudget(t *testing.T) {
+	require := require.New(t)
+
+	b, err := NewRequestBudget(RequestBudgetConfig{
+		MaxOutstandingRequestsPerPeer: 2,
+	})
+	require.NoError(err)
+
+	var (
+		nodeID0  = ids.GenerateTestNodeID()
+		nodeID1  = ids.GenerateTestNodeID()
+		chainID0 = ids.GenerateTestID()
+		chainID1 = ids.GenerateTestID()
+
+		// A bootstrapping fetch and an app request, from different chains,
+		// to the same peer.
+		ancestors = ids.RequestID{
+			NodeID:             nodeID0,
+			SourceChainID:      chainID0,
+			DestinationChainID: chainID0,
+			RequestID:          1,
+			Op:                 byte(message.AncestorsOp),
+		}
+		appResponse = ids.RequestID{
+			NodeID:             nodeID0,
+			SourceChainID:      chainID1,
+			DestinationChainID: chainID1,
+			RequestID:          1,
+			Op:                 byte(message.AppResponseOp),
+		}
+		chits = ids.RequestID{
+			NodeID:             nodeID0,
+			SourceChainID:      chainID0,
+			DestinationChainID: chainID0,
+			RequestID:          2,
+			Op:                 byte(message.ChitsOp),
+		}
+		otherPeerChits = ids.RequestID{
+			NodeID:             nodeID1,
+			SourceChainID:      chainID0,
+			DestinationChainID: chainID0,
+			RequestID:          2,
+			Op:                 byte(message.ChitsOp),
+		}
+	)
+
+	require.True(b.Acquire(ancestors))
+	require.Equal(1, b.Available(nodeID0))
+	require.True(b.Acquire(appResponse))
+	// Acquiring an outstanding request again doesn't use more of the budget.
+	require.True(b.Acquire(appResponse))
+	require.Zero(b.Available(nodeID0))
+
+	// The peer is at its limit, but other peers aren't.
+	require.False(b.Acquire(chits))
+	require.Equal(2, b.Available(nodeID1))
+	require.True(b.Acquire(otherPeerChits))
+
+	// Releasing a request that wasn't acquired doesn't free any of the budget.
+	b.Release(chits)
+	require.False(b.Acquire(chits))
+
+	b.Release(ancestors)
+	require.Equal(1, b.Available(nodeID0))
+	require.True(b.Acquire(chits))
+	require.False(b.Acquire(ancestors))
+}
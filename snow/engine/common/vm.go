@@ -95,3 +95,16 @@ type VM interface {
 	// information about their accounts.
 	CreateHandlers(context.Context) (map[string]http.Handler, error)
 }
+
+// ReadinessVM defines the optional method a VM may implement to report that
+// it is temporarily unable to build blocks even though it has finished
+// bootstrapping.
+type ReadinessVM interface {
+	// ReadyToBuild returns false if the VM is currently unable to build
+	// blocks. While the VM isn't ready, the consensus engine holds any
+	// pending requests to build blocks rather than calling BuildBlock.
+	//
+	// Once the VM becomes ready again, it must send a VMReady message to the
+	// consensus engine.
+	ReadyToBuild(context.Context) bool
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+var (
+	DefaultAdaptiveSamplingConfig = AdaptiveSamplingConfig{
+		MinK:           10,
+		ConfidentPolls: 4,
+	}
+
+	ErrAdaptiveSamplingInvalid = errors.New("adaptive sampling config invalid")
+)
+
+// AdaptiveSamplingConfig configures the engine to query fewer validators for a
+// block once it has accumulated a strong preference.
+//
+// A block has a strong preference once it has been the preference of
+// [ConfidentPolls] consecutive polls, each of which reached alphaConfidence
+// for it. Repeated queries for the block then sample [MinK] validators, and
+// the alphas of the polls are scaled to [MinK]. Sampling is restored to K as
+// soon as a poll doesn't reach alphaConfidence for the block, or the
+// preference changes.
+type AdaptiveSamplingConfig struct {
+	MinK           int `json:"minK"`
+	ConfidentPolls int `json:"confidentPolls"`
+}
+
+// Verify returns nil if the config can be used with [params].
+//
+// A config is valid if the following conditions are met:
+//
+// - 0 < MinK <= K
+// - K - AlphaConfidence < AlphaPreference scaled to MinK
+// - 0 < ConfidentPolls
+//
+// A poll of K validators tolerates K - AlphaConfidence faulty validators. The
+// second condition ensures that this many faulty validators can't change the
+// preference, or finalize a block, in a poll of MinK validators on their own.
+//
+// Note: K/2 < AlphaPreference implies that AlphaPreference scaled to MinK is
+// more than MinK/2, since it's rounded up, so polls of MinK validators still
+// require a majority to change the preference.
+func (c AdaptiveSamplingConfig) Verify(params snowball.Parameters) error {
+	switch {
+	case c.MinK <= 0 || params.K < c.MinK:
+		return fmt.Errorf("%w: k = %d, minK = %d: fails the condition that: 0 < minK <= k", ErrAdaptiveSamplingInvalid, params.K, c.MinK)
+	case poll.ScaleAlpha(params.AlphaPreference, c.MinK, params.K) <= params.K-params.AlphaConfidence:
+		return fmt.Errorf("%w: k = %d, alphaPreference = %d, alphaConfidence = %d, minK = %d: fails the condition that: k - alphaConfidence < alphaPreference scaled to minK",
+			ErrAdaptiveSamplingInvalid, params.K, params.AlphaPreference, params.AlphaConfidence, c.MinK)
+	case c.ConfidentPolls <= 0:
+		return fmt.Errorf("%w: confidentPolls = %d: fails the condition that: 0 < confidentPolls", ErrAdaptiveSamplingInvalid, c.ConfidentPolls)
+	default:
+		return nil
+	}
+}
+
+// sampleSize returns the number of validators to query for [blkID].
+func (t *Transitive) sampleSize(blkID ids.ID) int {
+	if t.AdaptiveSampling == nil ||
+		t.numConfidentPolls < t.AdaptiveSampling.ConfidentPolls ||
+		t.confidentBlkID != blkID ||
+		t.Consensus.Preference() != blkID {
+		return t.Params.K
+	}
+	return t.AdaptiveSampling.MinK
+}
+
+// recordPollConfidence updates the number of consecutive polls that reached
+// alphaConfidence for the preference, given that the preference was
+// [preference] before [result] was recorded.
+func (t *Transitive) recordPollConfidence(preference ids.ID, result bag.Bag[ids.ID]) {
+	if t.AdaptiveSampling == nil {
+		return
+	}
+	if result.Count(preference) < t.Params.AlphaConfidence || t.Consensus.Preference() != preference {
+		t.numConfidentPolls = 0
+		return
+	}
+	if t.confidentBlkID != preference {
+		t.confidentBlkID = preference
+		t.numConfidentPolls = 0
+	}
+	t.numConfidentPolls++
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+func TestAdaptiveSamplingConfigVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      AdaptiveSamplingConfig
+		expectedErr error
+	}{
+		{
+			name:        "default",
+			config:      DefaultAdaptiveSamplingConfig,
+			expectedErr: nil,
+		},
+		{
+			name: "minK equals k",
+			config: AdaptiveSamplingConfig{
+				MinK:           snowball.DefaultParameters.K,
+				ConfidentPolls: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "minK is zero",
+			config: AdaptiveSamplingConfig{
+				MinK:           0,
+				ConfidentPolls: 1,
+			},
+			expectedErr: ErrAdaptiveSamplingInvalid,
+		},
+		{
+			name: "minK above k",
+			config: AdaptiveSamplingConfig{
+				MinK:           snowball.DefaultParameters.K + 1,
+				ConfidentPolls: 1,
+			},
+			expectedErr: ErrAdaptiveSamplingInvalid,
+		},
+		{
+			name: "minK is one",
+			config: AdaptiveSamplingConfig{
+				MinK:           1,
+				ConfidentPolls: 1,
+			},
+			expectedErr: ErrAdaptiveSamplingInvalid,
+		},
+		{
+			// ceil(15 * 7 / 20) = 6 > 20 - 15
+			name: "minK at the fault tolerance bound",
+			config: AdaptiveSamplingConfig{
+				MinK:           7,
+				ConfidentPolls: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			// ceil(15 * 6 / 20) = 5 <= 20 - 15
+			name: "minK below the fault tolerance bound",
+			config: AdaptiveSamplingConfig{
+				MinK:           6,
+				ConfidentPolls: 1,
+			},
+			expectedErr: ErrAdaptiveSamplingInvalid,
+		},
+		{
+			name: "confidentPolls is zero",
+			config: AdaptiveSamplingConfig{
+				MinK:           snowball.DefaultParameters.K,
+				ConfidentPolls: 0,
+			},
+			expectedErr: ErrAdaptiveSamplingInvalid,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Verify(snowball.DefaultParameters)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestEngineAdaptiveSampleSize(t *testing.T) {
+	require := require.New(t)
+
+	engCfg := DefaultConfigs()
+	engCfg.Params.K = 4
+	engCfg.Params.AlphaPreference = 3
+	engCfg.Params.AlphaConfidence = 3
+	engCfg.AdaptiveSampling = &AdaptiveSamplingConfig{
+		MinK:           2,
+		ConfidentPolls: 2,
+	}
+	require.NoError(engCfg.AdaptiveSampling.Verify(engCfg.Params))
+
+	_, _, _, _, te, gBlk := setup(t, common.DefaultConfigTest(), engCfg)
+	otherBlkID := ids.GenerateTestID()
+
+	confidentResult := bag.Of(gBlk.ID(), gBlk.ID(), gBlk.ID())
+	unconfidentResult := bag.Of(gBlk.ID(), gBlk.ID(), otherBlkID)
+
+	require.Equal(4, te.sampleSize(gBlk.ID()))
+
+	// The preference must reach alphaConfidence in [ConfidentPolls]
+	// consecutive polls.
+	te.recordPollConfidence(gBlk.ID(), confidentResult)
+	require.Equal(4, te.sampleSize(gBlk.ID()))
+	te.recordPollConfidence(gBlk.ID(), confidentResult)
+	require.Equal(2, te.sampleSize(gBlk.ID()))
+
+	// Only queries for the preference are reduced.
+	require.Equal(4, te.sampleSize(otherBlkID))
+
+	// A poll that doesn't reach alphaConfidence restores k.
+	te.recordPollConfidence(gBlk.ID(), unconfidentResult)
+	require.Equal(4, te.sampleSize(gBlk.ID()))
+}
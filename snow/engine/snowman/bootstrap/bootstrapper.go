@@ -25,8 +25,14 @@ import (
 	"github.com/ava-labs/avalanchego/version"
 )
 
-// Parameters for delaying bootstrapping to avoid potential CPU burns
-const bootstrappingDelay = 10 * time.Second
+const (
+	// Parameters for delaying bootstrapping to avoid potential CPU burns
+	bootstrappingDelay = 10 * time.Second
+
+	// Delay before retrying the fetches that weren't sent because too many
+	// requests were outstanding across the subnet
+	fetchRetryDelay = 100 * time.Millisecond
+)
 
 var (
 	_ common.BootstrapableEngine = (*bootstrapper)(nil)
@@ -78,6 +84,13 @@ type bootstrapper struct {
 	// again.
 	fetchFrom set.Set[ids.NodeID]
 
+	// deferredFetches is the set of blocks that weren't requested because the
+	// [FetchScheduler] didn't allow another request at the time. They're
+	// requested again after one of this chain's requests finishes or after
+	// [fetchRetryDelay].
+	deferredFetches    set.Set[ids.ID]
+	awaitingFetchRetry bool
+
 	// bootstrappedOnce ensures that the [Bootstrapped] callback is only invoked
 	// once, even if bootstrapping is retried.
 	bootstrappedOnce sync.Once
@@ -165,6 +178,9 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 		)
 		return nil
 	}
+	if err := b.releaseFetch(ctx, nodeID); err != nil {
+		return err
+	}
 
 	lenBlks := len(blks)
 	if lenBlks == 0 {
@@ -234,6 +250,9 @@ func (b *bootstrapper) GetAncestorsFailed(ctx context.Context, nodeID ids.NodeID
 		)
 		return nil
 	}
+	if err := b.releaseFetch(ctx, nodeID); err != nil {
+		return err
+	}
 
 	// This node timed out their request, so we can add them back to [fetchFrom]
 	b.fetchFrom.Add(nodeID)
@@ -277,6 +296,10 @@ func (b *bootstrapper) Disconnected(ctx context.Context, nodeID ids.NodeID) erro
 }
 
 func (b *bootstrapper) Timeout(ctx context.Context) error {
+	if b.awaitingFetchRetry {
+		b.awaitingFetchRetry = false
+		return b.fetchDeferred(ctx)
+	}
 	if !b.awaitingTimeout {
 		return errUnexpectedTimeout
 	}
@@ -299,6 +322,12 @@ func (b *bootstrapper) Shutdown(ctx context.Context) error {
 	b.Ctx.Lock.Lock()
 	defer b.Ctx.Lock.Unlock()
 
+	// Responses to the outstanding requests won't be handled once the chain
+	// is shut down, so they must not keep counting against the subnet.
+	if b.Config.FetchScheduler != nil {
+		b.Config.FetchScheduler.Done(b.Ctx.ChainID)
+	}
+
 	return b.VM.Shutdown(ctx)
 }
 
@@ -375,7 +404,7 @@ func (b *bootstrapper) ForceAccepted(ctx context.Context, acceptedContainerIDs [
 // Get block [blkID] and its ancestors from a validator
 func (b *bootstrapper) fetch(ctx context.Context, blkID ids.ID) error {
 	// Make sure we haven't already requested this block
-	if b.OutstandingRequests.Contains(blkID) {
+	if b.OutstandingRequests.Contains(blkID) || b.deferredFetches.Contains(blkID) {
 		return nil
 	}
 
@@ -384,10 +413,20 @@ func (b *bootstrapper) fetch(ctx context.Context, blkID ids.ID) error {
 		return b.checkFinish(ctx)
 	}
 
-	validatorID, ok := b.fetchFrom.Peek()
-	if !ok {
+	if b.fetchFrom.Len() == 0 {
 		return fmt.Errorf("dropping request for %s as there are no validators", blkID)
 	}
+	validatorID, ok := b.acquireFetch()
+	if !ok {
+		// Too many requests are outstanding across the subnet, so this block
+		// will be requested later.
+		b.deferredFetches.Add(blkID)
+		if !b.awaitingFetchRetry {
+			b.Config.Timer.RegisterTimeout(fetchRetryDelay)
+			b.awaitingFetchRetry = true
+		}
+		return nil
+	}
 
 	// We only allow one outbound request at a time from a node
 	b.markUnavailable(validatorID)
@@ -399,6 +438,39 @@ func (b *bootstrapper) fetch(ctx context.Context, blkID ids.ID) error {
 	return nil
 }
 
+// acquireFetch returns the peer to send the next request to. Returns false if
+// the [FetchScheduler] doesn't allow another request right now.
+//
+// Assumes [b.fetchFrom] isn't empty.
+func (b *bootstrapper) acquireFetch() (ids.NodeID, bool) {
+	if b.Config.FetchScheduler == nil {
+		return b.fetchFrom.Peek()
+	}
+	return b.Config.FetchScheduler.Acquire(b.Ctx.ChainID, b.fetchFrom)
+}
+
+// releaseFetch records that the request to [nodeID] has finished, and then
+// requests the deferred blocks, as the request may have made room for them.
+func (b *bootstrapper) releaseFetch(ctx context.Context, nodeID ids.NodeID) error {
+	if b.Config.FetchScheduler == nil {
+		return nil
+	}
+	b.Config.FetchScheduler.Release(b.Ctx.ChainID, nodeID)
+	return b.fetchDeferred(ctx)
+}
+
+// fetchDeferred requests the blocks that were deferred by [fetch].
+func (b *bootstrapper) fetchDeferred(ctx context.Context) error {
+	deferredFetches := b.deferredFetches
+	b.deferredFetches = nil
+	for blkID := range deferredFetches {
+		if err := b.fetch(ctx, blkID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // markUnavailable removes [nodeID] from the set of peers used to fetch
 // ancestors. If the set becomes empty, it is reset to the currently preferred
 // peers so bootstrapping can continue.
@@ -601,6 +673,9 @@ func (b *bootstrapper) checkFinish(ctx context.Context) error {
 
 	// Notify the subnet that this chain is synced
 	b.Config.BootstrapTracker.Bootstrapped(b.Ctx.ChainID)
+	if b.Config.FetchScheduler != nil {
+		b.Config.FetchScheduler.Done(b.Ctx.ChainID)
+	}
 
 	// If the subnet hasn't finished bootstrapping, this chain should remain
 	// syncing.
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -863,6 +864,179 @@ func TestBootstrapperAncestors(t *testing.T) {
 	require.Equal(choices.Accepted, blk2.Status())
 }
 
+func TestBootstrapperFetchScheduler(t *testing.T) {
+	require := require.New(t)
+
+	config, peerID, sender, vm := newConfig(t)
+
+	requestBudget, err := common.NewRequestBudget(common.RequestBudgetConfig{
+		MaxOutstandingRequestsPerPeer: 1,
+	})
+	require.NoError(err)
+	fetchScheduler, err := common.NewFetchScheduler(common.FetchSchedulerConfig{
+		MaxOutstandingRequests: 1,
+	}, requestBudget)
+	require.NoError(err)
+	config.FetchScheduler = fetchScheduler
+
+	registeredTimeout := false
+	config.Timer = &common.TimerTest{
+		T: t,
+		RegisterTimeoutF: func(time.Duration) {
+			registeredTimeout = true
+		},
+	}
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blkBytes0 := []byte{0}
+	blkBytes1 := []byte{1}
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+		BytesV:  blkBytes0,
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Unknown,
+		},
+		ParentV: blk0.IDV,
+		HeightV: 1,
+		BytesV:  blkBytes1,
+	}
+
+	vm.CantSetState = false
+	vm.CantLastAccepted = false
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return blk0.ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		require.Equal(blk0.ID(), blkID)
+		return blk0, nil
+	}
+
+	bs, err := New(
+		config,
+		func(context.Context, uint32) error {
+			config.Ctx.State.Set(snow.EngineState{
+				Type:  p2p.EngineType_ENGINE_TYPE_SNOWMAN,
+				State: snow.NormalOp,
+			})
+			return nil
+		},
+	)
+	require.NoError(err)
+
+	require.NoError(bs.Start(context.Background(), 0))
+
+	parsedBlk1 := false
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case blkID0:
+			return blk0, nil
+		case blkID1:
+			if parsedBlk1 {
+				return blk1, nil
+			}
+			return nil, database.ErrNotFound
+		default:
+			require.FailNow(database.ErrNotFound.Error())
+			return nil, database.ErrNotFound
+		}
+	}
+	vm.ParseBlockF = func(_ context.Context, blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes0):
+			return blk0, nil
+		case bytes.Equal(blkBytes, blkBytes1):
+			blk1.StatusV = choices.Processing
+			parsedBlk1 = true
+			return blk1, nil
+		}
+		require.FailNow(errUnknownBlock.Error())
+		return nil, errUnknownBlock
+	}
+
+	requestID := new(uint32)
+	requested := ids.Empty
+	sender.SendGetAncestorsF = func(_ context.Context, vdr ids.NodeID, reqID uint32, blkID ids.ID) {
+		require.Equal(peerID, vdr)
+		require.Equal(blkID1, blkID)
+		*requestID = reqID
+		requested = blkID
+	}
+
+	// Another chain in the subnet is using the only request, so the fetch is
+	// deferred.
+	otherChainID := ids.GenerateTestID()
+	_, ok := fetchScheduler.Acquire(otherChainID, set.Of(peerID))
+	require.True(ok)
+
+	require.NoError(bs.ForceAccepted(context.Background(), []ids.ID{blkID1}))
+	require.Equal(ids.Empty, requested)
+	require.True(registeredTimeout)
+
+	// Once the other chain's request finishes, the fetch is retried.
+	fetchScheduler.Release(otherChainID, peerID)
+	require.NoError(bs.Timeout(context.Background()))
+	require.Equal(blkID1, requested)
+
+	// The other chain can't send a request while this chain's is outstanding.
+	_, ok = fetchScheduler.Acquire(otherChainID, set.Of(peerID))
+	require.False(ok)
+
+	require.NoError(bs.Ancestors(context.Background(), peerID, *requestID, [][]byte{blkBytes1}))
+	require.Equal(snow.NormalOp, config.Ctx.State.Get().State)
+	require.Equal(choices.Accepted, blk1.Status())
+
+	_, ok = fetchScheduler.Acquire(otherChainID, set.Of(peerID))
+	require.True(ok)
+}
+
+func TestBootstrapperShutdownReleasesFetches(t *testing.T) {
+	require := require.New(t)
+
+	config, peerID, _, vm := newConfig(t)
+
+	requestBudget, err := common.NewRequestBudget(common.RequestBudgetConfig{
+		MaxOutstandingRequestsPerPeer: 1,
+	})
+	require.NoError(err)
+	fetchScheduler, err := common.NewFetchScheduler(common.FetchSchedulerConfig{
+		MaxOutstandingRequests: 1,
+	}, requestBudget)
+	require.NoError(err)
+	config.FetchScheduler = fetchScheduler
+
+	bs, err := New(
+		config,
+		func(context.Context, uint32) error {
+			return nil
+		},
+	)
+	require.NoError(err)
+
+	// The chain is shut down while one of its requests is outstanding.
+	_, ok := fetchScheduler.Acquire(config.Ctx.ChainID, set.Of(peerID))
+	require.True(ok)
+
+	otherChainID := ids.GenerateTestID()
+	_, ok = fetchScheduler.Acquire(otherChainID, set.Of(peerID))
+	require.False(ok)
+
+	vm.CantShutdown = false
+	require.NoError(bs.Shutdown(context.Background()))
+
+	_, ok = fetchScheduler.Acquire(otherChainID, set.Of(peerID))
+	require.True(ok)
+}
+
 func TestBootstrapperFinalized(t *testing.T) {
 	require := require.New(t)
 
@@ -23,4 +23,7 @@ type Config struct {
 	Params      snowball.Parameters
 	Consensus   snowman.Consensus
 	PartialSync bool
+	// AdaptiveSampling, if non-nil, reduces the number of validators queried
+	// for a block that has accumulated a strong preference.
+	AdaptiveSampling *AdaptiveSamplingConfig
 }
@@ -18,6 +18,7 @@ type metrics struct {
 	numNonVerifieds                       prometheus.Gauge
 	numBuilt                              prometheus.Counter
 	numBuildsFailed                       prometheus.Counter
+	numBuildsDeferred                     prometheus.Counter
 	numUselessPutBytes                    prometheus.Counter
 	numUselessPushQueryBytes              prometheus.Counter
 	numMissingAcceptedBlocks              prometheus.Counter
@@ -25,6 +26,7 @@ type metrics struct {
 	numProcessingAncestorFetchesDropped   prometheus.Counter
 	numProcessingAncestorFetchesSucceeded prometheus.Counter
 	numProcessingAncestorFetchesUnneeded  prometheus.Counter
+	numReducedSampleQueries               prometheus.Counter
 	getAncestorsBlks                      metric.Averager
 	selectedVoteIndex                     metric.Averager
 }
@@ -66,6 +68,11 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		Name:      "blk_builds_failed",
 		Help:      "Number of BuildBlock calls that have failed",
 	})
+	m.numBuildsDeferred = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blk_builds_deferred",
+		Help:      "Number of times building blocks was deferred because the VM wasn't ready",
+	})
 	m.numUselessPutBytes = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "num_useless_put_bytes",
@@ -101,6 +108,11 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		Name:      "num_processing_ancestor_fetches_unneeded",
 		Help:      "Number of votes that were directly applied to blocks",
 	})
+	m.numReducedSampleQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "num_reduced_sample_queries",
+		Help:      "Number of queries that sampled fewer than k validators due to adaptive sampling",
+	})
 	m.getAncestorsBlks = metric.NewAveragerWithErrs(
 		namespace,
 		"get_ancestors_blks",
@@ -124,6 +136,7 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		reg.Register(m.numNonVerifieds),
 		reg.Register(m.numBuilt),
 		reg.Register(m.numBuildsFailed),
+		reg.Register(m.numBuildsDeferred),
 		reg.Register(m.numUselessPutBytes),
 		reg.Register(m.numUselessPushQueryBytes),
 		reg.Register(m.numMissingAcceptedBlocks),
@@ -131,6 +144,7 @@ func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error
 		reg.Register(m.numProcessingAncestorFetchesDropped),
 		reg.Register(m.numProcessingAncestorFetchesSucceeded),
 		reg.Register(m.numProcessingAncestorFetchesUnneeded),
+		reg.Register(m.numReducedSampleQueries),
 	)
 	return errs.Err
 }
@@ -63,6 +63,12 @@ type Transitive struct {
 	// track outstanding preference requests
 	polls poll.Set
 
+	// confidentBlkID is the preference that the last [numConfidentPolls]
+	// polls reached alphaConfidence for. Only tracked if adaptive sampling is
+	// enabled.
+	confidentBlkID    ids.ID
+	numConfidentPolls int
+
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
 
@@ -90,6 +96,10 @@ type Transitive struct {
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
 
+	// readinessVM is nil if the VM doesn't report whether it is ready to build
+	// blocks.
+	readinessVM common.ReadinessVM
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -112,10 +122,21 @@ func newTransitive(config Config) (*Transitive, error) {
 	acceptedFrontiers := tracker.NewAccepted()
 	config.Validators.RegisterCallbackListener(config.Ctx.SubnetID, acceptedFrontiers)
 
-	factory := poll.NewEarlyTermNoTraversalFactory(
-		config.Params.AlphaPreference,
-		config.Params.AlphaConfidence,
-	)
+	readinessVM, _ := config.VM.(common.ReadinessVM)
+
+	var factory poll.Factory
+	if config.AdaptiveSampling != nil {
+		factory = poll.NewScaledEarlyTermNoTraversalFactory(
+			config.Params.K,
+			config.Params.AlphaPreference,
+			config.Params.AlphaConfidence,
+		)
+	} else {
+		factory = poll.NewEarlyTermNoTraversalFactory(
+			config.Params.AlphaPreference,
+			config.Params.AlphaConfidence,
+		)
+	}
 	t := &Transitive{
 		Config:                      config,
 		StateSummaryFrontierHandler: common.NewNoOpStateSummaryFrontierHandler(config.Ctx.Log),
@@ -129,6 +150,7 @@ func newTransitive(config Config) (*Transitive, error) {
 		nonVerifieds:                ancestor.NewTree(),
 		nonVerifiedCache:            nonVerifiedCache,
 		acceptedFrontiers:           acceptedFrontiers,
+		readinessVM:                 readinessVM,
 		polls: poll.NewSet(
 			factory,
 			config.Ctx.Log,
@@ -383,6 +405,10 @@ func (t *Transitive) Notify(ctx context.Context, msg common.Message) error {
 	case common.StateSyncDone:
 		t.Ctx.StateSyncing.Set(false)
 		return nil
+	case common.VMReady:
+		// the VM is able to build the blocks that were requested while it
+		// wasn't ready.
+		return t.buildBlocks(ctx)
 	default:
 		t.Ctx.Log.Warn("received an unexpected message from the VM",
 			zap.Stringer("messageString", msg),
@@ -564,6 +590,16 @@ func (t *Transitive) buildBlocks(ctx context.Context) error {
 		return err
 	}
 	for t.pendingBuildBlocks > 0 && t.Consensus.NumProcessing() < t.Params.OptimalProcessing {
+		if t.readinessVM != nil && !t.readinessVM.ReadyToBuild(ctx) {
+			// The pending builds are retried once the VM sends VMReady.
+			t.Ctx.Log.Debug("deferring block building",
+				zap.String("reason", "VM isn't ready"),
+				zap.Int("numPendingBuilds", t.pendingBuildBlocks),
+			)
+			t.numBuildsDeferred.Inc()
+			return nil
+		}
+
 		t.pendingBuildBlocks--
 
 		blk, err := t.VM.BuildBlock(ctx)
@@ -791,7 +827,8 @@ func (t *Transitive) sendQuery(
 		zap.Stringer("validators", t.Validators),
 	)
 
-	vdrIDs, err := t.Validators.Sample(t.Ctx.SubnetID, t.Params.K)
+	sampleSize := t.sampleSize(blkID)
+	vdrIDs, err := t.Validators.Sample(t.Ctx.SubnetID, sampleSize)
 	if err != nil {
 		t.Ctx.Log.Error("dropped query for block",
 			zap.String("reason", "insufficient number of validators"),
@@ -822,6 +859,9 @@ func (t *Transitive) sendQuery(
 		)
 		return
 	}
+	if sampleSize < t.Params.K {
+		t.numReducedSampleQueries.Inc()
+	}
 
 	vdrSet := set.Of(vdrIDs...)
 	if push {
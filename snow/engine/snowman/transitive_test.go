@@ -691,6 +691,64 @@ func TestEngineBuildBlock(t *testing.T) {
 	require.True(*pushSent)
 }
 
+type testReadinessVM struct {
+	ready bool
+}
+
+func (vm *testReadinessVM) ReadyToBuild(context.Context) bool {
+	return vm.ready
+}
+
+func TestEngineBuildBlockDeferredUntilVMReady(t *testing.T) {
+	require := require.New(t)
+
+	_, _, sender, vm, te, gBlk := setupDefaultConfig(t)
+
+	sender.Default(true)
+
+	readinessVM := &testReadinessVM{}
+	te.readinessVM = readinessVM
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+
+	pushSent := false
+	sender.SendPushQueryF = func(context.Context, set.Set[ids.NodeID], uint32, []byte, uint64) {
+		pushSent = true
+	}
+
+	vm.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		require.FailNow("should not build a block while the VM isn't ready")
+		return nil, nil
+	}
+	require.NoError(te.Notify(context.Background(), common.PendingTxs))
+	require.Equal(1, te.pendingBuildBlocks)
+
+	readinessVM.ready = true
+	vm.BuildBlockF = func(context.Context) (snowman.Block, error) {
+		return blk, nil
+	}
+	require.NoError(te.Notify(context.Background(), common.VMReady))
+	require.Zero(te.pendingBuildBlocks)
+	require.True(pushSent)
+}
+
 func TestEngineRepoll(t *testing.T) {
 	require := require.New(t)
 	vdr, _, sender, _, te, _ := setupDefaultConfig(t)
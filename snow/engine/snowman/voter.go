@@ -74,9 +74,11 @@ func (v *voter) Update(ctx context.Context) {
 		v.t.Ctx.Log.Debug("finishing poll",
 			zap.Stringer("result", &result),
 		)
+		preference := v.t.Consensus.Preference()
 		if err := v.t.Consensus.RecordPoll(ctx, result); err != nil {
 			v.t.errs.Add(err)
 		}
+		v.t.recordPollConfidence(preference, result)
 	}
 
 	if v.t.errs.Errored() {
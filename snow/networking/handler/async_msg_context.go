@@ -0,0 +1,31 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"context"
+	"time"
+)
+
+var _ context.Context = (*asyncMsgContext)(nil)
+
+// asyncMsgContext is the context an asynchronous message is handled with. It
+// holds the values of the context the message was pushed with, but is only
+// done once [cancelled] is done.
+type asyncMsgContext struct {
+	context.Context
+	cancelled context.Context
+}
+
+func (c *asyncMsgContext) Deadline() (time.Time, bool) {
+	return c.cancelled.Deadline()
+}
+
+func (c *asyncMsgContext) Done() <-chan struct{} {
+	return c.cancelled.Done()
+}
+
+func (c *asyncMsgContext) Err() error {
+	return c.cancelled.Err()
+}
@@ -41,6 +41,9 @@ const (
 	// If a consensus message takes longer than this to process, the handler
 	// will log a warning.
 	syncProcessingTimeWarnLimit = 30 * time.Second
+	// The maximum amount of time we'll wait before re-checking whether the
+	// subnet's CPU usage has dropped to an acceptable level.
+	maxCPUUsageRecheckDelay = time.Second
 )
 
 var (
@@ -110,7 +113,15 @@ type handler struct {
 	asyncMessageQueue MessageQueue
 	// Worker pool for handling asynchronous consensus messages
 	asyncMessagePool errgroup.Group
-	timeouts         chan struct{}
+	// The maximum time to wait for asynchronous messages that are being
+	// handled when the handler stops before cancelling their contexts.
+	drainTimeout time.Duration
+	// Cancels the contexts of the asynchronous messages that are being
+	// handled.
+	cancelAsyncMsgs context.CancelFunc
+	// Done once [cancelAsyncMsgs] is called.
+	asyncMsgsCtx context.Context
+	timeouts     chan struct{}
 
 	closeOnce            sync.Once
 	startClosingTime     time.Time
@@ -136,6 +147,7 @@ func New(
 	msgFromVMChan <-chan common.Message,
 	gossipFrequency time.Duration,
 	threadPoolSize int,
+	drainTimeout time.Duration,
 	resourceTracker tracker.ResourceTracker,
 	subnetConnector validators.SubnetConnector,
 	subnet subnets.Subnet,
@@ -147,6 +159,7 @@ func New(
 		msgFromVMChan:   msgFromVMChan,
 		preemptTimeouts: subnet.OnBootstrapCompleted(),
 		gossipFrequency: gossipFrequency,
+		drainTimeout:    drainTimeout,
 		timeouts:        make(chan struct{}, 1),
 		closingChan:     make(chan struct{}),
 		closed:          make(chan struct{}),
@@ -156,6 +169,7 @@ func New(
 		peerTracker:     peerTracker,
 	}
 	h.asyncMessagePool.SetLimit(threadPoolSize)
+	h.asyncMsgsCtx, h.cancelAsyncMsgs = context.WithCancel(context.Background())
 
 	var err error
 
@@ -364,6 +378,10 @@ func (h *handler) dispatchSync(ctx context.Context) {
 
 	// Handle sync messages from the router
 	for {
+		if !h.awaitCPUUsage() {
+			return
+		}
+
 		// Get the next message we should process. If the handler is shutting
 		// down, we may fail to pop a message.
 		ctx, msg, ok := h.popUnexpiredMsg(h.syncMessageQueue, h.metrics.expired)
@@ -385,14 +403,16 @@ func (h *handler) dispatchSync(ctx context.Context) {
 
 func (h *handler) dispatchAsync(ctx context.Context) {
 	defer func() {
-		// We never return an error in any of our functions, so it is safe to
-		// drop any error here.
-		_ = h.asyncMessagePool.Wait()
+		h.drainAsyncMsgs()
 		h.closeDispatcher(ctx)
 	}()
 
 	// Handle async messages from the router
 	for {
+		if !h.awaitCPUUsage() {
+			return
+		}
+
 		// Get the next message we should process. If the handler is shutting
 		// down, we may fail to pop a message.
 		ctx, msg, ok := h.popUnexpiredMsg(h.asyncMessageQueue, h.metrics.asyncExpired)
@@ -465,6 +485,8 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 	h.resourceTracker.StartProcessing(nodeID, startTime)
 	h.ctx.Lock.Lock()
 	lockAcquiredTime := h.clock.Time()
+	// Waiting for the lock doesn't use the CPU, so it isn't counted.
+	h.subnet.CPUUsage().StartProcessing(lockAcquiredTime)
 	defer func() {
 		h.ctx.Lock.Unlock()
 
@@ -475,6 +497,8 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 			msgHandlingTime   = endTime.Sub(lockAcquiredTime)
 		)
 		h.resourceTracker.StopProcessing(nodeID, endTime)
+		h.subnet.CPUUsage().StopProcessing(endTime)
+		h.metrics.cpuTime.Add(float64(msgHandlingTime))
 		messageHistograms.processingTime.Observe(float64(processingTime))
 		messageHistograms.msgHandlingTime.Observe(float64(msgHandlingTime))
 		msg.OnFinishedHandling()
@@ -780,6 +804,10 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 }
 
 func (h *handler) handleAsyncMsg(ctx context.Context, msg Message) {
+	ctx = &asyncMsgContext{
+		Context:   ctx,
+		cancelled: h.asyncMsgsCtx,
+	}
 	h.asyncMessagePool.Go(func() error {
 		if err := h.executeAsyncMsg(ctx, msg); err != nil {
 			h.StopWithError(ctx, fmt.Errorf(
@@ -813,6 +841,7 @@ func (h *handler) executeAsyncMsg(ctx context.Context, msg Message) error {
 		)
 	}
 	h.resourceTracker.StartProcessing(nodeID, startTime)
+	h.subnet.CPUUsage().StartProcessing(startTime)
 	defer func() {
 		var (
 			endTime           = h.clock.Time()
@@ -820,6 +849,8 @@ func (h *handler) executeAsyncMsg(ctx context.Context, msg Message) error {
 			processingTime    = endTime.Sub(startTime)
 		)
 		h.resourceTracker.StopProcessing(nodeID, endTime)
+		h.subnet.CPUUsage().StopProcessing(endTime)
+		h.metrics.cpuTime.Add(float64(processingTime))
 		// There is no lock grabbed here, so both metrics are identical
 		messageHistograms.processingTime.Observe(float64(processingTime))
 		messageHistograms.msgHandlingTime.Observe(float64(processingTime))
@@ -913,6 +944,8 @@ func (h *handler) handleChanMsg(msg message.InboundMessage) error {
 	}
 	h.ctx.Lock.Lock()
 	lockAcquiredTime := h.clock.Time()
+	// Waiting for the lock doesn't use the CPU, so it isn't counted.
+	h.subnet.CPUUsage().StartProcessing(lockAcquiredTime)
 	defer func() {
 		h.ctx.Lock.Unlock()
 
@@ -922,6 +955,8 @@ func (h *handler) handleChanMsg(msg message.InboundMessage) error {
 			processingTime    = endTime.Sub(startTime)
 			msgHandlingTime   = endTime.Sub(lockAcquiredTime)
 		)
+		h.subnet.CPUUsage().StopProcessing(endTime)
+		h.metrics.cpuTime.Add(float64(msgHandlingTime))
 		messageHistograms.processingTime.Observe(float64(processingTime))
 		messageHistograms.msgHandlingTime.Observe(float64(msgHandlingTime))
 		msg.OnFinishedHandling()
@@ -999,6 +1034,67 @@ func (h *handler) popUnexpiredMsg(
 	}
 }
 
+// awaitCPUUsage blocks until the CPU usage of this chain's subnet has dropped
+// to the maximum allowed by the subnet. Messages generated by the handler and the VM are
+// never delayed.
+// Returns false if the handler started shutting down while waiting.
+func (h *handler) awaitCPUUsage() bool {
+	cpuUsage := h.subnet.CPUUsage()
+	waitDuration := cpuUsage.TimeUntilAllowed(h.clock.Time())
+	if waitDuration <= 0 {
+		return true
+	}
+
+	h.metrics.cpuThrottled.Inc()
+	for waitDuration > 0 {
+		// Re-check at least every [maxCPUUsageRecheckDelay] in case it will
+		// be a very long time until the usage reaches the maximum.
+		if waitDuration > maxCPUUsageRecheckDelay {
+			waitDuration = maxCPUUsageRecheckDelay
+		}
+
+		timer := time.NewTimer(waitDuration)
+		select {
+		case <-h.closingChan:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		waitDuration = cpuUsage.TimeUntilAllowed(h.clock.Time())
+	}
+	return true
+}
+
+// drainAsyncMsgs waits for the asynchronous messages that are being handled
+// to finish. If they don't finish within [h.drainTimeout], their contexts are
+// cancelled and drainAsyncMsgs waits for them to return.
+//
+// Messages that are still queued aren't handled.
+func (h *handler) drainAsyncMsgs() {
+	drained := make(chan struct{})
+	go func() {
+		// We never return an error in any of our functions, so it is safe to
+		// drop any error here.
+		_ = h.asyncMessagePool.Wait()
+		close(drained)
+	}()
+
+	timer := time.NewTimer(h.drainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+		return
+	case <-timer.C:
+	}
+
+	h.ctx.Log.Info("cancelling async messages that didn't finish while draining",
+		zap.Duration("drainTimeout", h.drainTimeout),
+	)
+	h.cancelAsyncMsgs()
+	<-drained
+}
+
 // Invariant: if closeDispatcher is called, Stop has already been called.
 func (h *handler) closeDispatcher(ctx context.Context) {
 	if h.numDispatchersClosed.Add(1) < numDispatchersToClose {
@@ -1012,6 +1108,7 @@ func (h *handler) closeDispatcher(ctx context.Context) {
 // no message dispatchers ever started.
 func (h *handler) shutdown(ctx context.Context, startClosingTime time.Time) {
 	defer func() {
+		h.cancelAsyncMsgs()
 		if h.onStopped != nil {
 			go h.onStopped()
 		}
@@ -30,7 +30,10 @@ import (
 	commontracker "github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 )
 
-const testThreadPoolSize = 2
+const (
+	testThreadPoolSize = 2
+	testDrainTimeout   = time.Second
+)
 
 var errFatal = errors.New("error should cause handler to close")
 
@@ -58,6 +61,7 @@ func TestHandlerDropsTimedOutMessages(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -155,6 +159,7 @@ func TestHandlerClosesOnError(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -248,6 +253,7 @@ func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 		nil,
 		1,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -329,6 +335,7 @@ func TestHandlerDispatchInternal(t *testing.T) {
 		msgFromVMChan,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -406,6 +413,7 @@ func TestHandlerSubnetConnector(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		connector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -578,6 +586,7 @@ func TestDynamicEngineTypeDispatch(t *testing.T) {
 				nil,
 				time.Second,
 				testThreadPoolSize,
+				testDrainTimeout,
 				resourceTracker,
 				validators.UnhandledSubnetConnector,
 				subnets.New(ids.EmptyNodeID, subnets.Config{}),
@@ -652,6 +661,7 @@ func TestHandlerStartError(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		nil,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -671,3 +681,124 @@ func TestHandlerStartError(t *testing.T) {
 	_, err = handler.AwaitStopped(context.Background())
 	require.NoError(err)
 }
+
+func TestHandlerDrainsAsyncMessages(t *testing.T) {
+	tests := []struct {
+		name         string
+		drainTimeout time.Duration
+		// handle is called to handle the AppRequest once the handler
+		// started stopping.
+		handle      func(context.Context) error
+		expectedErr error
+	}{
+		{
+			name:         "finishes before timeout",
+			drainTimeout: time.Minute,
+			handle: func(ctx context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				return ctx.Err()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "cancelled after timeout",
+			drainTimeout: 10 * time.Millisecond,
+			handle: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			expectedErr: context.Canceled,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ctx := snow.DefaultConsensusContextTest()
+			vdrs := validators.NewManager()
+			nodeID := ids.GenerateTestNodeID()
+			require.NoError(vdrs.AddStaker(ctx.SubnetID, nodeID, nil, ids.Empty, 1))
+
+			resourceTracker, err := tracker.NewResourceTracker(
+				prometheus.NewRegistry(),
+				resource.NoUsage,
+				meter.ContinuousFactory{},
+				time.Second,
+			)
+			require.NoError(err)
+			handler, err := New(
+				ctx,
+				vdrs,
+				nil,
+				time.Second,
+				testThreadPoolSize,
+				test.drainTimeout,
+				resourceTracker,
+				validators.UnhandledSubnetConnector,
+				subnets.New(ctx.NodeID, subnets.Config{}),
+				commontracker.NewPeers(),
+			)
+			require.NoError(err)
+
+			bootstrapper := &common.BootstrapperTest{
+				BootstrapableTest: common.BootstrapableTest{
+					T: t,
+				},
+				EngineTest: common.EngineTest{
+					T: t,
+				},
+			}
+			bootstrapper.Default(false)
+
+			var (
+				handling  = make(chan struct{})
+				stopping  = make(chan struct{})
+				handleErr error
+				handled   bool
+			)
+			engine := &common.EngineTest{T: t}
+			engine.Default(false)
+			engine.ContextF = func() *snow.ConsensusContext {
+				return ctx
+			}
+			engine.AppRequestF = func(ctx context.Context, _ ids.NodeID, _ uint32, _ time.Time, _ []byte) error {
+				close(handling)
+				<-stopping
+				handleErr = test.handle(ctx)
+				handled = true
+				return nil
+			}
+			engine.ShutdownF = func(context.Context) error {
+				// The engine is only shut down once the AppRequest finished.
+				require.True(handled)
+				return nil
+			}
+
+			handler.SetEngineManager(&EngineManager{
+				Snowman: &Engine{
+					Bootstrapper: bootstrapper,
+					Consensus:    engine,
+				},
+			})
+			ctx.State.Set(snow.EngineState{
+				Type:  p2p.EngineType_ENGINE_TYPE_SNOWMAN,
+				State: snow.NormalOp, // assumed bootstrap is done
+			})
+
+			handler.Start(context.Background(), false)
+			handler.Push(context.Background(), Message{
+				InboundMessage: message.InboundAppRequest(ids.Empty, 1, time.Minute, nil, nodeID),
+				EngineType:     p2p.EngineType_ENGINE_TYPE_UNSPECIFIED,
+			})
+			<-handling
+
+			handler.Stop(context.Background())
+			close(stopping)
+
+			_, err = handler.AwaitStopped(context.Background())
+			require.NoError(err)
+			require.True(handled)
+			require.ErrorIs(handleErr, test.expectedErr)
+		})
+	}
+}
@@ -74,6 +74,7 @@ func TestHealthCheckSubnet(t *testing.T) {
 				nil,
 				time.Second,
 				testThreadPoolSize,
+				testDrainTimeout,
 				resourceTracker,
 				validators.UnhandledSubnetConnector,
 				sb,
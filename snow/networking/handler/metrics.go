@@ -16,6 +16,8 @@ import (
 type metrics struct {
 	expired      prometheus.Counter
 	asyncExpired prometheus.Counter
+	cpuTime      prometheus.Counter
+	cpuThrottled prometheus.Counter
 	messages     map[message.Op]*messageProcessing
 }
 
@@ -37,9 +39,21 @@ func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
 		Name:      "async_expired",
 		Help:      "Incoming async messages dropped because the message deadline expired",
 	})
+	cpuTime := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cpu_time",
+		Help:      "Time (in ns) spent handling messages, excluding the time spent waiting for the chain's lock",
+	})
+	cpuThrottled := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cpu_throttled",
+		Help:      "Number of times handling messages was delayed because the subnet's CPU usage was too high",
+	})
 	errs.Add(
 		reg.Register(expired),
 		reg.Register(asyncExpired),
+		reg.Register(cpuTime),
+		reg.Register(cpuThrottled),
 	)
 
 	messages := make(map[message.Op]*messageProcessing, len(message.ConsensusOps))
@@ -67,6 +81,8 @@ func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
 	return &metrics{
 		expired:      expired,
 		asyncExpired: asyncExpired,
+		cpuTime:      cpuTime,
+		cpuThrottled: cpuThrottled,
 		messages:     messages,
 	}, errs.Err
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
@@ -85,6 +86,9 @@ type ChainRouter struct {
 	healthConfig HealthConfig
 	// aggregator of requests based on their time
 	timedRequests linkedhashmap.LinkedHashmap[ids.RequestID, requestEntry]
+	// Released once a request is cleared, so that the request no longer
+	// counts against the budget of the peer it was sent to. May be nil.
+	requestBudget common.RequestBudget
 }
 
 // Initialize the router.
@@ -102,6 +106,7 @@ func (cr *ChainRouter) Initialize(
 	trackedSubnets set.Set[ids.ID],
 	onFatal func(exitCode int),
 	healthConfig HealthConfig,
+	requestBudget common.RequestBudget,
 	metricsNamespace string,
 	metricsRegisterer prometheus.Registerer,
 ) error {
@@ -116,6 +121,7 @@ func (cr *ChainRouter) Initialize(
 	cr.timedRequests = linkedhashmap.New[ids.RequestID, requestEntry]()
 	cr.peers = make(map[ids.NodeID]*peer)
 	cr.healthConfig = healthConfig
+	cr.requestBudget = requestBudget
 
 	// Mark myself as connected
 	cr.myNodeID = nodeID
@@ -501,6 +507,7 @@ func (cr *ChainRouter) Disconnected(nodeID ids.NodeID) {
 
 	peer := cr.peers[nodeID]
 	delete(cr.peers, nodeID)
+	cr.timeoutManager.Disconnected(nodeID)
 	if _, benched := cr.benched[nodeID]; benched {
 		return
 	}
@@ -695,6 +702,9 @@ func (cr *ChainRouter) clearRequest(
 
 	cr.timedRequests.Delete(uniqueRequestID)
 	cr.metrics.outstandingRequests.Set(float64(cr.timedRequests.Len()))
+	if cr.requestBudget != nil {
+		cr.requestBudget.Release(uniqueRequestID)
+	}
 	return uniqueRequestID, &request
 }
 
@@ -41,6 +41,7 @@ import (
 const (
 	engineType         = p2p.EngineType_ENGINE_TYPE_AVALANCHE
 	testThreadPoolSize = 2
+	testDrainTimeout   = time.Second
 )
 
 func TestShutdown(t *testing.T) {
@@ -58,6 +59,7 @@ func TestShutdown(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist,
 		"",
 		prometheus.NewRegistry(),
@@ -78,6 +80,7 @@ func TestShutdown(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -97,6 +100,7 @@ func TestShutdown(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(chainCtx.NodeID, subnets.Config{}),
@@ -199,6 +203,7 @@ func TestShutdownTimesOut(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist,
 		"",
 		metrics,
@@ -220,6 +225,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		metrics,
 	))
@@ -237,6 +243,7 @@ func TestShutdownTimesOut(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -340,6 +347,7 @@ func TestRouterTimeout(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"",
 		prometheus.NewRegistry(),
@@ -361,6 +369,7 @@ func TestRouterTimeout(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -399,6 +408,7 @@ func TestRouterTimeout(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -701,6 +711,7 @@ func TestRouterHonorsRequestedEngine(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"",
 		prometheus.NewRegistry(),
@@ -722,6 +733,7 @@ func TestRouterHonorsRequestedEngine(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -834,6 +846,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"",
 		prometheus.NewRegistry(),
@@ -855,6 +868,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -878,6 +892,7 @@ func TestRouterClearTimeouts(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -1126,6 +1141,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"",
 		prometheus.NewRegistry(),
@@ -1147,6 +1163,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -1174,6 +1191,7 @@ func TestValidatorOnlyMessageDrops(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		sb,
@@ -1279,6 +1297,7 @@ func TestRouterCrossChainMessages(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"timeoutManager",
 		prometheus.NewRegistry(),
@@ -1301,6 +1320,7 @@ func TestRouterCrossChainMessages(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -1331,6 +1351,7 @@ func TestRouterCrossChainMessages(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(requester.NodeID, subnets.Config{}),
@@ -1362,6 +1383,7 @@ func TestRouterCrossChainMessages(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(responder.NodeID, subnets.Config{}),
@@ -1453,6 +1475,7 @@ func TestConnectedSubnet(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"timeoutManager",
 		prometheus.NewRegistry(),
@@ -1479,6 +1502,7 @@ func TestConnectedSubnet(t *testing.T) {
 		trackedSubnets,
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -1573,6 +1597,7 @@ func TestValidatorOnlyAllowedNodeMessageDrops(t *testing.T) {
 			TimeoutCoefficient: 1,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist.NewNoBenchlist(),
 		"",
 		prometheus.NewRegistry(),
@@ -1594,6 +1619,7 @@ func TestValidatorOnlyAllowedNodeMessageDrops(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -1626,6 +1652,7 @@ func TestValidatorOnlyAllowedNodeMessageDrops(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		sb,
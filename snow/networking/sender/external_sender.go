@@ -31,4 +31,9 @@ type ExternalSender interface {
 		numPeersToSend int,
 		allower subnets.Allower,
 	) set.Set[ids.NodeID]
+
+	// GossipPeers returns the connected peers that gossip about [subnetID]
+	// can be sent to, mapped to their weight in [subnetID], which is 0 for
+	// peers that aren't validators of [subnetID].
+	GossipPeers(subnetID ids.ID, allower subnets.Allower) map[ids.NodeID]uint64
 }
@@ -54,6 +54,20 @@ func (mr *MockExternalSenderMockRecorder) Gossip(arg0, arg1, arg2, arg3, arg4, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Gossip", reflect.TypeOf((*MockExternalSender)(nil).Gossip), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// GossipPeers mocks base method.
+func (m *MockExternalSender) GossipPeers(arg0 ids.ID, arg1 subnets.Allower) map[ids.NodeID]uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GossipPeers", arg0, arg1)
+	ret0, _ := ret[0].(map[ids.NodeID]uint64)
+	return ret0
+}
+
+// GossipPeers indicates an expected call of GossipPeers.
+func (mr *MockExternalSenderMockRecorder) GossipPeers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GossipPeers", reflect.TypeOf((*MockExternalSender)(nil).GossipPeers), arg0, arg1)
+}
+
 // Send mocks base method.
 func (m *MockExternalSender) Send(arg0 message.OutboundMessage, arg1 set.Set[ids.NodeID], arg2 ids.ID, arg3 subnets.Allower) set.Set[ids.NodeID] {
 	m.ctrl.T.Helper()
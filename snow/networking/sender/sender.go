@@ -40,11 +40,20 @@ type sender struct {
 	router   router.Router
 	timeouts timeout.Manager
 
+	// Limits the requests outstanding to each peer. May be nil.
+	requestBudget common.RequestBudget
+	// Selects the peers that gossip is pushed to. If nil, the network samples
+	// the peers.
+	gossipStrategy common.GossipStrategy
+
 	// Request message type --> Counts how many of that request
 	// have failed because the node was benched
 	failedDueToBench map[message.Op]prometheus.Counter
-	engineType       p2p.EngineType
-	subnet           subnets.Subnet
+	// Request message type --> Counts how many of that request
+	// have failed because the node had no request budget left
+	failedDueToBudget map[message.Op]prometheus.Counter
+	engineType        p2p.EngineType
+	subnet            subnets.Subnet
 }
 
 func New(
@@ -53,52 +62,84 @@ func New(
 	externalSender ExternalSender,
 	router router.Router,
 	timeouts timeout.Manager,
+	requestBudget common.RequestBudget,
+	gossipStrategy common.GossipStrategy,
 	engineType p2p.EngineType,
 	subnet subnets.Subnet,
 ) (common.Sender, error) {
 	s := &sender{
-		ctx:              ctx,
-		msgCreator:       msgCreator,
-		sender:           externalSender,
-		router:           router,
-		timeouts:         timeouts,
-		failedDueToBench: make(map[message.Op]prometheus.Counter, len(message.ConsensusRequestOps)),
-		engineType:       engineType,
-		subnet:           subnet,
+		ctx:               ctx,
+		msgCreator:        msgCreator,
+		sender:            externalSender,
+		router:            router,
+		timeouts:          timeouts,
+		requestBudget:     requestBudget,
+		gossipStrategy:    gossipStrategy,
+		failedDueToBench:  make(map[message.Op]prometheus.Counter, len(message.ConsensusRequestOps)),
+		failedDueToBudget: make(map[message.Op]prometheus.Counter, len(message.ConsensusRequestOps)),
+		engineType:        engineType,
+		subnet:            subnet,
+	}
+
+	var registerer prometheus.Registerer
+	switch engineType {
+	case p2p.EngineType_ENGINE_TYPE_SNOWMAN:
+		registerer = ctx.Registerer
+	case p2p.EngineType_ENGINE_TYPE_AVALANCHE:
+		registerer = ctx.AvalancheRegisterer
+	default:
+		return nil, fmt.Errorf("unknown engine type %s", engineType)
 	}
 
 	for _, op := range message.ConsensusRequestOps {
-		counter := prometheus.NewCounter(
+		benchedCounter := prometheus.NewCounter(
 			prometheus.CounterOpts{
 				Name: fmt.Sprintf("%s_failed_benched", op),
 				Help: fmt.Sprintf("# of times a %s request was not sent because the node was benched", op),
 			},
 		)
-
-		switch engineType {
-		case p2p.EngineType_ENGINE_TYPE_SNOWMAN:
-			if err := ctx.Registerer.Register(counter); err != nil {
-				return nil, fmt.Errorf("couldn't register metric for %s: %w", op, err)
-			}
-		case p2p.EngineType_ENGINE_TYPE_AVALANCHE:
-			if err := ctx.AvalancheRegisterer.Register(counter); err != nil {
-				return nil, fmt.Errorf("couldn't register metric for %s: %w", op, err)
-			}
-		default:
-			return nil, fmt.Errorf("unknown engine type %s", engineType)
+		budgetCounter := prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%s_failed_budget", op),
+				Help: fmt.Sprintf("# of times a %s request was not sent because the node had too many outstanding requests", op),
+			},
+		)
+		if err := registerer.Register(benchedCounter); err != nil {
+			return nil, fmt.Errorf("couldn't register metric for %s: %w", op, err)
+		}
+		if err := registerer.Register(budgetCounter); err != nil {
+			return nil, fmt.Errorf("couldn't register metric for %s: %w", op, err)
 		}
 
-		s.failedDueToBench[op] = counter
+		s.failedDueToBench[op] = benchedCounter
+		s.failedDueToBudget[op] = budgetCounter
 	}
 	return s, nil
 }
 
+// acquireBudget returns true if a request to [nodeID] expecting a response of
+// type [op] can be sent without exceeding the request budget of [nodeID]. If
+// so, the request counts against the budget until the router clears it.
+func (s *sender) acquireBudget(nodeID ids.NodeID, requestID uint32, op message.Op) bool {
+	if s.requestBudget == nil {
+		return true
+	}
+	// Matches the ID of the request registered with the router.
+	return s.requestBudget.Acquire(ids.RequestID{
+		NodeID:             nodeID,
+		SourceChainID:      s.ctx.ChainID,
+		DestinationChainID: s.ctx.ChainID,
+		RequestID:          requestID,
+		Op:                 byte(op),
+	})
+}
+
 func (s *sender) SendGetStateSummaryFrontier(ctx context.Context, nodeIDs set.Set[ids.NodeID], requestID uint32) {
 	ctx = utils.Detach(ctx)
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.StateSummaryFrontierOp)
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from each of these nodes.
@@ -136,6 +177,24 @@ func (s *sender) SendGetStateSummaryFrontier(ctx context.Context, nodeIDs set.Se
 		go s.router.HandleInbound(ctx, inMsg)
 	}
 
+	// Some of [nodeIDs] may have too many outstanding requests. We don't send
+	// them another one. We just have them immediately fail.
+	for nodeID := range nodeIDs {
+		if !s.acquireBudget(nodeID, requestID, message.StateSummaryFrontierOp) {
+			s.failedDueToBudget[message.GetStateSummaryFrontierOp].Inc() // update metric
+			nodeIDs.Remove(nodeID)
+
+			// Immediately register a failure. Do so asynchronously to avoid
+			// deadlock.
+			inMsg := message.InternalGetStateSummaryFrontierFailed(
+				nodeID,
+				s.ctx.ChainID,
+				requestID,
+			)
+			go s.router.HandleInbound(ctx, inMsg)
+		}
+	}
+
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.GetStateSummaryFrontier(
 		s.ctx.ChainID,
@@ -239,7 +298,7 @@ func (s *sender) SendGetAcceptedStateSummary(ctx context.Context, nodeIDs set.Se
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.AcceptedStateSummaryOp)
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from each of these nodes.
@@ -278,6 +337,24 @@ func (s *sender) SendGetAcceptedStateSummary(ctx context.Context, nodeIDs set.Se
 		go s.router.HandleInbound(ctx, inMsg)
 	}
 
+	// Some of [nodeIDs] may have too many outstanding requests. We don't send
+	// them another one. We just have them immediately fail.
+	for nodeID := range nodeIDs {
+		if !s.acquireBudget(nodeID, requestID, message.AcceptedStateSummaryOp) {
+			s.failedDueToBudget[message.GetAcceptedStateSummaryOp].Inc() // update metric
+			nodeIDs.Remove(nodeID)
+
+			// Immediately register a failure. Do so asynchronously to avoid
+			// deadlock.
+			inMsg := message.InternalGetAcceptedStateSummaryFailed(
+				nodeID,
+				s.ctx.ChainID,
+				requestID,
+			)
+			go s.router.HandleInbound(ctx, inMsg)
+		}
+	}
+
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.GetAcceptedStateSummary(
 		s.ctx.ChainID,
@@ -373,7 +450,7 @@ func (s *sender) SendGetAcceptedFrontier(ctx context.Context, nodeIDs set.Set[id
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.AcceptedFrontierOp)
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from each of these nodes.
@@ -508,7 +585,7 @@ func (s *sender) SendGetAccepted(ctx context.Context, nodeIDs set.Set[ids.NodeID
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.AcceptedOp)
 
 	// Tell the router to expect a response message or a message notifying
 	// that we won't get a response from each of these nodes.
@@ -668,14 +745,22 @@ func (s *sender) SendGetAncestors(ctx context.Context, nodeID ids.NodeID, reques
 	// even bother sending requests to them. We just have them immediately fail.
 	if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
 		s.failedDueToBench[message.GetAncestorsOp].Inc() // update metric
-		s.timeouts.RegisterRequestToUnreachableValidator()
+		s.timeouts.RegisterRequestToUnreachableValidator(message.AncestorsOp)
+		go s.router.HandleInbound(ctx, inMsg)
+		return
+	}
+
+	// [nodeID] may have too many outstanding requests. If so, we don't send it
+	// another one. We just have it immediately fail.
+	if !s.acquireBudget(nodeID, requestID, message.AncestorsOp) {
+		s.failedDueToBudget[message.GetAncestorsOp].Inc() // update metric
 		go s.router.HandleInbound(ctx, inMsg)
 		return
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.AncestorsOp)
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.GetAncestors(
 		s.ctx.ChainID,
@@ -714,7 +799,7 @@ func (s *sender) SendGetAncestors(ctx context.Context, nodeID ids.NodeID, reques
 			zap.Stringer("containerID", containerID),
 		)
 
-		s.timeouts.RegisterRequestToUnreachableValidator()
+		s.timeouts.RegisterRequestToUnreachableValidator(message.AncestorsOp)
 		go s.router.HandleInbound(ctx, inMsg)
 	}
 }
@@ -784,14 +869,22 @@ func (s *sender) SendGet(ctx context.Context, nodeID ids.NodeID, requestID uint3
 	// even bother sending requests to them. We just have them immediately fail.
 	if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
 		s.failedDueToBench[message.GetOp].Inc() // update metric
-		s.timeouts.RegisterRequestToUnreachableValidator()
+		s.timeouts.RegisterRequestToUnreachableValidator(message.PutOp)
+		go s.router.HandleInbound(ctx, inMsg)
+		return
+	}
+
+	// [nodeID] may have too many outstanding requests. If so, we don't send it
+	// another one. We just have it immediately fail.
+	if !s.acquireBudget(nodeID, requestID, message.PutOp) {
+		s.failedDueToBudget[message.GetOp].Inc() // update metric
 		go s.router.HandleInbound(ctx, inMsg)
 		return
 	}
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.PutOp)
 	// Create the outbound message.
 	outMsg, err := s.msgCreator.Get(
 		s.ctx.ChainID,
@@ -831,7 +924,7 @@ func (s *sender) SendGet(ctx context.Context, nodeID ids.NodeID, requestID uint3
 			zap.Stringer("containerID", containerID),
 		)
 
-		s.timeouts.RegisterRequestToUnreachableValidator()
+		s.timeouts.RegisterRequestToUnreachableValidator(message.PutOp)
 		go s.router.HandleInbound(ctx, inMsg)
 	}
 }
@@ -913,7 +1006,7 @@ func (s *sender) SendPushQuery(
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.ChitsOp)
 
 	// Sending a message to myself. No need to send it over the network. Just
 	// put it right into the router. Do so asynchronously to avoid deadlock.
@@ -938,7 +1031,26 @@ func (s *sender) SendPushQuery(
 		if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
 			s.failedDueToBench[message.PushQueryOp].Inc() // update metric
 			nodeIDs.Remove(nodeID)
-			s.timeouts.RegisterRequestToUnreachableValidator()
+			s.timeouts.RegisterRequestToUnreachableValidator(message.ChitsOp)
+
+			// Immediately register a failure. Do so asynchronously to avoid
+			// deadlock.
+			inMsg := message.InternalQueryFailed(
+				nodeID,
+				s.ctx.ChainID,
+				requestID,
+				s.engineType,
+			)
+			go s.router.HandleInbound(ctx, inMsg)
+		}
+	}
+
+	// Some of [nodeIDs] may have too many outstanding requests. We don't send
+	// them another one. We just have them immediately fail.
+	for nodeID := range nodeIDs {
+		if !s.acquireBudget(nodeID, requestID, message.ChitsOp) {
+			s.failedDueToBudget[message.PushQueryOp].Inc() // update metric
+			nodeIDs.Remove(nodeID)
 
 			// Immediately register a failure. Do so asynchronously to avoid
 			// deadlock.
@@ -1005,7 +1117,7 @@ func (s *sender) SendPushQuery(
 			}
 
 			// Register failures for nodes we didn't send a request to.
-			s.timeouts.RegisterRequestToUnreachableValidator()
+			s.timeouts.RegisterRequestToUnreachableValidator(message.ChitsOp)
 			inMsg := message.InternalQueryFailed(
 				nodeID,
 				s.ctx.ChainID,
@@ -1052,7 +1164,7 @@ func (s *sender) SendPullQuery(
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.ChitsOp)
 
 	// Sending a message to myself. No need to send it over the network. Just
 	// put it right into the router. Do so asynchronously to avoid deadlock.
@@ -1077,7 +1189,26 @@ func (s *sender) SendPullQuery(
 		if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
 			s.failedDueToBench[message.PullQueryOp].Inc() // update metric
 			nodeIDs.Remove(nodeID)
-			s.timeouts.RegisterRequestToUnreachableValidator()
+			s.timeouts.RegisterRequestToUnreachableValidator(message.ChitsOp)
+			// Immediately register a failure. Do so asynchronously to avoid
+			// deadlock.
+			inMsg := message.InternalQueryFailed(
+				nodeID,
+				s.ctx.ChainID,
+				requestID,
+				s.engineType,
+			)
+			go s.router.HandleInbound(ctx, inMsg)
+		}
+	}
+
+	// Some of [nodeIDs] may have too many outstanding requests. We don't send
+	// them another one. We just have them immediately fail.
+	for nodeID := range nodeIDs {
+		if !s.acquireBudget(nodeID, requestID, message.ChitsOp) {
+			s.failedDueToBudget[message.PullQueryOp].Inc() // update metric
+			nodeIDs.Remove(nodeID)
+
 			// Immediately register a failure. Do so asynchronously to avoid
 			// deadlock.
 			inMsg := message.InternalQueryFailed(
@@ -1133,7 +1264,7 @@ func (s *sender) SendPullQuery(
 			)
 
 			// Register failures for nodes we didn't send a request to.
-			s.timeouts.RegisterRequestToUnreachableValidator()
+			s.timeouts.RegisterRequestToUnreachableValidator(message.ChitsOp)
 			inMsg := message.InternalQueryFailed(
 				nodeID,
 				s.ctx.ChainID,
@@ -1232,7 +1363,7 @@ func (s *sender) SendCrossChainAppRequest(ctx context.Context, chainID ids.ID, r
 		s.ctx.ChainID,
 		chainID,
 		requestID,
-		s.timeouts.TimeoutDuration(),
+		s.timeouts.TimeoutDuration(message.CrossChainAppResponseOp),
 		appRequestBytes,
 	)
 	go s.router.HandleInbound(ctx, inMsg)
@@ -1281,7 +1412,7 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs set.Set[ids.NodeID]
 
 	// Note that this timeout duration won't exactly match the one that gets
 	// registered. That's OK.
-	deadline := s.timeouts.TimeoutDuration()
+	deadline := s.timeouts.TimeoutDuration(message.AppResponseOp)
 
 	// Sending a message to myself. No need to send it over the network. Just
 	// put it right into the router. Do so asynchronously to avoid deadlock.
@@ -1304,7 +1435,25 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs set.Set[ids.NodeID]
 		if s.timeouts.IsBenched(nodeID, s.ctx.ChainID) {
 			s.failedDueToBench[message.AppRequestOp].Inc() // update metric
 			nodeIDs.Remove(nodeID)
-			s.timeouts.RegisterRequestToUnreachableValidator()
+			s.timeouts.RegisterRequestToUnreachableValidator(message.AppResponseOp)
+
+			// Immediately register a failure. Do so asynchronously to avoid
+			// deadlock.
+			inMsg := message.InternalAppRequestFailed(
+				nodeID,
+				s.ctx.ChainID,
+				requestID,
+			)
+			go s.router.HandleInbound(ctx, inMsg)
+		}
+	}
+
+	// Some of [nodeIDs] may have too many outstanding requests. We don't send
+	// them another one. We just have them immediately fail.
+	for nodeID := range nodeIDs {
+		if !s.acquireBudget(nodeID, requestID, message.AppResponseOp) {
+			s.failedDueToBudget[message.AppRequestOp].Inc() // update metric
+			nodeIDs.Remove(nodeID)
 
 			// Immediately register a failure. Do so asynchronously to avoid
 			// deadlock.
@@ -1365,7 +1514,7 @@ func (s *sender) SendAppRequest(ctx context.Context, nodeIDs set.Set[ids.NodeID]
 			}
 
 			// Register failures for nodes we didn't send a request to.
-			s.timeouts.RegisterRequestToUnreachableValidator()
+			s.timeouts.RegisterRequestToUnreachableValidator(message.AppResponseOp)
 			inMsg := message.InternalAppRequestFailed(
 				nodeID,
 				s.ctx.ChainID,
@@ -1498,13 +1647,11 @@ func (s *sender) SendAppGossip(_ context.Context, appGossipBytes []byte) error {
 	nonValidatorSize := int(gossipConfig.AppGossipNonValidatorSize)
 	peerSize := int(gossipConfig.AppGossipPeerSize)
 
-	sentTo := s.sender.Gossip(
+	sentTo := s.gossip(
 		outMsg,
-		s.ctx.SubnetID,
 		validatorSize,
 		nonValidatorSize,
 		peerSize,
-		s.subnet,
 	)
 	if sentTo.Len() == 0 {
 		if s.ctx.Log.Enabled(logging.Verbo) {
@@ -1542,13 +1689,11 @@ func (s *sender) SendGossip(_ context.Context, container []byte) {
 	}
 
 	gossipConfig := s.subnet.Config().GossipConfig
-	sentTo := s.sender.Gossip(
+	sentTo := s.gossip(
 		outMsg,
-		s.ctx.SubnetID,
 		int(gossipConfig.AcceptedFrontierValidatorSize),
 		int(gossipConfig.AcceptedFrontierNonValidatorSize),
 		int(gossipConfig.AcceptedFrontierPeerSize),
-		s.subnet,
 	)
 	if sentTo.Len() == 0 {
 		if s.ctx.Log.Enabled(logging.Verbo) {
@@ -1591,13 +1736,11 @@ func (s *sender) Accept(ctx *snow.ConsensusContext, _ ids.ID, container []byte)
 	}
 
 	gossipConfig := s.subnet.Config().GossipConfig
-	sentTo := s.sender.Gossip(
+	sentTo := s.gossip(
 		outMsg,
-		s.ctx.SubnetID,
 		int(gossipConfig.OnAcceptValidatorSize),
 		int(gossipConfig.OnAcceptNonValidatorSize),
 		int(gossipConfig.OnAcceptPeerSize),
-		s.subnet,
 	)
 	if sentTo.Len() == 0 {
 		if s.ctx.Log.Enabled(logging.Verbo) {
@@ -1615,3 +1758,35 @@ func (s *sender) Accept(ctx *snow.ConsensusContext, _ ids.ID, container []byte)
 	}
 	return nil
 }
+
+// gossip sends [msg] to peers selected by [s.gossipStrategy], or sampled by
+// [s.sender] if there is no strategy, and returns the peers it was sent to.
+func (s *sender) gossip(
+	msg message.OutboundMessage,
+	numValidatorsToSend int,
+	numNonValidatorsToSend int,
+	numPeersToSend int,
+) set.Set[ids.NodeID] {
+	if s.gossipStrategy == nil {
+		return s.sender.Gossip(
+			msg,
+			s.ctx.SubnetID,
+			numValidatorsToSend,
+			numNonValidatorsToSend,
+			numPeersToSend,
+			s.subnet,
+		)
+	}
+
+	weights := s.sender.GossipPeers(s.ctx.SubnetID, s.subnet)
+	peers := make([]common.GossipPeer, 0, len(weights))
+	for nodeID, weight := range weights {
+		peers = append(peers, common.GossipPeer{
+			NodeID:  nodeID,
+			Weight:  weight,
+			Latency: s.timeouts.Latency(nodeID),
+		})
+	}
+	nodeIDs := s.gossipStrategy.Sample(peers, numValidatorsToSend, numNonValidatorsToSend, numPeersToSend)
+	return s.sender.Send(msg, nodeIDs, s.ctx.SubnetID, s.subnet)
+}
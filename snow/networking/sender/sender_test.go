@@ -39,7 +39,10 @@ import (
 	commontracker "github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 )
 
-const testThreadPoolSize = 2
+const (
+	testThreadPoolSize = 2
+	testDrainTimeout   = time.Second
+)
 
 var defaultSubnetConfig = subnets.Config{
 	GossipConfig: subnets.GossipConfig{
@@ -65,6 +68,7 @@ func TestTimeout(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 			TimeoutCoefficient: 1.25,
 		},
+		nil,
 		benchlist,
 		"",
 		prometheus.NewRegistry(),
@@ -94,6 +98,7 @@ func TestTimeout(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		router.HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -107,6 +112,8 @@ func TestTimeout(t *testing.T) {
 		externalSender,
 		&chainRouter,
 		tm,
+		nil,
+		nil,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
 		subnets.New(ctx.NodeID, defaultSubnetConfig),
 	)
@@ -126,6 +133,7 @@ func TestTimeout(t *testing.T) {
 		nil,
 		time.Hour,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -322,6 +330,7 @@ func TestReliableMessages(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 			TimeoutCoefficient: 1.25,
 		},
+		nil,
 		benchlist,
 		"",
 		prometheus.NewRegistry(),
@@ -352,6 +361,7 @@ func TestReliableMessages(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		router.HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -365,6 +375,8 @@ func TestReliableMessages(t *testing.T) {
 		externalSender,
 		&chainRouter,
 		tm,
+		nil,
+		nil,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
 		subnets.New(ctx.NodeID, defaultSubnetConfig),
 	)
@@ -384,6 +396,7 @@ func TestReliableMessages(t *testing.T) {
 		nil,
 		1,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -470,6 +483,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 			TimeoutCoefficient: 1.25,
 		},
+		nil,
 		benchlist,
 		"",
 		prometheus.NewRegistry(),
@@ -500,6 +514,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		router.HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -513,6 +528,8 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		externalSender,
 		&chainRouter,
 		tm,
+		nil,
+		nil,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
 		subnets.New(ctx.NodeID, defaultSubnetConfig),
 	)
@@ -532,6 +549,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 		nil,
 		time.Second,
 		testThreadPoolSize,
+		testDrainTimeout,
 		resourceTracker,
 		validators.UnhandledSubnetConnector,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -825,13 +843,15 @@ func TestSender_Bootstrap_Requests(t *testing.T) {
 				externalSender,
 				router,
 				timeoutManager,
+				nil,
+				nil,
 				engineType,
 				subnets.New(ctx.NodeID, defaultSubnetConfig),
 			)
 			require.NoError(err)
 
 			// Set the timeout (deadline)
-			timeoutManager.EXPECT().TimeoutDuration().Return(deadline).AnyTimes()
+			timeoutManager.EXPECT().TimeoutDuration(gomock.Any()).Return(deadline).AnyTimes()
 
 			// Make sure we register requests with the router
 			for nodeID := range nodeIDs {
@@ -875,6 +895,152 @@ func TestSender_Bootstrap_Requests(t *testing.T) {
 	}
 }
 
+func TestSender_StateSync_RequestBudget(t *testing.T) {
+	var (
+		chainID    = ids.GenerateTestID()
+		subnetID   = ids.GenerateTestID()
+		myNodeID   = ids.GenerateTestNodeID()
+		busyNodeID = ids.GenerateTestNodeID()
+		deadline   = time.Second
+		requestID  = uint32(1337)
+		ctx        = snow.DefaultContextTest()
+		heights    = []uint64{1, 2, 3}
+		engineType = p2p.EngineType_ENGINE_TYPE_SNOWMAN
+	)
+	ctx.ChainID = chainID
+	ctx.SubnetID = subnetID
+	ctx.NodeID = myNodeID
+	snowCtx := &snow.ConsensusContext{
+		Context:             ctx,
+		Registerer:          prometheus.NewRegistry(),
+		AvalancheRegisterer: prometheus.NewRegistry(),
+	}
+
+	type test struct {
+		name                string
+		failedMsg           message.InboundMessage
+		expectedResponseOp  message.Op
+		setMsgCreatorExpect func(msgCreator *message.MockOutboundMsgBuilder)
+		sendF               func(sender common.Sender, nodeIDs set.Set[ids.NodeID])
+	}
+
+	tests := []test{
+		{
+			name: "GetStateSummaryFrontier",
+			failedMsg: message.InternalGetStateSummaryFrontierFailed(
+				busyNodeID,
+				chainID,
+				requestID,
+			),
+			expectedResponseOp: message.StateSummaryFrontierOp,
+			setMsgCreatorExpect: func(msgCreator *message.MockOutboundMsgBuilder) {
+				msgCreator.EXPECT().GetStateSummaryFrontier(
+					chainID,
+					requestID,
+					deadline,
+				).Return(nil, nil)
+			},
+			sendF: func(sender common.Sender, nodeIDs set.Set[ids.NodeID]) {
+				sender.SendGetStateSummaryFrontier(context.Background(), nodeIDs, requestID)
+			},
+		},
+		{
+			name: "GetAcceptedStateSummary",
+			failedMsg: message.InternalGetAcceptedStateSummaryFailed(
+				busyNodeID,
+				chainID,
+				requestID,
+			),
+			expectedResponseOp: message.AcceptedStateSummaryOp,
+			setMsgCreatorExpect: func(msgCreator *message.MockOutboundMsgBuilder) {
+				msgCreator.EXPECT().GetAcceptedStateSummary(
+					chainID,
+					requestID,
+					deadline,
+					heights,
+				).Return(nil, nil)
+			},
+			sendF: func(sender common.Sender, nodeIDs set.Set[ids.NodeID]) {
+				sender.SendGetAcceptedStateSummary(context.Background(), nodeIDs, requestID, heights)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			var (
+				msgCreator     = message.NewMockOutboundMsgBuilder(ctrl)
+				externalSender = NewMockExternalSender(ctrl)
+				timeoutManager = timeout.NewMockManager(ctrl)
+				router         = router.NewMockRouter(ctrl)
+			)
+			snowCtx.Registerer = prometheus.NewRegistry()
+
+			requestBudget, err := common.NewRequestBudget(common.RequestBudgetConfig{
+				MaxOutstandingRequestsPerPeer: 1,
+			})
+			require.NoError(err)
+
+			sender, err := New(
+				snowCtx,
+				msgCreator,
+				externalSender,
+				router,
+				timeoutManager,
+				requestBudget,
+				nil,
+				engineType,
+				subnets.New(ctx.NodeID, defaultSubnetConfig),
+			)
+			require.NoError(err)
+
+			// [busyNodeID] has no request budget left.
+			require.True(requestBudget.Acquire(ids.RequestID{
+				NodeID:             busyNodeID,
+				SourceChainID:      chainID,
+				DestinationChainID: chainID,
+				RequestID:          requestID + 1,
+				Op:                 byte(tt.expectedResponseOp),
+			}))
+
+			timeoutManager.EXPECT().TimeoutDuration(gomock.Any()).Return(deadline).AnyTimes()
+			router.EXPECT().RegisterRequest(
+				gomock.Any(),          // Context
+				busyNodeID,            // Node ID
+				chainID,               // Source Chain
+				chainID,               // Destination Chain
+				requestID,             // Request ID
+				tt.expectedResponseOp, // Operation
+				tt.failedMsg,          // Failure Message
+				p2p.EngineType_ENGINE_TYPE_UNSPECIFIED,
+			)
+
+			// The request fails immediately instead of being sent.
+			calledHandleInbound := make(chan struct{})
+			router.EXPECT().HandleInbound(gomock.Any(), gomock.Any()).Do(
+				func(_ context.Context, msg message.InboundMessage) {
+					require.Equal(tt.failedMsg, msg)
+					close(calledHandleInbound)
+				},
+			)
+			tt.setMsgCreatorExpect(msgCreator)
+			externalSender.EXPECT().Send(
+				gomock.Any(), // Outbound message
+				set.Set[ids.NodeID]{},
+				subnetID, // Subnet ID
+				gomock.Any(),
+			).Return(nil)
+
+			tt.sendF(sender, set.Of(busyNodeID))
+
+			<-calledHandleInbound
+		})
+	}
+}
+
 func TestSender_Bootstrap_Responses(t *testing.T) {
 	var (
 		chainID           = ids.GenerateTestID()
@@ -1047,13 +1213,15 @@ func TestSender_Bootstrap_Responses(t *testing.T) {
 				externalSender,
 				router,
 				timeoutManager,
+				nil,
+				nil,
 				engineType,
 				subnets.New(ctx.NodeID, defaultSubnetConfig),
 			)
 			require.NoError(err)
 
 			// Set the timeout (deadline)
-			timeoutManager.EXPECT().TimeoutDuration().Return(deadline).AnyTimes()
+			timeoutManager.EXPECT().TimeoutDuration(gomock.Any()).Return(deadline).AnyTimes()
 
 			// Case: sending to ourselves
 			{
@@ -1208,19 +1376,26 @@ func TestSender_Single_Request(t *testing.T) {
 			)
 			snowCtx.Registerer = prometheus.NewRegistry()
 
+			requestBudget, err := common.NewRequestBudget(common.RequestBudgetConfig{
+				MaxOutstandingRequestsPerPeer: 1,
+			})
+			require.NoError(err)
+
 			sender, err := New(
 				snowCtx,
 				msgCreator,
 				externalSender,
 				router,
 				timeoutManager,
+				requestBudget,
+				nil,
 				engineType,
 				subnets.New(ctx.NodeID, defaultSubnetConfig),
 			)
 			require.NoError(err)
 
 			// Set the timeout (deadline)
-			timeoutManager.EXPECT().TimeoutDuration().Return(deadline).AnyTimes()
+			timeoutManager.EXPECT().TimeoutDuration(gomock.Any()).Return(deadline).AnyTimes()
 
 			// Case: sending to myself
 			{
@@ -1258,7 +1433,7 @@ func TestSender_Single_Request(t *testing.T) {
 			{
 				timeoutManager.EXPECT().IsBenched(destinationNodeID, chainID).Return(true)
 
-				timeoutManager.EXPECT().RegisterRequestToUnreachableValidator()
+				timeoutManager.EXPECT().RegisterRequestToUnreachableValidator(gomock.Any())
 
 				// Make sure we register requests with the router
 				expectedFailedMsg := tt.failedMsgF(destinationNodeID)
@@ -1290,11 +1465,56 @@ func TestSender_Single_Request(t *testing.T) {
 				<-calledHandleInbound
 			}
 
+			// Case: Node has too many outstanding requests
+			{
+				outstandingRequestID := ids.RequestID{
+					NodeID:             destinationNodeID,
+					SourceChainID:      chainID,
+					DestinationChainID: chainID,
+					RequestID:          requestID + 1,
+					Op:                 byte(tt.expectedResponseOp),
+				}
+				require.True(requestBudget.Acquire(outstandingRequestID))
+
+				timeoutManager.EXPECT().IsBenched(destinationNodeID, chainID).Return(false)
+
+				// Make sure we register requests with the router
+				expectedFailedMsg := tt.failedMsgF(destinationNodeID)
+				router.EXPECT().RegisterRequest(
+					gomock.Any(),          // Context
+					destinationNodeID,     // Node ID
+					chainID,               // Source Chain
+					chainID,               // Destination Chain
+					requestID,             // Request ID
+					tt.expectedResponseOp, // Operation
+					expectedFailedMsg,     // Failure Message
+					engineType,            // Engine Type
+				)
+
+				// Note that HandleInbound is called in a separate goroutine
+				// so we need to use a channel to synchronize the test.
+				calledHandleInbound := make(chan struct{})
+				router.EXPECT().HandleInbound(gomock.Any(), gomock.Any()).Do(
+					func(_ context.Context, msg message.InboundMessage) {
+						// Make sure we're sending ourselves
+						// the expected message.
+						tt.assertMsgToMyself(require, msg)
+						close(calledHandleInbound)
+					},
+				)
+
+				tt.sendF(require, sender, destinationNodeID)
+
+				<-calledHandleInbound
+
+				requestBudget.Release(outstandingRequestID)
+			}
+
 			// Case: Node is not myself, not benched and send fails
 			{
 				timeoutManager.EXPECT().IsBenched(destinationNodeID, chainID).Return(false)
 
-				timeoutManager.EXPECT().RegisterRequestToUnreachableValidator()
+				timeoutManager.EXPECT().RegisterRequestToUnreachableValidator(gomock.Any())
 
 				// Make sure we register requests with the router
 				expectedFailedMsg := tt.failedMsgF(destinationNodeID)
@@ -1334,3 +1554,76 @@ func TestSender_Single_Request(t *testing.T) {
 		})
 	}
 }
+
+// testGossipStrategy records the peers that it's asked to sample from and
+// returns [sampled].
+type testGossipStrategy struct {
+	peers         []common.GossipPeer
+	numValidators int
+	sampled       set.Set[ids.NodeID]
+}
+
+func (s *testGossipStrategy) Sample(peers []common.GossipPeer, numValidators, _, _ int) set.Set[ids.NodeID] {
+	s.peers = peers
+	s.numValidators = numValidators
+	return s.sampled
+}
+
+func TestSenderGossipStrategy(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	var (
+		subnetID       = ids.GenerateTestID()
+		validatorID    = ids.GenerateTestNodeID()
+		nonValidatorID = ids.GenerateTestNodeID()
+		ctx            = snow.DefaultConsensusContextTest()
+		msgCreator     = message.NewMockOutboundMsgBuilder(ctrl)
+		externalSender = NewMockExternalSender(ctrl)
+		timeoutManager = timeout.NewMockManager(ctrl)
+		strategy       = &testGossipStrategy{
+			sampled: set.Of(validatorID),
+		}
+	)
+	ctx.SubnetID = subnetID
+
+	sender, err := New(
+		ctx,
+		msgCreator,
+		externalSender,
+		router.NewMockRouter(ctrl),
+		timeoutManager,
+		nil,
+		strategy,
+		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
+		subnets.New(ctx.NodeID, defaultSubnetConfig),
+	)
+	require.NoError(err)
+
+	// The peers sampled by the strategy are sent the gossip, instead of the
+	// peers sampled by the network.
+	msgCreator.EXPECT().AppGossip(ctx.ChainID, gomock.Any()).Return(nil, nil)
+	externalSender.EXPECT().GossipPeers(subnetID, gomock.Any()).Return(map[ids.NodeID]uint64{
+		validatorID:    10,
+		nonValidatorID: 0,
+	})
+	timeoutManager.EXPECT().Latency(validatorID).Return(time.Second)
+	timeoutManager.EXPECT().Latency(nonValidatorID).Return(time.Duration(0))
+	externalSender.EXPECT().Send(gomock.Any(), set.Of(validatorID), subnetID, gomock.Any()).Return(set.Of(validatorID))
+
+	require.NoError(sender.SendAppGossip(context.Background(), []byte{1}))
+	require.ElementsMatch(
+		[]common.GossipPeer{
+			{
+				NodeID:  validatorID,
+				Weight:  10,
+				Latency: time.Second,
+			},
+			{
+				NodeID: nonValidatorID,
+			},
+		},
+		strategy.peers,
+	)
+	require.Equal(int(defaultSubnetConfig.AppGossipValidatorSize), strategy.numValidators)
+}
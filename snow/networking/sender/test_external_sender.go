@@ -14,24 +14,27 @@ import (
 )
 
 var (
-	errSend   = errors.New("unexpectedly called Send")
-	errGossip = errors.New("unexpectedly called Gossip")
+	errSend        = errors.New("unexpectedly called Send")
+	errGossip      = errors.New("unexpectedly called Gossip")
+	errGossipPeers = errors.New("unexpectedly called GossipPeers")
 )
 
 // ExternalSenderTest is a test sender
 type ExternalSenderTest struct {
 	TB testing.TB
 
-	CantSend, CantGossip bool
+	CantSend, CantGossip, CantGossipPeers bool
 
-	SendF   func(msg message.OutboundMessage, nodeIDs set.Set[ids.NodeID], subnetID ids.ID, allower subnets.Allower) set.Set[ids.NodeID]
-	GossipF func(msg message.OutboundMessage, subnetID ids.ID, numValidatorsToSend, numNonValidatorsToSend, numPeersToSend int, allower subnets.Allower) set.Set[ids.NodeID]
+	SendF        func(msg message.OutboundMessage, nodeIDs set.Set[ids.NodeID], subnetID ids.ID, allower subnets.Allower) set.Set[ids.NodeID]
+	GossipF      func(msg message.OutboundMessage, subnetID ids.ID, numValidatorsToSend, numNonValidatorsToSend, numPeersToSend int, allower subnets.Allower) set.Set[ids.NodeID]
+	GossipPeersF func(subnetID ids.ID, allower subnets.Allower) map[ids.NodeID]uint64
 }
 
 // Default set the default callable value to [cant]
 func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantSend = cant
 	s.CantGossip = cant
+	s.CantGossipPeers = cant
 }
 
 func (s *ExternalSenderTest) Send(
@@ -74,3 +77,16 @@ func (s *ExternalSenderTest) Gossip(
 	}
 	return nil
 }
+
+func (s *ExternalSenderTest) GossipPeers(subnetID ids.ID, allower subnets.Allower) map[ids.NodeID]uint64 {
+	if s.GossipPeersF != nil {
+		return s.GossipPeersF(subnetID, allower)
+	}
+	if s.CantGossipPeers {
+		if s.TB != nil {
+			s.TB.Helper()
+			s.TB.Fatal(errGossipPeers)
+		}
+	}
+	return nil
+}
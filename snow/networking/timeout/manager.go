@@ -14,9 +14,14 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/utils/math"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
+// latencyHalflife is the halflife of the average latency of each node's
+// responses.
+const latencyHalflife = time.Minute
+
 var _ Manager = (*manager)(nil)
 
 // Manages timeouts for requests sent to peers.
@@ -24,8 +29,9 @@ type Manager interface {
 	// Start the manager. Must be called before any other method.
 	// Should be called in a goroutine.
 	Dispatch()
-	// TimeoutDuration returns the current timeout duration.
-	TimeoutDuration() time.Duration
+	// TimeoutDuration returns the current timeout duration of requests that
+	// expect a response of type [op].
+	TimeoutDuration(op message.Op) time.Duration
 	// IsBenched returns true if messages to [nodeID] regarding [chainID]
 	// should not be sent over the network and should immediately fail.
 	IsBenched(nodeID ids.NodeID, chainID ids.ID) bool
@@ -48,7 +54,8 @@ type Manager interface {
 	// (e.g. we're not connected), so we didn't send the query. For the sake
 	// of calculating the average latency and network timeout, we act as
 	// though we sent the validator a request and it timed out.
-	RegisterRequestToUnreachableValidator()
+	// [op] is the type of the response that we would have expected.
+	RegisterRequestToUnreachableValidator(op message.Op)
 	// Registers that [nodeID] sent us a response of type [op]
 	// for the given chain. The response corresponds to the given
 	// requestID we sent them. [latency] is the time between us
@@ -63,13 +70,26 @@ type Manager interface {
 	// Mark that we no longer expect a response to this request we sent.
 	// Does not modify the timeout.
 	RemoveRequest(requestID ids.RequestID)
+	// Latency returns the average latency of the responses of [nodeID], or 0
+	// if no response of [nodeID] was registered.
+	Latency(nodeID ids.NodeID) time.Duration
+	// Disconnected forgets the latency of the responses of [nodeID], which
+	// this node is no longer connected to.
+	Disconnected(nodeID ids.NodeID)
 
 	// Stops the manager.
 	Stop()
 }
 
+// NewManager returns a timeout manager that adapts the timeout of each type of
+// response separately, so that slow responses, such as AppResponses carrying
+// sync proofs, don't share a timeout with fast ones, such as Chits.
+//
+// The timeout of responses of type op is configured by [opTimeoutConfigs][op]
+// if it is set and by [timeoutConfig] otherwise.
 func NewManager(
 	timeoutConfig *timer.AdaptiveTimeoutConfig,
+	opTimeoutConfigs map[message.Op]timer.AdaptiveTimeoutConfig,
 	benchlistMgr benchlist.Manager,
 	metricsNamespace string,
 	metricsRegister prometheus.Registerer,
@@ -82,25 +102,64 @@ func NewManager(
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create timeout manager: %w", err)
 	}
+
+	opTMs := make(map[message.Op]timer.AdaptiveTimeoutManager, len(message.FailedToResponseOps))
+	for _, op := range message.FailedToResponseOps {
+		opTimeoutConfig, ok := opTimeoutConfigs[op]
+		if !ok {
+			opTimeoutConfig = *timeoutConfig
+		}
+		opTM, err := timer.NewAdaptiveTimeoutManager(
+			&opTimeoutConfig,
+			fmt.Sprintf("%s_%s", metricsNamespace, op),
+			metricsRegister,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create %s timeout manager: %w", op, err)
+		}
+		opTMs[op] = opTM
+	}
 	return &manager{
 		benchlistMgr: benchlistMgr,
 		tm:           tm,
+		opTMs:        opTMs,
+		latencies:    make(map[ids.NodeID]math.Averager),
 	}, nil
 }
 
 type manager struct {
-	tm           timer.AdaptiveTimeoutManager
+	// Manages the timeouts of requests whose response type isn't in [opTMs].
+	tm timer.AdaptiveTimeoutManager
+	// Response type --> manager of the timeouts of requests expecting that
+	// type of response.
+	opTMs        map[message.Op]timer.AdaptiveTimeoutManager
 	benchlistMgr benchlist.Manager
 	metrics      metrics
 	stopOnce     sync.Once
+
+	latenciesLock sync.Mutex
+	// Node --> average latency of its responses
+	latencies map[ids.NodeID]math.Averager
 }
 
 func (m *manager) Dispatch() {
+	for _, opTM := range m.opTMs {
+		go opTM.Dispatch()
+	}
 	m.tm.Dispatch()
 }
 
-func (m *manager) TimeoutDuration() time.Duration {
-	return m.tm.TimeoutDuration()
+func (m *manager) TimeoutDuration(op message.Op) time.Duration {
+	return m.getTM(op).TimeoutDuration()
+}
+
+// getTM returns the manager of the timeouts of requests expecting a response
+// of type [op].
+func (m *manager) getTM(op message.Op) timer.AdaptiveTimeoutManager {
+	if opTM, ok := m.opTMs[op]; ok {
+		return opTM
+	}
+	return m.tm
 }
 
 // IsBenched returns true if messages to [nodeID] regarding [chainID]
@@ -137,7 +196,7 @@ func (m *manager) RegisterRequest(
 		}
 		timeoutHandler()
 	}
-	m.tm.Put(requestID, measureLatency, newTimeoutHandler)
+	m.getTM(message.Op(requestID.Op)).Put(requestID, measureLatency, newTimeoutHandler)
 }
 
 // RegisterResponse registers that we received a response from [nodeID]
@@ -151,19 +210,55 @@ func (m *manager) RegisterResponse(
 ) {
 	m.metrics.Observe(nodeID, chainID, op, latency)
 	m.benchlistMgr.RegisterResponse(chainID, nodeID)
-	m.tm.Remove(requestID)
+	m.getTM(message.Op(requestID.Op)).Remove(requestID)
+	m.observeLatency(nodeID, latency)
+}
+
+func (m *manager) observeLatency(nodeID ids.NodeID, latency time.Duration) {
+	m.latenciesLock.Lock()
+	defer m.latenciesLock.Unlock()
+
+	now := time.Now()
+	averager, ok := m.latencies[nodeID]
+	if !ok {
+		m.latencies[nodeID] = math.NewAverager(float64(latency), latencyHalflife, now)
+		return
+	}
+	averager.Observe(float64(latency), now)
+}
+
+func (m *manager) Latency(nodeID ids.NodeID) time.Duration {
+	m.latenciesLock.Lock()
+	defer m.latenciesLock.Unlock()
+
+	averager, ok := m.latencies[nodeID]
+	if !ok {
+		return 0
+	}
+	return time.Duration(averager.Read())
+}
+
+func (m *manager) Disconnected(nodeID ids.NodeID) {
+	m.latenciesLock.Lock()
+	defer m.latenciesLock.Unlock()
+
+	delete(m.latencies, nodeID)
 }
 
 func (m *manager) RemoveRequest(requestID ids.RequestID) {
-	m.tm.Remove(requestID)
+	m.getTM(message.Op(requestID.Op)).Remove(requestID)
 }
 
-func (m *manager) RegisterRequestToUnreachableValidator() {
-	m.tm.ObserveLatency(m.TimeoutDuration())
+func (m *manager) RegisterRequestToUnreachableValidator(op message.Op) {
+	tm := m.getTM(op)
+	tm.ObserveLatency(tm.TimeoutDuration())
 }
 
 func (m *manager) Stop() {
 	m.stopOnce.Do(func() {
+		for _, opTM := range m.opTMs {
+			opTM.Stop()
+		}
 		m.tm.Stop()
 	})
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
@@ -27,6 +28,7 @@ func TestManagerFire(t *testing.T) {
 			TimeoutCoefficient: 1.25,
 			TimeoutHalflife:    5 * time.Minute,
 		},
+		nil,
 		benchlist,
 		"",
 		prometheus.NewRegistry(),
@@ -48,3 +50,111 @@ func TestManagerFire(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestManagerOpTimeouts(t *testing.T) {
+	require := require.New(t)
+
+	defaultConfig := timer.AdaptiveTimeoutConfig{
+		InitialTimeout:     time.Millisecond,
+		MinimumTimeout:     time.Millisecond,
+		MaximumTimeout:     10 * time.Second,
+		TimeoutCoefficient: 1.25,
+		TimeoutHalflife:    5 * time.Minute,
+	}
+	appResponseConfig := defaultConfig
+	appResponseConfig.InitialTimeout = time.Hour
+	appResponseConfig.MaximumTimeout = time.Hour
+
+	manager, err := NewManager(
+		&defaultConfig,
+		map[message.Op]timer.AdaptiveTimeoutConfig{
+			message.AppResponseOp: appResponseConfig,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+	go manager.Dispatch()
+	defer manager.Stop()
+
+	require.Equal(time.Millisecond, manager.TimeoutDuration(message.ChitsOp))
+	require.Equal(time.Hour, manager.TimeoutDuration(message.AppResponseOp))
+
+	// Timeouts of one response type shouldn't affect the others.
+	manager.RegisterRequestToUnreachableValidator(message.ChitsOp)
+	require.Greater(manager.TimeoutDuration(message.ChitsOp), time.Millisecond)
+	require.Equal(time.Millisecond, manager.TimeoutDuration(message.PutOp))
+	require.Equal(time.Hour, manager.TimeoutDuration(message.AppResponseOp))
+
+	// A request expecting Chits should time out long before a request
+	// expecting an AppResponse.
+	var (
+		appResponseTimedOut = make(chan struct{})
+		chitsTimedOut       = make(chan struct{})
+	)
+	manager.RegisterRequest(
+		ids.NodeID{},
+		ids.ID{},
+		true,
+		ids.RequestID{Op: byte(message.AppResponseOp)},
+		func() {
+			close(appResponseTimedOut)
+		},
+	)
+	manager.RegisterRequest(
+		ids.NodeID{},
+		ids.ID{},
+		true,
+		ids.RequestID{Op: byte(message.ChitsOp)},
+		func() {
+			close(chitsTimedOut)
+		},
+	)
+	<-chitsTimedOut
+
+	select {
+	case <-appResponseTimedOut:
+		require.FailNow("request expecting an AppResponse timed out")
+	default:
+	}
+}
+
+func TestManagerLatency(t *testing.T) {
+	require := require.New(t)
+
+	manager, err := NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     time.Millisecond,
+			MinimumTimeout:     time.Millisecond,
+			MaximumTimeout:     10 * time.Second,
+			TimeoutCoefficient: 1.25,
+			TimeoutHalflife:    5 * time.Minute,
+		},
+		nil,
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	var (
+		nodeID0 = ids.GenerateTestNodeID()
+		nodeID1 = ids.GenerateTestNodeID()
+	)
+	require.Zero(manager.Latency(nodeID0))
+
+	manager.RegisterResponse(nodeID0, ids.Empty, ids.RequestID{}, message.ChitsOp, 100*time.Millisecond)
+	require.Equal(100*time.Millisecond, manager.Latency(nodeID0))
+	require.Zero(manager.Latency(nodeID1))
+
+	// The latency is averaged over the responses.
+	manager.RegisterResponse(nodeID0, ids.Empty, ids.RequestID{}, message.ChitsOp, 300*time.Millisecond)
+	latency := manager.Latency(nodeID0)
+	require.Greater(latency, 100*time.Millisecond)
+	require.Less(latency, 300*time.Millisecond)
+
+	// The latency is forgotten once the node disconnects.
+	manager.Disconnected(nodeID0)
+	require.Zero(manager.Latency(nodeID0))
+}
@@ -52,6 +52,18 @@ func (mr *MockManagerMockRecorder) Dispatch() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockManager)(nil).Dispatch))
 }
 
+// Disconnected mocks base method.
+func (m *MockManager) Disconnected(arg0 ids.NodeID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Disconnected", arg0)
+}
+
+// Disconnected indicates an expected call of Disconnected.
+func (mr *MockManagerMockRecorder) Disconnected(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnected", reflect.TypeOf((*MockManager)(nil).Disconnected), arg0)
+}
+
 // IsBenched mocks base method.
 func (m *MockManager) IsBenched(arg0 ids.NodeID, arg1 ids.ID) bool {
 	m.ctrl.T.Helper()
@@ -66,6 +78,20 @@ func (mr *MockManagerMockRecorder) IsBenched(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBenched", reflect.TypeOf((*MockManager)(nil).IsBenched), arg0, arg1)
 }
 
+// Latency mocks base method.
+func (m *MockManager) Latency(arg0 ids.NodeID) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latency", arg0)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Latency indicates an expected call of Latency.
+func (mr *MockManagerMockRecorder) Latency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latency", reflect.TypeOf((*MockManager)(nil).Latency), arg0)
+}
+
 // RegisterChain mocks base method.
 func (m *MockManager) RegisterChain(arg0 *snow.ConsensusContext) error {
 	m.ctrl.T.Helper()
@@ -93,15 +119,15 @@ func (mr *MockManagerMockRecorder) RegisterRequest(arg0, arg1, arg2, arg3, arg4
 }
 
 // RegisterRequestToUnreachableValidator mocks base method.
-func (m *MockManager) RegisterRequestToUnreachableValidator() {
+func (m *MockManager) RegisterRequestToUnreachableValidator(arg0 message.Op) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "RegisterRequestToUnreachableValidator")
+	m.ctrl.Call(m, "RegisterRequestToUnreachableValidator", arg0)
 }
 
 // RegisterRequestToUnreachableValidator indicates an expected call of RegisterRequestToUnreachableValidator.
-func (mr *MockManagerMockRecorder) RegisterRequestToUnreachableValidator() *gomock.Call {
+func (mr *MockManagerMockRecorder) RegisterRequestToUnreachableValidator(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRequestToUnreachableValidator", reflect.TypeOf((*MockManager)(nil).RegisterRequestToUnreachableValidator))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRequestToUnreachableValidator", reflect.TypeOf((*MockManager)(nil).RegisterRequestToUnreachableValidator), arg0)
 }
 
 // RegisterResponse mocks base method.
@@ -141,15 +167,15 @@ func (mr *MockManagerMockRecorder) Stop() *gomock.Call {
 }
 
 // TimeoutDuration mocks base method.
-func (m *MockManager) TimeoutDuration() time.Duration {
+func (m *MockManager) TimeoutDuration(arg0 message.Op) time.Duration {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TimeoutDuration")
+	ret := m.ctrl.Call(m, "TimeoutDuration", arg0)
 	ret0, _ := ret[0].(time.Duration)
 	return ret0
 }
 
 // TimeoutDuration indicates an expected call of TimeoutDuration.
-func (mr *MockManagerMockRecorder) TimeoutDuration() *gomock.Call {
+func (mr *MockManagerMockRecorder) TimeoutDuration(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeoutDuration", reflect.TypeOf((*MockManager)(nil).TimeoutDuration))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimeoutDuration", reflect.TypeOf((*MockManager)(nil).TimeoutDuration), arg0)
 }
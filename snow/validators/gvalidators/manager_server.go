@@ -0,0 +1,235 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/validators"
+)
+
+// The maximum number of changes that may be queued for a subscriber before it
+// is dropped.
+const subscriptionBufferSize = 1024
+
+var (
+	_ pb.ValidatorsServer            = (*ManagerServer)(nil)
+	_ validators.SetCallbackListener = (*ManagerServer)(nil)
+
+	errSubscriberTooSlow = errors.New("subscriber too slow")
+)
+
+type subscriber struct {
+	changes chan *pb.ValidatorChange
+	// dropped is closed if the subscriber fell too far behind.
+	dropped chan struct{}
+}
+
+// ManagerServer serves the primary network validator set tracked by a
+// validators.Manager to external consumers.
+type ManagerServer struct {
+	pb.UnsafeValidatorsServer
+
+	state validators.State
+
+	lock sync.Mutex
+	// validators mirrors the current primary network validator set, including
+	// the txIDs that aren't exposed by the manager.
+	validators  map[ids.NodeID]*pb.Validator
+	subscribers set.Set[*subscriber]
+}
+
+// NewManagerServer returns a server for the primary network validators in
+// [manager]. Historical validator sets are read from [state].
+func NewManagerServer(manager validators.Manager, state validators.State) *ManagerServer {
+	s := &ManagerServer{
+		state:      state,
+		validators: make(map[ids.NodeID]*pb.Validator),
+	}
+	manager.RegisterCallbackListener(constants.PrimaryNetworkID, s)
+	return s
+}
+
+func (s *ManagerServer) OnValidatorAdded(nodeID ids.NodeID, pk *bls.PublicKey, txID ids.ID, weight uint64) {
+	var pkBytes []byte
+	if pk != nil {
+		pkBytes = bls.PublicKeyToBytes(pk)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.validators[nodeID] = &pb.Validator{
+		NodeId:    nodeID.Bytes(),
+		Weight:    weight,
+		PublicKey: pkBytes,
+		TxId:      txID[:],
+	}
+	s.publish(&pb.ValidatorChange{
+		NodeId:    nodeID.Bytes(),
+		PublicKey: pkBytes,
+		TxId:      txID[:],
+		NewWeight: weight,
+	})
+}
+
+func (s *ManagerServer) OnValidatorRemoved(nodeID ids.NodeID, weight uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.validators, nodeID)
+	s.publish(&pb.ValidatorChange{
+		NodeId:    nodeID.Bytes(),
+		OldWeight: weight,
+	})
+}
+
+func (s *ManagerServer) OnValidatorWeightChanged(nodeID ids.NodeID, oldWeight, newWeight uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if vdr, ok := s.validators[nodeID]; ok {
+		vdr.Weight = newWeight
+	}
+	s.publish(&pb.ValidatorChange{
+		NodeId:    nodeID.Bytes(),
+		OldWeight: oldWeight,
+		NewWeight: newWeight,
+	})
+}
+
+func (s *ManagerServer) GetCurrentHeight(ctx context.Context, _ *emptypb.Empty) (*pb.GetCurrentHeightResponse, error) {
+	height, err := s.state.GetCurrentHeight(ctx)
+	return &pb.GetCurrentHeightResponse{Height: height}, err
+}
+
+func (s *ManagerServer) GetCurrentValidators(context.Context, *emptypb.Empty) (*pb.GetCurrentValidatorsResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return &pb.GetCurrentValidatorsResponse{
+		Validators: s.currentValidators(),
+	}, nil
+}
+
+func (s *ManagerServer) GetValidatorsAt(ctx context.Context, req *pb.GetValidatorsAtRequest) (*pb.GetValidatorsAtResponse, error) {
+	vdrs, err := s.state.GetValidatorSet(ctx, req.Height, constants.PrimaryNetworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetValidatorsAtResponse{
+		Validators: make([]*pb.Validator, 0, len(vdrs)),
+	}
+	for _, vdr := range vdrs {
+		vdrPB := &pb.Validator{
+			NodeId: vdr.NodeID.Bytes(),
+			Weight: vdr.Weight,
+		}
+		if vdr.PublicKey != nil {
+			vdrPB.PublicKey = bls.PublicKeyToBytes(vdr.PublicKey)
+		}
+		resp.Validators = append(resp.Validators, vdrPB)
+	}
+	sortValidators(resp.Validators)
+	return resp, nil
+}
+
+func (s *ManagerServer) WatchValidators(_ *emptypb.Empty, stream pb.Validators_WatchValidatorsServer) error {
+	sub := &subscriber{
+		changes: make(chan *pb.ValidatorChange, subscriptionBufferSize),
+		dropped: make(chan struct{}),
+	}
+
+	// The snapshot is taken while holding the lock so that no change is
+	// missed or sent twice.
+	s.lock.Lock()
+	snapshot := s.currentValidators()
+	s.subscribers.Add(sub)
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		s.subscribers.Remove(sub)
+		s.lock.Unlock()
+	}()
+
+	for _, vdr := range snapshot {
+		err := stream.Send(&pb.ValidatorChange{
+			NodeId:    vdr.NodeId,
+			PublicKey: vdr.PublicKey,
+			TxId:      vdr.TxId,
+			NewWeight: vdr.Weight,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case change := <-sub.changes:
+			if err := stream.Send(change); err != nil {
+				return err
+			}
+		case <-sub.dropped:
+			return errSubscriberTooSlow
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// publish queues [change] for every subscriber. Subscribers that can't keep up
+// are dropped rather than blocking updates to the validator set.
+//
+// Assumes [s.lock] is held.
+func (s *ManagerServer) publish(change *pb.ValidatorChange) {
+	for sub := range s.subscribers {
+		select {
+		case sub.changes <- change:
+		default:
+			close(sub.dropped)
+			s.subscribers.Remove(sub)
+		}
+	}
+}
+
+// currentValidators returns a copy of the current validators sorted by nodeID.
+//
+// Assumes [s.lock] is held.
+func (s *ManagerServer) currentValidators() []*pb.Validator {
+	vdrs := maps.Values(s.validators)
+	for i, vdr := range vdrs {
+		vdrs[i] = &pb.Validator{
+			NodeId:    vdr.NodeId,
+			Weight:    vdr.Weight,
+			PublicKey: vdr.PublicKey,
+			TxId:      vdr.TxId,
+		}
+	}
+	sortValidators(vdrs)
+	return vdrs
+}
+
+func sortValidators(vdrs []*pb.Validator) {
+	slices.SortFunc(vdrs, func(a, b *pb.Validator) bool {
+		return bytes.Compare(a.NodeId, b.NodeId) < 0
+	})
+}
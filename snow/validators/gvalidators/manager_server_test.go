@@ -0,0 +1,200 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gvalidators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/mock/gomock"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/validators"
+)
+
+type testManagerServer struct {
+	client  pb.ValidatorsClient
+	manager validators.Manager
+	state   *validators.MockState
+	closeFn func()
+}
+
+func setupManagerServer(t testing.TB, ctrl *gomock.Controller) *testManagerServer {
+	require := require.New(t)
+
+	t.Helper()
+
+	s := &testManagerServer{
+		manager: validators.NewManager(),
+		state:   validators.NewMockState(ctrl),
+	}
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+	serverCloser := grpcutils.ServerCloser{}
+
+	server := grpcutils.NewServer()
+	pb.RegisterValidatorsServer(server, NewManagerServer(s.manager, s.state))
+	serverCloser.Add(server)
+
+	go grpcutils.Serve(listener, server)
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+
+	s.client = pb.NewValidatorsClient(conn)
+	s.closeFn = func() {
+		serverCloser.Stop()
+		_ = conn.Close()
+		_ = listener.Close()
+	}
+	return s
+}
+
+func TestManagerServerGetCurrentValidators(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	s := setupManagerServer(t, ctrl)
+	defer s.closeFn()
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	pk := bls.PublicFromSecretKey(sk)
+
+	nodeID := ids.GenerateTestNodeID()
+	txID := ids.GenerateTestID()
+	require.NoError(s.manager.AddStaker(constants.PrimaryNetworkID, nodeID, pk, txID, 1))
+	require.NoError(s.manager.AddWeight(constants.PrimaryNetworkID, nodeID, 2))
+
+	// Subnet validators aren't exposed.
+	require.NoError(s.manager.AddStaker(ids.GenerateTestID(), ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+
+	resp, err := s.client.GetCurrentValidators(context.Background(), &emptypb.Empty{})
+	require.NoError(err)
+	require.Len(resp.Validators, 1)
+
+	vdr := resp.Validators[0]
+	require.Equal(nodeID.Bytes(), vdr.NodeId)
+	require.Equal(uint64(3), vdr.Weight)
+	require.Equal(bls.PublicKeyToBytes(pk), vdr.PublicKey)
+	require.Equal(txID[:], vdr.TxId)
+}
+
+func TestManagerServerGetValidatorsAt(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	s := setupManagerServer(t, ctrl)
+	defer s.closeFn()
+
+	nodeID := ids.GenerateTestNodeID()
+	s.state.EXPECT().GetValidatorSet(gomock.Any(), uint64(5), constants.PrimaryNetworkID).Return(
+		map[ids.NodeID]*validators.GetValidatorOutput{
+			nodeID: {
+				NodeID: nodeID,
+				Weight: 10,
+			},
+		},
+		nil,
+	)
+
+	resp, err := s.client.GetValidatorsAt(context.Background(), &pb.GetValidatorsAtRequest{Height: 5})
+	require.NoError(err)
+	require.Len(resp.Validators, 1)
+	require.Equal(nodeID.Bytes(), resp.Validators[0].NodeId)
+	require.Equal(uint64(10), resp.Validators[0].Weight)
+	require.Empty(resp.Validators[0].PublicKey)
+
+	s.state.EXPECT().GetValidatorSet(gomock.Any(), uint64(6), constants.PrimaryNetworkID).Return(nil, errCustom)
+
+	_, err = s.client.GetValidatorsAt(context.Background(), &pb.GetValidatorsAtRequest{Height: 6})
+	require.Error(err) //nolint:forbidigo // currently returns grpc error
+}
+
+func TestManagerServerWatchValidators(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	s := setupManagerServer(t, ctrl)
+	defer s.closeFn()
+
+	nodeID0 := ids.GenerateTestNodeID()
+	require.NoError(s.manager.AddStaker(constants.PrimaryNetworkID, nodeID0, nil, ids.Empty, 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := s.client.WatchValidators(ctx, &emptypb.Empty{})
+	require.NoError(err)
+
+	// The stream starts with the current validators.
+	change, err := stream.Recv()
+	require.NoError(err)
+	require.Equal(nodeID0.Bytes(), change.NodeId)
+	require.Zero(change.OldWeight)
+	require.Equal(uint64(1), change.NewWeight)
+
+	nodeID1 := ids.GenerateTestNodeID()
+	require.NoError(s.manager.AddStaker(constants.PrimaryNetworkID, nodeID1, nil, ids.Empty, 2))
+	require.NoError(s.manager.AddWeight(constants.PrimaryNetworkID, nodeID0, 3))
+	require.NoError(s.manager.RemoveWeight(constants.PrimaryNetworkID, nodeID1, 2))
+
+	expectedChanges := []*pb.ValidatorChange{
+		{
+			NodeId:    nodeID1.Bytes(),
+			TxId:      ids.Empty[:],
+			NewWeight: 2,
+		},
+		{
+			NodeId:    nodeID0.Bytes(),
+			OldWeight: 1,
+			NewWeight: 4,
+		},
+		{
+			NodeId:    nodeID1.Bytes(),
+			OldWeight: 2,
+		},
+	}
+	for _, expected := range expectedChanges {
+		change, err := stream.Recv()
+		require.NoError(err)
+		require.Equal(expected.NodeId, change.NodeId)
+		require.Equal(expected.TxId, change.TxId)
+		require.Equal(expected.OldWeight, change.OldWeight)
+		require.Equal(expected.NewWeight, change.NewWeight)
+	}
+}
+
+func TestManagerServerDropsSlowSubscriber(t *testing.T) {
+	require := require.New(t)
+
+	s := NewManagerServer(validators.NewManager(), nil)
+	sub := &subscriber{
+		changes: make(chan *pb.ValidatorChange, subscriptionBufferSize),
+		dropped: make(chan struct{}),
+	}
+	s.subscribers.Add(sub)
+
+	nodeID := ids.GenerateTestNodeID()
+	for i := 0; i <= subscriptionBufferSize; i++ {
+		s.OnValidatorWeightChanged(nodeID, uint64(i), uint64(i+1))
+	}
+
+	require.Zero(s.subscribers.Len())
+	select {
+	case <-sub.dropped:
+	default:
+		require.FailNow("subscriber wasn't dropped")
+	}
+}
@@ -13,7 +13,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 )
 
-var errAllowedNodesWhenNotValidatorOnly = errors.New("allowedNodes can only be set when ValidatorOnly is true")
+var (
+	errAllowedNodesWhenNotValidatorOnly = errors.New("allowedNodes can only be set when ValidatorOnly is true")
+	errNegativeMaxCPUUsage              = errors.New("maxCPUUsage can't be negative")
+	errNegativeProposerBuildStagger     = errors.New("proposerBuildStagger can't be negative")
+)
 
 type GossipConfig struct {
 	AcceptedFrontierValidatorSize    uint `json:"gossipAcceptedFrontierValidatorSize"    yaml:"gossipAcceptedFrontierValidatorSize"`
@@ -63,6 +67,21 @@ type Config struct {
 	// TODO: Move this flag once the proposervm is configurable on a per-chain
 	// basis.
 	ProposerNumHistoricalBlocks uint64 `json:"proposerNumHistoricalBlocks" yaml:"proposerNumHistoricalBlocks"`
+	// ProposerBuildStagger is the minimum time between this node notifying
+	// two of this Subnet's Chains to build a snowman++ block. This avoids
+	// building blocks for several Chains at the same instant, which can be
+	// useful on resource constrained nodes or for Chains that share a mempool.
+	// If 0, Chains are notified independently of each other.
+	ProposerBuildStagger time.Duration `json:"proposerBuildStagger" yaml:"proposerBuildStagger"`
+
+	// MaxCPUUsage is the number of cores this Subnet's Chains may use in
+	// total, on average, to handle messages. While they use more, this node
+	// delays handling the Chains' messages so that a misbehaving Subnet can't
+	// starve the other Subnets running on this node.
+	// Disk usage isn't limited. It is reported, for each Chain, by the metrics
+	// of the Chain's database.
+	// If 0, the handling of messages is never delayed.
+	MaxCPUUsage float64 `json:"maxCPUUsage" yaml:"maxCPUUsage"`
 }
 
 func (c *Config) Valid() error {
@@ -72,5 +91,11 @@ func (c *Config) Valid() error {
 	if !c.ValidatorOnly && c.AllowedNodes.Len() > 0 {
 		return errAllowedNodesWhenNotValidatorOnly
 	}
+	if c.MaxCPUUsage < 0 {
+		return fmt.Errorf("%w: %f", errNegativeMaxCPUUsage, c.MaxCPUUsage)
+	}
+	if c.ProposerBuildStagger < 0 {
+		return fmt.Errorf("%w: %s", errNegativeProposerBuildStagger, c.ProposerBuildStagger)
+	}
 	return nil
 }
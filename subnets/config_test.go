@@ -5,6 +5,7 @@ package subnets
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -50,6 +51,22 @@ func TestValid(t *testing.T) {
 			},
 			expectedErr: errAllowedNodesWhenNotValidatorOnly,
 		},
+		{
+			name: "negative max CPU usage",
+			s: Config{
+				ConsensusParameters: validParameters,
+				MaxCPUUsage:         -1,
+			},
+			expectedErr: errNegativeMaxCPUUsage,
+		},
+		{
+			name: "negative proposer build stagger",
+			s: Config{
+				ConsensusParameters:  validParameters,
+				ProposerBuildStagger: -time.Second,
+			},
+			expectedErr: errNegativeProposerBuildStagger,
+		},
 		{
 			name: "valid",
 			s: Config{
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnets
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/math/meter"
+)
+
+// The halflife of the average CPU usage of a subnet.
+const cpuUsageHalflife = 15 * time.Second
+
+var _ CPUUsage = (*cpuUsage)(nil)
+
+// CPUUsage tracks the number of cores that the chains of a subnet use, on
+// average, to handle messages.
+type CPUUsage interface {
+	// StartProcessing marks that one of the chains started handling a message
+	// at [now].
+	StartProcessing(now time.Time)
+
+	// StopProcessing marks that one of the chains stopped handling a message
+	// at [now].
+	StopProcessing(now time.Time)
+
+	// TimeUntilAllowed returns how long to wait after [now] before handling
+	// the next message, assuming no message is handled until then.
+	// Returns 0 if the next message can be handled immediately.
+	TimeUntilAllowed(now time.Time) time.Duration
+}
+
+type cpuUsage struct {
+	// The number of cores the chains may use before the handling of their
+	// messages is delayed. If 0, the handling of messages is never delayed.
+	maxUsage float64
+
+	lock  sync.Mutex
+	meter meter.Meter
+}
+
+func newCPUUsage(maxUsage float64) *cpuUsage {
+	return &cpuUsage{
+		maxUsage: maxUsage,
+		meter:    meter.NewMeter(cpuUsageHalflife),
+	}
+}
+
+func (c *cpuUsage) StartProcessing(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.meter.Inc(now, 1)
+}
+
+func (c *cpuUsage) StopProcessing(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.meter.Dec(now, 1)
+}
+
+func (c *cpuUsage) TimeUntilAllowed(now time.Time) time.Duration {
+	if c.maxUsage <= 0 {
+		return 0
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.meter.TimeUntil(now, c.maxUsage)
+}
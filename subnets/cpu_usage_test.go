@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCPUUsageTimeUntilAllowed(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxUsage      float64
+		sinceHandled  time.Duration
+		expectAllowed bool
+	}{
+		{
+			name:          "unlimited",
+			maxUsage:      0,
+			expectAllowed: true,
+		},
+		{
+			name:          "usage too high",
+			maxUsage:      0.5,
+			expectAllowed: false,
+		},
+		{
+			name:          "usage decayed",
+			maxUsage:      0.5,
+			sinceHandled:  2 * cpuUsageHalflife,
+			expectAllowed: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			usage := newCPUUsage(test.maxUsage)
+
+			// Handle a message for long enough that the usage is ~1 core.
+			now := time.Unix(0, 0)
+			usage.StartProcessing(now)
+			now = now.Add(10 * cpuUsageHalflife)
+			usage.StopProcessing(now)
+
+			waitDuration := usage.TimeUntilAllowed(now.Add(test.sinceHandled))
+			if test.expectAllowed {
+				require.Zero(waitDuration)
+			} else {
+				require.Positive(waitDuration)
+			}
+		})
+	}
+}
+
+func TestCPUUsageSharedByChains(t *testing.T) {
+	require := require.New(t)
+
+	usage := newCPUUsage(1.5)
+
+	// Two chains of the subnet each handle messages with ~1 core, which is
+	// more than the subnet may use even though each chain uses less.
+	now := time.Unix(0, 0)
+	usage.StartProcessing(now)
+	usage.StartProcessing(now)
+	now = now.Add(10 * cpuUsageHalflife)
+	usage.StopProcessing(now)
+	usage.StopProcessing(now)
+
+	require.Positive(usage.TimeUntilAllowed(now))
+}
@@ -30,6 +30,9 @@ type Subnet interface {
 	// Config returns config of this Subnet
 	Config() Config
 
+	// CPUUsage returns the CPU usage of this Subnet's chains
+	CPUUsage() CPUUsage
+
 	Allower
 }
 
@@ -41,6 +44,7 @@ type subnet struct {
 	bootstrappedSema chan struct{}
 	config           Config
 	myNodeID         ids.NodeID
+	cpuUsage         *cpuUsage
 }
 
 func New(myNodeID ids.NodeID, config Config) Subnet {
@@ -48,6 +52,7 @@ func New(myNodeID ids.NodeID, config Config) Subnet {
 		bootstrappedSema: make(chan struct{}),
 		config:           config,
 		myNodeID:         myNodeID,
+		cpuUsage:         newCPUUsage(config.MaxCPUUsage),
 	}
 }
 
@@ -93,6 +98,10 @@ func (s *subnet) Config() Config {
 	return s.config
 }
 
+func (s *subnet) CPUUsage() CPUUsage {
+	return s.cpuUsage
+}
+
 func (s *subnet) IsAllowed(nodeID ids.NodeID, isValidator bool) bool {
 	// Case 1: NodeID is this node
 	// Case 2: This subnet is not validator-only subnet
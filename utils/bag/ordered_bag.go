@@ -0,0 +1,267 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bag
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var (
+	errDuplicateElement     = errors.New("duplicate element")
+	errInvalidCount         = errors.New("invalid count")
+	errInvalidLength        = errors.New("invalid length")
+	errInvalidSize          = errors.New("invalid element size")
+	errInvalidElementLength = errors.New("invalid element length")
+)
+
+// OrderedBag is a multiset that iterates over its unique elements in the order
+// they were first added.
+//
+// Unlike Bag, the results of List and Mode are deterministic, so an OrderedBag
+// can be used wherever iteration order is observable, such as when hashing or
+// serializing the bag.
+type OrderedBag[T comparable] struct {
+	counts map[T]int
+	order  set.OrderedSet[T]
+	size   int
+}
+
+// OfOrdered returns an OrderedBag initialized with [elts] in the provided
+// order.
+func OfOrdered[T comparable](elts ...T) OrderedBag[T] {
+	var b OrderedBag[T]
+	b.Add(elts...)
+	return b
+}
+
+// Sorted returns an OrderedBag containing the elements of [b], with their
+// counts, in sorted order.
+func Sorted[T interface {
+	comparable
+	utils.Sortable[T]
+}](b Bag[T]) OrderedBag[T] {
+	elts := b.List()
+	utils.Sort(elts)
+
+	var ordered OrderedBag[T]
+	for _, elt := range elts {
+		ordered.AddCount(elt, b.Count(elt))
+	}
+	return ordered
+}
+
+func (b *OrderedBag[T]) init() {
+	if b.counts == nil {
+		b.counts = make(map[T]int, minBagSize)
+	}
+}
+
+// Add increases the number of times each element has been seen by one.
+func (b *OrderedBag[T]) Add(elts ...T) {
+	for _, elt := range elts {
+		b.AddCount(elt, 1)
+	}
+}
+
+// AddCount increases the number of times the element has been seen by [count].
+// If [count] <= 0 this is a no-op.
+func (b *OrderedBag[T]) AddCount(elt T, count int) {
+	if count <= 0 {
+		return
+	}
+
+	b.init()
+
+	b.counts[elt] += count
+	b.order.Add(elt)
+	b.size += count
+}
+
+// Count returns the number of [elt] in the bag.
+func (b *OrderedBag[T]) Count(elt T) int {
+	return b.counts[elt]
+}
+
+// Len returns the number of elements in the bag.
+func (b *OrderedBag[_]) Len() int {
+	return b.size
+}
+
+// List returns a list of unique elements that have been added, in the order
+// they were first added.
+// The returned list doesn't have duplicates.
+func (b *OrderedBag[T]) List() []T {
+	return b.order.List()
+}
+
+// Equals returns true if the bags contain the same elements, with the same
+// counts, in the same order.
+func (b *OrderedBag[T]) Equals(other OrderedBag[T]) bool {
+	if b.size != other.size || !b.order.Equals(other.order) {
+		return false
+	}
+	for elt, count := range b.counts {
+		if other.counts[elt] != count {
+			return false
+		}
+	}
+	return true
+}
+
+// Mode returns the most common element in the bag and the count of that element.
+// If there's a tie, the tied element that was added first is returned.
+func (b *OrderedBag[T]) Mode() (T, int) {
+	var (
+		mode     T
+		modeFreq int
+	)
+	for _, elt := range b.order.List() {
+		if count := b.counts[elt]; count > modeFreq {
+			mode = elt
+			modeFreq = count
+		}
+	}
+	return mode, modeFreq
+}
+
+// Remove all instances of [elt] from the bag.
+func (b *OrderedBag[T]) Remove(elt T) {
+	count := b.counts[elt]
+	delete(b.counts, elt)
+	b.order.Remove(elt)
+	b.size -= count
+}
+
+// Bag returns the elements of this bag, with their counts, as an unordered Bag.
+func (b *OrderedBag[T]) Bag() Bag[T] {
+	var unordered Bag[T]
+	for elt, count := range b.counts {
+		unordered.AddCount(elt, count)
+	}
+	return unordered
+}
+
+func (b *OrderedBag[T]) PrefixedString(prefix string) string {
+	sb := strings.Builder{}
+
+	sb.WriteString(fmt.Sprintf("OrderedBag[%T]: (Size = %d)", utils.Zero[T](), b.Len()))
+	for _, elt := range b.order.List() {
+		sb.WriteString(fmt.Sprintf("\n%s    %v: %d", prefix, elt, b.counts[elt]))
+	}
+
+	return sb.String()
+}
+
+func (b *OrderedBag[_]) String() string {
+	return b.PrefixedString("")
+}
+
+// MarshalOrdered returns the binary encoding of [b]. The encoding is the number
+// of unique elements followed by each element and its count, in the order they
+// appear in [b]. [toBytes] must encode every element into exactly [size] bytes,
+// which must be positive.
+//
+// Two bags have the same encoding iff they contain the same elements, with the
+// same counts, in the same order. Use Sorted to encode a Bag canonically.
+func MarshalOrdered[T comparable](b *OrderedBag[T], size int, toBytes func(T) []byte) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %d", errInvalidSize, size)
+	}
+	elts := b.order.List()
+	if len(elts) > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: %d elements", errInvalidLength, len(elts))
+	}
+	length, err := encodedLen(len(elts), size)
+	if err != nil {
+		return nil, err
+	}
+
+	p := wrappers.Packer{
+		Bytes: make([]byte, length),
+	}
+	p.PackInt(uint32(len(elts)))
+	for _, elt := range elts {
+		eltBytes := toBytes(elt)
+		if len(eltBytes) != size {
+			return nil, fmt.Errorf("%w: %v encoded into %d bytes but expected %d", errInvalidElementLength, elt, len(eltBytes), size)
+		}
+		p.PackFixedBytes(eltBytes)
+		p.PackInt(uint32(b.counts[elt]))
+	}
+	return p.Bytes, p.Err
+}
+
+// UnmarshalOrdered parses the encoding produced by MarshalOrdered.
+// [fromBytes] is called with the [size] byte encoding of each element, so
+// [size] must be positive.
+func UnmarshalOrdered[T comparable](b []byte, size int, fromBytes func([]byte) (T, error)) (OrderedBag[T], error) {
+	// A size of 0 would allow any number of elements to be encoded in a
+	// fixed number of bytes.
+	if size <= 0 {
+		return OrderedBag[T]{}, fmt.Errorf("%w: %d", errInvalidSize, size)
+	}
+
+	p := wrappers.Packer{Bytes: b}
+	numElts := int(p.UnpackInt())
+	if p.Err != nil {
+		return OrderedBag[T]{}, p.Err
+	}
+	// Verify the length before allocating to avoid large allocations from
+	// malformed input.
+	expectedLen, err := encodedLen(numElts, size)
+	if err != nil {
+		return OrderedBag[T]{}, err
+	}
+	if len(b) != expectedLen {
+		return OrderedBag[T]{}, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidLength, expectedLen, len(b))
+	}
+
+	var ob OrderedBag[T]
+	for i := 0; i < numElts; i++ {
+		elt, err := fromBytes(p.UnpackFixedBytes(size))
+		if err != nil {
+			return OrderedBag[T]{}, err
+		}
+		count := p.UnpackInt()
+		if count == 0 || count > math.MaxInt32 {
+			return OrderedBag[T]{}, fmt.Errorf("%w: %d", errInvalidCount, count)
+		}
+		if ob.Count(elt) != 0 {
+			return OrderedBag[T]{}, fmt.Errorf("%w: %v", errDuplicateElement, elt)
+		}
+		ob.AddCount(elt, int(count))
+	}
+	return ob, nil
+}
+
+// encodedLen returns the length of the encoding of [numElts] elements of
+// [size] bytes each, with their counts. Returns an error if the length
+// doesn't fit in an int.
+func encodedLen(numElts, size int) (int, error) {
+	eltLen, err := safemath.Add64(uint64(size), wrappers.IntLen)
+	if err != nil {
+		return 0, err
+	}
+	eltsLen, err := safemath.Mul64(uint64(numElts), eltLen)
+	if err != nil {
+		return 0, err
+	}
+	length, err := safemath.Add64(eltsLen, wrappers.IntLen)
+	if err != nil {
+		return 0, err
+	}
+	if length > math.MaxInt {
+		return 0, safemath.ErrOverflow
+	}
+	return int(length), nil
+}
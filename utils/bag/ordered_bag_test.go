@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bag
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var errInvalidTestElement = errors.New("invalid test element")
+
+type testElement uint32
+
+func (e testElement) Less(other testElement) bool {
+	return e < other
+}
+
+func testElementBytes(e testElement) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(e))
+}
+
+func parseTestElement(b []byte) (testElement, error) {
+	if len(b) != wrappers.IntLen {
+		return 0, errInvalidTestElement
+	}
+	return testElement(binary.BigEndian.Uint32(b)), nil
+}
+
+func TestOrderedBag(t *testing.T) {
+	require := require.New(t)
+
+	b := OrderedBag[int]{}
+	b.Add(3, 1, 3)
+	b.AddCount(2, 3)
+	b.AddCount(4, 0)
+	require.Equal(6, b.Len())
+	require.Equal([]int{3, 1, 2}, b.List())
+	require.Equal(2, b.Count(3))
+	require.Equal(1, b.Count(1))
+	require.Equal(3, b.Count(2))
+	require.Zero(b.Count(4))
+
+	mode, freq := b.Mode()
+	require.Equal(2, mode)
+	require.Equal(3, freq)
+
+	b.Remove(2)
+	require.Equal([]int{3, 1}, b.List())
+	require.Equal(3, b.Len())
+
+	// Ties are broken by the order the elements were added
+	b.Add(1)
+	mode, freq = b.Mode()
+	require.Equal(3, mode)
+	require.Equal(2, freq)
+
+	require.True(b.Equals(OfOrdered(3, 3, 1, 1)))
+	require.False(b.Equals(OfOrdered(1, 1, 3, 3)))
+
+	unordered := b.Bag()
+	require.True(unordered.Equals(Of(1, 1, 3, 3)))
+}
+
+func TestSorted(t *testing.T) {
+	require := require.New(t)
+
+	b := Of[testElement](5, 1, 5, 2)
+	sorted := Sorted(b)
+	require.Equal([]testElement{1, 2, 5}, sorted.List())
+	require.Equal(2, sorted.Count(5))
+}
+
+func TestOrderedBagMarshalBinary(t *testing.T) {
+	tests := []struct {
+		name        string
+		bytes       []byte
+		expected    OrderedBag[testElement]
+		expectedErr error
+	}{
+		{
+			name:     "empty",
+			bytes:    []byte{0, 0, 0, 0},
+			expected: OrderedBag[testElement]{},
+		},
+		{
+			name: "ordered",
+			bytes: []byte{
+				0, 0, 0, 2,
+				0, 0, 0, 2, 0, 0, 0, 3,
+				0, 0, 0, 1, 0, 0, 0, 1,
+			},
+			expected: OfOrdered[testElement](2, 2, 1, 2),
+		},
+		{
+			name: "duplicate element",
+			bytes: []byte{
+				0, 0, 0, 2,
+				0, 0, 0, 1, 0, 0, 0, 1,
+				0, 0, 0, 1, 0, 0, 0, 1,
+			},
+			expectedErr: errDuplicateElement,
+		},
+		{
+			name: "zero count",
+			bytes: []byte{
+				0, 0, 0, 1,
+				0, 0, 0, 1, 0, 0, 0, 0,
+			},
+			expectedErr: errInvalidCount,
+		},
+		{
+			name: "trailing bytes",
+			bytes: []byte{
+				0, 0, 0, 1,
+				0, 0, 0, 1, 0, 0, 0, 1,
+				0,
+			},
+			expectedErr: errInvalidLength,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			b, err := UnmarshalOrdered(test.bytes, wrappers.IntLen, parseTestElement)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.True(test.expected.Equals(b))
+			bytes, err := MarshalOrdered(&b, wrappers.IntLen, testElementBytes)
+			require.NoError(err)
+			require.Equal(test.bytes, bytes)
+		})
+	}
+}
+
+func TestUnmarshalOrderedInvalidSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		_, err := UnmarshalOrdered([]byte{0xff, 0xff, 0xff, 0xff}, size, parseTestElement)
+		require.ErrorIs(t, err, errInvalidSize)
+	}
+}
+
+func TestUnmarshalOrderedLengthOverflow(t *testing.T) {
+	_, err := UnmarshalOrdered([]byte{0xff, 0xff, 0xff, 0xff}, math.MaxInt, parseTestElement)
+	require.ErrorIs(t, err, safemath.ErrOverflow)
+}
+
+func TestMarshalOrderedInvalidSize(t *testing.T) {
+	var b OrderedBag[testElement]
+	b.Add(1, 2)
+	for _, size := range []int{0, -1} {
+		_, err := MarshalOrdered(&b, size, testElementBytes)
+		require.ErrorIs(t, err, errInvalidSize)
+	}
+}
+
+func TestMarshalOrderedInvalidElementLength(t *testing.T) {
+	var b OrderedBag[testElement]
+	b.Add(1, 2)
+	_, err := MarshalOrdered(&b, wrappers.LongLen, testElementBytes)
+	require.ErrorIs(t, err, errInvalidElementLength)
+}
@@ -73,7 +73,9 @@ const (
 	// Router
 	DefaultAcceptedFrontierGossipFrequency                 = 10 * time.Second
 	DefaultConsensusAppConcurrency                         = 2
+	DefaultConsensusAppDrainTimeout                        = 5 * time.Second
 	DefaultConsensusShutdownTimeout                        = time.Minute
+	DefaultConsensusMaxOutstandingRequestsPerPeer          = 256
 	DefaultConsensusGossipAcceptedFrontierValidatorSize    = 0
 	DefaultConsensusGossipAcceptedFrontierNonValidatorSize = 0
 	DefaultConsensusGossipAcceptedFrontierPeerSize         = 15
@@ -0,0 +1,148 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	_ Describer = Method[struct{}, struct{}]{}
+
+	errInvalidMethodName = errors.New("invalid method name")
+	errMissingHandler    = errors.New("service doesn't handle method")
+	errWrongHandlerType  = errors.New("handler has the wrong type")
+	errUndeclaredHandler = errors.New("handler isn't declared")
+
+	httpRequestType = reflect.TypeOf((*http.Request)(nil))
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Method is a method of an API, declared once so that the API's clients and
+// its service agree on the method's name and on the types of its arguments
+// and reply.
+type Method[Args, Reply any] struct {
+	// The name that clients call the method with, formatted as
+	// "<service>.<method>", where <method> is the name of the handler of the
+	// service with its first letter lowercased.
+	Name string
+}
+
+// Call calls the method with [args] through [requester] and returns its
+// reply.
+func (m Method[Args, Reply]) Call(
+	ctx context.Context,
+	requester EndpointRequester,
+	args *Args,
+	options ...Option,
+) (*Reply, error) {
+	reply := new(Reply)
+	err := requester.SendRequest(ctx, m.Name, args, reply, options...)
+	return reply, err
+}
+
+// Describe returns the name of the method and the types of its arguments and
+// reply.
+func (m Method[Args, Reply]) Describe() MethodDescription {
+	return MethodDescription{
+		Name:      m.Name,
+		ArgsType:  reflect.TypeOf((*Args)(nil)).Elem(),
+		ReplyType: reflect.TypeOf((*Reply)(nil)).Elem(),
+	}
+}
+
+// MethodDescription is a [Method] without its type parameters.
+type MethodDescription struct {
+	Name      string
+	ArgsType  reflect.Type
+	ReplyType reflect.Type
+}
+
+// Describer describes a method, whatever its types are.
+type Describer interface {
+	Describe() MethodDescription
+}
+
+// VerifyService returns an error unless the handlers of [service], once it's
+// registered as [serviceName] with a gorilla rpc server using the codec of
+// utils/json, are exactly [methods].
+// That is, every method has a handler of type
+//
+//	func(*http.Request, *Args, *Reply) error
+//
+// and every handler is one of the methods.
+//
+// It's meant to be called by the tests of a service, so that the methods its
+// clients call can't drift from its handlers.
+func VerifyService(serviceName string, service interface{}, methods ...Describer) error {
+	serviceType := reflect.TypeOf(service)
+	declared := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		description := method.Describe()
+		handlerName, err := handlerName(serviceName, description.Name)
+		if err != nil {
+			return err
+		}
+		declared[handlerName] = struct{}{}
+
+		handler, ok := serviceType.MethodByName(handlerName)
+		if !ok {
+			return fmt.Errorf("%w: %s", errMissingHandler, description.Name)
+		}
+		if !isHandler(handler) ||
+			handler.Type.In(2) != reflect.PointerTo(description.ArgsType) ||
+			handler.Type.In(3) != reflect.PointerTo(description.ReplyType) {
+			return fmt.Errorf("%w: %s is %s but %s takes *%s and *%s",
+				errWrongHandlerType,
+				handlerName,
+				handler.Type,
+				description.Name,
+				description.ArgsType,
+				description.ReplyType,
+			)
+		}
+	}
+
+	for i := 0; i < serviceType.NumMethod(); i++ {
+		handler := serviceType.Method(i)
+		if _, ok := declared[handler.Name]; !ok && isHandler(handler) {
+			return fmt.Errorf("%w: %s", errUndeclaredHandler, handler.Name)
+		}
+	}
+	return nil
+}
+
+// handlerName returns the name of the handler of the service registered as
+// [serviceName] that handles the method [methodName].
+func handlerName(serviceName string, methodName string) (string, error) {
+	service, method, ok := strings.Cut(methodName, ".")
+	firstRune, runeLen := utf8.DecodeRuneInString(method)
+	if !ok || service != serviceName || !unicode.IsLower(firstRune) {
+		return "", fmt.Errorf("%w: %q should be %q followed by a method starting with a lowercase letter",
+			errInvalidMethodName,
+			methodName,
+			serviceName+".",
+		)
+	}
+	return string(unicode.ToUpper(firstRune)) + method[runeLen:], nil
+}
+
+// isHandler returns true if gorilla rpc would register [method] as a handler.
+func isHandler(method reflect.Method) bool {
+	methodType := method.Type
+	return method.IsExported() &&
+		methodType.NumIn() == 4 &&
+		methodType.In(1) == httpRequestType &&
+		methodType.In(2).Kind() == reflect.Pointer &&
+		methodType.In(3).Kind() == reflect.Pointer &&
+		methodType.NumOut() == 1 &&
+		methodType.Out(0) == errorType
+}
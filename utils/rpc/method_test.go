@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testArgs struct {
+	Value int
+}
+
+type testReply struct {
+	Value int
+}
+
+type testService struct{}
+
+func (*testService) Echo(_ *http.Request, args *testArgs, reply *testReply) error {
+	reply.Value = args.Value
+	return nil
+}
+
+func (*testService) Ping(*http.Request, *struct{}, *struct{}) error {
+	return nil
+}
+
+// NotAHandler isn't registered by gorilla rpc, so it isn't a method.
+func (*testService) NotAHandler() {}
+
+type testRequester struct{}
+
+func (testRequester) SendRequest(_ context.Context, method string, params interface{}, reply interface{}, _ ...Option) error {
+	if method != "test.echo" {
+		return errMissingHandler
+	}
+	return (&testService{}).Echo(nil, params.(*testArgs), reply.(*testReply))
+}
+
+func TestMethodCall(t *testing.T) {
+	require := require.New(t)
+
+	echo := Method[testArgs, testReply]{Name: "test.echo"}
+	reply, err := echo.Call(context.Background(), testRequester{}, &testArgs{Value: 5})
+	require.NoError(err)
+	require.Equal(5, reply.Value)
+}
+
+func TestVerifyService(t *testing.T) {
+	var (
+		echo = Method[testArgs, testReply]{Name: "test.echo"}
+		ping = Method[struct{}, struct{}]{Name: "test.ping"}
+	)
+	tests := []struct {
+		name        string
+		serviceName string
+		methods     []Describer
+		expectedErr error
+	}{
+		{
+			name:        "valid",
+			serviceName: "test",
+			methods:     []Describer{echo, ping},
+		},
+		{
+			name:        "wrong service",
+			serviceName: "other",
+			methods:     []Describer{echo, ping},
+			expectedErr: errInvalidMethodName,
+		},
+		{
+			name:        "uppercase method",
+			serviceName: "test",
+			methods:     []Describer{echo, Method[struct{}, struct{}]{Name: "test.Ping"}},
+			expectedErr: errInvalidMethodName,
+		},
+		{
+			name:        "missing handler",
+			serviceName: "test",
+			methods:     []Describer{echo, ping, Method[struct{}, struct{}]{Name: "test.pong"}},
+			expectedErr: errMissingHandler,
+		},
+		{
+			name:        "wrong args",
+			serviceName: "test",
+			methods:     []Describer{echo, Method[testArgs, struct{}]{Name: "test.ping"}},
+			expectedErr: errWrongHandlerType,
+		},
+		{
+			name:        "wrong reply",
+			serviceName: "test",
+			methods:     []Describer{Method[testArgs, testArgs]{Name: "test.echo"}, ping},
+			expectedErr: errWrongHandlerType,
+		},
+		{
+			name:        "not a handler",
+			serviceName: "test",
+			methods:     []Describer{echo, ping, Method[struct{}, struct{}]{Name: "test.notAHandler"}},
+			expectedErr: errWrongHandlerType,
+		},
+		{
+			name:        "undeclared handler",
+			serviceName: "test",
+			methods:     []Describer{echo},
+			expectedErr: errUndeclaredHandler,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyService(tt.serviceName, &testService{}, tt.methods...)
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package set
+
+import (
+	"errors"
+	"fmt"
+
+	stdjson "encoding/json"
+	stdmath "math"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	_ stdjson.Marshaler = (*OrderedSet[int])(nil)
+
+	errDuplicateElement     = errors.New("duplicate element")
+	errInvalidLength        = errors.New("invalid length")
+	errInvalidSize          = errors.New("invalid element size")
+	errInvalidElementLength = errors.New("invalid element length")
+)
+
+// OrderedSet is a set of elements that iterates over its elements in the order
+// they were first added.
+//
+// Unlike Set, the order of the elements returned by List and MarshalJSON is
+// deterministic, so an OrderedSet can be used wherever iteration order is
+// observable, such as when hashing or serializing the set.
+type OrderedSet[T comparable] struct {
+	// indices maps the element in the set to the index that it appears in
+	// elements.
+	indices  map[T]int
+	elements []T
+}
+
+// OfOrdered returns an OrderedSet initialized with [elts] in the provided
+// order.
+func OfOrdered[T comparable](elts ...T) OrderedSet[T] {
+	s := NewOrderedSet[T](len(elts))
+	s.Add(elts...)
+	return s
+}
+
+// Sorted returns an OrderedSet containing the elements of [s] in sorted order.
+func Sorted[T interface {
+	comparable
+	utils.Sortable[T]
+}](s Set[T]) OrderedSet[T] {
+	elts := s.List()
+	utils.Sort(elts)
+	return OfOrdered(elts...)
+}
+
+// Return a new ordered set with initial capacity [size].
+// More or less than [size] elements can be added to this set.
+// Using NewOrderedSet() rather than OrderedSet[T]{} is just an optimization
+// that can be used if you know how many elements will be put in this set.
+func NewOrderedSet[T comparable](size int) OrderedSet[T] {
+	if size < 0 {
+		return OrderedSet[T]{}
+	}
+	return OrderedSet[T]{
+		indices:  make(map[T]int, size),
+		elements: make([]T, 0, size),
+	}
+}
+
+// Add all the elements to this set.
+// If the element is already in the set, its position is unchanged.
+func (s *OrderedSet[T]) Add(elts ...T) {
+	s.resize(2 * len(elts))
+	for _, elt := range elts {
+		s.add(elt)
+	}
+}
+
+// Union adds all the elements from the provided set to this set, in the order
+// they appear in the provided set.
+func (s *OrderedSet[T]) Union(set OrderedSet[T]) {
+	s.resize(2 * set.Len())
+	for _, elt := range set.elements {
+		s.add(elt)
+	}
+}
+
+// Difference removes all the elements in [set] from [s].
+func (s *OrderedSet[T]) Difference(set OrderedSet[T]) {
+	s.Remove(set.elements...)
+}
+
+// Contains returns true iff the set contains this element.
+func (s OrderedSet[T]) Contains(elt T) bool {
+	_, contains := s.indices[elt]
+	return contains
+}
+
+// Len returns the number of elements in this set.
+func (s OrderedSet[_]) Len() int {
+	return len(s.elements)
+}
+
+// Remove all the given elements from this set.
+// If an element isn't in the set, it's ignored.
+// The order of the remaining elements is unchanged.
+func (s *OrderedSet[T]) Remove(elts ...T) {
+	var (
+		removed bool
+		// The smallest index that was removed. Only the indices of the
+		// elements after it need to be updated.
+		firstRemoved = len(s.elements)
+	)
+	for _, elt := range elts {
+		index, ok := s.indices[elt]
+		if !ok {
+			continue
+		}
+		delete(s.indices, elt)
+		removed = true
+		firstRemoved = math.Min(firstRemoved, index)
+	}
+	if !removed {
+		return
+	}
+
+	remaining := s.elements[:firstRemoved]
+	for _, elt := range s.elements[firstRemoved:] {
+		if _, ok := s.indices[elt]; !ok {
+			continue
+		}
+		s.indices[elt] = len(remaining)
+		remaining = append(remaining, elt)
+	}
+	for i := len(remaining); i < len(s.elements); i++ {
+		s.elements[i] = utils.Zero[T]()
+	}
+	s.elements = remaining
+}
+
+// Clear empties this set
+func (s *OrderedSet[T]) Clear() {
+	maps.Clear(s.indices)
+	for i := range s.elements {
+		s.elements[i] = utils.Zero[T]()
+	}
+	s.elements = s.elements[:0]
+}
+
+// List converts this set into a list, in the order the elements were added.
+func (s OrderedSet[T]) List() []T {
+	return slices.Clone(s.elements)
+}
+
+// Equals returns true if the sets contain the same elements in the same order.
+func (s OrderedSet[T]) Equals(other OrderedSet[T]) bool {
+	return slices.Equal(s.elements, other.elements)
+}
+
+// Set returns the elements of this set as an unordered Set.
+func (s OrderedSet[T]) Set() Set[T] {
+	return Of(s.elements...)
+}
+
+func (s *OrderedSet[T]) UnmarshalJSON(b []byte) error {
+	str := string(b)
+	if str == json.Null {
+		return nil
+	}
+	var elts []T
+	if err := stdjson.Unmarshal(b, &elts); err != nil {
+		return err
+	}
+	s.Clear()
+	s.Add(elts...)
+	return nil
+}
+
+// MarshalJSON returns the elements of the set as a JSON list, in the order the
+// elements were added.
+func (s *OrderedSet[T]) MarshalJSON() ([]byte, error) {
+	if len(s.elements) == 0 {
+		return []byte("[]"), nil
+	}
+	return stdjson.Marshal(s.elements)
+}
+
+func (s *OrderedSet[T]) resize(size int) {
+	if s.indices == nil {
+		if minSetSize > size {
+			size = minSetSize
+		}
+		s.indices = make(map[T]int, size)
+	}
+}
+
+func (s *OrderedSet[T]) add(elt T) {
+	if _, ok := s.indices[elt]; ok {
+		return
+	}
+
+	s.indices[elt] = len(s.elements)
+	s.elements = append(s.elements, elt)
+}
+
+// MarshalOrdered returns the binary encoding of [s]. The encoding is the number
+// of elements followed by each element, in the order they appear in [s].
+// [toBytes] must encode every element into exactly [size] bytes, which must be
+// positive.
+//
+// Two sets have the same encoding iff they contain the same elements in the
+// same order. Use Sorted to encode a Set canonically. For example:
+//
+//	MarshalOrdered(Sorted(s), ids.IDLen, func(id ids.ID) []byte { return id[:] })
+func MarshalOrdered[T comparable](s OrderedSet[T], size int, toBytes func(T) []byte) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %d", errInvalidSize, size)
+	}
+	if len(s.elements) > stdmath.MaxUint32 {
+		return nil, fmt.Errorf("%w: %d elements", errInvalidLength, len(s.elements))
+	}
+	length, err := encodedLen(len(s.elements), size)
+	if err != nil {
+		return nil, err
+	}
+
+	p := wrappers.Packer{
+		Bytes: make([]byte, length),
+	}
+	p.PackInt(uint32(len(s.elements)))
+	for _, elt := range s.elements {
+		eltBytes := toBytes(elt)
+		if len(eltBytes) != size {
+			return nil, fmt.Errorf("%w: %v encoded into %d bytes but expected %d", errInvalidElementLength, elt, len(eltBytes), size)
+		}
+		p.PackFixedBytes(eltBytes)
+	}
+	return p.Bytes, p.Err
+}
+
+// UnmarshalOrdered parses the encoding produced by MarshalOrdered.
+// [fromBytes] is called with the [size] byte encoding of each element, so
+// [size] must be positive.
+func UnmarshalOrdered[T comparable](b []byte, size int, fromBytes func([]byte) (T, error)) (OrderedSet[T], error) {
+	// A size of 0 would allow any number of elements to be encoded in a
+	// fixed number of bytes.
+	if size <= 0 {
+		return OrderedSet[T]{}, fmt.Errorf("%w: %d", errInvalidSize, size)
+	}
+
+	p := wrappers.Packer{Bytes: b}
+	numElts := int(p.UnpackInt())
+	if p.Err != nil {
+		return OrderedSet[T]{}, p.Err
+	}
+	// Verify the length before allocating to avoid large allocations from
+	// malformed input.
+	expectedLen, err := encodedLen(numElts, size)
+	if err != nil {
+		return OrderedSet[T]{}, err
+	}
+	if len(b) != expectedLen {
+		return OrderedSet[T]{}, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidLength, expectedLen, len(b))
+	}
+
+	s := NewOrderedSet[T](numElts)
+	for i := 0; i < numElts; i++ {
+		elt, err := fromBytes(p.UnpackFixedBytes(size))
+		if err != nil {
+			return OrderedSet[T]{}, err
+		}
+		if s.Contains(elt) {
+			return OrderedSet[T]{}, fmt.Errorf("%w: %v", errDuplicateElement, elt)
+		}
+		s.add(elt)
+	}
+	return s, nil
+}
+
+// encodedLen returns the length of the encoding of [numElts] elements of
+// [size] bytes each. Returns an error if the length doesn't fit in an int.
+func encodedLen(numElts, size int) (int, error) {
+	eltsLen, err := math.Mul64(uint64(numElts), uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	length, err := math.Add64(eltsLen, wrappers.IntLen)
+	if err != nil {
+		return 0, err
+	}
+	if length > stdmath.MaxInt {
+		return 0, math.ErrOverflow
+	}
+	return int(length), nil
+}
@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package set
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var errInvalidTestElement = errors.New("invalid test element")
+
+type testElement uint32
+
+func (e testElement) Less(other testElement) bool {
+	return e < other
+}
+
+func testElementBytes(e testElement) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(e))
+}
+
+func parseTestElement(b []byte) (testElement, error) {
+	if len(b) != wrappers.IntLen {
+		return 0, errInvalidTestElement
+	}
+	return testElement(binary.BigEndian.Uint32(b)), nil
+}
+
+func TestOrderedSet(t *testing.T) {
+	require := require.New(t)
+
+	s := OrderedSet[int]{}
+	s.Add(3, 1, 2, 1)
+	require.Equal(3, s.Len())
+	require.Equal([]int{3, 1, 2}, s.List())
+	require.True(s.Contains(1))
+
+	// Re-adding an element doesn't change its position
+	s.Add(3)
+	require.Equal([]int{3, 1, 2}, s.List())
+
+	// Removing elements preserves the order of the remaining elements
+	s.Add(4, 5)
+	s.Remove(1, 4, 6)
+	require.Equal([]int{3, 2, 5}, s.List())
+	require.False(s.Contains(1))
+	require.False(s.Contains(4))
+
+	s2 := OfOrdered(5, 7)
+	s.Union(s2)
+	require.Equal([]int{3, 2, 5, 7}, s.List())
+
+	s.Difference(OfOrdered(3))
+	require.Equal([]int{2, 5, 7}, s.List())
+	require.Equal(Of(2, 5, 7), s.Set())
+
+	require.True(s.Equals(OfOrdered(2, 5, 7)))
+	require.False(s.Equals(OfOrdered(7, 5, 2)))
+
+	s.Clear()
+	require.Zero(s.Len())
+	s.Add(1337)
+	require.Equal([]int{1337}, s.List())
+}
+
+func TestSorted(t *testing.T) {
+	require := require.New(t)
+
+	s := Of[testElement](5, 1, 4, 2, 3)
+	require.Equal([]testElement{1, 2, 3, 4, 5}, Sorted(s).List())
+}
+
+func TestOrderedSetMarshalJSON(t *testing.T) {
+	require := require.New(t)
+
+	s := OrderedSet[int]{}
+	asJSON, err := json.Marshal(&s)
+	require.NoError(err)
+	require.Equal("[]", string(asJSON))
+
+	s = OfOrdered(3, 1, 2)
+	asJSON, err = json.Marshal(&s)
+	require.NoError(err)
+	require.Equal("[3,1,2]", string(asJSON))
+
+	var parsed OrderedSet[int]
+	require.NoError(json.Unmarshal(asJSON, &parsed))
+	require.True(s.Equals(parsed))
+}
+
+func TestOrderedSetMarshalBinary(t *testing.T) {
+	tests := []struct {
+		name        string
+		bytes       []byte
+		expected    OrderedSet[testElement]
+		expectedErr error
+	}{
+		{
+			name:     "empty",
+			bytes:    []byte{0, 0, 0, 0},
+			expected: OrderedSet[testElement]{},
+		},
+		{
+			name: "ordered",
+			bytes: []byte{
+				0, 0, 0, 2,
+				0, 0, 0, 2,
+				0, 0, 0, 1,
+			},
+			expected: OfOrdered[testElement](2, 1),
+		},
+		{
+			name: "duplicate element",
+			bytes: []byte{
+				0, 0, 0, 2,
+				0, 0, 0, 1,
+				0, 0, 0, 1,
+			},
+			expectedErr: errDuplicateElement,
+		},
+		{
+			name: "trailing bytes",
+			bytes: []byte{
+				0, 0, 0, 1,
+				0, 0, 0, 1,
+				0,
+			},
+			expectedErr: errInvalidLength,
+		},
+		{
+			name: "missing bytes",
+			bytes: []byte{
+				0, 0, 0, 2,
+				0, 0, 0, 1,
+			},
+			expectedErr: errInvalidLength,
+		},
+		{
+			name:        "missing length",
+			bytes:       []byte{0},
+			expectedErr: wrappers.ErrInsufficientLength,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			s, err := UnmarshalOrdered(test.bytes, wrappers.IntLen, parseTestElement)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.True(test.expected.Equals(s))
+			bytes, err := MarshalOrdered(s, wrappers.IntLen, testElementBytes)
+			require.NoError(err)
+			require.Equal(test.bytes, bytes)
+		})
+	}
+}
+
+func TestUnmarshalOrderedInvalidSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		_, err := UnmarshalOrdered([]byte{0xff, 0xff, 0xff, 0xff}, size, parseTestElement)
+		require.ErrorIs(t, err, errInvalidSize)
+	}
+}
+
+func TestUnmarshalOrderedLengthOverflow(t *testing.T) {
+	_, err := UnmarshalOrdered([]byte{0xff, 0xff, 0xff, 0xff}, math.MaxInt, parseTestElement)
+	require.ErrorIs(t, err, safemath.ErrOverflow)
+}
+
+func TestMarshalOrderedInvalidSize(t *testing.T) {
+	s := OfOrdered[testElement](1, 2)
+	for _, size := range []int{0, -1} {
+		_, err := MarshalOrdered(s, size, testElementBytes)
+		require.ErrorIs(t, err, errInvalidSize)
+	}
+}
+
+func TestMarshalOrderedInvalidElementLength(t *testing.T) {
+	s := OfOrdered[testElement](1, 2)
+	_, err := MarshalOrdered(s, wrappers.LongLen, testElementBytes)
+	require.ErrorIs(t, err, errInvalidElementLength)
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package validate provides helpers to verify configs and report every
+// problem with a config at once.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	_ error = (*Error)(nil)
+
+	ErrInvalidConfig = errors.New("invalid config")
+	ErrOutOfRange    = errors.New("out of range")
+)
+
+// Error is returned when a config fails verification. It reports all of the
+// problems that were found.
+type Error struct {
+	// Name of the config that failed verification.
+	Name string
+	// Problems found with the config, in the order they were checked.
+	Errs []error
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid %s config: %s", e.Name, strings.Join(msgs, "; "))
+}
+
+// Is allows errors.Is(err, ErrInvalidConfig) to match any *Error.
+func (e *Error) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+func (e *Error) Unwrap() []error {
+	return e.Errs
+}
+
+// Verifier accumulates the problems found while verifying a config.
+//
+// Verifier is not thread safe.
+type Verifier struct {
+	name string
+	errs []error
+}
+
+// New returns a verifier for the config named [name].
+func New(name string) *Verifier {
+	return &Verifier{name: name}
+}
+
+// Add records [err] if it is non-nil.
+func (v *Verifier) Add(err error) {
+	if err != nil {
+		v.errs = append(v.errs, err)
+	}
+}
+
+// Check records [err] if [ok] is false.
+func (v *Verifier) Check(ok bool, err error) {
+	if !ok {
+		v.errs = append(v.errs, err)
+	}
+}
+
+// Checkf records an error formatted from [format] and [args] if [ok] is false.
+func (v *Verifier) Checkf(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, args...))
+	}
+}
+
+// Err returns an *Error reporting every recorded problem, or nil if no
+// problem was recorded.
+func (v *Verifier) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &Error{
+		Name: v.name,
+		Errs: v.errs,
+	}
+}
+
+// AtLeast records an error if [value] of [field] is less than [min].
+func AtLeast[T constraints.Integer | constraints.Float](v *Verifier, field string, value, min T) {
+	if value < min {
+		v.errs = append(v.errs, fmt.Errorf("%w: %s must be >= %v but was %v", ErrOutOfRange, field, min, value))
+	}
+}
+
+// AtMost records an error if [value] of [field] is greater than [max].
+func AtMost[T constraints.Integer | constraints.Float](v *Verifier, field string, value, max T) {
+	if value > max {
+		v.errs = append(v.errs, fmt.Errorf("%w: %s must be <= %v but was %v", ErrOutOfRange, field, max, value))
+	}
+}
+
+// InRange records an error if [value] of [field] isn't in [min, max].
+func InRange[T constraints.Integer | constraints.Float](v *Verifier, field string, value, min, max T) {
+	if value < min || value > max {
+		v.errs = append(v.errs, fmt.Errorf("%w: %s must be in [%v, %v] but was %v", ErrOutOfRange, field, min, max, value))
+	}
+}
+
+// Default sets [field] to [value] if [field] is the zero value.
+func Default[T comparable](field *T, value T) {
+	var zero T
+	if *field == zero {
+		*field = value
+	}
+}
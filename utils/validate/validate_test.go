@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = errors.New("non-nil error")
+
+func TestVerifier(t *testing.T) {
+	require := require.New(t)
+
+	v := New("test")
+	AtLeast(v, "a", 1, 1)
+	AtMost(v, "b", 1, 1)
+	InRange(v, "c", 1, 0, 2)
+	v.Check(true, errTest)
+	v.Checkf(true, "unexpected")
+	v.Add(nil)
+	require.NoError(v.Err())
+
+	AtLeast(v, "a", 0, 1)
+	AtMost(v, "b", 2.5, 1)
+	InRange(v, "c", 3, 0, 2)
+	v.Check(false, errTest)
+	v.Checkf(false, "d (%d) must be less than e (%d)", 2, 1)
+
+	err := v.Err()
+	require.ErrorIs(err, ErrInvalidConfig)
+	require.ErrorIs(err, ErrOutOfRange)
+	require.ErrorIs(err, errTest)
+	require.EqualError(err, "invalid test config: "+
+		"out of range: a must be >= 1 but was 0; "+
+		"out of range: b must be <= 1 but was 2.5; "+
+		"out of range: c must be in [0, 2] but was 3; "+
+		"non-nil error; "+
+		"d (2) must be less than e (1)",
+	)
+}
+
+func TestDefault(t *testing.T) {
+	require := require.New(t)
+
+	var value int
+	Default(&value, 1)
+	require.Equal(1, value)
+
+	Default(&value, 2)
+	require.Equal(1, value)
+}
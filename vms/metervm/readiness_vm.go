@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metervm
+
+import "context"
+
+func (vm *blockVM) ReadyToBuild(ctx context.Context) bool {
+	if vm.readinessVM == nil {
+		return true
+	}
+
+	return vm.readinessVM.ReadyToBuild(ctx)
+}
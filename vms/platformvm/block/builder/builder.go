@@ -14,6 +14,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/units"
@@ -37,6 +38,7 @@ var (
 	ErrEndOfTime       = errors.New("program time is suspiciously far in the future")
 	ErrNoPendingBlocks = errors.New("no pending blocks")
 	ErrChainNotSynced  = errors.New("chain not synced")
+	ErrBatchRejected   = errors.New("another tx in the batch was rejected")
 )
 
 type Builder interface {
@@ -53,6 +55,11 @@ type Builder interface {
 	// AddUnverifiedTx verifier the tx before adding it to mempool
 	AddUnverifiedTx(tx *txs.Tx) error
 
+	// AddUnverifiedTxs verifies the txs before adding them to mempool.
+	// Returns the result of adding each tx.
+	// If [atomic] is true, either every tx is added or none are.
+	AddUnverifiedTxs(txs []*txs.Tx, atomic bool) []error
+
 	// BuildBlock is called on timer clock to attempt to create
 	// next block
 	BuildBlock(context.Context) (snowman.Block, error)
@@ -129,13 +136,157 @@ func (b *builder) AddUnverifiedTx(tx *txs.Tx) error {
 		return ErrChainNotSynced
 	}
 
-	txID := tx.ID()
-	if b.Mempool.Has(txID) {
+	if b.Mempool.Has(tx.ID()) {
 		// If the transaction is already in the mempool - then it looks the same
 		// as if it was successfully added
 		return nil
 	}
 
+	if err := b.verifyTx(tx); err != nil {
+		return err
+	}
+	if err := b.addTx(tx); err != nil {
+		return err
+	}
+	return b.GossipTx(tx)
+}
+
+// AddUnverifiedTxs verifies the transactions and attempts to add them to the
+// mempool.
+//
+// If [atomic] is true, the new txs are verified in order on top of the
+// preferred block as if they were issued in the same block, so a tx may depend
+// on the txs before it. If any tx fails verification or can't be added to the
+// mempool, none of the txs are added and every tx that didn't fail is
+// reported as [ErrBatchRejected]. Txs that were already in the mempool are
+// left there.
+func (b *builder) AddUnverifiedTxs(batch []*txs.Tx, atomic bool) []error {
+	errs := make([]error, len(batch))
+	if !b.txExecutorBackend.Bootstrapped.Get() {
+		for i := range errs {
+			errs[i] = ErrChainNotSynced
+		}
+		return errs
+	}
+
+	// Every tx is verified against the preferred block before any tx is added
+	// so that an atomic batch is rejected before the mempool is modified.
+	var (
+		isNew    = make([]bool, len(batch))
+		newTxs   []*txs.Tx
+		newTxIDs = set.Set[ids.ID]{}
+	)
+	for i, tx := range batch {
+		txID := tx.ID()
+		if b.Mempool.Has(txID) {
+			// If the transaction is already in the mempool - then it looks the
+			// same as if it was successfully added
+			continue
+		}
+
+		isNew[i] = true
+		if newTxIDs.Contains(txID) {
+			// The same tx may be included in the batch multiple times.
+			continue
+		}
+		newTxIDs.Add(txID)
+		newTxs = append(newTxs, tx)
+	}
+
+	if atomic {
+		if err := b.verifyBatch(batch, newTxs, isNew, errs); err != nil {
+			rejectBatch(errs, isNew)
+			return errs
+		}
+	} else {
+		for i, tx := range batch {
+			if isNew[i] {
+				errs[i] = b.verifyTx(tx)
+			}
+		}
+	}
+
+	var added []*txs.Tx
+	for i, tx := range batch {
+		// The same tx may be included in the batch multiple times.
+		if !isNew[i] || errs[i] != nil || b.Mempool.Has(tx.ID()) {
+			continue
+		}
+
+		if err := b.addTx(tx); err != nil {
+			errs[i] = err
+			if atomic {
+				b.Mempool.Remove(added)
+				rejectBatch(errs, isNew)
+				return errs
+			}
+			continue
+		}
+		added = append(added, tx)
+	}
+
+	for i, tx := range batch {
+		if isNew[i] && errs[i] == nil {
+			errs[i] = b.GossipTx(tx)
+		}
+	}
+	return errs
+}
+
+// rejectBatch marks every new tx that didn't fail as rejected.
+func rejectBatch(errs []error, isNew []bool) {
+	for i, err := range errs {
+		if isNew[i] && err == nil {
+			errs[i] = ErrBatchRejected
+		}
+	}
+}
+
+// verifyBatch verifies [newTxs], the new txs of [batch], in order on top of the
+// preferred block. If verification fails, the error is reported in [errs] for
+// every occurrence of the tx that failed, which is marked as dropped unless it
+// only starts staking too far in the future. If the batch couldn't be verified
+// for a reason unrelated to its txs, the error is reported for every new tx
+// and none is marked as dropped.
+func (b *builder) verifyBatch(batch []*txs.Tx, newTxs []*txs.Tx, isNew []bool, errs []error) error {
+	if len(newTxs) == 0 {
+		return nil
+	}
+
+	failedIndex, err := txexecutor.VerifyMempoolTxs(
+		b.txExecutorBackend,
+		b.preferredBlockID,
+		b.blkManager,
+		newTxs,
+	)
+	if err == nil {
+		return nil
+	}
+
+	if failedIndex < 0 {
+		for i := range batch {
+			if isNew[i] {
+				errs[i] = err
+			}
+		}
+		return err
+	}
+
+	failedTxID := newTxs[failedIndex].ID()
+	if !errors.Is(err, txexecutor.ErrFutureStakeTime) {
+		b.MarkDropped(failedTxID, err)
+	}
+	for i, tx := range batch {
+		if isNew[i] && tx.ID() == failedTxID {
+			errs[i] = err
+		}
+	}
+	return err
+}
+
+// verifyTx verifies [tx] on top of the preferred block. If verification
+// fails, [tx] is marked as dropped.
+func (b *builder) verifyTx(tx *txs.Tx) error {
 	verifier := txexecutor.MempoolTxVerifier{
 		Backend:       b.txExecutorBackend,
 		ParentID:      b.preferredBlockID, // We want to build off of the preferred block
@@ -143,18 +294,20 @@ func (b *builder) AddUnverifiedTx(tx *txs.Tx) error {
 		Tx:            tx,
 	}
 	if err := tx.Unsigned.Visit(&verifier); err != nil {
-		b.MarkDropped(txID, err)
+		b.MarkDropped(tx.ID(), err)
 		return err
 	}
+	return nil
+}
 
+// addTx adds the verified [tx] to the mempool.
+func (b *builder) addTx(tx *txs.Tx) error {
 	// If we are partially syncing the Primary Network, we should not be
 	// maintaining the transaction mempool locally.
-	if !b.txExecutorBackend.Config.PartialSyncPrimaryNetwork {
-		if err := b.Mempool.Add(tx); err != nil {
-			return err
-		}
+	if b.txExecutorBackend.Config.PartialSyncPrimaryNetwork {
+		return nil
 	}
-	return b.GossipTx(tx)
+	return b.Mempool.Add(tx)
 }
 
 // BuildBlock builds a block to be added to consensus.
@@ -13,6 +13,7 @@ import (
 
 	"go.uber.org/mock/gomock"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
@@ -21,6 +22,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs/mempool"
@@ -66,6 +68,214 @@ func TestBlockBuilderAddLocalTx(t *testing.T) {
 	require.False(env.mempool.Has(txID))
 }
 
+func TestBlockBuilderAddUnverifiedTxs(t *testing.T) {
+	tests := []struct {
+		name            string
+		atomic          bool
+		invalid         bool
+		future          bool
+		expectedErrs    []error
+		expectedAdded   []bool
+		expectedDropped []bool
+	}{
+		{
+			name:            "non-atomic",
+			expectedErrs:    []error{nil, nil},
+			expectedAdded:   []bool{true, true},
+			expectedDropped: []bool{false, false},
+		},
+		{
+			name:            "atomic",
+			atomic:          true,
+			expectedErrs:    []error{nil, nil},
+			expectedAdded:   []bool{true, true},
+			expectedDropped: []bool{false, false},
+		},
+		{
+			name:            "non-atomic with invalid tx",
+			invalid:         true,
+			expectedErrs:    []error{nil, txexecutor.ErrTimestampNotBeforeStartTime},
+			expectedAdded:   []bool{true, false},
+			expectedDropped: []bool{false, true},
+		},
+		{
+			name:            "atomic with invalid tx",
+			atomic:          true,
+			invalid:         true,
+			expectedErrs:    []error{ErrBatchRejected, txexecutor.ErrTimestampNotBeforeStartTime},
+			expectedAdded:   []bool{false, false},
+			expectedDropped: []bool{false, true},
+		},
+		{
+			name:            "non-atomic with future tx",
+			future:          true,
+			expectedErrs:    []error{nil, nil},
+			expectedAdded:   []bool{true, true},
+			expectedDropped: []bool{false, false},
+		},
+		{
+			// The changes of a tx that starts too far in the future aren't
+			// applied, so the txs after it can't be verified.
+			name:            "atomic with future tx",
+			atomic:          true,
+			future:          true,
+			expectedErrs:    []error{ErrBatchRejected, txexecutor.ErrFutureStakeTime},
+			expectedAdded:   []bool{false, false},
+			expectedDropped: []bool{false, false},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			env := newEnvironment(t)
+			env.ctx.Lock.Lock()
+			defer func() {
+				require.NoError(shutdownEnvironment(env))
+			}()
+
+			env.sender.SendAppGossipF = func(context.Context, []byte) error {
+				return nil
+			}
+
+			// A validator can't start at the current chain time.
+			startTime := env.state.GetTimestamp().Add(time.Second)
+			switch {
+			case test.invalid:
+				startTime = env.state.GetTimestamp()
+			case test.future:
+				startTime = env.state.GetTimestamp().Add(txexecutor.MaxFutureStartTime + time.Second)
+			}
+			addValidatorTx, err := env.txBuilder.NewAddValidatorTx(
+				env.config.MinValidatorStake,
+				uint64(startTime.Unix()),
+				uint64(startTime.Add(defaultMinStakingDuration).Unix()),
+				ids.GenerateTestNodeID(),
+				preFundedKeys[2].PublicKey().Address(),
+				reward.PercentDenominator,
+				[]*secp256k1.PrivateKey{preFundedKeys[2]},
+				ids.ShortEmpty,
+			)
+			require.NoError(err)
+
+			// The txs are funded by different keys so they don't conflict.
+			batch := []*txs.Tx{
+				getValidTx(env.txBuilder, t),
+				addValidatorTx,
+			}
+
+			errs := env.Builder.AddUnverifiedTxs(batch, test.atomic)
+			require.Len(errs, len(batch))
+			for i, tx := range batch {
+				require.ErrorIs(errs[i], test.expectedErrs[i])
+				require.Equal(test.expectedAdded[i], env.mempool.Has(tx.ID()))
+				require.Equal(test.expectedDropped[i], env.mempool.GetDropReason(tx.ID()) != nil)
+			}
+		})
+	}
+}
+
+func TestBlockBuilderAddUnverifiedTxsMissingState(t *testing.T) {
+	require := require.New(t)
+
+	env := newEnvironment(t)
+	env.ctx.Lock.Lock()
+	defer func() {
+		require.NoError(shutdownEnvironment(env))
+	}()
+
+	// The state of the preferred block can't be found, which has nothing to
+	// do with the txs.
+	env.Builder.SetPreference(ids.GenerateTestID())
+
+	batch := []*txs.Tx{
+		getValidTx(env.txBuilder, t),
+	}
+	errs := env.Builder.AddUnverifiedTxs(batch, true /*=atomic*/)
+	require.Len(errs, len(batch))
+	for i, tx := range batch {
+		require.ErrorIs(errs[i], state.ErrMissingParentState)
+		require.False(env.mempool.Has(tx.ID()))
+		require.NoError(env.mempool.GetDropReason(tx.ID()))
+	}
+}
+
+func TestBlockBuilderAddUnverifiedTxsDelegatorOfNewValidator(t *testing.T) {
+	tests := []struct {
+		name          string
+		atomic        bool
+		expectedErrs  []error
+		expectedAdded []bool
+	}{
+		{
+			name:          "non-atomic",
+			expectedErrs:  []error{nil, database.ErrNotFound},
+			expectedAdded: []bool{true, false},
+		},
+		{
+			name:          "atomic",
+			atomic:        true,
+			expectedErrs:  []error{nil, nil},
+			expectedAdded: []bool{true, true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			env := newEnvironment(t)
+			env.ctx.Lock.Lock()
+			defer func() {
+				require.NoError(shutdownEnvironment(env))
+			}()
+
+			env.sender.SendAppGossipF = func(context.Context, []byte) error {
+				return nil
+			}
+
+			var (
+				nodeID    = ids.GenerateTestNodeID()
+				startTime = env.state.GetTimestamp().Add(time.Second)
+				endTime   = startTime.Add(defaultMinStakingDuration)
+			)
+			addValidatorTx, err := env.txBuilder.NewAddValidatorTx(
+				env.config.MinValidatorStake,
+				uint64(startTime.Unix()),
+				uint64(endTime.Unix()),
+				nodeID,
+				preFundedKeys[2].PublicKey().Address(),
+				reward.PercentDenominator,
+				[]*secp256k1.PrivateKey{preFundedKeys[2]},
+				ids.ShortEmpty,
+			)
+			require.NoError(err)
+
+			// The delegator is only valid once the validator is added.
+			addDelegatorTx, err := env.txBuilder.NewAddDelegatorTx(
+				env.config.MinDelegatorStake,
+				uint64(startTime.Unix()),
+				uint64(endTime.Unix()),
+				nodeID,
+				preFundedKeys[3].PublicKey().Address(),
+				[]*secp256k1.PrivateKey{preFundedKeys[3]},
+				ids.ShortEmpty,
+			)
+			require.NoError(err)
+
+			batch := []*txs.Tx{
+				addValidatorTx,
+				addDelegatorTx,
+			}
+			errs := env.Builder.AddUnverifiedTxs(batch, test.atomic)
+			require.Len(errs, len(batch))
+			for i, tx := range batch {
+				require.ErrorIs(errs[i], test.expectedErrs[i])
+				require.Equal(test.expectedAdded[i], env.mempool.Has(tx.ID()))
+			}
+		})
+	}
+}
+
 func TestPreviouslyDroppedTxsCanBeReAddedToMempool(t *testing.T) {
 	require := require.New(t)
 
@@ -30,6 +30,7 @@ type acceptor struct {
 	metrics      metrics.Metrics
 	validators   validators.Manager
 	bootstrapped *utils.Atomic[bool]
+	chainTime    *chainTimeTracker
 }
 
 func (a *acceptor) BanffAbortBlock(b *block.BanffAbortBlock) error {
@@ -42,11 +43,16 @@ func (a *acceptor) BanffCommitBlock(b *block.BanffCommitBlock) error {
 
 func (a *acceptor) BanffProposalBlock(b *block.BanffProposalBlock) error {
 	a.proposalBlock(b, "banff proposal")
+	a.observeChainTime(b)
 	return nil
 }
 
 func (a *acceptor) BanffStandardBlock(b *block.BanffStandardBlock) error {
-	return a.standardBlock(b, "banff standard")
+	if err := a.standardBlock(b, "banff standard"); err != nil {
+		return err
+	}
+	a.observeChainTime(b)
+	return nil
 }
 
 func (a *acceptor) ApricotAbortBlock(b *block.ApricotAbortBlock) error {
@@ -275,6 +281,15 @@ func (a *acceptor) standardBlock(b block.Block, blockType string) error {
 	return nil
 }
 
+// observeChainTime records the timestamp of [b] once the node is
+// bootstrapped. Option blocks aren't recorded, because their timestamps are
+// their parents'.
+func (a *acceptor) observeChainTime(b block.BanffBlock) {
+	if a.bootstrapped.Get() {
+		a.chainTime.observe(b.Height(), b.Timestamp())
+	}
+}
+
 func (a *acceptor) commonAccept(b block.Block) error {
 	blkID := b.ID()
 
@@ -175,9 +175,12 @@ func TestAcceptorVisitStandardBlock(t *testing.T) {
 				SharedMemory: sharedMemory,
 			},
 		},
-		metrics:    metrics.Noop,
-		validators: validators.TestManager,
+		metrics:      metrics.Noop,
+		validators:   validators.TestManager,
+		bootstrapped: &utils.Atomic[bool]{},
+		chainTime:    newChainTimeTracker(clk),
 	}
+	acceptor.bootstrapped.Set(true)
 
 	blk, err := block.NewBanffStandardBlock(
 		clk.Time(),
@@ -245,6 +248,7 @@ func TestAcceptorVisitStandardBlock(t *testing.T) {
 	require.NoError(acceptor.BanffStandardBlock(blk))
 	require.True(calledOnAcceptFunc)
 	require.Equal(blk.ID(), acceptor.backend.lastAccepted)
+	require.Equal(1, acceptor.chainTime.stats().Samples)
 }
 
 func TestAcceptorVisitCommitBlock(t *testing.T) {
@@ -0,0 +1,108 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"math"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/buffer"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const (
+	// chainTimeWindowSize is the number of recently accepted blocks that chain
+	// time estimates are based on.
+	chainTimeWindowSize = 128
+
+	// chainTimeConfidenceZ is the z-score of a 95% confidence interval.
+	chainTimeConfidenceZ = 1.96
+)
+
+// ChainTimeStats are estimates of how the chain time relates to the local
+// clock, based on recently accepted blocks.
+type ChainTimeStats struct {
+	// Samples is the number of recently accepted blocks the estimates are
+	// based on.
+	Samples int
+	// Skew is the estimated amount of time that the local clock is ahead of
+	// the clocks of the nodes that proposed recent blocks. It includes the
+	// time it took to accept the blocks, so it's an upper bound.
+	Skew time.Duration
+	// SkewMargin is the half-width of the 95% confidence interval of [Skew].
+	SkewMargin time.Duration
+	// BlockInterval is the mean chain time between consecutive blocks, or 0
+	// if there aren't enough samples to estimate it.
+	BlockInterval time.Duration
+}
+
+type chainTimeSample struct {
+	height    uint64
+	timestamp time.Time
+	// skew is the local time when the block was accepted minus its timestamp
+	skew time.Duration
+}
+
+// chainTimeTracker keeps the timestamps of the most recently accepted blocks
+// that were proposed with the proposer's local time.
+type chainTimeTracker struct {
+	clock   *mockable.Clock
+	samples buffer.Deque[chainTimeSample]
+}
+
+func newChainTimeTracker(clock *mockable.Clock) *chainTimeTracker {
+	return &chainTimeTracker{
+		clock:   clock,
+		samples: buffer.NewUnboundedDeque[chainTimeSample](chainTimeWindowSize + 1),
+	}
+}
+
+// observe records that the block at [height] with [timestamp] was just
+// accepted.
+func (c *chainTimeTracker) observe(height uint64, timestamp time.Time) {
+	c.samples.PushRight(chainTimeSample{
+		height:    height,
+		timestamp: timestamp,
+		skew:      c.clock.Time().Sub(timestamp),
+	})
+	if c.samples.Len() > chainTimeWindowSize {
+		_, _ = c.samples.PopLeft()
+	}
+}
+
+func (c *chainTimeTracker) stats() ChainTimeStats {
+	samples := c.samples.List()
+	stats := ChainTimeStats{
+		Samples: len(samples),
+	}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample.skew)
+	}
+	n := float64(len(samples))
+	mean := sum / n
+	stats.Skew = time.Duration(mean)
+
+	if len(samples) > 1 {
+		var squaredDiffs float64
+		for _, sample := range samples {
+			diff := float64(sample.skew) - mean
+			squaredDiffs += diff * diff
+		}
+		stdDev := math.Sqrt(squaredDiffs / (n - 1))
+		stats.SkewMargin = time.Duration(chainTimeConfidenceZ * stdDev / math.Sqrt(n))
+	}
+
+	first := samples[0]
+	last := samples[len(samples)-1]
+	if last.height > first.height && last.timestamp.After(first.timestamp) {
+		blocks := last.height - first.height
+		stats.BlockInterval = last.timestamp.Sub(first.timestamp) / time.Duration(blocks)
+	}
+	return stats
+}
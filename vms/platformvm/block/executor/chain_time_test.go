@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+func TestChainTimeTracker(t *testing.T) {
+	require := require.New(t)
+
+	clk := &mockable.Clock{}
+	start := time.Unix(1_000_000, 0)
+	clk.Set(start)
+	tracker := newChainTimeTracker(clk)
+
+	stats := tracker.stats()
+	require.Equal(ChainTimeStats{}, stats)
+
+	// One block every 2 seconds, accepted 1 or 3 seconds after its timestamp.
+	for i := 0; i < 4; i++ {
+		timestamp := start.Add(time.Duration(2*i) * time.Second)
+		delay := time.Second
+		if i%2 == 1 {
+			delay = 3 * time.Second
+		}
+		clk.Set(timestamp.Add(delay))
+		tracker.observe(uint64(10+i), timestamp)
+	}
+
+	stats = tracker.stats()
+	require.Equal(4, stats.Samples)
+	require.Equal(2*time.Second, stats.Skew)
+	// The sample standard deviation is 2/sqrt(3) seconds.
+	require.InDelta(float64(1960*time.Millisecond)/math.Sqrt(3), float64(stats.SkewMargin), float64(time.Microsecond))
+	require.Equal(2*time.Second, stats.BlockInterval)
+
+	// Only the most recent blocks are kept.
+	for i := 0; i < chainTimeWindowSize; i++ {
+		timestamp := start.Add(time.Hour + time.Duration(5*i)*time.Second)
+		clk.Set(timestamp)
+		tracker.observe(uint64(100+i), timestamp)
+	}
+
+	stats = tracker.stats()
+	require.Equal(chainTimeWindowSize, stats.Samples)
+	require.Zero(stats.Skew)
+	require.Zero(stats.SkewMargin)
+	require.Equal(5*time.Second, stats.BlockInterval)
+}
@@ -24,6 +24,10 @@ type Manager interface {
 	GetBlock(blkID ids.ID) (snowman.Block, error)
 	GetStatelessBlock(blkID ids.ID) (block.Block, error)
 	NewBlock(block.Block) snowman.Block
+
+	// ChainTimeStats returns estimates of how the chain time relates to the
+	// local clock, based on recently accepted blocks.
+	ChainTimeStats() ChainTimeStats
 }
 
 func NewManager(
@@ -40,9 +44,11 @@ func NewManager(
 		ctx:          txExecutorBackend.Ctx,
 		blkIDToState: map[ids.ID]*blockState{},
 	}
+	chainTime := newChainTimeTracker(txExecutorBackend.Clk)
 
 	return &manager{
-		backend: backend,
+		backend:   backend,
+		chainTime: chainTime,
 		verifier: &verifier{
 			backend:           backend,
 			txExecutorBackend: txExecutorBackend,
@@ -52,6 +58,7 @@ func NewManager(
 			metrics:      metrics,
 			validators:   validatorManager,
 			bootstrapped: txExecutorBackend.Bootstrapped,
+			chainTime:    chainTime,
 		},
 		rejector: &rejector{
 			backend:         backend,
@@ -62,9 +69,10 @@ func NewManager(
 
 type manager struct {
 	*backend
-	verifier block.Visitor
-	acceptor block.Visitor
-	rejector block.Visitor
+	chainTime *chainTimeTracker
+	verifier  block.Visitor
+	acceptor  block.Visitor
+	rejector  block.Visitor
 }
 
 func (m *manager) GetBlock(blkID ids.ID) (snowman.Block, error) {
@@ -85,3 +93,7 @@ func (m *manager) NewBlock(blk block.Block) snowman.Block {
 		Block:   blk,
 	}
 }
+
+func (m *manager) ChainTimeStats() ChainTimeStats {
+	return m.chainTime.stats()
+}
@@ -40,6 +40,20 @@ func (m *MockManager) EXPECT() *MockManagerMockRecorder {
 	return m.recorder
 }
 
+// ChainTimeStats mocks base method.
+func (m *MockManager) ChainTimeStats() ChainTimeStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainTimeStats")
+	ret0, _ := ret[0].(ChainTimeStats)
+	return ret0
+}
+
+// ChainTimeStats indicates an expected call of ChainTimeStats.
+func (mr *MockManagerMockRecorder) ChainTimeStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainTimeStats", reflect.TypeOf((*MockManager)(nil).ChainTimeStats))
+}
+
 // GetBlock mocks base method.
 func (m *MockManager) GetBlock(arg0 ids.ID) (snowman.Block, error) {
 	m.ctrl.T.Helper()
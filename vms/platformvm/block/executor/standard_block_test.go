@@ -139,6 +139,7 @@ func TestBanffStandardBlockTimeVerification(t *testing.T) {
 	pendingIt.EXPECT().Next().Return(false).AnyTimes()
 	pendingIt.EXPECT().Release().Return().AnyTimes()
 	onParentAccept.EXPECT().GetPendingStakerIterator().Return(pendingIt, nil).AnyTimes()
+	onParentAccept.EXPECT().GetFrozenValidatorIterator().Return(state.EmptyFrozenValidatorIterator, nil).AnyTimes()
 
 	onParentAccept.EXPECT().GetTimestamp().Return(chainTime).AnyTimes()
 
@@ -5,18 +5,27 @@ package platformvm
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	stdjson "encoding/json"
+
+	"github.com/gorilla/rpc/v2/json2"
+
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs/executor"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
 
 	platformapi "github.com/ava-labs/avalanchego/vms/platformvm/api"
 )
@@ -80,6 +89,15 @@ type Client interface {
 	GetPendingValidators(ctx context.Context, subnetID ids.ID, nodeIDs []ids.NodeID, options ...rpc.Option) ([]interface{}, []interface{}, error)
 	// GetCurrentSupply returns an upper bound on the supply of AVAX in the system along with the P-chain height
 	GetCurrentSupply(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error)
+	// GetSupplyAt returns the supply of the subnet once the block at [height]
+	// was accepted
+	GetSupplyAt(ctx context.Context, subnetID ids.ID, height uint64, options ...rpc.Option) (uint64, error)
+	// GetSupplyChanges returns the changes to the supply of the subnet made by
+	// the blocks with heights in [startHeight, endHeight]
+	GetSupplyChanges(ctx context.Context, subnetID ids.ID, startHeight, endHeight uint64, options ...rpc.Option) ([]SupplyChange, error)
+	// GetUptimeAttestation returns the node's observed uptimes of the current
+	// validators of the subnet, signed with the node's BLS key
+	GetUptimeAttestation(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*SignedUptimeAttestation, error)
 	// SampleValidators returns the nodeIDs of a sample of [sampleSize] validators from the current validator set for subnet with ID [subnetID]
 	SampleValidators(ctx context.Context, subnetID ids.ID, sampleSize uint16, options ...rpc.Option) ([]ids.NodeID, error)
 	// AddValidator issues a transaction to add a validator to the primary network
@@ -202,6 +220,16 @@ type Client interface {
 	GetBlockchains(ctx context.Context, options ...rpc.Option) ([]APIBlockchain, error)
 	// IssueTx issues the transaction and returns its txID
 	IssueTx(ctx context.Context, tx []byte, options ...rpc.Option) (ids.ID, error)
+	// IssueTxWithIdempotencyKey issues the transaction and returns its txID.
+	// If the transaction was recently issued with the same [idempotencyKey],
+	// it isn't reissued and the status of the transaction is also returned.
+	// Returns an error if [idempotencyKey] was recently used to issue a
+	// different transaction.
+	IssueTxWithIdempotencyKey(ctx context.Context, tx []byte, idempotencyKey string, options ...rpc.Option) (ids.ID, *status.Status, error)
+	// IssueTxs issues the transactions and returns the result of issuing each
+	// of them. If [atomic] is true, none of the transactions are issued unless
+	// every transaction is issued.
+	IssueTxs(ctx context.Context, txs [][]byte, atomic bool, options ...rpc.Option) ([]IssueTxResult, error)
 	// GetTx returns the byte representation of the transaction corresponding to [txID]
 	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
 	// GetTxStatus returns the status of the transaction corresponding to [txID]
@@ -227,6 +255,14 @@ type Client interface {
 		validatorsOnly bool,
 		options ...rpc.Option,
 	) (map[ids.ID]uint64, [][]byte, error)
+	// GetStakeSummary returns the stake of each of [addrs] on the Primary
+	// Network, along with the rewards that each address is expected to
+	// receive.
+	GetStakeSummary(
+		ctx context.Context,
+		addrs []ids.ShortID,
+		options ...rpc.Option,
+	) (map[ids.ShortID]*StakeSummary, error)
 	// GetMinStake returns the minimum staking amount in nAVAX for validators
 	// and delegators respectively
 	GetMinStake(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error)
@@ -249,8 +285,36 @@ type Client interface {
 	//
 	// Deprecated: GetRewardUTXOs should be fetched from a dedicated indexer.
 	GetRewardUTXOs(context.Context, *api.GetTxArgs, ...rpc.Option) ([][]byte, error)
+	// GetStaker returns the lifecycle of the staker added by [txID]
+	GetStaker(ctx context.Context, txID ids.ID, options ...rpc.Option) (*GetStakerReply, error)
+	// GetOwners returns the owners of the rewards of the stakers added by
+	// [stakerTxIDs] and the owners of [utxoIDs]
+	GetOwners(
+		ctx context.Context,
+		stakerTxIDs []ids.ID,
+		utxoIDs []*avax.UTXOID,
+		options ...rpc.Option,
+	) (*GetOwnersReply, error)
+	// GetRewardHistory returns up to [limit] rewards paid to [address] or
+	// for the stakers of [nodeID], starting after [cursor]. Exactly one of
+	// [address] and [nodeID] must be provided.
+	GetRewardHistory(
+		ctx context.Context,
+		address ids.ShortID,
+		nodeID ids.NodeID,
+		cursor string,
+		limit uint32,
+		options ...rpc.Option,
+	) (*GetRewardHistoryReply, error)
 	// GetTimestamp returns the current chain timestamp
 	GetTimestamp(ctx context.Context, options ...rpc.Option) (time.Time, error)
+	// GetChainTime returns the current chain timestamp along with estimates of
+	// the local clock's skew and of when the next staker change happens
+	GetChainTime(ctx context.Context, options ...rpc.Option) (*GetChainTimeReply, error)
+	// CheckForks compares the P-chain of up to [numPeers] sampled primary
+	// network validators with the node's, and reports the first height at
+	// which each validator diverged
+	CheckForks(ctx context.Context, numPeers uint32, options ...rpc.Option) (*CheckForksReply, error)
 	// GetValidatorsAt returns the weights of the validator set of a provided
 	// subnet at the specified height.
 	GetValidatorsAt(
@@ -259,6 +323,15 @@ type Client interface {
 		height uint64,
 		options ...rpc.Option,
 	) (map[ids.NodeID]*validators.GetValidatorOutput, error)
+	// GetCometBFTValidatorSet returns the validator set of a subnet in the
+	// format used by CometBFT light clients. If [height] is nil, the current
+	// validator set is returned.
+	GetCometBFTValidatorSet(
+		ctx context.Context,
+		subnetID ids.ID,
+		height *uint64,
+		options ...rpc.Option,
+	) (*GetCometBFTValidatorSetReply, error)
 	// GetBlock returns the block with the given id.
 	GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error)
 	// GetBlockByHeight returns the block at the given [height].
@@ -463,6 +536,75 @@ func (c *client) GetCurrentSupply(ctx context.Context, subnetID ids.ID, options
 	return uint64(res.Supply), uint64(res.Height), err
 }
 
+func (c *client) GetSupplyAt(ctx context.Context, subnetID ids.ID, height uint64, options ...rpc.Option) (uint64, error) {
+	res := &GetSupplyAtReply{}
+	err := c.requester.SendRequest(ctx, "platform.getSupplyAt", &GetSupplyAtArgs{
+		SubnetID: subnetID,
+		Height:   json.Uint64(height),
+	}, res, options...)
+	return uint64(res.Supply), err
+}
+
+func (c *client) GetSupplyChanges(ctx context.Context, subnetID ids.ID, startHeight, endHeight uint64, options ...rpc.Option) ([]SupplyChange, error) {
+	res := &GetSupplyChangesReply{}
+	err := c.requester.SendRequest(ctx, "platform.getSupplyChanges", &GetSupplyChangesArgs{
+		SubnetID:    subnetID,
+		StartHeight: json.Uint64(startHeight),
+		EndHeight:   json.Uint64(endHeight),
+	}, res, options...)
+	return res.Changes, err
+}
+
+func (c *client) GetUptimeAttestation(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*SignedUptimeAttestation, error) {
+	res := &GetUptimeAttestationReply{}
+	err := c.requester.SendRequest(ctx, "platform.getUptimeAttestation", &GetUptimeAttestationArgs{
+		SubnetID: subnetID,
+	}, res, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	attestationBytes, err := formatting.Decode(res.Encoding, res.Attestation)
+	if err != nil {
+		return nil, err
+	}
+	attestation, err := ParseUptimeAttestation(attestationBytes)
+	if err != nil {
+		return nil, err
+	}
+	msgBytes, err := formatting.Decode(res.Encoding, res.UnsignedMessage)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := warp.ParseUnsignedMessage(msgBytes)
+	if err != nil {
+		return nil, err
+	}
+	pkBytes, err := formatting.Decode(res.Encoding, res.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := bls.PublicKeyFromBytes(pkBytes)
+	if err != nil {
+		return nil, err
+	}
+	sigBytes, err := formatting.Decode(res.Encoding, res.Signature)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := bls.SignatureFromBytes(sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedUptimeAttestation{
+		Attestation: attestation,
+		Bytes:       attestationBytes,
+		Message:     msg,
+		PublicKey:   pk,
+		Signature:   sig,
+	}, nil
+}
+
 func (c *client) SampleValidators(ctx context.Context, subnetID ids.ID, sampleSize uint16, options ...rpc.Option) ([]ids.NodeID, error) {
 	res := &SampleValidatorsReply{}
 	err := c.requester.SendRequest(ctx, "platform.sampleValidators", &SampleValidatorsArgs{
@@ -710,14 +852,52 @@ func (c *client) IssueTx(ctx context.Context, txBytes []byte, options ...rpc.Opt
 		return ids.ID{}, err
 	}
 
-	res := &api.JSONTxID{}
-	err = c.requester.SendRequest(ctx, "platform.issueTx", &api.FormattedTx{
-		Tx:       txStr,
-		Encoding: formatting.Hex,
+	res := &IssueTxReply{}
+	err = c.requester.SendRequest(ctx, "platform.issueTx", &IssueTxArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		},
 	}, res, options...)
 	return res.TxID, err
 }
 
+func (c *client) IssueTxWithIdempotencyKey(ctx context.Context, txBytes []byte, idempotencyKey string, options ...rpc.Option) (ids.ID, *status.Status, error) {
+	txStr, err := formatting.Encode(formatting.Hex, txBytes)
+	if err != nil {
+		return ids.ID{}, nil, err
+	}
+
+	res := &IssueTxReply{}
+	err = c.requester.SendRequest(ctx, "platform.issueTx", &IssueTxArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		},
+		IdempotencyKey: idempotencyKey,
+	}, res, options...)
+	return res.TxID, res.Status, err
+}
+
+func (c *client) IssueTxs(ctx context.Context, txs [][]byte, atomic bool, options ...rpc.Option) ([]IssueTxResult, error) {
+	txStrs := make([]string, len(txs))
+	for i, txBytes := range txs {
+		txStr, err := formatting.Encode(formatting.Hex, txBytes)
+		if err != nil {
+			return nil, err
+		}
+		txStrs[i] = txStr
+	}
+
+	res := &IssueTxsReply{}
+	err := c.requester.SendRequest(ctx, "platform.issueTxs", &IssueTxsArgs{
+		Txs:      txStrs,
+		Encoding: formatting.Hex,
+		Atomic:   atomic,
+	}, res, options...)
+	return res.Results, err
+}
+
 func (c *client) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error) {
 	res := &api.FormattedTx{}
 	err := c.requester.SendRequest(ctx, "platform.getTx", &api.GetTxArgs{
@@ -799,6 +979,29 @@ func (c *client) GetStake(
 	return staked, outputs, err
 }
 
+func (c *client) GetStakeSummary(
+	ctx context.Context,
+	addrs []ids.ShortID,
+	options ...rpc.Option,
+) (map[ids.ShortID]*StakeSummary, error) {
+	addrStrs := ids.ShortIDsToStrings(addrs)
+	res := &GetStakeSummaryReply{}
+	err := c.requester.SendRequest(ctx, "platform.getStakeSummary", &GetStakeSummaryArgs{
+		JSONAddresses: api.JSONAddresses{
+			Addresses: addrStrs,
+		},
+	}, res, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[ids.ShortID]*StakeSummary, len(addrs))
+	for i, addr := range addrs {
+		summaries[addr] = res.Summaries[addrStrs[i]]
+	}
+	return summaries, nil
+}
+
 func (c *client) GetMinStake(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (uint64, uint64, error) {
 	res := &GetMinStakeReply{}
 	err := c.requester.SendRequest(ctx, "platform.getMinStake", &GetMinStakeArgs{
@@ -849,12 +1052,73 @@ func (c *client) GetRewardUTXOs(ctx context.Context, args *api.GetTxArgs, option
 	return utxos, err
 }
 
+func (c *client) GetStaker(ctx context.Context, txID ids.ID, options ...rpc.Option) (*GetStakerReply, error) {
+	res := &GetStakerReply{}
+	err := c.requester.SendRequest(ctx, "platform.getStaker", &api.GetTxArgs{
+		TxID: txID,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetOwners(
+	ctx context.Context,
+	stakerTxIDs []ids.ID,
+	utxoIDs []*avax.UTXOID,
+	options ...rpc.Option,
+) (*GetOwnersReply, error) {
+	utxoIDStrs := make([]string, len(utxoIDs))
+	for i, utxoID := range utxoIDs {
+		utxoIDStrs[i] = utxoID.String()
+	}
+	res := &GetOwnersReply{}
+	err := c.requester.SendRequest(ctx, "platform.getOwners", &GetOwnersArgs{
+		StakerTxIDs: stakerTxIDs,
+		UTXOIDs:     utxoIDStrs,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetRewardHistory(
+	ctx context.Context,
+	address ids.ShortID,
+	nodeID ids.NodeID,
+	cursor string,
+	limit uint32,
+	options ...rpc.Option,
+) (*GetRewardHistoryReply, error) {
+	args := &GetRewardHistoryArgs{
+		NodeID: nodeID,
+		Cursor: cursor,
+		Limit:  json.Uint32(limit),
+	}
+	if address != ids.ShortEmpty {
+		args.Address = address.String()
+	}
+	res := &GetRewardHistoryReply{}
+	err := c.requester.SendRequest(ctx, "platform.getRewardHistory", args, res, options...)
+	return res, err
+}
+
 func (c *client) GetTimestamp(ctx context.Context, options ...rpc.Option) (time.Time, error) {
 	res := &GetTimestampReply{}
 	err := c.requester.SendRequest(ctx, "platform.getTimestamp", struct{}{}, res, options...)
 	return res.Timestamp, err
 }
 
+func (c *client) GetChainTime(ctx context.Context, options ...rpc.Option) (*GetChainTimeReply, error) {
+	res := &GetChainTimeReply{}
+	err := c.requester.SendRequest(ctx, "platform.getChainTime", struct{}{}, res, options...)
+	return res, err
+}
+
+func (c *client) CheckForks(ctx context.Context, numPeers uint32, options ...rpc.Option) (*CheckForksReply, error) {
+	res := &CheckForksReply{}
+	err := c.requester.SendRequest(ctx, "platform.checkForks", &CheckForksArgs{
+		NumPeers: json.Uint32(numPeers),
+	}, res, options...)
+	return res, err
+}
+
 func (c *client) GetValidatorsAt(
 	ctx context.Context,
 	subnetID ids.ID,
@@ -869,6 +1133,24 @@ func (c *client) GetValidatorsAt(
 	return res.Validators, err
 }
 
+func (c *client) GetCometBFTValidatorSet(
+	ctx context.Context,
+	subnetID ids.ID,
+	height *uint64,
+	options ...rpc.Option,
+) (*GetCometBFTValidatorSetReply, error) {
+	args := &GetCometBFTValidatorSetArgs{
+		SubnetID: subnetID,
+	}
+	if height != nil {
+		h := json.Uint64(*height)
+		args.Height = &h
+	}
+	res := &GetCometBFTValidatorSetReply{}
+	err := c.requester.SendRequest(ctx, "platform.getCometBFTValidatorSet", args, res, options...)
+	return res, err
+}
+
 func (c *client) GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error) {
 	res := &api.FormattedBlock{}
 	if err := c.requester.SendRequest(ctx, "platform.getBlock", &api.GetBlockArgs{
@@ -891,3 +1173,24 @@ func (c *client) GetBlockByHeight(ctx context.Context, height uint64, options ..
 	}
 	return formatting.Decode(res.Encoding, res.Block)
 }
+
+// GetRejection returns why a tx was rejected if [err] was returned by IssueTx
+// because the tx was rejected.
+func GetRejection(err error) (*executor.Rejection, bool) {
+	var jsonErr *json2.Error
+	if !errors.As(err, &jsonErr) || jsonErr.Data == nil {
+		return nil, false
+	}
+
+	// The data was decoded without knowing its type, so it is re-encoded to
+	// parse it.
+	dataBytes, err := stdjson.Marshal(jsonErr.Data)
+	if err != nil {
+		return nil, false
+	}
+	rejection := &executor.Rejection{}
+	if err := stdjson.Unmarshal(dataBytes, rejection); err != nil {
+		return nil, false
+	}
+	return rejection, true
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// CometBFTBLSPubKeyType is the name CometBFT uses for BLS12-381 public
+	// keys in JSON.
+	CometBFTBLSPubKeyType = "cometbft/PubKeyBls12_381"
+
+	// maxCometBFTTotalVotingPower is the maximum total voting power of a
+	// CometBFT validator set.
+	maxCometBFTTotalVotingPower = math.MaxInt64 / 8
+
+	// Field numbers of the CometBFT protobuf messages that are hashed into the
+	// validator set hash.
+	cometBFTPublicKeyBLS12381Field     = 3
+	cometBFTSimpleValidatorPubKeyField = 1
+	cometBFTSimpleValidatorPowerField  = 2
+
+	cometBFTAddressLen = 20
+)
+
+var errVotingPowerTooLarge = errors.New("total voting power exceeds the CometBFT maximum")
+
+// CometBFTPubKey is a public key in the JSON format used by CometBFT.
+type CometBFTPubKey struct {
+	Type string `json:"type"`
+	// Value is base64 encoded when marshalled to JSON.
+	Value []byte `json:"value"`
+}
+
+// CometBFTValidator is a validator in the JSON format used by CometBFT.
+type CometBFTValidator struct {
+	// Address is the upper case hex encoding of the first 20 bytes of the
+	// SHA-256 hash of the public key.
+	Address          string         `json:"address"`
+	PubKey           CometBFTPubKey `json:"pub_key"`
+	VotingPower      json.Uint64    `json:"voting_power"`
+	ProposerPriority json.Uint64    `json:"proposer_priority"`
+}
+
+// CometBFTValidatorSet is a validator set in the order used by CometBFT along
+// with its CometBFT hash.
+type CometBFTValidatorSet struct {
+	Validators       []CometBFTValidator
+	TotalVotingPower uint64
+	Hash             []byte
+}
+
+// NewCometBFTValidatorSet converts [vdrs] into a CometBFT validator set.
+//
+// Each validator's weight is used as its voting power and its BLS public key as
+// its CometBFT public key. Validators without a BLS public key are omitted
+// because their signatures can't be verified by a light client. Validators are
+// sorted by decreasing voting power and then by increasing address.
+func NewCometBFTValidatorSet(vdrs map[ids.NodeID]*validators.GetValidatorOutput) (*CometBFTValidatorSet, error) {
+	type validator struct {
+		address     []byte
+		pkBytes     []byte
+		votingPower uint64
+	}
+
+	var (
+		vdrList          = make([]validator, 0, len(vdrs))
+		totalVotingPower uint64
+	)
+	for _, vdr := range vdrs {
+		if vdr.PublicKey == nil {
+			continue
+		}
+		totalVotingPower += vdr.Weight
+		if vdr.Weight > maxCometBFTTotalVotingPower || totalVotingPower > maxCometBFTTotalVotingPower {
+			return nil, fmt.Errorf("%w: %d", errVotingPowerTooLarge, uint64(maxCometBFTTotalVotingPower))
+		}
+
+		pkBytes := bls.PublicKeyToBytes(vdr.PublicKey)
+		pkHash := sha256.Sum256(pkBytes)
+		vdrList = append(vdrList, validator{
+			address:     pkHash[:cometBFTAddressLen],
+			pkBytes:     pkBytes,
+			votingPower: vdr.Weight,
+		})
+	}
+	slices.SortFunc(vdrList, func(a, b validator) bool {
+		if a.votingPower != b.votingPower {
+			return a.votingPower > b.votingPower
+		}
+		return bytes.Compare(a.address, b.address) < 0
+	})
+
+	var (
+		vdrSet = &CometBFTValidatorSet{
+			Validators:       make([]CometBFTValidator, len(vdrList)),
+			TotalVotingPower: totalVotingPower,
+		}
+		leaves = make([][]byte, len(vdrList))
+	)
+	for i, vdr := range vdrList {
+		vdrSet.Validators[i] = CometBFTValidator{
+			Address: strings.ToUpper(hex.EncodeToString(vdr.address)),
+			PubKey: CometBFTPubKey{
+				Type:  CometBFTBLSPubKeyType,
+				Value: vdr.pkBytes,
+			},
+			VotingPower: json.Uint64(vdr.votingPower),
+		}
+		leaves[i] = cometBFTSimpleValidatorBytes(vdr.pkBytes, vdr.votingPower)
+	}
+	vdrSet.Hash = cometBFTMerkleRoot(leaves)
+	return vdrSet, nil
+}
+
+// cometBFTSimpleValidatorBytes returns the protobuf encoding of the CometBFT
+// SimpleValidator message, which is what CometBFT hashes for each validator.
+func cometBFTSimpleValidatorBytes(pkBytes []byte, votingPower uint64) []byte {
+	var pk []byte
+	pk = protowire.AppendTag(pk, cometBFTPublicKeyBLS12381Field, protowire.BytesType)
+	pk = protowire.AppendBytes(pk, pkBytes)
+
+	var vdr []byte
+	vdr = protowire.AppendTag(vdr, cometBFTSimpleValidatorPubKeyField, protowire.BytesType)
+	vdr = protowire.AppendBytes(vdr, pk)
+	vdr = protowire.AppendTag(vdr, cometBFTSimpleValidatorPowerField, protowire.VarintType)
+	return protowire.AppendVarint(vdr, votingPower)
+}
+
+// cometBFTMerkleRoot returns the RFC 6962 merkle root of [leaves], which is
+// how CometBFT hashes a validator set.
+func cometBFTMerkleRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		hash := sha256.Sum256(nil)
+		return hash[:]
+	case 1:
+		hash := sha256.Sum256(append([]byte{0}, leaves[0]...))
+		return hash[:]
+	}
+
+	// Split at the largest power of two that is less than the number of
+	// leaves.
+	split := 1
+	for split*2 < len(leaves) {
+		split *= 2
+	}
+	left := cometBFTMerkleRoot(leaves[:split])
+	right := cometBFTMerkleRoot(leaves[split:])
+
+	inner := make([]byte, 0, 1+len(left)+len(right))
+	inner = append(inner, 1)
+	inner = append(inner, left...)
+	inner = append(inner, right...)
+	hash := sha256.Sum256(inner)
+	return hash[:]
+}
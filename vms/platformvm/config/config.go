@@ -16,6 +16,17 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 )
 
+// ValidatorChurnConfig limits the churn of a subnet's validator set.
+type ValidatorChurnConfig struct {
+	// Length of the periods that the churn is limited over. If 0, the churn
+	// isn't limited.
+	Period time.Duration `json:"period"`
+	// Maximum fraction of the subnet's stake weight, denominated in
+	// [reward.PercentDenominator], that can enter or exit its validator set in
+	// a single [Period]
+	MaxChurn uint64 `json:"maxChurn"`
+}
+
 // Struct collecting all foundational parameters of PlatformVM
 type Config struct {
 	// The node's chain manager
@@ -92,6 +103,19 @@ type Config struct {
 	// Config for the minting function
 	RewardConfig reward.Config
 
+	// Length of the periods that the churn of each subnet's validator set is
+	// limited over, after the D upgrade. If 0, the churn isn't limited.
+	ValidatorChurnPeriod time.Duration
+
+	// Maximum fraction of a subnet's stake weight, denominated in
+	// [reward.PercentDenominator], that can enter or exit its validator set in
+	// a single [ValidatorChurnPeriod]
+	MaxValidatorChurn uint64
+
+	// Subnet ID --> churn limit of its validator set, used instead of
+	// [ValidatorChurnPeriod] and [MaxValidatorChurn]
+	SubnetValidatorChurn map[ids.ID]ValidatorChurnConfig
+
 	// Time of the AP3 network upgrade
 	ApricotPhase3Time time.Time
 
@@ -152,6 +176,18 @@ func (c *Config) GetCreateSubnetTxFee(timestamp time.Time) uint64 {
 	return c.CreateAssetTxFee
 }
 
+// GetValidatorChurnConfig returns the churn limit of the validator set of
+// [subnetID].
+func (c *Config) GetValidatorChurnConfig(subnetID ids.ID) ValidatorChurnConfig {
+	if churnConfig, ok := c.SubnetValidatorChurn[subnetID]; ok {
+		return churnConfig
+	}
+	return ValidatorChurnConfig{
+		Period:   c.ValidatorChurnPeriod,
+		MaxChurn: c.MaxValidatorChurn,
+	}
+}
+
 // Create the blockchain described in [tx], but only if this node is a member of
 // the subnet that validates the chain
 func (c *Config) CreateChain(chainID ids.ID, tx *txs.CreateChainTx) {
@@ -5,6 +5,7 @@ package config
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/ava-labs/avalanchego/utils/units"
 )
@@ -19,6 +20,11 @@ var DefaultExecutionConfig = ExecutionConfig{
 	BlockIDCacheSize:             8192,
 	FxOwnerCacheSize:             4 * units.MiB,
 	ChecksumsEnabled:             false,
+	PruneCompactionEnabled:       true,
+	PruneCompactionInterval:      10 * time.Second,
+	StateHistoryEnabled:          false,
+	StateHistoryRetention:        100_000,
+	UptimeAttestationEnabled:     false,
 }
 
 // ExecutionConfig provides execution parameters of PlatformVM
@@ -32,6 +38,23 @@ type ExecutionConfig struct {
 	BlockIDCacheSize             int  `json:"block-id-cache-size"`
 	FxOwnerCacheSize             int  `json:"fx-owner-cache-size"`
 	ChecksumsEnabled             bool `json:"checksums-enabled"`
+	// PruneCompactionEnabled compacts the database in the background after
+	// the state is pruned, so that the space freed by pruning is reclaimed.
+	PruneCompactionEnabled bool `json:"prune-compaction-enabled"`
+	// PruneCompactionInterval is the time waited before compacting each range
+	// of the pruned database.
+	PruneCompactionInterval time.Duration `json:"prune-compaction-interval"`
+	// StateHistoryEnabled records the value of every UTXO before each block
+	// that modifies it and the timestamp after each block, so that they can be
+	// read at past heights. Disabling it stops recording the history, but
+	// doesn't delete the history that was already recorded.
+	StateHistoryEnabled bool `json:"state-history-enabled"`
+	// StateHistoryRetention is the number of most recent heights whose state
+	// history is retained. If 0, the state history is never pruned.
+	StateHistoryRetention uint64 `json:"state-history-retention"`
+	// UptimeAttestationEnabled allows platform.getUptimeAttestation to sign
+	// this node's observed uptimes with its BLS key.
+	UptimeAttestationEnabled bool `json:"uptime-attestation-enabled"`
 }
 
 // GetExecutionConfig returns an ExecutionConfig
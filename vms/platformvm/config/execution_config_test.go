@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -47,7 +48,12 @@ func TestExecutionConfigUnmarshal(t *testing.T) {
 			"chain-db-cache-size": 7,
 			"block-id-cache-size": 8,
 			"fx-owner-cache-size": 9,
-			"checksums-enabled": true
+			"checksums-enabled": true,
+			"prune-compaction-enabled": false,
+			"prune-compaction-interval": 1000000000,
+			"state-history-enabled": true,
+			"state-history-retention": 10,
+			"uptime-attestation-enabled": true
 		}`)
 		ec, err := GetExecutionConfig(b)
 		require.NoError(err)
@@ -61,6 +67,11 @@ func TestExecutionConfigUnmarshal(t *testing.T) {
 			BlockIDCacheSize:             8,
 			FxOwnerCacheSize:             9,
 			ChecksumsEnabled:             true,
+			PruneCompactionEnabled:       false,
+			PruneCompactionInterval:      time.Second,
+			StateHistoryEnabled:          true,
+			StateHistoryRetention:        10,
+			UptimeAttestationEnabled:     true,
 		}
 		require.Equal(expected, ec)
 	})
@@ -0,0 +1,427 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package forkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// RequestTimeout is the maximum amount of time to wait for a peer to
+	// answer a request.
+	RequestTimeout = 5 * time.Second
+
+	// The maximum number of requests sent to a peer to find the first height
+	// at which it diverged from the local chain. Each request narrows the
+	// range of heights by a factor of [MaxHeights]-1.
+	maxRounds = 12
+)
+
+var (
+	errRequestFailed     = errors.New("request failed")
+	errInvalidResponse   = errors.New("invalid response")
+	errDivergenceUnknown = errors.New("couldn't find the first diverging height")
+)
+
+// Chain is the local chain that peers are compared against.
+type Chain interface {
+	// LastAccepted returns the height and ID of the last accepted block.
+	LastAccepted() (uint64, ids.ID, error)
+	// GetBlockIDAtHeight returns the ID of the block accepted at [height], or
+	// [database.ErrNotFound] if no block was accepted at [height].
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+	// Checksum returns the checksum of the UTXOs, or [ids.Empty] if checksums
+	// aren't maintained.
+	Checksum() ids.ID
+}
+
+// PeerResult is the comparison of a peer's chain with the local chain.
+type PeerResult struct {
+	NodeID ids.NodeID
+	// Err is non-nil if the peer couldn't be compared.
+	Err error
+
+	LastAcceptedHeight uint64
+	LastAcceptedID     ids.ID
+	// Diverged is true if the peer accepted a different block than the local
+	// chain at some height.
+	Diverged bool
+	// FirstDivergingHeight is the lowest height at which the peer accepted a
+	// different block than the local chain, if [Diverged].
+	FirstDivergingHeight uint64
+	// ChecksumMismatch is true if the peer accepted the same last block as
+	// the local chain, but its UTXOs have a different checksum.
+	ChecksumMismatch bool
+}
+
+// Checker compares the local chain with the chains of peers, by asking them
+// for the IDs of the blocks they accepted at sampled heights. Since a block ID
+// commits to the block's parent, two chains that accepted the same block at a
+// height accepted the same blocks at every lower height, so the first height
+// at which they diverged is found by bisection.
+//
+// Checker also answers the requests of peers.
+type Checker struct {
+	log    logging.Logger
+	sender common.AppSender
+	// Held while reading [chain].
+	lock  sync.Locker
+	chain Chain
+
+	requestsLock  sync.Mutex
+	nextRequestID uint32
+	// Request ID --> Channel the response is sent on, or nil if the request
+	// failed
+	pendingRequests map[uint32]pendingRequest
+}
+
+type pendingRequest struct {
+	nodeID    ids.NodeID
+	responses chan<- []byte
+}
+
+func New(log logging.Logger, sender common.AppSender, lock sync.Locker, chain Chain) *Checker {
+	return &Checker{
+		log:             log,
+		sender:          sender,
+		lock:            lock,
+		chain:           chain,
+		pendingRequests: make(map[uint32]pendingRequest),
+	}
+}
+
+// AppRequest answers the request of a peer.
+// Invalid requests are dropped.
+// Assumes [c.lock] isn't held.
+func (c *Checker) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, _ time.Time, requestBytes []byte) error {
+	request, err := ParseRequest(requestBytes)
+	if err != nil {
+		c.log.Debug("dropping fork check request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	response, err := c.respond(request)
+	if err != nil {
+		return err
+	}
+	responseBytes, err := response.Bytes()
+	if err != nil {
+		return err
+	}
+	return c.sender.SendAppResponse(ctx, nodeID, requestID, responseBytes)
+}
+
+func (c *Checker) respond(request *Request) (*Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	height, blkID, err := c.chain.LastAccepted()
+	if err != nil {
+		return nil, err
+	}
+	response := &Response{
+		LastAcceptedHeight: height,
+		LastAcceptedID:     blkID,
+		Checksum:           c.chain.Checksum(),
+		BlockIDs:           make([]ids.ID, len(request.Heights)),
+	}
+	for i, height := range request.Heights {
+		response.BlockIDs[i], err = c.getBlockIDAtHeight(height)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// getBlockIDAtHeight returns [ids.Empty] if no block was accepted at [height].
+// Assumes [c.lock] is held.
+func (c *Checker) getBlockIDAtHeight(height uint64) (ids.ID, error) {
+	blkID, err := c.chain.GetBlockIDAtHeight(height)
+	if err == database.ErrNotFound {
+		return ids.Empty, nil
+	}
+	return blkID, err
+}
+
+// AppResponse delivers the response of a peer to the request it answers.
+func (c *Checker) AppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, responseBytes []byte) error {
+	c.deliver(nodeID, requestID, responseBytes)
+	return nil
+}
+
+// AppRequestFailed fails the request.
+func (c *Checker) AppRequestFailed(_ context.Context, nodeID ids.NodeID, requestID uint32) error {
+	c.deliver(nodeID, requestID, nil)
+	return nil
+}
+
+func (c *Checker) deliver(nodeID ids.NodeID, requestID uint32, responseBytes []byte) {
+	c.requestsLock.Lock()
+	defer c.requestsLock.Unlock()
+
+	request, ok := c.pendingRequests[requestID]
+	if !ok || request.nodeID != nodeID {
+		c.log.Debug("dropping unexpected fork check response",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return
+	}
+	delete(c.pendingRequests, requestID)
+	request.responses <- responseBytes
+}
+
+// request asks [nodeID] for the IDs of the blocks it accepted at [heights].
+// Assumes [c.lock] isn't held.
+func (c *Checker) request(ctx context.Context, nodeID ids.NodeID, heights []uint64) (*Response, error) {
+	requestBytes, err := (&Request{Heights: heights}).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(chan []byte, 1)
+	c.requestsLock.Lock()
+	requestID := c.nextRequestID
+	c.nextRequestID++
+	c.pendingRequests[requestID] = pendingRequest{
+		nodeID:    nodeID,
+		responses: responses,
+	}
+	c.requestsLock.Unlock()
+
+	if err := c.sender.SendAppRequest(ctx, set.Of(nodeID), requestID, requestBytes); err != nil {
+		c.dropRequest(requestID)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	select {
+	case responseBytes := <-responses:
+		if responseBytes == nil {
+			return nil, errRequestFailed
+		}
+		response, err := ParseResponse(responseBytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidResponse, err)
+		}
+		if len(response.BlockIDs) != len(heights) {
+			return nil, fmt.Errorf("%w: expected %d block IDs but got %d", errInvalidResponse, len(heights), len(response.BlockIDs))
+		}
+		return response, nil
+	case <-ctx.Done():
+		c.dropRequest(requestID)
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Checker) dropRequest(requestID uint32) {
+	c.requestsLock.Lock()
+	defer c.requestsLock.Unlock()
+
+	delete(c.pendingRequests, requestID)
+}
+
+// Check compares the chain of each of [nodeIDs] with the local chain.
+// The peers are compared concurrently.
+// Assumes [c.lock] isn't held.
+func (c *Checker) Check(ctx context.Context, nodeIDs []ids.NodeID) []PeerResult {
+	results := make([]PeerResult, len(nodeIDs))
+	var wg sync.WaitGroup
+	for i, nodeID := range nodeIDs {
+		wg.Add(1)
+		go func(i int, nodeID ids.NodeID) {
+			defer wg.Done()
+
+			results[i] = c.checkPeer(ctx, nodeID)
+		}(i, nodeID)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkPeer compares the chain of [nodeID] with the local chain.
+//
+// The first request samples heights exponentially further from the local last
+// accepted block. If the peer diverged, each following request samples the
+// heights between the highest height known to match and the lowest height
+// known to differ, until they're adjacent.
+// Assumes [c.lock] isn't held.
+func (c *Checker) checkPeer(ctx context.Context, nodeID ids.NodeID) PeerResult {
+	result := PeerResult{
+		NodeID: nodeID,
+	}
+
+	c.lock.Lock()
+	localHeight, localID, err := c.chain.LastAccepted()
+	localChecksum := c.chain.Checksum()
+	c.lock.Unlock()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	heights := exponentialHeights(localHeight)
+	response, err := c.request(ctx, nodeID, heights)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.LastAcceptedHeight = response.LastAcceptedHeight
+	result.LastAcceptedID = response.LastAcceptedID
+
+	bounds := divergenceBounds{}
+	// The peer's last accepted block is compared too, in case it's below the
+	// local last accepted block and wasn't sampled.
+	if response.LastAcceptedHeight <= localHeight {
+		heights = append(heights, response.LastAcceptedHeight)
+		response.BlockIDs = append(response.BlockIDs, response.LastAcceptedID)
+	}
+	for round := 0; ; round++ {
+		if err := c.compare(&bounds, heights, response.BlockIDs); err != nil {
+			result.Err = err
+			return result
+		}
+		if !bounds.diverged {
+			// Either chain may be behind the other, but they agree on every
+			// height they both accepted a block at.
+			sameTip := response.LastAcceptedHeight == localHeight && response.LastAcceptedID == localID
+			result.ChecksumMismatch = sameTip &&
+				response.Checksum != ids.Empty &&
+				localChecksum != ids.Empty &&
+				response.Checksum != localChecksum
+			return result
+		}
+		if bounds.found() {
+			result.Diverged = true
+			result.FirstDivergingHeight = bounds.divergeHeight
+			return result
+		}
+		if round == maxRounds {
+			result.Err = errDivergenceUnknown
+			return result
+		}
+
+		heights = bounds.nextHeights()
+		response, err = c.request(ctx, nodeID, heights)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+	}
+}
+
+// compare narrows [bounds] with the peer's [blkIDs] at [heights].
+// Heights the peer or the local chain didn't accept a block at are skipped.
+// Assumes [c.lock] isn't held.
+func (c *Checker) compare(bounds *divergenceBounds, heights []uint64, blkIDs []ids.ID) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, height := range heights {
+		peerID := blkIDs[i]
+		if peerID == ids.Empty {
+			continue
+		}
+		localID, err := c.getBlockIDAtHeight(height)
+		if err != nil {
+			return err
+		}
+		if localID == ids.Empty {
+			continue
+		}
+		bounds.observe(height, localID == peerID)
+	}
+	return nil
+}
+
+// divergenceBounds bounds the first height at which two chains diverged.
+type divergenceBounds struct {
+	// True if a height at which the chains match is known.
+	matched bool
+	// The highest height known to match.
+	matchHeight uint64
+	// True if a height at which the chains differ is known.
+	diverged bool
+	// The lowest height known to differ.
+	divergeHeight uint64
+}
+
+func (b *divergenceBounds) observe(height uint64, matches bool) {
+	switch {
+	case matches && (!b.matched || height > b.matchHeight):
+		b.matched = true
+		b.matchHeight = height
+	case !matches && (!b.diverged || height < b.divergeHeight):
+		b.diverged = true
+		b.divergeHeight = height
+	}
+}
+
+// found returns true if the first diverging height is known, which is when
+// every lower height is known to match.
+func (b *divergenceBounds) found() bool {
+	return b.diverged && (b.divergeHeight == 0 || b.matched && b.matchHeight+1 == b.divergeHeight)
+}
+
+// nextHeights returns the heights to sample between the highest height known
+// to match and the lowest height known to differ, exclusively.
+// Assumes [b.diverged] and ![b.found()].
+func (b *divergenceBounds) nextHeights() []uint64 {
+	low := uint64(0)
+	if b.matched {
+		low = b.matchHeight + 1
+	}
+	return linearHeights(low, b.divergeHeight-1)
+}
+
+// exponentialHeights returns [top] and the heights exponentially further below
+// it, down to 0.
+func exponentialHeights(top uint64) []uint64 {
+	heights := []uint64{top}
+	for step := uint64(1); step <= top && len(heights) < MaxHeights-1; step *= 2 {
+		heights = append(heights, top-step)
+	}
+	if heights[len(heights)-1] != 0 {
+		heights = append(heights, 0)
+	}
+	return heights
+}
+
+// linearHeights returns up to [MaxHeights] evenly spaced heights in
+// [low, high], including both.
+func linearHeights(low, high uint64) []uint64 {
+	if high-low < MaxHeights {
+		heights := make([]uint64, 0, high-low+1)
+		for height := low; height <= high; height++ {
+			heights = append(heights, height)
+		}
+		return heights
+	}
+
+	step := (high - low) / (MaxHeights - 1)
+	heights := make([]uint64, 0, MaxHeights)
+	for i := uint64(0); i < MaxHeights-1; i++ {
+		heights = append(heights, low+i*step)
+	}
+	return append(heights, high)
+}
@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package forkcheck
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+type testChain struct {
+	blkIDs   []ids.ID
+	checksum ids.ID
+}
+
+func (c *testChain) LastAccepted() (uint64, ids.ID, error) {
+	height := uint64(len(c.blkIDs) - 1)
+	return height, c.blkIDs[height], nil
+}
+
+func (c *testChain) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	if height >= uint64(len(c.blkIDs)) {
+		return ids.Empty, database.ErrNotFound
+	}
+	return c.blkIDs[height], nil
+}
+
+func (c *testChain) Checksum() ids.ID {
+	return c.checksum
+}
+
+// newTestChains returns two chains that share their first [shared] blocks,
+// followed by [localOnly] and [peerOnly] different blocks.
+func newTestChains(shared, localOnly, peerOnly int) (*testChain, *testChain) {
+	local := &testChain{}
+	peer := &testChain{}
+	for i := 0; i < shared; i++ {
+		blkID := ids.GenerateTestID()
+		local.blkIDs = append(local.blkIDs, blkID)
+		peer.blkIDs = append(peer.blkIDs, blkID)
+	}
+	for i := 0; i < localOnly; i++ {
+		local.blkIDs = append(local.blkIDs, ids.GenerateTestID())
+	}
+	for i := 0; i < peerOnly; i++ {
+		peer.blkIDs = append(peer.blkIDs, ids.GenerateTestID())
+	}
+	return local, peer
+}
+
+// newTestCheckers returns the checkers of [localChain] and [peerChain], whose
+// requests are delivered to each other. If [fail] is true, the requests of the
+// local checker fail. Returns the number of requests sent by the local
+// checker.
+func newTestCheckers(t *testing.T, localChain, peerChain Chain, fail bool) (*Checker, ids.NodeID, func() int) {
+	var (
+		localID      = ids.GenerateTestNodeID()
+		peerID       = ids.GenerateTestNodeID()
+		localSender  = &common.SenderTest{T: t}
+		peerSender   = &common.SenderTest{T: t}
+		local        = New(logging.NoLog{}, localSender, &sync.Mutex{}, localChain)
+		peer         = New(logging.NoLog{}, peerSender, &sync.Mutex{}, peerChain)
+		requestsLock sync.Mutex
+		requests     int
+	)
+	localSender.SendAppRequestF = func(_ context.Context, nodeIDs set.Set[ids.NodeID], requestID uint32, request []byte) error {
+		require.Equal(t, set.Of(peerID), nodeIDs)
+
+		requestsLock.Lock()
+		requests++
+		requestsLock.Unlock()
+
+		if fail {
+			go func() {
+				_ = local.AppRequestFailed(context.Background(), peerID, requestID)
+			}()
+			return nil
+		}
+		go func() {
+			_ = peer.AppRequest(context.Background(), localID, requestID, time.Time{}, request)
+		}()
+		return nil
+	}
+	peerSender.SendAppResponseF = func(_ context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+		require.Equal(t, localID, nodeID)
+
+		go func() {
+			_ = local.AppResponse(context.Background(), peerID, requestID, response)
+		}()
+		return nil
+	}
+	return local, peerID, func() int {
+		requestsLock.Lock()
+		defer requestsLock.Unlock()
+
+		return requests
+	}
+}
+
+func TestCheckerCheck(t *testing.T) {
+	tests := []struct {
+		name                 string
+		shared               int
+		localOnly            int
+		peerOnly             int
+		expectedDiverged     bool
+		expectedFirstHeight  uint64
+		expectedMaxRequests  int
+		expectedPeerAccepted uint64
+	}{
+		{
+			name:                 "same chain",
+			shared:               1000,
+			expectedMaxRequests:  1,
+			expectedPeerAccepted: 999,
+		},
+		{
+			name:                 "peer behind",
+			shared:               700,
+			localOnly:            300,
+			expectedMaxRequests:  1,
+			expectedPeerAccepted: 699,
+		},
+		{
+			name:                 "peer ahead",
+			shared:               700,
+			peerOnly:             300,
+			expectedMaxRequests:  1,
+			expectedPeerAccepted: 999,
+		},
+		{
+			name:                 "diverged",
+			shared:               1000,
+			localOnly:            4000,
+			peerOnly:             2000,
+			expectedDiverged:     true,
+			expectedFirstHeight:  1000,
+			expectedMaxRequests:  3,
+			expectedPeerAccepted: 2999,
+		},
+		{
+			name:                 "diverged below the peer's last accepted block",
+			shared:               10,
+			localOnly:            100_000,
+			peerOnly:             3,
+			expectedDiverged:     true,
+			expectedFirstHeight:  10,
+			expectedMaxRequests:  2,
+			expectedPeerAccepted: 12,
+		},
+		{
+			name:                 "different genesis",
+			localOnly:            500,
+			peerOnly:             500,
+			expectedDiverged:     true,
+			expectedFirstHeight:  0,
+			expectedMaxRequests:  1,
+			expectedPeerAccepted: 499,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			localChain, peerChain := newTestChains(test.shared, test.localOnly, test.peerOnly)
+			checker, peerID, requests := newTestCheckers(t, localChain, peerChain, false)
+
+			results := checker.Check(context.Background(), []ids.NodeID{peerID})
+			require.Len(results, 1)
+			result := results[0]
+			require.NoError(result.Err)
+			require.Equal(peerID, result.NodeID)
+			require.Equal(test.expectedPeerAccepted, result.LastAcceptedHeight)
+			require.Equal(test.expectedDiverged, result.Diverged)
+			require.Equal(test.expectedFirstHeight, result.FirstDivergingHeight)
+			require.False(result.ChecksumMismatch)
+			require.LessOrEqual(requests(), test.expectedMaxRequests)
+		})
+	}
+}
+
+func TestCheckerChecksumMismatch(t *testing.T) {
+	require := require.New(t)
+
+	localChain, peerChain := newTestChains(100, 0, 0)
+	localChain.checksum = ids.GenerateTestID()
+	peerChain.checksum = ids.GenerateTestID()
+	checker, peerID, _ := newTestCheckers(t, localChain, peerChain, false)
+
+	results := checker.Check(context.Background(), []ids.NodeID{peerID})
+	require.NoError(results[0].Err)
+	require.False(results[0].Diverged)
+	require.True(results[0].ChecksumMismatch)
+
+	// Checksums aren't compared if the peer doesn't maintain them.
+	peerChain.checksum = ids.Empty
+	results = checker.Check(context.Background(), []ids.NodeID{peerID})
+	require.NoError(results[0].Err)
+	require.False(results[0].ChecksumMismatch)
+}
+
+func TestCheckerRequestFailed(t *testing.T) {
+	localChain, peerChain := newTestChains(100, 0, 0)
+	checker, peerID, _ := newTestCheckers(t, localChain, peerChain, true)
+
+	results := checker.Check(context.Background(), []ids.NodeID{peerID})
+	require.ErrorIs(t, results[0].Err, errRequestFailed)
+}
+
+func TestParseRequestTooManyHeights(t *testing.T) {
+	require := require.New(t)
+
+	requestBytes, err := (&Request{Heights: make([]uint64, MaxHeights+1)}).Bytes()
+	require.NoError(err)
+	_, err = ParseRequest(requestBytes)
+	require.ErrorIs(err, errTooManyHeights)
+}
+
+func TestSampledHeights(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]uint64{0}, exponentialHeights(0))
+	require.Equal([]uint64{10, 9, 8, 6, 2, 0}, exponentialHeights(10))
+	require.LessOrEqual(len(exponentialHeights(1<<63)), MaxHeights)
+
+	require.Equal([]uint64{3, 4, 5}, linearHeights(3, 5))
+	heights := linearHeights(0, 1_000_000)
+	require.Len(heights, MaxHeights)
+	require.Equal(uint64(0), heights[0])
+	require.Equal(uint64(1_000_000), heights[MaxHeights-1])
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package forkcheck
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// MaxHeights is the maximum number of heights in a [Request].
+const MaxHeights = 64
+
+var errTooManyHeights = errors.New("too many heights")
+
+// Request asks a peer for the IDs of the blocks it accepted at [Heights].
+type Request struct {
+	Heights []uint64 `serialize:"true"`
+}
+
+// Response is a peer's answer to a [Request].
+type Response struct {
+	LastAcceptedHeight uint64 `serialize:"true"`
+	LastAcceptedID     ids.ID `serialize:"true"`
+	// Checksum is the checksum of the peer's UTXOs after accepting
+	// [LastAcceptedID], or [ids.Empty] if the peer doesn't maintain checksums.
+	Checksum ids.ID `serialize:"true"`
+	// BlockIDs[i] is the ID of the block the peer accepted at the height
+	// Heights[i] of the request, or [ids.Empty] if it hasn't accepted a block
+	// at that height.
+	BlockIDs []ids.ID `serialize:"true"`
+}
+
+func (r *Request) Bytes() ([]byte, error) {
+	return txs.Codec.Marshal(txs.Version, r)
+}
+
+func ParseRequest(b []byte) (*Request, error) {
+	r := &Request{}
+	if _, err := txs.Codec.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	if len(r.Heights) > MaxHeights {
+		return nil, fmt.Errorf("%w: %d > %d", errTooManyHeights, len(r.Heights), MaxHeights)
+	}
+	return r, nil
+}
+
+func (r *Response) Bytes() ([]byte, error) {
+	return txs.Codec.Marshal(txs.Version, r)
+}
+
+func ParseResponse(b []byte) (*Response, error) {
+	r := &Response{}
+	if _, err := txs.Codec.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
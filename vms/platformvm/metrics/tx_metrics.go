@@ -29,7 +29,8 @@ type txMetrics struct {
 	numAddPermissionlessValidatorTxs,
 	numAddPermissionlessDelegatorTxs,
 	numTransferSubnetOwnershipTxs,
-	numBaseTxs prometheus.Counter
+	numBaseTxs,
+	numFreezeSubnetValidatorTxs prometheus.Counter
 }
 
 func newTxMetrics(
@@ -53,6 +54,7 @@ func newTxMetrics(
 		numAddPermissionlessDelegatorTxs: newTxMetric(namespace, "add_permissionless_delegator", registerer, &errs),
 		numTransferSubnetOwnershipTxs:    newTxMetric(namespace, "transfer_subnet_ownership", registerer, &errs),
 		numBaseTxs:                       newTxMetric(namespace, "base", registerer, &errs),
+		numFreezeSubnetValidatorTxs:      newTxMetric(namespace, "freeze_subnet_validator", registerer, &errs),
 	}
 	return m, errs.Err
 }
@@ -146,3 +148,8 @@ func (m *txMetrics) BaseTx(*txs.BaseTx) error {
 	m.numBaseTxs.Inc()
 	return nil
 }
+
+func (m *txMetrics) FreezeSubnetValidatorTx(*txs.FreezeSubnetValidatorTx) error {
+	m.numFreezeSubnetValidatorTxs.Inc()
+	return nil
+}
@@ -5,14 +5,18 @@ package platformvm
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"strings"
 	"time"
 
 	stdjson "encoding/json"
 
+	"github.com/gorilla/rpc/v2/json2"
+
 	"go.uber.org/zap"
 
 	"golang.org/x/exp/maps"
@@ -29,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/sampler"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/keystore"
@@ -45,15 +50,23 @@ import (
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
 	platformapi "github.com/ava-labs/avalanchego/vms/platformvm/api"
+	blockbuilder "github.com/ava-labs/avalanchego/vms/platformvm/block/builder"
 )
 
 const (
 	// Max number of addresses that can be passed in as argument to GetUTXOs
 	maxGetUTXOsAddrs = 1024
 
-	// Max number of addresses that can be passed in as argument to GetStake
+	// Max number of addresses that can be passed in as argument to GetStake and
+	// GetStakeSummary
 	maxGetStakeAddrs = 256
 
+	// Max number of txs that can be passed in as argument to IssueTxs
+	maxIssueTxs = 1024
+
+	// Max number of heights that can be queried by GetSupplyChanges
+	maxGetSupplyChangesHeights = 100_000
+
 	// Minimum amount of delay to allow a transaction to be issued through the
 	// API
 	minAddStakerDelay = 2 * executor.SyncBound
@@ -61,6 +74,15 @@ const (
 	// Note: Staker attributes cache should be large enough so that no evictions
 	// happen when the API loops through all stakers.
 	stakerAttributesCacheSize = 100_000
+
+	// Max length of an idempotency key passed to IssueTx
+	maxIdempotencyKeyLen = 256
+
+	// Max number of idempotency keys that are remembered
+	idempotencyKeysCacheSize = 4096
+
+	// Duration an idempotency key is remembered after the tx was issued
+	idempotencyKeyRetention = 10 * time.Minute
 )
 
 var (
@@ -80,6 +102,19 @@ var (
 	errMissingPrivateKey        = errors.New("argument 'privateKey' not given")
 	errStartAfterEndTime        = errors.New("start time must be before end time")
 	errStartTimeInThePast       = errors.New("start time in the past")
+	errNotStakerTx              = errors.New("tx is not a staker tx")
+	errStakerTxNotCommitted     = errors.New("staker tx is not committed")
+	errNoTxs                    = errors.New("no txs provided")
+	errIdempotencyKeyTooLong    = fmt.Errorf("idempotency key must be at most %d bytes", maxIdempotencyKeyLen)
+	errIdempotencyKeyReused     = errors.New("idempotency key was used to issue a different tx")
+	errHeightNotAccepted        = errors.New("height is above the last accepted height")
+	errStartAfterEndHeight      = errors.New("start height must not be after end height")
+	errUptimesNotTracked        = errors.New("uptimes of subnet aren't tracked")
+	errAttestationDisabled      = errors.New("uptime attestation is disabled")
+	errUnsupportedOwnerType     = errors.New("unsupported owner type")
+	errUnsupportedOutputType    = errors.New("unsupported output type")
+	errInvalidRewardHistoryArgs = errors.New("exactly one of 'address' or 'nodeID' must be provided")
+	errTooManyPeers             = errors.New("too many peers requested")
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -87,6 +122,13 @@ type Service struct {
 	vm                    *VM
 	addrManager           avax.AddressManager
 	stakerAttributesCache *cache.LRU[ids.ID, *stakerAttributes]
+	// Idempotency key --> The tx issued with the key
+	idempotencyKeys *cache.LRU[string, *idempotentTx]
+}
+
+type idempotentTx struct {
+	txID     ids.ID
+	issuedAt time.Time
 }
 
 // All attributes are optional and may not be filled for each stakerTx.
@@ -298,6 +340,39 @@ utxoFor:
 					lockedStakeables[assetID] = newBalance
 				}
 			}
+		case *stakeable.VestingOut:
+			innerOut, ok := out.TransferableOut.(*secp256k1fx.TransferOutput)
+			if !ok {
+				s.vm.ctx.Log.Warn("unexpected output type in UTXO",
+					zap.String("type", fmt.Sprintf("%T", out.TransferableOut)),
+				)
+				continue utxoFor
+			}
+			if innerOut.Locktime > currentTime {
+				newBalance, err := safemath.Add64(lockedNotStakeables[assetID], out.Amount())
+				if err != nil {
+					lockedNotStakeables[assetID] = math.MaxUint64
+				} else {
+					lockedNotStakeables[assetID] = newBalance
+				}
+				break
+			}
+
+			// The vested tranches are unlocked and the others are only
+			// stakeable.
+			unlocked := out.Unlocked(currentTime)
+			newBalance, err := safemath.Add64(unlockeds[assetID], unlocked)
+			if err != nil {
+				unlockeds[assetID] = math.MaxUint64
+			} else {
+				unlockeds[assetID] = newBalance
+			}
+			newBalance, err = safemath.Add64(lockedStakeables[assetID], out.Amount()-unlocked)
+			if err != nil {
+				lockedStakeables[assetID] = math.MaxUint64
+			} else {
+				lockedStakeables[assetID] = newBalance
+			}
 		default:
 			continue utxoFor
 		}
@@ -1130,6 +1205,118 @@ func (s *Service) GetCurrentSupply(r *http.Request, args *GetCurrentSupplyArgs,
 	return nil
 }
 
+// GetSupplyAtArgs are the arguments for calling GetSupplyAt
+type GetSupplyAtArgs struct {
+	SubnetID ids.ID      `json:"subnetID"`
+	Height   json.Uint64 `json:"height"`
+}
+
+// GetSupplyAtReply are the results from calling GetSupplyAt
+type GetSupplyAtReply struct {
+	Supply json.Uint64 `json:"supply"`
+}
+
+// GetSupplyAt returns the supply of the subnet once the block at the provided
+// height was accepted
+func (s *Service) GetSupplyAt(r *http.Request, args *GetSupplyAtArgs, reply *GetSupplyAtReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getSupplyAt"),
+		zap.Stringer("subnetID", args.SubnetID),
+		zap.Uint64("height", uint64(args.Height)),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	if err := s.verifyHeightAccepted(r.Context(), uint64(args.Height)); err != nil {
+		return err
+	}
+
+	supply, err := s.vm.state.GetSupplyAt(args.SubnetID, uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("fetching supply at height %d failed: %w", args.Height, err)
+	}
+	reply.Supply = json.Uint64(supply)
+	return nil
+}
+
+// GetSupplyChangesArgs are the arguments for calling GetSupplyChanges
+type GetSupplyChangesArgs struct {
+	SubnetID    ids.ID      `json:"subnetID"`
+	StartHeight json.Uint64 `json:"startHeight"`
+	EndHeight   json.Uint64 `json:"endHeight"`
+}
+
+// SupplyChange is the supply of a subnet after the block at [Height] modified
+// it
+type SupplyChange struct {
+	Height json.Uint64 `json:"height"`
+	Supply json.Uint64 `json:"supply"`
+}
+
+// GetSupplyChangesReply are the results from calling GetSupplyChanges
+type GetSupplyChangesReply struct {
+	Changes []SupplyChange `json:"changes"`
+}
+
+// GetSupplyChanges returns the changes to the supply of the subnet made by the
+// blocks in the provided height range, inclusive
+func (s *Service) GetSupplyChanges(r *http.Request, args *GetSupplyChangesArgs, reply *GetSupplyChangesReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getSupplyChanges"),
+		zap.Stringer("subnetID", args.SubnetID),
+		zap.Uint64("startHeight", uint64(args.StartHeight)),
+		zap.Uint64("endHeight", uint64(args.EndHeight)),
+	)
+
+	startHeight := uint64(args.StartHeight)
+	endHeight := uint64(args.EndHeight)
+	switch {
+	case startHeight > endHeight:
+		return errStartAfterEndHeight
+	case endHeight-startHeight >= maxGetSupplyChangesHeights:
+		return fmt.Errorf("number of heights requested (%d) exceeds maximum of %d", endHeight-startHeight+1, maxGetSupplyChangesHeights)
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	if err := s.verifyHeightAccepted(r.Context(), endHeight); err != nil {
+		return err
+	}
+
+	changes, err := s.vm.state.GetSupplyChanges(args.SubnetID, startHeight, endHeight)
+	if err != nil {
+		return fmt.Errorf("fetching supply changes failed: %w", err)
+	}
+
+	reply.Changes = make([]SupplyChange, len(changes))
+	for i, change := range changes {
+		reply.Changes[i] = SupplyChange{
+			Height: json.Uint64(change.Height),
+			Supply: json.Uint64(change.Supply),
+		}
+	}
+	return nil
+}
+
+// verifyHeightAccepted returns an error if [height] is above the last accepted
+// height.
+//
+// Assumes [s.vm.ctx.Lock] is held.
+func (s *Service) verifyHeightAccepted(ctx context.Context, height uint64) error {
+	lastAcceptedHeight, err := s.vm.GetCurrentHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching current height failed: %w", err)
+	}
+	if height > lastAcceptedHeight {
+		return fmt.Errorf("%w: %d > %d", errHeightNotAccepted, height, lastAcceptedHeight)
+	}
+	return nil
+}
+
 // SampleValidatorsArgs are the arguments for calling SampleValidators
 type SampleValidatorsArgs struct {
 	// Number of validators in the sample
@@ -2158,12 +2345,42 @@ func (s *Service) GetBlockchains(_ *http.Request, _ *struct{}, response *GetBloc
 	return nil
 }
 
-func (s *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response *api.JSONTxID) error {
+// IssueTxArgs are the arguments for calling IssueTx
+type IssueTxArgs struct {
+	api.FormattedTx
+	// If non-empty, issuing a tx with a key that was used to issue the same tx
+	// within the retention window returns the original tx's status instead of
+	// reissuing the tx.
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// IssueTxReply is the response from calling IssueTx
+type IssueTxReply struct {
+	api.JSONTxID
+	// Status of the tx previously issued with the same idempotency key.
+	// Only set if the idempotency key was already used.
+	Status *status.Status `json:"status,omitempty"`
+}
+
+// IdempotencyKeyReusedError is the data of the error returned by IssueTx when
+// the idempotency key was used to issue a different tx within the retention
+// window.
+type IdempotencyKeyReusedError struct {
+	IdempotencyKey string `json:"idempotencyKey"`
+	// ID of the tx that was previously issued with the idempotency key
+	TxID ids.ID `json:"txID"`
+}
+
+func (s *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *IssueTxReply) error {
 	s.vm.ctx.Log.Debug("API called",
 		zap.String("service", "platform"),
 		zap.String("method", "issueTx"),
 	)
 
+	if len(args.IdempotencyKey) > maxIdempotencyKeyLen {
+		return errIdempotencyKeyTooLong
+	}
+
 	txBytes, err := formatting.Decode(args.Encoding, args.Tx)
 	if err != nil {
 		return fmt.Errorf("problem decoding transaction: %w", err)
@@ -2172,15 +2389,164 @@ func (s *Service) IssueTx(_ *http.Request, args *api.FormattedTx, response *api.
 	if err != nil {
 		return fmt.Errorf("couldn't parse tx: %w", err)
 	}
+	txID := tx.ID()
 
 	s.vm.ctx.Lock.Lock()
 	defer s.vm.ctx.Lock.Unlock()
 
+	if args.IdempotencyKey != "" {
+		issued, ok := s.idempotencyKeys.Get(args.IdempotencyKey)
+		if ok && s.vm.clock.Time().Sub(issued.issuedAt) <= idempotencyKeyRetention {
+			if issued.txID != txID {
+				// The previously issued tx is reported as the error's data so
+				// that issuers can recover its ID.
+				return &json2.Error{
+					Code:    json2.E_INVALID_REQ,
+					Message: fmt.Sprintf("%s: previously issued %s", errIdempotencyKeyReused, issued.txID),
+					Data: &IdempotencyKeyReusedError{
+						IdempotencyKey: args.IdempotencyKey,
+						TxID:           issued.txID,
+					},
+				}
+			}
+
+			// This is a retry of a tx that was already issued, so report the
+			// status of the original submission rather than reissuing it.
+			statusResponse := GetTxStatusResponse{}
+			if err := s.getTxStatus(txID, &statusResponse); err != nil {
+				return err
+			}
+			response.TxID = txID
+			response.Status = &statusResponse.Status
+			return nil
+		}
+	}
+
 	if err := s.vm.Builder.AddUnverifiedTx(tx); err != nil {
-		return fmt.Errorf("couldn't issue tx: %w", err)
+		// The rejection is reported as the error's data so that issuers can
+		// tell why the tx was rejected without parsing the message.
+		return &json2.Error{
+			Code:    json2.E_SERVER,
+			Message: fmt.Sprintf("couldn't issue tx: %s", err),
+			Data:    executor.NewRejection(err),
+		}
 	}
 
-	response.TxID = tx.ID()
+	if args.IdempotencyKey != "" {
+		s.idempotencyKeys.Put(args.IdempotencyKey, &idempotentTx{
+			txID:     txID,
+			issuedAt: s.vm.clock.Time(),
+		})
+	}
+
+	response.TxID = txID
+	return nil
+}
+
+// IssueTxsArgs are the arguments for calls to IssueTxs
+type IssueTxsArgs struct {
+	Txs      []string            `json:"txs"`
+	Encoding formatting.Encoding `json:"encoding"`
+	// If true, none of the txs are issued unless every tx is issued. The txs
+	// are verified in order as if they were issued in the same block, so a
+	// tx may depend on the txs before it.
+	Atomic bool `json:"atomic"`
+}
+
+// IssueTxResult is the result of issuing one of the txs passed to IssueTxs
+type IssueTxResult struct {
+	TxID ids.ID `json:"txID"`
+	// Empty if the tx was issued
+	Error string `json:"error,omitempty"`
+	// Why the tx was rejected. Only set if the tx was parsed but couldn't be
+	// issued.
+	Rejection *executor.Rejection `json:"rejection,omitempty"`
+}
+
+// IssueTxsReply is the response from calling IssueTxs
+type IssueTxsReply struct {
+	// The result of issuing each tx, in the order the txs were provided
+	Results []IssueTxResult `json:"results"`
+}
+
+// IssueTxs issues a batch of txs. Txs that can't be parsed or fail
+// verification are reported in the response rather than returned as an error.
+// If [args.Atomic] is true and any tx can't be issued, none of the txs are
+// issued.
+func (s *Service) IssueTxs(_ *http.Request, args *IssueTxsArgs, reply *IssueTxsReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "issueTxs"),
+		zap.Int("numTxs", len(args.Txs)),
+		zap.Bool("atomic", args.Atomic),
+	)
+
+	switch numTxs := len(args.Txs); {
+	case numTxs == 0:
+		return errNoTxs
+	case numTxs > maxIssueTxs:
+		return fmt.Errorf("number of txs given, %d, exceeds maximum, %d", numTxs, maxIssueTxs)
+	}
+
+	var (
+		batch     = make([]*txs.Tx, len(args.Txs))
+		parseErrs = make([]error, len(args.Txs))
+		numParsed int
+	)
+	for i, txStr := range args.Txs {
+		txBytes, err := formatting.Decode(args.Encoding, txStr)
+		if err != nil {
+			parseErrs[i] = fmt.Errorf("problem decoding transaction: %w", err)
+			continue
+		}
+		tx, err := txs.Parse(txs.Codec, txBytes)
+		if err != nil {
+			parseErrs[i] = fmt.Errorf("couldn't parse tx: %w", err)
+			continue
+		}
+		batch[i] = tx
+		numParsed++
+	}
+
+	reply.Results = make([]IssueTxResult, len(args.Txs))
+	for i, tx := range batch {
+		if tx != nil {
+			reply.Results[i].TxID = tx.ID()
+		}
+	}
+
+	if args.Atomic && numParsed != len(batch) {
+		for i, err := range parseErrs {
+			if err == nil {
+				err = blockbuilder.ErrBatchRejected
+			}
+			reply.Results[i].Error = err.Error()
+		}
+		return nil
+	}
+
+	parsed := make([]*txs.Tx, 0, numParsed)
+	for _, tx := range batch {
+		if tx != nil {
+			parsed = append(parsed, tx)
+		}
+	}
+
+	s.vm.ctx.Lock.Lock()
+	issueErrs := s.vm.Builder.AddUnverifiedTxs(parsed, args.Atomic)
+	s.vm.ctx.Lock.Unlock()
+
+	for i, tx := range batch {
+		err := parseErrs[i]
+		if tx != nil {
+			err = issueErrs[0]
+			issueErrs = issueErrs[1:]
+			reply.Results[i].Rejection = executor.NewRejection(err)
+		}
+		if err != nil {
+			reply.Results[i].Error = err.Error()
+		}
+	}
 	return nil
 }
 
@@ -2223,6 +2589,9 @@ type GetTxStatusResponse struct {
 	// Reason this tx was dropped.
 	// Only non-empty if Status is dropped
 	Reason string `json:"reason,omitempty"`
+	// Why this tx was dropped.
+	// Only set if Status is dropped
+	Rejection *executor.Rejection `json:"rejection,omitempty"`
 }
 
 // GetTxStatus gets a tx's status
@@ -2235,7 +2604,14 @@ func (s *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, response *
 	s.vm.ctx.Lock.Lock()
 	defer s.vm.ctx.Lock.Unlock()
 
-	_, txStatus, err := s.vm.state.GetTx(args.TxID)
+	return s.getTxStatus(args.TxID, response)
+}
+
+// getTxStatus populates [response] with the status of [txID].
+//
+// Assumes [s.vm.ctx.Lock] is held.
+func (s *Service) getTxStatus(txID ids.ID, response *GetTxStatusResponse) error {
+	_, txStatus, err := s.vm.state.GetTx(txID)
 	if err == nil { // Found the status. Report it.
 		response.Status = txStatus
 		return nil
@@ -2257,7 +2633,7 @@ func (s *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, response *
 		return fmt.Errorf("could not retrieve state for block %s", preferredID)
 	}
 
-	_, _, err = onAccept.GetTx(args.TxID)
+	_, _, err = onAccept.GetTx(txID)
 	if err == nil {
 		// Found the status in the preferred block's db. Report tx is processing.
 		response.Status = status.Processing
@@ -2267,7 +2643,7 @@ func (s *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, response *
 		return err
 	}
 
-	if s.vm.Builder.Has(args.TxID) {
+	if s.vm.Builder.Has(txID) {
 		// Found the tx in the mempool. Report tx is processing.
 		response.Status = status.Processing
 		return nil
@@ -2275,7 +2651,7 @@ func (s *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, response *
 
 	// Note: we check if tx is dropped only after having looked for it
 	// in the database and the mempool, because dropped txs may be re-issued.
-	reason := s.vm.Builder.GetDropReason(args.TxID)
+	reason := s.vm.Builder.GetDropReason(txID)
 	if reason == nil {
 		// The tx isn't being tracked by the node.
 		response.Status = status.Unknown
@@ -2285,6 +2661,7 @@ func (s *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, response *
 	// The tx was recently dropped because it was invalid.
 	response.Status = status.Dropped
 	response.Reason = reason.Error()
+	response.Rejection = executor.NewRejection(reason)
 	return nil
 }
 
@@ -2578,97 +2955,746 @@ func (s *Service) GetRewardUTXOs(_ *http.Request, args *api.GetTxArgs, reply *Ge
 	return nil
 }
 
-// GetTimestampReply is the response from GetTimestamp
-type GetTimestampReply struct {
-	// Current timestamp
-	Timestamp time.Time `json:"timestamp"`
+// StakerStatus describes where a staker is in its lifecycle.
+type StakerStatus string
+
+const (
+	// StakerPending is the status of a staker that hasn't started staking.
+	StakerPending StakerStatus = "Pending"
+	// StakerCurrent is the status of a staker that is currently staking.
+	StakerCurrent StakerStatus = "Current"
+	// StakerCompleted is the status of a staker that has been removed from the
+	// staker set.
+	StakerCompleted StakerStatus = "Completed"
+)
+
+// GetStakerReply is the response from calling GetStaker.
+type GetStakerReply struct {
+	TxID      ids.ID       `json:"txID"`
+	SubnetID  ids.ID       `json:"subnetID"`
+	NodeID    ids.NodeID   `json:"nodeID"`
+	Status    StakerStatus `json:"status"`
+	StartTime json.Uint64  `json:"startTime"`
+	EndTime   json.Uint64  `json:"endTime"`
+	Weight    json.Uint64  `json:"weight"`
+	// The reward the staker will receive if it is rewarded when it stops
+	// staking. Only populated for current stakers.
+	PotentialReward *json.Uint64 `json:"potentialReward,omitempty"`
+	// The reward that was paid to the staker when it stopped staking. Only
+	// populated for completed stakers.
+	Reward *json.Uint64 `json:"reward,omitempty"`
 }
 
-// GetTimestamp returns the current timestamp on chain.
-func (s *Service) GetTimestamp(_ *http.Request, _ *struct{}, reply *GetTimestampReply) error {
+// GetStaker returns the lifecycle of the staker that was added by the
+// transaction with ID [args.TxID].
+func (s *Service) GetStaker(_ *http.Request, args *api.GetTxArgs, reply *GetStakerReply) error {
 	s.vm.ctx.Log.Debug("API called",
 		zap.String("service", "platform"),
-		zap.String("method", "getTimestamp"),
+		zap.String("method", "getStaker"),
+		zap.Stringer("txID", args.TxID),
 	)
 
 	s.vm.ctx.Lock.Lock()
 	defer s.vm.ctx.Lock.Unlock()
 
-	reply.Timestamp = s.vm.state.GetTimestamp()
-	return nil
-}
-
-// GetValidatorsAtArgs is the response from GetValidatorsAt
-type GetValidatorsAtArgs struct {
-	Height   json.Uint64 `json:"height"`
-	SubnetID ids.ID      `json:"subnetID"`
-}
-
-type jsonGetValidatorOutput struct {
-	PublicKey *string     `json:"publicKey"`
-	Weight    json.Uint64 `json:"weight"`
-}
-
-func (v *GetValidatorsAtReply) MarshalJSON() ([]byte, error) {
-	m := make(map[ids.NodeID]*jsonGetValidatorOutput, len(v.Validators))
-	for _, vdr := range v.Validators {
-		vdrJSON := &jsonGetValidatorOutput{
-			Weight: json.Uint64(vdr.Weight),
-		}
+	tx, txStatus, err := s.vm.state.GetTx(args.TxID)
+	if err != nil {
+		return fmt.Errorf("couldn't get tx %s: %w", args.TxID, err)
+	}
+	if txStatus != status.Committed {
+		return fmt.Errorf("%w: %s has status %s", errStakerTxNotCommitted, args.TxID, txStatus)
+	}
+	stakerTx, ok := tx.Unsigned.(txs.Staker)
+	if !ok {
+		return fmt.Errorf("%w: %s has type %T", errNotStakerTx, args.TxID, tx.Unsigned)
+	}
 
-		if vdr.PublicKey != nil {
-			pk, err := formatting.Encode(formatting.HexNC, bls.PublicKeyToBytes(vdr.PublicKey))
-			if err != nil {
-				return nil, err
-			}
-			vdrJSON.PublicKey = &pk
-		}
+	reply.TxID = args.TxID
+	reply.SubnetID = stakerTx.SubnetID()
+	reply.NodeID = stakerTx.NodeID()
 
-		m[vdr.NodeID] = vdrJSON
+	staker, err := s.getStaker(
+		args.TxID,
+		stakerTx,
+		s.vm.state.GetCurrentValidator,
+		s.vm.state.GetCurrentDelegatorIterator,
+	)
+	if err != nil {
+		return err
+	}
+	if staker != nil {
+		potentialReward := json.Uint64(staker.PotentialReward)
+		reply.Status = StakerCurrent
+		reply.StartTime = json.Uint64(staker.StartTime.Unix())
+		reply.EndTime = json.Uint64(staker.EndTime.Unix())
+		reply.Weight = json.Uint64(staker.Weight)
+		reply.PotentialReward = &potentialReward
+		return nil
 	}
-	return stdjson.Marshal(m)
-}
 
-func (v *GetValidatorsAtReply) UnmarshalJSON(b []byte) error {
-	var m map[ids.NodeID]*jsonGetValidatorOutput
-	if err := stdjson.Unmarshal(b, &m); err != nil {
+	// Pending and completed stakers are described by the tx that added them.
+	reply.StartTime = json.Uint64(stakerTx.StartTime().Unix())
+	reply.EndTime = json.Uint64(stakerTx.EndTime().Unix())
+	reply.Weight = json.Uint64(stakerTx.Weight())
+
+	staker, err = s.getStaker(
+		args.TxID,
+		stakerTx,
+		s.vm.state.GetPendingValidator,
+		s.vm.state.GetPendingDelegatorIterator,
+	)
+	if err != nil {
 		return err
 	}
-
-	if m == nil {
-		v.Validators = nil
+	if staker != nil {
+		reply.Status = StakerPending
 		return nil
 	}
 
-	v.Validators = make(map[ids.NodeID]*validators.GetValidatorOutput, len(m))
-	for nodeID, vdrJSON := range m {
-		vdr := &validators.GetValidatorOutput{
-			NodeID: nodeID,
-			Weight: uint64(vdrJSON.Weight),
+	utxos, err := s.vm.state.GetRewardUTXOs(args.TxID)
+	if err != nil {
+		return fmt.Errorf("couldn't get reward UTXOs: %w", err)
+	}
+	var rewardAmount uint64
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(avax.Amounter)
+		if !ok {
+			continue
 		}
-
-		if vdrJSON.PublicKey != nil {
-			pkBytes, err := formatting.Decode(formatting.HexNC, *vdrJSON.PublicKey)
-			if err != nil {
-				return err
-			}
-			vdr.PublicKey, err = bls.PublicKeyFromBytes(pkBytes)
-			if err != nil {
-				return err
-			}
+		rewardAmount, err = safemath.Add64(rewardAmount, out.Amount())
+		if err != nil {
+			return err
 		}
-
-		v.Validators[nodeID] = vdr
 	}
-	return nil
-}
 
-// GetValidatorsAtReply is the response from GetValidatorsAt
-type GetValidatorsAtReply struct {
-	Validators map[ids.NodeID]*validators.GetValidatorOutput
+	jsonRewardAmount := json.Uint64(rewardAmount)
+	reply.Status = StakerCompleted
+	reply.Reward = &jsonRewardAmount
+	return nil
 }
 
-// GetValidatorsAt returns the weights of the validator set of a provided subnet
+// getStaker returns the staker added by [txID] from the staker set described by
+// [getValidator] and [getDelegators], or nil if it isn't in the staker set.
+func (*Service) getStaker(
+	txID ids.ID,
+	stakerTx txs.Staker,
+	getValidator func(subnetID ids.ID, nodeID ids.NodeID) (*state.Staker, error),
+	getDelegators func(subnetID ids.ID, nodeID ids.NodeID) (state.StakerIterator, error),
+) (*state.Staker, error) {
+	subnetID := stakerTx.SubnetID()
+	nodeID := stakerTx.NodeID()
+	if stakerTx.CurrentPriority().IsValidator() {
+		staker, err := getValidator(subnetID, nodeID)
+		switch {
+		case err == database.ErrNotFound:
+			return nil, nil
+		case err != nil:
+			return nil, err
+		case staker.TxID != txID:
+			return nil, nil
+		default:
+			return staker, nil
+		}
+	}
+
+	delegators, err := getDelegators(subnetID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer delegators.Release()
+
+	for delegators.Next() {
+		staker := delegators.Value()
+		if staker.TxID == txID {
+			return staker, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetOwnersArgs are the arguments for calling GetOwners.
+type GetOwnersArgs struct {
+	// Txs that added the stakers whose reward owners should be returned.
+	StakerTxIDs []ids.ID `json:"stakerTxIDs"`
+	// UTXOs, in the form "txID:outputIndex", whose owners should be returned.
+	UTXOIDs []string `json:"utxoIDs"`
+}
+
+// StakerOwners are the owners of the rewards of a staker.
+type StakerOwners struct {
+	TxID ids.ID `json:"txID"`
+	// The owners of the validation and delegation rewards of a validator.
+	// Only populated for validators of the Primary Network and permissionless
+	// subnets.
+	ValidationRewardOwner *platformapi.Owner `json:"validationRewardOwner,omitempty"`
+	DelegationRewardOwner *platformapi.Owner `json:"delegationRewardOwner,omitempty"`
+	// The owner of the rewards of a delegator.
+	RewardOwner *platformapi.Owner `json:"rewardOwner,omitempty"`
+}
+
+// UTXOOwner is the owner of a UTXO.
+type UTXOOwner struct {
+	UTXOID string `json:"utxoID"`
+	// The time until which the UTXO can only be used for staking. Only
+	// populated for stakeable locked UTXOs.
+	StakeableLocktime *json.Uint64 `json:"stakeableLocktime,omitempty"`
+	// The tranches the UTXO vests in. Only populated for vesting UTXOs.
+	VestingSchedule []Tranche          `json:"vestingSchedule,omitempty"`
+	Owner           *platformapi.Owner `json:"owner"`
+}
+
+// Tranche is an amount of a vesting UTXO that can only be used for staking
+// until its locktime.
+type Tranche struct {
+	Locktime json.Uint64 `json:"locktime"`
+	Amount   json.Uint64 `json:"amount"`
+}
+
+// GetOwnersReply is the response from calling GetOwners.
+type GetOwnersReply struct {
+	Stakers []StakerOwners `json:"stakers"`
+	UTXOs   []UTXOOwner    `json:"utxos"`
+}
+
+// GetOwners returns the addresses, threshold, and locktime of the owners of
+// the rewards of the stakers added by [args.StakerTxIDs] and of the UTXOs
+// [args.UTXOIDs].
+func (s *Service) GetOwners(_ *http.Request, args *GetOwnersArgs, reply *GetOwnersReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getOwners"),
+		zap.Int("numStakerTxIDs", len(args.StakerTxIDs)),
+		zap.Int("numUTXOIDs", len(args.UTXOIDs)),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	reply.Stakers = make([]StakerOwners, 0, len(args.StakerTxIDs))
+	for _, txID := range args.StakerTxIDs {
+		owners, err := s.getStakerOwners(txID)
+		if err != nil {
+			return err
+		}
+		reply.Stakers = append(reply.Stakers, *owners)
+	}
+
+	reply.UTXOs = make([]UTXOOwner, 0, len(args.UTXOIDs))
+	for _, utxoIDStr := range args.UTXOIDs {
+		owner, err := s.getUTXOOwner(utxoIDStr)
+		if err != nil {
+			return err
+		}
+		reply.UTXOs = append(reply.UTXOs, *owner)
+	}
+	return nil
+}
+
+func (s *Service) getStakerOwners(txID ids.ID) (*StakerOwners, error) {
+	tx, _, err := s.vm.state.GetTx(txID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get tx %s: %w", txID, err)
+	}
+
+	owners := &StakerOwners{
+		TxID: txID,
+	}
+	switch stakerTx := tx.Unsigned.(type) {
+	case txs.ValidatorTx:
+		owners.ValidationRewardOwner, err = s.getAPIFxOwner(stakerTx.ValidationRewardsOwner())
+		if err != nil {
+			return nil, err
+		}
+		owners.DelegationRewardOwner, err = s.getAPIFxOwner(stakerTx.DelegationRewardsOwner())
+		if err != nil {
+			return nil, err
+		}
+	case txs.DelegatorTx:
+		owners.RewardOwner, err = s.getAPIFxOwner(stakerTx.RewardsOwner())
+		if err != nil {
+			return nil, err
+		}
+	case txs.Staker:
+		// Permissioned subnet validators aren't rewarded.
+	default:
+		return nil, fmt.Errorf("%w: %s has type %T", errNotStakerTx, txID, tx.Unsigned)
+	}
+	return owners, nil
+}
+
+func (s *Service) getUTXOOwner(utxoIDStr string) (*UTXOOwner, error) {
+	utxoID, err := avax.UTXOIDFromString(utxoIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse UTXO ID %q: %w", utxoIDStr, err)
+	}
+	utxo, err := s.vm.state.GetUTXO(utxoID.InputID())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+	}
+
+	owner := &UTXOOwner{
+		UTXOID: utxoID.String(),
+	}
+	out := utxo.Out
+	if lockedOut, ok := out.(*stakeable.LockOut); ok {
+		locktime := json.Uint64(lockedOut.Locktime)
+		owner.StakeableLocktime = &locktime
+		out = lockedOut.TransferableOut
+	}
+	if vestingOut, ok := out.(*stakeable.VestingOut); ok {
+		owner.VestingSchedule = make([]Tranche, len(vestingOut.Tranches))
+		for i, tranche := range vestingOut.Tranches {
+			owner.VestingSchedule[i] = Tranche{
+				Locktime: json.Uint64(tranche.Locktime),
+				Amount:   json.Uint64(tranche.Amount),
+			}
+		}
+		out = vestingOut.TransferableOut
+	}
+	transferOut, ok := out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has output type %T", errUnsupportedOutputType, utxoID, out)
+	}
+	owner.Owner, err = s.getAPIOwner(&transferOut.OutputOwners)
+	return owner, err
+}
+
+// GetRewardHistoryArgs are the arguments for calling GetRewardHistory.
+// Exactly one of [Address] and [NodeID] must be provided.
+type GetRewardHistoryArgs struct {
+	// Address that the rewards were paid to.
+	Address string `json:"address"`
+	// Node ID of the stakers that were rewarded.
+	NodeID ids.NodeID `json:"nodeID"`
+	// Cursor returned by the previous call. If empty, the most recent rewards
+	// are returned.
+	Cursor string      `json:"cursor"`
+	Limit  json.Uint32 `json:"limit"`
+}
+
+// RewardPayout is a reward paid when a staker was removed from the staker
+// set.
+type RewardPayout struct {
+	// ID of the tx that added the rewarded staker.
+	TxID      ids.ID      `json:"txID"`
+	UTXOID    string      `json:"utxoID"`
+	SubnetID  ids.ID      `json:"subnetID"`
+	NodeID    ids.NodeID  `json:"nodeID"`
+	AssetID   ids.ID      `json:"assetID"`
+	Amount    json.Uint64 `json:"amount"`
+	Addresses []string    `json:"addresses"`
+	// Height of the block that paid the reward.
+	Height json.Uint64 `json:"height"`
+	// Chain time when the reward was paid.
+	Timestamp json.Uint64 `json:"timestamp"`
+}
+
+// GetRewardHistoryReply is the response from calling GetRewardHistory.
+type GetRewardHistoryReply struct {
+	// Number of rewards returned
+	NumFetched json.Uint64 `json:"numFetched"`
+	// The rewards, from the most recent to the oldest
+	Rewards []RewardPayout `json:"rewards"`
+	// Cursor to pass to the next call to get the following rewards
+	NextCursor string `json:"nextCursor"`
+}
+
+// GetRewardHistory returns the rewards paid to an address or for the stakers
+// of a node, from the most recent to the oldest. The rewards paid before this
+// node started indexing them can't be returned, so instead of an empty page,
+// an error is returned once they are reached.
+func (s *Service) GetRewardHistory(_ *http.Request, args *GetRewardHistoryArgs, reply *GetRewardHistoryReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getRewardHistory"),
+	)
+
+	if (args.Address == "") == (args.NodeID == ids.EmptyNodeID) {
+		return errInvalidRewardHistoryArgs
+	}
+
+	var cursor []byte
+	if args.Cursor != "" {
+		var err error
+		cursor, err = formatting.Decode(formatting.HexNC, args.Cursor)
+		if err != nil {
+			return fmt.Errorf("couldn't parse cursor %q: %w", args.Cursor, err)
+		}
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || builder.MaxPageSize < limit {
+		limit = builder.MaxPageSize
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	var (
+		payouts []*state.RewardPayout
+		err     error
+	)
+	if args.Address != "" {
+		var addr ids.ShortID
+		addr, err = avax.ParseServiceAddress(s.addrManager, args.Address)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", args.Address, err)
+		}
+		payouts, err = s.vm.state.GetRewardPayoutsByAddress(addr, cursor, limit)
+		if err != nil {
+			return fmt.Errorf("couldn't get reward history: %w", err)
+		}
+	} else {
+		payouts, err = s.vm.state.GetRewardPayoutsByNodeID(args.NodeID, cursor, limit)
+		if err != nil {
+			return fmt.Errorf("couldn't get reward history: %w", err)
+		}
+	}
+
+	reply.NumFetched = json.Uint64(len(payouts))
+	reply.Rewards = make([]RewardPayout, len(payouts))
+	for i, payout := range payouts {
+		addrs := make([]string, len(payout.Addresses))
+		for j, addr := range payout.Addresses {
+			addrs[j], err = s.addrManager.FormatLocalAddress(addr)
+			if err != nil {
+				return fmt.Errorf("couldn't format address %s: %w", addr, err)
+			}
+		}
+		utxoID := avax.UTXOID{
+			TxID:        payout.TxID,
+			OutputIndex: payout.OutputIndex,
+		}
+		reply.Rewards[i] = RewardPayout{
+			TxID:      payout.TxID,
+			UTXOID:    utxoID.String(),
+			SubnetID:  payout.SubnetID,
+			NodeID:    payout.NodeID,
+			AssetID:   payout.AssetID,
+			Amount:    json.Uint64(payout.Amount),
+			Addresses: addrs,
+			Height:    json.Uint64(payout.Height),
+			Timestamp: json.Uint64(payout.Timestamp),
+		}
+	}
+
+	if len(payouts) == 0 {
+		reply.NextCursor = args.Cursor
+		return nil
+	}
+	reply.NextCursor, err = formatting.Encode(formatting.HexNC, payouts[len(payouts)-1].Cursor())
+	return err
+}
+
+// GetTimestampReply is the response from GetTimestamp
+type GetTimestampReply struct {
+	// Current timestamp
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GetTimestamp returns the current timestamp on chain.
+func (s *Service) GetTimestamp(_ *http.Request, _ *struct{}, reply *GetTimestampReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getTimestamp"),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	reply.Timestamp = s.vm.state.GetTimestamp()
+	return nil
+}
+
+// GetChainTimeReply is the response from GetChainTime.
+// Durations are in nanoseconds.
+type GetChainTimeReply struct {
+	// Current chain time
+	Timestamp time.Time `json:"timestamp"`
+	// Current time of this node's clock
+	LocalTime time.Time `json:"localTime"`
+	// Number of recently accepted blocks that the estimates below are based on
+	Samples json.Uint64 `json:"samples"`
+	// Estimated amount of time that this node's clock is ahead of the clocks
+	// of the nodes proposing blocks. Since it includes the time it takes to
+	// accept a block, it's an upper bound.
+	Skew time.Duration `json:"skew"`
+	// Bounds of the 95% confidence interval of [Skew]
+	SkewLowerBound time.Duration `json:"skewLowerBound"`
+	SkewUpperBound time.Duration `json:"skewUpperBound"`
+	// Mean chain time between recently accepted blocks, or 0 if there aren't
+	// enough samples to estimate it
+	BlockInterval time.Duration `json:"blockInterval"`
+	// Next time a staker will be added to or removed from the validator set
+	NextStakerChangeTime time.Time `json:"nextStakerChangeTime"`
+	// Estimated number of blocks until the chain time reaches
+	// [NextStakerChangeTime], or 0 if [BlockInterval] is 0
+	ExpectedBlocks json.Uint64 `json:"expectedBlocks"`
+}
+
+// GetChainTime returns the current chain time along with estimates of how it
+// relates to this node's clock, so that transactions can be timed against
+// the next staker change.
+//
+// The estimates are based on the blocks accepted since this node finished
+// bootstrapping.
+func (s *Service) GetChainTime(_ *http.Request, _ *struct{}, reply *GetChainTimeReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getChainTime"),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	nextStakerChangeTime, err := executor.GetNextStakerChangeTime(s.vm.state)
+	if err != nil {
+		return fmt.Errorf("couldn't get next staker change time: %w", err)
+	}
+
+	stats := s.vm.manager.ChainTimeStats()
+	reply.Timestamp = s.vm.state.GetTimestamp()
+	reply.LocalTime = s.vm.clock.Time()
+	reply.Samples = json.Uint64(stats.Samples)
+	reply.Skew = stats.Skew
+	reply.SkewLowerBound = stats.Skew - stats.SkewMargin
+	reply.SkewUpperBound = stats.Skew + stats.SkewMargin
+	reply.BlockInterval = stats.BlockInterval
+	reply.NextStakerChangeTime = nextStakerChangeTime
+
+	untilChange := nextStakerChangeTime.Sub(reply.Timestamp)
+	if stats.BlockInterval > 0 && untilChange > 0 {
+		expectedBlocks := (untilChange + stats.BlockInterval - 1) / stats.BlockInterval
+		reply.ExpectedBlocks = json.Uint64(expectedBlocks)
+	}
+	return nil
+}
+
+const (
+	defaultCheckForksPeers = 5
+	maxCheckForksPeers     = 32
+)
+
+// CheckForksArgs are the arguments for calling CheckForks
+type CheckForksArgs struct {
+	// Number of primary network validators to compare this node with. If 0,
+	// [defaultCheckForksPeers] are sampled.
+	NumPeers json.Uint32 `json:"numPeers"`
+}
+
+// ForkCheckPeer is the comparison of a validator's P-chain with this node's
+type ForkCheckPeer struct {
+	NodeID ids.NodeID  `json:"nodeID"`
+	Weight json.Uint64 `json:"weight"`
+	// Error is non-empty if the validator couldn't be compared
+	Error              string      `json:"error,omitempty"`
+	LastAcceptedHeight json.Uint64 `json:"lastAcceptedHeight"`
+	LastAcceptedID     ids.ID      `json:"lastAcceptedID"`
+	// Diverged is true if the validator accepted a different block than this
+	// node at [FirstDivergingHeight]
+	Diverged             bool        `json:"diverged"`
+	FirstDivergingHeight json.Uint64 `json:"firstDivergingHeight"`
+	// ChecksumMismatch is true if the validator accepted the same last block
+	// as this node, but its UTXOs have a different checksum
+	ChecksumMismatch bool `json:"checksumMismatch"`
+}
+
+// CheckForksReply is the response from calling CheckForks
+type CheckForksReply struct {
+	LastAcceptedHeight json.Uint64 `json:"lastAcceptedHeight"`
+	LastAcceptedID     ids.ID      `json:"lastAcceptedID"`
+	// Checksum of this node's UTXOs, or empty if checksums aren't enabled
+	Checksum ids.ID          `json:"checksum"`
+	Peers    []ForkCheckPeer `json:"peers"`
+	// Total weight of the validators that were compared
+	RespondedWeight json.Uint64 `json:"respondedWeight"`
+	// Total weight of the validators that diverged from this node or whose
+	// checksum doesn't match
+	DivergedWeight json.Uint64 `json:"divergedWeight"`
+	// Forked is true if more than half of [RespondedWeight] diverged
+	Forked bool `json:"forked"`
+	// Lowest height at which a validator diverged, if any diverged
+	FirstDivergingHeight json.Uint64 `json:"firstDivergingHeight"`
+}
+
+// CheckForks samples primary network validators for the blocks they accepted
+// and compares them with the blocks this node accepted, to detect whether
+// this node is on a fork of the P-chain. For each validator that diverged,
+// the first height at which it accepted a different block is reported.
+func (s *Service) CheckForks(r *http.Request, args *CheckForksArgs, reply *CheckForksReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "checkForks"),
+	)
+
+	numPeers := int(args.NumPeers)
+	switch {
+	case numPeers == 0:
+		numPeers = defaultCheckForksPeers
+	case numPeers > maxCheckForksPeers:
+		return fmt.Errorf("%w: %d > %d", errTooManyPeers, numPeers, maxCheckForksPeers)
+	}
+
+	nodeIDs, err := s.sampleForkCheckPeers(numPeers)
+	if err != nil {
+		return err
+	}
+
+	// [s.vm.ctx.Lock] isn't held while waiting for the peers.
+	results := s.vm.forkChecker.Check(r.Context(), nodeIDs)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	height, blkID, err := forkCheckChain{s.vm.state}.LastAccepted()
+	if err != nil {
+		return err
+	}
+	reply.LastAcceptedHeight = json.Uint64(height)
+	reply.LastAcceptedID = blkID
+	reply.Checksum = s.vm.state.Checksum()
+	reply.Peers = make([]ForkCheckPeer, len(results))
+	var (
+		respondedWeight uint64
+		divergedWeight  uint64
+		anyDiverged     bool
+	)
+	for i, result := range results {
+		peer := ForkCheckPeer{
+			NodeID:               result.NodeID,
+			Weight:               json.Uint64(s.vm.Validators.GetWeight(constants.PrimaryNetworkID, result.NodeID)),
+			LastAcceptedHeight:   json.Uint64(result.LastAcceptedHeight),
+			LastAcceptedID:       result.LastAcceptedID,
+			Diverged:             result.Diverged,
+			FirstDivergingHeight: json.Uint64(result.FirstDivergingHeight),
+			ChecksumMismatch:     result.ChecksumMismatch,
+		}
+		reply.Peers[i] = peer
+		if result.Err != nil {
+			reply.Peers[i].Error = result.Err.Error()
+			continue
+		}
+
+		respondedWeight, err = safemath.Add64(respondedWeight, uint64(peer.Weight))
+		if err != nil {
+			return err
+		}
+		if !peer.Diverged && !peer.ChecksumMismatch {
+			continue
+		}
+		divergedWeight, err = safemath.Add64(divergedWeight, uint64(peer.Weight))
+		if err != nil {
+			return err
+		}
+		if peer.Diverged && (!anyDiverged || peer.FirstDivergingHeight < reply.FirstDivergingHeight) {
+			anyDiverged = true
+			reply.FirstDivergingHeight = peer.FirstDivergingHeight
+		}
+	}
+	reply.RespondedWeight = json.Uint64(respondedWeight)
+	reply.DivergedWeight = json.Uint64(divergedWeight)
+	reply.Forked = divergedWeight > respondedWeight/2
+	return nil
+}
+
+// sampleForkCheckPeers returns up to [numPeers] primary network validators
+// other than this node, sampled uniformly.
+func (s *Service) sampleForkCheckPeers(numPeers int) ([]ids.NodeID, error) {
+	validatorIDs := s.vm.Validators.GetValidatorIDs(constants.PrimaryNetworkID)
+	peerIDs := make([]ids.NodeID, 0, len(validatorIDs))
+	for _, nodeID := range validatorIDs {
+		if nodeID != s.vm.ctx.NodeID {
+			peerIDs = append(peerIDs, nodeID)
+		}
+	}
+	if numPeers > len(peerIDs) {
+		numPeers = len(peerIDs)
+	}
+
+	peerSampler := sampler.NewUniform()
+	peerSampler.Initialize(uint64(len(peerIDs)))
+	indices, err := peerSampler.Sample(numPeers)
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := make([]ids.NodeID, len(indices))
+	for i, index := range indices {
+		nodeIDs[i] = peerIDs[index]
+	}
+	return nodeIDs, nil
+}
+
+// GetValidatorsAtArgs is the response from GetValidatorsAt
+type GetValidatorsAtArgs struct {
+	Height   json.Uint64 `json:"height"`
+	SubnetID ids.ID      `json:"subnetID"`
+}
+
+type jsonGetValidatorOutput struct {
+	PublicKey *string     `json:"publicKey"`
+	Weight    json.Uint64 `json:"weight"`
+}
+
+func (v *GetValidatorsAtReply) MarshalJSON() ([]byte, error) {
+	m := make(map[ids.NodeID]*jsonGetValidatorOutput, len(v.Validators))
+	for _, vdr := range v.Validators {
+		vdrJSON := &jsonGetValidatorOutput{
+			Weight: json.Uint64(vdr.Weight),
+		}
+
+		if vdr.PublicKey != nil {
+			pk, err := formatting.Encode(formatting.HexNC, bls.PublicKeyToBytes(vdr.PublicKey))
+			if err != nil {
+				return nil, err
+			}
+			vdrJSON.PublicKey = &pk
+		}
+
+		m[vdr.NodeID] = vdrJSON
+	}
+	return stdjson.Marshal(m)
+}
+
+func (v *GetValidatorsAtReply) UnmarshalJSON(b []byte) error {
+	var m map[ids.NodeID]*jsonGetValidatorOutput
+	if err := stdjson.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	if m == nil {
+		v.Validators = nil
+		return nil
+	}
+
+	v.Validators = make(map[ids.NodeID]*validators.GetValidatorOutput, len(m))
+	for nodeID, vdrJSON := range m {
+		vdr := &validators.GetValidatorOutput{
+			NodeID: nodeID,
+			Weight: uint64(vdrJSON.Weight),
+		}
+
+		if vdrJSON.PublicKey != nil {
+			pkBytes, err := formatting.Decode(formatting.HexNC, *vdrJSON.PublicKey)
+			if err != nil {
+				return err
+			}
+			vdr.PublicKey, err = bls.PublicKeyFromBytes(pkBytes)
+			if err != nil {
+				return err
+			}
+		}
+
+		v.Validators[nodeID] = vdr
+	}
+	return nil
+}
+
+// GetValidatorsAtReply is the response from GetValidatorsAt
+type GetValidatorsAtReply struct {
+	Validators map[ids.NodeID]*validators.GetValidatorOutput
+}
+
+// GetValidatorsAt returns the weights of the validator set of a provided subnet
 // at the specified height.
 func (s *Service) GetValidatorsAt(r *http.Request, args *GetValidatorsAtArgs, reply *GetValidatorsAtReply) error {
 	height := uint64(args.Height)
@@ -2691,6 +3717,296 @@ func (s *Service) GetValidatorsAt(r *http.Request, args *GetValidatorsAtArgs, re
 	return nil
 }
 
+// GetCometBFTValidatorSetArgs are the arguments for calling
+// GetCometBFTValidatorSet
+type GetCometBFTValidatorSetArgs struct {
+	// SubnetID of the validator set. Defaults to the primary network.
+	SubnetID ids.ID `json:"subnetID"`
+	// Height of the validator set. Defaults to the current height.
+	Height *json.Uint64 `json:"height"`
+}
+
+// GetCometBFTValidatorSetReply is the response from calling
+// GetCometBFTValidatorSet
+type GetCometBFTValidatorSetReply struct {
+	// Height of the validator set
+	Height json.Uint64 `json:"height"`
+	// Validators in the format and order used by CometBFT
+	Validators       []CometBFTValidator `json:"validators"`
+	TotalVotingPower json.Uint64         `json:"totalVotingPower"`
+	// Hash is the upper case hex encoding of the CometBFT hash of [Validators]
+	Hash string `json:"hash"`
+}
+
+// GetCometBFTValidatorSet returns the validator set of a subnet in the format
+// used by CometBFT light clients.
+func (s *Service) GetCometBFTValidatorSet(r *http.Request, args *GetCometBFTValidatorSetArgs, reply *GetCometBFTValidatorSetReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getCometBFTValidatorSet"),
+		zap.Stringer("subnetID", args.SubnetID),
+	)
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	ctx := r.Context()
+	var height uint64
+	if args.Height != nil {
+		height = uint64(*args.Height)
+	} else {
+		var err error
+		height, err = s.vm.GetCurrentHeight(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get current height: %w", err)
+		}
+	}
+
+	vdrs, err := s.vm.GetValidatorSet(ctx, height, args.SubnetID)
+	if err != nil {
+		return fmt.Errorf("failed to get validator set: %w", err)
+	}
+	vdrSet, err := NewCometBFTValidatorSet(vdrs)
+	if err != nil {
+		return err
+	}
+
+	reply.Height = json.Uint64(height)
+	reply.Validators = vdrSet.Validators
+	reply.TotalVotingPower = json.Uint64(vdrSet.TotalVotingPower)
+	reply.Hash = strings.ToUpper(hex.EncodeToString(vdrSet.Hash))
+	return nil
+}
+
+// GetUptimeAttestationArgs are the arguments for calling GetUptimeAttestation
+type GetUptimeAttestationArgs struct {
+	// SubnetID of the validators to attest to. Defaults to the primary network.
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// GetUptimeAttestationReply is the response from calling GetUptimeAttestation
+type GetUptimeAttestationReply struct {
+	// Attestation is the serialized UptimeAttestation
+	Attestation string `json:"attestation"`
+	// UnsignedMessage is the warp message whose payload is the
+	// UptimeAttestationPayload of [Attestation]
+	UnsignedMessage string `json:"unsignedMessage"`
+	// PublicKey is the BLS public key of this node
+	PublicKey string `json:"publicKey"`
+	// Signature is the BLS signature of this node over [UnsignedMessage]
+	Signature string `json:"signature"`
+	// Encoding of all the above fields
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetUptimeAttestation returns this node's observed uptimes of the current
+// validators of a subnet, signed with this node's BLS key. It is only served if
+// uptime attestation is enabled in the execution config.
+func (s *Service) GetUptimeAttestation(_ *http.Request, args *GetUptimeAttestationArgs, reply *GetUptimeAttestationReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getUptimeAttestation"),
+		zap.Stringer("subnetID", args.SubnetID),
+	)
+
+	if !s.vm.uptimeAttestationEnabled {
+		return errAttestationDisabled
+	}
+
+	// Only attest to uptimes that we have been actively tracking.
+	if args.SubnetID != constants.PrimaryNetworkID && !s.vm.TrackedSubnets.Contains(args.SubnetID) {
+		return fmt.Errorf("%w: %s", errUptimesNotTracked, args.SubnetID)
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	attestation := &UptimeAttestation{
+		NodeID:    s.vm.ctx.NodeID,
+		SubnetID:  args.SubnetID,
+		Timestamp: s.vm.clock.Unix(),
+	}
+
+	stakers, err := s.vm.state.GetCurrentStakerIterator()
+	if err != nil {
+		return err
+	}
+	defer stakers.Release()
+
+	for stakers.Next() {
+		staker := stakers.Value()
+		if staker.SubnetID != args.SubnetID || !staker.Priority.IsCurrentValidator() {
+			continue
+		}
+
+		uptime, err := s.vm.uptimeManager.CalculateUptimePercentFrom(staker.NodeID, staker.SubnetID, staker.StartTime)
+		if err != nil {
+			return fmt.Errorf("failed to calculate uptime of %s: %w", staker.NodeID, err)
+		}
+		attestation.Uptimes = append(attestation.Uptimes, ValidatorUptime{
+			NodeID:    staker.NodeID,
+			StartTime: uint64(staker.StartTime.Unix()),
+			Uptime:    uint32(uptime * UptimeDenominator),
+		})
+	}
+	utils.Sort(attestation.Uptimes)
+
+	attestationBytes, err := attestation.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to marshal uptime attestation: %w", err)
+	}
+	msg, err := NewUptimeAttestationMessage(s.vm.ctx.NetworkID, s.vm.ctx.ChainID, s.vm.ctx.NodeID, attestationBytes)
+	if err != nil {
+		return fmt.Errorf("failed to create uptime attestation message: %w", err)
+	}
+	signature, err := s.vm.ctx.WarpSigner.Sign(msg)
+	if err != nil {
+		return fmt.Errorf("failed to sign uptime attestation: %w", err)
+	}
+
+	reply.Encoding = formatting.HexNC
+	reply.Attestation, err = formatting.Encode(reply.Encoding, attestationBytes)
+	if err != nil {
+		return fmt.Errorf("couldn't encode uptime attestation: %w", err)
+	}
+	reply.UnsignedMessage, err = formatting.Encode(reply.Encoding, msg.Bytes())
+	if err != nil {
+		return fmt.Errorf("couldn't encode uptime attestation message: %w", err)
+	}
+	reply.PublicKey, err = formatting.Encode(reply.Encoding, bls.PublicKeyToBytes(s.vm.ctx.PublicKey))
+	if err != nil {
+		return fmt.Errorf("couldn't encode public key: %w", err)
+	}
+	reply.Signature, err = formatting.Encode(reply.Encoding, signature)
+	if err != nil {
+		return fmt.Errorf("couldn't encode signature: %w", err)
+	}
+	return nil
+}
+
+// StakePosition is the part of a staker on the Primary Network that belongs to
+// an address.
+type StakePosition struct {
+	// TxID of the staker
+	TxID   ids.ID     `json:"txID"`
+	NodeID ids.NodeID `json:"nodeID"`
+	// Validator is true if the staker is a validator rather than a delegator
+	Validator bool `json:"validator"`
+	// Pending is true if the staker hasn't started staking yet
+	Pending   bool        `json:"pending"`
+	StartTime json.Uint64 `json:"startTime"`
+	EndTime   json.Uint64 `json:"endTime"`
+	// Staked is the amount of nAVAX staked by the address
+	Staked json.Uint64 `json:"staked"`
+	// PotentialReward is the amount of nAVAX the address will be rewarded if
+	// the staker is rewarded, including delegation fees. It's 0 for pending
+	// stakers because their rewards aren't known until they start staking.
+	PotentialReward json.Uint64 `json:"potentialReward"`
+}
+
+// StakeSummary is the stake of an address on the Primary Network
+type StakeSummary struct {
+	// ActiveStake is the amount of nAVAX staked by current stakers
+	ActiveStake json.Uint64 `json:"activeStake"`
+	// PendingStake is the amount of nAVAX staked by pending stakers
+	PendingStake json.Uint64 `json:"pendingStake"`
+	// PotentialReward is the sum of the potential rewards of [Positions]
+	PotentialReward json.Uint64     `json:"potentialReward"`
+	Positions       []StakePosition `json:"positions"`
+}
+
+// GetStakeSummaryArgs are the arguments for calling GetStakeSummary
+type GetStakeSummaryArgs struct {
+	api.JSONAddresses
+}
+
+// GetStakeSummaryReply is the response from calling GetStakeSummary
+type GetStakeSummaryReply struct {
+	// Address, as given in the arguments --> stake summary of the address
+	Summaries map[string]*StakeSummary `json:"summaries"`
+}
+
+// GetStakeSummary returns the stake of each address in [args.Addresses] on the
+// Primary Network, along with the rewards that each address is expected to
+// receive.
+//
+// Stake is attributed to every given address that owns the staked output. A
+// reward is attributed to every given address that owns the rewards output.
+// There is no continuous staking in this VM, so every position ends at its
+// staker's end time.
+func (s *Service) GetStakeSummary(_ *http.Request, args *GetStakeSummaryArgs, reply *GetStakeSummaryReply) error {
+	s.vm.ctx.Log.Debug("API called",
+		zap.String("service", "platform"),
+		zap.String("method", "getStakeSummary"),
+	)
+
+	if len(args.Addresses) > maxGetStakeAddrs {
+		return fmt.Errorf("%d addresses provided but this method can take at most %d", len(args.Addresses), maxGetStakeAddrs)
+	}
+
+	addrs := make([]ids.ShortID, len(args.Addresses))
+	for i, addrStr := range args.Addresses {
+		addr, err := avax.ParseServiceAddress(s.addrManager, addrStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+		}
+		addrs[i] = addr
+	}
+
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	summarizer := &stakeSummarizer{
+		service:   s,
+		summaries: make(map[ids.ShortID]*StakeSummary, len(addrs)),
+		positions: make(map[ids.ShortID]map[ids.ID]int, len(addrs)),
+	}
+	for _, addr := range addrs {
+		summarizer.summaries[addr] = &StakeSummary{
+			Positions: []StakePosition{},
+		}
+		summarizer.positions[addr] = make(map[ids.ID]int)
+	}
+
+	currentStakerIterator, err := s.vm.state.GetCurrentStakerIterator()
+	if err != nil {
+		return err
+	}
+	defer currentStakerIterator.Release()
+
+	for currentStakerIterator.Next() {
+		staker := currentStakerIterator.Value()
+		if staker.SubnetID != constants.PrimaryNetworkID {
+			continue
+		}
+		if err := summarizer.addCurrentStaker(staker); err != nil {
+			return err
+		}
+	}
+
+	pendingStakerIterator, err := s.vm.state.GetPendingStakerIterator()
+	if err != nil {
+		return err
+	}
+	defer pendingStakerIterator.Release()
+
+	for pendingStakerIterator.Next() {
+		staker := pendingStakerIterator.Value()
+		if staker.SubnetID != constants.PrimaryNetworkID {
+			continue
+		}
+		if err := summarizer.addStake(staker, true /*=pending*/); err != nil {
+			return err
+		}
+	}
+
+	reply.Summaries = make(map[string]*StakeSummary, len(addrs))
+	for i, addrStr := range args.Addresses {
+		reply.Summaries[addrStr] = summarizer.summaries[addrs[i]]
+	}
+	return nil
+}
+
 func (s *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, response *api.GetBlockResponse) error {
 	s.vm.ctx.Log.Debug("API called",
 		zap.String("service", "platform"),
@@ -2797,6 +4113,16 @@ func (s *Service) getAPIOwner(owner *secp256k1fx.OutputOwners) (*platformapi.Own
 	return apiOwner, nil
 }
 
+// getAPIFxOwner is getAPIOwner for owners that haven't been cast to
+// [*secp256k1fx.OutputOwners] yet.
+func (s *Service) getAPIFxOwner(owner fx.Owner) (*platformapi.Owner, error) {
+	outputOwners, ok := owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errUnsupportedOwnerType, owner)
+	}
+	return s.getAPIOwner(outputOwners)
+}
+
 // Takes in a staker and a set of addresses
 // Returns:
 // 1) The total amount staked by addresses in [addrs]
@@ -2812,8 +4138,11 @@ func getStakeHelper(tx *txs.Tx, addrs set.Set[ids.ShortID], totalAmountStaked ma
 	// Go through all of the staked outputs
 	for _, output := range stake {
 		out := output.Out
-		if lockedOut, ok := out.(*stakeable.LockOut); ok {
-			// This output can only be used for staking until [stakeOnlyUntil]
+		// This output can only be used for staking until its locktime
+		switch lockedOut := out.(type) {
+		case *stakeable.LockOut:
+			out = lockedOut.TransferableOut
+		case *stakeable.VestingOut:
 			out = lockedOut.TransferableOut
 		}
 		secpOut, ok := out.(*secp256k1fx.TransferOutput)
@@ -2848,3 +4177,157 @@ func getStakeHelper(tx *txs.Tx, addrs set.Set[ids.ShortID], totalAmountStaked ma
 	}
 	return stakedOuts
 }
+
+// stakeSummarizer aggregates the stake and rewards of a set of addresses.
+type stakeSummarizer struct {
+	service *Service
+	// Address --> stake summary of the address
+	summaries map[ids.ShortID]*StakeSummary
+	// Address --> staker TxID --> index of the position in the summary of the
+	// address
+	positions map[ids.ShortID]map[ids.ID]int
+}
+
+// addCurrentStaker adds the stake of [staker] and its potential rewards.
+func (ss *stakeSummarizer) addCurrentStaker(staker *state.Staker) error {
+	if err := ss.addStake(staker, false /*=pending*/); err != nil {
+		return err
+	}
+
+	attr, err := ss.service.loadStakerTxAttributes(staker.TxID)
+	if err != nil {
+		return err
+	}
+
+	if staker.Priority.IsValidator() {
+		if err := ss.addReward(staker, attr.validationRewardsOwner, staker.PotentialReward); err != nil {
+			return err
+		}
+
+		// Delegation fees that have already been accrued to the validator.
+		delegateeReward, err := ss.service.vm.state.GetDelegateeReward(staker.SubnetID, staker.NodeID)
+		if err != nil {
+			return err
+		}
+		return ss.addReward(staker, attr.delegationRewardsOwner, delegateeReward)
+	}
+
+	validator, err := ss.service.vm.state.GetCurrentValidator(staker.SubnetID, staker.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validator of delegator %s: %w", staker.TxID, err)
+	}
+	validatorAttr, err := ss.service.loadStakerTxAttributes(validator.TxID)
+	if err != nil {
+		return err
+	}
+
+	delegateeReward, delegatorReward := reward.Split(staker.PotentialReward, validatorAttr.shares)
+	if err := ss.addReward(staker, attr.rewardsOwner, delegatorReward); err != nil {
+		return err
+	}
+	return ss.addReward(validator, validatorAttr.delegationRewardsOwner, delegateeReward)
+}
+
+// addStake adds the stake of [staker] to the addresses that own it.
+func (ss *stakeSummarizer) addStake(staker *state.Staker, pending bool) error {
+	tx, _, err := ss.service.vm.state.GetTx(staker.TxID)
+	if err != nil {
+		return err
+	}
+	stakerTx, ok := tx.Unsigned.(txs.PermissionlessStaker)
+	if !ok {
+		return nil
+	}
+
+	for _, output := range stakerTx.Stake() {
+		out := output.Out
+		switch lockedOut := out.(type) {
+		case *stakeable.LockOut:
+			out = lockedOut.TransferableOut
+		case *stakeable.VestingOut:
+			out = lockedOut.TransferableOut
+		}
+		secpOut, ok := out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			continue
+		}
+
+		for _, addr := range set.Of(secpOut.Addrs...).List() {
+			summary, ok := ss.summaries[addr]
+			if !ok {
+				continue
+			}
+
+			position := ss.getPosition(addr, staker, pending)
+			newStaked, err := safemath.Add64(uint64(position.Staked), secpOut.Amt)
+			if err != nil {
+				return err
+			}
+			position.Staked = json.Uint64(newStaked)
+
+			total := &summary.ActiveStake
+			if pending {
+				total = &summary.PendingStake
+			}
+			newTotal, err := safemath.Add64(uint64(*total), secpOut.Amt)
+			if err != nil {
+				return err
+			}
+			*total = json.Uint64(newTotal)
+		}
+	}
+	return nil
+}
+
+// addReward adds [amount] to the potential rewards of the addresses in
+// [owner] from the current staker [staker].
+func (ss *stakeSummarizer) addReward(staker *state.Staker, owner fx.Owner, amount uint64) error {
+	if amount == 0 {
+		return nil
+	}
+	outputOwners, ok := owner.(*secp256k1fx.OutputOwners)
+	if !ok {
+		return nil
+	}
+
+	for _, addr := range set.Of(outputOwners.Addrs...).List() {
+		summary, ok := ss.summaries[addr]
+		if !ok {
+			continue
+		}
+
+		position := ss.getPosition(addr, staker, false /*=pending*/)
+		newReward, err := safemath.Add64(uint64(position.PotentialReward), amount)
+		if err != nil {
+			return err
+		}
+		position.PotentialReward = json.Uint64(newReward)
+
+		newTotal, err := safemath.Add64(uint64(summary.PotentialReward), amount)
+		if err != nil {
+			return err
+		}
+		summary.PotentialReward = json.Uint64(newTotal)
+	}
+	return nil
+}
+
+// getPosition returns the position of [addr] in [staker], creating it if it
+// doesn't exist yet.
+func (ss *stakeSummarizer) getPosition(addr ids.ShortID, staker *state.Staker, pending bool) *StakePosition {
+	summary := ss.summaries[addr]
+	if i, ok := ss.positions[addr][staker.TxID]; ok {
+		return &summary.Positions[i]
+	}
+
+	ss.positions[addr][staker.TxID] = len(summary.Positions)
+	summary.Positions = append(summary.Positions, StakePosition{
+		TxID:      staker.TxID,
+		NodeID:    staker.NodeID,
+		Validator: staker.Priority.IsValidator(),
+		Pending:   pending,
+		StartTime: json.Uint64(staker.StartTime.Unix()),
+		EndTime:   json.Uint64(staker.EndTime.Unix()),
+	})
+	return &summary.Positions[len(summary.Positions)-1]
+}
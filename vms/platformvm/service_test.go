@@ -5,15 +5,20 @@ package platformvm
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	stdjson "encoding/json"
 
+	"github.com/gorilla/rpc/v2/json2"
+
 	"github.com/stretchr/testify/require"
 
 	"go.uber.org/mock/gomock"
@@ -33,13 +38,18 @@ import (
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
 	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	vmkeystore "github.com/ava-labs/avalanchego/vms/components/keystore"
@@ -86,6 +96,9 @@ func defaultService(t *testing.T) (*Service, *mutableSharedMemory) {
 		stakerAttributesCache: &cache.LRU[ids.ID, *stakerAttributes]{
 			Size: stakerAttributesCacheSize,
 		},
+		idempotencyKeys: &cache.LRU[string, *idempotentTx]{
+			Size: idempotencyKeysCacheSize,
+		},
 	}, mutableSharedMemory
 }
 
@@ -388,6 +401,211 @@ func TestGetTx(t *testing.T) {
 	}
 }
 
+func TestIssueTxs(t *testing.T) {
+	tests := []struct {
+		name           string
+		atomic         bool
+		expectedIssued bool
+	}{
+		{
+			name:           "non-atomic",
+			atomic:         false,
+			expectedIssued: true,
+		},
+		{
+			name:           "atomic",
+			atomic:         true,
+			expectedIssued: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			service, _ := defaultService(t)
+			defer func() {
+				service.vm.ctx.Lock.Lock()
+				require.NoError(service.vm.Shutdown(context.Background()))
+				service.vm.ctx.Lock.Unlock()
+			}()
+
+			service.vm.ctx.Lock.Lock()
+			tx, err := service.vm.txBuilder.NewCreateChainTx(
+				testSubnet1.ID(),
+				[]byte{},
+				constants.AVMID,
+				[]ids.ID{},
+				"chain name",
+				[]*secp256k1.PrivateKey{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+				keys[0].PublicKey().Address(), // change addr
+			)
+			require.NoError(err)
+			service.vm.ctx.Lock.Unlock()
+
+			txStr, err := formatting.Encode(formatting.Hex, tx.Bytes())
+			require.NoError(err)
+
+			args := IssueTxsArgs{
+				Txs: []string{
+					txStr,
+					"0x00", // unparsable tx
+				},
+				Encoding: formatting.Hex,
+				Atomic:   test.atomic,
+			}
+			reply := IssueTxsReply{}
+			require.NoError(service.IssueTxs(nil, &args, &reply))
+			require.Len(reply.Results, 2)
+			require.Equal(tx.ID(), reply.Results[0].TxID)
+			require.Equal(test.expectedIssued, reply.Results[0].Error == "")
+			require.Equal(ids.Empty, reply.Results[1].TxID)
+			require.NotEmpty(reply.Results[1].Error)
+
+			service.vm.ctx.Lock.Lock()
+			require.Equal(test.expectedIssued, service.vm.Builder.Has(tx.ID()))
+			service.vm.ctx.Lock.Unlock()
+		})
+	}
+}
+
+func TestIssueTxIdempotencyKey(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	service.vm.ctx.Lock.Lock()
+	newChainTx := func(name string) string {
+		tx, err := service.vm.txBuilder.NewCreateChainTx(
+			testSubnet1.ID(),
+			[]byte{},
+			constants.AVMID,
+			[]ids.ID{},
+			name,
+			[]*secp256k1.PrivateKey{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			keys[0].PublicKey().Address(), // change addr
+		)
+		require.NoError(err)
+
+		txStr, err := formatting.Encode(formatting.Hex, tx.Bytes())
+		require.NoError(err)
+		return txStr
+	}
+	txStr := newChainTx("chain name")
+	otherTxStr := newChainTx("other chain name")
+	service.vm.ctx.Lock.Unlock()
+
+	args := &IssueTxArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		},
+		IdempotencyKey: "key",
+	}
+	reply := IssueTxReply{}
+	require.NoError(service.IssueTx(nil, args, &reply))
+	require.Nil(reply.Status)
+	txID := reply.TxID
+
+	// Retrying with the same key reports the status of the original tx
+	// rather than a duplicate tx error.
+	reply = IssueTxReply{}
+	require.NoError(service.IssueTx(nil, args, &reply))
+	require.Equal(txID, reply.TxID)
+	require.NotNil(reply.Status)
+	require.Equal(status.Processing, *reply.Status)
+
+	// The key can't be used to issue a different tx.
+	err := service.IssueTx(nil, &IssueTxArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       otherTxStr,
+			Encoding: formatting.Hex,
+		},
+		IdempotencyKey: "key",
+	}, &IssueTxReply{})
+	var jsonErr *json2.Error
+	require.ErrorAs(err, &jsonErr)
+	require.Equal(json2.E_INVALID_REQ, jsonErr.Code)
+	require.Equal(&IdempotencyKeyReusedError{
+		IdempotencyKey: "key",
+		TxID:           txID,
+	}, jsonErr.Data)
+
+	// Once the retention window has passed, the key is forgotten.
+	service.vm.ctx.Lock.Lock()
+	service.vm.clock.Set(service.vm.clock.Time().Add(idempotencyKeyRetention + time.Second))
+	service.vm.ctx.Lock.Unlock()
+
+	reply = IssueTxReply{}
+	require.NoError(service.IssueTx(nil, args, &reply))
+	require.Equal(txID, reply.TxID)
+	require.Nil(reply.Status)
+
+	args.IdempotencyKey = string(make([]byte, maxIdempotencyKeyLen+1))
+	err = service.IssueTx(nil, args, &IssueTxReply{})
+	require.ErrorIs(err, errIdempotencyKeyTooLong)
+}
+
+func TestIssueTxRejection(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	service.vm.ctx.Lock.Lock()
+	startTime := service.vm.clock.Time().Add(txexecutor.SyncBound)
+	tx, err := service.vm.txBuilder.NewAddValidatorTx(
+		service.vm.MinValidatorStake-1, // stake below the minimum
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(defaultMinStakingDuration).Unix()),
+		ids.GenerateTestNodeID(),
+		ids.GenerateTestShortID(),
+		reward.PercentDenominator,
+		[]*secp256k1.PrivateKey{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+	service.vm.ctx.Lock.Unlock()
+
+	txStr, err := formatting.Encode(formatting.Hex, tx.Bytes())
+	require.NoError(err)
+
+	err = service.IssueTx(nil, &IssueTxArgs{
+		FormattedTx: api.FormattedTx{
+			Tx:       txStr,
+			Encoding: formatting.Hex,
+		},
+	}, &IssueTxReply{})
+	rejection, ok := GetRejection(err)
+	require.True(ok)
+	require.Equal(txexecutor.RejectionWeightTooSmall, rejection.Code)
+
+	// The rejection is also reported by the tx status.
+	statusReply := GetTxStatusResponse{}
+	require.NoError(service.GetTxStatus(nil, &GetTxStatusArgs{TxID: tx.ID()}, &statusReply))
+	require.Equal(status.Dropped, statusReply.Status)
+	require.Equal(rejection, statusReply.Rejection)
+}
+
+func TestIssueTxsInvalidArgs(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	reply := IssueTxsReply{}
+	err := service.IssueTxs(nil, &IssueTxsArgs{}, &reply)
+	require.ErrorIs(err, errNoTxs)
+}
+
 func TestGetBalance(t *testing.T) {
 	require := require.New(t)
 	service, _ := defaultService(t)
@@ -590,6 +808,157 @@ func TestGetStake(t *testing.T) {
 	require.Equal(stakeAmount+oldStake, outputs[0].Out.Amount()+outputs[1].Out.Amount()+outputs[2].Out.Amount())
 }
 
+func TestGetStakeSummary(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defaultAddress(t, service)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	service.vm.ctx.Lock.Lock()
+
+	validatorNodeID := ids.NodeID(keys[0].PublicKey().Address())
+	validator, err := service.vm.state.GetCurrentValidator(constants.PrimaryNetworkID, validatorNodeID)
+	require.NoError(err)
+	validatorAttr, err := service.loadStakerTxAttributes(validator.TxID)
+	require.NoError(err)
+
+	// Add a delegator to the validator of [keys[0]] whose rewards go to
+	// another address.
+	var (
+		delegatorStake        = service.vm.MinDelegatorStake + 12345
+		delegatorReward       = uint64(1_000_000)
+		delegatorRewardAddr   = ids.GenerateTestShortID()
+		delegatorEndTime      = uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())
+		pendingValidatorStake = service.vm.MinValidatorStake + 54321
+	)
+	delegatorTx, err := service.vm.txBuilder.NewAddDelegatorTx(
+		delegatorStake,
+		uint64(defaultGenesisTime.Unix()),
+		delegatorEndTime,
+		validatorNodeID,
+		delegatorRewardAddr,
+		[]*secp256k1.PrivateKey{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+
+	delegator, err := state.NewCurrentStaker(
+		delegatorTx.ID(),
+		delegatorTx.Unsigned.(*txs.AddDelegatorTx),
+		delegatorReward,
+	)
+	require.NoError(err)
+
+	service.vm.state.PutCurrentDelegator(delegator)
+	service.vm.state.AddTx(delegatorTx, status.Committed)
+
+	// Add a pending validator staked by [keys[0]].
+	pendingValidatorTx, err := service.vm.txBuilder.NewAddValidatorTx(
+		pendingValidatorStake,
+		uint64(defaultGenesisTime.Unix()),
+		delegatorEndTime,
+		ids.GenerateTestNodeID(),
+		ids.GenerateTestShortID(),
+		0,
+		[]*secp256k1.PrivateKey{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+
+	pendingValidator, err := state.NewPendingStaker(
+		pendingValidatorTx.ID(),
+		pendingValidatorTx.Unsigned.(*txs.AddValidatorTx),
+	)
+	require.NoError(err)
+
+	service.vm.state.PutPendingValidator(pendingValidator)
+	service.vm.state.AddTx(pendingValidatorTx, status.Committed)
+	require.NoError(service.vm.state.Commit())
+
+	service.vm.ctx.Lock.Unlock()
+
+	stakerAddr, err := service.addrManager.FormatLocalAddress(keys[0].PublicKey().Address())
+	require.NoError(err)
+	rewardAddr, err := service.addrManager.FormatLocalAddress(delegatorRewardAddr)
+	require.NoError(err)
+	unusedAddr, err := service.addrManager.FormatLocalAddress(ids.GenerateTestShortID())
+	require.NoError(err)
+
+	args := GetStakeSummaryArgs{
+		JSONAddresses: api.JSONAddresses{
+			Addresses: []string{stakerAddr, rewardAddr, unusedAddr},
+		},
+	}
+	reply := GetStakeSummaryReply{}
+	require.NoError(service.GetStakeSummary(nil, &args, &reply))
+	require.Len(reply.Summaries, 3)
+
+	delegateeReward, delegatorShare := reward.Split(delegatorReward, validatorAttr.shares)
+
+	// [keys[0]] staked the delegator, the genesis validator, and the pending
+	// validator. It receives the validator's rewards and delegation fees.
+	// Positions are ordered like the current and then the pending stakers.
+	stakerSummary := reply.Summaries[stakerAddr]
+	require.Equal(json.Uint64(defaultWeight+delegatorStake), stakerSummary.ActiveStake)
+	require.Equal(json.Uint64(pendingValidatorStake), stakerSummary.PendingStake)
+	require.Equal(json.Uint64(validator.PotentialReward+delegateeReward), stakerSummary.PotentialReward)
+	require.Equal(
+		[]StakePosition{
+			{
+				TxID:      delegatorTx.ID(),
+				NodeID:    validatorNodeID,
+				StartTime: json.Uint64(delegator.StartTime.Unix()),
+				EndTime:   json.Uint64(delegator.EndTime.Unix()),
+				Staked:    json.Uint64(delegatorStake),
+			},
+			{
+				TxID:            validator.TxID,
+				NodeID:          validatorNodeID,
+				Validator:       true,
+				StartTime:       json.Uint64(validator.StartTime.Unix()),
+				EndTime:         json.Uint64(validator.EndTime.Unix()),
+				Staked:          json.Uint64(defaultWeight),
+				PotentialReward: json.Uint64(validator.PotentialReward + delegateeReward),
+			},
+			{
+				TxID:      pendingValidatorTx.ID(),
+				NodeID:    pendingValidator.NodeID,
+				Validator: true,
+				Pending:   true,
+				StartTime: json.Uint64(pendingValidator.StartTime.Unix()),
+				EndTime:   json.Uint64(pendingValidator.EndTime.Unix()),
+				Staked:    json.Uint64(pendingValidatorStake),
+			},
+		},
+		stakerSummary.Positions,
+	)
+
+	// The delegator's reward address only receives the delegator's share of
+	// the rewards.
+	rewardSummary := reply.Summaries[rewardAddr]
+	require.Zero(rewardSummary.ActiveStake)
+	require.Zero(rewardSummary.PendingStake)
+	require.Equal(json.Uint64(delegatorShare), rewardSummary.PotentialReward)
+	require.Equal(
+		[]StakePosition{
+			{
+				TxID:            delegatorTx.ID(),
+				NodeID:          validatorNodeID,
+				StartTime:       json.Uint64(delegator.StartTime.Unix()),
+				EndTime:         json.Uint64(delegator.EndTime.Unix()),
+				PotentialReward: json.Uint64(delegatorShare),
+			},
+		},
+		rewardSummary.Positions,
+	)
+
+	require.Equal(&StakeSummary{Positions: []StakePosition{}}, reply.Summaries[unusedAddr])
+}
+
 func TestGetCurrentValidators(t *testing.T) {
 	require := require.New(t)
 	service, _ := defaultService(t)
@@ -719,6 +1088,337 @@ func TestGetCurrentValidators(t *testing.T) {
 	}
 }
 
+func TestGetStaker(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	// Unknown txs aren't stakers
+	args := api.GetTxArgs{TxID: ids.GenerateTestID()}
+	reply := GetStakerReply{}
+	err := service.GetStaker(nil, &args, &reply)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	service.vm.ctx.Lock.Lock()
+
+	stakeAmount := service.vm.MinValidatorStake + 54321
+	nodeID := ids.GenerateTestNodeID()
+	startTime := uint64(defaultGenesisTime.Unix())
+	endTime := uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix())
+	tx, err := service.vm.txBuilder.NewAddValidatorTx(
+		stakeAmount,
+		startTime,
+		endTime,
+		nodeID,
+		ids.GenerateTestShortID(),
+		0,
+		[]*secp256k1.PrivateKey{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+
+	pendingStaker, err := state.NewPendingStaker(
+		tx.ID(),
+		tx.Unsigned.(*txs.AddValidatorTx),
+	)
+	require.NoError(err)
+
+	service.vm.state.PutPendingValidator(pendingStaker)
+	service.vm.state.AddTx(tx, status.Committed)
+	require.NoError(service.vm.state.Commit())
+
+	service.vm.ctx.Lock.Unlock()
+
+	args.TxID = tx.ID()
+	reply = GetStakerReply{}
+	require.NoError(service.GetStaker(nil, &args, &reply))
+	require.Equal(GetStakerReply{
+		TxID:      tx.ID(),
+		SubnetID:  constants.PrimaryNetworkID,
+		NodeID:    nodeID,
+		Status:    StakerPending,
+		StartTime: json.Uint64(startTime),
+		EndTime:   json.Uint64(endTime),
+		Weight:    json.Uint64(stakeAmount),
+	}, reply)
+
+	// Promote the staker to the current staker set
+	service.vm.ctx.Lock.Lock()
+
+	const potentialReward = 1234
+	currentStaker, err := state.NewCurrentStaker(
+		tx.ID(),
+		tx.Unsigned.(*txs.AddValidatorTx),
+		potentialReward,
+	)
+	require.NoError(err)
+
+	service.vm.state.DeletePendingValidator(pendingStaker)
+	service.vm.state.PutCurrentValidator(currentStaker)
+	require.NoError(service.vm.state.Commit())
+
+	service.vm.ctx.Lock.Unlock()
+
+	reply = GetStakerReply{}
+	require.NoError(service.GetStaker(nil, &args, &reply))
+	require.Equal(StakerCurrent, reply.Status)
+	require.NotNil(reply.PotentialReward)
+	require.Equal(json.Uint64(potentialReward), *reply.PotentialReward)
+	require.Nil(reply.Reward)
+
+	// Reward the staker
+	service.vm.ctx.Lock.Lock()
+
+	service.vm.state.DeleteCurrentValidator(currentStaker)
+	service.vm.state.AddRewardUTXO(tx.ID(), &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        tx.ID(),
+			OutputIndex: 1,
+		},
+		Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: potentialReward,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+			},
+		},
+	})
+	require.NoError(service.vm.state.Commit())
+
+	service.vm.ctx.Lock.Unlock()
+
+	reply = GetStakerReply{}
+	require.NoError(service.GetStaker(nil, &args, &reply))
+	require.Equal(StakerCompleted, reply.Status)
+	require.Nil(reply.PotentialReward)
+	require.NotNil(reply.Reward)
+	require.Equal(json.Uint64(potentialReward), *reply.Reward)
+}
+
+func TestGetRewardHistory(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	reply := GetRewardHistoryReply{}
+	err := service.GetRewardHistory(nil, &GetRewardHistoryArgs{}, &reply)
+	require.ErrorIs(err, errInvalidRewardHistoryArgs)
+
+	service.vm.ctx.Lock.Lock()
+
+	nodeID := ids.GenerateTestNodeID()
+	tx, err := service.vm.txBuilder.NewAddValidatorTx(
+		service.vm.MinValidatorStake,
+		uint64(defaultGenesisTime.Unix()),
+		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()),
+		nodeID,
+		ids.GenerateTestShortID(),
+		0,
+		[]*secp256k1.PrivateKey{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+	service.vm.state.AddTx(tx, status.Committed)
+	require.NoError(service.vm.state.Commit())
+
+	const rewardAmount = 1234
+	rewardAddr := keys[1].PublicKey().Address()
+	service.vm.state.AddRewardUTXO(tx.ID(), &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        tx.ID(),
+			OutputIndex: 1,
+		},
+		Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: rewardAmount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{rewardAddr},
+			},
+		},
+	})
+	require.NoError(service.vm.state.Commit())
+
+	service.vm.ctx.Lock.Unlock()
+
+	rewardAddrStr, err := service.addrManager.FormatLocalAddress(rewardAddr)
+	require.NoError(err)
+
+	reply = GetRewardHistoryReply{}
+	require.NoError(service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+		Address: rewardAddrStr,
+	}, &reply))
+	require.Equal(json.Uint64(1), reply.NumFetched)
+	require.Len(reply.Rewards, 1)
+
+	reward := reply.Rewards[0]
+	require.Equal(tx.ID(), reward.TxID)
+	require.Equal(nodeID, reward.NodeID)
+	require.Equal(constants.PrimaryNetworkID, reward.SubnetID)
+	require.Equal(json.Uint64(rewardAmount), reward.Amount)
+	require.Equal([]string{rewardAddrStr}, reward.Addresses)
+
+	// The rewards of the node are indexed too.
+	nodeReply := GetRewardHistoryReply{}
+	require.NoError(service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+		NodeID: nodeID,
+	}, &nodeReply))
+	require.Equal(reply.Rewards, nodeReply.Rewards)
+
+	// There are no rewards after the last one.
+	nextReply := GetRewardHistoryReply{}
+	require.NoError(service.GetRewardHistory(nil, &GetRewardHistoryArgs{
+		Address: rewardAddrStr,
+		Cursor:  reply.NextCursor,
+	}, &nextReply))
+	require.Zero(nextReply.NumFetched)
+	require.Equal(reply.NextCursor, nextReply.NextCursor)
+}
+
+func TestGetOwners(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	service.vm.ctx.Lock.Lock()
+
+	rewardAddr := ids.GenerateTestShortID()
+	tx, err := service.vm.txBuilder.NewAddValidatorTx(
+		service.vm.MinValidatorStake,
+		uint64(defaultGenesisTime.Unix()),
+		uint64(defaultGenesisTime.Add(defaultMinStakingDuration).Unix()),
+		ids.GenerateTestNodeID(),
+		rewardAddr,
+		0,
+		[]*secp256k1.PrivateKey{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	require.NoError(err)
+	service.vm.state.AddTx(tx, status.Committed)
+
+	// A stakeable locked UTXO owned by a 2-of-3 multisig
+	multisigAddrs := []ids.ShortID{
+		keys[0].PublicKey().Address(),
+		keys[1].PublicKey().Address(),
+		keys[2].PublicKey().Address(),
+	}
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        ids.GenerateTestID(),
+			OutputIndex: 3,
+		},
+		Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+		Out: &stakeable.LockOut{
+			Locktime: 1000,
+			TransferableOut: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Locktime:  500,
+					Threshold: 2,
+					Addrs:     multisigAddrs,
+				},
+			},
+		},
+	}
+	service.vm.state.AddUTXO(utxo)
+
+	// A vesting UTXO
+	vestingUTXO := &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        ids.GenerateTestID(),
+			OutputIndex: 1,
+		},
+		Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
+		Out: &stakeable.VestingOut{
+			Tranches: []stakeable.Tranche{
+				{Locktime: 1000, Amount: 1},
+				{Locktime: 2000, Amount: 2},
+			},
+			TransferableOut: &secp256k1fx.TransferOutput{
+				Amt: 3,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{rewardAddr},
+				},
+			},
+		},
+	}
+	service.vm.state.AddUTXO(vestingUTXO)
+	require.NoError(service.vm.state.Commit())
+
+	service.vm.ctx.Lock.Unlock()
+
+	rewardAddrStr, err := service.addrManager.FormatLocalAddress(rewardAddr)
+	require.NoError(err)
+	multisigAddrStrs := make([]string, len(multisigAddrs))
+	for i, addr := range multisigAddrs {
+		multisigAddrStrs[i], err = service.addrManager.FormatLocalAddress(addr)
+		require.NoError(err)
+	}
+
+	args := GetOwnersArgs{
+		StakerTxIDs: []ids.ID{tx.ID()},
+		UTXOIDs:     []string{utxo.UTXOID.String(), vestingUTXO.UTXOID.String()},
+	}
+	reply := GetOwnersReply{}
+	require.NoError(service.GetOwners(nil, &args, &reply))
+
+	rewardOwner := &pchainapi.Owner{
+		Locktime:  0,
+		Threshold: 1,
+		Addresses: []string{rewardAddrStr},
+	}
+	stakeableLocktime := json.Uint64(1000)
+	require.Equal(GetOwnersReply{
+		Stakers: []StakerOwners{
+			{
+				TxID:                  tx.ID(),
+				ValidationRewardOwner: rewardOwner,
+				DelegationRewardOwner: rewardOwner,
+			},
+		},
+		UTXOs: []UTXOOwner{
+			{
+				UTXOID:            utxo.UTXOID.String(),
+				StakeableLocktime: &stakeableLocktime,
+				Owner: &pchainapi.Owner{
+					Locktime:  500,
+					Threshold: 2,
+					Addresses: multisigAddrStrs,
+				},
+			},
+			{
+				UTXOID: vestingUTXO.UTXOID.String(),
+				VestingSchedule: []Tranche{
+					{Locktime: 1000, Amount: 1},
+					{Locktime: 2000, Amount: 2},
+				},
+				Owner: rewardOwner,
+			},
+		},
+	}, reply)
+
+	// Unknown UTXOs don't have owners
+	args = GetOwnersArgs{
+		UTXOIDs: []string{(&avax.UTXOID{TxID: ids.GenerateTestID()}).String()},
+	}
+	err = service.GetOwners(nil, &args, &reply)
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
 func TestGetTimestamp(t *testing.T) {
 	require := require.New(t)
 	service, _ := defaultService(t)
@@ -744,6 +1444,292 @@ func TestGetTimestamp(t *testing.T) {
 	require.Equal(newTimestamp, reply.Timestamp)
 }
 
+func TestGetChainTime(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	service.vm.ctx.Lock.Lock()
+	chainTime := service.vm.state.GetTimestamp()
+	localTime := chainTime.Add(time.Second)
+	service.vm.clock.Set(localTime)
+	nextStakerChangeTime, err := txexecutor.GetNextStakerChangeTime(service.vm.state)
+	require.NoError(err)
+	stats := service.vm.manager.ChainTimeStats()
+	service.vm.ctx.Lock.Unlock()
+
+	reply := GetChainTimeReply{}
+	require.NoError(service.GetChainTime(nil, nil, &reply))
+	require.Equal(chainTime, reply.Timestamp)
+	require.Equal(localTime, reply.LocalTime)
+	require.Equal(nextStakerChangeTime, reply.NextStakerChangeTime)
+
+	require.Equal(json.Uint64(stats.Samples), reply.Samples)
+	require.Equal(stats.Skew, reply.Skew)
+	require.Equal(stats.Skew-stats.SkewMargin, reply.SkewLowerBound)
+	require.Equal(stats.Skew+stats.SkewMargin, reply.SkewUpperBound)
+	require.Equal(stats.BlockInterval, reply.BlockInterval)
+}
+
+func TestGetSupplyAt(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	service.vm.ctx.Lock.Lock()
+	genesisSupply, err := service.vm.state.GetSupplyAt(constants.PrimaryNetworkID, 0)
+	require.NoError(err)
+	lastAcceptedHeight, err := service.vm.GetCurrentHeight(context.Background())
+	require.NoError(err)
+	service.vm.ctx.Lock.Unlock()
+
+	request := &http.Request{}
+	reply := GetSupplyAtReply{}
+	require.NoError(service.GetSupplyAt(request, &GetSupplyAtArgs{
+		SubnetID: constants.PrimaryNetworkID,
+	}, &reply))
+	require.Equal(json.Uint64(genesisSupply), reply.Supply)
+
+	err = service.GetSupplyAt(request, &GetSupplyAtArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		Height:   json.Uint64(lastAcceptedHeight + 1),
+	}, &reply)
+	require.ErrorIs(err, errHeightNotAccepted)
+
+	changesReply := GetSupplyChangesReply{}
+	require.NoError(service.GetSupplyChanges(request, &GetSupplyChangesArgs{
+		SubnetID: constants.PrimaryNetworkID,
+	}, &changesReply))
+	require.Equal([]SupplyChange{
+		{
+			Height: 0,
+			Supply: json.Uint64(genesisSupply),
+		},
+	}, changesReply.Changes)
+
+	err = service.GetSupplyChanges(request, &GetSupplyChangesArgs{
+		SubnetID:    constants.PrimaryNetworkID,
+		StartHeight: 1,
+	}, &changesReply)
+	require.ErrorIs(err, errStartAfterEndHeight)
+}
+
+func TestNewCometBFTValidatorSet(t *testing.T) {
+	require := require.New(t)
+
+	newPublicKey := func() *bls.PublicKey {
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+		return bls.PublicFromSecretKey(sk)
+	}
+
+	var (
+		heavyNodeID  = ids.GenerateTestNodeID()
+		lightNodeID0 = ids.GenerateTestNodeID()
+		lightNodeID1 = ids.GenerateTestNodeID()
+		vdrs         = map[ids.NodeID]*validators.GetValidatorOutput{
+			heavyNodeID: {
+				NodeID:    heavyNodeID,
+				PublicKey: newPublicKey(),
+				Weight:    3,
+			},
+			lightNodeID0: {
+				NodeID:    lightNodeID0,
+				PublicKey: newPublicKey(),
+				Weight:    1,
+			},
+			lightNodeID1: {
+				NodeID:    lightNodeID1,
+				PublicKey: newPublicKey(),
+				Weight:    1,
+			},
+		}
+	)
+	// Validators without a public key are omitted.
+	noKeyNodeID := ids.GenerateTestNodeID()
+	vdrs[noKeyNodeID] = &validators.GetValidatorOutput{
+		NodeID: noKeyNodeID,
+		Weight: 10,
+	}
+
+	vdrSet, err := NewCometBFTValidatorSet(vdrs)
+	require.NoError(err)
+	require.Equal(uint64(5), vdrSet.TotalVotingPower)
+	require.Len(vdrSet.Validators, 3)
+
+	// Validators are sorted by decreasing voting power, then by address.
+	heavyPKBytes := bls.PublicKeyToBytes(vdrs[heavyNodeID].PublicKey)
+	require.Equal(heavyPKBytes, vdrSet.Validators[0].PubKey.Value)
+	require.Equal(CometBFTBLSPubKeyType, vdrSet.Validators[0].PubKey.Type)
+	require.Equal(json.Uint64(3), vdrSet.Validators[0].VotingPower)
+	require.Less(vdrSet.Validators[1].Address, vdrSet.Validators[2].Address)
+
+	heavyAddress := hashing.ComputeHash256(heavyPKBytes)[:20]
+	require.Equal(strings.ToUpper(hex.EncodeToString(heavyAddress)), vdrSet.Validators[0].Address)
+
+	// The hash is the RFC 6962 merkle root of the SimpleValidator encodings.
+	leaves := make([][]byte, len(vdrSet.Validators))
+	for i, vdr := range vdrSet.Validators {
+		leaf := []byte{0x0a, 0x32, 0x1a, 0x30}
+		leaf = append(leaf, vdr.PubKey.Value...)
+		leaf = append(leaf, 0x10, byte(vdr.VotingPower))
+		leaves[i] = hashing.ComputeHash256(append([]byte{0x00}, leaf...))
+	}
+	innerHash := func(left, right []byte) []byte {
+		inner := append([]byte{0x01}, left...)
+		return hashing.ComputeHash256(append(inner, right...))
+	}
+	expectedHash := innerHash(innerHash(leaves[0], leaves[1]), leaves[2])
+	require.Equal(expectedHash, vdrSet.Hash)
+
+	vdrs[heavyNodeID].Weight = math.MaxInt64
+	_, err = NewCometBFTValidatorSet(vdrs)
+	require.ErrorIs(err, errVotingPowerTooLarge)
+}
+
+func TestGetCometBFTValidatorSet(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	request := &http.Request{}
+	reply := GetCometBFTValidatorSetReply{}
+	require.NoError(service.GetCometBFTValidatorSet(request, &GetCometBFTValidatorSetArgs{
+		SubnetID: constants.PrimaryNetworkID,
+	}, &reply))
+
+	service.vm.ctx.Lock.Lock()
+	height, err := service.vm.GetCurrentHeight(context.Background())
+	require.NoError(err)
+	vdrs, err := service.vm.GetValidatorSet(context.Background(), height, constants.PrimaryNetworkID)
+	service.vm.ctx.Lock.Unlock()
+	require.NoError(err)
+
+	expected, err := NewCometBFTValidatorSet(vdrs)
+	require.NoError(err)
+	require.Equal(json.Uint64(height), reply.Height)
+	require.Equal(expected.Validators, reply.Validators)
+	require.Equal(json.Uint64(expected.TotalVotingPower), reply.TotalVotingPower)
+	require.Equal(strings.ToUpper(hex.EncodeToString(expected.Hash)), reply.Hash)
+
+	// Heights that haven't been accepted are rejected.
+	futureHeight := json.Uint64(height + 1)
+	err = service.GetCometBFTValidatorSet(request, &GetCometBFTValidatorSetArgs{
+		SubnetID: constants.PrimaryNetworkID,
+		Height:   &futureHeight,
+	}, &reply)
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestGetUptimeAttestation(t *testing.T) {
+	require := require.New(t)
+	service, _ := defaultService(t)
+	defer func() {
+		service.vm.ctx.Lock.Lock()
+		require.NoError(service.vm.Shutdown(context.Background()))
+		service.vm.ctx.Lock.Unlock()
+	}()
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	service.vm.ctx.PublicKey = bls.PublicFromSecretKey(sk)
+	service.vm.ctx.WarpSigner = warp.NewSigner(sk, service.vm.ctx.NetworkID, service.vm.ctx.ChainID)
+
+	request := &http.Request{}
+	reply := GetUptimeAttestationReply{}
+	err = service.GetUptimeAttestation(request, &GetUptimeAttestationArgs{
+		SubnetID: constants.PrimaryNetworkID,
+	}, &reply)
+	require.ErrorIs(err, errAttestationDisabled)
+
+	service.vm.uptimeAttestationEnabled = true
+	require.NoError(service.GetUptimeAttestation(request, &GetUptimeAttestationArgs{
+		SubnetID: constants.PrimaryNetworkID,
+	}, &reply))
+
+	decode := func(s string) []byte {
+		b, err := formatting.Decode(reply.Encoding, s)
+		require.NoError(err)
+		return b
+	}
+	attestationBytes := decode(reply.Attestation)
+	attestation, err := ParseUptimeAttestation(attestationBytes)
+	require.NoError(err)
+	require.Equal(service.vm.ctx.NodeID, attestation.NodeID)
+	require.Equal(constants.PrimaryNetworkID, attestation.SubnetID)
+	require.Len(attestation.Uptimes, len(keys))
+	for i, uptime := range attestation.Uptimes {
+		if i > 0 {
+			require.True(attestation.Uptimes[i-1].Less(uptime))
+		}
+		require.LessOrEqual(uptime.Uptime, uint32(UptimeDenominator))
+	}
+
+	msg, err := warp.ParseUnsignedMessage(decode(reply.UnsignedMessage))
+	require.NoError(err)
+	pk, err := bls.PublicKeyFromBytes(decode(reply.PublicKey))
+	require.NoError(err)
+	sig, err := bls.SignatureFromBytes(decode(reply.Signature))
+	require.NoError(err)
+
+	signedAttestation := &SignedUptimeAttestation{
+		Attestation: attestation,
+		Bytes:       attestationBytes,
+		Message:     msg,
+		PublicKey:   pk,
+		Signature:   sig,
+	}
+	require.NoError(signedAttestation.Verify())
+
+	p, err := ParseUptimeAttestationPayload(msg.Payload)
+	require.NoError(err)
+	require.Equal(service.vm.ctx.NodeID, p.NodeID)
+
+	// The signature can't be claimed by another node.
+	attestation.NodeID = ids.GenerateTestNodeID()
+	signedAttestation.Bytes, err = attestation.Bytes()
+	require.NoError(err)
+	err = signedAttestation.Verify()
+	require.ErrorIs(err, errInvalidUptimeAttestation)
+
+	// The signature doesn't cover a modified attestation.
+	attestation.NodeID = service.vm.ctx.NodeID
+	attestation.Timestamp++
+	signedAttestation.Bytes, err = attestation.Bytes()
+	require.NoError(err)
+	err = signedAttestation.Verify()
+	require.ErrorIs(err, errInvalidUptimeAttestation)
+
+	// A signature over any other payload isn't an attestation.
+	hash, err := payload.NewHash(hashing.ComputeHash256Array(signedAttestation.Bytes))
+	require.NoError(err)
+	signedAttestation.Message, err = warp.NewUnsignedMessage(msg.NetworkID, msg.SourceChainID, hash.Bytes())
+	require.NoError(err)
+	sigBytes, err := service.vm.ctx.WarpSigner.Sign(signedAttestation.Message)
+	require.NoError(err)
+	signedAttestation.Signature, err = bls.SignatureFromBytes(sigBytes)
+	require.NoError(err)
+	err = signedAttestation.Verify()
+	require.ErrorIs(err, errInvalidUptimeAttestation)
+
+	err = service.GetUptimeAttestation(request, &GetUptimeAttestationArgs{
+		SubnetID: ids.GenerateTestID(),
+	}, &reply)
+	require.ErrorIs(err, errUptimesNotTracked)
+}
+
 func TestGetBlock(t *testing.T) {
 	tests := []struct {
 		name     string
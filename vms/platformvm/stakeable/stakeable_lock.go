@@ -30,7 +30,8 @@ func (s *LockOut) Verify() error {
 	if s.Locktime == 0 {
 		return errInvalidLocktime
 	}
-	if _, nested := s.TransferableOut.(*LockOut); nested {
+	switch s.TransferableOut.(type) {
+	case *LockOut, *VestingOut:
 		return errNestedStakeableLocks
 	}
 	return s.TransferableOut.Verify()
@@ -45,7 +46,8 @@ func (s *LockIn) Verify() error {
 	if s.Locktime == 0 {
 		return errInvalidLocktime
 	}
-	if _, nested := s.TransferableIn.(*LockIn); nested {
+	switch s.TransferableIn.(type) {
+	case *LockIn, *VestingIn:
 		return errNestedStakeableLocks
 	}
 	return s.TransferableIn.Verify()
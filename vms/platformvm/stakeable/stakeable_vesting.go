@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stakeable
+
+import (
+	"errors"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	errNoTranches         = errors.New("no tranches")
+	errEmptyTranche       = errors.New("tranche has no amount")
+	errTranchesNotSorted  = errors.New("tranches not sorted by strictly increasing locktime")
+	errTrancheSumMismatch = errors.New("tranches don't sum to the amount")
+)
+
+// Tranche is an amount that can only be used for staking until [Locktime].
+type Tranche struct {
+	Locktime uint64 `serialize:"true" json:"locktime"`
+	Amount   uint64 `serialize:"true" json:"amount"`
+}
+
+// VestingOut is an output whose amount vests in tranches. Until the locktime
+// of a tranche, its amount can only be used for staking, as if it were held by
+// a [LockOut] with that locktime.
+type VestingOut struct {
+	// Sorted by strictly increasing locktime.
+	Tranches             []Tranche `serialize:"true" json:"tranches"`
+	avax.TransferableOut `serialize:"true" json:"output"`
+}
+
+func (s *VestingOut) Addresses() [][]byte {
+	if addressable, ok := s.TransferableOut.(avax.Addressable); ok {
+		return addressable.Addresses()
+	}
+	return nil
+}
+
+// EndLocktime returns the locktime of the last tranche, after which the whole
+// amount has vested.
+func (s *VestingOut) EndLocktime() uint64 {
+	return s.Tranches[len(s.Tranches)-1].Locktime
+}
+
+// Unlocked returns the amount of the tranches whose locktime is at most
+// [time].
+func (s *VestingOut) Unlocked(time uint64) uint64 {
+	var amount uint64
+	for _, tranche := range s.Tranches {
+		if tranche.Locktime > time {
+			break
+		}
+		// The tranches are verified to not overflow.
+		amount += tranche.Amount
+	}
+	return amount
+}
+
+func (s *VestingOut) Verify() error {
+	switch s.TransferableOut.(type) {
+	case *LockOut, *VestingOut:
+		return errNestedStakeableLocks
+	}
+	if err := verifyTranches(s.Tranches, s.TransferableOut.Amount()); err != nil {
+		return err
+	}
+	return s.TransferableOut.Verify()
+}
+
+// VestingIn consumes a [VestingOut] that has tranches that are still locked.
+// Its tranches must be the tranches of the consumed output.
+type VestingIn struct {
+	// Sorted by strictly increasing locktime.
+	Tranches            []Tranche `serialize:"true" json:"tranches"`
+	avax.TransferableIn `serialize:"true" json:"input"`
+}
+
+func (s *VestingIn) Verify() error {
+	switch s.TransferableIn.(type) {
+	case *LockIn, *VestingIn:
+		return errNestedStakeableLocks
+	}
+	if err := verifyTranches(s.Tranches, s.TransferableIn.Amount()); err != nil {
+		return err
+	}
+	return s.TransferableIn.Verify()
+}
+
+// SplitTranches splits [tranches] into the tranches of [amount] that vest last
+// and the tranches of the rest. A tranche is split if only part of it is
+// needed. [amount] must be at most the sum of [tranches].
+func SplitTranches(tranches []Tranche, amount uint64) (rest []Tranche, last []Tranche) {
+	i := len(tranches)
+	for i > 0 && tranches[i-1].Amount <= amount {
+		i--
+		amount -= tranches[i].Amount
+	}
+	rest = slices.Clone(tranches[:i])
+	last = slices.Clone(tranches[i:])
+	if amount > 0 {
+		// [amount] is less than the amount of the last tranche of [rest]
+		rest[i-1].Amount -= amount
+		last = append([]Tranche{{Locktime: rest[i-1].Locktime, Amount: amount}}, last...)
+	}
+	return rest, last
+}
+
+// verifyTranches verifies that [tranches] is a valid vesting schedule of
+// [amount].
+func verifyTranches(tranches []Tranche, amount uint64) error {
+	if len(tranches) == 0 {
+		return errNoTranches
+	}
+
+	var (
+		sum          uint64
+		prevLocktime uint64
+	)
+	for _, tranche := range tranches {
+		switch {
+		case tranche.Locktime == 0:
+			return errInvalidLocktime
+		case tranche.Amount == 0:
+			return errEmptyTranche
+		case tranche.Locktime <= prevLocktime:
+			return errTranchesNotSorted
+		}
+		newSum, err := math.Add64(sum, tranche.Amount)
+		if err != nil {
+			return err
+		}
+		sum = newSum
+		prevLocktime = tranche.Locktime
+	}
+	if sum != amount {
+		return errTrancheSumMismatch
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package stakeable
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+func TestVerifyTranches(t *testing.T) {
+	tests := []struct {
+		name        string
+		tranches    []Tranche
+		amount      uint64
+		expectedErr error
+	}{
+		{
+			name: "happy path",
+			tranches: []Tranche{
+				{Locktime: 1, Amount: 2},
+				{Locktime: 3, Amount: 4},
+			},
+			amount:      6,
+			expectedErr: nil,
+		},
+		{
+			name:        "no tranches",
+			amount:      6,
+			expectedErr: errNoTranches,
+		},
+		{
+			name: "invalid locktime",
+			tranches: []Tranche{
+				{Locktime: 0, Amount: 6},
+			},
+			amount:      6,
+			expectedErr: errInvalidLocktime,
+		},
+		{
+			name: "empty tranche",
+			tranches: []Tranche{
+				{Locktime: 1, Amount: 6},
+				{Locktime: 2, Amount: 0},
+			},
+			amount:      6,
+			expectedErr: errEmptyTranche,
+		},
+		{
+			name: "duplicate locktime",
+			tranches: []Tranche{
+				{Locktime: 1, Amount: 2},
+				{Locktime: 1, Amount: 4},
+			},
+			amount:      6,
+			expectedErr: errTranchesNotSorted,
+		},
+		{
+			name: "unsorted",
+			tranches: []Tranche{
+				{Locktime: 3, Amount: 2},
+				{Locktime: 1, Amount: 4},
+			},
+			amount:      6,
+			expectedErr: errTranchesNotSorted,
+		},
+		{
+			name: "overflow",
+			tranches: []Tranche{
+				{Locktime: 1, Amount: math.MaxUint64},
+				{Locktime: 2, Amount: 1},
+			},
+			amount:      0,
+			expectedErr: safemath.ErrOverflow,
+		},
+		{
+			name: "wrong sum",
+			tranches: []Tranche{
+				{Locktime: 1, Amount: 2},
+				{Locktime: 3, Amount: 4},
+			},
+			amount:      7,
+			expectedErr: errTrancheSumMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, verifyTranches(tt.tranches, tt.amount), tt.expectedErr)
+		})
+	}
+}
+
+func TestVestingOutVerify(t *testing.T) {
+	tranches := []Tranche{
+		{Locktime: 1, Amount: 2},
+		{Locktime: 3, Amount: 4},
+	}
+	tests := []struct {
+		name             string
+		transferableOutF func(*gomock.Controller) avax.TransferableOut
+		expectedErr      error
+	}{
+		{
+			name: "happy path",
+			transferableOutF: func(ctrl *gomock.Controller) avax.TransferableOut {
+				o := avax.NewMockTransferableOut(ctrl)
+				o.EXPECT().Amount().Return(uint64(6))
+				o.EXPECT().Verify().Return(nil)
+				return o
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "nested lock",
+			transferableOutF: func(*gomock.Controller) avax.TransferableOut {
+				return &LockOut{}
+			},
+			expectedErr: errNestedStakeableLocks,
+		},
+		{
+			name: "nested vesting",
+			transferableOutF: func(*gomock.Controller) avax.TransferableOut {
+				return &VestingOut{}
+			},
+			expectedErr: errNestedStakeableLocks,
+		},
+		{
+			name: "wrong amount",
+			transferableOutF: func(ctrl *gomock.Controller) avax.TransferableOut {
+				o := avax.NewMockTransferableOut(ctrl)
+				o.EXPECT().Amount().Return(uint64(5))
+				return o
+			},
+			expectedErr: errTrancheSumMismatch,
+		},
+		{
+			name: "inner output fails verification",
+			transferableOutF: func(ctrl *gomock.Controller) avax.TransferableOut {
+				o := avax.NewMockTransferableOut(ctrl)
+				o.EXPECT().Amount().Return(uint64(6))
+				o.EXPECT().Verify().Return(errTest)
+				return o
+			},
+			expectedErr: errTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			vestingOut := &VestingOut{
+				Tranches:        tranches,
+				TransferableOut: tt.transferableOutF(ctrl),
+			}
+			require.Equal(t, tt.expectedErr, vestingOut.Verify())
+		})
+	}
+}
+
+func TestVestingInVerify(t *testing.T) {
+	tranches := []Tranche{
+		{Locktime: 1, Amount: 2},
+		{Locktime: 3, Amount: 4},
+	}
+	tests := []struct {
+		name            string
+		transferableInF func(*gomock.Controller) avax.TransferableIn
+		expectedErr     error
+	}{
+		{
+			name: "happy path",
+			transferableInF: func(ctrl *gomock.Controller) avax.TransferableIn {
+				o := avax.NewMockTransferableIn(ctrl)
+				o.EXPECT().Amount().Return(uint64(6))
+				o.EXPECT().Verify().Return(nil)
+				return o
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "nested lock",
+			transferableInF: func(*gomock.Controller) avax.TransferableIn {
+				return &LockIn{}
+			},
+			expectedErr: errNestedStakeableLocks,
+		},
+		{
+			name: "nested vesting",
+			transferableInF: func(*gomock.Controller) avax.TransferableIn {
+				return &VestingIn{}
+			},
+			expectedErr: errNestedStakeableLocks,
+		},
+		{
+			name: "wrong amount",
+			transferableInF: func(ctrl *gomock.Controller) avax.TransferableIn {
+				o := avax.NewMockTransferableIn(ctrl)
+				o.EXPECT().Amount().Return(uint64(7))
+				return o
+			},
+			expectedErr: errTrancheSumMismatch,
+		},
+		{
+			name: "inner input fails verification",
+			transferableInF: func(ctrl *gomock.Controller) avax.TransferableIn {
+				o := avax.NewMockTransferableIn(ctrl)
+				o.EXPECT().Amount().Return(uint64(6))
+				o.EXPECT().Verify().Return(errTest)
+				return o
+			},
+			expectedErr: errTest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			vestingIn := &VestingIn{
+				Tranches:       tranches,
+				TransferableIn: tt.transferableInF(ctrl),
+			}
+			require.Equal(t, tt.expectedErr, vestingIn.Verify())
+		})
+	}
+}
+
+func TestVestingOutUnlocked(t *testing.T) {
+	require := require.New(t)
+
+	out := &VestingOut{
+		Tranches: []Tranche{
+			{Locktime: 10, Amount: 1},
+			{Locktime: 20, Amount: 2},
+			{Locktime: 30, Amount: 4},
+		},
+	}
+	require.Zero(out.Unlocked(9))
+	require.Equal(uint64(1), out.Unlocked(10))
+	require.Equal(uint64(3), out.Unlocked(29))
+	require.Equal(uint64(7), out.Unlocked(30))
+	require.Equal(uint64(30), out.EndLocktime())
+}
+
+func TestSplitTranches(t *testing.T) {
+	tranches := []Tranche{
+		{Locktime: 10, Amount: 1},
+		{Locktime: 20, Amount: 2},
+		{Locktime: 30, Amount: 4},
+	}
+	tests := []struct {
+		name         string
+		amount       uint64
+		expectedRest []Tranche
+		expectedLast []Tranche
+	}{
+		{
+			name:         "nothing",
+			amount:       0,
+			expectedRest: tranches,
+			expectedLast: []Tranche{},
+		},
+		{
+			name:   "part of a tranche",
+			amount: 3,
+			expectedRest: []Tranche{
+				{Locktime: 10, Amount: 1},
+				{Locktime: 20, Amount: 2},
+				{Locktime: 30, Amount: 1},
+			},
+			expectedLast: []Tranche{
+				{Locktime: 30, Amount: 3},
+			},
+		},
+		{
+			name:   "whole tranches",
+			amount: 6,
+			expectedRest: []Tranche{
+				{Locktime: 10, Amount: 1},
+			},
+			expectedLast: []Tranche{
+				{Locktime: 20, Amount: 2},
+				{Locktime: 30, Amount: 4},
+			},
+		},
+		{
+			name:   "across tranches",
+			amount: 5,
+			expectedRest: []Tranche{
+				{Locktime: 10, Amount: 1},
+				{Locktime: 20, Amount: 1},
+			},
+			expectedLast: []Tranche{
+				{Locktime: 20, Amount: 1},
+				{Locktime: 30, Amount: 4},
+			},
+		},
+		{
+			name:         "everything",
+			amount:       7,
+			expectedRest: []Tranche{},
+			expectedLast: tranches,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			rest, last := SplitTranches(tranches, test.amount)
+			require.Equal(test.expectedRest, rest)
+			require.Equal(test.expectedLast, last)
+		})
+	}
+
+	// The tranches that were split aren't modified.
+	require.Equal(t, []Tranche{
+		{Locktime: 10, Amount: 1},
+		{Locktime: 20, Amount: 2},
+		{Locktime: 30, Amount: 4},
+	}, tranches)
+}
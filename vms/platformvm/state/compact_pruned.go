@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// The number of ranges [blockDB] is split into when compacting it. Block IDs
+// are uniformly distributed, so each range holds roughly the same number of
+// blocks.
+const pruneCompactionRanges = 16
+
+// compactionRange is a range of keys in a database to compact.
+type compactionRange struct {
+	name  string
+	db    database.Compacter
+	start []byte
+	limit []byte
+}
+
+// prunedRanges returns the ranges of the database that were modified by
+// PruneAndIndex.
+func (s *state) prunedRanges() []compactionRange {
+	const rangeSize = 256 / pruneCompactionRanges
+
+	ranges := make([]compactionRange, 0, pruneCompactionRanges+1)
+	for i := 0; i < pruneCompactionRanges; i++ {
+		r := compactionRange{
+			name:  "block",
+			db:    s.blockDB,
+			start: []byte{byte(i * rangeSize)},
+		}
+		// The limit of the last range is left as nil to include all remaining
+		// keys.
+		if i < pruneCompactionRanges-1 {
+			r.limit = []byte{byte((i + 1) * rangeSize)}
+		}
+		ranges = append(ranges, r)
+	}
+
+	// Heights are packed as big endian, so nearly all of them share their
+	// first byte. Splitting [blockIDDB] wouldn't reduce the size of each
+	// compaction.
+	return append(ranges, compactionRange{
+		name: "blockID",
+		db:   s.blockIDDB,
+	})
+}
+
+func (s *state) CompactPruned(ctx context.Context, interval time.Duration, log logging.Logger) error {
+	log.Info("starting compaction of pruned state",
+		zap.Duration("interval", interval),
+	)
+
+	var (
+		startTime = time.Now()
+		ranges    = s.prunedRanges()
+		timer     = time.NewTimer(interval)
+	)
+	defer timer.Stop()
+
+	for i, r := range ranges {
+		// Waiting before every compaction, rather than only between them,
+		// gives the node time to finish any work that was delayed by pruning.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := r.db.Compact(r.start, r.limit); err != nil {
+			return fmt.Errorf("failed to compact %s range %d: %w", r.name, i, err)
+		}
+
+		log.Debug("compacted pruned state range",
+			zap.String("name", r.name),
+			zap.Binary("start", r.start),
+			zap.Binary("limit", r.limit),
+			zap.Int("numCompacted", i+1),
+			zap.Int("numRanges", len(ranges)),
+		)
+		timer.Reset(interval)
+	}
+
+	log.Info("finished compaction of pruned state",
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return nil
+}
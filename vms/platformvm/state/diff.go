@@ -45,6 +45,12 @@ type diff struct {
 	addedSubnets []*txs.Tx
 	// Subnet ID --> Owner of the subnet
 	subnetOwners map[ids.ID]fx.Owner
+	// The validators that were frozen or unfrozen. If the time that a
+	// validator is frozen until is zero, the validator was unfrozen.
+	frozenValidators *frozenValidatorIndex
+	// Subnet ID + start of the churn period --> churn of the subnet's
+	// validator set in the period
+	validatorChurns map[validatorChurnKey]*ValidatorChurn
 	// Subnet ID --> Tx that transforms the subnet
 	transformedSubnets map[ids.ID]*txs.Tx
 	cachedSubnets      []*txs.Tx
@@ -73,6 +79,8 @@ func NewDiff(
 		stateVersions: stateVersions,
 		timestamp:     parentState.GetTimestamp(),
 		subnetOwners:  make(map[ids.ID]fx.Owner),
+
+		frozenValidators: newFrozenValidatorIndex(),
 	}, nil
 }
 
@@ -314,6 +322,149 @@ func (d *diff) SetSubnetOwner(subnetID ids.ID, owner fx.Owner) {
 	d.subnetOwners[subnetID] = owner
 }
 
+func (d *diff) GetFrozenUntil(subnetID ids.ID, nodeID ids.NodeID) (time.Time, error) {
+	if until, ok := d.frozenValidators.get(subnetID, nodeID); ok {
+		if until.IsZero() {
+			return time.Time{}, database.ErrNotFound
+		}
+		return until, nil
+	}
+
+	// If the validator wasn't frozen or unfrozen in this diff, ask the parent
+	// state.
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	return parentState.GetFrozenUntil(subnetID, nodeID)
+}
+
+func (d *diff) SetFrozenUntil(subnetID ids.ID, nodeID ids.NodeID, until time.Time) {
+	d.frozenValidators.set(subnetID, nodeID, until)
+}
+
+func (d *diff) GetValidatorChurn(subnetID ids.ID, periodStart time.Time) (*ValidatorChurn, error) {
+	key := validatorChurnKey{
+		subnetID:    subnetID,
+		periodStart: periodStart.Unix(),
+	}
+	if churn, ok := d.validatorChurns[key]; ok {
+		return churn, nil
+	}
+
+	// If the churn wasn't modified in this diff, ask the parent state.
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	return parentState.GetValidatorChurn(subnetID, periodStart)
+}
+
+func (d *diff) SetValidatorChurn(subnetID ids.ID, periodStart time.Time, churn *ValidatorChurn) {
+	if d.validatorChurns == nil {
+		d.validatorChurns = make(map[validatorChurnKey]*ValidatorChurn)
+	}
+	d.validatorChurns[validatorChurnKey{
+		subnetID:    subnetID,
+		periodStart: periodStart.Unix(),
+	}] = churn
+}
+
+func (d *diff) GetFrozenValidatorIterator() (FrozenValidatorIterator, error) {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	parentIterator, err := parentState.GetFrozenValidatorIterator()
+	if err != nil {
+		return nil, err
+	}
+	return newFrozenValidatorDiffIterator(
+		parentIterator,
+		d.frozenValidators.iterator(),
+		d.frozenValidators.until,
+	), nil
+}
+
+func (d *diff) GetSubnetWeight(subnetID ids.ID) (uint64, error) {
+	parentState, ok := d.stateVersions.GetState(d.parentID)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMissingParentState, d.parentID)
+	}
+	parentWeight, err := parentState.GetSubnetWeight(subnetID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only the stakers of [subnetID] that were modified by this diff, and the
+	// validators that were frozen or unfrozen by it, change the weight of the
+	// parent state.
+	weight := &subnetWeight{low: parentWeight}
+	validatorDiffs := d.currentStakerDiffs.validatorDiffs[subnetID]
+	for _, validatorDiff := range validatorDiffs {
+		switch validatorDiff.validatorStatus {
+		case added:
+			isFrozen, err := isFrozenValidator(d, validatorDiff.validator)
+			if err != nil {
+				return 0, err
+			}
+			if !isFrozen {
+				weight.add(validatorDiff.validator.Weight)
+			}
+		case deleted:
+			wasFrozen, err := isFrozenValidator(parentState, validatorDiff.validator)
+			if err != nil {
+				return 0, err
+			}
+			if !wasFrozen {
+				weight.remove(validatorDiff.validator.Weight)
+			}
+		}
+
+		if validatorDiff.addedDelegators != nil {
+			validatorDiff.addedDelegators.Ascend(func(delegator *Staker) bool {
+				if _, ok := validatorDiff.deletedDelegators[delegator.TxID]; !ok {
+					weight.add(delegator.Weight)
+				}
+				return true
+			})
+		}
+		for _, delegator := range validatorDiff.deletedDelegators {
+			if validatorDiff.addedDelegators == nil || !validatorDiff.addedDelegators.Has(delegator) {
+				weight.remove(delegator.Weight)
+			}
+		}
+	}
+
+	for nodeID, until := range d.frozenValidators.until[subnetID] {
+		if validatorDiff, ok := validatorDiffs[nodeID]; ok && validatorDiff.validatorStatus != unmodified {
+			// The weight of added and deleted validators was counted above.
+			continue
+		}
+
+		validator, err := parentState.GetCurrentValidator(subnetID, nodeID)
+		if err == database.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		wasFrozen, err := isFrozenValidator(parentState, validator)
+		if err != nil {
+			return 0, err
+		}
+		isFrozen := !until.IsZero()
+		switch {
+		case !wasFrozen && isFrozen:
+			weight.remove(validator.Weight)
+		case wasFrozen && !isFrozen:
+			weight.add(validator.Weight)
+		}
+	}
+	return weight.Uint64()
+}
+
 func (d *diff) GetSubnetTransformation(subnetID ids.ID) (*txs.Tx, error) {
 	tx, exists := d.transformedSubnets[subnetID]
 	if exists {
@@ -560,5 +711,13 @@ func (d *diff) Apply(baseState State) error {
 	for subnetID, owner := range d.subnetOwners {
 		baseState.SetSubnetOwner(subnetID, owner)
 	}
+	for subnetID, nodes := range d.frozenValidators.until {
+		for nodeID, until := range nodes {
+			baseState.SetFrozenUntil(subnetID, nodeID, until)
+		}
+	}
+	for key, churn := range d.validatorChurns {
+		baseState.SetValidatorChurn(key.subnetID, time.Unix(key.periodStart, 0), churn)
+	}
 	return nil
 }
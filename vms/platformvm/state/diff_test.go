@@ -578,3 +578,140 @@ func TestDiffSubnetOwner(t *testing.T) {
 	require.NoError(err)
 	require.Equal(owner2, owner)
 }
+
+func TestDiffFrozenValidatorIterator(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	state, _ := newInitializedState(require)
+
+	states := NewMockVersions(ctrl)
+	lastAcceptedID := ids.GenerateTestID()
+	states.EXPECT().GetState(lastAcceptedID).Return(state, true).AnyTimes()
+
+	var (
+		subnetID = ids.GenerateTestID()
+		nodeID0  = ids.GenerateTestNodeID()
+		nodeID1  = ids.GenerateTestNodeID()
+		nodeID2  = ids.GenerateTestNodeID()
+		nodeID3  = ids.GenerateTestNodeID()
+		until    = initialTime.Add(time.Hour)
+	)
+
+	state.SetFrozenUntil(subnetID, nodeID0, until.Add(2*time.Second))
+	state.SetFrozenUntil(subnetID, nodeID1, until)
+	state.SetFrozenUntil(subnetID, nodeID2, until.Add(4*time.Second))
+	requireFrozenValidators(t, state, nodeID1, nodeID0, nodeID2)
+
+	d, err := NewDiff(lastAcceptedID, states)
+	require.NoError(err)
+	requireFrozenValidators(t, d, nodeID1, nodeID0, nodeID2)
+
+	// Modifications of the diff are ordered with the frozen validators of the
+	// parent state and mask them.
+	d.SetFrozenUntil(subnetID, nodeID0, time.Time{})
+	d.SetFrozenUntil(subnetID, nodeID1, until.Add(3*time.Second))
+	d.SetFrozenUntil(subnetID, nodeID3, until.Add(time.Second))
+	requireFrozenValidators(t, d, nodeID3, nodeID1, nodeID2)
+	requireFrozenValidators(t, state, nodeID1, nodeID0, nodeID2)
+
+	require.NoError(d.Apply(state))
+	requireFrozenValidators(t, state, nodeID3, nodeID1, nodeID2)
+}
+
+// requireFrozenValidators requires that the frozen validators of [chain] are
+// [nodeIDs], in the order that they are unfrozen.
+func requireFrozenValidators(t *testing.T, chain Chain, nodeIDs ...ids.NodeID) {
+	require := require.New(t)
+
+	it, err := chain.GetFrozenValidatorIterator()
+	require.NoError(err)
+	defer it.Release()
+
+	var frozenNodeIDs []ids.NodeID
+	for it.Next() {
+		frozenNodeIDs = append(frozenNodeIDs, it.Value().NodeID)
+	}
+	require.Equal(nodeIDs, frozenNodeIDs)
+}
+
+func TestDiffSubnetWeight(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	baseState, _ := newInitializedState(require)
+
+	states := NewMockVersions(ctrl)
+	lastAcceptedID := ids.GenerateTestID()
+	states.EXPECT().GetState(lastAcceptedID).Return(baseState, true).AnyTimes()
+
+	var (
+		subnetID = ids.GenerateTestID()
+		until    = initialTime.Add(time.Hour)
+
+		newStaker = func(nodeID ids.NodeID, weight uint64, priority txs.Priority) *Staker {
+			return &Staker{
+				TxID:     ids.GenerateTestID(),
+				NodeID:   nodeID,
+				SubnetID: subnetID,
+				Weight:   weight,
+				NextTime: until,
+				Priority: priority,
+			}
+		}
+		requireSubnetWeight = func(chain Chain, expected uint64) {
+			weight, err := chain.GetSubnetWeight(subnetID)
+			require.NoError(err)
+			require.Equal(expected, weight)
+		}
+
+		permissionedValidator0  = newStaker(ids.GenerateTestNodeID(), 1, txs.SubnetPermissionedValidatorCurrentPriority)
+		permissionedValidator1  = newStaker(ids.GenerateTestNodeID(), 2, txs.SubnetPermissionedValidatorCurrentPriority)
+		permissionlessValidator = newStaker(ids.GenerateTestNodeID(), 4, txs.SubnetPermissionlessValidatorCurrentPriority)
+		delegator0              = newStaker(permissionlessValidator.NodeID, 8, txs.SubnetPermissionlessDelegatorCurrentPriority)
+		delegator1              = newStaker(permissionlessValidator.NodeID, 16, txs.SubnetPermissionlessDelegatorCurrentPriority)
+		delegator2              = newStaker(permissionlessValidator.NodeID, 32, txs.SubnetPermissionlessDelegatorCurrentPriority)
+	)
+
+	requireSubnetWeight(baseState, 0)
+
+	baseState.PutCurrentValidator(permissionedValidator0)
+	baseState.PutCurrentValidator(permissionlessValidator)
+	baseState.PutCurrentDelegator(delegator0)
+	requireSubnetWeight(baseState, 13)
+
+	// Frozen validators have no weight.
+	baseState.SetFrozenUntil(subnetID, permissionedValidator0.NodeID, until)
+	requireSubnetWeight(baseState, 12)
+
+	d, err := NewDiff(lastAcceptedID, states)
+	require.NoError(err)
+	requireSubnetWeight(d, 12)
+
+	// Unfreezing a validator of the parent state adds back its weight.
+	d.SetFrozenUntil(subnetID, permissionedValidator0.NodeID, time.Time{})
+	requireSubnetWeight(d, 13)
+
+	// A validator that is added and frozen in the diff has no weight.
+	d.PutCurrentValidator(permissionedValidator1)
+	requireSubnetWeight(d, 15)
+	d.SetFrozenUntil(subnetID, permissionedValidator1.NodeID, until)
+	requireSubnetWeight(d, 13)
+
+	d.DeleteCurrentDelegator(delegator0)
+	d.PutCurrentDelegator(delegator1)
+	requireSubnetWeight(d, 21)
+
+	// A delegator that is added and deleted in the diff has no weight.
+	d.PutCurrentDelegator(delegator2)
+	d.DeleteCurrentDelegator(delegator2)
+	requireSubnetWeight(d, 21)
+	requireSubnetWeight(baseState, 12)
+
+	require.NoError(d.Apply(baseState))
+	requireSubnetWeight(baseState, 21)
+
+	// The weight matches the weight computed from all the current stakers.
+	require.NoError(baseState.(*state).initSubnetWeights())
+	requireSubnetWeight(baseState, 21)
+}
@@ -39,6 +39,14 @@ func marshalStartDiffKey(subnetID ids.ID, height uint64) []byte {
 	return key
 }
 
+// marshalHeightKey returns a key that orders [height] the same way as
+// [marshalStartDiffKey].
+func marshalHeightKey(height uint64) []byte {
+	key := make([]byte, database.Uint64Size)
+	packIterableHeight(key, height)
+	return key
+}
+
 func marshalDiffKey(subnetID ids.ID, height uint64, nodeID ids.NodeID) []byte {
 	key := make([]byte, diffKeyLength)
 	copy(key, subnetID[:])
@@ -0,0 +1,261 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/google/btree"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	_ btree.LessFunc[*FrozenValidator] = (*FrozenValidator).Less
+
+	_ FrozenValidatorIterator = (*frozenValidatorTreeIterator)(nil)
+	_ FrozenValidatorIterator = (*frozenValidatorDiffIterator)(nil)
+
+	// EmptyFrozenValidatorIterator contains no frozen validators.
+	EmptyFrozenValidatorIterator FrozenValidatorIterator = emptyFrozenValidatorIterator{}
+)
+
+// FrozenValidatorIterator defines an interface for iterating over frozen
+// validators in the order that they are unfrozen.
+type FrozenValidatorIterator interface {
+	// Next attempts to move the iterator to the next frozen validator. It
+	// returns false once there are no more frozen validators to return.
+	Next() bool
+
+	// Value returns the current frozen validator. Value should only be called
+	// after a call to Next which returned true.
+	Value() *FrozenValidator
+
+	// Release any resources associated with the iterator. This must be called
+	// after the interator is no longer needed.
+	Release()
+}
+
+// Less returns true if [v] is unfrozen before [than]. Ties are broken by the
+// subnet ID and then the node ID.
+func (v *FrozenValidator) Less(than *FrozenValidator) bool {
+	if v.Until.Before(than.Until) {
+		return true
+	}
+	if than.Until.Before(v.Until) {
+		return false
+	}
+	if cmp := bytes.Compare(v.SubnetID[:], than.SubnetID[:]); cmp != 0 {
+		return cmp < 0
+	}
+	return bytes.Compare(v.NodeID[:], than.NodeID[:]) < 0
+}
+
+// isFrozenValidator returns true if [staker] is a permissioned validator that
+// is frozen in [chain].
+func isFrozenValidator(chain Chain, staker *Staker) (bool, error) {
+	// Invariant: Only permissioned validators can be frozen.
+	if !staker.Priority.IsPermissionedValidator() {
+		return false, nil
+	}
+	_, err := chain.GetFrozenUntil(staker.SubnetID, staker.NodeID)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// frozenValidatorIndex is an in-memory index of frozen validators ordered by
+// the time that they are unfrozen.
+type frozenValidatorIndex struct {
+	// Subnet ID --> Node ID --> time that the validator is frozen until
+	until map[ids.ID]map[ids.NodeID]time.Time
+	tree  *btree.BTreeG[*FrozenValidator]
+}
+
+func newFrozenValidatorIndex() *frozenValidatorIndex {
+	return &frozenValidatorIndex{
+		until: make(map[ids.ID]map[ids.NodeID]time.Time),
+		tree:  btree.NewG(defaultTreeDegree, (*FrozenValidator).Less),
+	}
+}
+
+// get returns the time that the validator is frozen until and true, or false
+// if the validator isn't in the index.
+func (f *frozenValidatorIndex) get(subnetID ids.ID, nodeID ids.NodeID) (time.Time, bool) {
+	until, ok := f.until[subnetID][nodeID]
+	return until, ok
+}
+
+// set records that the validator is frozen until [until]. If [until] is the
+// zero time, the validator is removed from the tree but kept in the map, so
+// that unfreezes can be recorded by diffs.
+func (f *frozenValidatorIndex) set(subnetID ids.ID, nodeID ids.NodeID, until time.Time) {
+	nodes, ok := f.until[subnetID]
+	if !ok {
+		nodes = make(map[ids.NodeID]time.Time)
+		f.until[subnetID] = nodes
+	}
+	if oldUntil, ok := nodes[nodeID]; ok && !oldUntil.IsZero() {
+		f.tree.Delete(&FrozenValidator{
+			SubnetID: subnetID,
+			NodeID:   nodeID,
+			Until:    oldUntil,
+		})
+	}
+	nodes[nodeID] = until
+	if !until.IsZero() {
+		f.tree.ReplaceOrInsert(&FrozenValidator{
+			SubnetID: subnetID,
+			NodeID:   nodeID,
+			Until:    until,
+		})
+	}
+}
+
+// delete removes the validator from the index.
+func (f *frozenValidatorIndex) delete(subnetID ids.ID, nodeID ids.NodeID) {
+	nodes := f.until[subnetID]
+	if until, ok := nodes[nodeID]; ok && !until.IsZero() {
+		f.tree.Delete(&FrozenValidator{
+			SubnetID: subnetID,
+			NodeID:   nodeID,
+			Until:    until,
+		})
+	}
+	delete(nodes, nodeID)
+	if len(nodes) == 0 {
+		delete(f.until, subnetID)
+	}
+}
+
+func (f *frozenValidatorIndex) iterator() FrozenValidatorIterator {
+	return &frozenValidatorTreeIterator{
+		tree: f.tree,
+	}
+}
+
+type emptyFrozenValidatorIterator struct{}
+
+func (emptyFrozenValidatorIterator) Next() bool {
+	return false
+}
+
+func (emptyFrozenValidatorIterator) Value() *FrozenValidator {
+	return nil
+}
+
+func (emptyFrozenValidatorIterator) Release() {}
+
+// frozenValidatorTreeIterator iterates over the frozen validators in [tree] in
+// ascending order. Each call to Next is a lookup of the successor of the
+// current frozen validator, so no resources are held between calls. Note that
+// it isn't safe to modify [tree] while iterating over it.
+type frozenValidatorTreeIterator struct {
+	tree    *btree.BTreeG[*FrozenValidator]
+	current *FrozenValidator
+	done    bool
+}
+
+func (i *frozenValidatorTreeIterator) Next() bool {
+	if i.done {
+		return false
+	}
+
+	var next *FrozenValidator
+	if i.current == nil {
+		next, _ = i.tree.Min()
+	} else {
+		i.tree.AscendGreaterOrEqual(i.current, func(v *FrozenValidator) bool {
+			if !i.current.Less(v) {
+				return true
+			}
+			next = v
+			return false
+		})
+	}
+	i.current = next
+	i.done = next == nil
+	return !i.done
+}
+
+func (i *frozenValidatorTreeIterator) Value() *FrozenValidator {
+	return i.current
+}
+
+func (*frozenValidatorTreeIterator) Release() {}
+
+// frozenValidatorDiffIterator merges the frozen validators of a diff with the
+// frozen validators of its parent that weren't modified by the diff.
+type frozenValidatorDiffIterator struct {
+	parent  FrozenValidatorIterator
+	added   FrozenValidatorIterator
+	masked  map[ids.ID]map[ids.NodeID]time.Time
+	current *FrozenValidator
+
+	initialized    bool
+	parentHasValue bool
+	addedHasValue  bool
+}
+
+func newFrozenValidatorDiffIterator(
+	parent FrozenValidatorIterator,
+	added FrozenValidatorIterator,
+	masked map[ids.ID]map[ids.NodeID]time.Time,
+) FrozenValidatorIterator {
+	return &frozenValidatorDiffIterator{
+		parent: parent,
+		added:  added,
+		masked: masked,
+	}
+}
+
+func (i *frozenValidatorDiffIterator) Next() bool {
+	if !i.initialized {
+		i.initialized = true
+		i.nextParent()
+		i.addedHasValue = i.added.Next()
+	}
+
+	switch {
+	case i.parentHasValue && (!i.addedHasValue || i.parent.Value().Less(i.added.Value())):
+		i.current = i.parent.Value()
+		i.nextParent()
+	case i.addedHasValue:
+		i.current = i.added.Value()
+		i.addedHasValue = i.added.Next()
+	default:
+		i.current = nil
+		return false
+	}
+	return true
+}
+
+// nextParent advances [parent] to the next frozen validator that wasn't
+// modified by the diff.
+func (i *frozenValidatorDiffIterator) nextParent() {
+	for i.parent.Next() {
+		v := i.parent.Value()
+		if _, ok := i.masked[v.SubnetID][v.NodeID]; !ok {
+			i.parentHasValue = true
+			return
+		}
+	}
+	i.parentHasValue = false
+}
+
+func (i *frozenValidatorDiffIterator) Value() *FrozenValidator {
+	return i.current
+}
+
+func (i *frozenValidatorDiffIterator) Release() {
+	i.parent.Release()
+	i.added.Release()
+}
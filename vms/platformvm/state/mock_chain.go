@@ -264,6 +264,36 @@ func (mr *MockChainMockRecorder) GetDelegateeReward(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegateeReward", reflect.TypeOf((*MockChain)(nil).GetDelegateeReward), arg0, arg1)
 }
 
+// GetFrozenUntil mocks base method.
+func (m *MockChain) GetFrozenUntil(arg0 ids.ID, arg1 ids.NodeID) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrozenUntil", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrozenUntil indicates an expected call of GetFrozenUntil.
+func (mr *MockChainMockRecorder) GetFrozenUntil(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrozenUntil", reflect.TypeOf((*MockChain)(nil).GetFrozenUntil), arg0, arg1)
+}
+
+// GetFrozenValidatorIterator mocks base method.
+func (m *MockChain) GetFrozenValidatorIterator() (FrozenValidatorIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrozenValidatorIterator")
+	ret0, _ := ret[0].(FrozenValidatorIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrozenValidatorIterator indicates an expected call of GetFrozenValidatorIterator.
+func (mr *MockChainMockRecorder) GetFrozenValidatorIterator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrozenValidatorIterator", reflect.TypeOf((*MockChain)(nil).GetFrozenValidatorIterator))
+}
+
 // GetPendingDelegatorIterator mocks base method.
 func (m *MockChain) GetPendingDelegatorIterator(arg0 ids.ID, arg1 ids.NodeID) (StakerIterator, error) {
 	m.ctrl.T.Helper()
@@ -354,6 +384,21 @@ func (mr *MockChainMockRecorder) GetSubnetTransformation(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetTransformation", reflect.TypeOf((*MockChain)(nil).GetSubnetTransformation), arg0)
 }
 
+// GetSubnetWeight mocks base method.
+func (m *MockChain) GetSubnetWeight(arg0 ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetWeight", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetWeight indicates an expected call of GetSubnetWeight.
+func (mr *MockChainMockRecorder) GetSubnetWeight(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetWeight", reflect.TypeOf((*MockChain)(nil).GetSubnetWeight), arg0)
+}
+
 // GetSubnets mocks base method.
 func (m *MockChain) GetSubnets() ([]*txs.Tx, error) {
 	m.ctrl.T.Helper()
@@ -414,6 +459,21 @@ func (mr *MockChainMockRecorder) GetUTXO(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockChain)(nil).GetUTXO), arg0)
 }
 
+// GetValidatorChurn mocks base method.
+func (m *MockChain) GetValidatorChurn(arg0 ids.ID, arg1 time.Time) (*ValidatorChurn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorChurn", arg0, arg1)
+	ret0, _ := ret[0].(*ValidatorChurn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorChurn indicates an expected call of GetValidatorChurn.
+func (mr *MockChainMockRecorder) GetValidatorChurn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorChurn", reflect.TypeOf((*MockChain)(nil).GetValidatorChurn), arg0, arg1)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockChain) PutCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -488,6 +548,18 @@ func (mr *MockChainMockRecorder) SetDelegateeReward(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDelegateeReward", reflect.TypeOf((*MockChain)(nil).SetDelegateeReward), arg0, arg1, arg2)
 }
 
+// SetFrozenUntil mocks base method.
+func (m *MockChain) SetFrozenUntil(arg0 ids.ID, arg1 ids.NodeID, arg2 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFrozenUntil", arg0, arg1, arg2)
+}
+
+// SetFrozenUntil indicates an expected call of SetFrozenUntil.
+func (mr *MockChainMockRecorder) SetFrozenUntil(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFrozenUntil", reflect.TypeOf((*MockChain)(nil).SetFrozenUntil), arg0, arg1, arg2)
+}
+
 // SetSubnetOwner mocks base method.
 func (m *MockChain) SetSubnetOwner(arg0 ids.ID, arg1 fx.Owner) {
 	m.ctrl.T.Helper()
@@ -511,3 +583,15 @@ func (mr *MockChainMockRecorder) SetTimestamp(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimestamp", reflect.TypeOf((*MockChain)(nil).SetTimestamp), arg0)
 }
+
+// SetValidatorChurn mocks base method.
+func (m *MockChain) SetValidatorChurn(arg0 ids.ID, arg1 time.Time, arg2 *ValidatorChurn) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetValidatorChurn", arg0, arg1, arg2)
+}
+
+// SetValidatorChurn indicates an expected call of SetValidatorChurn.
+func (mr *MockChainMockRecorder) SetValidatorChurn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidatorChurn", reflect.TypeOf((*MockChain)(nil).SetValidatorChurn), arg0, arg1, arg2)
+}
@@ -278,6 +278,36 @@ func (mr *MockDiffMockRecorder) GetDelegateeReward(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegateeReward", reflect.TypeOf((*MockDiff)(nil).GetDelegateeReward), arg0, arg1)
 }
 
+// GetFrozenUntil mocks base method.
+func (m *MockDiff) GetFrozenUntil(arg0 ids.ID, arg1 ids.NodeID) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrozenUntil", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrozenUntil indicates an expected call of GetFrozenUntil.
+func (mr *MockDiffMockRecorder) GetFrozenUntil(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrozenUntil", reflect.TypeOf((*MockDiff)(nil).GetFrozenUntil), arg0, arg1)
+}
+
+// GetFrozenValidatorIterator mocks base method.
+func (m *MockDiff) GetFrozenValidatorIterator() (FrozenValidatorIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrozenValidatorIterator")
+	ret0, _ := ret[0].(FrozenValidatorIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrozenValidatorIterator indicates an expected call of GetFrozenValidatorIterator.
+func (mr *MockDiffMockRecorder) GetFrozenValidatorIterator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrozenValidatorIterator", reflect.TypeOf((*MockDiff)(nil).GetFrozenValidatorIterator))
+}
+
 // GetPendingDelegatorIterator mocks base method.
 func (m *MockDiff) GetPendingDelegatorIterator(arg0 ids.ID, arg1 ids.NodeID) (StakerIterator, error) {
 	m.ctrl.T.Helper()
@@ -368,6 +398,21 @@ func (mr *MockDiffMockRecorder) GetSubnetTransformation(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetTransformation", reflect.TypeOf((*MockDiff)(nil).GetSubnetTransformation), arg0)
 }
 
+// GetSubnetWeight mocks base method.
+func (m *MockDiff) GetSubnetWeight(arg0 ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetWeight", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetWeight indicates an expected call of GetSubnetWeight.
+func (mr *MockDiffMockRecorder) GetSubnetWeight(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetWeight", reflect.TypeOf((*MockDiff)(nil).GetSubnetWeight), arg0)
+}
+
 // GetSubnets mocks base method.
 func (m *MockDiff) GetSubnets() ([]*txs.Tx, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +473,21 @@ func (mr *MockDiffMockRecorder) GetUTXO(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockDiff)(nil).GetUTXO), arg0)
 }
 
+// GetValidatorChurn mocks base method.
+func (m *MockDiff) GetValidatorChurn(arg0 ids.ID, arg1 time.Time) (*ValidatorChurn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorChurn", arg0, arg1)
+	ret0, _ := ret[0].(*ValidatorChurn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorChurn indicates an expected call of GetValidatorChurn.
+func (mr *MockDiffMockRecorder) GetValidatorChurn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorChurn", reflect.TypeOf((*MockDiff)(nil).GetValidatorChurn), arg0, arg1)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockDiff) PutCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -502,6 +562,18 @@ func (mr *MockDiffMockRecorder) SetDelegateeReward(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDelegateeReward", reflect.TypeOf((*MockDiff)(nil).SetDelegateeReward), arg0, arg1, arg2)
 }
 
+// SetFrozenUntil mocks base method.
+func (m *MockDiff) SetFrozenUntil(arg0 ids.ID, arg1 ids.NodeID, arg2 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFrozenUntil", arg0, arg1, arg2)
+}
+
+// SetFrozenUntil indicates an expected call of SetFrozenUntil.
+func (mr *MockDiffMockRecorder) SetFrozenUntil(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFrozenUntil", reflect.TypeOf((*MockDiff)(nil).SetFrozenUntil), arg0, arg1, arg2)
+}
+
 // SetSubnetOwner mocks base method.
 func (m *MockDiff) SetSubnetOwner(arg0 ids.ID, arg1 fx.Owner) {
 	m.ctrl.T.Helper()
@@ -525,3 +597,15 @@ func (mr *MockDiffMockRecorder) SetTimestamp(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTimestamp", reflect.TypeOf((*MockDiff)(nil).SetTimestamp), arg0)
 }
+
+// SetValidatorChurn mocks base method.
+func (m *MockDiff) SetValidatorChurn(arg0 ids.ID, arg1 time.Time, arg2 *ValidatorChurn) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetValidatorChurn", arg0, arg1, arg2)
+}
+
+// SetValidatorChurn indicates an expected call of SetValidatorChurn.
+func (mr *MockDiffMockRecorder) SetValidatorChurn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidatorChurn", reflect.TypeOf((*MockDiff)(nil).SetValidatorChurn), arg0, arg1, arg2)
+}
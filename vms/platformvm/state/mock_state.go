@@ -243,6 +243,20 @@ func (mr *MockStateMockRecorder) CommitBatch() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitBatch", reflect.TypeOf((*MockState)(nil).CommitBatch))
 }
 
+// CompactPruned mocks base method.
+func (m *MockState) CompactPruned(arg0 context.Context, arg1 time.Duration, arg2 logging.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactPruned", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompactPruned indicates an expected call of CompactPruned.
+func (mr *MockStateMockRecorder) CompactPruned(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactPruned", reflect.TypeOf((*MockState)(nil).CompactPruned), arg0, arg1, arg2)
+}
+
 // DeleteCurrentDelegator mocks base method.
 func (m *MockState) DeleteCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -408,6 +422,36 @@ func (mr *MockStateMockRecorder) GetDelegateeReward(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegateeReward", reflect.TypeOf((*MockState)(nil).GetDelegateeReward), arg0, arg1)
 }
 
+// GetFrozenUntil mocks base method.
+func (m *MockState) GetFrozenUntil(arg0 ids.ID, arg1 ids.NodeID) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrozenUntil", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrozenUntil indicates an expected call of GetFrozenUntil.
+func (mr *MockStateMockRecorder) GetFrozenUntil(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrozenUntil", reflect.TypeOf((*MockState)(nil).GetFrozenUntil), arg0, arg1)
+}
+
+// GetFrozenValidatorIterator mocks base method.
+func (m *MockState) GetFrozenValidatorIterator() (FrozenValidatorIterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrozenValidatorIterator")
+	ret0, _ := ret[0].(FrozenValidatorIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrozenValidatorIterator indicates an expected call of GetFrozenValidatorIterator.
+func (mr *MockStateMockRecorder) GetFrozenValidatorIterator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrozenValidatorIterator", reflect.TypeOf((*MockState)(nil).GetFrozenValidatorIterator))
+}
+
 // GetLastAccepted mocks base method.
 func (m *MockState) GetLastAccepted() ids.ID {
 	m.ctrl.T.Helper()
@@ -467,6 +511,36 @@ func (mr *MockStateMockRecorder) GetPendingValidator(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingValidator", reflect.TypeOf((*MockState)(nil).GetPendingValidator), arg0, arg1)
 }
 
+// GetRewardPayoutsByAddress mocks base method.
+func (m *MockState) GetRewardPayoutsByAddress(arg0 ids.ShortID, arg1 []byte, arg2 int) ([]*RewardPayout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardPayoutsByAddress", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*RewardPayout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardPayoutsByAddress indicates an expected call of GetRewardPayoutsByAddress.
+func (mr *MockStateMockRecorder) GetRewardPayoutsByAddress(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardPayoutsByAddress", reflect.TypeOf((*MockState)(nil).GetRewardPayoutsByAddress), arg0, arg1, arg2)
+}
+
+// GetRewardPayoutsByNodeID mocks base method.
+func (m *MockState) GetRewardPayoutsByNodeID(arg0 ids.NodeID, arg1 []byte, arg2 int) ([]*RewardPayout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardPayoutsByNodeID", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*RewardPayout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardPayoutsByNodeID indicates an expected call of GetRewardPayoutsByNodeID.
+func (mr *MockStateMockRecorder) GetRewardPayoutsByNodeID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardPayoutsByNodeID", reflect.TypeOf((*MockState)(nil).GetRewardPayoutsByNodeID), arg0, arg1, arg2)
+}
+
 // GetRewardUTXOs mocks base method.
 func (m *MockState) GetRewardUTXOs(arg0 ids.ID) ([]*avax.UTXO, error) {
 	m.ctrl.T.Helper()
@@ -542,6 +616,21 @@ func (mr *MockStateMockRecorder) GetSubnetTransformation(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetTransformation", reflect.TypeOf((*MockState)(nil).GetSubnetTransformation), arg0)
 }
 
+// GetSubnetWeight mocks base method.
+func (m *MockState) GetSubnetWeight(arg0 ids.ID) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubnetWeight", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubnetWeight indicates an expected call of GetSubnetWeight.
+func (mr *MockStateMockRecorder) GetSubnetWeight(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetWeight", reflect.TypeOf((*MockState)(nil).GetSubnetWeight), arg0)
+}
+
 // GetSubnets mocks base method.
 func (m *MockState) GetSubnets() ([]*txs.Tx, error) {
 	m.ctrl.T.Helper()
@@ -557,6 +646,36 @@ func (mr *MockStateMockRecorder) GetSubnets() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnets", reflect.TypeOf((*MockState)(nil).GetSubnets))
 }
 
+// GetSupplyAt mocks base method.
+func (m *MockState) GetSupplyAt(arg0 ids.ID, arg1 uint64) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupplyAt", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupplyAt indicates an expected call of GetSupplyAt.
+func (mr *MockStateMockRecorder) GetSupplyAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupplyAt", reflect.TypeOf((*MockState)(nil).GetSupplyAt), arg0, arg1)
+}
+
+// GetSupplyChanges mocks base method.
+func (m *MockState) GetSupplyChanges(arg0 ids.ID, arg1, arg2 uint64) ([]SupplyChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupplyChanges", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]SupplyChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupplyChanges indicates an expected call of GetSupplyChanges.
+func (mr *MockStateMockRecorder) GetSupplyChanges(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupplyChanges", reflect.TypeOf((*MockState)(nil).GetSupplyChanges), arg0, arg1, arg2)
+}
+
 // GetTimestamp mocks base method.
 func (m *MockState) GetTimestamp() time.Time {
 	m.ctrl.T.Helper()
@@ -571,6 +690,21 @@ func (mr *MockStateMockRecorder) GetTimestamp() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimestamp", reflect.TypeOf((*MockState)(nil).GetTimestamp))
 }
 
+// GetTimestampAt mocks base method.
+func (m *MockState) GetTimestampAt(arg0 uint64) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimestampAt", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimestampAt indicates an expected call of GetTimestampAt.
+func (mr *MockStateMockRecorder) GetTimestampAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimestampAt", reflect.TypeOf((*MockState)(nil).GetTimestampAt), arg0)
+}
+
 // GetTx mocks base method.
 func (m *MockState) GetTx(arg0 ids.ID) (*txs.Tx, status.Status, error) {
 	m.ctrl.T.Helper()
@@ -602,6 +736,21 @@ func (mr *MockStateMockRecorder) GetUTXO(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockState)(nil).GetUTXO), arg0)
 }
 
+// GetUTXOAt mocks base method.
+func (m *MockState) GetUTXOAt(arg0 ids.ID, arg1 uint64) (*avax.UTXO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUTXOAt", arg0, arg1)
+	ret0, _ := ret[0].(*avax.UTXO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUTXOAt indicates an expected call of GetUTXOAt.
+func (mr *MockStateMockRecorder) GetUTXOAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXOAt", reflect.TypeOf((*MockState)(nil).GetUTXOAt), arg0, arg1)
+}
+
 // GetUptime mocks base method.
 func (m *MockState) GetUptime(arg0 ids.NodeID, arg1 ids.ID) (time.Duration, time.Time, error) {
 	m.ctrl.T.Helper()
@@ -618,6 +767,21 @@ func (mr *MockStateMockRecorder) GetUptime(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUptime", reflect.TypeOf((*MockState)(nil).GetUptime), arg0, arg1)
 }
 
+// GetValidatorChurn mocks base method.
+func (m *MockState) GetValidatorChurn(arg0 ids.ID, arg1 time.Time) (*ValidatorChurn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetValidatorChurn", arg0, arg1)
+	ret0, _ := ret[0].(*ValidatorChurn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetValidatorChurn indicates an expected call of GetValidatorChurn.
+func (mr *MockStateMockRecorder) GetValidatorChurn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetValidatorChurn", reflect.TypeOf((*MockState)(nil).GetValidatorChurn), arg0, arg1)
+}
+
 // PruneAndIndex mocks base method.
 func (m *MockState) PruneAndIndex(arg0 sync.Locker, arg1 logging.Logger) error {
 	m.ctrl.T.Helper()
@@ -706,6 +870,18 @@ func (mr *MockStateMockRecorder) SetDelegateeReward(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDelegateeReward", reflect.TypeOf((*MockState)(nil).SetDelegateeReward), arg0, arg1, arg2)
 }
 
+// SetFrozenUntil mocks base method.
+func (m *MockState) SetFrozenUntil(arg0 ids.ID, arg1 ids.NodeID, arg2 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetFrozenUntil", arg0, arg1, arg2)
+}
+
+// SetFrozenUntil indicates an expected call of SetFrozenUntil.
+func (mr *MockStateMockRecorder) SetFrozenUntil(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFrozenUntil", reflect.TypeOf((*MockState)(nil).SetFrozenUntil), arg0, arg1, arg2)
+}
+
 // SetHeight mocks base method.
 func (m *MockState) SetHeight(arg0 uint64) {
 	m.ctrl.T.Helper()
@@ -768,6 +944,18 @@ func (mr *MockStateMockRecorder) SetUptime(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUptime", reflect.TypeOf((*MockState)(nil).SetUptime), arg0, arg1, arg2, arg3)
 }
 
+// SetValidatorChurn mocks base method.
+func (m *MockState) SetValidatorChurn(arg0 ids.ID, arg1 time.Time, arg2 *ValidatorChurn) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetValidatorChurn", arg0, arg1, arg2)
+}
+
+// SetValidatorChurn indicates an expected call of SetValidatorChurn.
+func (mr *MockStateMockRecorder) SetValidatorChurn(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidatorChurn", reflect.TypeOf((*MockState)(nil).SetValidatorChurn), arg0, arg1, arg2)
+}
+
 // ShouldPrune mocks base method.
 func (m *MockState) ShouldPrune() (bool, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,207 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+const (
+	// RewardPayoutCursorLength is the length of the cursor of a reward
+	// payout.
+	//
+	// rewardPayoutCursor = [inverseHeight] + [utxoID]
+	RewardPayoutCursorLength = database.Uint64Size + ids.IDLen
+	// rewardPayoutKey = [owner] + [rewardPayoutCursor]
+	rewardPayoutKeyLength = ids.ShortIDLen + RewardPayoutCursorLength
+)
+
+var (
+	ErrInvalidRewardPayoutCursor = fmt.Errorf("expected reward payout cursor length %d", RewardPayoutCursorLength)
+	ErrRewardPayoutsNotIndexed   = errors.New("reward payouts not indexed")
+
+	errUnexpectedRewardPayoutKeyLength = fmt.Errorf("expected reward payout key length %d", rewardPayoutKeyLength)
+	errUnexpectedRewardedTx            = errors.New("rewarded tx isn't a staker")
+	errUnexpectedRewardOutput          = errors.New("unexpected reward output type")
+)
+
+// RewardPayout is a reward UTXO that was produced when a staker was removed
+// from the current staker set.
+type RewardPayout struct {
+	// ID of the staker tx that was rewarded.
+	TxID        ids.ID     `serialize:"true"`
+	OutputIndex uint32     `serialize:"true"`
+	SubnetID    ids.ID     `serialize:"true"`
+	NodeID      ids.NodeID `serialize:"true"`
+	AssetID     ids.ID     `serialize:"true"`
+	Amount      uint64     `serialize:"true"`
+	// Addresses that the reward was paid to.
+	Addresses []ids.ShortID `serialize:"true"`
+	// Height of the block that paid the reward.
+	Height uint64 `serialize:"true"`
+	// Unix time of the chain when the reward was paid.
+	Timestamp uint64 `serialize:"true"`
+}
+
+// UTXOID returns the ID of the reward UTXO.
+func (p *RewardPayout) UTXOID() ids.ID {
+	return p.TxID.Prefix(uint64(p.OutputIndex))
+}
+
+// Cursor returns the position of [p] in the reward history. Payouts are
+// ordered from the most recent to the oldest.
+func (p *RewardPayout) Cursor() []byte {
+	cursor := make([]byte, RewardPayoutCursorLength)
+	packIterableHeight(cursor, p.Height)
+	utxoID := p.UTXOID()
+	copy(cursor[database.Uint64Size:], utxoID[:])
+	return cursor
+}
+
+func marshalRewardPayoutKey(owner ids.ShortID, cursor []byte) []byte {
+	key := make([]byte, rewardPayoutKeyLength)
+	copy(key, owner[:])
+	copy(key[ids.ShortIDLen:], cursor)
+	return key
+}
+
+// writeRewardPayouts indexes the reward UTXOs that are being written by the
+// addresses they were paid to and by the node ID of the rewarded staker.
+//
+// Must be called before writeRewardUTXOs.
+func (s *state) writeRewardPayouts(height uint64) error {
+	// The rewards paid before the index was created aren't indexed, so the
+	// first indexed height is recorded to report them as missing.
+	if s.rewardPayoutsStartHeight == nil {
+		if err := database.PutUInt64(s.singletonDB, rewardPayoutsStartHeightKey, height); err != nil {
+			return fmt.Errorf("failed to write reward payouts start height: %w", err)
+		}
+		s.rewardPayoutsStartHeight = &height
+	}
+
+	for txID, utxos := range s.addedRewardUTXOs {
+		stakerTx, _, err := s.GetTx(txID)
+		if err != nil {
+			return fmt.Errorf("failed to get rewarded tx %s: %w", txID, err)
+		}
+		staker, ok := stakerTx.Unsigned.(txs.Staker)
+		if !ok {
+			return fmt.Errorf("%w: %T", errUnexpectedRewardedTx, stakerTx.Unsigned)
+		}
+		nodeID := staker.NodeID()
+
+		for _, utxo := range utxos {
+			out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+			if !ok {
+				return fmt.Errorf("%w: %T", errUnexpectedRewardOutput, utxo.Out)
+			}
+
+			payout := &RewardPayout{
+				TxID:        txID,
+				OutputIndex: utxo.OutputIndex,
+				SubnetID:    staker.SubnetID(),
+				NodeID:      nodeID,
+				AssetID:     utxo.AssetID(),
+				Amount:      out.Amt,
+				Addresses:   out.Addrs,
+				Height:      height,
+				Timestamp:   uint64(s.timestamp.Unix()),
+			}
+			payoutBytes, err := txs.GenesisCodec.Marshal(txs.Version, payout)
+			if err != nil {
+				return fmt.Errorf("failed to serialize reward payout: %w", err)
+			}
+
+			cursor := payout.Cursor()
+			key := marshalRewardPayoutKey(ids.ShortID(nodeID), cursor)
+			if err := s.rewardPayoutsByNodeIDDB.Put(key, payoutBytes); err != nil {
+				return fmt.Errorf("failed to write reward payout: %w", err)
+			}
+			for _, addr := range out.Addrs {
+				key := marshalRewardPayoutKey(addr, cursor)
+				if err := s.rewardPayoutsByAddressDB.Put(key, payoutBytes); err != nil {
+					return fmt.Errorf("failed to write reward payout: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *state) GetRewardPayoutsByAddress(addr ids.ShortID, cursor []byte, limit int) ([]*RewardPayout, error) {
+	payouts, err := getRewardPayouts(s.rewardPayoutsByAddressDB, addr, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return payouts, s.checkRewardPayoutsIndexed(payouts)
+}
+
+func (s *state) GetRewardPayoutsByNodeID(nodeID ids.NodeID, cursor []byte, limit int) ([]*RewardPayout, error) {
+	payouts, err := getRewardPayouts(s.rewardPayoutsByNodeIDDB, ids.ShortID(nodeID), cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return payouts, s.checkRewardPayoutsIndexed(payouts)
+}
+
+// checkRewardPayoutsIndexed returns ErrRewardPayoutsNotIndexed if no more
+// [payouts] were found but payouts before the first indexed height may exist.
+func (s *state) checkRewardPayoutsIndexed(payouts []*RewardPayout) error {
+	switch {
+	case len(payouts) != 0:
+		return nil
+	case s.rewardPayoutsStartHeight == nil:
+		return ErrRewardPayoutsNotIndexed
+	case *s.rewardPayoutsStartHeight != 0:
+		return fmt.Errorf("%w: before height %d", ErrRewardPayoutsNotIndexed, *s.rewardPayoutsStartHeight)
+	default:
+		return nil
+	}
+}
+
+// getRewardPayouts returns up to [limit] of the payouts made to [owner] in
+// [db] that were made before the payout at [cursor]. If [cursor] is empty, the
+// most recent payouts are returned.
+func getRewardPayouts(
+	db database.Iteratee,
+	owner ids.ShortID,
+	cursor []byte,
+	limit int,
+) ([]*RewardPayout, error) {
+	if len(cursor) != 0 && len(cursor) != RewardPayoutCursorLength {
+		return nil, ErrInvalidRewardPayoutCursor
+	}
+
+	it := db.NewIteratorWithStartAndPrefix(
+		marshalRewardPayoutKey(owner, cursor),
+		owner[:],
+	)
+	defer it.Release()
+
+	var payouts []*RewardPayout
+	for len(payouts) < limit && it.Next() {
+		key := it.Key()
+		if len(key) != rewardPayoutKeyLength {
+			return nil, errUnexpectedRewardPayoutKeyLength
+		}
+		if len(cursor) != 0 && bytes.Equal(key[ids.ShortIDLen:], cursor) {
+			// The payout at [cursor] was already returned.
+			continue
+		}
+
+		payout := &RewardPayout{}
+		if _, err := txs.GenesisCodec.Unmarshal(it.Value(), payout); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reward payout: %w", err)
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, it.Error()
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -53,6 +54,9 @@ const (
 	pruneCommitSleepMultiplier = 5
 	pruneCommitSleepCap        = 10 * time.Second
 	pruneUpdateFrequency       = 30 * time.Second
+
+	// The maximum number of heights whose history is pruned per block.
+	maxPrunedHistoryHeights = 64
 )
 
 var (
@@ -61,6 +65,10 @@ var (
 	errValidatorSetAlreadyPopulated = errors.New("validator set already populated")
 	errIsNotSubnet                  = errors.New("is not a subnet")
 
+	errUnexpectedFrozenValidatorKeyLength = fmt.Errorf("expected frozen validator key length %d", frozenValidatorKeyLength)
+
+	ErrHeightNotRetained = errors.New("height not retained")
+
 	blockIDPrefix                       = []byte("blockID")
 	blockPrefix                         = []byte("block")
 	validatorsPrefix                    = []byte("validators")
@@ -76,20 +84,34 @@ var (
 	flatValidatorPublicKeyDiffsPrefix   = []byte("flatPublicKeyDiffs")
 	txPrefix                            = []byte("tx")
 	rewardUTXOsPrefix                   = []byte("rewardUTXOs")
+	rewardPayoutsByAddressPrefix        = []byte("rewardPayoutsByAddress")
+	rewardPayoutsByNodeIDPrefix         = []byte("rewardPayoutsByNodeID")
 	utxoPrefix                          = []byte("utxo")
 	subnetPrefix                        = []byte("subnet")
 	subnetOwnerPrefix                   = []byte("subnetOwner")
+	frozenValidatorPrefix               = []byte("frozenValidator")
+	validatorChurnPrefix                = []byte("validatorChurn")
 	transformedSubnetPrefix             = []byte("transformedSubnet")
 	supplyPrefix                        = []byte("supply")
+	supplyHistoryPrefix                 = []byte("supplyHistory")
+	utxoDiffsPrefix                     = []byte("utxoDiffs")
+	utxoDiffHeightsPrefix               = []byte("utxoDiffHeights")
+	timestampHistoryPrefix              = []byte("timestampHistory")
 	chainPrefix                         = []byte("chain")
 	singletonPrefix                     = []byte("singleton")
 
-	timestampKey      = []byte("timestamp")
-	currentSupplyKey  = []byte("current supply")
-	lastAcceptedKey   = []byte("last accepted")
-	heightsIndexedKey = []byte("heights indexed")
-	initializedKey    = []byte("initialized")
-	prunedKey         = []byte("pruned")
+	timestampKey          = []byte("timestamp")
+	currentSupplyKey      = []byte("current supply")
+	genesisSupplyKey      = []byte("genesis supply")
+	lastAcceptedKey       = []byte("last accepted")
+	heightsIndexedKey     = []byte("heights indexed")
+	historyStartHeightKey = []byte("history start height")
+	initializedKey        = []byte("initialized")
+	prunedKey             = []byte("pruned")
+
+	stateHistoryStartHeightKey = []byte("state history start height")
+
+	rewardPayoutsStartHeightKey = []byte("reward payouts start height")
 )
 
 // Chain collects all methods to manage the state of the chain for block
@@ -115,6 +137,28 @@ type Chain interface {
 	GetSubnetOwner(subnetID ids.ID) (fx.Owner, error)
 	SetSubnetOwner(subnetID ids.ID, owner fx.Owner)
 
+	// GetFrozenUntil returns the time that the current validator of
+	// [subnetID] with [nodeID] is frozen until. Returns database.ErrNotFound
+	// if the validator isn't frozen.
+	GetFrozenUntil(subnetID ids.ID, nodeID ids.NodeID) (time.Time, error)
+	// SetFrozenUntil freezes the current validator of [subnetID] with [nodeID]
+	// until [until]. If [until] is the zero time, the validator is unfrozen.
+	SetFrozenUntil(subnetID ids.ID, nodeID ids.NodeID, until time.Time)
+	// GetFrozenValidatorIterator returns the frozen validators in the order
+	// that they are unfrozen.
+	GetFrozenValidatorIterator() (FrozenValidatorIterator, error)
+
+	// GetValidatorChurn returns the churn of the validator set of [subnetID]
+	// in the churn period that starts at [periodStart]. Returns
+	// database.ErrNotFound if no churn was recorded in the period.
+	GetValidatorChurn(subnetID ids.ID, periodStart time.Time) (*ValidatorChurn, error)
+	SetValidatorChurn(subnetID ids.ID, periodStart time.Time, churn *ValidatorChurn)
+
+	// GetSubnetWeight returns the weight of the current validator set of
+	// [subnetID], including the delegators of its validators. Frozen
+	// validators have no weight.
+	GetSubnetWeight(subnetID ids.ID) (uint64, error)
+
 	GetSubnetTransformation(subnetID ids.ID) (*txs.Tx, error)
 	AddSubnetTransformation(transformSubnetTx *txs.Tx)
 
@@ -140,6 +184,43 @@ type State interface {
 
 	GetBlockIDAtHeight(height uint64) (ids.ID, error)
 
+	// GetSupplyAt returns the supply of [subnetID] once the block at [height]
+	// was accepted. Until the first recorded change, the supply of the primary
+	// network is its genesis supply. Returns ErrHeightNotRetained if [height]
+	// is before the history was recorded, and database.ErrNotFound if
+	// [subnetID] had no supply at [height].
+	GetSupplyAt(subnetID ids.ID, height uint64) (uint64, error)
+
+	// GetSupplyChanges returns the changes to the supply of [subnetID] made by
+	// the blocks with heights in [startHeight, endHeight], ordered by height.
+	GetSupplyChanges(subnetID ids.ID, startHeight, endHeight uint64) ([]SupplyChange, error)
+
+	// GetUTXOAt returns the UTXO with [utxoID] once the block at [height] was
+	// accepted. Returns database.ErrNotFound if the UTXO didn't exist at
+	// [height] and ErrHeightNotRetained if the state history of [height]
+	// isn't retained.
+	GetUTXOAt(utxoID ids.ID, height uint64) (*avax.UTXO, error)
+
+	// GetTimestampAt returns the chain timestamp once the block at [height]
+	// was accepted. Returns ErrHeightNotRetained if the state history of
+	// [height] isn't retained.
+	GetTimestampAt(height uint64) (time.Time, error)
+
+	// GetRewardPayoutsByAddress returns up to [limit] of the reward payouts
+	// made to [addr], from the most recent to the oldest, starting after the
+	// payout at [cursor]. If [cursor] is empty, the most recent payouts are
+	// returned. Returns ErrRewardPayoutsNotIndexed instead of an empty list
+	// if payouts made before the index was created may remain.
+	GetRewardPayoutsByAddress(addr ids.ShortID, cursor []byte, limit int) ([]*RewardPayout, error)
+
+	// GetRewardPayoutsByNodeID returns up to [limit] of the reward payouts
+	// made for stakers of [nodeID], from the most recent to the oldest,
+	// starting after the payout at [cursor]. If [cursor] is empty, the most
+	// recent payouts are returned. Returns ErrRewardPayoutsNotIndexed instead
+	// of an empty list if payouts made before the index was created may
+	// remain.
+	GetRewardPayoutsByNodeID(nodeID ids.NodeID, cursor []byte, limit int) ([]*RewardPayout, error)
+
 	// ApplyCurrentValidators adds all the current validators and delegators of
 	// [subnetID] into [vdrs].
 	ApplyCurrentValidators(subnetID ids.ID, vdrs validators.Manager) error
@@ -198,6 +279,15 @@ type State interface {
 	// TODO: Remove after v1.11.x is activated
 	PruneAndIndex(sync.Locker, logging.Logger) error
 
+	// Compacts the parts of the database that were modified by
+	// [PruneAndIndex()] so that the space freed by pruning is reclaimed. The
+	// database is compacted in ranges, waiting for [interval] before each
+	// compaction to limit the impact on the node. Returns the error of [ctx]
+	// if it's cancelled before all ranges are compacted.
+	//
+	// TODO: Remove after v1.11.x is activated
+	CompactPruned(ctx context.Context, interval time.Duration, log logging.Logger) error
+
 	// Commit changes to the base database.
 	Commit() error
 
@@ -268,13 +358,29 @@ type stateBlk struct {
  * | '-. txID
  * |   '-. list
  * |     '-- utxoID -> utxo bytes
+ * |-. rewardPayoutsByAddress
+ * | '-- address+height+utxoID -> reward payout bytes
+ * |-. rewardPayoutsByNodeID
+ * | '-- nodeID+height+utxoID -> reward payout bytes
  * |- utxos
  * | '-- utxoDB
  * |-. subnets
  * | '-. list
  * |   '-- txID -> nil
+ * |-. supplyHistory
+ * | '-- subnetID+height -> supply
+ * |-. utxoDiffs
+ * | '-- utxoID+height -> utxo bytes before the block at height or nil
+ * |-. utxoDiffHeights
+ * | '-- height+utxoID -> nil
+ * |-. timestampHistory
+ * | '-- height -> timestamp
  * |-. subnetOwners
  * | '-. subnetID -> owner
+ * |-. frozenValidators
+ * | '-- subnetID+nodeID -> frozen until
+ * |-. validatorChurns
+ * | '-- subnetID+periodStart -> validator churn bytes
  * |-. chains
  * | '-. subnetID
  * |   '-. list
@@ -284,7 +390,11 @@ type stateBlk struct {
  *   |-- prunedKey -> nil
  *   |-- timestampKey -> timestamp
  *   |-- currentSupplyKey -> currentSupply
+ *   |-- genesisSupplyKey -> supply once the genesis block was accepted
  *   |-- lastAcceptedKey -> lastAccepted
+ *   |-- historyStartHeightKey -> first height with recorded supply history
+ *   |-- stateHistoryStartHeightKey -> first height with retained UTXO and timestamp history
+ *   |-- rewardPayoutsStartHeightKey -> first height with indexed reward payouts
  *   '-- heightsIndexKey -> startIndexHeight + endIndexHeight
  */
 type state struct {
@@ -300,6 +410,9 @@ type state struct {
 
 	currentStakers *baseStakers
 	pendingStakers *baseStakers
+	// Subnet ID --> weight of the current validator set of the subnet,
+	// excluding frozen validators
+	subnetWeights map[ids.ID]*subnetWeight
 
 	currentHeight uint64
 
@@ -358,6 +471,22 @@ type state struct {
 	subnetOwnerCache cache.Cacher[ids.ID, fxOwnerAndSize] // cache of subnetID -> owner if the entry is nil, it is not in the database
 	subnetOwnerDB    database.Database
 
+	// Subnet ID --> Node ID --> time that the validator is frozen until in
+	// the persisted state. All the frozen validators are kept in memory.
+	frozenValidators map[ids.ID]map[ids.NodeID]time.Time
+	// Subnet ID --> Node ID --> time that the validator is frozen until. If
+	// the time is zero, the validator was unfrozen.
+	modifiedFrozenValidators map[ids.ID]map[ids.NodeID]time.Time
+	// The frozen validators, including the modified ones, ordered by the time
+	// that they are unfrozen.
+	currentFrozenValidators *frozenValidatorIndex
+	frozenValidatorDB       database.Database
+
+	// Subnet ID + start of the churn period --> churn of the subnet's
+	// validator set in the period
+	modifiedValidatorChurns map[validatorChurnKey]*ValidatorChurn
+	validatorChurnDB        database.Database
+
 	transformedSubnets     map[ids.ID]*txs.Tx            // map of subnetID -> transformSubnetTx
 	transformedSubnetCache cache.Cacher[ids.ID, *txs.Tx] // cache of subnetID -> transformSubnetTx if the entry is nil, it is not in the database
 	transformedSubnetDB    database.Database
@@ -366,6 +495,19 @@ type state struct {
 	supplyCache      cache.Cacher[ids.ID, *uint64] // cache of subnetID -> current supply if the entry is nil, it is not in the database
 	supplyDB         database.Database
 
+	// Supply of each subnet after each block that modified it.
+	supplyHistoryDB database.Database
+	// Value of each UTXO before each block that modified it, and the IDs of
+	// the UTXOs modified at each height.
+	utxoDiffsDB       database.Database
+	utxoDiffHeightsDB database.Database
+	// Timestamp after each block that modified it.
+	timestampHistoryDB database.Database
+	// Reward UTXOs indexed by the addresses they were paid to and by the node
+	// ID of the rewarded staker.
+	rewardPayoutsByAddressDB database.Database
+	rewardPayoutsByNodeIDDB  database.Database
+
 	addedChains  map[ids.ID][]*txs.Tx                    // maps subnetID -> the newly added chains to the subnet
 	chainCache   cache.Cacher[ids.ID, []*txs.Tx]         // cache of subnetID -> the chains after all local modifications []*txs.Tx
 	chainDBCache cache.Cacher[ids.ID, linkeddb.LinkedDB] // cache of subnetID -> linkedDB
@@ -374,10 +516,26 @@ type state struct {
 	// The persisted fields represent the current database value
 	timestamp, persistedTimestamp         time.Time
 	currentSupply, persistedCurrentSupply uint64
+	// [genesisSupply] is the supply of the primary network at genesis.
+	genesisSupply uint64
 	// [lastAccepted] is the most recently accepted block.
 	lastAccepted, persistedLastAccepted ids.ID
 	indexedHeights                      *heightRange
-	singletonDB                         database.Database
+	// [historyStartHeight] is the first height whose supplies can be read
+	// from the history. It is nil until the first block is written.
+	historyStartHeight *uint64
+	// If [stateHistoryEnabled], the UTXOs and the timestamp of the last
+	// [stateHistoryRetention] heights can be read from the history, or of
+	// every height if [stateHistoryRetention] is 0.
+	stateHistoryEnabled   bool
+	stateHistoryRetention uint64
+	// [stateHistoryStartHeight] is the first height whose UTXOs and timestamp
+	// can be read from the history. It is nil if the history isn't recorded.
+	stateHistoryStartHeight *uint64
+	// [rewardPayoutsStartHeight] is the first height whose reward payouts
+	// are indexed. It is nil until the first block is written.
+	rewardPayoutsStartHeight *uint64
+	singletonDB              database.Database
 }
 
 // heightRange is used to track which heights are safe to use the native DB
@@ -411,6 +569,13 @@ func (v *ValidatorWeightDiff) Add(negative bool, amount uint64) error {
 	return nil
 }
 
+// SupplyChange is the supply of a subnet after the block at [Height] modified
+// it.
+type SupplyChange struct {
+	Height uint64
+	Supply uint64
+}
+
 type heightWithSubnet struct {
 	Height   uint64 `serialize:"true"`
 	SubnetID ids.ID `serialize:"true"`
@@ -431,6 +596,37 @@ type fxOwnerAndSize struct {
 	size  int
 }
 
+// FrozenValidator is a current subnet validator that has no weight in the
+// validator set until [Until].
+type FrozenValidator struct {
+	SubnetID ids.ID
+	NodeID   ids.NodeID
+	Until    time.Time
+}
+
+// frozenValidatorKey = [subnetID] + [nodeID]
+const frozenValidatorKeyLength = ids.IDLen + ids.NodeIDLen
+
+func marshalFrozenValidatorKey(subnetID ids.ID, nodeID ids.NodeID) []byte {
+	key := make([]byte, frozenValidatorKeyLength)
+	copy(key, subnetID[:])
+	copy(key[ids.IDLen:], nodeID[:])
+	return key
+}
+
+func unmarshalFrozenValidatorKey(key []byte) (ids.ID, ids.NodeID, error) {
+	if len(key) != frozenValidatorKeyLength {
+		return ids.Empty, ids.EmptyNodeID, errUnexpectedFrozenValidatorKeyLength
+	}
+	var (
+		subnetID ids.ID
+		nodeID   ids.NodeID
+	)
+	copy(subnetID[:], key)
+	copy(nodeID[:], key[ids.IDLen:])
+	return subnetID, nodeID, nil
+}
+
 func txSize(_ ids.ID, tx *txs.Tx) int {
 	if tx == nil {
 		return ids.IDLen + constants.PointerOverhead
@@ -652,6 +848,7 @@ func newState(
 
 		currentStakers: newBaseStakers(),
 		pendingStakers: newBaseStakers(),
+		subnetWeights:  make(map[ids.ID]*subnetWeight),
 
 		validatorsDB:                    validatorsDB,
 		currentValidatorsDB:             currentValidatorsDB,
@@ -696,6 +893,14 @@ func newState(
 		subnetOwnerDB:    subnetOwnerDB,
 		subnetOwnerCache: subnetOwnerCache,
 
+		frozenValidators:         make(map[ids.ID]map[ids.NodeID]time.Time),
+		modifiedFrozenValidators: make(map[ids.ID]map[ids.NodeID]time.Time),
+		currentFrozenValidators:  newFrozenValidatorIndex(),
+		frozenValidatorDB:        prefixdb.New(frozenValidatorPrefix, baseDB),
+
+		modifiedValidatorChurns: make(map[validatorChurnKey]*ValidatorChurn),
+		validatorChurnDB:        prefixdb.New(validatorChurnPrefix, baseDB),
+
 		transformedSubnets:     make(map[ids.ID]*txs.Tx),
 		transformedSubnetCache: transformedSubnetCache,
 		transformedSubnetDB:    prefixdb.New(transformedSubnetPrefix, baseDB),
@@ -704,6 +909,17 @@ func newState(
 		supplyCache:      supplyCache,
 		supplyDB:         prefixdb.New(supplyPrefix, baseDB),
 
+		supplyHistoryDB:    prefixdb.New(supplyHistoryPrefix, baseDB),
+		utxoDiffsDB:        prefixdb.New(utxoDiffsPrefix, baseDB),
+		utxoDiffHeightsDB:  prefixdb.New(utxoDiffHeightsPrefix, baseDB),
+		timestampHistoryDB: prefixdb.New(timestampHistoryPrefix, baseDB),
+
+		stateHistoryEnabled:   execCfg.StateHistoryEnabled,
+		stateHistoryRetention: execCfg.StateHistoryRetention,
+
+		rewardPayoutsByAddressDB: prefixdb.New(rewardPayoutsByAddressPrefix, baseDB),
+		rewardPayoutsByNodeIDDB:  prefixdb.New(rewardPayoutsByNodeIDPrefix, baseDB),
+
 		addedChains:  make(map[ids.ID][]*txs.Tx),
 		chainDB:      prefixdb.New(chainPrefix, baseDB),
 		chainCache:   chainCache,
@@ -719,10 +935,16 @@ func (s *state) GetCurrentValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker
 
 func (s *state) PutCurrentValidator(staker *Staker) {
 	s.currentStakers.PutValidator(staker)
+	if !s.isFrozen(staker) {
+		s.addSubnetWeight(staker.SubnetID, staker.Weight)
+	}
 }
 
 func (s *state) DeleteCurrentValidator(staker *Staker) {
 	s.currentStakers.DeleteValidator(staker)
+	if !s.isFrozen(staker) {
+		s.removeSubnetWeight(staker.SubnetID, staker.Weight)
+	}
 }
 
 func (s *state) GetCurrentDelegatorIterator(subnetID ids.ID, nodeID ids.NodeID) (StakerIterator, error) {
@@ -731,10 +953,12 @@ func (s *state) GetCurrentDelegatorIterator(subnetID ids.ID, nodeID ids.NodeID)
 
 func (s *state) PutCurrentDelegator(staker *Staker) {
 	s.currentStakers.PutDelegator(staker)
+	s.addSubnetWeight(staker.SubnetID, staker.Weight)
 }
 
 func (s *state) DeleteCurrentDelegator(staker *Staker) {
 	s.currentStakers.DeleteDelegator(staker)
+	s.removeSubnetWeight(staker.SubnetID, staker.Weight)
 }
 
 func (s *state) GetCurrentStakerIterator() (StakerIterator, error) {
@@ -894,6 +1118,119 @@ func (s *state) SetSubnetOwner(subnetID ids.ID, owner fx.Owner) {
 	s.subnetOwners[subnetID] = owner
 }
 
+func (s *state) GetFrozenUntil(subnetID ids.ID, nodeID ids.NodeID) (time.Time, error) {
+	until, ok := s.modifiedFrozenValidators[subnetID][nodeID]
+	if !ok {
+		until, ok = s.frozenValidators[subnetID][nodeID]
+	}
+	if !ok || until.IsZero() {
+		return time.Time{}, database.ErrNotFound
+	}
+	return until, nil
+}
+
+func (s *state) SetFrozenUntil(subnetID ids.ID, nodeID ids.NodeID, until time.Time) {
+	// The weight of a current validator is removed from, or added back to,
+	// the weight of its subnet when it's frozen or unfrozen.
+	if validator, err := s.currentStakers.GetValidator(subnetID, nodeID); err == nil {
+		wasFrozen := s.isFrozen(validator)
+		isFrozen := !until.IsZero()
+		switch {
+		case !wasFrozen && isFrozen:
+			s.removeSubnetWeight(subnetID, validator.Weight)
+		case wasFrozen && !isFrozen:
+			s.addSubnetWeight(subnetID, validator.Weight)
+		}
+	}
+
+	nodes, ok := s.modifiedFrozenValidators[subnetID]
+	if !ok {
+		nodes = make(map[ids.NodeID]time.Time)
+		s.modifiedFrozenValidators[subnetID] = nodes
+	}
+	nodes[nodeID] = until
+
+	if until.IsZero() {
+		s.currentFrozenValidators.delete(subnetID, nodeID)
+	} else {
+		s.currentFrozenValidators.set(subnetID, nodeID, until)
+	}
+}
+
+func (s *state) GetFrozenValidatorIterator() (FrozenValidatorIterator, error) {
+	return s.currentFrozenValidators.iterator(), nil
+}
+
+// isFrozen returns true if [staker] is a frozen permissioned validator.
+func (s *state) isFrozen(staker *Staker) bool {
+	// GetFrozenUntil doesn't return errors other than database.ErrNotFound.
+	isFrozen, _ := isFrozenValidator(s, staker)
+	return isFrozen
+}
+
+func (s *state) GetSubnetWeight(subnetID ids.ID) (uint64, error) {
+	weight, ok := s.subnetWeights[subnetID]
+	if !ok {
+		return 0, nil
+	}
+	return weight.Uint64()
+}
+
+func (s *state) addSubnetWeight(subnetID ids.ID, weight uint64) {
+	w, ok := s.subnetWeights[subnetID]
+	if !ok {
+		w = &subnetWeight{}
+		s.subnetWeights[subnetID] = w
+	}
+	w.add(weight)
+}
+
+func (s *state) removeSubnetWeight(subnetID ids.ID, weight uint64) {
+	w, ok := s.subnetWeights[subnetID]
+	if !ok {
+		w = &subnetWeight{}
+		s.subnetWeights[subnetID] = w
+	}
+	w.remove(weight)
+	if w.isZero() {
+		delete(s.subnetWeights, subnetID)
+	}
+}
+
+func (s *state) GetValidatorChurn(subnetID ids.ID, periodStart time.Time) (*ValidatorChurn, error) {
+	key := validatorChurnKey{
+		subnetID:    subnetID,
+		periodStart: periodStart.Unix(),
+	}
+	if churn, ok := s.modifiedValidatorChurns[key]; ok {
+		return churn, nil
+	}
+
+	churnBytes, err := s.validatorChurnDB.Get(key.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	churn := &ValidatorChurn{}
+	if _, err := txs.GenesisCodec.Unmarshal(churnBytes, churn); err != nil {
+		return nil, err
+	}
+	return churn, nil
+}
+
+func (s *state) SetValidatorChurn(subnetID ids.ID, periodStart time.Time, churn *ValidatorChurn) {
+	s.modifiedValidatorChurns[validatorChurnKey{
+		subnetID:    subnetID,
+		periodStart: periodStart.Unix(),
+	}] = churn
+}
+
+// wasFrozen returns true if the current validator of [subnetID] with [nodeID]
+// is frozen in the persisted state.
+func (s *state) wasFrozen(subnetID ids.ID, nodeID ids.NodeID) bool {
+	_, ok := s.frozenValidators[subnetID][nodeID]
+	return ok
+}
+
 func (s *state) GetSubnetTransformation(subnetID ids.ID) (*txs.Tx, error) {
 	if tx, exists := s.transformedSubnets[subnetID]; exists {
 		return tx, nil
@@ -1139,8 +1476,154 @@ func (s *state) SetCurrentSupply(subnetID ids.ID, cs uint64) {
 	}
 }
 
+func (s *state) GetSupplyAt(subnetID ids.ID, height uint64) (uint64, error) {
+	// Heights are stored inverted, so the first entry is the latest change at
+	// or before [height].
+	it := s.supplyHistoryDB.NewIteratorWithStartAndPrefix(
+		marshalStartDiffKey(subnetID, height),
+		subnetID[:],
+	)
+	defer it.Release()
+
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return 0, err
+		}
+		// The supply of the primary network can only have changed after
+		// genesis. If the history was recorded from genesis, the supply
+		// didn't change before the first recorded change.
+		if subnetID == constants.PrimaryNetworkID &&
+			(height == 0 || (s.historyStartHeight != nil && *s.historyStartHeight == 0)) {
+			return s.genesisSupply, nil
+		}
+		if s.historyStartHeight == nil || height < *s.historyStartHeight {
+			return 0, fmt.Errorf("%w: %d", ErrHeightNotRetained, height)
+		}
+		return 0, database.ErrNotFound
+	}
+	return database.ParseUInt64(it.Value())
+}
+
+func (s *state) GetUTXOAt(utxoID ids.ID, height uint64) (*avax.UTXO, error) {
+	if err := s.checkHeightRetained(height); err != nil {
+		return nil, err
+	}
+
+	// Heights are stored inverted, so the diffs are iterated from the latest
+	// block to the earliest. The value of the UTXO at [height] is the value
+	// before the earliest block after [height] that modified it.
+	it := s.utxoDiffsDB.NewIteratorWithPrefix(utxoID[:])
+	defer it.Release()
+
+	var (
+		modified      bool
+		prevUTXOBytes []byte
+	)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != startDiffKeyLength {
+			return nil, errUnexpectedDiffKeyLength
+		}
+		if unpackIterableHeight(key[ids.IDLen:]) <= height {
+			break
+		}
+		modified = true
+		prevUTXOBytes = it.Value()
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	if !modified {
+		return s.utxoState.GetUTXO(utxoID)
+	}
+	if len(prevUTXOBytes) == 0 {
+		return nil, database.ErrNotFound
+	}
+
+	utxo := &avax.UTXO{}
+	if _, err := txs.GenesisCodec.Unmarshal(prevUTXOBytes, utxo); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal UTXO: %w", err)
+	}
+	return utxo, nil
+}
+
+func (s *state) GetTimestampAt(height uint64) (time.Time, error) {
+	if err := s.checkHeightRetained(height); err != nil {
+		return time.Time{}, err
+	}
+
+	// Heights are stored inverted, so the first entry is the latest change at
+	// or before [height].
+	it := s.timestampHistoryDB.NewIteratorWithStart(marshalHeightKey(height))
+	defer it.Release()
+
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return time.Time{}, err
+		}
+		return time.Time{}, database.ErrNotFound
+	}
+	return database.ParseTimestamp(it.Value())
+}
+
+// checkHeightRetained returns ErrHeightNotRetained if the UTXOs and the
+// timestamp at [height] can't be read from the history.
+func (s *state) checkHeightRetained(height uint64) error {
+	if s.stateHistoryStartHeight == nil || height < *s.stateHistoryStartHeight {
+		return fmt.Errorf("%w: %d", ErrHeightNotRetained, height)
+	}
+	return nil
+}
+
+func (s *state) GetSupplyChanges(subnetID ids.ID, startHeight, endHeight uint64) ([]SupplyChange, error) {
+	it := s.supplyHistoryDB.NewIteratorWithStartAndPrefix(
+		marshalStartDiffKey(subnetID, endHeight),
+		subnetID[:],
+	)
+	defer it.Release()
+
+	var changes []SupplyChange
+	for it.Next() {
+		key := it.Key()
+		if len(key) != startDiffKeyLength {
+			return nil, errUnexpectedDiffKeyLength
+		}
+		height := unpackIterableHeight(key[ids.IDLen:])
+		if height < startHeight {
+			break
+		}
+
+		supply, err := database.ParseUInt64(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, SupplyChange{
+			Height: height,
+			Supply: supply,
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	// The changes were read from the highest height to the lowest.
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	return changes, nil
+}
+
 func (s *state) ApplyCurrentValidators(subnetID ids.ID, vdrs validators.Manager) error {
 	for nodeID, validator := range s.currentStakers.validators[subnetID] {
+		// Frozen validators have no weight in the validator set.
+		//
+		// Invariant: Only permissioned subnet validators, which don't have
+		// delegators, can be frozen.
+		if s.wasFrozen(subnetID, nodeID) {
+			continue
+		}
+
 		staker := validator.validator
 		if err := vdrs.AddStaker(subnetID, nodeID, staker.PublicKey, staker.TxID, staker.Weight); err != nil {
 			return err
@@ -1379,6 +1862,10 @@ func (s *state) syncGenesis(genesisBlk block.Block, genesis *genesis.Genesis) er
 		s.AddTx(vdrTx, status.Committed)
 		s.SetCurrentSupply(constants.PrimaryNetworkID, newCurrentSupply)
 	}
+	s.genesisSupply = s.currentSupply
+	if err := database.PutUInt64(s.singletonDB, genesisSupplyKey, s.genesisSupply); err != nil {
+		return fmt.Errorf("failed to write genesis supply: %w", err)
+	}
 
 	for _, chain := range genesis.Chains {
 		unsignedChain, ok := chain.Unsigned.(*txs.CreateChainTx)
@@ -1408,6 +1895,8 @@ func (s *state) load() error {
 		s.loadMetadata(),
 		s.loadCurrentValidators(),
 		s.loadPendingValidators(),
+		s.loadFrozenValidators(),
+		s.initSubnetWeights(),
 		s.initValidatorSets(),
 	)
 }
@@ -1434,6 +1923,33 @@ func (s *state) loadMetadata() error {
 	s.persistedLastAccepted = lastAccepted
 	s.lastAccepted = lastAccepted
 
+	historyStartHeight, err := database.GetUInt64(s.singletonDB, historyStartHeightKey)
+	switch err {
+	case nil:
+		s.historyStartHeight = &historyStartHeight
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
+	stateHistoryStartHeight, err := database.GetUInt64(s.singletonDB, stateHistoryStartHeightKey)
+	switch err {
+	case nil:
+		s.stateHistoryStartHeight = &stateHistoryStartHeight
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
+	rewardPayoutsStartHeight, err := database.GetUInt64(s.singletonDB, rewardPayoutsStartHeightKey)
+	switch err {
+	case nil:
+		s.rewardPayoutsStartHeight = &rewardPayoutsStartHeight
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
 	// Lookup the most recently indexed range on disk. If we haven't started
 	// indexing the weights, then we keep the indexed heights as nil.
 	indexedHeightsBytes, err := s.singletonDB.Get(heightsIndexedKey)
@@ -1686,8 +2202,50 @@ func (s *state) loadPendingValidators() error {
 	)
 }
 
-// Invariant: initValidatorSets requires loadCurrentValidators to have already
-// been called.
+func (s *state) loadFrozenValidators() error {
+	it := s.frozenValidatorDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		subnetID, nodeID, err := unmarshalFrozenValidatorKey(it.Key())
+		if err != nil {
+			return err
+		}
+		until, err := database.ParseUInt64(it.Value())
+		if err != nil {
+			return err
+		}
+
+		nodes, ok := s.frozenValidators[subnetID]
+		if !ok {
+			nodes = make(map[ids.NodeID]time.Time)
+			s.frozenValidators[subnetID] = nodes
+		}
+		nodes[nodeID] = time.Unix(int64(until), 0)
+		s.currentFrozenValidators.set(subnetID, nodeID, nodes[nodeID])
+	}
+	return it.Error()
+}
+
+// Invariant: initSubnetWeights requires loadCurrentValidators and
+// loadFrozenValidators to have already been called.
+func (s *state) initSubnetWeights() error {
+	s.subnetWeights = make(map[ids.ID]*subnetWeight)
+
+	currentStakerIterator := s.currentStakers.GetStakerIterator()
+	defer currentStakerIterator.Release()
+
+	for currentStakerIterator.Next() {
+		staker := currentStakerIterator.Value()
+		if !s.isFrozen(staker) {
+			s.addSubnetWeight(staker.SubnetID, staker.Weight)
+		}
+	}
+	return nil
+}
+
+// Invariant: initValidatorSets requires loadCurrentValidators and
+// loadFrozenValidators to have already been called.
 func (s *state) initValidatorSets() error {
 	if s.cfg.Validators.Count(constants.PrimaryNetworkID) != 0 {
 		// Enforce the invariant that the validator set is empty here.
@@ -1728,10 +2286,14 @@ func (s *state) write(updateValidators bool, height uint64) error {
 	return utils.Err(
 		s.writeBlocks(),
 		s.writeCurrentStakers(updateValidators, height),
+		s.writeFrozenValidators(), // Must be called after writeCurrentStakers
+		s.writeValidatorChurns(),
 		s.writePendingStakers(),
 		s.WriteValidatorMetadata(s.currentValidatorList, s.currentSubnetValidatorList), // Must be called after writeCurrentStakers
 		s.writeTXs(),
+		s.writeRewardPayouts(height), // Must be called before writeRewardUTXOs
 		s.writeRewardUTXOs(),
+		s.writeHistory(height), // Must be called before writeUTXOs, writeSubnetSupplies and writeMetadata
 		s.writeUTXOs(),
 		s.writeSubnets(),
 		s.writeSubnetOwners(),
@@ -1759,8 +2321,16 @@ func (s *state) Close() error {
 		s.rewardUTXODB.Close(),
 		s.utxoDB.Close(),
 		s.subnetBaseDB.Close(),
+		s.frozenValidatorDB.Close(),
+		s.validatorChurnDB.Close(),
 		s.transformedSubnetDB.Close(),
 		s.supplyDB.Close(),
+		s.supplyHistoryDB.Close(),
+		s.utxoDiffsDB.Close(),
+		s.utxoDiffHeightsDB.Close(),
+		s.timestampHistoryDB.Close(),
+		s.rewardPayoutsByAddressDB.Close(),
+		s.rewardPayoutsByNodeIDDB.Close(),
 		s.chainDB.Close(),
 		s.singletonDB.Close(),
 		s.blockDB.Close(),
@@ -1786,6 +2356,11 @@ func (s *state) sync(genesis []byte) error {
 				err,
 			)
 		}
+	} else if err := s.loadGenesisSupply(genesis); err != nil {
+		return fmt.Errorf(
+			"failed to load the genesis supply: %w",
+			err,
+		)
 	}
 
 	if err := s.load(); err != nil {
@@ -1797,6 +2372,48 @@ func (s *state) sync(genesis []byte) error {
 	return nil
 }
 
+// loadGenesisSupply sets the supply of the primary network once the genesis
+// block was accepted.
+//
+// Databases initialized before the genesis supply was persisted compute it
+// from [genesisBytes]. As in [syncGenesis], it includes the potential rewards
+// of the genesis validators.
+func (s *state) loadGenesisSupply(genesisBytes []byte) error {
+	genesisSupply, err := database.GetUInt64(s.singletonDB, genesisSupplyKey)
+	if err == nil {
+		s.genesisSupply = genesisSupply
+		return nil
+	}
+	if err != database.ErrNotFound {
+		return err
+	}
+
+	genesis, err := genesis.Parse(genesisBytes)
+	if err != nil {
+		return err
+	}
+
+	supply := genesis.InitialSupply
+	for _, vdrTx := range genesis.Validators {
+		tx, ok := vdrTx.Unsigned.(*txs.AddValidatorTx)
+		if !ok {
+			return fmt.Errorf("expected tx type *txs.AddValidatorTx but got %T", vdrTx.Unsigned)
+		}
+
+		potentialReward := s.rewards.Calculate(
+			tx.Validator.Duration(),
+			tx.Validator.Wght,
+			supply,
+		)
+		supply, err = safemath.Add64(supply, potentialReward)
+		if err != nil {
+			return err
+		}
+	}
+	s.genesisSupply = supply
+	return database.PutUInt64(s.singletonDB, genesisSupplyKey, supply)
+}
+
 func (s *state) init(genesisBytes []byte) error {
 	// Create the genesis block and save it as being accepted (We don't do
 	// genesisBlock.Accept() because then it'd look for genesisBlock's
@@ -1960,6 +2577,10 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64) error
 	rawNestedPublicKeyDiffDB := prefixdb.New(heightBytes, s.nestedValidatorPublicKeyDiffsDB)
 	nestedPKDiffDB := linkeddb.NewDefault(rawNestedPublicKeyDiffDB)
 
+	// Subnet ID --> Node IDs of the validators that were added or removed. The
+	// weight changes caused by freezing or unfreezing these validators are
+	// recorded along with their addition or removal.
+	addedOrRemoved := make(map[ids.ID]set.Set[ids.NodeID])
 	for subnetID, validatorDiffs := range s.currentStakers.validatorDiffs {
 		delete(s.currentStakers.validatorDiffs, subnetID)
 
@@ -1982,6 +2603,9 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64) error
 		rawNestedWeightDiffDB := prefixdb.New(prefixBytes, s.nestedValidatorWeightDiffsDB)
 		nestedWeightDiffDB := linkeddb.NewDefault(rawNestedWeightDiffDB)
 
+		addedOrRemovedNodeIDs := set.Set[ids.NodeID]{}
+		addedOrRemoved[subnetID] = addedOrRemovedNodeIDs
+
 		// Record the change in weight and/or public key for each validator.
 		for nodeID, validatorDiff := range validatorDiffs {
 			// Copy [nodeID] so it doesn't get overwritten next iteration.
@@ -2082,25 +2706,31 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64) error
 				return err
 			}
 
+			// Frozen validators have no weight in the validator set.
+			//
+			// Invariant: Only permissioned subnet validators, which don't
+			// have delegators, can be frozen.
+			switch validatorDiff.validatorStatus {
+			case added:
+				if _, err := s.GetFrozenUntil(subnetID, nodeID); err == nil {
+					weightDiff.Amount = 0
+				}
+				addedOrRemovedNodeIDs.Add(nodeID)
+			case deleted:
+				if s.wasFrozen(subnetID, nodeID) {
+					weightDiff.Amount = 0
+				}
+				// Removed validators are no longer frozen.
+				s.SetFrozenUntil(subnetID, nodeID, time.Time{})
+				addedOrRemovedNodeIDs.Add(nodeID)
+			}
+
 			if weightDiff.Amount == 0 {
 				// No weight change to record; go to next validator.
 				continue
 			}
 
-			err = s.flatValidatorWeightDiffsDB.Put(
-				marshalDiffKey(subnetID, height, nodeID),
-				marshalWeightDiff(weightDiff),
-			)
-			if err != nil {
-				return err
-			}
-
-			// TODO: Remove this once we no longer support version rollbacks.
-			weightDiffBytes, err := block.GenesisCodec.Marshal(block.Version, weightDiff)
-			if err != nil {
-				return fmt.Errorf("failed to serialize validator weight diff: %w", err)
-			}
-			if err := nestedWeightDiffDB.Put(nodeID[:], weightDiffBytes); err != nil {
+			if err := s.writeWeightDiff(subnetID, height, nodeID, weightDiff, nestedWeightDiffDB); err != nil {
 				return err
 			}
 
@@ -2136,6 +2766,10 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64) error
 		}
 	}
 
+	if err := s.writeFrozenValidatorWeights(updateValidators, height, addedOrRemoved); err != nil {
+		return err
+	}
+
 	// TODO: Move validator set management out of the state package
 	//
 	// Attempt to update the stake metrics
@@ -2153,6 +2787,108 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64) error
 	return nil
 }
 
+// writeFrozenValidatorWeights records the weight changes of the current
+// validators that were frozen or unfrozen, other than the ones in
+// [addedOrRemoved].
+func (s *state) writeFrozenValidatorWeights(
+	updateValidators bool,
+	height uint64,
+	addedOrRemoved map[ids.ID]set.Set[ids.NodeID],
+) error {
+	for subnetID, nodes := range s.modifiedFrozenValidators {
+		var (
+			addedOrRemovedNodeIDs = addedOrRemoved[subnetID]
+			nestedWeightDiffDB    linkeddb.LinkedDB
+		)
+		for nodeID, until := range nodes {
+			if addedOrRemovedNodeIDs.Contains(nodeID) {
+				continue
+			}
+
+			isFrozen := !until.IsZero()
+			if isFrozen == s.wasFrozen(subnetID, nodeID) {
+				continue
+			}
+
+			staker, err := s.currentStakers.GetValidator(subnetID, nodeID)
+			if err != nil {
+				return fmt.Errorf("failed to get frozen validator: %w", err)
+			}
+
+			if nestedWeightDiffDB == nil {
+				prefixBytes, err := block.GenesisCodec.Marshal(block.Version, heightWithSubnet{
+					Height:   height,
+					SubnetID: subnetID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create prefix bytes: %w", err)
+				}
+				rawNestedWeightDiffDB := prefixdb.New(prefixBytes, s.nestedValidatorWeightDiffsDB)
+				nestedWeightDiffDB = linkeddb.NewDefault(rawNestedWeightDiffDB)
+			}
+
+			weightDiff := &ValidatorWeightDiff{
+				Decrease: isFrozen,
+				Amount:   staker.Weight,
+			}
+			if err := s.writeWeightDiff(subnetID, height, nodeID, weightDiff, nestedWeightDiffDB); err != nil {
+				return err
+			}
+
+			// TODO: Move the validator set management out of the state package
+			if !updateValidators {
+				continue
+			}
+
+			// We only track the current validator set of tracked subnets.
+			if !s.cfg.TrackedSubnets.Contains(subnetID) {
+				continue
+			}
+
+			if isFrozen {
+				err = s.cfg.Validators.RemoveWeight(subnetID, nodeID, staker.Weight)
+			} else {
+				err = s.cfg.Validators.AddStaker(
+					subnetID,
+					nodeID,
+					staker.PublicKey,
+					staker.TxID,
+					staker.Weight,
+				)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update validator weight: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// writeWeightDiff records that the weight of [nodeID] in [subnetID] changed by
+// [weightDiff] in the block at [height].
+func (s *state) writeWeightDiff(
+	subnetID ids.ID,
+	height uint64,
+	nodeID ids.NodeID,
+	weightDiff *ValidatorWeightDiff,
+	nestedWeightDiffDB linkeddb.LinkedDB,
+) error {
+	err := s.flatValidatorWeightDiffsDB.Put(
+		marshalDiffKey(subnetID, height, nodeID),
+		marshalWeightDiff(weightDiff),
+	)
+	if err != nil {
+		return err
+	}
+
+	// TODO: Remove this once we no longer support version rollbacks.
+	weightDiffBytes, err := block.GenesisCodec.Marshal(block.Version, weightDiff)
+	if err != nil {
+		return fmt.Errorf("failed to serialize validator weight diff: %w", err)
+	}
+	return nestedWeightDiffDB.Put(nodeID[:], weightDiffBytes)
+}
+
 func writeCurrentDelegatorDiff(
 	currentDelegatorList linkeddb.LinkedDB,
 	weightDiff *ValidatorWeightDiff,
@@ -2350,6 +3086,37 @@ func (s *state) writeSubnetOwners() error {
 	return nil
 }
 
+func (s *state) writeFrozenValidators() error {
+	for subnetID, nodes := range s.modifiedFrozenValidators {
+		delete(s.modifiedFrozenValidators, subnetID)
+
+		for nodeID, until := range nodes {
+			key := marshalFrozenValidatorKey(subnetID, nodeID)
+			if until.IsZero() {
+				delete(s.frozenValidators[subnetID], nodeID)
+				if len(s.frozenValidators[subnetID]) == 0 {
+					delete(s.frozenValidators, subnetID)
+				}
+				if err := s.frozenValidatorDB.Delete(key); err != nil {
+					return fmt.Errorf("failed to delete frozen validator: %w", err)
+				}
+				continue
+			}
+
+			frozenNodes, ok := s.frozenValidators[subnetID]
+			if !ok {
+				frozenNodes = make(map[ids.NodeID]time.Time)
+				s.frozenValidators[subnetID] = frozenNodes
+			}
+			frozenNodes[nodeID] = until
+			if err := database.PutUInt64(s.frozenValidatorDB, key, uint64(until.Unix())); err != nil {
+				return fmt.Errorf("failed to write frozen validator: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *state) writeTransformedSubnets() error {
 	for subnetID, tx := range s.transformedSubnets {
 		txID := tx.ID()
@@ -2366,6 +3133,208 @@ func (s *state) writeTransformedSubnets() error {
 	return nil
 }
 
+func (s *state) writeHistory(height uint64) error {
+	if s.historyStartHeight == nil {
+		if err := s.writeSupplySnapshot(height); err != nil {
+			return err
+		}
+	}
+	if err := s.writeSupplyHistory(height); err != nil {
+		return err
+	}
+
+	if !s.stateHistoryEnabled {
+		// If the history is enabled again, it must start after the heights
+		// that weren't recorded.
+		if s.stateHistoryStartHeight == nil {
+			return nil
+		}
+		if err := s.singletonDB.Delete(stateHistoryStartHeightKey); err != nil {
+			return fmt.Errorf("failed to delete state history start height: %w", err)
+		}
+		s.stateHistoryStartHeight = nil
+		return nil
+	}
+
+	if s.stateHistoryStartHeight == nil {
+		if err := s.writeStateHistorySnapshot(height); err != nil {
+			return err
+		}
+	}
+	return utils.Err(
+		s.writeUTXODiffs(height),
+		s.writeTimestampHistory(height),
+		s.pruneStateHistory(height), // Must be called after writeTimestampHistory
+	)
+}
+
+// writeSupplySnapshot records the supplies that were persisted before the
+// history was recorded, so that they can be read at [height] even if [height]
+// doesn't modify them.
+func (s *state) writeSupplySnapshot(height uint64) error {
+	if err := database.PutUInt64(s.singletonDB, historyStartHeightKey, height); err != nil {
+		return fmt.Errorf("failed to write history start height: %w", err)
+	}
+	s.historyStartHeight = &height
+
+	key := marshalStartDiffKey(constants.PrimaryNetworkID, height)
+	if err := database.PutUInt64(s.supplyHistoryDB, key, s.persistedCurrentSupply); err != nil {
+		return fmt.Errorf("failed to write supply history: %w", err)
+	}
+
+	it := s.supplyDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		subnetID, err := ids.ToID(it.Key())
+		if err != nil {
+			return err
+		}
+		key := marshalStartDiffKey(subnetID, height)
+		if err := s.supplyHistoryDB.Put(key, it.Value()); err != nil {
+			return fmt.Errorf("failed to write supply history: %w", err)
+		}
+	}
+	return it.Error()
+}
+
+// writeStateHistorySnapshot records the timestamp that was persisted before
+// the state history was recorded, so that it can be read at [height] even if
+// [height] doesn't modify it.
+func (s *state) writeStateHistorySnapshot(height uint64) error {
+	if err := database.PutUInt64(s.singletonDB, stateHistoryStartHeightKey, height); err != nil {
+		return fmt.Errorf("failed to write state history start height: %w", err)
+	}
+	s.stateHistoryStartHeight = &height
+
+	if err := database.PutTimestamp(s.timestampHistoryDB, marshalHeightKey(height), s.persistedTimestamp); err != nil {
+		return fmt.Errorf("failed to write timestamp history: %w", err)
+	}
+	return nil
+}
+
+// pruneStateHistory deletes the history of the heights that are no longer
+// retained once the block at [height] is accepted.
+//
+// At most [maxPrunedHistoryHeights] heights are pruned per block, so the
+// history of a shrunk retention window is deleted over multiple blocks.
+func (s *state) pruneStateHistory(height uint64) error {
+	if s.stateHistoryRetention == 0 || height < s.stateHistoryRetention {
+		return nil
+	}
+	startHeight := *s.stateHistoryStartHeight
+	retainedStartHeight := height - s.stateHistoryRetention + 1
+	if startHeight >= retainedStartHeight {
+		return nil
+	}
+	newStartHeight := retainedStartHeight
+	if newStartHeight-startHeight > maxPrunedHistoryHeights {
+		newStartHeight = startHeight + maxPrunedHistoryHeights
+	}
+
+	// The timestamp at [newStartHeight] may have been recorded at a pruned
+	// height.
+	timestamp, err := s.GetTimestampAt(newStartHeight)
+	if err != nil {
+		return err
+	}
+	if err := database.PutTimestamp(s.timestampHistoryDB, marshalHeightKey(newStartHeight), timestamp); err != nil {
+		return fmt.Errorf("failed to write timestamp history: %w", err)
+	}
+
+	for prunedHeight := startHeight; prunedHeight < newStartHeight; prunedHeight++ {
+		if err := s.timestampHistoryDB.Delete(marshalHeightKey(prunedHeight)); err != nil {
+			return fmt.Errorf("failed to delete timestamp history: %w", err)
+		}
+		if err := s.pruneUTXODiffs(prunedHeight); err != nil {
+			return err
+		}
+	}
+
+	if err := database.PutUInt64(s.singletonDB, stateHistoryStartHeightKey, newStartHeight); err != nil {
+		return fmt.Errorf("failed to write state history start height: %w", err)
+	}
+	s.stateHistoryStartHeight = &newStartHeight
+	return nil
+}
+
+// pruneUTXODiffs deletes the values of the UTXOs before the block at [height]
+// modified them.
+func (s *state) pruneUTXODiffs(height uint64) error {
+	it := s.utxoDiffHeightsDB.NewIteratorWithPrefix(marshalHeightKey(height))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != database.Uint64Size+ids.IDLen {
+			return errUnexpectedDiffKeyLength
+		}
+		utxoID, err := ids.ToID(key[database.Uint64Size:])
+		if err != nil {
+			return err
+		}
+		if err := s.utxoDiffsDB.Delete(marshalStartDiffKey(utxoID, height)); err != nil {
+			return fmt.Errorf("failed to delete UTXO diff: %w", err)
+		}
+		if err := s.utxoDiffHeightsDB.Delete(key); err != nil {
+			return fmt.Errorf("failed to delete UTXO diff height: %w", err)
+		}
+	}
+	return it.Error()
+}
+
+func (s *state) writeUTXODiffs(height uint64) error {
+	for utxoID := range s.modifiedUTXOs {
+		var prevUTXOBytes []byte
+		prevUTXO, err := s.utxoState.GetUTXO(utxoID)
+		switch err {
+		case nil:
+			prevUTXOBytes, err = txs.GenesisCodec.Marshal(txs.Version, prevUTXO)
+			if err != nil {
+				return fmt.Errorf("failed to marshal UTXO: %w", err)
+			}
+		case database.ErrNotFound:
+		default:
+			return fmt.Errorf("failed to get UTXO: %w", err)
+		}
+
+		key := marshalStartDiffKey(utxoID, height)
+		if err := s.utxoDiffsDB.Put(key, prevUTXOBytes); err != nil {
+			return fmt.Errorf("failed to write UTXO diff: %w", err)
+		}
+		heightKey := append(marshalHeightKey(height), utxoID[:]...)
+		if err := s.utxoDiffHeightsDB.Put(heightKey, nil); err != nil {
+			return fmt.Errorf("failed to write UTXO diff height: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *state) writeTimestampHistory(height uint64) error {
+	if s.persistedTimestamp.Equal(s.timestamp) {
+		return nil
+	}
+	if err := database.PutTimestamp(s.timestampHistoryDB, marshalHeightKey(height), s.timestamp); err != nil {
+		return fmt.Errorf("failed to write timestamp history: %w", err)
+	}
+	return nil
+}
+
+func (s *state) writeSupplyHistory(height uint64) error {
+	if s.persistedCurrentSupply != s.currentSupply {
+		key := marshalStartDiffKey(constants.PrimaryNetworkID, height)
+		if err := database.PutUInt64(s.supplyHistoryDB, key, s.currentSupply); err != nil {
+			return fmt.Errorf("failed to write supply history: %w", err)
+		}
+	}
+	for subnetID, supply := range s.modifiedSupplies {
+		key := marshalStartDiffKey(subnetID, height)
+		if err := database.PutUInt64(s.supplyHistoryDB, key, supply); err != nil {
+			return fmt.Errorf("failed to write supply history: %w", err)
+		}
+	}
+	return nil
+}
+
 func (s *state) writeSubnetSupplies() error {
 	for subnetID, supply := range s.modifiedSupplies {
 		supply := supply
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	_ ChainAtHeight = (*stateAtHeight)(nil)
+
+	ErrHeightNotAccepted = errors.New("height not accepted")
+)
+
+// ChainAtHeight is the chain state once the block at a height was accepted.
+//
+// Only the state that is recorded by height can be read: the timestamp, the
+// supplies and the UTXOs. Stakers, subnets, chains, reward UTXOs and txs
+// aren't recorded by height.
+type ChainAtHeight interface {
+	GetTimestamp() time.Time
+	GetCurrentSupply(subnetID ids.ID) (uint64, error)
+	GetUTXO(utxoID ids.ID) (*avax.UTXO, error)
+}
+
+type stateAtHeight struct {
+	state     State
+	height    uint64
+	timestamp time.Time
+}
+
+// NewStateAtHeight returns the state of [s] once the block at [height] was
+// accepted. Returns ErrHeightNotRetained if the state history of [height]
+// isn't retained.
+func NewStateAtHeight(s State, height uint64) (ChainAtHeight, error) {
+	lastAccepted, err := s.GetStatelessBlock(s.GetLastAccepted())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last accepted block: %w", err)
+	}
+	if lastAcceptedHeight := lastAccepted.Height(); height > lastAcceptedHeight {
+		return nil, fmt.Errorf("%w: %d > %d", ErrHeightNotAccepted, height, lastAcceptedHeight)
+	}
+
+	timestamp, err := s.GetTimestampAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return &stateAtHeight{
+		state:     s,
+		height:    height,
+		timestamp: timestamp,
+	}, nil
+}
+
+func (s *stateAtHeight) GetTimestamp() time.Time {
+	return s.timestamp
+}
+
+func (s *stateAtHeight) GetCurrentSupply(subnetID ids.ID) (uint64, error) {
+	return s.state.GetSupplyAt(subnetID, s.height)
+}
+
+func (s *stateAtHeight) GetUTXO(utxoID ids.ID) (*avax.UTXO, error) {
+	return s.state.GetUTXOAt(utxoID, s.height)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
@@ -33,6 +34,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/genesis"
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
@@ -90,8 +92,23 @@ func TestStateSyncGenesis(t *testing.T) {
 }
 
 func newInitializedState(require *require.Assertions) (State, database.Database) {
-	s, db := newUninitializedState(require)
+	execCfg, _ := config.GetExecutionConfig(nil)
+	return newInitializedStateWithConfig(require, execCfg)
+}
+
+func newInitializedStateWithConfig(require *require.Assertions, execCfg *config.ExecutionConfig) (State, database.Database) {
+	db := memdb.New()
+	s := newStateFromDBWithConfig(require, db, execCfg)
+
+	genesisBlkID := ids.GenerateTestID()
+	genesisBlk, err := block.NewApricotCommitBlock(genesisBlkID, 0)
+	require.NoError(err)
+	require.NoError(s.(*state).syncGenesis(genesisBlk, newTestGenesis(require)))
 
+	return s, db
+}
+
+func newTestGenesis(require *require.Assertions) *genesis.Genesis {
 	initialValidator := &txs.AddValidatorTx{
 		Validator: txs.Validator{
 			NodeID: initialNodeID,
@@ -122,8 +139,7 @@ func newInitializedState(require *require.Assertions) (State, database.Database)
 	initialChainTx := &txs.Tx{Unsigned: initialChain}
 	require.NoError(initialChainTx.Initialize(txs.Codec))
 
-	genesisBlkID := ids.GenerateTestID()
-	genesisState := &genesis.Genesis{
+	return &genesis.Genesis{
 		UTXOs: []*genesis.UTXO{
 			{
 				UTXO: avax.UTXO{
@@ -148,12 +164,6 @@ func newInitializedState(require *require.Assertions) (State, database.Database)
 		Timestamp:     uint64(initialTime.Unix()),
 		InitialSupply: units.Schmeckle + units.Avax,
 	}
-
-	genesisBlk, err := block.NewApricotCommitBlock(genesisBlkID, 0)
-	require.NoError(err)
-	require.NoError(s.(*state).syncGenesis(genesisBlk, genesisState))
-
-	return s, db
 }
 
 func newUninitializedState(require *require.Assertions) (State, database.Database) {
@@ -163,6 +173,10 @@ func newUninitializedState(require *require.Assertions) (State, database.Databas
 
 func newStateFromDB(require *require.Assertions, db database.Database) State {
 	execCfg, _ := config.GetExecutionConfig(nil)
+	return newStateFromDBWithConfig(require, db, execCfg)
+}
+
+func newStateFromDBWithConfig(require *require.Assertions, db database.Database, execCfg *config.ExecutionConfig) State {
 	state, err := newState(
 		db,
 		metrics.Noop,
@@ -696,3 +710,634 @@ func TestStateSubnetOwner(t *testing.T) {
 	require.NoError(err)
 	require.Equal(owner2, owner)
 }
+
+func TestStateFrozenValidators(t *testing.T) {
+	require := require.New(t)
+
+	s, db := newInitializedState(require)
+
+	var (
+		subnetID    = ids.GenerateTestID()
+		frozenUntil = initialTime.Add(time.Hour)
+		stakers     = make([]*Staker, 3)
+	)
+	for i := range stakers {
+		stakers[i] = &Staker{
+			TxID:      ids.GenerateTestID(),
+			NodeID:    ids.GenerateTestNodeID(),
+			SubnetID:  subnetID,
+			Weight:    uint64(i + 1),
+			StartTime: initialTime,
+			EndTime:   initialValidatorEndTime,
+			NextTime:  initialValidatorEndTime,
+			Priority:  txs.SubnetPermissionedValidatorCurrentPriority,
+		}
+	}
+	validatorOutput := func(stakers ...*Staker) map[ids.NodeID]*validators.GetValidatorOutput {
+		vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(stakers))
+		for _, staker := range stakers {
+			vdrs[staker.NodeID] = &validators.GetValidatorOutput{
+				NodeID: staker.NodeID,
+				Weight: staker.Weight,
+			}
+		}
+		return vdrs
+	}
+
+	vdrs := s.(*state).cfg.Validators
+	s.(*state).cfg.TrackedSubnets.Add(subnetID)
+
+	blocks := []struct {
+		name        string
+		apply       func()
+		expectedSet map[ids.NodeID]*validators.GetValidatorOutput
+	}{
+		{
+			name: "add validators",
+			apply: func() {
+				s.PutCurrentValidator(stakers[0])
+				s.PutCurrentValidator(stakers[1])
+			},
+			expectedSet: validatorOutput(stakers[0], stakers[1]),
+		},
+		{
+			name: "freeze validator",
+			apply: func() {
+				s.SetFrozenUntil(subnetID, stakers[0].NodeID, frozenUntil)
+			},
+			expectedSet: validatorOutput(stakers[1]),
+		},
+		{
+			name: "add frozen validator",
+			apply: func() {
+				s.PutCurrentValidator(stakers[2])
+				s.SetFrozenUntil(subnetID, stakers[2].NodeID, frozenUntil)
+			},
+			expectedSet: validatorOutput(stakers[1]),
+		},
+		{
+			name: "unfreeze validator",
+			apply: func() {
+				s.SetFrozenUntil(subnetID, stakers[0].NodeID, time.Time{})
+			},
+			expectedSet: validatorOutput(stakers[0], stakers[1]),
+		},
+		{
+			name: "remove frozen validator",
+			apply: func() {
+				s.DeleteCurrentValidator(stakers[2])
+			},
+			expectedSet: validatorOutput(stakers[0], stakers[1]),
+		},
+		{
+			name: "freeze validator again",
+			apply: func() {
+				s.SetFrozenUntil(subnetID, stakers[1].NodeID, frozenUntil)
+			},
+			expectedSet: validatorOutput(stakers[0]),
+		},
+	}
+	for i, blk := range blocks {
+		blk.apply()
+
+		height := uint64(i + 1)
+		s.SetHeight(height)
+		require.NoError(s.Commit(), blk.name)
+
+		requireEqualWeightsValidatorSet(require, blk.expectedSet, vdrs.GetMap(subnetID))
+
+		// The validator sets at the previous heights can be recalculated from
+		// the weight diffs.
+		for j := 0; j < i; j++ {
+			vdrSet := copyValidatorSet(blk.expectedSet)
+			require.NoError(s.ApplyValidatorWeightDiffs(
+				context.Background(),
+				vdrSet,
+				height,
+				uint64(j+2),
+				subnetID,
+			))
+			requireEqualWeightsValidatorSet(require, blocks[j].expectedSet, vdrSet)
+		}
+	}
+
+	// Removed validators are no longer frozen.
+	_, err := s.GetFrozenUntil(subnetID, stakers[2].NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// Frozen validators aren't added to untracked validator sets.
+	subnetVdrs := validators.NewManager()
+	require.NoError(s.ApplyCurrentValidators(subnetID, subnetVdrs))
+	requireEqualWeightsValidatorSet(require, validatorOutput(stakers[0]), subnetVdrs.GetMap(subnetID))
+
+	// Frozen validators are persisted.
+	s = newStateFromDB(require, db)
+	require.NoError(s.(*state).loadFrozenValidators())
+
+	until, err := s.GetFrozenUntil(subnetID, stakers[1].NodeID)
+	require.NoError(err)
+	require.Equal(frozenUntil.Unix(), until.Unix())
+
+	frozenValidatorIterator, err := s.GetFrozenValidatorIterator()
+	require.NoError(err)
+	defer frozenValidatorIterator.Release()
+
+	require.True(frozenValidatorIterator.Next())
+	require.Equal(subnetID, frozenValidatorIterator.Value().SubnetID)
+	require.Equal(stakers[1].NodeID, frozenValidatorIterator.Value().NodeID)
+	require.False(frozenValidatorIterator.Next())
+}
+
+func TestStateValidatorChurn(t *testing.T) {
+	require := require.New(t)
+
+	s, db := newInitializedState(require)
+
+	var (
+		subnetID    = ids.GenerateTestID()
+		periodStart = initialTime.Truncate(time.Hour)
+		churn       = &ValidatorChurn{
+			SubnetWeight: 100,
+			Churn:        10,
+		}
+	)
+
+	_, err := s.GetValidatorChurn(subnetID, periodStart)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	s.SetValidatorChurn(subnetID, periodStart, churn)
+	gotChurn, err := s.GetValidatorChurn(subnetID, periodStart)
+	require.NoError(err)
+	require.Equal(churn, gotChurn)
+
+	s.SetHeight(1)
+	require.NoError(s.Commit())
+
+	// The churn is read from disk after the state is reloaded.
+	s = newStateFromDB(require, db)
+	gotChurn, err = s.GetValidatorChurn(subnetID, periodStart)
+	require.NoError(err)
+	require.Equal(churn, gotChurn)
+
+	_, err = s.GetValidatorChurn(subnetID, periodStart.Add(time.Hour))
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestStateSupplyHistory(t *testing.T) {
+	require := require.New(t)
+
+	state, _ := newInitializedState(require)
+
+	// The genesis supply is recorded at height 0.
+	genesisSupply, err := state.GetCurrentSupply(constants.PrimaryNetworkID)
+	require.NoError(err)
+	supply, err := state.GetSupplyAt(constants.PrimaryNetworkID, 0)
+	require.NoError(err)
+	require.Equal(genesisSupply, supply)
+
+	subnetID := ids.GenerateTestID()
+	_, err = state.GetSupplyAt(subnetID, 0)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	state.SetHeight(1)
+	state.SetCurrentSupply(constants.PrimaryNetworkID, genesisSupply+1)
+	state.SetCurrentSupply(subnetID, 10)
+	require.NoError(state.Commit())
+
+	state.SetHeight(3)
+	state.SetCurrentSupply(subnetID, 20)
+	require.NoError(state.Commit())
+
+	supply, err = state.GetSupplyAt(constants.PrimaryNetworkID, 5)
+	require.NoError(err)
+	require.Equal(genesisSupply+1, supply)
+
+	_, err = state.GetSupplyAt(subnetID, 0)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	supply, err = state.GetSupplyAt(subnetID, 2)
+	require.NoError(err)
+	require.Equal(uint64(10), supply)
+
+	supply, err = state.GetSupplyAt(subnetID, 3)
+	require.NoError(err)
+	require.Equal(uint64(20), supply)
+
+	changes, err := state.GetSupplyChanges(subnetID, 0, 5)
+	require.NoError(err)
+	require.Equal([]SupplyChange{
+		{Height: 1, Supply: 10},
+		{Height: 3, Supply: 20},
+	}, changes)
+
+	changes, err = state.GetSupplyChanges(subnetID, 2, 5)
+	require.NoError(err)
+	require.Equal([]SupplyChange{
+		{Height: 3, Supply: 20},
+	}, changes)
+
+	changes, err = state.GetSupplyChanges(constants.PrimaryNetworkID, 0, 2)
+	require.NoError(err)
+	require.Equal([]SupplyChange{
+		{Height: 0, Supply: genesisSupply},
+		{Height: 1, Supply: genesisSupply + 1},
+	}, changes)
+}
+
+func TestStateLoadGenesisSupply(t *testing.T) {
+	require := require.New(t)
+
+	initializedState, _ := newInitializedState(require)
+	genesisSupply, err := initializedState.GetCurrentSupply(constants.PrimaryNetworkID)
+	require.NoError(err)
+
+	genesisBytes, err := genesis.Codec.Marshal(genesis.Version, newTestGenesis(require))
+	require.NoError(err)
+
+	s, _ := newUninitializedState(require)
+	state := s.(*state)
+	require.NoError(state.loadGenesisSupply(genesisBytes))
+	require.Equal(genesisSupply, state.genesisSupply)
+}
+
+func TestStateSupplyAtBeforeHistory(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newInitializedState(require)
+	state := s.(*state)
+	genesisSupply, err := state.GetCurrentSupply(constants.PrimaryNetworkID)
+	require.NoError(err)
+
+	// Simulate a node that started recording the history after genesis.
+	require.NoError(state.Commit())
+	require.NoError(database.ClearPrefix(state.supplyHistoryDB, nil, 1024))
+	require.NoError(state.singletonDB.Delete(historyStartHeightKey))
+	state.historyStartHeight = nil
+
+	state.SetHeight(5)
+	state.SetCurrentSupply(constants.PrimaryNetworkID, genesisSupply+1)
+	require.NoError(state.Commit())
+
+	// The supply at genesis is always known.
+	supply, err := state.GetSupplyAt(constants.PrimaryNetworkID, 0)
+	require.NoError(err)
+	require.Equal(genesisSupply, supply)
+
+	// The supply may have changed between genesis and the start of the
+	// history.
+	_, err = state.GetSupplyAt(constants.PrimaryNetworkID, 4)
+	require.ErrorIs(err, ErrHeightNotRetained)
+	_, err = state.GetSupplyAt(ids.GenerateTestID(), 4)
+	require.ErrorIs(err, ErrHeightNotRetained)
+
+	supply, err = state.GetSupplyAt(constants.PrimaryNetworkID, 5)
+	require.NoError(err)
+	require.Equal(genesisSupply+1, supply)
+}
+
+func TestStateRewardPayouts(t *testing.T) {
+	require := require.New(t)
+
+	state, _ := newInitializedState(require)
+
+	var (
+		subnetID = ids.GenerateTestID()
+		nodeID   = ids.GenerateTestNodeID()
+		addr0    = ids.GenerateTestShortID()
+		addr1    = ids.GenerateTestShortID()
+		assetID  = ids.GenerateTestID()
+	)
+	newStakerTx := func() *txs.Tx {
+		tx := &txs.Tx{
+			Unsigned: &txs.AddSubnetValidatorTx{
+				BaseTx: txs.BaseTx{
+					BaseTx: avax.BaseTx{
+						NetworkID:    constants.UnitTestID,
+						BlockchainID: ids.GenerateTestID(),
+					},
+				},
+				SubnetValidator: txs.SubnetValidator{
+					Validator: txs.Validator{
+						NodeID: nodeID,
+						End:    1,
+						Wght:   1,
+					},
+					Subnet: subnetID,
+				},
+				SubnetAuth: &secp256k1fx.Input{},
+			},
+		}
+		require.NoError(tx.Initialize(txs.Codec))
+		state.AddTx(tx, status.Committed)
+		return tx
+	}
+	newRewardUTXO := func(txID ids.ID, amount uint64, addrs ...ids.ShortID) *avax.UTXO {
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID:        txID,
+				OutputIndex: 1,
+			},
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     addrs,
+				},
+			},
+		}
+	}
+
+	tx0 := newStakerTx()
+	tx1 := newStakerTx()
+	require.NoError(state.Commit())
+
+	timestamp1 := initialTime.Add(time.Second)
+	state.SetHeight(1)
+	state.SetTimestamp(timestamp1)
+	state.AddRewardUTXO(tx0.ID(), newRewardUTXO(tx0.ID(), 10, addr0, addr1))
+	require.NoError(state.Commit())
+
+	timestamp2 := initialTime.Add(2 * time.Second)
+	state.SetHeight(2)
+	state.SetTimestamp(timestamp2)
+	state.AddRewardUTXO(tx1.ID(), newRewardUTXO(tx1.ID(), 20, addr0))
+	require.NoError(state.Commit())
+
+	payout0 := &RewardPayout{
+		TxID:        tx0.ID(),
+		OutputIndex: 1,
+		SubnetID:    subnetID,
+		NodeID:      nodeID,
+		AssetID:     assetID,
+		Amount:      10,
+		Addresses:   []ids.ShortID{addr0, addr1},
+		Height:      1,
+		Timestamp:   uint64(timestamp1.Unix()),
+	}
+	payout1 := &RewardPayout{
+		TxID:        tx1.ID(),
+		OutputIndex: 1,
+		SubnetID:    subnetID,
+		NodeID:      nodeID,
+		AssetID:     assetID,
+		Amount:      20,
+		Addresses:   []ids.ShortID{addr0},
+		Height:      2,
+		Timestamp:   uint64(timestamp2.Unix()),
+	}
+
+	payouts, err := state.GetRewardPayoutsByNodeID(nodeID, nil, 10)
+	require.NoError(err)
+	require.Equal([]*RewardPayout{payout1, payout0}, payouts)
+
+	payouts, err = state.GetRewardPayoutsByAddress(addr1, nil, 10)
+	require.NoError(err)
+	require.Equal([]*RewardPayout{payout0}, payouts)
+
+	// Page through the payouts to [addr0].
+	payouts, err = state.GetRewardPayoutsByAddress(addr0, nil, 1)
+	require.NoError(err)
+	require.Equal([]*RewardPayout{payout1}, payouts)
+
+	payouts, err = state.GetRewardPayoutsByAddress(addr0, payouts[0].Cursor(), 1)
+	require.NoError(err)
+	require.Equal([]*RewardPayout{payout0}, payouts)
+
+	payouts, err = state.GetRewardPayoutsByAddress(addr0, payouts[0].Cursor(), 1)
+	require.NoError(err)
+	require.Empty(payouts)
+
+	_, err = state.GetRewardPayoutsByAddress(addr0, []byte{1}, 1)
+	require.ErrorIs(err, ErrInvalidRewardPayoutCursor)
+}
+
+func TestStateRewardPayoutsNotIndexed(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	execCfg, _ := config.GetExecutionConfig(nil)
+	s := newStateFromDBWithConfig(require, db, execCfg)
+	genesisBlk, err := block.NewApricotCommitBlock(ids.GenerateTestID(), 0)
+	require.NoError(err)
+	require.NoError(s.(*state).syncGenesis(genesisBlk, newTestGenesis(require)))
+
+	// Simulate a node that accepted blocks before the payouts were indexed.
+	require.NoError(s.(*state).singletonDB.Delete(rewardPayoutsStartHeightKey))
+	s.(*state).rewardPayoutsStartHeight = nil
+
+	nodeID := ids.GenerateTestNodeID()
+	_, err = s.GetRewardPayoutsByNodeID(nodeID, nil, 1)
+	require.ErrorIs(err, ErrRewardPayoutsNotIndexed)
+
+	s.SetHeight(5)
+	require.NoError(s.Commit())
+
+	// The index start height is persisted.
+	s = newStateFromDBWithConfig(require, db, execCfg)
+	require.NoError(s.(*state).loadMetadata())
+	require.Equal(uint64(5), *s.(*state).rewardPayoutsStartHeight)
+	_, err = s.GetRewardPayoutsByNodeID(nodeID, nil, 1)
+	require.ErrorIs(err, ErrRewardPayoutsNotIndexed)
+	_, err = s.GetRewardPayoutsByAddress(ids.GenerateTestShortID(), nil, 1)
+	require.ErrorIs(err, ErrRewardPayoutsNotIndexed)
+}
+
+func TestStateCompactPruned(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newInitializedState(require)
+	require.NoError(s.CompactPruned(context.Background(), 0, logging.NoLog{}))
+
+	// Every block ID is included in exactly one range of [blockDB].
+	ranges := s.(*state).prunedRanges()
+	var blockRanges []compactionRange
+	for _, r := range ranges {
+		if r.db == s.(*state).blockDB {
+			blockRanges = append(blockRanges, r)
+		}
+	}
+	require.Len(blockRanges, pruneCompactionRanges)
+	require.Equal([]byte{0}, blockRanges[0].start)
+	for i := 1; i < len(blockRanges); i++ {
+		require.Equal(blockRanges[i-1].limit, blockRanges[i].start)
+	}
+	require.Nil(blockRanges[len(blockRanges)-1].limit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := s.CompactPruned(ctx, time.Hour, logging.NoLog{})
+	require.ErrorIs(err, context.Canceled)
+}
+
+func TestStateAtHeight(t *testing.T) {
+	require := require.New(t)
+
+	execCfg, _ := config.GetExecutionConfig(nil)
+	execCfg.StateHistoryEnabled = true
+	state, _ := newInitializedStateWithConfig(require, execCfg)
+
+	acceptBlock := func(height uint64) {
+		blk, err := block.NewApricotCommitBlock(state.GetLastAccepted(), height)
+		require.NoError(err)
+		state.AddStatelessBlock(blk)
+		state.SetLastAccepted(blk.ID())
+		state.SetHeight(height)
+		require.NoError(state.Commit())
+	}
+	newUTXO := func() *avax.UTXO {
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: initialTxID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: units.Schmeckle,
+			},
+		}
+	}
+
+	genesisTimestamp := state.GetTimestamp()
+	genesisSupply, err := state.GetCurrentSupply(constants.PrimaryNetworkID)
+	require.NoError(err)
+
+	utxo0 := newUTXO()
+	state.AddUTXO(utxo0)
+	state.SetTimestamp(genesisTimestamp.Add(time.Second))
+	state.SetCurrentSupply(constants.PrimaryNetworkID, genesisSupply+1)
+	acceptBlock(1)
+
+	utxo1 := newUTXO()
+	state.DeleteUTXO(utxo0.InputID())
+	state.AddUTXO(utxo1)
+	acceptBlock(2)
+
+	type expectedState struct {
+		timestamp time.Time
+		supply    uint64
+		utxo0     *avax.UTXO
+		utxo1     *avax.UTXO
+	}
+	expectedStates := []expectedState{
+		{
+			timestamp: genesisTimestamp,
+			supply:    genesisSupply,
+		},
+		{
+			timestamp: genesisTimestamp.Add(time.Second),
+			supply:    genesisSupply + 1,
+			utxo0:     utxo0,
+		},
+		{
+			timestamp: genesisTimestamp.Add(time.Second),
+			supply:    genesisSupply + 1,
+			utxo1:     utxo1,
+		},
+	}
+	for height, expected := range expectedStates {
+		chain, err := NewStateAtHeight(state, uint64(height))
+		require.NoError(err)
+
+		require.Equal(expected.timestamp.Unix(), chain.GetTimestamp().Unix())
+
+		supply, err := chain.GetCurrentSupply(constants.PrimaryNetworkID)
+		require.NoError(err)
+		require.Equal(expected.supply, supply)
+
+		for _, test := range []struct {
+			utxoID   ids.ID
+			expected *avax.UTXO
+		}{
+			{utxoID: utxo0.InputID(), expected: expected.utxo0},
+			{utxoID: utxo1.InputID(), expected: expected.utxo1},
+		} {
+			utxo, err := chain.GetUTXO(test.utxoID)
+			if test.expected == nil {
+				require.ErrorIs(err, database.ErrNotFound)
+				continue
+			}
+			require.NoError(err)
+			require.Equal(test.expected.InputID(), utxo.InputID())
+		}
+	}
+
+	_, err = NewStateAtHeight(state, uint64(len(expectedStates)))
+	require.ErrorIs(err, ErrHeightNotAccepted)
+}
+
+func TestStateHistoryRetention(t *testing.T) {
+	require := require.New(t)
+
+	execCfg, _ := config.GetExecutionConfig(nil)
+	execCfg.StateHistoryEnabled = true
+	execCfg.StateHistoryRetention = 2
+	s, _ := newInitializedStateWithConfig(require, execCfg)
+	state := s.(*state)
+
+	genesisTimestamp := state.GetTimestamp()
+	utxos := make([]*avax.UTXO, 4)
+	for i := range utxos {
+		height := uint64(i + 1)
+		utxos[i] = &avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: initialTxID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: units.Schmeckle,
+			},
+		}
+		state.AddUTXO(utxos[i])
+		if height == 1 {
+			state.SetTimestamp(genesisTimestamp.Add(time.Second))
+		}
+		state.SetHeight(height)
+		require.NoError(state.Commit())
+	}
+
+	// Only the last 2 heights are retained.
+	for height := uint64(0); height < 3; height++ {
+		_, err := state.GetTimestampAt(height)
+		require.ErrorIs(err, ErrHeightNotRetained)
+		_, err = state.GetUTXOAt(utxos[0].InputID(), height)
+		require.ErrorIs(err, ErrHeightNotRetained)
+	}
+
+	// The timestamp recorded at a pruned height is still readable.
+	timestamp, err := state.GetTimestampAt(3)
+	require.NoError(err)
+	require.Equal(genesisTimestamp.Add(time.Second).Unix(), timestamp.Unix())
+
+	_, err = state.GetUTXOAt(utxos[3].InputID(), 3)
+	require.ErrorIs(err, database.ErrNotFound)
+	utxo, err := state.GetUTXOAt(utxos[2].InputID(), 3)
+	require.NoError(err)
+	require.Equal(utxos[2].InputID(), utxo.InputID())
+
+	// The UTXO diffs of the pruned heights are deleted.
+	for height := uint64(1); height < 3; height++ {
+		has, err := state.utxoDiffsDB.Has(marshalStartDiffKey(utxos[height-1].InputID(), height))
+		require.NoError(err)
+		require.False(has)
+	}
+	has, err := state.utxoDiffsDB.Has(marshalStartDiffKey(utxos[2].InputID(), 3))
+	require.NoError(err)
+	require.True(has)
+}
+
+func TestStateHistoryDisabled(t *testing.T) {
+	require := require.New(t)
+
+	state, _ := newInitializedState(require)
+	state.SetHeight(1)
+	require.NoError(state.Commit())
+
+	_, err := state.GetTimestampAt(1)
+	require.ErrorIs(err, ErrHeightNotRetained)
+	_, err = NewStateAtHeight(state, 0)
+	require.ErrorIs(err, ErrHeightNotRetained)
+
+	// The supply history is always recorded.
+	_, err = state.GetSupplyAt(constants.PrimaryNetworkID, 1)
+	require.NoError(err)
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"math"
+	"math/bits"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// subnetWeight is the weight of a subnet's validator set. Stakers are added and
+// removed one at a time and can't report an error, so the weight is kept as a
+// 128-bit integer and checked to fit in a uint64 only when it's read.
+type subnetWeight struct {
+	high uint64
+	low  uint64
+}
+
+func (w *subnetWeight) add(weight uint64) {
+	var carry uint64
+	w.low, carry = bits.Add64(w.low, weight, 0)
+	w.high += carry
+}
+
+func (w *subnetWeight) remove(weight uint64) {
+	var borrow uint64
+	w.low, borrow = bits.Sub64(w.low, weight, 0)
+	w.high -= borrow
+}
+
+func (w *subnetWeight) isZero() bool {
+	return w.high == 0 && w.low == 0
+}
+
+// Uint64 returns the weight, or an error if it doesn't fit in a uint64.
+func (w *subnetWeight) Uint64() (uint64, error) {
+	switch w.high {
+	case 0:
+		return w.low, nil
+	case math.MaxUint64:
+		// More weight was removed than was added.
+		return 0, safemath.ErrUnderflow
+	default:
+		return 0, safemath.ErrOverflow
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+func TestSubnetWeight(t *testing.T) {
+	require := require.New(t)
+
+	w := &subnetWeight{}
+	require.True(w.isZero())
+
+	// The weight can exceed a uint64 while stakers are added, as long as it
+	// fits once they are read.
+	w.add(math.MaxUint64)
+	w.add(1)
+	_, err := w.Uint64()
+	require.ErrorIs(err, safemath.ErrOverflow)
+
+	w.remove(2)
+	weight, err := w.Uint64()
+	require.NoError(err)
+	require.Equal(uint64(math.MaxUint64-1), weight)
+
+	w.remove(math.MaxUint64)
+	_, err = w.Uint64()
+	require.ErrorIs(err, safemath.ErrUnderflow)
+
+	w.add(1)
+	require.True(w.isZero())
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// validatorChurnKey = [subnetID] + [periodStart]
+const validatorChurnKeyLength = ids.IDLen + database.Uint64Size
+
+// ValidatorChurn is the stake weight that entered or exited the validator set
+// of a subnet during a churn period.
+type ValidatorChurn struct {
+	// Weight of the subnet's validator set when the first churn of the period
+	// was recorded. The churn of the period is limited relative to it.
+	SubnetWeight uint64 `serialize:"true"`
+	// Churn is the total weight that entered or exited the validator set
+	// during the period, or is scheduled to.
+	Churn uint64 `serialize:"true"`
+}
+
+type validatorChurnKey struct {
+	subnetID ids.ID
+	// Unix time of the start of the churn period.
+	periodStart int64
+}
+
+func (k validatorChurnKey) Bytes() []byte {
+	key := make([]byte, validatorChurnKeyLength)
+	copy(key, k.subnetID[:])
+	binary.BigEndian.PutUint64(key[ids.IDLen:], uint64(k.periodStart))
+	return key
+}
+
+func (s *state) writeValidatorChurns() error {
+	for key, churn := range s.modifiedValidatorChurns {
+		delete(s.modifiedValidatorChurns, key)
+
+		churnBytes, err := txs.GenesisCodec.Marshal(txs.Version, churn)
+		if err != nil {
+			return fmt.Errorf("failed to serialize validator churn: %w", err)
+		}
+		if err := s.validatorChurnDB.Put(key.Bytes(), churnBytes); err != nil {
+			return fmt.Errorf("failed to write validator churn: %w", err)
+		}
+	}
+	return nil
+}
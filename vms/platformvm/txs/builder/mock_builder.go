@@ -161,6 +161,21 @@ func (mr *MockBuilderMockRecorder) NewExportTx(arg0, arg1, arg2, arg3, arg4 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewExportTx", reflect.TypeOf((*MockBuilder)(nil).NewExportTx), arg0, arg1, arg2, arg3, arg4)
 }
 
+// NewFreezeSubnetValidatorTx mocks base method.
+func (m *MockBuilder) NewFreezeSubnetValidatorTx(arg0 ids.NodeID, arg1 ids.ID, arg2 uint64, arg3 []*secp256k1.PrivateKey, arg4 ids.ShortID) (*txs.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewFreezeSubnetValidatorTx", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*txs.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewFreezeSubnetValidatorTx indicates an expected call of NewFreezeSubnetValidatorTx.
+func (mr *MockBuilderMockRecorder) NewFreezeSubnetValidatorTx(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFreezeSubnetValidatorTx", reflect.TypeOf((*MockBuilder)(nil).NewFreezeSubnetValidatorTx), arg0, arg1, arg2, arg3, arg4)
+}
+
 // NewImportTx mocks base method.
 func (m *MockBuilder) NewImportTx(arg0 ids.ID, arg1 ids.ShortID, arg2 []*secp256k1.PrivateKey, arg3 ids.ShortID) (*txs.Tx, error) {
 	m.ctrl.T.Helper()
@@ -107,5 +107,9 @@ func RegisterDUnsignedTxsTypes(targetCodec linearcodec.Codec) error {
 	return utils.Err(
 		targetCodec.RegisterType(&TransferSubnetOwnershipTx{}),
 		targetCodec.RegisterType(&BaseTx{}),
+		targetCodec.RegisterType(&FreezeSubnetValidatorTx{}),
+
+		targetCodec.RegisterType(&stakeable.VestingIn{}),
+		targetCodec.RegisterType(&stakeable.VestingOut{}),
 	)
 }
@@ -550,6 +550,105 @@ func TestAdvanceTimeTxRemoveSubnetValidator(t *testing.T) {
 	require.False(ok)
 }
 
+func TestAdvanceTimeTxUnfreezeSubnetValidator(t *testing.T) {
+	require := require.New(t)
+	env := newEnvironment(t, false /*=postBanff*/, false /*=postCortina*/)
+	env.ctx.Lock.Lock()
+	defer func() {
+		require.NoError(shutdownEnvironment(env))
+	}()
+
+	subnetID := testSubnet1.ID()
+	env.config.TrackedSubnets.Add(subnetID)
+
+	// Add a subnet validator to the staker set
+	subnetValidatorNodeID := ids.NodeID(preFundedKeys[0].PublicKey().Address())
+	subnetVdrStartTime := defaultValidateStartTime
+	subnetVdrEndTime := defaultValidateStartTime.Add(defaultMinStakingDuration)
+	tx, err := env.txBuilder.NewAddSubnetValidatorTx(
+		1,                                 // Weight
+		uint64(subnetVdrStartTime.Unix()), // Start time
+		uint64(subnetVdrEndTime.Unix()),   // end time
+		subnetValidatorNodeID,             // Node ID
+		subnetID,                          // Subnet ID
+		[]*secp256k1.PrivateKey{preFundedKeys[0], preFundedKeys[1]},
+		ids.ShortEmpty,
+	)
+	require.NoError(err)
+
+	staker, err := state.NewCurrentStaker(
+		tx.ID(),
+		tx.Unsigned.(*txs.AddSubnetValidatorTx),
+		0,
+	)
+	require.NoError(err)
+
+	env.state.PutCurrentValidator(staker)
+	env.state.AddTx(tx, status.Committed)
+	env.state.SetHeight(1)
+	require.NoError(env.state.Commit())
+	require.Equal(uint64(1), env.config.Validators.GetWeight(subnetID, subnetValidatorNodeID))
+
+	// Freeze the validator until before it stops validating
+	frozenUntil := env.state.GetTimestamp().Add(time.Hour)
+	tx, err = env.txBuilder.NewFreezeSubnetValidatorTx(
+		subnetValidatorNodeID,
+		subnetID,
+		uint64(frozenUntil.Unix()),
+		[]*secp256k1.PrivateKey{preFundedKeys[0], preFundedKeys[1]},
+		ids.ShortEmpty,
+	)
+	require.NoError(err)
+
+	onAcceptState, err := state.NewDiff(lastAcceptedID, env)
+	require.NoError(err)
+
+	require.NoError(tx.Unsigned.Visit(&StandardTxExecutor{
+		Backend: &env.backend,
+		State:   onAcceptState,
+		Tx:      tx,
+	}))
+	require.NoError(onAcceptState.Apply(env.state))
+	env.state.SetHeight(2)
+	require.NoError(env.state.Commit())
+
+	// The frozen validator is still a current validator, but has no weight
+	_, err = env.state.GetCurrentValidator(subnetID, subnetValidatorNodeID)
+	require.NoError(err)
+	require.Zero(env.config.Validators.GetWeight(subnetID, subnetValidatorNodeID))
+
+	nextChangeTime, err := GetNextStakerChangeTime(env.state)
+	require.NoError(err)
+	require.Equal(frozenUntil, nextChangeTime)
+
+	// Advance time to when the validator is unfrozen
+	env.clk.Set(frozenUntil)
+	tx, err = env.txBuilder.NewAdvanceTimeTx(frozenUntil)
+	require.NoError(err)
+
+	onCommitState, err := state.NewDiff(lastAcceptedID, env)
+	require.NoError(err)
+
+	onAbortState, err := state.NewDiff(lastAcceptedID, env)
+	require.NoError(err)
+
+	executor := ProposalTxExecutor{
+		OnCommitState: onCommitState,
+		OnAbortState:  onAbortState,
+		Backend:       &env.backend,
+		Tx:            tx,
+	}
+	require.NoError(tx.Unsigned.Visit(&executor))
+
+	_, err = executor.OnCommitState.GetFrozenUntil(subnetID, subnetValidatorNodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	require.NoError(executor.OnCommitState.Apply(env.state))
+	env.state.SetHeight(3)
+	require.NoError(env.state.Commit())
+	require.Equal(uint64(1), env.config.Validators.GetWeight(subnetID, subnetValidatorNodeID))
+}
+
 func TestTrackedSubnet(t *testing.T) {
 	for _, tracked := range []bool{true, false} {
 		t.Run(fmt.Sprintf("tracked %t", tracked), func(t *testing.T) {
@@ -894,3 +894,68 @@ func TestProposalTxExecuteAddValidator(t *testing.T) {
 		require.ErrorIs(err, ErrFlowCheckFailed)
 	}
 }
+
+func TestProposalTxExecuteVestingBeforeD(t *testing.T) {
+	tests := []struct {
+		name        string
+		dDelay      time.Duration
+		expectedErr error
+	}{
+		{
+			name:        "pre-D",
+			dDelay:      time.Second,
+			expectedErr: ErrDUpgradeNotActive,
+		},
+		{
+			name:        "post-D",
+			dDelay:      0,
+			expectedErr: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			env := newEnvironment(t, false /*=postBanff*/, false /*=postCortina*/)
+			env.ctx.Lock.Lock()
+			defer func() {
+				require.NoError(shutdownEnvironment(env))
+			}()
+
+			env.config.DTime = env.state.GetTimestamp().Add(test.dDelay)
+
+			tx, err := env.txBuilder.NewAddValidatorTx(
+				env.config.MinValidatorStake,
+				uint64(defaultValidateStartTime.Unix())+1,
+				uint64(defaultValidateEndTime.Unix()),
+				ids.GenerateTestNodeID(),
+				ids.ShortEmpty,
+				reward.PercentDenominator,
+				[]*secp256k1.PrivateKey{preFundedKeys[0]},
+				ids.ShortEmpty, // change addr
+			)
+			require.NoError(err)
+			tx = vestOutput(
+				t,
+				tx,
+				tx.Unsigned.(*txs.AddValidatorTx).StakeOuts[0],
+				uint64(defaultValidateEndTime.Add(time.Hour).Unix()),
+				preFundedKeys[0],
+			)
+
+			onCommitState, err := state.NewDiff(lastAcceptedID, env)
+			require.NoError(err)
+
+			onAbortState, err := state.NewDiff(lastAcceptedID, env)
+			require.NoError(err)
+
+			executor := ProposalTxExecutor{
+				OnCommitState: onCommitState,
+				OnAbortState:  onAbortState,
+				Backend:       &env.backend,
+				Tx:            tx,
+			}
+			err = tx.Unsigned.Visit(&executor)
+			require.ErrorIs(err, test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs/mempool"
+	"github.com/ava-labs/avalanchego/vms/platformvm/utxo"
+)
+
+// RejectionCode identifies why a tx was rejected.
+type RejectionCode string
+
+const (
+	RejectionUnknown                         RejectionCode = "unknown"
+	RejectionConflictsWithOtherTx            RejectionCode = "conflictsWithOtherTx"
+	RejectionInvalidInput                    RejectionCode = "invalidInput"
+	RejectionInvalidOutput                   RejectionCode = "invalidOutput"
+	RejectionInsufficientFunds               RejectionCode = "insufficientFunds"
+	RejectionFlowCheckFailed                 RejectionCode = "flowCheckFailed"
+	RejectionWeightTooSmall                  RejectionCode = "weightTooSmall"
+	RejectionWeightTooLarge                  RejectionCode = "weightTooLarge"
+	RejectionInsufficientDelegationFee       RejectionCode = "insufficientDelegationFee"
+	RejectionStakeTooShort                   RejectionCode = "stakeTooShort"
+	RejectionStakeTooLong                    RejectionCode = "stakeTooLong"
+	RejectionStakeOverflow                   RejectionCode = "stakeOverflow"
+	RejectionOverDelegated                   RejectionCode = "overDelegated"
+	RejectionPeriodMismatch                  RejectionCode = "periodMismatch"
+	RejectionNotValidator                    RejectionCode = "notValidator"
+	RejectionAlreadyValidator                RejectionCode = "alreadyValidator"
+	RejectionDuplicateValidator              RejectionCode = "duplicateValidator"
+	RejectionDelegateToPermissionedValidator RejectionCode = "delegateToPermissionedValidator"
+	RejectionWrongStakedAssetID              RejectionCode = "wrongStakedAssetID"
+	RejectionTimestampNotBeforeStartTime     RejectionCode = "timestampNotBeforeStartTime"
+	RejectionUpgradeNotActive                RejectionCode = "upgradeNotActive"
+	RejectionValidatorChurnTooHigh           RejectionCode = "validatorChurnTooHigh"
+)
+
+// rejectionCodes maps verification errors to their rejection codes. Errors
+// that wrap other errors in this list must appear after them so that the most
+// specific code is reported.
+var rejectionCodes = []struct {
+	err  error
+	code RejectionCode
+}{
+	{mempool.ErrConflictsWithOtherTx, RejectionConflictsWithOtherTx},
+	{utxo.ErrInsufficientFunds, RejectionInsufficientFunds},
+	{utxo.ErrInsufficientUnlockedFunds, RejectionInsufficientFunds},
+	{utxo.ErrInsufficientLockedFunds, RejectionInsufficientFunds},
+	{ErrFlowCheckFailed, RejectionFlowCheckFailed},
+	{ErrWeightTooSmall, RejectionWeightTooSmall},
+	{ErrWeightTooLarge, RejectionWeightTooLarge},
+	{ErrInsufficientDelegationFee, RejectionInsufficientDelegationFee},
+	{ErrStakeTooShort, RejectionStakeTooShort},
+	{ErrStakeTooLong, RejectionStakeTooLong},
+	{ErrStakeOverflow, RejectionStakeOverflow},
+	{ErrOverDelegated, RejectionOverDelegated},
+	{ErrPeriodMismatch, RejectionPeriodMismatch},
+	{ErrNotValidator, RejectionNotValidator},
+	{ErrAlreadyValidator, RejectionAlreadyValidator},
+	{ErrDuplicateValidator, RejectionDuplicateValidator},
+	{ErrDelegateToPermissionedValidator, RejectionDelegateToPermissionedValidator},
+	{ErrWrongStakedAssetID, RejectionWrongStakedAssetID},
+	{ErrTimestampNotBeforeStartTime, RejectionTimestampNotBeforeStartTime},
+	{ErrDUpgradeNotActive, RejectionUpgradeNotActive},
+	{ErrValidatorChurnTooHigh, RejectionValidatorChurnTooHigh},
+}
+
+// Rejection describes why a tx was rejected in a form that can be reported to
+// the issuer of the tx.
+type Rejection struct {
+	Code RejectionCode `json:"code"`
+	// The index of the input that couldn't be spent, if the rejection was
+	// caused by a single input.
+	InputIndex *int `json:"inputIndex,omitempty"`
+	// The index of the output that couldn't be produced, if the rejection was
+	// caused by a single output.
+	OutputIndex *int `json:"outputIndex,omitempty"`
+	// Human readable description of the rejection.
+	Message string `json:"message"`
+}
+
+// NewRejection returns the rejection that describes [err]. Returns nil if
+// [err] is nil.
+func NewRejection(err error) *Rejection {
+	if err == nil {
+		return nil
+	}
+
+	rejection := &Rejection{
+		Code:    RejectionUnknown,
+		Message: err.Error(),
+	}
+	for _, rejectionCode := range rejectionCodes {
+		if errors.Is(err, rejectionCode.err) {
+			rejection.Code = rejectionCode.code
+			break
+		}
+	}
+
+	var (
+		inputErr  *utxo.InputError
+		outputErr *utxo.OutputError
+	)
+	switch {
+	case errors.As(err, &inputErr):
+		rejection.InputIndex = &inputErr.Index
+		if rejection.Code == RejectionUnknown || rejection.Code == RejectionFlowCheckFailed {
+			rejection.Code = RejectionInvalidInput
+		}
+	case errors.As(err, &outputErr):
+		rejection.OutputIndex = &outputErr.Index
+		if rejection.Code == RejectionUnknown || rejection.Code == RejectionFlowCheckFailed {
+			rejection.Code = RejectionInvalidOutput
+		}
+	}
+	return rejection
+}
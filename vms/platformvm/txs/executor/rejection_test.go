@@ -0,0 +1,95 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs/mempool"
+	"github.com/ava-labs/avalanchego/vms/platformvm/utxo"
+)
+
+func TestNewRejection(t *testing.T) {
+	var (
+		inputIndex  = 1
+		outputIndex = 2
+		errCustom   = errors.New("custom")
+	)
+	tests := []struct {
+		name              string
+		err               error
+		expectedRejection *Rejection
+	}{
+		{
+			name:              "no error",
+			err:               nil,
+			expectedRejection: nil,
+		},
+		{
+			name: "unknown error",
+			err:  errCustom,
+			expectedRejection: &Rejection{
+				Code:    RejectionUnknown,
+				Message: errCustom.Error(),
+			},
+		},
+		{
+			name: "stake below minimum",
+			err:  ErrWeightTooSmall,
+			expectedRejection: &Rejection{
+				Code:    RejectionWeightTooSmall,
+				Message: ErrWeightTooSmall.Error(),
+			},
+		},
+		{
+			name: "conflicting tx",
+			err:  fmt.Errorf("%w: %w", mempool.ErrConflictsWithOtherTx, errCustom),
+			expectedRejection: &Rejection{
+				Code:    RejectionConflictsWithOtherTx,
+				Message: fmt.Sprintf("%s: %s", mempool.ErrConflictsWithOtherTx, errCustom),
+			},
+		},
+		{
+			name: "insufficient funds",
+			err:  fmt.Errorf("%w: %w", ErrFlowCheckFailed, utxo.ErrInsufficientUnlockedFunds),
+			expectedRejection: &Rejection{
+				Code:    RejectionInsufficientFunds,
+				Message: fmt.Sprintf("%s: %s", ErrFlowCheckFailed, utxo.ErrInsufficientUnlockedFunds),
+			},
+		},
+		{
+			name: "invalid input",
+			err: fmt.Errorf("%w: %w", ErrFlowCheckFailed, &utxo.InputError{
+				Index: inputIndex,
+				Err:   errCustom,
+			}),
+			expectedRejection: &Rejection{
+				Code:       RejectionInvalidInput,
+				InputIndex: &inputIndex,
+				Message:    fmt.Sprintf("%s: input %d: %s", ErrFlowCheckFailed, inputIndex, errCustom),
+			},
+		},
+		{
+			name: "invalid output",
+			err: fmt.Errorf("%w: %w", ErrFlowCheckFailed, &utxo.OutputError{
+				Index: outputIndex,
+				Err:   errCustom,
+			}),
+			expectedRejection: &Rejection{
+				Code:        RejectionInvalidOutput,
+				OutputIndex: &outputIndex,
+				Message:     fmt.Sprintf("%s: output %d: %s", ErrFlowCheckFailed, outputIndex, errCustom),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expectedRejection, NewRejection(test.err))
+		})
+	}
+}
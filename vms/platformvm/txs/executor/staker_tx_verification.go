@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 
@@ -28,6 +29,7 @@ var (
 	ErrFutureStakeTime                 = fmt.Errorf("staker is attempting to start staking more than %s ahead of the current chain time", MaxFutureStartTime)
 	ErrNotValidator                    = errors.New("isn't a current or pending validator")
 	ErrRemovePermissionlessValidator   = errors.New("attempting to remove permissionless validator")
+	ErrFreezePermissionlessValidator   = errors.New("attempting to freeze permissionless validator")
 	ErrStakeOverflow                   = errors.New("validator stake exceeds limit")
 	ErrPeriodMismatch                  = errors.New("proposed staking period is not inside dependant staking period")
 	ErrOverDelegated                   = errors.New("validator would be over delegated")
@@ -151,6 +153,10 @@ func verifyAddValidatorTx(
 		)
 	}
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, outs); err != nil {
+		return nil, err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -241,6 +247,10 @@ func verifyAddSubnetValidatorTx(
 		return err
 	}
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, tx.Outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -315,6 +325,10 @@ func verifyRemoveSubnetValidatorTx(
 		return nil, false, err
 	}
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, tx.Outs); err != nil {
+		return nil, false, err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -332,6 +346,72 @@ func verifyRemoveSubnetValidatorTx(
 	return vdr, isCurrentValidator, nil
 }
 
+// Returns an error if the given tx is invalid.
+// The transaction is valid if:
+// * [tx.NodeID] is a current PoA validator of [tx.Subnet].
+// * [sTx]'s creds authorize it to spend the stated inputs.
+// * [sTx]'s creds authorize it to freeze a validator of [tx.Subnet].
+// * The flow checker passes.
+func verifyFreezeSubnetValidatorTx(
+	backend *Backend,
+	chainState state.Chain,
+	sTx *txs.Tx,
+	tx *txs.FreezeSubnetValidatorTx,
+) error {
+	if !backend.Config.IsDActivated(chainState.GetTimestamp()) {
+		return ErrDUpgradeNotActive
+	}
+
+	// Verify the tx is well-formed
+	if err := sTx.SyntacticVerify(backend.Ctx); err != nil {
+		return err
+	}
+
+	vdr, err := chainState.GetCurrentValidator(tx.Subnet, tx.NodeID)
+	if err != nil {
+		// Only current validators can be frozen.
+		return fmt.Errorf(
+			"%s %w of %s: %w",
+			tx.NodeID,
+			ErrNotValidator,
+			tx.Subnet,
+			err,
+		)
+	}
+
+	// Invariant: Frozen validators don't have delegators, which would
+	// otherwise still contribute weight to the validator set.
+	if !vdr.Priority.IsPermissionedValidator() {
+		return ErrFreezePermissionlessValidator
+	}
+
+	if !backend.Bootstrapped.Get() {
+		// Not bootstrapped yet -- don't need to do full verification.
+		return nil
+	}
+
+	baseTxCreds, err := verifySubnetAuthorization(backend, chainState, sTx, tx.Subnet, tx.SubnetAuth)
+	if err != nil {
+		return err
+	}
+
+	// Verify the flowcheck
+	if err := backend.FlowChecker.VerifySpend(
+		tx,
+		chainState,
+		tx.Ins,
+		tx.Outs,
+		baseTxCreds,
+		map[ids.ID]uint64{
+			backend.Ctx.AVAXAssetID: backend.Config.TxFee,
+		},
+	); err != nil {
+		return fmt.Errorf("%w: %w", ErrFlowCheckFailed, err)
+	}
+
+	return nil
+}
+
 // verifyAddDelegatorTx carries out the validation for an AddDelegatorTx.
 // It returns the tx outputs that should be returned if this delegator is not
 // added to the staking set.
@@ -424,6 +504,10 @@ func verifyAddDelegatorTx(
 		return nil, ErrOverDelegated
 	}
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, outs); err != nil {
+		return nil, err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -548,6 +632,10 @@ func verifyAddPermissionlessValidatorTx(
 	copy(outs, tx.Outs)
 	copy(outs[len(tx.Outs):], tx.StakeOuts)
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -692,6 +780,10 @@ func verifyAddPermissionlessDelegatorTx(
 		txFee = backend.Config.AddPrimaryNetworkDelegatorFee
 	}
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -746,6 +838,10 @@ func verifyTransferSubnetOwnershipTx(
 		return err
 	}
 
+	if err := verifyVestingActivated(backend, chainState, tx.Ins, tx.Outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	if err := backend.FlowChecker.VerifySpend(
 		tx,
@@ -762,3 +858,24 @@ func verifyTransferSubnetOwnershipTx(
 
 	return nil
 }
+
+// verifyVestingActivated returns an error if any of [ins] or [outs] vests
+// before the D upgrade is activated.
+func verifyVestingActivated(
+	backend *Backend,
+	chainState state.Chain,
+	ins []*avax.TransferableInput,
+	outs []*avax.TransferableOutput,
+) error {
+	for i, in := range ins {
+		if _, ok := in.In.(*stakeable.VestingIn); ok && !backend.Config.IsDActivated(chainState.GetTimestamp()) {
+			return fmt.Errorf("%w: input %d vests", ErrDUpgradeNotActive, i)
+		}
+	}
+	for i, out := range outs {
+		if _, ok := out.Out.(*stakeable.VestingOut); ok && !backend.Config.IsDActivated(chainState.GetTimestamp()) {
+			return fmt.Errorf("%w: output %d vests", ErrDUpgradeNotActive, i)
+		}
+	}
+	return nil
+}
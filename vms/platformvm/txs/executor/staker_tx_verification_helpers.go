@@ -95,7 +95,8 @@ func getDelegatorRules(
 }
 
 // GetNextStakerChangeTime returns the next time a staker will be either added
-// or removed to/from the current validator set.
+// or removed to/from the current validator set, or a frozen validator will be
+// unfrozen.
 func GetNextStakerChangeTime(state state.Chain) (time.Time, error) {
 	currentStakerIterator, err := state.GetCurrentStakerIterator()
 	if err != nil {
@@ -109,23 +110,35 @@ func GetNextStakerChangeTime(state state.Chain) (time.Time, error) {
 	}
 	defer pendingStakerIterator.Release()
 
-	hasCurrentStaker := currentStakerIterator.Next()
-	hasPendingStaker := pendingStakerIterator.Next()
-	switch {
-	case hasCurrentStaker && hasPendingStaker:
-		nextCurrentTime := currentStakerIterator.Value().NextTime
-		nextPendingTime := pendingStakerIterator.Value().NextTime
-		if nextCurrentTime.Before(nextPendingTime) {
-			return nextCurrentTime, nil
+	frozenValidatorIterator, err := state.GetFrozenValidatorIterator()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer frozenValidatorIterator.Release()
+
+	var (
+		nextTime time.Time
+		found    bool
+	)
+	updateNextTime := func(t time.Time) {
+		if !found || t.Before(nextTime) {
+			nextTime = t
+			found = true
 		}
-		return nextPendingTime, nil
-	case hasCurrentStaker:
-		return currentStakerIterator.Value().NextTime, nil
-	case hasPendingStaker:
-		return pendingStakerIterator.Value().NextTime, nil
-	default:
+	}
+	if currentStakerIterator.Next() {
+		updateNextTime(currentStakerIterator.Value().NextTime)
+	}
+	if pendingStakerIterator.Next() {
+		updateNextTime(pendingStakerIterator.Value().NextTime)
+	}
+	if frozenValidatorIterator.Next() {
+		updateNextTime(frozenValidatorIterator.Value().Until)
+	}
+	if !found {
 		return time.Time{}, database.ErrNotFound
 	}
+	return nextTime, nil
 }
 
 // GetValidator returns information about the given validator, which may be a
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/set"
@@ -58,6 +59,10 @@ func (e *StandardTxExecutor) CreateChainTx(tx *txs.CreateChainTx) error {
 		return err
 	}
 
+	if err := verifyVestingActivated(e.Backend, e.State, tx.Ins, tx.Outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	timestamp := e.State.GetTimestamp()
 	createBlockchainTxFee := e.Config.GetCreateBlockchainTxFee(timestamp)
@@ -97,6 +102,10 @@ func (e *StandardTxExecutor) CreateSubnetTx(tx *txs.CreateSubnetTx) error {
 		return err
 	}
 
+	if err := verifyVestingActivated(e.Backend, e.State, tx.Ins, tx.Outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	timestamp := e.State.GetTimestamp()
 	createSubnetTxFee := e.Config.GetCreateSubnetTxFee(timestamp)
@@ -130,6 +139,14 @@ func (e *StandardTxExecutor) ImportTx(tx *txs.ImportTx) error {
 		return err
 	}
 
+	ins := make([]*avax.TransferableInput, len(tx.Ins)+len(tx.ImportedInputs))
+	copy(ins, tx.Ins)
+	copy(ins[len(tx.Ins):], tx.ImportedInputs)
+
+	if err := verifyVestingActivated(e.Backend, e.State, ins, tx.Outs); err != nil {
+		return err
+	}
+
 	e.Inputs = set.NewSet[ids.ID](len(tx.ImportedInputs))
 	utxoIDs := make([][]byte, len(tx.ImportedInputs))
 	for i, in := range tx.ImportedInputs {
@@ -167,10 +184,6 @@ func (e *StandardTxExecutor) ImportTx(tx *txs.ImportTx) error {
 			utxos[i+len(tx.Ins)] = utxo
 		}
 
-		ins := make([]*avax.TransferableInput, len(tx.Ins)+len(tx.ImportedInputs))
-		copy(ins, tx.Ins)
-		copy(ins[len(tx.Ins):], tx.ImportedInputs)
-
 		if err := e.FlowChecker.VerifySpendUTXOs(
 			tx,
 			utxos,
@@ -218,6 +231,10 @@ func (e *StandardTxExecutor) ExportTx(tx *txs.ExportTx) error {
 		}
 	}
 
+	if err := verifyVestingActivated(e.Backend, e.State, tx.Ins, outs); err != nil {
+		return err
+	}
+
 	// Verify the flowcheck
 	if err := e.FlowChecker.VerifySpend(
 		tx,
@@ -295,6 +312,9 @@ func (e *StandardTxExecutor) AddValidatorTx(tx *txs.AddValidatorTx) error {
 	if err != nil {
 		return err
 	}
+	if err := recordStakerChurn(e.Backend, e.State, newStaker); err != nil {
+		return err
+	}
 
 	e.State.PutPendingValidator(newStaker)
 	avax.Consume(e.State, tx.Ins)
@@ -326,6 +346,9 @@ func (e *StandardTxExecutor) AddSubnetValidatorTx(tx *txs.AddSubnetValidatorTx)
 	if err != nil {
 		return err
 	}
+	if err := recordStakerChurn(e.Backend, e.State, newStaker); err != nil {
+		return err
+	}
 
 	e.State.PutPendingValidator(newStaker)
 	avax.Consume(e.State, tx.Ins)
@@ -349,6 +372,9 @@ func (e *StandardTxExecutor) AddDelegatorTx(tx *txs.AddDelegatorTx) error {
 	if err != nil {
 		return err
 	}
+	if err := recordStakerChurn(e.Backend, e.State, newStaker); err != nil {
+		return err
+	}
 
 	e.State.PutPendingDelegator(newStaker)
 	avax.Consume(e.State, tx.Ins)
@@ -374,6 +400,9 @@ func (e *StandardTxExecutor) RemoveSubnetValidatorTx(tx *txs.RemoveSubnetValidat
 	}
 
 	if isCurrentValidator {
+		if err := recordValidatorRemovalChurn(e.Backend, e.State, staker); err != nil {
+			return err
+		}
 		e.State.DeleteCurrentValidator(staker)
 	} else {
 		e.State.DeletePendingValidator(staker)
@@ -404,6 +433,10 @@ func (e *StandardTxExecutor) TransformSubnetTx(tx *txs.TransformSubnetTx) error
 		return err
 	}
 
+	if err := verifyVestingActivated(e.Backend, e.State, tx.Ins, tx.Outs); err != nil {
+		return err
+	}
+
 	totalRewardAmount := tx.MaximumSupply - tx.InitialSupply
 	if err := e.Backend.FlowChecker.VerifySpend(
 		tx,
@@ -449,6 +482,9 @@ func (e *StandardTxExecutor) AddPermissionlessValidatorTx(tx *txs.AddPermissionl
 	if err != nil {
 		return err
 	}
+	if err := recordStakerChurn(e.Backend, e.State, newStaker); err != nil {
+		return err
+	}
 
 	e.State.PutPendingValidator(newStaker)
 	avax.Consume(e.State, tx.Ins)
@@ -483,6 +519,9 @@ func (e *StandardTxExecutor) AddPermissionlessDelegatorTx(tx *txs.AddPermissionl
 	if err != nil {
 		return err
 	}
+	if err := recordStakerChurn(e.Backend, e.State, newStaker); err != nil {
+		return err
+	}
 
 	e.State.PutPendingDelegator(newStaker)
 	avax.Consume(e.State, tx.Ins)
@@ -515,6 +554,81 @@ func (e *StandardTxExecutor) TransferSubnetOwnershipTx(tx *txs.TransferSubnetOwn
 	return nil
 }
 
+// Verifies a [*txs.FreezeSubnetValidatorTx] and, if it passes, executes it on
+// [e.State]. For verification rules, see [verifyFreezeSubnetValidatorTx]. This
+// transaction will result in [tx.NodeID] having no weight in the validator set
+// of [tx.Subnet] until [tx.FrozenUntil]. If [tx.FrozenUntil] isn't after the
+// chain time, [tx.NodeID] is unfrozen instead.
+func (e *StandardTxExecutor) FreezeSubnetValidatorTx(tx *txs.FreezeSubnetValidatorTx) error {
+	err := verifyFreezeSubnetValidatorTx(
+		e.Backend,
+		e.State,
+		e.Tx,
+		tx,
+	)
+	if err != nil {
+		return err
+	}
+
+	currentTimestamp := e.State.GetTimestamp()
+	frozenUntil := time.Unix(int64(tx.FrozenUntil), 0)
+	if !frozenUntil.After(currentTimestamp) {
+		frozenUntil = time.Time{}
+	}
+	if err := e.recordFreezeChurn(tx, currentTimestamp, frozenUntil); err != nil {
+		return err
+	}
+	e.State.SetFrozenUntil(tx.Subnet, tx.NodeID, frozenUntil)
+
+	txID := e.Tx.ID()
+	avax.Consume(e.State, tx.Ins)
+	avax.Produce(e.State, txID, tx.Outs)
+
+	return nil
+}
+
+// recordFreezeChurn records the churn of freezing the validator of [tx] until
+// [frozenUntil], or of unfreezing it if [frozenUntil] is the zero time. The
+// weight of a frozen validator exits the validator set when it's frozen and
+// enters it again when it's unfrozen.
+func (e *StandardTxExecutor) recordFreezeChurn(
+	tx *txs.FreezeSubnetValidatorTx,
+	currentTimestamp time.Time,
+	frozenUntil time.Time,
+) error {
+	if e.Config.ValidatorChurnPeriod == 0 {
+		return nil
+	}
+
+	vdr, err := e.State.GetCurrentValidator(tx.Subnet, tx.NodeID)
+	if err != nil {
+		return err
+	}
+	_, err = e.State.GetFrozenUntil(tx.Subnet, tx.NodeID)
+	isFrozen := err == nil
+	if err != nil && err != database.ErrNotFound {
+		return err
+	}
+
+	switch {
+	case !isFrozen && frozenUntil.IsZero():
+		// The validator's weight doesn't change.
+		return nil
+	case !isFrozen:
+		if err := recordValidatorChurn(e.Backend, e.State, tx.Subnet, currentTimestamp, vdr.Weight); err != nil {
+			return err
+		}
+		return recordValidatorChurn(e.Backend, e.State, tx.Subnet, frozenUntil, vdr.Weight)
+	case frozenUntil.IsZero():
+		// The validator is unfrozen now instead of when it was frozen until.
+		// The churn that was recorded then is kept.
+		return recordValidatorChurn(e.Backend, e.State, tx.Subnet, currentTimestamp, vdr.Weight)
+	default:
+		// The validator is unfrozen at [frozenUntil] instead.
+		return recordValidatorChurn(e.Backend, e.State, tx.Subnet, frozenUntil, vdr.Weight)
+	}
+}
+
 func (e *StandardTxExecutor) BaseTx(tx *txs.BaseTx) error {
 	if !e.Backend.Config.IsDActivated(e.State.GetTimestamp()) {
 		return ErrDUpgradeNotActive
@@ -26,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
 	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
@@ -1582,3 +1583,87 @@ func TestStandardExecutorTransformSubnetTx(t *testing.T) {
 		})
 	}
 }
+
+// vestOutput makes [out] of [tx] vest at [locktime] and signs [tx] again with
+// [key], which must own every input of [tx].
+func vestOutput(t *testing.T, tx *txs.Tx, out *avax.TransferableOutput, locktime uint64, key *secp256k1.PrivateKey) *txs.Tx {
+	out.Out = &stakeable.VestingOut{
+		Tranches: []stakeable.Tranche{{
+			Locktime: locktime,
+			Amount:   out.Out.Amount(),
+		}},
+		TransferableOut: out.Out,
+	}
+
+	signers := make([][]*secp256k1.PrivateKey, tx.Unsigned.InputIDs().Len())
+	for i := range signers {
+		signers[i] = []*secp256k1.PrivateKey{key}
+	}
+	tx, err := txs.NewSigned(tx.Unsigned, txs.Codec, signers)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestStandardTxExecutorVestingBeforeD(t *testing.T) {
+	tests := []struct {
+		name        string
+		dDelay      time.Duration
+		expectedErr error
+	}{
+		{
+			name:        "pre-D",
+			dDelay:      time.Second,
+			expectedErr: ErrDUpgradeNotActive,
+		},
+		{
+			name:        "post-D",
+			dDelay:      0,
+			expectedErr: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			env := newEnvironment(t, false /*=postBanff*/, false /*=postCortina*/)
+			env.ctx.Lock.Lock()
+			defer func() {
+				require.NoError(shutdownEnvironment(env))
+			}()
+
+			chainTime := env.state.GetTimestamp()
+			env.config.BanffTime = chainTime
+			env.config.DTime = chainTime.Add(test.dDelay)
+
+			startTime := defaultGenesisTime.Add(1 * time.Second)
+			tx, err := env.txBuilder.NewAddValidatorTx(
+				env.config.MinValidatorStake,
+				uint64(startTime.Unix()),
+				uint64(startTime.Add(defaultMinStakingDuration).Unix()),
+				ids.GenerateTestNodeID(),
+				ids.ShortEmpty,
+				reward.PercentDenominator,
+				[]*secp256k1.PrivateKey{preFundedKeys[0]},
+				ids.ShortEmpty, // change addr
+			)
+			require.NoError(err)
+			tx = vestOutput(
+				t,
+				tx,
+				tx.Unsigned.(*txs.AddValidatorTx).StakeOuts[0],
+				uint64(startTime.Add(defaultMaxStakingDuration).Unix()),
+				preFundedKeys[0],
+			)
+
+			onAcceptState, err := state.NewDiff(lastAcceptedID, env)
+			require.NoError(err)
+
+			executor := StandardTxExecutor{
+				Backend: &env.backend,
+				State:   onAcceptState,
+				Tx:      tx,
+			}
+			err = tx.Unsigned.Visit(&executor)
+			require.ErrorIs(err, test.expectedErr)
+		})
+	}
+}
@@ -69,6 +69,7 @@ type stateChanges struct {
 	pendingValidatorsToRemove []*state.Staker
 	pendingDelegatorsToRemove []*state.Staker
 	currentValidatorsToRemove []*state.Staker
+	validatorsToUnfreeze      []*state.FrozenValidator
 }
 
 func (s *stateChanges) Apply(stateDiff state.Diff) {
@@ -91,12 +92,15 @@ func (s *stateChanges) Apply(stateDiff state.Diff) {
 	for _, currentValidatorToRemove := range s.currentValidatorsToRemove {
 		stateDiff.DeleteCurrentValidator(currentValidatorToRemove)
 	}
+	for _, validatorToUnfreeze := range s.validatorsToUnfreeze {
+		stateDiff.SetFrozenUntil(validatorToUnfreeze.SubnetID, validatorToUnfreeze.NodeID, time.Time{})
+	}
 }
 
 func (s *stateChanges) Len() int {
 	return len(s.currentValidatorsToAdd) + len(s.currentDelegatorsToAdd) +
 		len(s.pendingValidatorsToRemove) + len(s.pendingDelegatorsToRemove) +
-		len(s.currentValidatorsToRemove)
+		len(s.currentValidatorsToRemove) + len(s.validatorsToUnfreeze)
 }
 
 // AdvanceTimeTo does not modify [parentState].
@@ -205,6 +209,22 @@ func AdvanceTimeTo(
 
 		changes.currentValidatorsToRemove = append(changes.currentValidatorsToRemove, stakerToRemove)
 	}
+
+	// Unfreeze any frozen validators whose freeze ends at or before the new
+	// timestamp.
+	frozenValidatorIterator, err := parentState.GetFrozenValidatorIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer frozenValidatorIterator.Release()
+
+	for frozenValidatorIterator.Next() {
+		frozenValidator := frozenValidatorIterator.Value()
+		if frozenValidator.Until.After(newChainTime) {
+			break
+		}
+		changes.validatorsToUnfreeze = append(changes.validatorsToUnfreeze, frozenValidator)
+	}
 	return changes, nil
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 )
 
@@ -20,6 +21,50 @@ type MempoolTxVerifier struct {
 	ParentID      ids.ID
 	StateVersions state.Versions
 	Tx            *txs.Tx
+
+	// If non-nil, [Tx] is verified on top of [state], which holds the changes
+	// of the txs verified before it, rather than on top of [ParentID].
+	state state.Diff
+}
+
+// VerifyMempoolTxs verifies [batch] on top of [parentID] as if its txs were
+// issued in order in the same block, so that a tx can depend on the txs
+// before it. For example, a delegator can delegate to a validator added by an
+// earlier tx of the batch.
+//
+// Unlike a single tx, a tx of the batch that starts staking too far in the
+// future fails with [ErrFutureStakeTime], because its changes aren't applied
+// and the txs after it would be verified without them.
+//
+// Returns the index of the first tx that failed verification and its error,
+// or len([batch]) if every tx is valid. If the batch couldn't be verified for
+// a reason unrelated to its txs, such as a failure to read the state, the
+// index is -1.
+func VerifyMempoolTxs(
+	backend *Backend,
+	parentID ids.ID,
+	stateVersions state.Versions,
+	batch []*txs.Tx,
+) (int, error) {
+	v := MempoolTxVerifier{
+		Backend:       backend,
+		ParentID:      parentID,
+		StateVersions: stateVersions,
+	}
+	batchState, err := v.standardBaseState()
+	if err != nil {
+		return -1, err
+	}
+
+	v.state = batchState
+	for i, tx := range batch {
+		v.Tx = tx
+		if err := tx.Unsigned.Visit(&v); err != nil {
+			return i, err
+		}
+		batchState.AddTx(tx, status.Committed)
+	}
+	return len(batch), nil
 }
 
 func (*MempoolTxVerifier) AdvanceTimeTx(*txs.AdvanceTimeTx) error {
@@ -82,10 +127,18 @@ func (v *MempoolTxVerifier) BaseTx(tx *txs.BaseTx) error {
 	return v.standardTx(tx)
 }
 
+func (v *MempoolTxVerifier) FreezeSubnetValidatorTx(tx *txs.FreezeSubnetValidatorTx) error {
+	return v.standardTx(tx)
+}
+
 func (v *MempoolTxVerifier) standardTx(tx txs.UnsignedTx) error {
-	baseState, err := v.standardBaseState()
-	if err != nil {
-		return err
+	baseState := v.state
+	if baseState == nil {
+		var err error
+		baseState, err = v.standardBaseState()
+		if err != nil {
+			return err
+		}
 	}
 
 	executor := StandardTxExecutor{
@@ -93,10 +146,11 @@ func (v *MempoolTxVerifier) standardTx(tx txs.UnsignedTx) error {
 		State:   baseState,
 		Tx:      v.Tx,
 	}
-	err = tx.Visit(&executor)
+	err := tx.Visit(&executor)
 	// We ignore [errFutureStakeTime] here because the time will be advanced
-	// when this transaction is issued.
-	if errors.Is(err, ErrFutureStakeTime) {
+	// when this transaction is issued. In a batch, the txs after this one
+	// depend on its changes, which weren't made, so the error is returned.
+	if errors.Is(err, ErrFutureStakeTime) && v.state == nil {
 		return nil
 	}
 	return err
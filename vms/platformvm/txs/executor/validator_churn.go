@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+)
+
+var ErrValidatorChurnTooHigh = errors.New("validator churn would exceed the limit of the churn period")
+
+// recordStakerChurn records the weight of [staker] as entering the validator
+// set of its subnet at its start time and exiting it at its end time.
+func recordStakerChurn(backend *Backend, chainState state.Chain, staker *state.Staker) error {
+	if err := recordValidatorChurn(backend, chainState, staker.SubnetID, staker.StartTime, staker.Weight); err != nil {
+		return err
+	}
+	return recordValidatorChurn(backend, chainState, staker.SubnetID, staker.EndTime, staker.Weight)
+}
+
+// recordValidatorRemovalChurn records the weight of [staker] as exiting the
+// validator set of its subnet at the current chain time, instead of at its end
+// time. The churn that was recorded at its end time is kept.
+func recordValidatorRemovalChurn(backend *Backend, chainState state.Chain, staker *state.Staker) error {
+	if backend.Config.GetValidatorChurnConfig(staker.SubnetID).Period == 0 {
+		return nil
+	}
+	return recordValidatorChurn(backend, chainState, staker.SubnetID, chainState.GetTimestamp(), staker.Weight)
+}
+
+// recordValidatorChurn records that [weight] enters or exits the validator set
+// of [subnetID] at [changeTime].
+//
+// Churn is only recorded after the D upgrade, if a churn period is configured
+// for [subnetID]. Returns [ErrValidatorChurnTooHigh] if the churn of the period
+// containing [changeTime] would exceed the fraction of the subnet's weight that
+// its churn limit allows. Subnets that had no weight when the first churn of the
+// period was recorded aren't limited, so that their validator set can be
+// bootstrapped.
+func recordValidatorChurn(
+	backend *Backend,
+	chainState state.Chain,
+	subnetID ids.ID,
+	changeTime time.Time,
+	weight uint64,
+) error {
+	churnConfig := backend.Config.GetValidatorChurnConfig(subnetID)
+	period := churnConfig.Period
+	if period == 0 || !backend.Config.IsDActivated(chainState.GetTimestamp()) {
+		return nil
+	}
+
+	periodStart := changeTime.Truncate(period)
+	churn, err := chainState.GetValidatorChurn(subnetID, periodStart)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		subnetWeight, err := chainState.GetSubnetWeight(subnetID)
+		if err != nil {
+			return err
+		}
+		churn = &state.ValidatorChurn{
+			SubnetWeight: subnetWeight,
+		}
+	default:
+		return err
+	}
+
+	newChurn, err := math.Add64(churn.Churn, weight)
+	if err != nil {
+		return err
+	}
+	if backend.Bootstrapped.Get() && churn.SubnetWeight > 0 {
+		maxChurn := maxValidatorChurn(churn.SubnetWeight, churnConfig.MaxChurn)
+		if newChurn > maxChurn {
+			return fmt.Errorf(
+				"%w: %d of %d weight of subnet %s would change in the period starting at %s",
+				ErrValidatorChurnTooHigh,
+				newChurn,
+				churn.SubnetWeight,
+				subnetID,
+				periodStart,
+			)
+		}
+	}
+
+	chainState.SetValidatorChurn(subnetID, periodStart, &state.ValidatorChurn{
+		SubnetWeight: churn.SubnetWeight,
+		Churn:        newChurn,
+	})
+	return nil
+}
+
+// maxValidatorChurn returns [maxChurn] of [subnetWeight], where [maxChurn] is
+// denominated in [reward.PercentDenominator], without overflowing.
+func maxValidatorChurn(subnetWeight, maxChurn uint64) uint64 {
+	// Invariant: [maxChurn] <= [reward.PercentDenominator], so neither product
+	// overflows.
+	return subnetWeight/reward.PercentDenominator*maxChurn +
+		subnetWeight%reward.PercentDenominator*maxChurn/reward.PercentDenominator
+}
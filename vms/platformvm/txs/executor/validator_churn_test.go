@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/vms/platformvm/config"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+func TestStandardTxExecutorValidatorChurnLimit(t *testing.T) {
+	require := require.New(t)
+	env := newEnvironment(t, false /*=postBanff*/, false /*=postCortina*/)
+	env.ctx.Lock.Lock()
+	defer func() {
+		require.NoError(shutdownEnvironment(env))
+	}()
+
+	env.config.BanffTime = env.state.GetTimestamp()
+	env.config.ValidatorChurnPeriod = time.Hour
+	env.config.MaxValidatorChurn = reward.PercentDenominator / 10 // 10%
+
+	subnetID := testSubnet1.ID()
+	height := uint64(0)
+	addSubnetValidator := func(key *secp256k1.PrivateKey, weight uint64, startTime, endTime time.Time) error {
+		tx, err := env.txBuilder.NewAddSubnetValidatorTx(
+			weight,
+			uint64(startTime.Unix()),
+			uint64(endTime.Unix()),
+			ids.NodeID(key.PublicKey().Address()),
+			subnetID,
+			[]*secp256k1.PrivateKey{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+			testSubnet1ControlKeys[0].PublicKey().Address(), // change addr
+		)
+		require.NoError(err)
+
+		onAcceptState, err := state.NewDiff(lastAcceptedID, env)
+		require.NoError(err)
+
+		if err := tx.Unsigned.Visit(&StandardTxExecutor{
+			Backend: &env.backend,
+			State:   onAcceptState,
+			Tx:      tx,
+		}); err != nil {
+			return err
+		}
+
+		require.NoError(onAcceptState.Apply(env.state))
+		height++
+		env.state.SetHeight(height)
+		require.NoError(env.state.Commit())
+		return nil
+	}
+
+	// Give the subnet a current validator with a weight of 100. Its churn
+	// isn't recorded, since it's added directly to the state.
+	tx, err := env.txBuilder.NewAddSubnetValidatorTx(
+		100,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		ids.NodeID(preFundedKeys[0].PublicKey().Address()),
+		subnetID,
+		[]*secp256k1.PrivateKey{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	require.NoError(err)
+	staker, err := state.NewCurrentStaker(tx.ID(), tx.Unsigned.(*txs.AddSubnetValidatorTx), 0)
+	require.NoError(err)
+	env.state.PutCurrentValidator(staker)
+	env.state.AddTx(tx, status.Committed)
+	height++
+	env.state.SetHeight(height)
+	require.NoError(env.state.Commit())
+
+	// 10% of the subnet's weight can enter in the period of the start time
+	// and exit in the period of the end time.
+	startTime := defaultValidateStartTime.Add(time.Second)
+	require.NoError(addSubnetValidator(preFundedKeys[1], 10, startTime, defaultValidateEndTime))
+
+	for _, periodStart := range []time.Time{startTime.Truncate(time.Hour), defaultValidateEndTime.Truncate(time.Hour)} {
+		churn, err := env.state.GetValidatorChurn(subnetID, periodStart)
+		require.NoError(err)
+		require.Equal(&state.ValidatorChurn{
+			SubnetWeight: 100,
+			Churn:        10,
+		}, churn)
+	}
+
+	// Any more weight entering in the same period exceeds the limit.
+	err = addSubnetValidator(preFundedKeys[2], 1, startTime.Add(time.Second), defaultValidateEndTime.Add(-time.Hour))
+	require.ErrorIs(err, ErrValidatorChurnTooHigh)
+
+	// Weight can enter and exit in other periods.
+	require.NoError(addSubnetValidator(preFundedKeys[2], 1, startTime.Add(time.Hour), defaultValidateEndTime.Add(-time.Hour)))
+
+	// The limit of a subnet can be configured separately.
+	env.config.SubnetValidatorChurn = map[ids.ID]config.ValidatorChurnConfig{
+		subnetID: {
+			Period:   time.Hour,
+			MaxChurn: reward.PercentDenominator / 5, // 20%
+		},
+	}
+	require.NoError(addSubnetValidator(preFundedKeys[3], 10, startTime.Add(time.Second), defaultValidateEndTime.Add(-2*time.Hour)))
+	err = addSubnetValidator(preFundedKeys[4], 1, startTime.Add(time.Second), defaultValidateEndTime.Add(-2*time.Hour))
+	require.ErrorIs(err, ErrValidatorChurnTooHigh)
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+)
+
+var (
+	_ UnsignedTx = (*FreezeSubnetValidatorTx)(nil)
+
+	ErrFreezePrimaryNetworkValidator = errors.New("can't freeze primary network validator with FreezeSubnetValidatorTx")
+)
+
+// Freezes or unfreezes a validator of a permissioned subnet. A frozen validator
+// remains a current validator of the subnet, but it has no weight in the
+// validator set until it is unfrozen.
+type FreezeSubnetValidatorTx struct {
+	BaseTx `serialize:"true"`
+	// The node to freeze.
+	NodeID ids.NodeID `serialize:"true" json:"nodeID"`
+	// The subnet the node is validating.
+	Subnet ids.ID `serialize:"true" json:"subnetID"`
+	// Unix time that the validator is automatically unfrozen at. If this time
+	// isn't after the chain time, the validator is unfrozen immediately.
+	FrozenUntil uint64 `serialize:"true" json:"frozenUntil"`
+	// Proves that the issuer has the right to freeze the node.
+	SubnetAuth verify.Verifiable `serialize:"true" json:"subnetAuthorization"`
+}
+
+func (tx *FreezeSubnetValidatorTx) SyntacticVerify(ctx *snow.Context) error {
+	switch {
+	case tx == nil:
+		return ErrNilTx
+	case tx.SyntacticallyVerified:
+		// already passed syntactic verification
+		return nil
+	case tx.Subnet == constants.PrimaryNetworkID:
+		return ErrFreezePrimaryNetworkValidator
+	}
+
+	if err := tx.BaseTx.SyntacticVerify(ctx); err != nil {
+		return err
+	}
+	if err := tx.SubnetAuth.Verify(); err != nil {
+		return err
+	}
+
+	tx.SyntacticallyVerified = true
+	return nil
+}
+
+func (tx *FreezeSubnetValidatorTx) Visit(visitor Visitor) error {
+	return visitor.FreezeSubnetValidatorTx(tx)
+}
@@ -84,6 +84,11 @@ func (i *issuer) BaseTx(*txs.BaseTx) error {
 	return nil
 }
 
+func (i *issuer) FreezeSubnetValidatorTx(*txs.FreezeSubnetValidatorTx) error {
+	i.m.addDecisionTx(i.tx)
+	return nil
+}
+
 func (i *issuer) AddPermissionlessValidatorTx(*txs.AddPermissionlessValidatorTx) error {
 	i.m.addStakerTx(i.tx)
 	return nil
@@ -34,6 +34,8 @@ const (
 var (
 	_ Mempool = (*mempool)(nil)
 
+	ErrConflictsWithOtherTx = errors.New("tx conflicts with other tx")
+
 	errMempoolFull = errors.New("mempool is full")
 )
 
@@ -176,7 +178,7 @@ func (m *mempool) Add(tx *txs.Tx) error {
 
 	inputs := tx.Unsigned.InputIDs()
 	if m.consumedUTXOs.Overlaps(inputs) {
-		return fmt.Errorf("tx %s conflicts with a transaction in the mempool", txID)
+		return fmt.Errorf("%w: tx %s conflicts with a transaction in the mempool", ErrConflictsWithOtherTx, txID)
 	}
 
 	if err := tx.Unsigned.Visit(&issuer{
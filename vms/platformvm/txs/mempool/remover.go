@@ -67,6 +67,11 @@ func (r *remover) BaseTx(*txs.BaseTx) error {
 	return nil
 }
 
+func (r *remover) FreezeSubnetValidatorTx(*txs.FreezeSubnetValidatorTx) error {
+	r.m.removeDecisionTxs([]*txs.Tx{r.tx})
+	return nil
+}
+
 func (r *remover) AddPermissionlessValidatorTx(*txs.AddPermissionlessValidatorTx) error {
 	r.m.removeStakerTx(r.tx)
 	return nil
@@ -20,4 +20,5 @@ type Visitor interface {
 	AddPermissionlessDelegatorTx(*AddPermissionlessDelegatorTx) error
 	TransferSubnetOwnershipTx(*TransferSubnetOwnershipTx) error
 	BaseTx(*BaseTx) error
+	FreezeSubnetValidatorTx(*FreezeSubnetValidatorTx) error
 }
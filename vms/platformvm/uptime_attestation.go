@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp"
+)
+
+// UptimeDenominator is the uptime reported for a validator that was online for
+// the entire period.
+const UptimeDenominator = 10_000
+
+var (
+	// uptimeAttestationDomain prefixes every UptimeAttestationPayload. It
+	// can't be parsed as the codec version of a [payload.Payload], so a
+	// signature over an uptime attestation can't be used as the signature of
+	// any other warp message.
+	uptimeAttestationDomain = []byte("avalanche:uptimeAttestation:v1")
+
+	uptimeAttestationPayloadLen = len(uptimeAttestationDomain) + ids.NodeIDLen + ids.IDLen
+
+	errInvalidUptimeAttestation = errors.New("invalid uptime attestation")
+)
+
+// UptimeAttestation is a node's local view of the uptimes of the current
+// validators of a subnet.
+type UptimeAttestation struct {
+	// NodeID is the node that observed the uptimes.
+	NodeID   ids.NodeID `serialize:"true"`
+	SubnetID ids.ID     `serialize:"true"`
+	// Timestamp is the unix time at which the uptimes were calculated.
+	Timestamp uint64            `serialize:"true"`
+	Uptimes   []ValidatorUptime `serialize:"true"`
+}
+
+// ValidatorUptime is the uptime of a validator from its start time until the
+// timestamp of the attestation.
+type ValidatorUptime struct {
+	NodeID    ids.NodeID `serialize:"true"`
+	StartTime uint64     `serialize:"true"`
+	// Uptime is out of [UptimeDenominator].
+	Uptime uint32 `serialize:"true"`
+}
+
+func (u ValidatorUptime) Less(other ValidatorUptime) bool {
+	return u.NodeID.Less(other.NodeID)
+}
+
+func (a *UptimeAttestation) Bytes() ([]byte, error) {
+	return txs.Codec.Marshal(txs.Version, a)
+}
+
+func ParseUptimeAttestation(b []byte) (*UptimeAttestation, error) {
+	a := &UptimeAttestation{}
+	if _, err := txs.Codec.Unmarshal(b, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// UptimeAttestationPayload is the payload of the warp message that is signed
+// to attest to an UptimeAttestation. It commits to the hash of the
+// attestation, so that the attestation isn't bounded by the maximum size of a
+// warp payload, and to the node that produced it.
+type UptimeAttestationPayload struct {
+	NodeID          ids.NodeID
+	AttestationHash ids.ID
+}
+
+// Bytes returns the domain tag followed by [NodeID] and [AttestationHash].
+func (p *UptimeAttestationPayload) Bytes() []byte {
+	b := make([]byte, 0, uptimeAttestationPayloadLen)
+	b = append(b, uptimeAttestationDomain...)
+	b = append(b, p.NodeID[:]...)
+	return append(b, p.AttestationHash[:]...)
+}
+
+func ParseUptimeAttestationPayload(b []byte) (*UptimeAttestationPayload, error) {
+	if len(b) != uptimeAttestationPayloadLen || !bytes.HasPrefix(b, uptimeAttestationDomain) {
+		return nil, fmt.Errorf("%w: payload isn't an uptime attestation", errInvalidUptimeAttestation)
+	}
+	b = b[len(uptimeAttestationDomain):]
+	p := &UptimeAttestationPayload{}
+	copy(p.NodeID[:], b[:ids.NodeIDLen])
+	copy(p.AttestationHash[:], b[ids.NodeIDLen:])
+	return p, nil
+}
+
+// NewUptimeAttestationMessage returns the warp message that is signed by
+// [nodeID] to attest to [attestationBytes].
+func NewUptimeAttestationMessage(
+	networkID uint32,
+	chainID ids.ID,
+	nodeID ids.NodeID,
+	attestationBytes []byte,
+) (*warp.UnsignedMessage, error) {
+	p := &UptimeAttestationPayload{
+		NodeID:          nodeID,
+		AttestationHash: hashing.ComputeHash256Array(attestationBytes),
+	}
+	return warp.NewUnsignedMessage(networkID, chainID, p.Bytes())
+}
+
+// SignedUptimeAttestation is an UptimeAttestation along with the BLS signature
+// of the node that produced it.
+type SignedUptimeAttestation struct {
+	Attestation *UptimeAttestation
+	// Bytes is the serialized [Attestation].
+	Bytes     []byte
+	Message   *warp.UnsignedMessage
+	PublicKey *bls.PublicKey
+	Signature *bls.Signature
+}
+
+// Verify returns nil if [Attestation] is the serialization of [Bytes],
+// [Message] attests to [Bytes] on behalf of the node that produced
+// [Attestation], and [Signature] is the signature of [PublicKey] over
+// [Message].
+//
+// Verify doesn't check that [PublicKey] is registered to the node that
+// produced the attestation.
+func (a *SignedUptimeAttestation) Verify() error {
+	attestationBytes, err := a.Attestation.Bytes()
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidUptimeAttestation, err)
+	}
+	if !bytes.Equal(attestationBytes, a.Bytes) {
+		return fmt.Errorf("%w: attestation doesn't match the attestation bytes", errInvalidUptimeAttestation)
+	}
+	p, err := ParseUptimeAttestationPayload(a.Message.Payload)
+	if err != nil {
+		return err
+	}
+	if p.NodeID != a.Attestation.NodeID {
+		return fmt.Errorf("%w: message is signed for %s, not %s", errInvalidUptimeAttestation, p.NodeID, a.Attestation.NodeID)
+	}
+	if p.AttestationHash != hashing.ComputeHash256Array(a.Bytes) {
+		return fmt.Errorf("%w: message doesn't attest to the attestation bytes", errInvalidUptimeAttestation)
+	}
+	if !bls.Verify(a.PublicKey, a.Signature, a.Message.Bytes()) {
+		return fmt.Errorf("%w: invalid signature", errInvalidUptimeAttestation)
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 
 	"go.uber.org/zap"
 
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
@@ -37,8 +39,37 @@ var (
 	errLocktimeMismatch             = errors.New("input locktime does not match UTXO locktime")
 	errCantSign                     = errors.New("can't sign")
 	errLockedFundsNotMarkedAsLocked = errors.New("locked funds not marked as locked")
+	errVestingScheduleMismatch      = errors.New("input vesting schedule does not match UTXO vesting schedule")
 )
 
+// InputError is returned when the input at [Index] can't be spent.
+type InputError struct {
+	Index int
+	Err   error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("input %d: %s", e.Index, e.Err)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// OutputError is returned when the output at [Index] can't be produced.
+type OutputError struct {
+	Index int
+	Err   error
+}
+
+func (e *OutputError) Error() string {
+	return fmt.Sprintf("output %d: %s", e.Index, e.Err)
+}
+
+func (e *OutputError) Unwrap() error {
+	return e.Err
+}
+
 // TODO: Stake and Authorize should be replaced by similar methods in the
 // P-chain wallet
 type Spender interface {
@@ -441,11 +472,14 @@ func (h *handler) VerifySpend(
 	for index, input := range ins {
 		utxo, err := utxoDB.GetUTXO(input.InputID())
 		if err != nil {
-			return fmt.Errorf(
-				"failed to read consumed UTXO %s due to: %w",
-				&input.UTXOID,
-				err,
-			)
+			return &InputError{
+				Index: index,
+				Err: fmt.Errorf(
+					"failed to read consumed UTXO %s due to: %w",
+					&input.UTXOID,
+					err,
+				),
+			}
 		}
 		utxos[index] = utxo
 	}
@@ -501,131 +535,164 @@ func (h *handler) VerifySpendUTXOs(
 		realAssetID := utxo.AssetID()
 		claimedAssetID := input.AssetID()
 		if realAssetID != claimedAssetID {
-			return fmt.Errorf(
-				"%w: %s != %s",
-				errAssetIDMismatch,
-				claimedAssetID,
-				realAssetID,
-			)
+			return &InputError{
+				Index: index,
+				Err: fmt.Errorf(
+					"%w: %s != %s",
+					errAssetIDMismatch,
+					claimedAssetID,
+					realAssetID,
+				),
+			}
 		}
 
 		out := utxo.Out
 		locktime := uint64(0)
+		// Set [tranches] to the vesting schedule of this UTXO, if applicable
+		var tranches []stakeable.Tranche
 		// Set [locktime] to this UTXO's locktime, if applicable
-		if inner, ok := out.(*stakeable.LockOut); ok {
+		switch inner := out.(type) {
+		case *stakeable.LockOut:
 			out = inner.TransferableOut
 			locktime = inner.Locktime
+		case *stakeable.VestingOut:
+			out = inner.TransferableOut
+			tranches = inner.Tranches
+			// The UTXO is locked until its last tranche vests
+			locktime = inner.EndLocktime()
 		}
 
 		in := input.In
-		// The UTXO says it's locked until [locktime], but this input, which
-		// consumes it, is not locked even though [locktime] hasn't passed. This
-		// is invalid.
-		if inner, ok := in.(*stakeable.LockIn); now < locktime && !ok {
-			return errLockedFundsNotMarkedAsLocked
-		} else if ok {
-			if inner.Locktime != locktime {
+		switch inner := in.(type) {
+		case *stakeable.LockIn:
+			if tranches != nil || inner.Locktime != locktime {
 				// This input is locked, but its locktime is wrong
-				return fmt.Errorf(
-					"%w: %d != %d",
-					errLocktimeMismatch,
-					inner.Locktime,
-					locktime,
-				)
+				return &InputError{
+					Index: index,
+					Err: fmt.Errorf(
+						"%w: %d != %d",
+						errLocktimeMismatch,
+						inner.Locktime,
+						locktime,
+					),
+				}
 			}
 			in = inner.TransferableIn
+		case *stakeable.VestingIn:
+			if !slices.Equal(inner.Tranches, tranches) {
+				// This input vests, but its schedule is wrong
+				return &InputError{
+					Index: index,
+					Err:   errVestingScheduleMismatch,
+				}
+			}
+			in = inner.TransferableIn
+		default:
+			// The UTXO says it's locked until [locktime], but this input, which
+			// consumes it, is not locked even though [locktime] hasn't passed.
+			// This is invalid.
+			if now < locktime {
+				return &InputError{
+					Index: index,
+					Err:   errLockedFundsNotMarkedAsLocked,
+				}
+			}
 		}
 
 		// Verify that this tx's credentials allow [in] to be spent
 		if err := h.fx.VerifyTransfer(tx, in, creds[index], out); err != nil {
-			return fmt.Errorf("failed to verify transfer: %w", err)
+			return &InputError{
+				Index: index,
+				Err:   fmt.Errorf("failed to verify transfer: %w", err),
+			}
 		}
 
-		amount := in.Amount()
+		if tranches == nil {
+			tranches = []stakeable.Tranche{{
+				Locktime: locktime,
+				Amount:   in.Amount(),
+			}}
+		}
 
-		if now >= locktime {
-			newUnlockedConsumed, err := math.Add64(unlockedConsumed[realAssetID], amount)
+		// Locked tranches are tracked by the owner of the UTXO
+		var ownerID ids.ID
+		if now < locktime {
+			var err error
+			ownerID, err = getOwnerID(out)
 			if err != nil {
-				return err
+				return &InputError{
+					Index: index,
+					Err:   err,
+				}
 			}
-			unlockedConsumed[realAssetID] = newUnlockedConsumed
-			continue
 		}
+		for _, tranche := range tranches {
+			if now >= tranche.Locktime {
+				newUnlockedConsumed, err := math.Add64(unlockedConsumed[realAssetID], tranche.Amount)
+				if err != nil {
+					return &InputError{
+						Index: index,
+						Err:   err,
+					}
+				}
+				unlockedConsumed[realAssetID] = newUnlockedConsumed
+				continue
+			}
 
-		owned, ok := out.(fx.Owned)
-		if !ok {
-			return fmt.Errorf("expected fx.Owned but got %T", out)
-		}
-		owner := owned.Owners()
-		ownerBytes, err := txs.Codec.Marshal(txs.Version, owner)
-		if err != nil {
-			return fmt.Errorf("couldn't marshal owner: %w", err)
-		}
-		lockedConsumedAsset, ok := lockedConsumed[realAssetID]
-		if !ok {
-			lockedConsumedAsset = make(map[uint64]map[ids.ID]uint64)
-			lockedConsumed[realAssetID] = lockedConsumedAsset
-		}
-		ownerID := hashing.ComputeHash256Array(ownerBytes)
-		owners, ok := lockedConsumedAsset[locktime]
-		if !ok {
-			owners = make(map[ids.ID]uint64)
-			lockedConsumedAsset[locktime] = owners
-		}
-		newAmount, err := math.Add64(owners[ownerID], amount)
-		if err != nil {
-			return err
+			if err := addLocked(lockedConsumed, realAssetID, tranche.Locktime, ownerID, tranche.Amount); err != nil {
+				return &InputError{
+					Index: index,
+					Err:   err,
+				}
+			}
 		}
-		owners[ownerID] = newAmount
 	}
 
-	for _, out := range outs {
+	for index, out := range outs {
 		assetID := out.AssetID()
 
 		output := out.Output()
-		locktime := uint64(0)
-		// Set [locktime] to this output's locktime, if applicable
-		if inner, ok := output.(*stakeable.LockOut); ok {
+		var tranches []stakeable.Tranche
+		// Set [tranches] to this output's locked amounts, if applicable
+		switch inner := output.(type) {
+		case *stakeable.LockOut:
 			output = inner.TransferableOut
-			locktime = inner.Locktime
+			tranches = []stakeable.Tranche{{
+				Locktime: inner.Locktime,
+				Amount:   output.Amount(),
+			}}
+		case *stakeable.VestingOut:
+			output = inner.TransferableOut
+			tranches = inner.Tranches
 		}
 
-		amount := output.Amount()
-
-		if locktime == 0 {
-			newUnlockedProduced, err := math.Add64(unlockedProduced[assetID], amount)
+		if tranches == nil {
+			newUnlockedProduced, err := math.Add64(unlockedProduced[assetID], output.Amount())
 			if err != nil {
-				return err
+				return &OutputError{
+					Index: index,
+					Err:   err,
+				}
 			}
 			unlockedProduced[assetID] = newUnlockedProduced
 			continue
 		}
 
-		owned, ok := output.(fx.Owned)
-		if !ok {
-			return fmt.Errorf("expected fx.Owned but got %T", out)
-		}
-		owner := owned.Owners()
-		ownerBytes, err := txs.Codec.Marshal(txs.Version, owner)
+		ownerID, err := getOwnerID(output)
 		if err != nil {
-			return fmt.Errorf("couldn't marshal owner: %w", err)
-		}
-		lockedProducedAsset, ok := lockedProduced[assetID]
-		if !ok {
-			lockedProducedAsset = make(map[uint64]map[ids.ID]uint64)
-			lockedProduced[assetID] = lockedProducedAsset
-		}
-		ownerID := hashing.ComputeHash256Array(ownerBytes)
-		owners, ok := lockedProducedAsset[locktime]
-		if !ok {
-			owners = make(map[ids.ID]uint64)
-			lockedProducedAsset[locktime] = owners
+			return &OutputError{
+				Index: index,
+				Err:   err,
+			}
 		}
-		newAmount, err := math.Add64(owners[ownerID], amount)
-		if err != nil {
-			return err
+		for _, tranche := range tranches {
+			if err := addLocked(lockedProduced, assetID, tranche.Locktime, ownerID, tranche.Amount); err != nil {
+				return &OutputError{
+					Index: index,
+					Err:   err,
+				}
+			}
 		}
-		owners[ownerID] = newAmount
 	}
 
 	// Make sure that for each assetID and locktime, tokens produced <= tokens consumed
@@ -669,3 +736,44 @@ func (h *handler) VerifySpendUTXOs(
 	}
 	return nil
 }
+
+// getOwnerID returns the hash of the owners of [out].
+func getOwnerID(out interface{}) (ids.ID, error) {
+	owned, ok := out.(fx.Owned)
+	if !ok {
+		return ids.Empty, fmt.Errorf("expected fx.Owned but got %T", out)
+	}
+	owner := owned.Owners()
+	ownerBytes, err := txs.Codec.Marshal(txs.Version, owner)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("couldn't marshal owner: %w", err)
+	}
+	return hashing.ComputeHash256Array(ownerBytes), nil
+}
+
+// addLocked adds [amount] of [assetID] that is locked until [locktime] and
+// owned by [ownerID] to [locked].
+func addLocked(
+	locked map[ids.ID]map[uint64]map[ids.ID]uint64,
+	assetID ids.ID,
+	locktime uint64,
+	ownerID ids.ID,
+	amount uint64,
+) error {
+	lockedAsset, ok := locked[assetID]
+	if !ok {
+		lockedAsset = make(map[uint64]map[ids.ID]uint64)
+		locked[assetID] = lockedAsset
+	}
+	owners, ok := lockedAsset[locktime]
+	if !ok {
+		owners = make(map[ids.ID]uint64)
+		lockedAsset[locktime] = owners
+	}
+	newAmount, err := math.Add64(owners[ownerID], amount)
+	if err != nil {
+		return err
+	}
+	owners[ownerID] = newAmount
+	return nil
+}
@@ -1083,6 +1083,239 @@ func TestVerifySpendUTXOs(t *testing.T) {
 			producedAmounts: make(map[ids.ID]uint64),
 			expectedErr:     nil,
 		},
+		{
+			description: "attempt to consume vesting output as unlocked",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) - 1, Amount: 1},
+						{Locktime: uint64(now.Unix()) + 1, Amount: 1},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 2,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &secp256k1fx.TransferInput{
+					Amt: 2,
+				},
+			}},
+			outs: []*avax.TransferableOutput{},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{},
+			expectedErr:     errLockedFundsNotMarkedAsLocked,
+		},
+		{
+			description: "attempt to consume vesting output as locked",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 2},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 2,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &stakeable.LockIn{
+					Locktime: uint64(now.Unix()) + 1,
+					TransferableIn: &secp256k1fx.TransferInput{
+						Amt: 2,
+					},
+				},
+			}},
+			outs: []*avax.TransferableOutput{},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{},
+			expectedErr:     errLocktimeMismatch,
+		},
+		{
+			description: "attempt to modify vesting schedule",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 1},
+						{Locktime: uint64(now.Unix()) + 2, Amount: 1},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 2,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &stakeable.VestingIn{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 2},
+					},
+					TransferableIn: &secp256k1fx.TransferInput{
+						Amt: 2,
+					},
+				},
+			}},
+			outs: []*avax.TransferableOutput{},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{},
+			expectedErr:     errVestingScheduleMismatch,
+		},
+		{
+			description: "attempt to consume locked output as vesting",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.LockOut{
+					Locktime: uint64(now.Unix()) + 1,
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 2,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &stakeable.VestingIn{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 2},
+					},
+					TransferableIn: &secp256k1fx.TransferInput{
+						Amt: 2,
+					},
+				},
+			}},
+			outs: []*avax.TransferableOutput{},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{},
+			expectedErr:     errVestingScheduleMismatch,
+		},
+		{
+			description: "partially vested input, one locked output, positive fee",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) - 1, Amount: 1},
+						{Locktime: uint64(now.Unix()) + 1, Amount: 2},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 3,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &stakeable.VestingIn{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) - 1, Amount: 1},
+						{Locktime: uint64(now.Unix()) + 1, Amount: 2},
+					},
+					TransferableIn: &secp256k1fx.TransferInput{
+						Amt: 3,
+					},
+				},
+			}},
+			outs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.LockOut{
+					Locktime: uint64(now.Unix()) + 1,
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 2,
+					},
+				},
+			}},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{
+				h.ctx.AVAXAssetID: 1,
+			},
+			expectedErr: nil,
+		},
+		{
+			description: "attempt to vest earlier",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 1},
+						{Locktime: uint64(now.Unix()) + 2, Amount: 2},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 3,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &stakeable.VestingIn{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 1},
+						{Locktime: uint64(now.Unix()) + 2, Amount: 2},
+					},
+					TransferableIn: &secp256k1fx.TransferInput{
+						Amt: 3,
+					},
+				},
+			}},
+			outs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) + 1, Amount: 2},
+						{Locktime: uint64(now.Unix()) + 2, Amount: 1},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 3,
+					},
+				},
+			}},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{},
+			expectedErr:     ErrInsufficientLockedFunds,
+		},
+		{
+			description: "fully vested input, no outputs, positive fee",
+			utxos: []*avax.UTXO{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				Out: &stakeable.VestingOut{
+					Tranches: []stakeable.Tranche{
+						{Locktime: uint64(now.Unix()) - 1, Amount: 1},
+						{Locktime: uint64(now.Unix()), Amount: 1},
+					},
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt: 2,
+					},
+				},
+			}},
+			ins: []*avax.TransferableInput{{
+				Asset: avax.Asset{ID: h.ctx.AVAXAssetID},
+				In: &secp256k1fx.TransferInput{
+					Amt: 2,
+				},
+			}},
+			outs: []*avax.TransferableOutput{},
+			creds: []verify.Verifiable{
+				&secp256k1fx.Credential{},
+			},
+			producedAmounts: map[ids.ID]uint64{
+				h.ctx.AVAXAssetID: 2,
+			},
+			expectedErr: nil,
+		},
 	}
 
 	for _, test := range tests {
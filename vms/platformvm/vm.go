@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
@@ -34,6 +35,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/api"
 	"github.com/ava-labs/avalanchego/vms/platformvm/block"
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
+	"github.com/ava-labs/avalanchego/vms/platformvm/forkcheck"
 	"github.com/ava-labs/avalanchego/vms/platformvm/fx"
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
@@ -86,8 +88,18 @@ type VM struct {
 	txBuilder txbuilder.Builder
 	manager   blockexecutor.Manager
 
+	// Compares the chain with the chains of peers. See [Service.CheckForks].
+	forkChecker *forkcheck.Checker
+
+	// See [config.ExecutionConfig.UptimeAttestationEnabled].
+	uptimeAttestationEnabled bool
+
 	// TODO: Remove after v1.11.x is activated
 	pruned utils.Atomic[bool]
+	// Cancels the compaction of the database after pruning, if any.
+	//
+	// TODO: Remove after v1.11.x is activated
+	cancelPruneCompaction context.CancelFunc
 }
 
 // Initialize this blockchain.
@@ -124,6 +136,7 @@ func (vm *VM) Initialize(
 
 	vm.ctx = chainCtx
 	vm.db = db
+	vm.uptimeAttestationEnabled = execConfig.UptimeAttestationEnabled
 
 	vm.codecRegistry = linearcodec.NewDefault()
 	vm.fx = &secp256k1fx.Fx{}
@@ -148,6 +161,8 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	vm.forkChecker = forkcheck.New(chainCtx.Log, appSender, &chainCtx.Lock, forkCheckChain{vm.state})
+
 	validatorManager := pvalidators.NewManager(chainCtx.Log, vm.Config, vm.state, vm.metrics, &vm.clock)
 	vm.State = validatorManager
 	vm.atomicUtxosManager = avax.NewAtomicUTXOManager(chainCtx.SharedMemory, txs.Codec)
@@ -228,6 +243,8 @@ func (vm *VM) Initialize(
 		return nil
 	}
 
+	compactionCtx, cancel := context.WithCancel(context.Background())
+	vm.cancelPruneCompaction = cancel
 	go func() {
 		err := vm.state.PruneAndIndex(&vm.ctx.Lock, vm.ctx.Log)
 		if err != nil {
@@ -237,6 +254,19 @@ func (vm *VM) Initialize(
 		}
 
 		vm.pruned.Set(true)
+
+		if err != nil || !execConfig.PruneCompactionEnabled {
+			return
+		}
+
+		err = vm.state.CompactPruned(compactionCtx, execConfig.PruneCompactionInterval, vm.ctx.Log)
+		// Compaction is cancelled when the VM is shutdown, which isn't an
+		// error.
+		if err != nil && compactionCtx.Err() == nil {
+			vm.ctx.Log.Error("compaction of pruned state failed",
+				zap.Error(err),
+			)
+		}
 	}()
 
 	return nil
@@ -343,6 +373,10 @@ func (vm *VM) Shutdown(context.Context) error {
 		return nil
 	}
 
+	if vm.cancelPruneCompaction != nil {
+		vm.cancelPruneCompaction()
+	}
+
 	vm.Builder.Shutdown()
 
 	if vm.bootstrapped.Get() {
@@ -413,6 +447,9 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]http.Handler, error) {
 		stakerAttributesCache: &cache.LRU[ids.ID, *stakerAttributes]{
 			Size: stakerAttributesCacheSize,
 		},
+		idempotencyKeys: &cache.LRU[string, *idempotentTx]{
+			Size: idempotencyKeysCacheSize,
+		},
 	}
 	err := server.RegisterService(service, "platform")
 	return map[string]http.Handler{
@@ -447,6 +484,35 @@ func (vm *VM) Disconnected(_ context.Context, nodeID ids.NodeID) error {
 	return vm.state.Commit()
 }
 
+// AppRequest answers the fork checks of peers, which are the only requests
+// sent by this VM. The block builder's network only gossips txs.
+func (vm *VM) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, deadline time.Time, request []byte) error {
+	return vm.forkChecker.AppRequest(ctx, nodeID, requestID, deadline, request)
+}
+
+func (vm *VM) AppResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	return vm.forkChecker.AppResponse(ctx, nodeID, requestID, response)
+}
+
+func (vm *VM) AppRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32) error {
+	return vm.forkChecker.AppRequestFailed(ctx, nodeID, requestID)
+}
+
+// forkCheckChain is the chain that [forkcheck.Checker] compares with the
+// chains of peers.
+type forkCheckChain struct {
+	state.State
+}
+
+func (c forkCheckChain) LastAccepted() (uint64, ids.ID, error) {
+	blkID := c.GetLastAccepted()
+	blk, err := c.GetStatelessBlock(blkID)
+	if err != nil {
+		return 0, ids.Empty, err
+	}
+	return blk.Height(), blkID, nil
+}
+
 func (vm *VM) CodecRegistry() codec.Registry {
 	return vm.codecRegistry
 }
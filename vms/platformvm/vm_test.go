@@ -1401,6 +1401,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 			TimeoutHalflife:    5 * time.Minute,
 			TimeoutCoefficient: 1.25,
 		},
+		nil,
 		benchlist,
 		"",
 		prometheus.NewRegistry(),
@@ -1426,6 +1427,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		set.Set[ids.ID]{},
 		nil,
 		router.HealthConfig{},
+		nil,
 		"",
 		prometheus.NewRegistry(),
 	))
@@ -1446,6 +1448,8 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		externalSender,
 		chainRouter,
 		timeoutManager,
+		nil,
+		nil,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
 		subnets.New(consensusCtx.NodeID, subnets.Config{GossipConfig: gossipConfig}),
 	)
@@ -1521,6 +1525,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 		msgChan,
 		time.Hour,
 		2,
+		time.Second,
 		cpuTracker,
 		vm,
 		subnets.New(ctx.NodeID, subnets.Config{}),
@@ -1026,6 +1026,7 @@ func initTestRemoteProposerVM(
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	valState := &validators.TestState{
@@ -666,6 +666,7 @@ func TestOptionTimestampValidity(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	coreVM.InitializeF = func(
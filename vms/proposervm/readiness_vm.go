@@ -0,0 +1,19 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var _ common.ReadinessVM = (*VM)(nil)
+
+func (vm *VM) ReadyToBuild(ctx context.Context) bool {
+	if vm.readinessVM == nil {
+		return true
+	}
+	return vm.readinessVM.ReadyToBuild(ctx)
+}
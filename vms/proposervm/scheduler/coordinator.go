@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// Coordinator staggers the times at which the schedulers of multiple chains
+// notify their engines to build a block. Chains of the same Subnet share a
+// Coordinator so that a resource constrained node never builds blocks for
+// several of them at the same instant.
+//
+// Coordinator is safe for concurrent use.
+type Coordinator struct {
+	// The minimum time between two reservations.
+	stagger time.Duration
+
+	lock sync.Mutex
+	// The time of the most recent reservation.
+	last time.Time
+}
+
+// NewCoordinator returns a Coordinator that keeps reservations at least
+// [stagger] apart.
+func NewCoordinator(stagger time.Duration) *Coordinator {
+	return &Coordinator{
+		stagger: stagger,
+	}
+}
+
+// Reserve returns the earliest time at or after [now] that is at least
+// [stagger] after the previous reservation, and reserves it.
+func (c *Coordinator) Reserve(now time.Time) time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	reserved := c.last.Add(c.stagger)
+	if reserved.Before(now) {
+		reserved = now
+	}
+	c.last = reserved
+	return reserved
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinatorReserve(t *testing.T) {
+	require := require.New(t)
+
+	stagger := time.Second
+	c := NewCoordinator(stagger)
+	now := time.Unix(1_000, 0)
+
+	require.Equal(now, c.Reserve(now))
+	require.Equal(now.Add(stagger), c.Reserve(now))
+	require.Equal(now.Add(2*stagger), c.Reserve(now.Add(stagger/2)))
+
+	later := now.Add(10 * stagger)
+	require.Equal(later, c.Reserve(later))
+}
@@ -37,20 +37,33 @@ type scheduler struct {
 	// from telling the engine to call its VM's BuildBlock method until the
 	// given time
 	newBuildBlockTime chan time.Time
+	// If non-nil, messages from the VM are only delivered to the engine at
+	// times reserved with [coordinator].
+	coordinator *Coordinator
 }
 
-func New(log logging.Logger, toEngine chan<- common.Message) (Scheduler, chan<- common.Message) {
+// If [coordinator] is non-nil, the engine is only notified at times reserved
+// with it.
+func New(log logging.Logger, toEngine chan<- common.Message, coordinator *Coordinator) (Scheduler, chan<- common.Message) {
 	vmToEngine := make(chan common.Message, cap(toEngine))
 	return &scheduler{
 		log:               log,
 		fromVM:            vmToEngine,
 		toEngine:          toEngine,
 		newBuildBlockTime: make(chan time.Time),
+		coordinator:       coordinator,
 	}, vmToEngine
 }
 
 func (s *scheduler) Dispatch(buildBlockTime time.Time) {
 	timer := time.NewTimer(time.Until(buildBlockTime))
+
+	// A message from the VM that is waiting for the time reserved with
+	// [s.coordinator] before being delivered to the engine.
+	var (
+		pendingMsg common.Message
+		hasPending bool
+	)
 waitloop:
 	for {
 		select {
@@ -72,21 +85,28 @@ waitloop:
 			continue waitloop
 		}
 
+		if hasPending {
+			s.sendToEngine(pendingMsg)
+			hasPending = false
+		}
+
 		for {
 			select {
 			case msg := <-s.fromVM:
-				// Give the engine the message from the VM asking the engine to
-				// build a block
-				select {
-				case s.toEngine <- msg:
-				default:
-					// If the channel to the engine is full, drop the message
-					// from the VM to avoid deadlock
-					s.log.Debug("dropping message from VM",
-						zap.String("reason", "channel to engine is full"),
-						zap.Stringer("messageString", msg),
-					)
+				if s.coordinator != nil {
+					now := time.Now()
+					if reserved := s.coordinator.Reserve(now); reserved.After(now) {
+						// Another chain was recently notified, so wait for
+						// the reserved time. We know [timer.C] was drained in
+						// the first select statement so its safe to call
+						// [timer.Reset]
+						pendingMsg = msg
+						hasPending = true
+						timer.Reset(reserved.Sub(now))
+						continue waitloop
+					}
 				}
+				s.sendToEngine(msg)
 			case buildBlockTime, ok := <-s.newBuildBlockTime:
 				// The time at which we should notify the engine that it should
 				// try to build a block has changed
@@ -103,6 +123,20 @@ waitloop:
 	}
 }
 
+// Give the engine the message from the VM asking the engine to build a block
+func (s *scheduler) sendToEngine(msg common.Message) {
+	select {
+	case s.toEngine <- msg:
+	default:
+		// If the channel to the engine is full, drop the message from the VM
+		// to avoid deadlock
+		s.log.Debug("dropping message from VM",
+			zap.String("reason", "channel to engine is full"),
+			zap.Stringer("messageString", msg),
+		)
+	}
+}
+
 func (s *scheduler) SetBuildBlockTime(t time.Time) {
 	s.newBuildBlockTime <- t
 }
@@ -17,7 +17,7 @@ func TestDelayFromNew(t *testing.T) {
 	toEngine := make(chan common.Message, 10)
 	startTime := time.Now().Add(50 * time.Millisecond)
 
-	s, fromVM := New(logging.NoLog{}, toEngine)
+	s, fromVM := New(logging.NoLog{}, toEngine, nil)
 	defer s.Close()
 	go s.Dispatch(startTime)
 
@@ -32,7 +32,7 @@ func TestDelayFromSetTime(t *testing.T) {
 	now := time.Now()
 	startTime := now.Add(50 * time.Millisecond)
 
-	s, fromVM := New(logging.NoLog{}, toEngine)
+	s, fromVM := New(logging.NoLog{}, toEngine, nil)
 	defer s.Close()
 	go s.Dispatch(now)
 
@@ -49,7 +49,7 @@ func TestReceipt(*testing.T) {
 	now := time.Now()
 	startTime := now.Add(50 * time.Millisecond)
 
-	s, fromVM := New(logging.NoLog{}, toEngine)
+	s, fromVM := New(logging.NoLog{}, toEngine, nil)
 	defer s.Close()
 	go s.Dispatch(now)
 
@@ -59,3 +59,29 @@ func TestReceipt(*testing.T) {
 
 	<-toEngine
 }
+
+func TestCoordinatorStaggersSchedulers(t *testing.T) {
+	require := require.New(t)
+
+	stagger := 50 * time.Millisecond
+	coordinator := NewCoordinator(stagger)
+	now := time.Now()
+
+	toEngine1 := make(chan common.Message, 10)
+	s1, fromVM1 := New(logging.NoLog{}, toEngine1, coordinator)
+	defer s1.Close()
+	go s1.Dispatch(now)
+
+	toEngine2 := make(chan common.Message, 10)
+	s2, fromVM2 := New(logging.NoLog{}, toEngine2, coordinator)
+	defer s2.Close()
+	go s2.Dispatch(now)
+
+	fromVM1 <- common.PendingTxs
+	<-toEngine1
+	notified := time.Now()
+
+	fromVM2 <- common.PendingTxs
+	<-toEngine2
+	require.GreaterOrEqual(time.Since(notified), stagger-5*time.Millisecond)
+}
@@ -76,6 +76,7 @@ func helperBuildStateSyncTestObjects(t *testing.T) (*fullVM, *VM) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	ctx := snow.DefaultContextTest()
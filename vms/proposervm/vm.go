@@ -89,6 +89,7 @@ type VM struct {
 	blockBuilderVM block.BuildBlockWithContextChainVM
 	batchedVM      block.BatchedChainVM
 	ssVM           block.StateSyncableVM
+	readinessVM    common.ReadinessVM
 
 	activationTime      time.Time
 	minimumPChainHeight uint64
@@ -98,6 +99,8 @@ type VM struct {
 	stakingLeafSigner crypto.Signer
 	// block certificate
 	stakingCertLeaf *staking.Certificate
+	// staggers block building with the other chains of the subnet, if non-nil
+	buildCoordinator *scheduler.Coordinator
 
 	state.State
 	hIndexer indexer.HeightIndexer
@@ -144,15 +147,18 @@ func New(
 	numHistoricalBlocks uint64,
 	stakingLeafSigner crypto.Signer,
 	stakingCertLeaf *staking.Certificate,
+	buildCoordinator *scheduler.Coordinator,
 ) *VM {
 	blockBuilderVM, _ := vm.(block.BuildBlockWithContextChainVM)
 	batchedVM, _ := vm.(block.BatchedChainVM)
 	ssVM, _ := vm.(block.StateSyncableVM)
+	readinessVM, _ := vm.(common.ReadinessVM)
 	return &VM{
 		ChainVM:        vm,
 		blockBuilderVM: blockBuilderVM,
 		batchedVM:      batchedVM,
 		ssVM:           ssVM,
+		readinessVM:    readinessVM,
 
 		activationTime:      activationTime,
 		minimumPChainHeight: minimumPChainHeight,
@@ -160,6 +166,7 @@ func New(
 		numHistoricalBlocks: numHistoricalBlocks,
 		stakingLeafSigner:   stakingLeafSigner,
 		stakingCertLeaf:     stakingCertLeaf,
+		buildCoordinator:    buildCoordinator,
 	}
 }
 
@@ -217,7 +224,7 @@ func (vm *VM) Initialize(
 	indexerState := state.New(indexerDB)
 	vm.hIndexer = indexer.NewHeightIndexer(vm, vm.ctx.Log, indexerState)
 
-	scheduler, vmToEngine := scheduler.New(vm.ctx.Log, toEngine)
+	scheduler, vmToEngine := scheduler.New(vm.ctx.Log, toEngine, vm.buildCoordinator)
 	vm.Scheduler = scheduler
 	vm.toScheduler = vmToEngine
 
@@ -52,6 +52,7 @@ func TestProposerVMInitializeShouldFailIfInnerVMCantVerifyItsHeightIndex(t *test
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 	defer func() {
 		// avoids leaking goroutines
@@ -140,6 +140,7 @@ func initTestProposerVM(
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	valState := &validators.TestState{
@@ -880,6 +881,7 @@ func TestExpiredBuildBlock(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	valState := &validators.TestState{
@@ -1222,6 +1224,7 @@ func TestInnerVMRollback(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	require.NoError(proVM.Initialize(
@@ -1309,6 +1312,7 @@ func TestInnerVMRollback(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	require.NoError(proVM.Initialize(
@@ -1801,6 +1805,7 @@ func TestRejectedHeightNotIndexed(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	valState := &validators.TestState{
@@ -2002,6 +2007,7 @@ func TestRejectedOptionHeightNotIndexed(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	valState := &validators.TestState{
@@ -2159,6 +2165,7 @@ func TestVMInnerBlkCache(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	innerVM.EXPECT().Initialize(
@@ -2388,6 +2395,7 @@ func TestVM_VerifyBlockWithContext(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	// make sure that DBs are compressed correctly
@@ -2599,6 +2607,7 @@ func TestHistoricalBlockDeletion(t *testing.T) {
 		DefaultNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	require.NoError(proVM.Initialize(
@@ -2697,6 +2706,7 @@ func TestHistoricalBlockDeletion(t *testing.T) {
 		numHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	require.NoError(proVM.Initialize(
@@ -2739,6 +2749,7 @@ func TestHistoricalBlockDeletion(t *testing.T) {
 		newNumHistoricalBlocks,
 		pTestSigner,
 		pTestCert,
+		nil,
 	)
 
 	require.NoError(proVM.Initialize(
@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import "context"
+
+func (vm *blockVM) ReadyToBuild(ctx context.Context) bool {
+	if vm.readinessVM == nil {
+		return true
+	}
+
+	ctx, span := vm.tracer.Start(ctx, vm.readyToBuildTag)
+	defer span.End()
+
+	return vm.readinessVM.ReadyToBuild(ctx)
+}
@@ -830,13 +830,21 @@ func (b *builder) getBalance(
 	// Iterate over the UTXOs
 	for _, utxo := range utxos {
 		outIntf := utxo.Out
-		if lockedOut, ok := outIntf.(*stakeable.LockOut); ok {
+		switch lockedOut := outIntf.(type) {
+		case *stakeable.LockOut:
 			if !options.AllowStakeableLocked() && lockedOut.Locktime > minIssuanceTime {
 				// This output is currently locked, so this output can't be
 				// burned.
 				continue
 			}
 			outIntf = lockedOut.TransferableOut
+		case *stakeable.VestingOut:
+			if !options.AllowStakeableLocked() && lockedOut.EndLocktime() > minIssuanceTime {
+				// This output hasn't fully vested, so this output can't be
+				// burned.
+				continue
+			}
+			outIntf = lockedOut.TransferableOut
 		}
 
 		out, ok := outIntf.(*secp256k1fx.TransferOutput)
@@ -909,6 +917,76 @@ func (b *builder) spend(
 		}
 
 		outIntf := utxo.Out
+		if vestingOut, ok := outIntf.(*stakeable.VestingOut); ok {
+			if minIssuanceTime >= vestingOut.EndLocktime() {
+				// This output has fully vested, so it will be handled during
+				// the next iteration of the UTXO set
+				continue
+			}
+
+			out, ok := vestingOut.TransferableOut.(*secp256k1fx.TransferOutput)
+			if !ok {
+				return nil, nil, nil, errUnknownOutputType
+			}
+
+			inputSigIndices, ok := common.MatchOwners(&out.OutputOwners, addrs, minIssuanceTime)
+			if !ok {
+				// We couldn't spend this UTXO, so we skip to the next one
+				continue
+			}
+
+			inputs = append(inputs, &avax.TransferableInput{
+				UTXOID: utxo.UTXOID,
+				Asset:  utxo.Asset,
+				In: &stakeable.VestingIn{
+					Tranches: vestingOut.Tranches,
+					TransferableIn: &secp256k1fx.TransferInput{
+						Amt: out.Amt,
+						Input: secp256k1fx.Input{
+							SigIndices: inputSigIndices,
+						},
+					},
+				},
+			})
+
+			// Stake any value that should be staked, starting with the
+			// tranches that vest last
+			lockedAmount := out.Amt - vestingOut.Unlocked(minIssuanceTime)
+			amountToStake := math.Min(
+				remainingAmountToStake, // Amount we still need to stake
+				lockedAmount,           // Amount available to stake
+			)
+			changeTranches, stakeTranches := stakeable.SplitTranches(vestingOut.Tranches, amountToStake)
+
+			// Add the output to the staked outputs
+			stakeOutputs = append(stakeOutputs, &avax.TransferableOutput{
+				Asset: utxo.Asset,
+				Out: &stakeable.VestingOut{
+					Tranches: stakeTranches,
+					TransferableOut: &secp256k1fx.TransferOutput{
+						Amt:          amountToStake,
+						OutputOwners: out.OutputOwners,
+					},
+				},
+			})
+
+			amountsToStake[assetID] -= amountToStake
+			if remainingAmount := out.Amt - amountToStake; remainingAmount > 0 {
+				// This input had extra value, so some of it must be returned
+				changeOutputs = append(changeOutputs, &avax.TransferableOutput{
+					Asset: utxo.Asset,
+					Out: &stakeable.VestingOut{
+						Tranches: changeTranches,
+						TransferableOut: &secp256k1fx.TransferOutput{
+							Amt:          remainingAmount,
+							OutputOwners: out.OutputOwners,
+						},
+					},
+				})
+			}
+			continue
+		}
+
 		lockedOut, ok := outIntf.(*stakeable.LockOut)
 		if !ok {
 			// This output isn't locked, so it will be handled during the next
@@ -993,13 +1071,21 @@ func (b *builder) spend(
 		}
 
 		outIntf := utxo.Out
-		if lockedOut, ok := outIntf.(*stakeable.LockOut); ok {
+		switch lockedOut := outIntf.(type) {
+		case *stakeable.LockOut:
 			if lockedOut.Locktime > minIssuanceTime {
 				// This output is currently locked, so this output can't be
 				// burned.
 				continue
 			}
 			outIntf = lockedOut.TransferableOut
+		case *stakeable.VestingOut:
+			if lockedOut.EndLocktime() > minIssuanceTime {
+				// This output hasn't fully vested, so this output can't be
+				// burned.
+				continue
+			}
+			outIntf = lockedOut.TransferableOut
 		}
 
 		out, ok := outIntf.(*secp256k1fx.TransferOutput)
@@ -156,6 +156,19 @@ func (s *signerVisitor) TransferSubnetOwnershipTx(tx *txs.TransferSubnetOwnershi
 	return sign(s.tx, true, txSigners)
 }
 
+func (s *signerVisitor) FreezeSubnetValidatorTx(tx *txs.FreezeSubnetValidatorTx) error {
+	txSigners, err := s.getSigners(constants.PlatformChainID, tx.Ins)
+	if err != nil {
+		return err
+	}
+	subnetAuthSigners, err := s.getSubnetSigners(tx.Subnet, tx.SubnetAuth)
+	if err != nil {
+		return err
+	}
+	txSigners = append(txSigners, subnetAuthSigners)
+	return sign(s.tx, true, txSigners)
+}
+
 func (s *signerVisitor) TransformSubnetTx(tx *txs.TransformSubnetTx) error {
 	txSigners, err := s.getSigners(constants.PlatformChainID, tx.Ins)
 	if err != nil {
@@ -189,7 +202,10 @@ func (s *signerVisitor) getSigners(sourceChainID ids.ID, ins []*avax.Transferabl
 	txSigners := make([][]keychain.Signer, len(ins))
 	for credIndex, transferInput := range ins {
 		inIntf := transferInput.In
-		if stakeableIn, ok := inIntf.(*stakeable.LockIn); ok {
+		switch stakeableIn := inIntf.(type) {
+		case *stakeable.LockIn:
+			inIntf = stakeableIn.TransferableIn
+		case *stakeable.VestingIn:
 			inIntf = stakeableIn.TransferableIn
 		}
 
@@ -213,7 +229,10 @@ func (s *signerVisitor) getSigners(sourceChainID ids.ID, ins []*avax.Transferabl
 		}
 
 		outIntf := utxo.Out
-		if stakeableOut, ok := outIntf.(*stakeable.LockOut); ok {
+		switch stakeableOut := outIntf.(type) {
+		case *stakeable.LockOut:
+			outIntf = stakeableOut.TransferableOut
+		case *stakeable.VestingOut:
 			outIntf = stakeableOut.TransferableOut
 		}
 
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+const maxArenaBatchSize = 1024
+
+// arena allocates values of type T in batches, so that allocating many values
+// with the same lifetime costs a few allocations rather than one per value.
+//
+// Values are never reused, so a value remains valid for as long as it's
+// referenced. However, a referenced value keeps the rest of its batch from
+// being garbage collected, so an arena should only be used for values that
+// are dropped at around the same time.
+//
+// An arena isn't safe for concurrent use.
+type arena[T any] struct {
+	batch         []T
+	nextBatchSize int
+}
+
+// newArena returns an arena whose first batch holds [estimatedSize] values, up
+// to [maxArenaBatchSize].
+func newArena[T any](estimatedSize int) arena[T] {
+	if estimatedSize > maxArenaBatchSize {
+		estimatedSize = maxArenaBatchSize
+	}
+	return arena[T]{
+		nextBatchSize: estimatedSize,
+	}
+}
+
+// new returns a pointer to a new zero value of type T.
+func (a *arena[T]) new() *T {
+	if len(a.batch) == 0 {
+		size := a.nextBatchSize
+		if size < 1 {
+			size = 1
+		}
+		a.batch = make([]T, size)
+		a.nextBatchSize = 2 * size
+		if a.nextBatchSize > maxArenaBatchSize {
+			a.nextBatchSize = maxArenaBatchSize
+		}
+	}
+	v := &a.batch[0]
+	a.batch = a.batch[1:]
+	return v
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArena(t *testing.T) {
+	require := require.New(t)
+
+	a := newArena[int](2)
+	values := make([]*int, 0, 3*maxArenaBatchSize)
+	for i := 0; i < cap(values); i++ {
+		v := a.new()
+		require.Zero(*v)
+		*v = i
+		values = append(values, v)
+	}
+
+	// Values are never handed out twice.
+	for i, v := range values {
+		require.Equal(i, *v)
+	}
+	require.Equal(maxArenaBatchSize, a.nextBatchSize)
+}
+
+func TestArenaEstimatedSize(t *testing.T) {
+	tests := []struct {
+		name              string
+		estimatedSize     int
+		expectedBatchSize int
+	}{
+		{
+			name:              "no estimate",
+			estimatedSize:     0,
+			expectedBatchSize: 1,
+		},
+		{
+			name:              "estimate",
+			estimatedSize:     10,
+			expectedBatchSize: 10,
+		},
+		{
+			name:              "estimate too large",
+			estimatedSize:     2 * maxArenaBatchSize,
+			expectedBatchSize: maxArenaBatchSize,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			a := newArena[int](test.estimatedSize)
+			_ = a.new()
+			require.Len(a.batch, test.expectedBatchSize-1)
+		})
+	}
+}
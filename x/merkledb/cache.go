@@ -77,6 +77,50 @@ func (c *onEvictCache[K, V]) Flush() error {
 	return c.resize(0)
 }
 
+// hasRoomFor returns true if [key] and [value] can be added to this cache
+// without evicting any element.
+func (c *onEvictCache[K, V]) hasRoomFor(key K, value V) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.currentSize+c.size(key, value) <= c.maxSize
+}
+
+// newestKeys returns the keys of up to [n] of the most recently added elements
+// for which [include] returns true, newest first.
+func (c *onEvictCache[K, V]) newestKeys(n int, include func(K, V) bool) []K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	keys := make([]K, 0, c.fifo.Len())
+	it := c.fifo.NewIterator()
+	for it.Next() {
+		if include(it.Key(), it.Value()) {
+			keys = append(keys, it.Key())
+		}
+	}
+
+	// [keys] is ordered from oldest to newest.
+	if len(keys) > n {
+		keys = keys[len(keys)-n:]
+	}
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+	return keys
+}
+
+// remove removes [key] from this cache without calling [c.onEviction].
+func (c *onEvictCache[K, V]) remove(key K) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if value, ok := c.fifo.Get(key); ok {
+		c.currentSize -= c.size(key, value)
+		c.fifo.Delete(key)
+	}
+}
+
 // removeOldest returns and removes the oldest element from this cache.
 //
 // Assumes [c.lock] is held.
@@ -223,3 +223,34 @@ func TestOnEvictCacheOnEvictionError(t *testing.T) {
 	_, ok = cache.Get(2)
 	require.False(ok)
 }
+
+func TestOnEvictCacheNewestKeys(t *testing.T) {
+	require := require.New(t)
+
+	size := func(int, int) int {
+		return 1
+	}
+	onEviction := func(int, int) error {
+		return nil
+	}
+	cache := newOnEvictCache(10, size, onEviction)
+	for i := 0; i < 5; i++ {
+		require.NoError(cache.Put(i, i))
+	}
+	// Re-adding an element marks it as the newest.
+	require.NoError(cache.Put(1, 1))
+
+	isEven := func(_ int, v int) bool {
+		return v%2 == 0
+	}
+	require.Equal([]int{1, 4, 3, 2, 0}, cache.newestKeys(10, func(int, int) bool { return true }))
+	require.Equal([]int{1, 4, 3}, cache.newestKeys(3, func(int, int) bool { return true }))
+	require.Equal([]int{4, 2, 0}, cache.newestKeys(10, isEven))
+	require.Empty(cache.newestKeys(0, isEven))
+
+	require.True(cache.hasRoomFor(5, 5))
+	for i := 5; i < 10; i++ {
+		require.NoError(cache.Put(i, i))
+	}
+	require.False(cache.hasRoomFor(10, 10))
+}
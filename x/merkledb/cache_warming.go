@@ -0,0 +1,210 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var cacheWarmingManifestKey = []byte(string(metadataPrefix) + "cacheWarmingManifest")
+
+// writeCacheWarmingManifest saves the keys of the most recently read
+// intermediate nodes so that they can be loaded into the read cache on the
+// next startup.
+func (db *merkleDB) writeCacheWarmingManifest() error {
+	if db.cacheWarmingSize == 0 {
+		return nil
+	}
+
+	keys := db.intermediateNodeDB.readCache.newestKeys(
+		db.cacheWarmingSize,
+		func(Key, *node) bool {
+			return true
+		},
+	)
+	manifest := &bytes.Buffer{}
+	for _, key := range keys {
+		codec.encodeKey(manifest, key)
+	}
+	return db.baseDB.Put(cacheWarmingManifestKey, manifest.Bytes())
+}
+
+// loadCacheWarmingManifest returns the keys saved by the last
+// [writeCacheWarmingManifest] and deletes them, so that they aren't used again
+// after a later unclean shutdown.
+func (db *merkleDB) loadCacheWarmingManifest() ([]Key, error) {
+	manifest, err := db.baseDB.Get(cacheWarmingManifestKey)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.baseDB.Delete(cacheWarmingManifestKey); err != nil {
+		return nil, err
+	}
+
+	var (
+		src  = bytes.NewReader(manifest)
+		keys []Key
+	)
+	for src.Len() > 0 {
+		key, err := codec.decodeKey(src, db.rootKey.branchFactor)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// warmCache loads the top levels of the trie into the cache if
+// [db.prewarmCacheOnOpen], and then the intermediate nodes with [keys], in
+// order, until they have all been loaded, the cache is full, or [ctx] is
+// cancelled.
+// If [limiter] is non-nil, it limits the rate at which nodes are read.
+//
+// Closes [db.cacheWarmingDone] when it returns.
+func (db *merkleDB) warmCache(ctx context.Context, keys []Key, limiter *rate.Limiter) {
+	defer close(db.cacheWarmingDone)
+
+	if db.prewarmCacheOnOpen && !db.prewarmCache(ctx, limiter) {
+		return
+	}
+
+	for _, key := range keys {
+		if !waitToWarm(ctx, limiter) {
+			return
+		}
+
+		if _, done, err := db.warmNode(key, false /*=hasValue*/); done || err != nil {
+			// Warming is best effort, so errors stop it without being
+			// reported. Any error will resurface when the node is read
+			// normally.
+			return
+		}
+	}
+}
+
+// prewarmCache loads the top [db.prewarmCacheDepth] levels of the trie below
+// the root into the cache, one level at a time, so that the nodes read by
+// almost every operation are cached first.
+// Returns false if warming should stop because the cache is full, [ctx] was
+// cancelled or a node couldn't be read.
+func (db *merkleDB) prewarmCache(ctx context.Context, limiter *rate.Limiter) bool {
+	type nodeRef struct {
+		key      Key
+		hasValue bool
+	}
+	var level []nodeRef
+	appendChildren := func(n *node) {
+		for index, entry := range n.children {
+			level = append(level, nodeRef{
+				key:      n.key.AppendExtend(index, entry.compressedKey),
+				hasValue: entry.hasValue,
+			})
+		}
+	}
+
+	db.commitLock.RLock()
+	appendChildren(db.root)
+	db.commitLock.RUnlock()
+
+	for depth := 1; len(level) > 0 && (db.prewarmCacheDepth == 0 || depth <= db.prewarmCacheDepth); depth++ {
+		refs := level
+		level = nil
+		for _, ref := range refs {
+			if !waitToWarm(ctx, limiter) {
+				return false
+			}
+
+			n, done, err := db.warmNode(ref.key, ref.hasValue)
+			if done || err != nil {
+				return false
+			}
+			if n != nil {
+				appendChildren(n)
+			}
+		}
+		db.metrics.SetCachePrewarmedDepth(depth)
+	}
+	return true
+}
+
+// waitToWarm waits until [limiter] allows another node to be read. Returns
+// false if [ctx] is cancelled.
+func waitToWarm(ctx context.Context, limiter *rate.Limiter) bool {
+	if limiter != nil {
+		return limiter.Wait(ctx) == nil
+	}
+	return ctx.Err() == nil
+}
+
+// warmNode loads the node with [key] into the read cache and returns it.
+// Returns nil if the node was removed, and true if the read cache of
+// intermediate nodes is full.
+func (db *merkleDB) warmNode(key Key, hasValue bool) (*node, bool, error) {
+	// Hold [commitLock] so that a commit can't replace the node in the cache
+	// between reading it from disk and caching it.
+	db.commitLock.RLock()
+	defer db.commitLock.RUnlock()
+
+	if db.closed {
+		return nil, false, database.ErrClosed
+	}
+
+	if hasValue {
+		return db.warmValueNode(key)
+	}
+
+	if n, ok := db.intermediateNodeDB.getCached(key); ok {
+		return n, false, nil
+	}
+	n, err := db.intermediateNodeDB.read(key)
+	if err == database.ErrNotFound {
+		// The node was removed since its key was found.
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Don't evict nodes to make room for colder ones.
+	if !db.intermediateNodeDB.readCache.hasRoomFor(key, n) {
+		return nil, true, nil
+	}
+	db.metrics.CacheNodePrewarmed()
+	return n, false, db.intermediateNodeDB.readCache.Put(key, n)
+}
+
+// warmValueNode loads the value node with [key] into the cache, unless the
+// cache is full, and returns it.
+//
+// Assumes [db.commitLock] is read locked.
+func (db *merkleDB) warmValueNode(key Key) (*node, bool, error) {
+	if n, ok := db.valueNodeDB.nodeCache.Get(key); ok {
+		return n, false, nil
+	}
+	n, err := db.valueNodeDB.Get(key)
+	if err == database.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Value nodes are cached in an LRU, which would evict nodes to make room
+	// for colder ones. Its children may still fit in the cache of
+	// intermediate nodes, so warming continues.
+	if db.valueNodeDB.nodeCache.PortionFilled() < 1 {
+		db.valueNodeDB.nodeCache.Put(key, n)
+		db.metrics.CacheNodePrewarmed()
+	}
+	return n, false, nil
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newCacheWarmingDB(t *testing.T, baseDB database.Database, cacheWarmingSize uint, nodesPerSecond uint) *merkleDB {
+	config := newDefaultConfig()
+	config.CacheWarmingSize = cacheWarmingSize
+	config.CacheWarmingNodesPerSecond = nodesPerSecond
+	db, err := newDatabase(
+		context.Background(),
+		baseDB,
+		config,
+		&mockMetrics{},
+	)
+	require.NoError(t, err)
+	return db
+}
+
+func putCacheWarmingKeys(t *testing.T, db *merkleDB) {
+	for i := 0; i < 1_000; i++ {
+		key := []byte(strconv.Itoa(i))
+		require.NoError(t, db.Put(key, key))
+	}
+}
+
+func getCacheWarmingKeys(t *testing.T, db *merkleDB) {
+	for i := 0; i < 1_000; i += 10 {
+		key := []byte(strconv.Itoa(i))
+		_, err := db.GetValue(context.Background(), key)
+		require.NoError(t, err)
+	}
+}
+
+func Test_MerkleDB_CacheWarming(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 100, 0)
+	putCacheWarmingKeys(t, db)
+	require.NoError(db.Close())
+
+	// The manifest records the nodes that were read, not those that were
+	// written.
+	db = newCacheWarmingDB(t, baseDB, 100, 0)
+	<-db.cacheWarmingDone
+	getCacheWarmingKeys(t, db)
+	expectedKeys := db.intermediateNodeDB.readCache.newestKeys(100, func(Key, *node) bool {
+		return true
+	})
+	require.NotEmpty(expectedKeys)
+	require.NoError(db.Close())
+
+	has, err := baseDB.Has(cacheWarmingManifestKey)
+	require.NoError(err)
+	require.True(has)
+
+	db = newCacheWarmingDB(t, baseDB, 100, 0)
+	<-db.cacheWarmingDone
+
+	// The manifest is only used once.
+	has, err = baseDB.Has(cacheWarmingManifestKey)
+	require.NoError(err)
+	require.False(has)
+
+	// Only the nodes in the manifest are cached, and they aren't buffered to
+	// be written back to disk.
+	require.ElementsMatch(
+		expectedKeys,
+		db.intermediateNodeDB.readCache.newestKeys(len(expectedKeys)+1, func(Key, *node) bool {
+			return true
+		}),
+	)
+	require.Zero(db.intermediateNodeDB.nodeCache.fifo.Len())
+	for _, key := range expectedKeys {
+		n, ok := db.intermediateNodeDB.readCache.Get(key)
+		require.True(ok)
+		require.NotNil(n)
+	}
+	require.NoError(db.Close())
+}
+
+func Test_IntermediateNodeDB_ReadCache(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 0, 0)
+	putCacheWarmingKeys(t, db)
+	require.NoError(db.Close())
+
+	db = newCacheWarmingDB(t, baseDB, 0, 0)
+	getCacheWarmingKeys(t, db)
+	keys := db.intermediateNodeDB.readCache.newestKeys(1, func(Key, *node) bool {
+		return true
+	})
+	require.Len(keys, 1)
+	key := keys[0]
+
+	// A changed node is removed from the read cache.
+	n, err := db.intermediateNodeDB.Get(key)
+	require.NoError(err)
+	require.NoError(db.intermediateNodeDB.Put(key, n.clone()))
+	_, ok := db.intermediateNodeDB.readCache.Get(key)
+	require.False(ok)
+
+	// Once the change is written, the node is cached again when it's read.
+	require.NoError(db.intermediateNodeDB.Flush())
+	_, err = db.intermediateNodeDB.Get(key)
+	require.NoError(err)
+	_, ok = db.intermediateNodeDB.readCache.Get(key)
+	require.True(ok)
+	require.NoError(db.Close())
+}
+
+func Test_MerkleDB_CacheWarming_Disabled(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 0, 0)
+	putCacheWarmingKeys(t, db)
+	require.NoError(db.Close())
+
+	has, err := baseDB.Has(cacheWarmingManifestKey)
+	require.NoError(err)
+	require.False(has)
+}
+
+func Test_MerkleDB_CacheWarming_UncleanShutdown(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 100, 0)
+	putCacheWarmingKeys(t, db)
+	require.NoError(db.Close())
+
+	// Pretend that the database was opened again and then wasn't closed
+	// cleanly.
+	require.NoError(baseDB.Put(cleanShutdownKey, didNotHaveCleanShutdown))
+
+	db = newCacheWarmingDB(t, baseDB, 100, 0)
+	<-db.cacheWarmingDone
+
+	has, err := baseDB.Has(cacheWarmingManifestKey)
+	require.NoError(err)
+	require.False(has)
+	require.NoError(db.Close())
+}
+
+func Test_MerkleDB_CacheWarming_Close(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 100, 0)
+	putCacheWarmingKeys(t, db)
+	require.NoError(db.Close())
+
+	// Warming a node per second would take far longer than the test.
+	db = newCacheWarmingDB(t, baseDB, 100, 1)
+	require.NoError(db.Close())
+	<-db.cacheWarmingDone
+}
+
+func Test_MerkleDB_PrewarmCacheOnOpen(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 0, 0)
+	putCacheWarmingKeys(t, db)
+	require.NoError(db.Close())
+
+	config := newDefaultConfig()
+	config.PrewarmCacheOnOpen = true
+	config.PrewarmCacheDepth = 1
+	metrics := &mockMetrics{}
+	db, err := newDatabase(context.Background(), baseDB, config, metrics)
+	require.NoError(err)
+	<-db.cacheWarmingDone
+
+	// Every node in the top level below the root is cached, and no deeper
+	// node is.
+	var (
+		level       = []*node{db.root}
+		numNodes    int64
+		numUncached int
+	)
+	for depth := 1; depth <= 2; depth++ {
+		var next []*node
+		for _, n := range level {
+			for index, entry := range n.children {
+				key := n.key.AppendExtend(index, entry.compressedKey)
+				var (
+					child *node
+					ok    bool
+				)
+				if entry.hasValue {
+					child, ok = db.valueNodeDB.nodeCache.Get(key)
+				} else {
+					child, ok = db.intermediateNodeDB.readCache.Get(key)
+				}
+				if depth == 2 {
+					require.False(ok)
+					numUncached++
+					continue
+				}
+				require.True(ok)
+				next = append(next, child)
+				numNodes++
+			}
+		}
+		level = next
+	}
+	require.NotZero(numUncached)
+	require.Equal(numNodes, metrics.cacheNodesPrewarmed)
+	require.Equal(1, metrics.cachePrewarmedDepth)
+	require.NoError(db.Close())
+}
+
+func Test_MerkleDB_PrewarmCacheOnOpen_Unbounded(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newCacheWarmingDB(t, baseDB, 0, 0)
+	putCacheWarmingKeys(t, db)
+	stats, err := db.Stats(context.Background())
+	require.NoError(err)
+	require.NoError(db.Close())
+
+	config := newDefaultConfig()
+	config.PrewarmCacheOnOpen = true
+	metrics := &mockMetrics{}
+	db, err = newDatabase(context.Background(), baseDB, config, metrics)
+	require.NoError(err)
+	<-db.cacheWarmingDone
+
+	// The caches are large enough for every node but the root, which is
+	// never cached.
+	require.Equal(int64(stats.NodeCount-1), metrics.cacheNodesPrewarmed)
+	require.Equal(len(stats.DepthHistogram)-1, metrics.cachePrewarmedDepth)
+	require.NoError(db.Close())
+}
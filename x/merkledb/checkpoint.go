@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var (
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+	errRollbackRootMismatch = errors.New("rollback resulted in an unexpected root")
+)
+
+// checkpoint is a root that the database can be rolled back to.
+type checkpoint struct {
+	rootID ids.ID
+	// The insert number that the first change committed after the checkpoint
+	// was taken is recorded with in [merkleDB.history]. The changes with
+	// insert numbers >= it are retained until the checkpoint is released.
+	insertNumber uint64
+}
+
+func (db *merkleDB) Checkpoint(name string) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	// Snapshots read [db.history] while only holding [db.lock].
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if existing, ok := db.checkpoints[name]; ok {
+		db.history.removeSnapshot(existing.insertNumber)
+	}
+	db.checkpoints[name] = checkpoint{
+		rootID:       db.getMerkleRoot(),
+		insertNumber: db.history.addSnapshot(),
+	}
+	return nil
+}
+
+func (db *merkleDB) RollbackTo(name string) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	db.lock.RLock()
+	if db.closed {
+		db.lock.RUnlock()
+		return database.ErrClosed
+	}
+	cp, ok := db.checkpoints[name]
+	if !ok {
+		db.lock.RUnlock()
+		return fmt.Errorf("%w: %q", ErrCheckpointNotFound, name)
+	}
+	values, err := db.history.getValuesBefore(cp.insertNumber)
+	db.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Restoring the values of the checkpoint results in its root, since the
+	// root only depends on the key-value pairs. Committing them as a new
+	// change, rather than reverting the node changes, keeps the history and
+	// the change proofs that span the rollback correct.
+	ops := make(map[string]maybe.Maybe[[]byte], len(values))
+	for key, value := range values {
+		ops[string(key.Bytes())] = value
+	}
+	view, err := newTrieView(db, db, ViewChanges{MapOps: ops})
+	if err != nil {
+		return err
+	}
+	if err := view.commitToDB(context.Background()); err != nil {
+		return err
+	}
+
+	db.lock.RLock()
+	rootID := db.getMerkleRoot()
+	db.lock.RUnlock()
+	if rootID != cp.rootID {
+		return fmt.Errorf("%w: expected %s but got %s", errRollbackRootMismatch, cp.rootID, rootID)
+	}
+	return nil
+}
+
+func (db *merkleDB) ReleaseCheckpoint(name string) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	cp, ok := db.checkpoints[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrCheckpointNotFound, name)
+	}
+	delete(db.checkpoints, name)
+	db.history.removeSnapshot(cp.insertNumber)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func Test_MerkleDB_Checkpoint(t *testing.T) {
+	for _, historyLength := range []uint{0, 2} {
+		t.Run(strconv.Itoa(int(historyLength)), func(t *testing.T) {
+			require := require.New(t)
+
+			config := newDefaultConfig()
+			config.HistoryLength = historyLength
+			db, err := newDB(context.Background(), memdb.New(), config)
+			require.NoError(err)
+
+			writeBasicBatch(t, db)
+			checkpointRoot, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.NoError(db.Checkpoint("a"))
+
+			// More changes are committed than the history length.
+			experiment := func() {
+				require.NoError(db.Put([]byte{0}, []byte{10}))
+				require.NoError(db.Delete([]byte{1}))
+				require.NoError(db.Put([]byte{5}, []byte{5}))
+				require.NoError(db.Put([]byte{0}, []byte{20}))
+			}
+			experiment()
+
+			view, err := db.NewView(context.Background(), ViewChanges{})
+			require.NoError(err)
+
+			require.NoError(db.RollbackTo("a"))
+			root, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(checkpointRoot, root)
+			require.True(view.(*trieView).isInvalid())
+
+			for i := byte(0); i < 5; i++ {
+				value, err := db.Get([]byte{i})
+				require.NoError(err)
+				require.Equal([]byte{i}, value)
+			}
+			_, err = db.Get([]byte{5})
+			require.ErrorIs(err, database.ErrNotFound)
+
+			// Rolling back to the current root is a no-op, and the checkpoint
+			// can be rolled back to again.
+			require.NoError(db.RollbackTo("a"))
+			experiment()
+			require.NoError(db.RollbackTo("a"))
+			root, err = db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(checkpointRoot, root)
+
+			require.ErrorIs(db.RollbackTo("b"), ErrCheckpointNotFound)
+			require.NoError(db.ReleaseCheckpoint("a"))
+			require.ErrorIs(db.ReleaseCheckpoint("a"), ErrCheckpointNotFound)
+			require.Empty(db.history.snapshots)
+
+			// Once released, the changes retained by the checkpoint are
+			// removed by the next commit.
+			require.NoError(db.Put([]byte{6}, []byte{6}))
+			require.LessOrEqual(db.history.history.Len(), int(historyLength))
+		})
+	}
+}
+
+func Test_MerkleDB_Checkpoint_Replace(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	require.NoError(db.Checkpoint("a"))
+	writeBasicBatch(t, db)
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// Checkpointing with the same name replaces the checkpoint.
+	require.NoError(db.Checkpoint("a"))
+	require.Len(db.history.snapshots, 1)
+
+	require.NoError(db.Put([]byte{5}, []byte{5}))
+	require.NoError(db.RollbackTo("a"))
+	rolledBackRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(root, rolledBackRoot)
+
+	require.NoError(db.ReleaseCheckpoint("a"))
+	require.Empty(db.history.snapshots)
+}
@@ -64,12 +64,21 @@ type encoder interface {
 	// Assumes [n] is non-nil.
 	encodeDBNode(n *dbNode) []byte
 	// Assumes [hv] is non-nil.
-	encodeHashValues(hv *hashValues) []byte
+	encodeHashValues(dst *bytes.Buffer, hv *hashValues)
+	encodeKey(dst *bytes.Buffer, key Key)
+	encodeUint(dst *bytes.Buffer, value uint64)
+	encodeByteSlice(dst *bytes.Buffer, value []byte)
+	encodeMaybeByteSlice(dst *bytes.Buffer, maybeValue maybe.Maybe[[]byte])
 }
 
 type decoder interface {
 	// Assumes [n] is non-nil.
 	decodeDBNode(bytes []byte, n *dbNode, factor BranchFactor) error
+	decodeKey(src *bytes.Reader, branchFactor BranchFactor) (Key, error)
+	decodeUint(src *bytes.Reader) (uint64, error)
+	decodeByteSlice(src *bytes.Reader) ([]byte, error)
+	decodeMaybeByteSlice(src *bytes.Reader) (maybe.Maybe[[]byte], error)
+	decodeID(src *bytes.Reader) (ids.ID, error)
 }
 
 func newCodec() encoderDecoder {
@@ -114,14 +123,14 @@ func (c *codecImpl) encodeDBNode(n *dbNode) []byte {
 	return buf.Bytes()
 }
 
-func (c *codecImpl) encodeHashValues(hv *hashValues) []byte {
+func (c *codecImpl) encodeHashValues(buf *bytes.Buffer, hv *hashValues) {
 	var (
 		numChildren = len(hv.Children)
 		// Estimate size [hv] to prevent memory allocations
 		estimatedLen = minVarIntLen + numChildren*hashValuesChildLen + estimatedValueLen + estimatedKeyLen
-		buf          = bytes.NewBuffer(make([]byte, 0, estimatedLen))
 	)
 
+	buf.Grow(estimatedLen)
 	c.encodeUint(buf, uint64(numChildren))
 
 	// ensure that the order of entries is consistent
@@ -133,8 +142,6 @@ func (c *codecImpl) encodeHashValues(hv *hashValues) []byte {
 	}
 	c.encodeMaybeByteSlice(buf, hv.Value)
 	c.encodeKey(buf, hv.Key)
-
-	return buf.Bytes()
 }
 
 func (c *codecImpl) decodeDBNode(b []byte, n *dbNode, branchFactor BranchFactor) error {
@@ -160,7 +167,7 @@ func (c *codecImpl) decodeDBNode(b []byte, n *dbNode, branchFactor BranchFactor)
 		return io.ErrUnexpectedEOF
 	}
 
-	n.children = make(map[byte]child, branchFactor)
+	n.children = make(map[byte]child, numChildren)
 	var previousChild uint64
 	for i := uint64(0); i < numChildren; i++ {
 		index, err := c.decodeUint(src)
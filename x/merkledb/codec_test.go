@@ -254,11 +254,13 @@ func FuzzEncodeHashValues(f *testing.F) {
 				}
 
 				// Serialize the *hashValues with both codecs
-				hvBytes1 := codec1.encodeHashValues(hv)
-				hvBytes2 := codec2.encodeHashValues(hv)
+				hvBytes1 := &bytes.Buffer{}
+				codec1.encodeHashValues(hvBytes1, hv)
+				hvBytes2 := &bytes.Buffer{}
+				codec2.encodeHashValues(hvBytes2, hv)
 
 				// Make sure they're the same
-				require.Equal(hvBytes1, hvBytes2)
+				require.Equal(hvBytes1.Bytes(), hvBytes2.Bytes())
 			}
 		},
 	)
@@ -270,3 +272,40 @@ func TestCodecDecodeKeyLengthOverflowRegression(t *testing.T) {
 	_, err := codec.decodeKey(bytes, BranchFactor16)
 	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
 }
+
+func TestEncodeHashValuesChildOrder(t *testing.T) {
+	require := require.New(t)
+
+	childIDs := []ids.ID{{1}, {2}, {3}}
+	indices := []byte{2, 7, 15}
+	key := ToKey([]byte{1}, BranchFactor16)
+
+	// Insert the same children in increasing and decreasing index order.
+	ascending := map[byte]child{}
+	for i, index := range indices {
+		ascending[index] = child{id: childIDs[i]}
+	}
+	descending := map[byte]child{}
+	for i := len(indices) - 1; i >= 0; i-- {
+		descending[indices[i]] = child{id: childIDs[i]}
+	}
+
+	// The children are encoded in increasing index order.
+	expected := &bytes.Buffer{}
+	codec.(*codecImpl).encodeUint(expected, uint64(len(indices)))
+	for i, index := range indices {
+		codec.(*codecImpl).encodeUint(expected, uint64(index))
+		_, _ = expected.Write(childIDs[i][:])
+	}
+	codec.(*codecImpl).encodeMaybeByteSlice(expected, maybe.Nothing[[]byte]())
+	codec.(*codecImpl).encodeKey(expected, key)
+
+	for _, children := range []map[byte]child{ascending, descending} {
+		buf := &bytes.Buffer{}
+		codec.encodeHashValues(buf, &hashValues{
+			Children: children,
+			Key:      key,
+		})
+		require.Equal(expected.Bytes(), buf.Bytes())
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+// CommitListener is called with the root and the key-value changes of a
+// commit. See [CommitNotifier].
+type CommitListener func(root ids.ID, changes []KeyChange)
+
+func (db *merkleDB) RegisterCommitListener(listener CommitListener) {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	db.commitListeners = append(db.commitListeners, listener)
+}
+
+// getCommitListenerChanges returns the key-value changes in [changes] that
+// the commit listeners are called with, in increasing key order. Returns nil
+// if there are no commit listeners.
+// Assumes [db.commitLock] is held.
+func (db *merkleDB) getCommitListenerChanges(changes *changeSummary) ([]KeyChange, error) {
+	if len(db.commitListeners) == 0 {
+		return nil, nil
+	}
+
+	keyChanges := make([]KeyChange, 0, changes.numValueChanges())
+	err := changes.forEachValueChange(func(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
+		// Deleting a missing key or putting a key's current value is recorded
+		// as a change, but doesn't change the key.
+		if maybe.Equal(valueChange.before, valueChange.after, bytes.Equal) {
+			return nil
+		}
+		keyChanges = append(keyChanges, KeyChange{
+			Key:   key.Bytes(),
+			Value: valueChange.after,
+		})
+		return nil
+	})
+	slices.SortFunc(keyChanges, func(a, b KeyChange) bool {
+		return bytes.Compare(a.Key, b.Key) == -1
+	})
+	return keyChanges, err
+}
+
+// notifyCommitListeners calls the commit listeners with [keyChanges], which
+// were just committed and resulted in [rootID].
+// Assumes [db.commitLock] is held.
+func (db *merkleDB) notifyCommitListeners(rootID ids.ID, keyChanges []KeyChange) {
+	if len(keyChanges) == 0 {
+		return
+	}
+
+	for _, listener := range db.commitListeners {
+		listener(rootID, keyChanges)
+	}
+}
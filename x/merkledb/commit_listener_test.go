@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+type committed struct {
+	root    ids.ID
+	changes []KeyChange
+}
+
+func TestCommitListener(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	require.NoError(db.Put([]byte{0}, []byte{0}))
+
+	var (
+		first  []committed
+		second []committed
+	)
+	db.RegisterCommitListener(func(root ids.ID, changes []KeyChange) {
+		// The commit is readable by the listener.
+		dbRoot, err := db.GetMerkleRoot(context.Background())
+		require.NoError(err)
+		require.Equal(root, dbRoot)
+
+		first = append(first, committed{root: root, changes: changes})
+	})
+	db.RegisterCommitListener(func(root ids.ID, changes []KeyChange) {
+		// Listeners are called in the order they were registered.
+		require.Len(first, len(second)+1)
+
+		second = append(second, committed{root: root, changes: changes})
+	})
+
+	require.NoError(db.Put([]byte{2}, []byte{2}))
+	root1, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{3}, Value: []byte{3}},
+			{Key: []byte{1}, Value: []byte{1}},
+			{Key: []byte{0}, Delete: true},
+		},
+	})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+	root2, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// Commits that don't change any keys aren't reported.
+	require.NoError(db.Delete([]byte{4}))
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+
+	expected := []committed{
+		{
+			root: root1,
+			changes: []KeyChange{
+				{Key: []byte{2}, Value: maybe.Some([]byte{2})},
+			},
+		},
+		{
+			root: root2,
+			changes: []KeyChange{
+				{Key: []byte{0}, Value: maybe.Nothing[[]byte]()},
+				{Key: []byte{1}, Value: maybe.Some([]byte{1})},
+				{Key: []byte{3}, Value: maybe.Some([]byte{3})},
+			},
+		},
+	}
+	require.Equal(expected, first)
+	require.Equal(expected, second)
+}
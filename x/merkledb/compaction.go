@@ -0,0 +1,280 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+// Value nodes are grouped into compaction ranges by the first byte of their
+// key. The value node of the empty key is in the first range.
+const numCompactionRanges = 256
+
+var compactionKey = []byte(string(metadataPrefix) + "compaction")
+
+type compactionRange struct {
+	// The size on disk of the value nodes in the range.
+	liveBytes uint64
+	// The size on disk of the value nodes in the range that were deleted or
+	// overwritten since the range was last compacted.
+	garbageBytes uint64
+	// True iff the range is waiting to be compacted, or being compacted.
+	pending bool
+}
+
+// compactionTracker tracks the garbage in the value nodes written to disk, so
+// that ranges with a large fraction of garbage can be compacted to reclaim
+// its space.
+type compactionTracker struct {
+	// See [Config.CompactionGarbageRatio].
+	garbageRatio float64
+	// See [Config.CompactionMinGarbageBytes].
+	minGarbageBytes uint64
+
+	ranges [numCompactionRanges]compactionRange
+	// The sum of the garbage bytes of the ranges.
+	garbageBytes uint64
+}
+
+// Returns nil if value nodes shouldn't be compacted automatically.
+func newCompactionTracker(garbageRatio float64, minGarbageBytes uint64) *compactionTracker {
+	if garbageRatio == 0 {
+		return nil
+	}
+	return &compactionTracker{
+		garbageRatio:    garbageRatio,
+		minGarbageBytes: minGarbageBytes,
+	}
+}
+
+func compactionRangeOf(key Key) byte {
+	if len(key.value) == 0 {
+		return 0
+	}
+	return key.value[0]
+}
+
+// valueNodeRange returns the range of keys in [merkleDB.baseDB] that the value
+// nodes of compaction range [r] are stored under.
+func valueNodeRange(r byte) ([]byte, []byte) {
+	start := []byte{valueNodePrefix[0], r}
+	if r == 0 {
+		// Include the value node of the empty key.
+		start = valueNodePrefix
+	}
+	limit := []byte{valueNodePrefix[0], r + 1}
+	if r == numCompactionRanges-1 {
+		limit = []byte{valueNodePrefix[0] + 1}
+	}
+	return start, limit
+}
+
+// encodedSize returns the size on disk of value node [n], as counted by
+// [merkleDB.initCompactionTracker]: the length of its prefixed key plus the
+// length of its encoded, and possibly compressed, bytes.
+func (db *valueNodeDB) encodedSize(n *node) uint64 {
+	nodeBytes, err := db.encode(n)
+	if err != nil {
+		// [n] was already encoded when it was written, so this doesn't
+		// happen. The uncompressed length is a fine estimate if it does.
+		nodeBytes = valueNodeBytes(n)
+	}
+	return uint64(len(valueNodePrefix) + len(n.key.Bytes()) + len(nodeBytes))
+}
+
+// record updates the tracker with the node changes of a commit. [size]
+// returns the size on disk of a value node.
+// Returns the ranges that should now be compacted. They're marked as pending
+// until [compacted] is called with them.
+func (t *compactionTracker) record(changes *changeSummary, size func(*node) uint64) []byte {
+	var toCompact []byte
+	for key, nodeChange := range changes.nodes {
+		r := &t.ranges[compactionRangeOf(key)]
+		if nodeChange.before != nil && nodeChange.before.hasValue() {
+			beforeSize := size(nodeChange.before)
+			// The live bytes counted before a restart may not include every
+			// value node written since, so they can be less than [beforeSize].
+			if beforeSize > r.liveBytes {
+				r.liveBytes = 0
+			} else {
+				r.liveBytes -= beforeSize
+			}
+			r.garbageBytes += beforeSize
+			t.garbageBytes += beforeSize
+		}
+		if nodeChange.after != nil && nodeChange.after.hasValue() {
+			r.liveBytes += size(nodeChange.after)
+		}
+	}
+	for i := range t.ranges {
+		r := &t.ranges[i]
+		if r.pending || r.garbageBytes == 0 || r.garbageBytes < t.minGarbageBytes {
+			continue
+		}
+		if float64(r.garbageBytes)/float64(r.garbageBytes+r.liveBytes) < t.garbageRatio {
+			continue
+		}
+		r.pending = true
+		toCompact = append(toCompact, byte(i))
+	}
+	return toCompact
+}
+
+// compacted records that compaction range [r] was compacted. [garbageBytes]
+// is the garbage of the range when its compaction started. Garbage created
+// since then may not have been reclaimed, so it's still tracked.
+func (t *compactionTracker) compacted(r byte, garbageBytes uint64) {
+	t.ranges[r].garbageBytes -= garbageBytes
+	t.ranges[r].pending = false
+	t.garbageBytes -= garbageBytes
+}
+
+// reset forgets every value node, for when they're about to be rewritten.
+func (t *compactionTracker) reset() {
+	t.ranges = [numCompactionRanges]compactionRange{}
+	t.garbageBytes = 0
+}
+
+func (t *compactionTracker) bytes() []byte {
+	b := &bytes.Buffer{}
+	for _, r := range t.ranges {
+		codec.encodeUint(b, r.liveBytes)
+		codec.encodeUint(b, r.garbageBytes)
+	}
+	return b.Bytes()
+}
+
+func (t *compactionTracker) parse(b []byte) error {
+	src := bytes.NewReader(b)
+	t.reset()
+	for i := range t.ranges {
+		r := &t.ranges[i]
+		var err error
+		if r.liveBytes, err = codec.decodeUint(src); err != nil {
+			return err
+		}
+		if r.garbageBytes, err = codec.decodeUint(src); err != nil {
+			return err
+		}
+		t.garbageBytes += r.garbageBytes
+	}
+	if src.Len() != 0 {
+		return errExtraSpace
+	}
+	return nil
+}
+
+// writeCompactionTracker saves the garbage of each compaction range so that
+// it can be compacted after a restart.
+//
+// Must be called once no more value nodes will be written.
+func (db *merkleDB) writeCompactionTracker() error {
+	if db.compaction == nil {
+		return nil
+	}
+	return db.baseDB.Put(compactionKey, db.compaction.bytes())
+}
+
+// loadCompactionTracker restores [db.compaction] from what was saved by the
+// last [writeCompactionTracker] and deletes it, so that it isn't used again
+// after a later unclean shutdown.
+// Returns false if nothing was saved.
+func (db *merkleDB) loadCompactionTracker() (bool, error) {
+	trackerBytes, err := db.baseDB.Get(compactionKey)
+	if err == database.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := db.baseDB.Delete(compactionKey); err != nil {
+		return false, err
+	}
+	if db.compaction == nil {
+		return false, nil
+	}
+	return true, db.compaction.parse(trackerBytes)
+}
+
+// initCompactionTracker counts the live bytes of each range of
+// [db.compaction] from disk, unless they were [loaded] by
+// [loadCompactionTracker]. The garbage left by deletes before the restart
+// isn't tracked then.
+//
+// Must be called before the database is used.
+func (db *merkleDB) initCompactionTracker(loaded bool) error {
+	if db.compaction == nil || loaded {
+		return nil
+	}
+
+	db.compaction.reset()
+	it := db.baseDB.NewIteratorWithPrefix(valueNodePrefix)
+	defer it.Release()
+
+	for it.Next() {
+		var (
+			key = it.Key()
+			r   byte
+		)
+		if len(key) > valueNodePrefixLen {
+			r = key[valueNodePrefixLen]
+		}
+		db.compaction.ranges[r].liveBytes += uint64(len(key) + len(it.Value()))
+	}
+	return it.Error()
+}
+
+// compactValueNodes compacts the ranges sent on [db.compactionRequests] until
+// [ctx] is cancelled.
+//
+// Closes [db.compactionDone] when it returns.
+func (db *merkleDB) compactValueNodes(ctx context.Context) {
+	defer close(db.compactionDone)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-db.compactionRequests:
+			db.lock.RLock()
+			garbageBytes := db.compaction.ranges[r].garbageBytes
+			db.lock.RUnlock()
+
+			start, limit := valueNodeRange(r)
+			err := db.baseDB.Compact(start, limit)
+
+			db.lock.Lock()
+			if err == nil {
+				db.compaction.compacted(r, garbageBytes)
+				db.metrics.ValueNodesCompacted()
+			} else {
+				// Compaction is best effort. The range is retried once a
+				// later commit deletes more of it.
+				db.compaction.compacted(r, 0)
+				db.metrics.ValueNodeCompactionFailed()
+			}
+			db.metrics.SetValueNodeGarbage(db.compaction.garbageBytes)
+			db.lock.Unlock()
+		}
+	}
+}
+
+// recordGarbage updates [db.compaction] with the node changes of a commit, and
+// requests the compaction of the ranges that should now be compacted.
+//
+// Assumes [db.lock] is held.
+func (db *merkleDB) recordGarbage(changes *changeSummary) {
+	if db.compaction == nil {
+		return
+	}
+	for _, r := range db.compaction.record(changes, db.valueNodeDB.encodedSize) {
+		// There is at most one pending request per range, so this never
+		// blocks.
+		db.compactionRequests <- r
+	}
+	db.metrics.SetValueNodeGarbage(db.compaction.garbageBytes)
+}
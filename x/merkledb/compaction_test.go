@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+// compactionRecorder reports the ranges compacted in its database.
+type compactionRecorder struct {
+	database.Database
+	compacted chan [2][]byte
+}
+
+func (c *compactionRecorder) Compact(start []byte, limit []byte) error {
+	c.compacted <- [2][]byte{start, limit}
+	return c.Database.Compact(start, limit)
+}
+
+func Test_MerkleDB_CompactValueNodes(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := &compactionRecorder{
+		Database:  memdb.New(),
+		compacted: make(chan [2][]byte, numCompactionRanges),
+	}
+	config := newDefaultConfig()
+	config.CompactionGarbageRatio = 0.5
+	config.CompactionMinGarbageBytes = 1
+	metrics := &mockMetrics{}
+	db, err := newDatabase(context.Background(), baseDB, config, metrics)
+	require.NoError(err)
+
+	for i := byte(0); i < 4; i++ {
+		require.NoError(db.Put([]byte{5, i}, []byte{i}))
+		require.NoError(db.Put([]byte{6, i}, []byte{i}))
+	}
+
+	// Half of the range isn't garbage yet.
+	require.NoError(db.Delete([]byte{5, 0}))
+	require.NoError(db.Delete([]byte{6, 0}))
+	require.Empty(baseDB.compacted)
+
+	require.NoError(db.Delete([]byte{5, 1}))
+	start, limit := valueNodeRange(5)
+	require.Equal([2][]byte{start, limit}, <-baseDB.compacted)
+	require.Equal([]byte{1, 5}, start)
+	require.Equal([]byte{1, 6}, limit)
+
+	// Only the garbage of range 6 is left once the compaction is recorded.
+	require.Eventually(func() bool {
+		metrics.lock.Lock()
+		defer metrics.lock.Unlock()
+
+		return metrics.valueNodeCompactions == 1
+	}, time.Second, time.Millisecond)
+	db.lock.RLock()
+	require.Zero(db.compaction.ranges[5].garbageBytes)
+	require.Equal(db.compaction.ranges[6].garbageBytes, db.compaction.garbageBytes)
+	require.Equal(db.compaction.garbageBytes, metrics.valueNodeGarbage)
+	db.lock.RUnlock()
+
+	// The tracked bytes are saved on a clean shutdown.
+	db.lock.RLock()
+	ranges := db.compaction.ranges
+	db.lock.RUnlock()
+	require.NoError(db.Close())
+	db, err = newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+	require.Equal(ranges, db.compaction.ranges)
+
+	// Otherwise, the live bytes are counted from disk.
+	require.NoError(db.Close())
+	require.NoError(baseDB.Delete(compactionKey))
+	db, err = newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+	for i := range ranges {
+		require.Equal(ranges[i].liveBytes, db.compaction.ranges[i].liveBytes)
+		require.Zero(db.compaction.ranges[i].garbageBytes)
+	}
+	require.NoError(db.Close())
+}
+
+func Test_MerkleDB_CompactValueNodes_Disabled(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := &compactionRecorder{
+		Database:  memdb.New(),
+		compacted: make(chan [2][]byte, numCompactionRanges),
+	}
+	db, err := newDatabase(context.Background(), baseDB, newDefaultConfig(), &mockMetrics{})
+	require.NoError(err)
+	require.Nil(db.compaction)
+
+	require.NoError(db.Put([]byte{5}, []byte{5}))
+	require.NoError(db.Delete([]byte{5}))
+	require.NoError(db.Close())
+	require.Empty(baseDB.compacted)
+}
+
+func Test_ValueNodeRange(t *testing.T) {
+	require := require.New(t)
+
+	start, limit := valueNodeRange(0)
+	require.Equal(valueNodePrefix, start)
+	require.Equal([]byte{1, 1}, limit)
+
+	start, limit = valueNodeRange(numCompactionRanges - 1)
+	require.Equal([]byte{1, 255}, start)
+	require.Equal(intermediateNodePrefix, limit)
+}
+
+func Test_MerkleDB_CompactValueNodes_CompressedSize(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	config := newNodeCompressionConfig(nodeCompressionDictionary)
+	config.CompactionGarbageRatio = 1
+	db, err := newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+	putNodeCompressionKeys(t, db)
+
+	// The tracked live bytes match the compressed bytes counted from disk.
+	ranges := db.compaction.ranges
+	require.NoError(db.Close())
+	require.NoError(baseDB.Delete(compactionKey))
+	db, err = newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+	for i := range ranges {
+		require.Equal(ranges[i].liveBytes, db.compaction.ranges[i].liveBytes)
+	}
+
+	// The live bytes don't underflow if a deleted value node wasn't counted.
+	key := []byte(nodeCompressionKeyPrefix + "0")
+	r := key[0]
+	db.compaction.ranges[r].liveBytes = 0
+	require.NoError(db.Delete(key))
+	require.Zero(db.compaction.ranges[r].liveBytes)
+	require.NotZero(db.compaction.ranges[r].garbageBytes)
+	require.NoError(db.Close())
+}
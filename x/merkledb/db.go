@@ -8,14 +8,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
 
 	"go.opentelemetry.io/otel/attribute"
 
@@ -29,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/maybe"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/validate"
 )
 
 const (
@@ -38,6 +43,10 @@ const (
 	minRebuildViewSizePerCommit          = 1000
 	rebuildIntermediateDeletionWriteSize = units.MiB
 	valueNodePrefixLen                   = 1
+
+	// The minimum number of nodes encoded by each goroutine during a commit.
+	// Commits of fewer nodes aren't worth encoding in parallel.
+	minNodesPerCommitWorker = 128
 )
 
 var (
@@ -49,11 +58,14 @@ var (
 	metadataPrefix         = []byte{0}
 	valueNodePrefix        = []byte{1}
 	intermediateNodePrefix = []byte{2}
+	viewSpillPrefix        = []byte{3}
 
 	cleanShutdownKey        = []byte(string(metadataPrefix) + "cleanShutdown")
 	hadCleanShutdown        = []byte{1}
 	didNotHaveCleanShutdown = []byte{0}
 
+	ErrProofDeadlineExceeded = errors.New("proof generation exceeded the maximum duration")
+
 	errSameRoot  = errors.New("start and end root are the same")
 	errNoNewRoot = errors.New("there was no updated root in change list")
 )
@@ -114,6 +126,127 @@ type RangeProofer interface {
 	CommitRangeProof(ctx context.Context, start, end maybe.Maybe[[]byte], proof *RangeProof) error
 }
 
+type RootPinner interface {
+	// PinRoot prevents the history needed to serve proofs at [rootID] from
+	// being removed until UnpinRoot is called as many times as PinRoot was.
+	// While any root is pinned, the history may exceed [Config.HistoryLength].
+	// Returns [ErrInsufficientHistory] if [rootID] isn't in the history.
+	// Returns [ErrTooManyPinnedRoots] if [Config.MaxPinnedRoots] other roots
+	// are already pinned.
+	PinRoot(rootID ids.ID) error
+
+	// UnpinRoot releases a pin on [rootID] taken by PinRoot.
+	// Returns [ErrRootNotPinned] if [rootID] isn't pinned.
+	UnpinRoot(rootID ids.ID) error
+}
+
+type TombstoneGetter interface {
+	// GetTombstone returns the root of the trie once [key] was deleted, if
+	// [key] was deleted by one of the last [Config.TombstoneWindow] commits and
+	// hasn't been inserted again since.
+	// This lets a consumer of changes distinguish a key that was recently
+	// deleted from a key that never existed without holding onto old roots.
+	// The key's absence from the current root can be proven with GetProof.
+	// Returns [database.ErrNotFound] otherwise.
+	//
+	// Tombstones are only kept in memory. After the database is reopened,
+	// keys deleted before it was closed have no tombstone, so
+	// [database.ErrNotFound] doesn't mean that [key] wasn't recently
+	// deleted. Consumers that must tell the two apart across restarts should
+	// keep the roots they need instead.
+	GetTombstone(ctx context.Context, key []byte) (ids.ID, error)
+}
+
+type HistoricalIteratee interface {
+	// NewIteratorAtRoot returns an iterator over the key-value pairs with
+	// keys >= [start] of the trie as it was when its root was [rootID].
+	// The trie is reconstructed from the change history, and the changes
+	// needed to do so are retained until the iterator is released, even if
+	// the history exceeds [Config.HistoryLength].
+	// Commits made during iteration aren't observed.
+	// Returns [ErrInsufficientHistory] if [rootID] isn't the current root and
+	// isn't in the history.
+	NewIteratorAtRoot(rootID ids.ID, start []byte) (database.Iterator, error)
+}
+
+type Checkpointer interface {
+	// Checkpoint records the current root under [name], replacing the
+	// checkpoint with the same name if there is one, so that the database can
+	// be rolled back to it with RollbackTo.
+	// The changes committed after the checkpoint are retained in the history
+	// until ReleaseCheckpoint is called, even if the history exceeds
+	// [Config.HistoryLength]. Like the history, checkpoints aren't persisted.
+	Checkpoint(name string) error
+
+	// RollbackTo commits the changes that return the database to the root of
+	// checkpoint [name], and keeps the checkpoint. Like any commit, it
+	// invalidates the views of the database.
+	// Returns [ErrCheckpointNotFound] if there is no checkpoint [name].
+	RollbackTo(name string) error
+
+	// ReleaseCheckpoint removes checkpoint [name] so that the changes it
+	// retained can be removed from the history.
+	// Returns [ErrCheckpointNotFound] if there is no checkpoint [name].
+	ReleaseCheckpoint(name string) error
+}
+
+type StatsGetter interface {
+	// Stats returns statistics about the nodes of the trie at the current
+	// root. They're maintained as changes are committed, so getting them
+	// doesn't visit the trie.
+	Stats(ctx context.Context) (Stats, error)
+}
+
+type RangeExporter interface {
+	// ExportRange writes the key-value pairs of the trie in [start, end] to
+	// [w], with the proof nodes needed to verify them against the current
+	// root of the trie. The export can be applied to another database with
+	// ImportRange.
+	// If [start] is Nothing, there's no lower bound on the range.
+	// If [end] is Nothing, there's no upper bound on the range.
+	ExportRange(ctx context.Context, start, end maybe.Maybe[[]byte], w io.Writer) error
+
+	// ImportRange reads an export written by ExportRange from [r], verifies
+	// that it holds every key-value pair in its range of the trie whose root
+	// is [expectedRootID], and then commits them. Keys in the range that
+	// aren't in the export are deleted. Nothing is committed unless the
+	// export is valid.
+	// Returns [ErrUnexpectedExportRoot] if the export is of another root.
+	ImportRange(ctx context.Context, r io.Reader, expectedRootID ids.ID) error
+}
+
+type Exporter interface {
+	// Export writes every key-value pair of the trie, along with its root, to
+	// [w] in a versioned format that doesn't depend on how the trie is stored.
+	// The export can be applied to another database with Import.
+	Export(ctx context.Context, w io.Writer) error
+
+	// Import reads an export written by Export from [r], checks that its
+	// key-value pairs have the export's root, and then commits them. Keys that
+	// aren't in the export are deleted, so the trie ends up with the export's
+	// root. Nothing is committed unless the export is valid.
+	// Returns [ErrExportRootMismatch] if the key-value pairs don't have the
+	// export's root.
+	Import(ctx context.Context, r io.Reader) error
+}
+
+type CheckpointWriter interface {
+	// WriteCheckpoint writes every node of the trie, along with its root, to
+	// [w] in a versioned format that doesn't depend on the underlying
+	// database or on how nodes are stored. A new database, possibly with
+	// another backend, can be opened from the checkpoint with
+	// [OpenFromCheckpoint] without recomputing the trie.
+	WriteCheckpoint(ctx context.Context, w io.Writer) error
+}
+
+type TraceLeveler interface {
+	// SetTraceLevel changes which spans are traced from now on.
+	SetTraceLevel(level TraceLevel)
+
+	// GetTraceLevel returns the current trace level.
+	GetTraceLevel() TraceLevel
+}
+
 type Prefetcher interface {
 	// PrefetchPath attempts to load all trie nodes on the path of [key]
 	// into the cache.
@@ -127,6 +260,29 @@ type Prefetcher interface {
 	PrefetchPaths(keys [][]byte) error
 }
 
+type CommitNotifier interface {
+	// RegisterCommitListener registers [listener] to be called after each
+	// subsequent commit that changes a key, with the new root and the changed
+	// keys, sorted, and their new values. A deleted key's value is Nothing.
+	//
+	// Listeners are called synchronously, in the order they were registered,
+	// before the commit lock is released, so no other commit is started until
+	// they return. They may read from the database, but must not commit to it,
+	// register listeners or modify [changes].
+	RegisterCommitListener(listener CommitListener)
+}
+
+type ReadOnlyViewer interface {
+	// NewReadOnlyView returns a view of the committed trie that has no
+	// changes and can't be committed, so reading from it is as cheap as
+	// reading from the database. The view is invalidated by the next commit
+	// that changes the root, after which reads from it return [ErrInvalid].
+	//
+	// If [onInvalidate] isn't nil, it's called once the view is invalidated,
+	// in its own goroutine, after the commit that invalidated it is written.
+	NewReadOnlyView(ctx context.Context, onInvalidate func()) (ReadOnlyTrie, error)
+}
+
 type MerkleDB interface {
 	database.Database
 	Trie
@@ -134,6 +290,17 @@ type MerkleDB interface {
 	ProofGetter
 	ChangeProofer
 	RangeProofer
+	RootPinner
+	TombstoneGetter
+	HistoricalIteratee
+	Checkpointer
+	StatsGetter
+	RangeExporter
+	Exporter
+	CheckpointWriter
+	CommitNotifier
+	ReadOnlyViewer
+	TraceLeveler
 	Prefetcher
 }
 
@@ -146,24 +313,216 @@ type Config struct {
 	//
 	// If 0 is specified, [runtime.NumCPU] will be used.
 	RootGenConcurrency uint
+	// CommitConcurrency is the maximum number of goroutines to use when
+	// encoding the nodes written by a commit. The nodes are still written to
+	// disk in a single batch.
+	//
+	// If 0 or 1 is specified, nodes are encoded by the committing goroutine.
+	CommitConcurrency uint
 	// The number of bytes to write to disk when intermediate nodes are evicted
 	// from their cache and written to disk.
 	EvictionBatchSize uint
 	// The number of changes to the database that we store in memory in order to
 	// serve change proofs.
 	HistoryLength uint
+	// The maximum number of roots that can be pinned at once to keep their
+	// history from being removed.
+	//
+	// If 0 is specified, roots can't be pinned.
+	MaxPinnedRoots uint
+	// The number of commits for which deleted keys are remembered as
+	// tombstones. Tombstones are kept in memory, so they aren't retained across
+	// restarts.
+	//
+	// If 0 is specified, no tombstones are kept.
+	TombstoneWindow uint
 	// The number of bytes to cache nodes with values.
 	ValueNodeCacheSize uint
-	// The number of bytes to cache nodes without values.
+	// The number of bytes to buffer changed nodes without values before they
+	// are written to disk.
 	IntermediateNodeCacheSize uint
+	// The number of bytes to cache nodes without values that were read from
+	// disk and haven't changed since.
+	//
+	// If 0 is specified, read nodes without values aren't cached.
+	IntermediateNodeReadCacheSize uint
+	// The maximum number of nodes without values whose keys are saved on a
+	// clean shutdown. The most recently read nodes in the read cache are
+	// saved. On the next startup, those nodes are loaded into the read cache
+	// in the background, most recently read first, until it is full. This
+	// avoids slow reads from disk while the cache would otherwise be cold.
+	//
+	// If 0 is specified, the cache isn't warmed.
+	CacheWarmingSize uint
+	// The maximum number of nodes to read from disk per second while warming
+	// the cache.
+	//
+	// If 0 is specified, warming isn't rate limited.
+	CacheWarmingNodesPerSecond uint
+	// If true, the top [PrewarmCacheDepth] levels of the trie below the root
+	// are loaded into the cache in the background once the database is
+	// opened, one level at a time, until the cache is full. Those nodes are
+	// read by almost every operation, so this avoids slow reads from disk
+	// after a restart even if no nodes were saved with [CacheWarmingSize].
+	// They are loaded before the saved nodes, at the rate limited by
+	// [CacheWarmingNodesPerSecond].
+	PrewarmCacheOnOpen bool
+	// The number of levels below the root loaded into the cache if
+	// [PrewarmCacheOnOpen] is true.
+	//
+	// If 0 is specified, levels are loaded until the cache is full.
+	PrewarmCacheDepth uint
+	// The maximum amount of time to spend generating a range or change proof.
+	// A range proof that exceeds this duration is truncated after the last
+	// key-value pair that was added to it, which keeps it verifiable. A change
+	// proof that exceeds this duration fails with [ErrProofDeadlineExceeded].
+	//
+	// If 0 is specified, proof generation is unbounded.
+	MaxProofDuration time.Duration
+	// The maximum amount of time that committing changes should take. Commits
+	// are never interrupted. If a commit exceeds this duration, the changes
+	// are still committed and the overrun is reported by the
+	// commit_deadline_exceeded metric.
+	//
+	// If 0 is specified, commits are unbounded.
+	MaxCommitDuration time.Duration
+	// If true, the checksum stored with each value is verified whenever the
+	// value is read from disk rather than from the cache. This detects
+	// corruption of the value store, independently of node IDs, at the cost
+	// of hashing every value that is read.
+	// This may be useful for debugging.
+	VerifyValueChecksums bool
+	// The maximum number of keys held by a bloom filter over the keys of the
+	// database, which lets reads of keys that aren't in the database skip
+	// the disk. Deleted keys stay in the filter, so once this many keys were
+	// written, reads of missing keys are skipped less often until the filter
+	// is repopulated from disk on the next startup.
+	// The filter is saved on a clean shutdown and repopulated from disk
+	// otherwise.
+	//
+	// If 0 is specified, no filter is kept.
+	ValueFilterSize uint
+	// If non-zero, a range of value nodes is compacted in the background once
+	// this fraction of the space it uses on disk is taken by value nodes that
+	// were deleted or overwritten since it was last compacted. Value nodes
+	// are split into ranges by the first byte of their key.
+	// Without compaction, the space used by deleted values may never be
+	// reclaimed by the underlying database.
+	// Must be in [0, 1]. If 0 is specified, value nodes are never compacted
+	// automatically.
+	CompactionGarbageRatio float64
+	// The minimum number of bytes of deleted or overwritten value nodes in a
+	// range for it to be compacted, so that small ranges aren't compacted
+	// after every commit.
+	CompactionMinGarbageBytes uint
+	// Validators of the values written to keys with a given prefix. Values
+	// written through Put, batches and views are checked by the validator of
+	// every prefix of their key, in order, when the change is staged. A
+	// rejected value fails with [ErrInvalidValue] and nothing is staged.
+	// Deletes and the values of proofs received from peers aren't validated.
+	ValueValidators []PrefixValidator
+	// Quotas on the work done to generate proofs on behalf of the callers
+	// labelled with [WithProofCaller], such as RPC consumers, so that they
+	// can't monopolize the database. Every trie node and key-value pair
+	// visited counts against the caller's quota, and a proof fails with
+	// [ErrProofQuotaExceeded] once it's exhausted. Proofs of callers without
+	// a quota are never limited.
+	ProofQuotas []ProofQuota
+	// If non-nil, every change committed to the database is also written to
+	// [Shadow], and the roots of the two are compared after each commit. This
+	// validates an alternate implementation, such as a new node encoding,
+	// against this one without affecting the database. [Shadow] must
+	// initially contain the same key-value pairs as the database.
+	Shadow ShadowDB
+	// Called with an error wrapping [ErrShadowDiverged] the first time
+	// [Shadow] fails to apply a commit or its root differs from the
+	// database's. Once diverged, the shadow is no longer written to.
+	// If nil, divergence is only reported through metrics.
+	OnShadowDivergence func(error)
+	// Called when a view is garbage collected without having been released
+	// or committed, which means that it was tracked by its parent until the
+	// next commit. It's called from the garbage collector's finalizer
+	// goroutine, so it must not block.
+	// If nil, leaked views are only reported through metrics.
+	OnViewLeak func()
+	// The estimated number of bytes that the value changes of a view can use
+	// before they're moved to disk. They're stored in the underlying database
+	// until the view is committed or released and the history no longer
+	// needs them, and read from disk as needed. Once a view is committed, or
+	// released and without children, its spilled changes can't be read
+	// through it. The changes spilled by leaked views are deleted when the
+	// database is reopened.
+	// The nodes changed by the view stay in memory.
+	// If 0 is specified, the value changes of views are always kept in memory.
+	ViewMemoryLimit uint
+	// If non-nil, the nodes are compressed by the compressor it returns when
+	// they're written to disk, for example with [NewZstdNodeCompressor]. Keys
+	// with long common prefixes are repeated in the nodes on the paths to
+	// them, so a dictionary of those prefixes can shrink the nodes
+	// considerably.
+	// Once a database has been opened with compression, it must always be
+	// opened with it. Compression can only be enabled on a new database.
+	NodeCompression NodeCompressorFactory
+	// The dictionary that [NodeCompression] is created with. It's stored in
+	// the database when compression is enabled, and the stored dictionary is
+	// used afterwards. If non-empty, it must match the stored dictionary.
+	NodeCompressionDictionary []byte
 	// If [Reg] is nil, metrics are collected locally but not exported through
 	// Prometheus.
 	// This may be useful for testing.
-	Reg        prometheus.Registerer
+	Reg prometheus.Registerer
+	// The initial trace level. It can be changed at runtime with
+	// [TraceLeveler.SetTraceLevel].
 	TraceLevel TraceLevel
 	Tracer     trace.Tracer
 }
 
+// Verify returns an error describing every invalid field of the config.
+func (c *Config) Verify() error {
+	v := validate.New("merkledb")
+	v.Add(c.BranchFactor.Valid())
+	v.Checkf(
+		c.EvictionBatchSize <= c.IntermediateNodeCacheSize,
+		"EvictionBatchSize (%d) must be <= IntermediateNodeCacheSize (%d)",
+		c.EvictionBatchSize, c.IntermediateNodeCacheSize,
+	)
+	v.Checkf(
+		c.IntermediateNodeReadCacheSize > 0 || (c.CacheWarmingSize == 0 && !c.PrewarmCacheOnOpen),
+		"IntermediateNodeReadCacheSize must be > 0 if CacheWarmingSize (%d) is > 0 or PrewarmCacheOnOpen is true",
+		c.CacheWarmingSize,
+	)
+	v.Checkf(
+		c.MaxPinnedRoots == 0 || c.HistoryLength > 0,
+		"MaxPinnedRoots (%d) must be 0 if HistoryLength is 0",
+		c.MaxPinnedRoots,
+	)
+	validate.AtLeast(v, "MaxProofDuration", c.MaxProofDuration, 0)
+	validate.AtLeast(v, "MaxCommitDuration", c.MaxCommitDuration, 0)
+	validate.InRange(v, "CompactionGarbageRatio", c.CompactionGarbageRatio, 0, 1)
+	for i, validator := range c.ValueValidators {
+		v.Checkf(
+			validator.Validate != nil,
+			"ValueValidators[%d] must have a Validate function",
+			i,
+		)
+	}
+	callers := set.NewSet[string](len(c.ProofQuotas))
+	for i, quota := range c.ProofQuotas {
+		v.Checkf(
+			quota.NodesPerSecond > 0,
+			"ProofQuotas[%d].NodesPerSecond must be > 0",
+			i,
+		)
+		v.Checkf(
+			!callers.Contains(quota.Caller),
+			"ProofQuotas[%d] has the same Caller (%q) as a previous quota",
+			i, quota.Caller,
+		)
+		callers.Add(quota.Caller)
+	}
+	return v.Err()
+}
+
 // merkleDB can only be edited by committing changes from a trieView.
 type merkleDB struct {
 	// Must be held when reading/writing fields.
@@ -182,21 +541,41 @@ type merkleDB struct {
 	valueNodeDB        *valueNodeDB
 	intermediateNodeDB *intermediateNodeDB
 
+	// Shared by [valueNodeDB] and [intermediateNodeDB]. Also holds the
+	// buffers of short-lived keys.
+	bufferPool *sync.Pool
+
 	// Stores change lists. Used to serve change proofs and construct
 	// historical views of the trie.
 	history *trieHistory
 
+	// Remembers the keys deleted by recent commits.
+	tombstones *tombstones
+
+	// Name --> checkpoint. See [Checkpointer].
+	checkpoints map[string]checkpoint
+
+	// Statistics about the nodes of the trie at [root]. See [StatsGetter].
+	stats *nodeStats
+
 	// True iff the db has been closed.
 	closed bool
 
 	metrics merkleMetrics
 
-	debugTracer trace.Tracer
-	infoTracer  trace.Tracer
+	// Spans are only started if [traceLevel] allows them.
+	traceLevel  utils.Atomic[TraceLevel]
+	debugTracer *levelTracer
+	infoTracer  *levelTracer
 
 	// The root of this trie.
 	root *node
 
+	// Describes the committed trie so that it can be read without holding
+	// [lock]. A new value is published whenever [root] or [closed] change
+	// and while a commit is being written.
+	readState atomic.Pointer[readState]
+
 	// Valid children of this trie.
 	childViews []*trieView
 
@@ -204,6 +583,61 @@ type merkleDB struct {
 	// [calculateNodeIDsHelper] at any given time.
 	calculateNodeIDsSema *semaphore.Weighted
 
+	// See [Config.CommitConcurrency].
+	commitConcurrency int
+
+	// See [Config.ValueValidators].
+	valueValidators []PrefixValidator
+	// Caller --> Limiter of the work done to generate the caller's proofs.
+	// See [Config.ProofQuotas].
+	proofQuotas map[string]*rate.Limiter
+
+	// See [Config.MaxProofDuration] and [Config.MaxCommitDuration].
+	maxProofDuration  time.Duration
+	maxCommitDuration time.Duration
+
+	// See [Config.CacheWarmingSize].
+	cacheWarmingSize int
+	// See [Config.PrewarmCacheOnOpen] and [Config.PrewarmCacheDepth].
+	prewarmCacheOnOpen bool
+	prewarmCacheDepth  int
+	// Stops the cache warming started on startup.
+	cancelCacheWarming context.CancelFunc
+	// Closed once the cache warming started on startup returns.
+	cacheWarmingDone chan struct{}
+
+	// Tracks the garbage of the value nodes on disk. Nil if value nodes
+	// aren't compacted automatically. See [Config.CompactionGarbageRatio].
+	compaction *compactionTracker
+	// Ranges of value nodes to compact. See [compactValueNodes].
+	compactionRequests chan byte
+	// Stops compacting value nodes.
+	cancelCompaction context.CancelFunc
+	// Closed once [compactValueNodes] returns.
+	compactionDone chan struct{}
+
+	// See [Config.Shadow].
+	shadow *shadow
+
+	// See [Config.OnViewLeak].
+	onViewLeak func()
+
+	// See [Config.ViewMemoryLimit].
+	viewMemoryLimit int
+	// Identifies the next spill of the value changes of a view.
+	nextSpillID atomic.Uint64
+
+	// See [CommitNotifier].
+	commitListeners []CommitListener
+
+	// The read-only views of the current root. See [ReadOnlyViewer].
+	// [lock] must be held when accessing this field.
+	readOnlyViews []*trieView
+	// The read-only views invalidated by the commit in progress, whose
+	// callbacks haven't been called yet.
+	// [commitLock] must be held when accessing this field.
+	invalidatedReadOnlyViews []*trieView
+
 	toKey   func(p []byte) Key
 	rootKey Key
 }
@@ -223,14 +657,11 @@ func newDatabase(
 	config Config,
 	metrics merkleMetrics,
 ) (*merkleDB, error) {
-	rootGenConcurrency := uint(runtime.NumCPU())
-	if config.RootGenConcurrency != 0 {
-		rootGenConcurrency = config.RootGenConcurrency
-	}
-
-	if err := config.BranchFactor.Valid(); err != nil {
+	if err := config.Verify(); err != nil {
 		return nil, err
 	}
+	validate.Default(&config.RootGenConcurrency, uint(runtime.NumCPU()))
+	validate.Default(&config.Tracer, trace.Noop)
 
 	toKey := func(b []byte) Key {
 		return ToKey(b, config.BranchFactor)
@@ -246,21 +677,62 @@ func newDatabase(
 	trieDB := &merkleDB{
 		metrics:              metrics,
 		baseDB:               db,
-		valueNodeDB:          newValueNodeDB(db, bufferPool, metrics, int(config.ValueNodeCacheSize), config.BranchFactor),
-		intermediateNodeDB:   newIntermediateNodeDB(db, bufferPool, metrics, int(config.IntermediateNodeCacheSize), int(config.EvictionBatchSize)),
-		history:              newTrieHistory(int(config.HistoryLength), toKey),
-		debugTracer:          getTracerIfEnabled(config.TraceLevel, DebugTrace, config.Tracer),
-		infoTracer:           getTracerIfEnabled(config.TraceLevel, InfoTrace, config.Tracer),
+		bufferPool:           bufferPool,
+		valueNodeDB:          newValueNodeDB(db, bufferPool, metrics, int(config.ValueNodeCacheSize), config.BranchFactor, config.VerifyValueChecksums),
+		intermediateNodeDB:   newIntermediateNodeDB(db, bufferPool, metrics, int(config.IntermediateNodeCacheSize), int(config.IntermediateNodeReadCacheSize), int(config.EvictionBatchSize)),
+		history:              newTrieHistory(int(config.HistoryLength), int(config.MaxPinnedRoots), toKey),
+		tombstones:           newTombstones(int(config.TombstoneWindow)),
+		checkpoints:          make(map[string]checkpoint),
 		childViews:           make([]*trieView, 0, defaultPreallocationSize),
-		calculateNodeIDsSema: semaphore.NewWeighted(int64(rootGenConcurrency)),
+		calculateNodeIDsSema: semaphore.NewWeighted(int64(config.RootGenConcurrency)),
+		commitConcurrency:    int(config.CommitConcurrency),
+		valueValidators:      slices.Clone(config.ValueValidators),
+		proofQuotas:          newProofQuotas(config.ProofQuotas),
+		maxProofDuration:     config.MaxProofDuration,
+		maxCommitDuration:    config.MaxCommitDuration,
+		cacheWarmingSize:     int(config.CacheWarmingSize),
+		prewarmCacheOnOpen:   config.PrewarmCacheOnOpen,
+		prewarmCacheDepth:    int(config.PrewarmCacheDepth),
+		cancelCacheWarming:   func() {},
+		cacheWarmingDone:     make(chan struct{}),
+		compaction:           newCompactionTracker(config.CompactionGarbageRatio, uint64(config.CompactionMinGarbageBytes)),
+		compactionRequests:   make(chan byte, numCompactionRanges),
+		cancelCompaction:     func() {},
+		compactionDone:       make(chan struct{}),
+		shadow:               newShadow(config.Shadow, config.OnShadowDivergence),
+		onViewLeak:           config.OnViewLeak,
+		viewMemoryLimit:      int(config.ViewMemoryLimit),
 		toKey:                toKey,
 		rootKey:              toKey(rootKey),
 	}
 
+	compression, err := loadNodeCompression(db, config, metrics)
+	if err != nil {
+		return nil, err
+	}
+	trieDB.valueNodeDB.compression = compression
+	trieDB.intermediateNodeDB.compression = compression
+	if err := upgradeValueNodeFormat(db, compression); err != nil {
+		return nil, err
+	}
+
+	trieDB.traceLevel.Set(config.TraceLevel)
+	trieDB.debugTracer = &levelTracer{
+		minLevel: DebugTrace,
+		level:    &trieDB.traceLevel,
+		tracer:   config.Tracer,
+	}
+	trieDB.infoTracer = &levelTracer{
+		minLevel: InfoTrace,
+		level:    &trieDB.traceLevel,
+		tracer:   config.Tracer,
+	}
+
 	root, err := trieDB.initializeRootIfNeeded()
 	if err != nil {
 		return nil, err
 	}
+	trieDB.publishReadState(nil)
 
 	// add current root to history (has no changes)
 	trieDB.history.record(&changeSummary{
@@ -269,13 +741,45 @@ func newDatabase(
 		nodes:  map[Key]*change[*node]{},
 	})
 
+	cacheWarmingKeys, err := trieDB.loadCacheWarmingManifest()
+	if err != nil {
+		return nil, err
+	}
+	loadedValueFilter, err := trieDB.loadValueFilter()
+	if err != nil {
+		return nil, err
+	}
+	loadedStats, err := trieDB.loadStats()
+	if err != nil {
+		return nil, err
+	}
+	loadedCompaction, err := trieDB.loadCompactionTracker()
+	if err != nil {
+		return nil, err
+	}
+
+	// The value changes spilled by the views of a previous run are no longer
+	// referenced.
+	if err := database.ClearPrefix(db, viewSpillPrefix, spillWriteSize); err != nil {
+		return nil, err
+	}
+
 	shutdownType, err := trieDB.baseDB.Get(cleanShutdownKey)
 	switch err {
 	case nil:
 		if bytes.Equal(shutdownType, didNotHaveCleanShutdown) {
+			// The manifest is from an earlier clean shutdown, so it may
+			// no longer describe the hottest nodes. Likewise, the value
+			// filter may be missing keys written since then.
+			cacheWarmingKeys = nil
+			loadedValueFilter = nil
 			if err := trieDB.rebuild(ctx, int(config.ValueNodeCacheSize)); err != nil {
 				return nil, err
 			}
+			// The stats and the live bytes of the value nodes were computed
+			// while rebuilding.
+			loadedStats = trieDB.stats
+			loadedCompaction = true
 		}
 	case database.ErrNotFound:
 		// If the marker wasn't found then the DB is being created for the first
@@ -284,22 +788,54 @@ func newDatabase(
 		return nil, err
 	}
 
+	if err := trieDB.initValueFilter(config.ValueFilterSize, loadedValueFilter); err != nil {
+		return nil, err
+	}
+	if err := trieDB.initStats(loadedStats); err != nil {
+		return nil, err
+	}
+	if err := trieDB.initCompactionTracker(loadedCompaction); err != nil {
+		return nil, err
+	}
+
 	// mark that the db has not yet been cleanly closed
-	err = trieDB.baseDB.Put(cleanShutdownKey, didNotHaveCleanShutdown)
-	return trieDB, err
+	if err := trieDB.baseDB.Put(cleanShutdownKey, didNotHaveCleanShutdown); err != nil {
+		return nil, err
+	}
+
+	var limiter *rate.Limiter
+	if config.CacheWarmingNodesPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(config.CacheWarmingNodesPerSecond), 1)
+	}
+	// Warming outlives [ctx], which only bounds the creation of the database.
+	warmingCtx, cancel := context.WithCancel(context.Background())
+	trieDB.cancelCacheWarming = cancel
+	go trieDB.warmCache(warmingCtx, cacheWarmingKeys, limiter)
+
+	compactionCtx, cancel := context.WithCancel(context.Background())
+	trieDB.cancelCompaction = cancel
+	go trieDB.compactValueNodes(compactionCtx)
+	return trieDB, nil
 }
 
 // Deletes every intermediate node and rebuilds them by re-adding every key/value.
 // TODO: make this more efficient by only clearing out the stale portions of the trie.
 func (db *merkleDB) rebuild(ctx context.Context, cacheSize int) error {
 	db.root = newNode(nil, db.rootKey)
+	db.publishReadState(nil)
+	db.stats = &nodeStats{}
+	db.stats.add(db.root)
+	if db.compaction != nil {
+		db.compaction.reset()
+	}
 
 	// Delete intermediate nodes.
 	if err := database.ClearPrefix(db.baseDB, intermediateNodePrefix, rebuildIntermediateDeletionWriteSize); err != nil {
 		return err
 	}
 
-	// Add all key-value pairs back into the database.
+	// Add all key-value pairs back into the database. Rebuilding is expected
+	// to be slow, so commits that exceed [db.maxCommitDuration] aren't errors.
 	opsSizeLimit := math.Max(
 		cacheSize/rebuildViewSizeFractionOfCacheSize,
 		minRebuildViewSizePerCommit,
@@ -415,6 +951,14 @@ func (db *merkleDB) Compact(start []byte, limit []byte) error {
 }
 
 func (db *merkleDB) Close() error {
+	// Stop warming the cache before grabbing [db.commitLock], which warming
+	// holds while loading each node.
+	db.cancelCacheWarming()
+	<-db.cacheWarmingDone
+	// A compaction in progress is finished first.
+	db.cancelCompaction()
+	<-db.compactionDone
+
 	db.commitLock.Lock()
 	defer db.commitLock.Unlock()
 
@@ -426,7 +970,20 @@ func (db *merkleDB) Close() error {
 	}
 
 	db.closed = true
+	db.publishReadState(nil)
 	db.valueNodeDB.Close()
+	if err := db.writeCacheWarmingManifest(); err != nil {
+		return err
+	}
+	if err := db.writeValueFilter(); err != nil {
+		return err
+	}
+	if err := db.writeStats(); err != nil {
+		return err
+	}
+	if err := db.writeCompactionTracker(); err != nil {
+		return err
+	}
 	// Flush intermediary nodes to disk.
 	if err := db.intermediateNodeDB.Flush(); err != nil {
 		return err
@@ -436,6 +993,14 @@ func (db *merkleDB) Close() error {
 	return db.baseDB.Put(cleanShutdownKey, hadCleanShutdown)
 }
 
+func (db *merkleDB) SetTraceLevel(level TraceLevel) {
+	db.traceLevel.Set(level)
+}
+
+func (db *merkleDB) GetTraceLevel() TraceLevel {
+	return db.traceLevel.Get()
+}
+
 func (db *merkleDB) PrefetchPaths(keys [][]byte) error {
 	db.commitLock.RLock()
 	defer db.commitLock.RUnlock()
@@ -476,7 +1041,8 @@ func (db *merkleDB) PrefetchPath(key []byte) error {
 func (db *merkleDB) prefetchPath(view *trieView, keyBytes []byte) error {
 	return view.visitPathToKey(db.toKey(keyBytes), func(n *node) error {
 		if !n.hasValue() {
-			return db.intermediateNodeDB.nodeCache.Put(n.key, n)
+			// Intermediate nodes are cached when they're read.
+			return nil
 		}
 
 		db.valueNodeDB.nodeCache.Put(n.key, n)
@@ -496,14 +1062,17 @@ func (db *merkleDB) GetValues(ctx context.Context, keys [][]byte) ([][]byte, []e
 	))
 	defer span.End()
 
-	// Lock to ensure no commit happens during the reads.
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
 	values := make([][]byte, len(keys))
 	errors := make([]error, len(keys))
-	for i, key := range keys {
-		values[i], errors[i] = db.getValueCopy(db.toKey(key))
+	// Read all the values from the same committed trie.
+	_ = db.readCommitted(func(state *readState) error {
+		for i, key := range keys {
+			values[i], errors[i] = state.getValue(db, db.toKey(key))
+		}
+		return nil
+	})
+	for i, value := range values {
+		values[i] = slices.Clone(value)
 	}
 	return values, errors
 }
@@ -514,17 +1083,7 @@ func (db *merkleDB) GetValue(ctx context.Context, key []byte) ([]byte, error) {
 	_, span := db.debugTracer.Start(ctx, "MerkleDB.GetValue")
 	defer span.End()
 
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
-	return db.getValueCopy(db.toKey(key))
-}
-
-// getValueCopy returns a copy of the value for the given [key].
-// Returns database.ErrNotFound if it doesn't exist.
-// Assumes [db.lock] is read locked.
-func (db *merkleDB) getValueCopy(key Key) ([]byte, error) {
-	val, err := db.getValueWithoutLock(key)
+	val, err := db.getValue(db.toKey(key))
 	if err != nil {
 		return nil, err
 	}
@@ -535,42 +1094,24 @@ func (db *merkleDB) getValueCopy(key Key) ([]byte, error) {
 // Returns database.ErrNotFound if it doesn't exist.
 // Assumes [db.lock] isn't held.
 func (db *merkleDB) getValue(key Key) ([]byte, error) {
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
-	return db.getValueWithoutLock(key)
-}
-
-// getValueWithoutLock returns the value for the given [key].
-// Returns database.ErrNotFound if it doesn't exist.
-// Assumes [db.lock] is read locked.
-func (db *merkleDB) getValueWithoutLock(key Key) ([]byte, error) {
-	if db.closed {
-		return nil, database.ErrClosed
-	}
-
-	n, err := db.getNode(key, true /* hasValue */)
-	if err != nil {
-		return nil, err
-	}
-	if n.value.IsNothing() {
-		return nil, database.ErrNotFound
-	}
-	return n.value.Value(), nil
+	var val []byte
+	err := db.readCommitted(func(state *readState) error {
+		var err error
+		val, err = state.getValue(db, key)
+		return err
+	})
+	return val, err
 }
 
 func (db *merkleDB) GetMerkleRoot(ctx context.Context) (ids.ID, error) {
 	_, span := db.infoTracer.Start(ctx, "MerkleDB.GetMerkleRoot")
 	defer span.End()
 
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
-	if db.closed {
+	state := db.readState.Load()
+	if state.closed {
 		return ids.Empty, database.ErrClosed
 	}
-
-	return db.getMerkleRoot(), nil
+	return state.root.id, nil
 }
 
 // Assumes [db.lock] is read locked.
@@ -578,17 +1119,46 @@ func (db *merkleDB) getMerkleRoot() ids.ID {
 	return db.root.id
 }
 
+// GetProof doesn't wait for in-progress commits.
+// The returned proof is generated against the most recently committed root.
 func (db *merkleDB) GetProof(ctx context.Context, key []byte) (*Proof, error) {
-	db.commitLock.RLock()
-	defer db.commitLock.RUnlock()
+	var proof *Proof
+	err := db.readCommitted(func(state *readState) error {
+		var err error
+		proof, err = db.getProof(ctx, state, key)
+		return err
+	})
+	return proof, err
+}
 
-	return db.getProof(ctx, key)
+// GetProofs doesn't wait for in-progress commits.
+// The returned proofs are generated against the most recently committed root.
+func (db *merkleDB) GetProofs(ctx context.Context, keys [][]byte) ([]*Proof, error) {
+	ctx, span := db.infoTracer.Start(ctx, "MerkleDB.GetProofs", oteltrace.WithAttributes(
+		attribute.Int("keyCount", len(keys)),
+	))
+	defer span.End()
+
+	var proofs []*Proof
+	err := db.readCommitted(func(state *readState) error {
+		if state.closed {
+			return database.ErrClosed
+		}
+
+		view, err := newTrieView(db, db, ViewChanges{})
+		if err != nil {
+			return err
+		}
+		// Don't need to lock [view] because nobody else has a reference to it.
+		proofs, err = view.getProofs(ctx, keys)
+		return err
+	})
+	return proofs, err
 }
 
-// Assumes [db.commitLock] is read locked.
 // Assumes [db.lock] is not held
-func (db *merkleDB) getProof(ctx context.Context, key []byte) (*Proof, error) {
-	if db.closed {
+func (db *merkleDB) getProof(ctx context.Context, state *readState, key []byte) (*Proof, error) {
+	if state.closed {
 		return nil, database.ErrClosed
 	}
 
@@ -600,16 +1170,57 @@ func (db *merkleDB) getProof(ctx context.Context, key []byte) (*Proof, error) {
 	return view.getProof(ctx, key)
 }
 
+// GetRangeProof doesn't wait for in-progress commits.
+// The returned proof is generated against the root that was committed when
+// GetRangeProof was called, even if other commits finish while the proof is
+// being generated. So the returned key-value pairs and the start and end
+// proofs are always consistent with each other.
 func (db *merkleDB) GetRangeProof(
 	ctx context.Context,
 	start maybe.Maybe[[]byte],
 	end maybe.Maybe[[]byte],
 	maxLength int,
 ) (*RangeProof, error) {
-	db.commitLock.RLock()
-	defer db.commitLock.RUnlock()
+	if maxLength <= 0 {
+		return nil, fmt.Errorf("%w but was %d", ErrInvalidMaxLength, maxLength)
+	}
 
-	return db.getRangeProofAtRoot(ctx, db.getMerkleRoot(), start, end, maxLength)
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.release()
+
+	return snapshot.GetRangeProof(ctx, start, end, maxLength)
+}
+
+// StreamRangeProof doesn't wait for in-progress commits.
+// Every proof passed to [fn] is generated against the root that was committed
+// when StreamRangeProof was called, even if other commits finish while the
+// proofs are being generated.
+func (db *merkleDB) StreamRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	chunkSize int,
+	fn func(*RangeProof) error,
+) error {
+	ctx, span := db.infoTracer.Start(ctx, "MerkleDB.StreamRangeProof", oteltrace.WithAttributes(
+		attribute.Int("chunkSize", chunkSize),
+	))
+	defer span.End()
+
+	if chunkSize <= 0 {
+		return fmt.Errorf("%w but was %d", ErrInvalidMaxLength, chunkSize)
+	}
+
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.release()
+
+	return snapshot.StreamRangeProof(ctx, start, end, chunkSize, fn)
 }
 
 func (db *merkleDB) GetRangeProofAtRoot(
@@ -625,6 +1236,59 @@ func (db *merkleDB) GetRangeProofAtRoot(
 	return db.getRangeProofAtRoot(ctx, rootID, start, end, maxLength)
 }
 
+func (db *merkleDB) PinRoot(rootID ids.ID) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	// Snapshots read [db.history] while only holding [db.lock].
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if err := db.history.pin(rootID); err != nil {
+		return err
+	}
+	db.metrics.SetPinnedRoots(len(db.history.pinnedRoots))
+	return nil
+}
+
+func (db *merkleDB) UnpinRoot(rootID ids.ID) error {
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	// Snapshots read [db.history] while only holding [db.lock].
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if err := db.history.unpin(rootID); err != nil {
+		return err
+	}
+	db.metrics.SetPinnedRoots(len(db.history.pinnedRoots))
+	return nil
+}
+
+func (db *merkleDB) GetTombstone(_ context.Context, key []byte) (ids.ID, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return ids.Empty, database.ErrClosed
+	}
+
+	rootID, ok := db.tombstones.get(db.toKey(key))
+	if !ok {
+		return ids.Empty, database.ErrNotFound
+	}
+	return rootID, nil
+}
+
 // Assumes [db.commitLock] is read locked.
 // Assumes [db.lock] is not held
 func (db *merkleDB) getRangeProofAtRoot(
@@ -641,11 +1305,13 @@ func (db *merkleDB) getRangeProofAtRoot(
 		return nil, fmt.Errorf("%w but was %d", ErrInvalidMaxLength, maxLength)
 	}
 
+	// The deadline includes the time spent building [historicalView].
+	deadline := db.proofDeadline()
 	historicalView, err := db.getHistoricalViewForRange(rootID, start, end)
 	if err != nil {
 		return nil, err
 	}
-	return historicalView.GetRangeProof(ctx, start, end, maxLength)
+	return historicalView.getRangeProof(ctx, start, end, maxLength, deadline)
 }
 
 func (db *merkleDB) GetChangeProof(
@@ -670,10 +1336,16 @@ func (db *merkleDB) GetChangeProof(
 		return nil, database.ErrClosed
 	}
 
+	deadline := db.proofDeadline()
 	changes, err := db.history.getValueChanges(startRootID, endRootID, start, end, maxLength)
 	if err != nil {
 		return nil, err
 	}
+	// Unlike a range proof, a change proof can't be truncated once the
+	// changes have been gathered because any key may have been changed.
+	if deadlineExceeded(deadline) {
+		return nil, ErrProofDeadlineExceeded
+	}
 
 	// [changedKeys] are a subset of the keys that were added or had their
 	// values modified between [startRootID] to [endRootID] sorted in increasing
@@ -686,6 +1358,9 @@ func (db *merkleDB) GetChangeProof(
 	}
 
 	for _, key := range changedKeys {
+		if err := db.chargeProofWork(ctx, 1); err != nil {
+			return nil, err
+		}
 		change := changes.values[key]
 
 		result.KeyChanges = append(result.KeyChanges, KeyChange{
@@ -706,6 +1381,9 @@ func (db *merkleDB) GetChangeProof(
 	if err != nil {
 		return nil, err
 	}
+	if deadlineExceeded(deadline) {
+		return nil, ErrProofDeadlineExceeded
+	}
 
 	if largestKey.HasValue() {
 		endProof, err := historicalView.getProof(ctx, largestKey.Value())
@@ -762,7 +1440,11 @@ func (db *merkleDB) NewView(
 		return nil, database.ErrClosed
 	}
 
-	newView, err := newTrieView(db, db, changes)
+	if err := db.validateValueChanges(changes); err != nil {
+		return nil, err
+	}
+
+	newView, err := newTrieViewWithMemoryLimit(db, db, changes, db.viewMemoryLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -772,18 +1454,12 @@ func (db *merkleDB) NewView(
 	defer db.lock.Unlock()
 
 	db.childViews = append(db.childViews, newView)
+	db.detectViewLeak(newView)
 	return newView, nil
 }
 
 func (db *merkleDB) Has(k []byte) (bool, error) {
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
-	if db.closed {
-		return false, database.ErrClosed
-	}
-
-	_, err := db.getValueWithoutLock(db.toKey(k))
+	_, err := db.getValue(db.toKey(k))
 	if err == database.ErrNotFound {
 		return false, nil
 	}
@@ -822,6 +1498,17 @@ func (db *merkleDB) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return db.valueNodeDB.newIteratorWithStartAndPrefix(start, prefix)
 }
 
+func (db *merkleDB) NewIteratorAtRoot(rootID ids.ID, start []byte) (database.Iterator, error) {
+	snapshot, err := db.newSnapshotAtRoot(rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	it := snapshot.NewIteratorWithStartAndPrefix(start, nil).(*snapshotIterator)
+	it.ownsSnapshot = true
+	return it, nil
+}
+
 func (db *merkleDB) Put(k, v []byte) error {
 	return db.PutContext(context.Background(), k, v)
 }
@@ -835,7 +1522,12 @@ func (db *merkleDB) PutContext(ctx context.Context, k, v []byte) error {
 		return database.ErrClosed
 	}
 
-	view, err := newTrieView(db, db, ViewChanges{BatchOps: []database.BatchOp{{Key: k, Value: v}}})
+	changes := ViewChanges{BatchOps: []database.BatchOp{{Key: k, Value: v}}}
+	if err := db.validateValueChanges(changes); err != nil {
+		return err
+	}
+
+	view, err := newTrieView(db, db, changes)
 	if err != nil {
 		return err
 	}
@@ -878,7 +1570,12 @@ func (db *merkleDB) commitBatch(ops []database.BatchOp) error {
 		return database.ErrClosed
 	}
 
-	view, err := newTrieView(db, db, ViewChanges{BatchOps: ops, ConsumeBytes: true})
+	changes := ViewChanges{BatchOps: ops, ConsumeBytes: true}
+	if err := db.validateValueChanges(changes); err != nil {
+		return err
+	}
+
+	view, err := newTrieView(db, db, changes)
 	if err != nil {
 		return err
 	}
@@ -907,7 +1604,7 @@ func (db *merkleDB) commitChanges(ctx context.Context, trieToCommit *trieView) e
 	changes := trieToCommit.changes
 	_, span := db.infoTracer.Start(ctx, "MerkleDB.commitChanges", oteltrace.WithAttributes(
 		attribute.Int("nodesChanged", len(changes.nodes)),
-		attribute.Int("valuesChanged", len(changes.values)),
+		attribute.Int("valuesChanged", changes.numValueChanges()),
 	))
 	defer span.End()
 
@@ -925,11 +1622,30 @@ func (db *merkleDB) commitChanges(ctx context.Context, trieToCommit *trieView) e
 	if !ok {
 		return errNoNewRoot
 	}
+	if rootChange.after.id != db.getMerkleRoot() {
+		db.invalidateReadOnlyViews()
+	}
+
+	// Readers continue to read the trie at the current root while the changes
+	// are being written. Once the changes are written, readers are given the
+	// new root.
+	db.publishReadState(changes)
+	defer db.publishReadState(nil)
 
 	currentValueNodeBatch := db.valueNodeDB.NewBatch()
 
+	// Write the nodes in key order so that nodes sharing a prefix, which are
+	// stored next to each other on disk, are written together.
+	keys := maps.Keys(changes.nodes)
+	slices.SortFunc(keys, Key.Less)
+
+	_, encodeSpan := db.infoTracer.Start(ctx, "MerkleDB.commitChanges.encodeNodes")
+	valueNodes := db.encodeNodes(keys, changes)
+	encodeSpan.End()
+
 	_, nodesSpan := db.infoTracer.Start(ctx, "MerkleDB.commitChanges.writeNodes")
-	for key, nodeChange := range changes.nodes {
+	for i, key := range keys {
+		nodeChange := changes.nodes[key]
 		shouldAddIntermediate := nodeChange.after != nil && !nodeChange.after.hasValue()
 		shouldDeleteIntermediate := !shouldAddIntermediate && nodeChange.before != nil && !nodeChange.before.hasValue()
 
@@ -949,7 +1665,11 @@ func (db *merkleDB) commitChanges(ctx context.Context, trieToCommit *trieView) e
 		}
 
 		if shouldAddValue {
-			currentValueNodeBatch.Put(key, nodeChange.after)
+			var valueBytes []byte
+			if valueNodes != nil {
+				valueBytes = valueNodes[i]
+			}
+			currentValueNodeBatch.putEncoded(key, nodeChange.after, valueBytes)
 		} else if shouldDeleteValue {
 			currentValueNodeBatch.Delete(key)
 		}
@@ -967,9 +1687,78 @@ func (db *merkleDB) commitChanges(ctx context.Context, trieToCommit *trieView) e
 	// so that we don't need to clean up on error.
 	db.root = rootChange.after
 	db.history.record(changes)
+	db.tombstones.record(changes)
+	db.stats.record(changes)
+	db.recordGarbage(changes)
 	return nil
 }
 
+// encodeNodes encodes the nodes that [changes] adds, using up to
+// [db.commitConcurrency] goroutines, so that writing them doesn't have to.
+// [keys] must be the sorted keys of [changes.nodes]. They're split into
+// contiguous ranges, so each goroutine encodes nodes that share prefixes.
+//
+// The encodings of nodes without values are cached in the nodes. Returns the
+// bytes to write for each node with a value, at the index of its key in
+// [keys]. Returns nil if the nodes weren't encoded.
+func (db *merkleDB) encodeNodes(keys []Key, changes *changeSummary) [][]byte {
+	numWorkers := db.commitConcurrency
+	if maxWorkers := len(keys) / minNodesPerCommitWorker; numWorkers > maxWorkers {
+		numWorkers = maxWorkers
+	}
+	if numWorkers <= 1 {
+		return nil
+	}
+
+	var (
+		valueNodes = make([][]byte, len(keys))
+		rangeSize  = (len(keys) + numWorkers - 1) / numWorkers
+		wg         sync.WaitGroup
+	)
+	for start := 0; start < len(keys); start += rangeSize {
+		end := start + rangeSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			for i := start; i < end; i++ {
+				n := changes.nodes[keys[i]].after
+				switch {
+				case n == nil:
+				case n.hasValue():
+					// If encoding fails, the node is encoded again when
+					// it's written, which reports the error.
+					if nodeBytes, err := db.valueNodeDB.encode(n); err == nil {
+						valueNodes[i] = nodeBytes
+					}
+				default:
+					_ = n.bytes()
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return valueNodes
+}
+
+// proofDeadline returns the time by which proof generation started now should
+// finish. Returns the zero time if proof generation is unbounded.
+func (db *merkleDB) proofDeadline() time.Time {
+	if db.maxProofDuration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(db.maxProofDuration)
+}
+
+// deadlineExceeded returns true if [deadline] is set and has passed.
+func deadlineExceeded(deadline time.Time) bool {
+	return !deadline.IsZero() && time.Now().After(deadline)
+}
+
 // moveChildViewsToDB removes any child views from the trieToCommit and moves them to the db
 // assumes [db.lock] is held
 func (db *merkleDB) moveChildViewsToDB(trieToCommit *trieView) {
@@ -1229,10 +2018,12 @@ func (db *merkleDB) getKeysNotInSet(start, end maybe.Maybe[[]byte], keySet set.S
 // Returns database.ErrNotFound if the node doesn't exist.
 // Assumes [db.lock] isn't held.
 func (db *merkleDB) getEditableNode(key Key, hasValue bool) (*node, error) {
-	db.lock.RLock()
-	defer db.lock.RUnlock()
-
-	n, err := db.getNode(key, hasValue)
+	var n *node
+	err := db.readCommitted(func(state *readState) error {
+		var err error
+		n, err = state.getNode(db, key, hasValue)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1245,15 +2036,7 @@ func (db *merkleDB) getEditableNode(key Key, hasValue bool) (*node, error) {
 // Returns database.ErrNotFound if the node doesn't exist.
 // Assumes [db.lock] is read locked.
 func (db *merkleDB) getNode(key Key, hasValue bool) (*node, error) {
-	switch {
-	case db.closed:
-		return nil, database.ErrClosed
-	case key == db.rootKey:
-		return db.root, nil
-	case hasValue:
-		return db.valueNodeDB.Get(key)
-	}
-	return db.intermediateNodeDB.Get(key)
+	return db.readState.Load().getNode(db, key, hasValue)
 }
 
 // Returns [key] prefixed by [prefix].
@@ -1284,6 +2067,19 @@ func getBufferFromPool(bufferPool *sync.Pool, size int) []byte {
 	return buffer
 }
 
+// Returns [buffer] with length exactly [size] and every byte zeroed.
+// If [buffer] isn't big enough, a new []byte is allocated.
+func resizeZeroedBuffer(buffer []byte, size int) []byte {
+	if cap(buffer) < size {
+		return make([]byte, size)
+	}
+	buffer = buffer[:size]
+	for i := range buffer {
+		buffer[i] = 0
+	}
+	return buffer
+}
+
 // cacheEntrySize returns a rough approximation of the memory consumed by storing the key and node
 func cacheEntrySize(key Key, n *node) int {
 	if n == nil {
@@ -6,6 +6,8 @@ package merkledb
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -18,6 +20,11 @@ import (
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -26,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/maybe"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/validate"
 )
 
 const defaultHistoryLength = 300
@@ -41,16 +49,46 @@ func newDB(ctx context.Context, db database.Database, config Config) (*merkleDB,
 
 func newDefaultConfig() Config {
 	return Config{
-		EvictionBatchSize:         10,
-		HistoryLength:             defaultHistoryLength,
-		ValueNodeCacheSize:        units.MiB,
-		IntermediateNodeCacheSize: units.MiB,
-		Reg:                       prometheus.NewRegistry(),
-		Tracer:                    trace.Noop,
-		BranchFactor:              BranchFactor16,
+		EvictionBatchSize:             10,
+		HistoryLength:                 defaultHistoryLength,
+		ValueNodeCacheSize:            units.MiB,
+		IntermediateNodeCacheSize:     units.MiB,
+		IntermediateNodeReadCacheSize: units.MiB,
+		Reg:                           prometheus.NewRegistry(),
+		Tracer:                        trace.Noop,
+		BranchFactor:                  BranchFactor16,
 	}
 }
 
+func Test_Config_Verify(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	require.NoError(config.Verify())
+
+	config.BranchFactor = 3
+	config.EvictionBatchSize = config.IntermediateNodeCacheSize + 1
+	config.IntermediateNodeReadCacheSize = 0
+	config.CacheWarmingSize = 1
+	config.MaxProofDuration = -time.Second
+	config.CompactionGarbageRatio = 2
+	config.ValueValidators = []PrefixValidator{{Prefix: []byte{1}}}
+	config.ProofQuotas = []ProofQuota{
+		{Caller: "rpc"},
+		{Caller: "rpc", NodesPerSecond: 1},
+	}
+	err := config.Verify()
+	require.ErrorIs(err, errInvalidBranchFactor)
+	require.ErrorIs(err, validate.ErrOutOfRange)
+
+	var verifyErr *validate.Error
+	require.ErrorAs(err, &verifyErr)
+	require.Len(verifyErr.Errs, 8)
+
+	_, err = New(context.Background(), memdb.New(), config)
+	require.ErrorIs(err, validate.ErrInvalidConfig)
+}
+
 func Test_MerkleDB_Get_Safety(t *testing.T) {
 	require := require.New(t)
 
@@ -163,6 +201,303 @@ func Test_MerkleDB_DB_Load_Root_From_DB(t *testing.T) {
 	require.Equal(root, reloadedRoot)
 }
 
+func Test_MerkleDB_MaxProofDuration(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.MaxProofDuration = time.Nanosecond
+	db, err := newDatabase(
+		context.Background(),
+		memdb.New(),
+		config,
+		&mockMetrics{},
+	)
+	require.NoError(err)
+
+	startRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	for i := 0; i < 100; i++ {
+		key := []byte(strconv.Itoa(i))
+		require.NoError(db.Put(key, key))
+	}
+	endRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// The range proof is truncated but still verifiable.
+	rangeProof, err := db.GetRangeProof(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 100)
+	require.NoError(err)
+	require.NotEmpty(rangeProof.KeyValues)
+	require.Less(len(rangeProof.KeyValues), 100)
+	require.NoError(rangeProof.Verify(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), endRoot))
+
+	_, err = db.GetChangeProof(context.Background(), startRoot, endRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 100)
+	require.ErrorIs(err, ErrProofDeadlineExceeded)
+}
+
+func Test_MerkleDB_MaxCommitDuration(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.MaxCommitDuration = time.Nanosecond
+	metrics := &mockMetrics{}
+	db, err := newDatabase(
+		context.Background(),
+		memdb.New(),
+		config,
+		metrics,
+	)
+	require.NoError(err)
+
+	// The overrun is reported but the value is still committed.
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	require.Equal(int64(1), metrics.commitDeadlineExceeded)
+
+	value, err := db.Get([]byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+}
+
+func Test_MerkleDB_CommitConcurrency(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	serialBaseDB := memdb.New()
+	serialDB, err := newDB(context.Background(), serialBaseDB, newDefaultConfig())
+	require.NoError(err)
+
+	config := newDefaultConfig()
+	config.CommitConcurrency = 4
+	parallelBaseDB := memdb.New()
+	parallelDB, err := newDB(context.Background(), parallelBaseDB, config)
+	require.NoError(err)
+
+	var keys [][]byte
+	for i := 0; i < 5; i++ {
+		ops := make([]database.BatchOp, 0, 2*minNodesPerCommitWorker*config.CommitConcurrency)
+		for j := 0; j < cap(ops)/2; j++ {
+			key := make([]byte, r.Intn(32)+1)
+			_, _ = r.Read(key)
+			keys = append(keys, key)
+			ops = append(ops, database.BatchOp{Key: key, Value: key[:r.Intn(len(key))]})
+		}
+		for j := 0; j < cap(ops)/2; j++ {
+			ops = append(ops, database.BatchOp{
+				Key:    keys[r.Intn(len(keys))],
+				Delete: true,
+			})
+		}
+
+		for _, db := range []*merkleDB{serialDB, parallelDB} {
+			view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+			require.NoError(err)
+			require.NoError(view.CommitToDB(context.Background()))
+		}
+
+		expectedRoot, err := serialDB.GetMerkleRoot(context.Background())
+		require.NoError(err)
+		root, err := parallelDB.GetMerkleRoot(context.Background())
+		require.NoError(err)
+		require.Equal(expectedRoot, root)
+	}
+
+	// The same nodes are written to disk.
+	require.NoError(serialDB.Close())
+	require.NoError(parallelDB.Close())
+
+	expectedIt := serialBaseDB.NewIterator()
+	defer expectedIt.Release()
+	it := parallelBaseDB.NewIterator()
+	defer it.Release()
+	for expectedIt.Next() {
+		require.True(it.Next())
+		require.Equal(expectedIt.Key(), it.Key())
+		require.Equal(expectedIt.Value(), it.Value())
+	}
+	require.False(it.Next())
+	require.NoError(expectedIt.Error())
+	require.NoError(it.Error())
+}
+
+func Test_MerkleDB_PinRoot(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.HistoryLength = 2
+	config.MaxPinnedRoots = 1
+	metrics := &mockMetrics{}
+	db, err := newDatabase(
+		context.Background(),
+		memdb.New(),
+		config,
+		metrics,
+	)
+	require.NoError(err)
+
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	pinnedRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	require.NoError(db.PinRoot(pinnedRoot))
+	require.Equal(1, metrics.pinnedRoots)
+
+	// Only a single root can be pinned.
+	require.NoError(db.Put([]byte("key"), []byte("value1")))
+	otherRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	err = db.PinRoot(otherRoot)
+	require.ErrorIs(err, ErrTooManyPinnedRoots)
+
+	// Pinning the same root again only takes another pin.
+	require.NoError(db.PinRoot(pinnedRoot))
+
+	// Push [pinnedRoot] out of the history window.
+	for i := 2; i < 10; i++ {
+		require.NoError(db.Put([]byte("key"), []byte(fmt.Sprintf("value%d", i))))
+	}
+
+	proof, err := db.GetRangeProofAtRoot(context.Background(), pinnedRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 10)
+	require.NoError(err)
+	require.Len(proof.KeyValues, 1)
+	require.Equal([]byte("value"), proof.KeyValues[0].Value)
+
+	// The root is retained until every pin is released.
+	require.NoError(db.UnpinRoot(pinnedRoot))
+	_, err = db.GetRangeProofAtRoot(context.Background(), pinnedRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 10)
+	require.NoError(err)
+
+	require.NoError(db.UnpinRoot(pinnedRoot))
+	require.Zero(metrics.pinnedRoots)
+	require.Equal(int(config.HistoryLength), db.history.history.Len())
+
+	_, err = db.GetRangeProofAtRoot(context.Background(), pinnedRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 10)
+	require.ErrorIs(err, ErrInsufficientHistory)
+
+	err = db.UnpinRoot(pinnedRoot)
+	require.ErrorIs(err, ErrRootNotPinned)
+
+	// Roots that aren't in the history can't be pinned.
+	err = db.PinRoot(pinnedRoot)
+	require.ErrorIs(err, ErrInsufficientHistory)
+}
+
+// recordingTracer records every span that it ends.
+type recordingTracer struct {
+	oteltrace.Tracer
+	recorder *tracetest.SpanRecorder
+}
+
+func newRecordingTracer() *recordingTracer {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return &recordingTracer{
+		Tracer:   provider.Tracer("test"),
+		recorder: recorder,
+	}
+}
+
+func (*recordingTracer) Close() error {
+	return nil
+}
+
+func Test_MerkleDB_SetTraceLevel(t *testing.T) {
+	require := require.New(t)
+
+	tracer := newRecordingTracer()
+	config := newDefaultConfig()
+	config.TraceLevel = NoTrace
+	config.Tracer = tracer
+	db, err := newDB(context.Background(), memdb.New(), config)
+	require.NoError(err)
+
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	require.Empty(tracer.recorder.Ended())
+
+	// Only info spans are started once the trace level is raised to info.
+	db.SetTraceLevel(InfoTrace)
+	require.Equal(InfoTrace, db.GetTraceLevel())
+
+	require.NoError(db.Put([]byte("key"), []byte("value1")))
+	infoSpans := len(tracer.recorder.Ended())
+	require.Positive(infoSpans)
+
+	_, err = db.GetValue(context.Background(), []byte("key"))
+	require.NoError(err)
+	require.Len(tracer.recorder.Ended(), infoSpans)
+
+	// Debug spans are started once the trace level is lowered to debug.
+	db.SetTraceLevel(DebugTrace)
+	_, err = db.GetValue(context.Background(), []byte("key"))
+	require.NoError(err)
+	spans := tracer.recorder.Ended()
+	require.Len(spans, infoSpans+1)
+	require.Equal("MerkleDB.GetValue", spans[infoSpans].Name())
+
+	require.Equal(DebugTrace, db.GetTraceLevel())
+}
+
+func Test_TraceLevel_JSON(t *testing.T) {
+	require := require.New(t)
+
+	for _, level := range []TraceLevel{DebugTrace, InfoTrace, NoTrace} {
+		levelJSON, err := json.Marshal(level)
+		require.NoError(err)
+
+		var parsedLevel TraceLevel
+		require.NoError(json.Unmarshal(levelJSON, &parsedLevel))
+		require.Equal(level, parsedLevel)
+	}
+
+	var level TraceLevel
+	err := json.Unmarshal([]byte(`"verbose"`), &level)
+	require.ErrorIs(err, ErrUnknownTraceLevel)
+}
+
+func Test_MerkleDB_GetTombstone(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.TombstoneWindow = 2
+	db, err := newDB(context.Background(), memdb.New(), config)
+	require.NoError(err)
+
+	// Keys that never existed don't have tombstones.
+	_, err = db.GetTombstone(context.Background(), []byte("key"))
+	require.ErrorIs(err, database.ErrNotFound)
+
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	require.NoError(db.Put([]byte("other"), []byte("value")))
+	require.NoError(db.Delete([]byte("key")))
+	deletedRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	rootID, err := db.GetTombstone(context.Background(), []byte("key"))
+	require.NoError(err)
+	require.Equal(deletedRoot, rootID)
+
+	// The tombstone is kept for [TombstoneWindow] commits.
+	require.NoError(db.Put([]byte("other"), []byte("value1")))
+	rootID, err = db.GetTombstone(context.Background(), []byte("key"))
+	require.NoError(err)
+	require.Equal(deletedRoot, rootID)
+
+	require.NoError(db.Put([]byte("other"), []byte("value2")))
+	_, err = db.GetTombstone(context.Background(), []byte("key"))
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// Inserting a deleted key again removes its tombstone.
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	require.NoError(db.Delete([]byte("key")))
+	_, err = db.GetTombstone(context.Background(), []byte("key"))
+	require.NoError(err)
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	_, err = db.GetTombstone(context.Background(), []byte("key"))
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
 func Test_MerkleDB_DB_Rebuild(t *testing.T) {
 	require := require.New(t)
 
@@ -492,6 +827,130 @@ func Test_MerkleDB_HealthCheck(t *testing.T) {
 	require.Nil(val)
 }
 
+// Test that reads of committed state don't wait for an in-progress commit and
+// observe the trie as it was before the commit.
+func Test_MerkleDB_Reads_During_Commit(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	writeBasicBatch(t, db)
+	oldRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	view, err := db.NewView(
+		context.Background(),
+		ViewChanges{
+			BatchOps: []database.BatchOp{
+				{Key: []byte{0}, Value: []byte{10}},
+				{Key: []byte{1}, Delete: true},
+				{Key: []byte{5}, Value: []byte{5}},
+			},
+		},
+	)
+	require.NoError(err)
+	viewImpl := view.(*trieView)
+	require.NoError(viewImpl.calculateNodeIDs(context.Background()))
+
+	// Simulate a commit that is in the middle of writing [view]'s changes.
+	db.commitLock.Lock()
+	db.lock.Lock()
+	db.publishReadState(viewImpl.changes)
+	for key, nodeChange := range viewImpl.changes.nodes {
+		if nodeChange.after == nil || !nodeChange.after.hasValue() {
+			continue
+		}
+		batch := db.valueNodeDB.NewBatch()
+		batch.Put(key, nodeChange.after)
+		require.NoError(batch.Write())
+	}
+
+	values, errs := db.GetValues(context.Background(), [][]byte{{0}, {1}, {5}})
+	require.NoError(errs[0])
+	require.Equal([]byte{0}, values[0])
+	require.NoError(errs[1])
+	require.Equal([]byte{1}, values[1])
+	require.ErrorIs(errs[2], database.ErrNotFound)
+
+	has, err := db.Has([]byte{5})
+	require.NoError(err)
+	require.False(has)
+
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(oldRoot, root)
+
+	proof, err := db.GetProof(context.Background(), []byte{0})
+	require.NoError(err)
+	require.Equal([]byte{0}, proof.Value.Value())
+	require.NoError(proof.Verify(context.Background(), oldRoot))
+
+	db.publishReadState(nil)
+	db.lock.Unlock()
+	db.commitLock.Unlock()
+}
+
+func Test_MerkleDB_GetRangeProof_DoesNotWaitForCommit(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	writeBasicBatch(t, db)
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// Simulate a commit that is preparing its changes.
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	proof, err := db.GetRangeProof(context.Background(), maybe.Some([]byte{1}), maybe.Some([]byte{3}), 10)
+	require.NoError(err)
+	require.Len(proof.KeyValues, 3)
+	require.NoError(proof.Verify(context.Background(), maybe.Some([]byte{1}), maybe.Some([]byte{3}), root))
+}
+
+// Test that concurrent readers never observe a partially written commit.
+func Test_MerkleDB_Concurrent_Reads_And_Commits(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	keys := [][]byte{{0}, {1}, {2}}
+	done := make(chan struct{})
+	readErrs := make(chan error, 1)
+	go func() {
+		defer close(readErrs)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			values, errs := db.GetValues(context.Background(), keys)
+			for i := range keys {
+				if !errors.Is(errs[i], errs[0]) || !bytes.Equal(values[i], values[0]) {
+					readErrs <- fmt.Errorf("observed torn read: %v, %v", values, errs)
+					return
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		batch := db.NewBatch()
+		for _, key := range keys {
+			require.NoError(batch.Put(key, []byte{byte(i)}))
+		}
+		require.NoError(batch.Write())
+	}
+	close(done)
+	require.NoError(<-readErrs)
+}
+
 // Test that untracked views aren't tracked in [db.childViews].
 func TestDatabaseNewUntrackedView(t *testing.T) {
 	require := require.New(t)
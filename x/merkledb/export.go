@@ -0,0 +1,435 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+// The version of the format written by Export.
+const exportVersion = 1
+
+var (
+	ErrUnexpectedExportRoot     = errors.New("export has an unexpected root")
+	ErrExportRootMismatch       = errors.New("export's key-value pairs don't match its root")
+	ErrUnsupportedExportVersion = errors.New("unsupported export version")
+
+	errExportBranchFactor  = errors.New("export has a different branch factor than the database")
+	errExportKeysNotSorted = errors.New("export's keys aren't in increasing order")
+
+	errIncompleteExport    = errors.New("export doesn't prove that it holds every key in its range")
+	errUnexpectedTailProof = errors.New("export has an unneeded tail proof")
+)
+
+// ExportRange doesn't wait for in-progress commits.
+// The export is generated against the root that was committed when
+// ExportRange was called, even if other commits finish while it's being
+// generated. Every key-value pair in the range is held in memory.
+func (db *merkleDB) ExportRange(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	w io.Writer,
+) error {
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.release()
+
+	root, err := snapshot.GetMerkleRoot(ctx)
+	if err != nil {
+		return err
+	}
+	// Stream a single proof, which isn't truncated once
+	// [Config.MaxProofDuration] has passed.
+	proof, err := streamSingleRangeProof(ctx, snapshot, start, end)
+	if err != nil {
+		return err
+	}
+	var tailProof *RangeProof
+	if tailStart, ok := exportTailStart(proof, end); ok {
+		tailProof, err = streamSingleRangeProof(ctx, snapshot, maybe.Some(tailStart), end)
+		if err != nil {
+			return err
+		}
+	}
+
+	exportBytes, err := encodeExport(root, db.rootKey.branchFactor, start, end, proof, tailProof)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(exportBytes)
+	return err
+}
+
+// encodeExport returns the encoding of an export, which is:
+//   - The root that it's verified against.
+//   - The branch factor of the trie it was exported from.
+//   - The start and end of its range.
+//   - A range proof of the key-value pairs in the range. Its end proof is
+//     the path to the greatest key in the range, so it doesn't prove that
+//     there are no greater keys in the range.
+//   - If there are keys in the range and the greatest one isn't the end of
+//     the range, a tail proof: a range proof without key-value pairs from the
+//     smallest key greater than the greatest key to the end of the range,
+//     which proves that there are no such keys. Otherwise, Nothing.
+//
+// The proofs are encoded as protobuf messages. [tailProof] is nil if the
+// export doesn't have one.
+func encodeExport(
+	root ids.ID,
+	branchFactor BranchFactor,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	proof *RangeProof,
+	tailProof *RangeProof,
+) ([]byte, error) {
+	proofBytes, err := proto.Marshal(proof.ToProto())
+	if err != nil {
+		return nil, err
+	}
+	tailProofBytes := maybe.Nothing[[]byte]()
+	if tailProof != nil {
+		b, err := proto.Marshal(tailProof.ToProto())
+		if err != nil {
+			return nil, err
+		}
+		tailProofBytes = maybe.Some(b)
+	}
+
+	buf := &bytes.Buffer{}
+	_, _ = buf.Write(root[:])
+	codec.encodeUint(buf, uint64(branchFactor))
+	codec.encodeMaybeByteSlice(buf, start)
+	codec.encodeMaybeByteSlice(buf, end)
+	codec.encodeByteSlice(buf, proofBytes)
+	codec.encodeMaybeByteSlice(buf, tailProofBytes)
+	return buf.Bytes(), nil
+}
+
+// ImportRange reads the whole export from [r] and verifies it before
+// committing anything.
+func (db *merkleDB) ImportRange(ctx context.Context, r io.Reader, expectedRootID ids.ID) error {
+	exportBytes, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	src := bytes.NewReader(exportBytes)
+	root, err := codec.decodeID(src)
+	if err != nil {
+		return err
+	}
+	if root != expectedRootID {
+		return fmt.Errorf("%w: expected %s but got %s", ErrUnexpectedExportRoot, expectedRootID, root)
+	}
+	branchFactor, err := codec.decodeUint(src)
+	if err != nil {
+		return err
+	}
+	if err := BranchFactor(branchFactor).Valid(); err != nil {
+		return err
+	}
+	start, err := codec.decodeMaybeByteSlice(src)
+	if err != nil {
+		return err
+	}
+	end, err := codec.decodeMaybeByteSlice(src)
+	if err != nil {
+		return err
+	}
+	proofBytes, err := codec.decodeByteSlice(src)
+	if err != nil {
+		return err
+	}
+	proof, err := parseExportProof(proofBytes, BranchFactor(branchFactor))
+	if err != nil {
+		return err
+	}
+	tailProofBytes, err := codec.decodeMaybeByteSlice(src)
+	if err != nil {
+		return err
+	}
+	var tailProof *RangeProof
+	if tailProofBytes.HasValue() {
+		tailProof, err = parseExportProof(tailProofBytes.Value(), BranchFactor(branchFactor))
+		if err != nil {
+			return err
+		}
+	}
+	if src.Len() != 0 {
+		return errExtraSpace
+	}
+
+	if err := VerifyRangeProofStateless(proof, start, end, root, BranchFactor(branchFactor)); err != nil {
+		return err
+	}
+	tailStart, needsTailProof := exportTailStart(proof, end)
+	switch {
+	case needsTailProof && tailProof == nil:
+		return errIncompleteExport
+	case !needsTailProof && tailProof != nil:
+		return errUnexpectedTailProof
+	case needsTailProof:
+		if err := VerifyRangeProofStateless(tailProof, maybe.Some(tailStart), end, root, BranchFactor(branchFactor)); err != nil {
+			return err
+		}
+		if len(tailProof.KeyValues) != 0 {
+			return errIncompleteExport
+		}
+	}
+
+	// Keys in the range that aren't in the export are deleted.
+	if err := db.CommitRangeProof(ctx, start, end, proof); err != nil {
+		return err
+	}
+	if needsTailProof {
+		return db.CommitRangeProof(ctx, maybe.Some(tailStart), end, tailProof)
+	}
+	return nil
+}
+
+// streamSingleRangeProof returns a proof of every key-value pair of [trie] in
+// [start, end].
+func streamSingleRangeProof(
+	ctx context.Context,
+	trie ReadOnlyTrie,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+) (*RangeProof, error) {
+	var proof *RangeProof
+	err := trie.StreamRangeProof(ctx, start, end, math.MaxInt, func(p *RangeProof) error {
+		proof = p
+		return nil
+	})
+	return proof, err
+}
+
+// exportTailStart returns the start of the range that the tail proof of an
+// export of the range ending at [end] with [proof] proves empty, and false if
+// the export doesn't need a tail proof.
+func exportTailStart(proof *RangeProof, end maybe.Maybe[[]byte]) ([]byte, bool) {
+	if len(proof.KeyValues) == 0 {
+		return nil, false
+	}
+	greatestKey := proof.KeyValues[len(proof.KeyValues)-1].Key
+	if end.HasValue() && bytes.Equal(greatestKey, end.Value()) {
+		return nil, false
+	}
+	// The smallest key that is greater than [greatestKey].
+	tailStart := make([]byte, len(greatestKey)+1)
+	copy(tailStart, greatestKey)
+	return tailStart, true
+}
+
+func parseExportProof(proofBytes []byte, branchFactor BranchFactor) (*RangeProof, error) {
+	var pbProof pb.RangeProof
+	if err := proto.Unmarshal(proofBytes, &pbProof); err != nil {
+		return nil, err
+	}
+	var proof RangeProof
+	return &proof, proof.UnmarshalProto(&pbProof, branchFactor)
+}
+
+// Export doesn't wait for in-progress commits.
+// The export is of the root that was committed when Export was called, even
+// if other commits finish while it's being written. Unlike ExportRange, the
+// key-value pairs are streamed to [w] rather than held in memory.
+//
+// The export is:
+//   - A header, which holds the version of the format, the root of the trie
+//     and its branch factor.
+//   - A record for each key-value pair, in increasing order of keys. Each
+//     record is prefixed with its length and holds the key and the value.
+//   - A zero length, which marks the end of the export, so that a truncated
+//     export isn't mistaken for a smaller trie.
+func (db *merkleDB) Export(ctx context.Context, w io.Writer) error {
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.release()
+
+	root, err := snapshot.GetMerkleRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	bufWriter := bufio.NewWriter(w)
+	buf := &bytes.Buffer{}
+	codec.encodeUint(buf, exportVersion)
+	_, _ = buf.Write(root[:])
+	codec.encodeUint(buf, uint64(db.rootKey.branchFactor))
+	if _, err := bufWriter.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	it := snapshot.NewIterator()
+	defer it.Release()
+
+	record := &bytes.Buffer{}
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record.Reset()
+		codec.encodeByteSlice(record, it.Key())
+		codec.encodeByteSlice(record, it.Value())
+
+		buf.Reset()
+		codec.encodeByteSlice(buf, record.Bytes())
+		if _, err := bufWriter.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	codec.encodeUint(buf, 0)
+	if _, err := bufWriter.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return bufWriter.Flush()
+}
+
+// Import reads the whole export from [r] and checks that its key-value pairs
+// match its root before committing anything.
+func (db *merkleDB) Import(ctx context.Context, r io.Reader) error {
+	src := bufio.NewReader(r)
+	version, err := readExportUint(src)
+	if err != nil {
+		return err
+	}
+	if version != exportVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedExportVersion, version)
+	}
+	var root ids.ID
+	if _, err := io.ReadFull(src, root[:]); err != nil {
+		return unexpectedEOF(err)
+	}
+	branchFactor, err := readExportUint(src)
+	if err != nil {
+		return err
+	}
+	if BranchFactor(branchFactor) != db.rootKey.branchFactor {
+		return fmt.Errorf("%w: expected %d but got %d", errExportBranchFactor, db.rootKey.branchFactor, branchFactor)
+	}
+
+	var (
+		ops       []database.BatchOp
+		keys      set.Set[string]
+		lastKey   []byte
+		recordBuf = &bytes.Buffer{}
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		length, err := readExportUint(src)
+		if err != nil {
+			return err
+		}
+		if length == 0 {
+			break
+		}
+
+		// Read the record without allocating [length] bytes up front, since
+		// the length may be corrupt.
+		recordBuf.Reset()
+		if _, err := io.CopyN(recordBuf, src, int64(length)); err != nil {
+			return unexpectedEOF(err)
+		}
+		record := bytes.NewReader(recordBuf.Bytes())
+		key, err := codec.decodeByteSlice(record)
+		if err != nil {
+			return err
+		}
+		value, err := codec.decodeByteSlice(record)
+		if err != nil {
+			return err
+		}
+		if record.Len() != 0 {
+			return errExtraSpace
+		}
+		if len(ops) > 0 && bytes.Compare(lastKey, key) >= 0 {
+			return errExportKeysNotSorted
+		}
+		lastKey = key
+
+		keys.Add(string(key))
+		ops = append(ops, database.BatchOp{
+			Key:   key,
+			Value: value,
+		})
+	}
+	if _, err := src.ReadByte(); err != io.EOF {
+		return errExtraSpace
+	}
+
+	db.commitLock.Lock()
+	defer db.commitLock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	// Keys that aren't in the export are deleted.
+	keysToDelete, err := db.getKeysNotInSet(maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), keys)
+	if err != nil {
+		return err
+	}
+	for _, keyToDelete := range keysToDelete {
+		ops = append(ops, database.BatchOp{
+			Key:    keyToDelete,
+			Delete: true,
+		})
+	}
+
+	// Don't need to lock [view] because nobody else has a reference to it.
+	view, err := newTrieView(db, db, ViewChanges{BatchOps: ops})
+	if err != nil {
+		return err
+	}
+	viewRoot, err := view.GetMerkleRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if viewRoot != root {
+		return fmt.Errorf("%w: expected %s but got %s", ErrExportRootMismatch, root, viewRoot)
+	}
+	return view.commitToDB(ctx)
+}
+
+func readExportUint(src *bufio.Reader) (uint64, error) {
+	value, err := binary.ReadUvarint(src)
+	return value, unexpectedEOF(err)
+}
+
+// unexpectedEOF returns [io.ErrUnexpectedEOF] if [err] is [io.EOF], since an
+// export that ends before its end marker is truncated.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+func newExportTestDB(t *testing.T, keys ...string) MerkleDB {
+	db, err := getBasicDB()
+	require.NoError(t, err)
+	for _, key := range keys {
+		require.NoError(t, db.Put([]byte(key), []byte("value "+key)))
+	}
+	return db
+}
+
+func requireKeyValues(t *testing.T, expected, actual database.Iteratee) {
+	expectedIt := expected.NewIterator()
+	defer expectedIt.Release()
+	actualIt := actual.NewIterator()
+	defer actualIt.Release()
+
+	for expectedIt.Next() {
+		require.True(t, actualIt.Next())
+		require.Equal(t, expectedIt.Key(), actualIt.Key())
+		require.Equal(t, expectedIt.Value(), actualIt.Value())
+	}
+	require.False(t, actualIt.Next())
+	require.NoError(t, expectedIt.Error())
+	require.NoError(t, actualIt.Error())
+}
+
+func Test_MerkleDB_ExportRange(t *testing.T) {
+	sourceKeys := []string{"a", "b", "ba", "bb", "c", "d", "e"}
+	tests := []struct {
+		name       string
+		start      maybe.Maybe[[]byte]
+		end        maybe.Maybe[[]byte]
+		targetKeys []string
+		// The key-value pairs of the target once the export is imported.
+		expectedKeys []string
+	}{
+		{
+			name:         "whole trie",
+			targetKeys:   []string{"a", "f"},
+			expectedKeys: sourceKeys,
+		},
+		{
+			name:         "bounded",
+			start:        maybe.Some([]byte("b")),
+			end:          maybe.Some([]byte("cc")),
+			targetKeys:   []string{"a", "b0", "c", "ca", "cc", "d"},
+			expectedKeys: []string{"a", "b", "ba", "bb", "c", "d"},
+		},
+		{
+			name:         "end is a key",
+			start:        maybe.Some([]byte("b")),
+			end:          maybe.Some([]byte("c")),
+			targetKeys:   []string{"b0", "d"},
+			expectedKeys: []string{"b", "ba", "bb", "c", "d"},
+		},
+		{
+			name:         "no end",
+			start:        maybe.Some([]byte("d")),
+			targetKeys:   []string{"a", "dd", "z"},
+			expectedKeys: []string{"a", "d", "e"},
+		},
+		{
+			name:         "empty range",
+			start:        maybe.Some([]byte("bc")),
+			end:          maybe.Some([]byte("bz")),
+			targetKeys:   []string{"a", "bd", "c"},
+			expectedKeys: []string{"a", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			source := newExportTestDB(t, sourceKeys...)
+			root, err := source.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			export := &bytes.Buffer{}
+			require.NoError(source.ExportRange(context.Background(), tt.start, tt.end, export))
+
+			target := newExportTestDB(t, tt.targetKeys...)
+			require.NoError(target.ImportRange(context.Background(), export, root))
+
+			// The keys in the range are the keys of the source, and the
+			// other keys are unchanged.
+			expected := newExportTestDB(t, tt.expectedKeys...)
+			requireKeyValues(t, expected, target)
+		})
+	}
+}
+
+func Test_MerkleDB_ImportRange_Invalid(t *testing.T) {
+	require := require.New(t)
+
+	source := newExportTestDB(t, "a", "b", "c", "d")
+	root, err := source.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	export := &bytes.Buffer{}
+	require.NoError(source.ExportRange(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), export))
+
+	target := newExportTestDB(t, "z")
+	err = target.ImportRange(context.Background(), bytes.NewReader(export.Bytes()), ids.GenerateTestID())
+	require.ErrorIs(err, ErrUnexpectedExportRoot)
+
+	// A truncated proof is valid, but doesn't prove that "c" and "d" aren't
+	// in the trie.
+	proof, err := source.GetRangeProof(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 2)
+	require.NoError(err)
+	exportBytes, err := encodeExport(root, BranchFactor16, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), proof, nil)
+	require.NoError(err)
+	err = target.ImportRange(context.Background(), bytes.NewReader(exportBytes), root)
+	require.ErrorIs(err, errIncompleteExport)
+
+	// Nor does a tail proof that includes them.
+	tailProof, err := source.GetRangeProof(context.Background(), maybe.Some([]byte{'b', 0}), maybe.Nothing[[]byte](), 2)
+	require.NoError(err)
+	exportBytes, err = encodeExport(root, BranchFactor16, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), proof, tailProof)
+	require.NoError(err)
+	err = target.ImportRange(context.Background(), bytes.NewReader(exportBytes), root)
+	require.ErrorIs(err, errIncompleteExport)
+
+	// Nothing was committed.
+	requireKeyValues(t, newExportTestDB(t, "z"), target)
+}
+
+func Test_MerkleDB_Export_Import(t *testing.T) {
+	require := require.New(t)
+
+	source := newExportTestDB(t, "a", "b", "c", "d")
+	require.NoError(source.Put([]byte("empty"), nil))
+	root, err := source.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	export := &bytes.Buffer{}
+	require.NoError(source.Export(context.Background(), export))
+
+	// The export is canonical.
+	otherExport := &bytes.Buffer{}
+	require.NoError(source.Export(context.Background(), otherExport))
+	require.Equal(export.Bytes(), otherExport.Bytes())
+
+	// Keys that aren't in the export are deleted.
+	target := newExportTestDB(t, "a", "z")
+	require.NoError(target.Import(context.Background(), bytes.NewReader(export.Bytes())))
+	requireKeyValues(t, source, target)
+	targetRoot, err := target.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(root, targetRoot)
+
+	// An empty trie can be exported.
+	empty := newExportTestDB(t)
+	export.Reset()
+	require.NoError(empty.Export(context.Background(), export))
+	require.NoError(target.Import(context.Background(), bytes.NewReader(export.Bytes())))
+	requireKeyValues(t, empty, target)
+}
+
+func Test_MerkleDB_Import_Invalid(t *testing.T) {
+	source := newExportTestDB(t, "a", "b", "c", "d")
+	export := &bytes.Buffer{}
+	require.NoError(t, source.Export(context.Background(), export))
+	exportBytes := export.Bytes()
+
+	// The version is the first byte and the root the next [ids.IDLen].
+	wrongVersion := slices.Clone(exportBytes)
+	wrongVersion[0] = exportVersion + 1
+	wrongRoot := slices.Clone(exportBytes)
+	wrongRoot[1]++
+
+	tests := []struct {
+		name        string
+		export      []byte
+		expectedErr error
+	}{
+		{
+			name:        "unsupported version",
+			export:      wrongVersion,
+			expectedErr: ErrUnsupportedExportVersion,
+		},
+		{
+			name:        "wrong root",
+			export:      wrongRoot,
+			expectedErr: ErrExportRootMismatch,
+		},
+		{
+			name:        "truncated",
+			export:      exportBytes[:len(exportBytes)-1],
+			expectedErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name:        "extra bytes",
+			export:      append(slices.Clone(exportBytes), 0),
+			expectedErr: errExtraSpace,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			target := newExportTestDB(t, "z")
+			err := target.Import(context.Background(), bytes.NewReader(tt.export))
+			require.ErrorIs(err, tt.expectedErr)
+
+			// Nothing was committed.
+			requireKeyValues(t, newExportTestDB(t, "z"), target)
+		})
+	}
+}
+
+func Test_MerkleDB_Import_BranchFactorMismatch(t *testing.T) {
+	require := require.New(t)
+
+	source := newExportTestDB(t, "a")
+	export := &bytes.Buffer{}
+	require.NoError(source.Export(context.Background(), export))
+
+	config := newDefaultConfig()
+	config.BranchFactor = BranchFactor4
+	target, err := newDatabase(context.Background(), memdb.New(), config, &mockMetrics{})
+	require.NoError(err)
+	err = target.Import(context.Background(), bytes.NewReader(export.Bytes()))
+	require.ErrorIs(err, errExportBranchFactor)
+}
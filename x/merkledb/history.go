@@ -15,7 +15,11 @@ import (
 	"github.com/ava-labs/avalanchego/utils/set"
 )
 
-var ErrInsufficientHistory = errors.New("insufficient history to generate proof")
+var (
+	ErrInsufficientHistory = errors.New("insufficient history to generate proof")
+	ErrTooManyPinnedRoots  = errors.New("too many pinned roots")
+	ErrRootNotPinned       = errors.New("root not pinned")
+)
 
 // stores previous trie states
 type trieHistory struct {
@@ -27,15 +31,35 @@ type trieHistory struct {
 
 	// Contains the history.
 	// Sorted by increasing order of insertion.
-	// Contains at most [maxHistoryLen] values.
+	// Contains at most [maxHistoryLen] values, unless older values are
+	// retained because a root is pinned.
 	history buffer.Deque[*changeSummaryAndInsertNumber]
 
 	// Each change is tagged with this monotonic increasing number.
 	nextInsertNumber uint64
 
+	// Maximum number of roots in [pinnedRoots].
+	maxPinnedRoots int
+
+	// Root ID --> The pin preventing the changes after the root from being
+	// removed from [history].
+	pinnedRoots map[ids.ID]*pinnedRoot
+
+	// Insert number --> The number of open snapshots that need the changes
+	// with insert numbers >= it to be retained in [history].
+	snapshots map[uint64]int
+
 	toKey func([]byte) Key
 }
 
+type pinnedRoot struct {
+	// The insert number of the change resulting in the root when it was
+	// pinned. Changes with insert numbers >= [insertNumber] are retained.
+	insertNumber uint64
+	// The number of times the root was pinned without being unpinned.
+	count int
+}
+
 // Tracks the beginning and ending state of a value.
 type change[T any] struct {
 	before T
@@ -56,21 +80,57 @@ type changeSummary struct {
 	rootID ids.ID
 	nodes  map[Key]*change[*node]
 	values map[Key]*change[maybe.Maybe[[]byte]]
+
+	// The changes in [nodes] and [values] are allocated from these arenas,
+	// since they're all dropped along with the changeSummary.
+	nodeChanges  arena[change[*node]]
+	valueChanges arena[change[maybe.Maybe[[]byte]]]
+
+	// If non-zero, [values] are moved to [spill] once their estimated size
+	// exceeds [valuesLimit]. See [Config.ViewMemoryLimit].
+	valuesLimit int
+	valuesSize  int
+	// The value changes moved to disk, which aren't in [values].
+	// Nil if none were moved.
+	spill *valueSpill
+	// The database that [values] are spilled to.
+	db *merkleDB
 }
 
 func newChangeSummary(estimatedSize int) *changeSummary {
 	return &changeSummary{
-		nodes:  make(map[Key]*change[*node], estimatedSize),
-		values: make(map[Key]*change[maybe.Maybe[[]byte]], estimatedSize),
+		nodes:        make(map[Key]*change[*node], estimatedSize),
+		values:       make(map[Key]*change[maybe.Maybe[[]byte]], estimatedSize),
+		nodeChanges:  newArena[change[*node]](estimatedSize),
+		valueChanges: newArena[change[maybe.Maybe[[]byte]]](estimatedSize),
 	}
 }
 
-func newTrieHistory(maxHistoryLookback int, toKey func([]byte) Key) *trieHistory {
+// Returns a new change to a node from [before] to [after].
+func (cs *changeSummary) newNodeChange(before, after *node) *change[*node] {
+	c := cs.nodeChanges.new()
+	c.before = before
+	c.after = after
+	return c
+}
+
+// Returns a new change to a value from [before] to [after].
+func (cs *changeSummary) newValueChange(before, after maybe.Maybe[[]byte]) *change[maybe.Maybe[[]byte]] {
+	c := cs.valueChanges.new()
+	c.before = before
+	c.after = after
+	return c
+}
+
+func newTrieHistory(maxHistoryLookback int, maxPinnedRoots int, toKey func([]byte) Key) *trieHistory {
 	return &trieHistory{
-		maxHistoryLen: maxHistoryLookback,
-		history:       buffer.NewUnboundedDeque[*changeSummaryAndInsertNumber](maxHistoryLookback),
-		lastChanges:   make(map[ids.ID]*changeSummaryAndInsertNumber),
-		toKey:         toKey,
+		maxHistoryLen:  maxHistoryLookback,
+		history:        buffer.NewUnboundedDeque[*changeSummaryAndInsertNumber](maxHistoryLookback),
+		lastChanges:    make(map[ids.ID]*changeSummaryAndInsertNumber),
+		maxPinnedRoots: maxPinnedRoots,
+		pinnedRoots:    make(map[ids.ID]*pinnedRoot),
+		snapshots:      make(map[uint64]int),
+		toKey:          toKey,
 	}
 }
 
@@ -181,11 +241,11 @@ func (th *trieHistory) getValueChanges(
 		changes, _ := th.history.Index(i)
 
 		// Add the changes from this commit to [combinedChanges].
-		for key, valueChange := range changes.values {
+		err := changes.forEachValueChange(func(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
 			// The key is outside the range [start, end].
 			if (startKey.HasValue() && key.Less(startKey.Value())) ||
 				(end.HasValue() && key.Greater(endKey.Value())) {
-				continue
+				return nil
 			}
 
 			// A change to this key already exists in [combinedChanges]
@@ -199,12 +259,16 @@ func (th *trieHistory) getValueChanges(
 					changedKeys.Remove(key)
 				}
 			} else {
-				combinedChanges.values[key] = &change[maybe.Maybe[[]byte]]{
-					before: valueChange.before,
-					after:  valueChange.after,
-				}
+				combinedChanges.values[key] = combinedChanges.newValueChange(
+					valueChange.before,
+					valueChange.after,
+				)
 				changedKeys.Add(key)
 			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -253,23 +317,28 @@ func (th *trieHistory) getChangesToGetToRoot(rootID ids.ID, start maybe.Maybe[[]
 		changes, _ := th.history.Index(i)
 
 		for key, changedNode := range changes.nodes {
-			combinedChanges.nodes[key] = &change[*node]{
-				after: changedNode.before,
-			}
+			combinedChanges.nodes[key] = combinedChanges.newNodeChange(
+				nil,
+				changedNode.before,
+			)
 		}
 
-		for key, valueChange := range changes.values {
+		err := changes.forEachValueChange(func(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
 			if (startKey.IsNothing() || !key.Less(startKey.Value())) &&
 				(endKey.IsNothing() || !key.Greater(endKey.Value())) {
 				if existing, ok := combinedChanges.values[key]; ok {
 					existing.after = valueChange.before
 				} else {
-					combinedChanges.values[key] = &change[maybe.Maybe[[]byte]]{
-						before: valueChange.after,
-						after:  valueChange.before,
-					}
+					combinedChanges.values[key] = combinedChanges.newValueChange(
+						valueChange.after,
+						valueChange.before,
+					)
 				}
 			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -278,23 +347,13 @@ func (th *trieHistory) getChangesToGetToRoot(rootID ids.ID, start maybe.Maybe[[]
 
 // record the provided set of changes in the history
 func (th *trieHistory) record(changes *changeSummary) {
-	// we aren't recording history so noop
-	if th.maxHistoryLen == 0 {
+	// we aren't recording history and no snapshot needs the changes so noop,
+	// other than removing the changes retained for removed snapshots
+	if th.maxHistoryLen == 0 && len(th.snapshots) == 0 {
+		th.prune()
 		return
 	}
 
-	if th.history.Len() == th.maxHistoryLen {
-		// This change causes us to go over our lookback limit.
-		// Remove the oldest set of changes.
-		oldestEntry, _ := th.history.PopLeft()
-
-		latestChange := th.lastChanges[oldestEntry.rootID]
-		if latestChange == oldestEntry {
-			// The removed change was the most recent resulting in this root ID.
-			delete(th.lastChanges, oldestEntry.rootID)
-		}
-	}
-
 	changesAndIndex := &changeSummaryAndInsertNumber{
 		changeSummary: changes,
 		insertNumber:  th.nextInsertNumber,
@@ -303,7 +362,186 @@ func (th *trieHistory) record(changes *changeSummary) {
 
 	// Add [changes] to the sorted change list.
 	_ = th.history.PushRight(changesAndIndex)
+	// The spilled value changes are kept until [changes] are pruned.
+	if changes.spill != nil {
+		changes.spill.acquire()
+	}
 
 	// Mark that this is the most recent change resulting in [changes.rootID].
 	th.lastChanges[changes.rootID] = changesAndIndex
+
+	th.prune()
+}
+
+// pin prevents the changes needed to serve proofs for [rootID] from being
+// removed from the history until [rootID] is unpinned.
+// Returns [ErrInsufficientHistory] if [rootID] isn't in the history.
+// Returns [ErrTooManyPinnedRoots] if [maxPinnedRoots] roots are already pinned.
+func (th *trieHistory) pin(rootID ids.ID) error {
+	if pin, ok := th.pinnedRoots[rootID]; ok {
+		pin.count++
+		return nil
+	}
+
+	if len(th.pinnedRoots) >= th.maxPinnedRoots {
+		return fmt.Errorf("%w: maximum is %d", ErrTooManyPinnedRoots, th.maxPinnedRoots)
+	}
+
+	lastChange, ok := th.lastChanges[rootID]
+	if !ok {
+		return fmt.Errorf("%w: root %s not found", ErrInsufficientHistory, rootID)
+	}
+
+	th.pinnedRoots[rootID] = &pinnedRoot{
+		insertNumber: lastChange.insertNumber,
+		count:        1,
+	}
+	return nil
+}
+
+// unpin releases a pin previously taken on [rootID] by [pin].
+// Once every pin on [rootID] is released, changes that are older than the
+// history length and not needed by another pinned root are removed.
+func (th *trieHistory) unpin(rootID ids.ID) error {
+	pin, ok := th.pinnedRoots[rootID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrRootNotPinned, rootID)
+	}
+
+	pin.count--
+	if pin.count > 0 {
+		return nil
+	}
+
+	delete(th.pinnedRoots, rootID)
+	th.prune()
+	return nil
+}
+
+// addSnapshot retains every change recorded from now on until
+// [removeSnapshot] is called with the returned insert number.
+func (th *trieHistory) addSnapshot() uint64 {
+	insertNumber := th.nextInsertNumber
+	th.snapshots[insertNumber]++
+	return insertNumber
+}
+
+// addSnapshotAtRoot retains every change recorded after the last change
+// resulting in [rootID] until [removeSnapshot] is called with the returned
+// insert number.
+// Returns [ErrInsufficientHistory] if [rootID] isn't in the history.
+func (th *trieHistory) addSnapshotAtRoot(rootID ids.ID) (uint64, error) {
+	lastChange, ok := th.lastChanges[rootID]
+	if !ok {
+		return 0, fmt.Errorf("%w: root %s not found", ErrInsufficientHistory, rootID)
+	}
+
+	insertNumber := lastChange.insertNumber + 1
+	th.snapshots[insertNumber]++
+	return insertNumber, nil
+}
+
+// removeSnapshot releases the changes retained by the [addSnapshot] or
+// [addSnapshotAtRoot] call that returned [insertNumber].
+// The released changes are removed by the next call to [prune], which lets
+// snapshots be removed without modifying [history].
+func (th *trieHistory) removeSnapshot(insertNumber uint64) {
+	th.snapshots[insertNumber]--
+	if th.snapshots[insertNumber] == 0 {
+		delete(th.snapshots, insertNumber)
+	}
+}
+
+// getChangesSince returns the changes with insert numbers >= [insertNumber]
+// in the order they were recorded.
+// Assumes the changes with insert numbers >= [insertNumber] are retained.
+func (th *trieHistory) getChangesSince(insertNumber uint64) []*changeSummary {
+	oldestChange, ok := th.history.PeekLeft()
+	if !ok || insertNumber >= th.nextInsertNumber {
+		return nil
+	}
+
+	changes := make([]*changeSummary, 0, th.nextInsertNumber-insertNumber)
+	for i := int(insertNumber - oldestChange.insertNumber); i < th.history.Len(); i++ {
+		change, _ := th.history.Index(i)
+		changes = append(changes, change.changeSummary)
+	}
+	return changes
+}
+
+// getValuesBefore returns the value of every key changed by a change with an
+// insert number >= [insertNumber], before the first of those changes.
+// Assumes the changes with insert numbers >= [insertNumber] are retained.
+func (th *trieHistory) getValuesBefore(insertNumber uint64) (map[Key]maybe.Maybe[[]byte], error) {
+	values := make(map[Key]maybe.Maybe[[]byte])
+	for _, changes := range th.getChangesSince(insertNumber) {
+		err := changes.forEachValueChange(func(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
+			if _, ok := values[key]; !ok {
+				values[key] = valueChange.before
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// getNodeChangeSince returns the first change to the node with [key] that has
+// an insert number >= [insertNumber]. Returns false if the node hasn't
+// changed since.
+// Assumes the changes with insert numbers >= [insertNumber] are retained.
+func (th *trieHistory) getNodeChangeSince(insertNumber uint64, key Key) (*change[*node], bool) {
+	oldestChange, ok := th.history.PeekLeft()
+	if !ok || insertNumber >= th.nextInsertNumber {
+		return nil, false
+	}
+
+	for i := int(insertNumber - oldestChange.insertNumber); i < th.history.Len(); i++ {
+		changes, _ := th.history.Index(i)
+		if nodeChange, ok := changes.nodes[key]; ok {
+			return nodeChange, true
+		}
+	}
+	return nil, false
+}
+
+// prune removes the oldest changes until the history contains at most
+// [maxHistoryLen] changes. Changes that are needed by a pinned root or an
+// open snapshot are never removed.
+func (th *trieHistory) prune() {
+	for th.history.Len() > th.maxHistoryLen {
+		oldestEntry, _ := th.history.PeekLeft()
+		if th.isPinned(oldestEntry.insertNumber) {
+			return
+		}
+
+		// This change causes us to go over our lookback limit.
+		// Remove the oldest set of changes.
+		_, _ = th.history.PopLeft()
+		oldestEntry.releaseSpill()
+
+		latestChange := th.lastChanges[oldestEntry.rootID]
+		if latestChange == oldestEntry {
+			// The removed change was the most recent resulting in this root ID.
+			delete(th.lastChanges, oldestEntry.rootID)
+		}
+	}
+}
+
+// isPinned returns true if the change with [insertNumber] is needed by a
+// pinned root or an open snapshot.
+func (th *trieHistory) isPinned(insertNumber uint64) bool {
+	for _, pin := range th.pinnedRoots {
+		if pin.insertNumber <= insertNumber {
+			return true
+		}
+	}
+	for snapshotInsertNumber := range th.snapshots {
+		if snapshotInsertNumber <= insertNumber {
+			return true
+		}
+	}
+	return false
 }
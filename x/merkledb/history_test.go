@@ -572,7 +572,7 @@ func TestHistoryRecord(t *testing.T) {
 	require := require.New(t)
 
 	maxHistoryLen := 3
-	th := newTrieHistory(maxHistoryLen, func(bytes []byte) Key {
+	th := newTrieHistory(maxHistoryLen, 0, func(bytes []byte) Key {
 		return ToKey(bytes, BranchFactor16)
 	})
 
@@ -647,7 +647,7 @@ func TestHistoryRecord(t *testing.T) {
 
 func TestHistoryGetChangesToRoot(t *testing.T) {
 	maxHistoryLen := 3
-	history := newTrieHistory(maxHistoryLen, func(bytes []byte) Key {
+	history := newTrieHistory(maxHistoryLen, 0, func(bytes []byte) Key {
 		return ToKey(bytes, BranchFactor16)
 	})
 
@@ -774,3 +774,47 @@ func TestHistoryGetChangesToRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestHistorySnapshot(t *testing.T) {
+	require := require.New(t)
+
+	th := newTrieHistory(0, 0, func(bytes []byte) Key {
+		return ToKey(bytes, BranchFactor16)
+	})
+
+	// Without history or snapshots, changes aren't recorded.
+	th.record(&changeSummary{rootID: ids.GenerateTestID()})
+	require.Zero(th.history.Len())
+
+	insertNumber := th.addSnapshot()
+
+	key := ToKey([]byte{1}, BranchFactor16)
+	before := newNode(nil, key)
+	change0 := newChangeSummary(1)
+	change0.rootID = ids.GenerateTestID()
+	change0.nodes[key] = change0.newNodeChange(before, nil)
+	th.record(change0)
+
+	change1 := newChangeSummary(1)
+	change1.rootID = ids.GenerateTestID()
+	change1.nodes[key] = change1.newNodeChange(nil, newNode(nil, key))
+	th.record(change1)
+
+	// The changes are retained for the snapshot.
+	require.Equal(2, th.history.Len())
+	require.Equal([]*changeSummary{change0, change1}, th.getChangesSince(insertNumber))
+
+	// The first change since the snapshot is returned.
+	nodeChange, ok := th.getNodeChangeSince(insertNumber, key)
+	require.True(ok)
+	require.Equal(before, nodeChange.before)
+
+	_, ok = th.getNodeChangeSince(insertNumber, ToKey([]byte{2}, BranchFactor16))
+	require.False(ok)
+
+	// The changes are removed by the next change once the snapshot is removed.
+	th.removeSnapshot(insertNumber)
+	require.Equal(2, th.history.Len())
+	th.record(&changeSummary{rootID: ids.GenerateTestID()})
+	require.Zero(th.history.Len())
+}
@@ -6,14 +6,22 @@ package merkledb
 import (
 	"sync"
 
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/database"
 )
 
 const defaultBufferLength = 256
 
+type evictedNode struct {
+	key  Key
+	node *node
+}
+
 // Holds intermediate nodes. That is, those without values.
 // Changes to this database aren't written to [baseDB] until
 // they're evicted from the [nodeCache] or Flush is called..
+// Nodes read from [baseDB] are cached separately in [readCache].
 type intermediateNodeDB struct {
 	// Holds unused []byte
 	bufferPool *sync.Pool
@@ -28,9 +36,23 @@ type intermediateNodeDB struct {
 	// A non-nil error returned from Put is considered fatal.
 	// Keys in [nodeCache] aren't prefixed with [intermediateNodePrefix].
 	nodeCache onEvictCache[Key, *node]
+	// Holds nodes read from [baseDB] that haven't changed since, most
+	// recently read last. Unlike [nodeCache], its nodes are never written to
+	// [baseDB] when they're evicted. A node is removed from [readCache] when
+	// it's changed in [nodeCache].
+	readCache onEvictCache[Key, *node]
+	// Protects [changes] and the recency of the nodes in [readCache].
+	lock sync.Mutex
+	// The number of times a node has been changed. A node read from [baseDB]
+	// while a node was changed may be stale, so it isn't cached.
+	changes uint64
 	// the number of bytes to evict during an eviction batch
 	evictionBatchSize int
 	metrics           merkleMetrics
+
+	// Compresses the nodes written to [baseDB].
+	// Must be set before the database is used.
+	compression nodeCompression
 }
 
 func newIntermediateNodeDB(
@@ -38,6 +60,7 @@ func newIntermediateNodeDB(
 	bufferPool *sync.Pool,
 	metrics merkleMetrics,
 	size int,
+	readCacheSize int,
 	evictionBatchSize int,
 ) *intermediateNodeDB {
 	result := &intermediateNodeDB{
@@ -51,18 +74,18 @@ func newIntermediateNodeDB(
 		cacheEntrySize,
 		result.onEviction,
 	)
+	result.readCache = newOnEvictCache(
+		readCacheSize,
+		cacheEntrySize,
+		func(Key, *node) error { return nil },
+	)
 	return result
 }
 
 // A non-nil error is considered fatal and closes [db.baseDB].
 func (db *intermediateNodeDB) onEviction(key Key, n *node) error {
-	writeBatch := db.baseDB.NewBatch()
-
+	evicted := []evictedNode{{key: key, node: n}}
 	totalSize := cacheEntrySize(key, n)
-	if err := db.addToBatch(writeBatch, key, n); err != nil {
-		_ = db.baseDB.Close()
-		return err
-	}
 
 	// Evict the oldest [evictionBatchSize] nodes from the cache
 	// and write them to disk. We write a batch of them, rather than
@@ -76,7 +99,19 @@ func (db *intermediateNodeDB) onEviction(key Key, n *node) error {
 			break
 		}
 		totalSize += cacheEntrySize(key, n)
-		if err := db.addToBatch(writeBatch, key, n); err != nil {
+		evicted = append(evicted, evictedNode{key: key, node: n})
+	}
+
+	// The nodes are evicted in the order they were last used, which is
+	// unrelated to where they are stored. Write them in key order so that
+	// nodes sharing a prefix are written together.
+	slices.SortFunc(evicted, func(a, b evictedNode) bool {
+		return a.key.Less(b.key)
+	})
+
+	writeBatch := db.baseDB.NewBatch()
+	for _, e := range evicted {
+		if err := db.addToBatch(writeBatch, e.key, e.node); err != nil {
 			_ = db.baseDB.Close()
 			return err
 		}
@@ -95,11 +130,22 @@ func (db *intermediateNodeDB) addToBatch(b database.Batch, key Key, n *node) err
 	if n == nil {
 		return b.Delete(dbKey)
 	}
-	return b.Put(dbKey, n.bytes())
+	nodeBytes, err := db.compression.compress(n.bytes())
+	if err != nil {
+		return err
+	}
+	return b.Put(dbKey, nodeBytes)
 }
 
 func (db *intermediateNodeDB) Get(key Key) (*node, error) {
-	if cachedValue, isCached := db.nodeCache.Get(key); isCached {
+	// [changes] is read before the caches are checked, so that if [key] is
+	// changed after it isn't found in them, the node read from [db.baseDB]
+	// isn't cached.
+	db.lock.Lock()
+	changes := db.changes
+	db.lock.Unlock()
+
+	if cachedValue, isCached := db.getCached(key); isCached {
 		db.metrics.IntermediateNodeCacheHit()
 		if cachedValue == nil {
 			return nil, database.ErrNotFound
@@ -108,6 +154,41 @@ func (db *intermediateNodeDB) Get(key Key) (*node, error) {
 	}
 	db.metrics.IntermediateNodeCacheMiss()
 
+	n, err := db.read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.changes != changes {
+		return n, nil
+	}
+	return n, db.readCache.Put(key, n)
+}
+
+// getCached returns the node with [key] if it's in [db.nodeCache] or
+// [db.readCache]. A read from [db.readCache] marks the node as the most
+// recently read.
+func (db *intermediateNodeDB) getCached(key Key) (*node, bool) {
+	if n, ok := db.nodeCache.Get(key); ok {
+		return n, true
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	n, ok := db.readCache.Get(key)
+	if ok {
+		// Can't error because [db.readCache] doesn't evict to [db.baseDB].
+		_ = db.readCache.Put(key, n)
+	}
+	return n, ok
+}
+
+// read returns the node with [key] from [db.baseDB] without caching it.
+func (db *intermediateNodeDB) read(key Key) (*node, error) {
 	dbKey := db.constructDBKey(key)
 	db.metrics.DatabaseNodeRead()
 	nodeBytes, err := db.baseDB.Get(dbKey)
@@ -116,6 +197,10 @@ func (db *intermediateNodeDB) Get(key Key) (*node, error) {
 	}
 	db.bufferPool.Put(dbKey)
 
+	nodeBytes, err = db.compression.decompress(nodeBytes)
+	if err != nil {
+		return nil, err
+	}
 	return parseNode(key, nodeBytes)
 }
 
@@ -133,7 +218,9 @@ func (db *intermediateNodeDB) constructDBKey(key Key) []byte {
 }
 
 func (db *intermediateNodeDB) Put(key Key, n *node) error {
-	return db.nodeCache.Put(key, n)
+	err := db.nodeCache.Put(key, n)
+	db.changed(key)
+	return err
 }
 
 func (db *intermediateNodeDB) Flush() error {
@@ -141,5 +228,17 @@ func (db *intermediateNodeDB) Flush() error {
 }
 
 func (db *intermediateNodeDB) Delete(key Key) error {
-	return db.nodeCache.Put(key, nil)
+	err := db.nodeCache.Put(key, nil)
+	db.changed(key)
+	return err
+}
+
+// changed removes [key] from [db.readCache] once it has been changed in
+// [db.nodeCache], and prevents nodes that are being read from being cached.
+func (db *intermediateNodeDB) changed(key Key) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.changes++
+	db.readCache.remove(key)
 }
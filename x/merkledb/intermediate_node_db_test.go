@@ -38,6 +38,7 @@ func Test_IntermediateNodeDB(t *testing.T) {
 		},
 		&mockMetrics{},
 		cacheSize,
+		0,
 		evictionBatchSize,
 	)
 
@@ -141,6 +142,7 @@ func FuzzIntermediateNodeDBConstructDBKey(f *testing.F) {
 		},
 		&mockMetrics{},
 		cacheSize,
+		0,
 		evictionBatchSize,
 	)
 	f.Fuzz(func(
@@ -186,6 +188,7 @@ func Test_IntermediateNodeDB_ConstructDBKey_DirtyBuffer(t *testing.T) {
 		},
 		&mockMetrics{},
 		cacheSize,
+		0,
 		evictionBatchSize,
 	)
 
@@ -213,9 +213,23 @@ func (k Key) bytesNeeded(tokens int) int {
 }
 
 func (k Key) AppendExtend(token byte, extensionKey Key) Key {
+	buffer := make([]byte, k.appendExtendLen(extensionKey))
+	return k.appendExtendIntoBuffer(buffer, token, extensionKey)
+}
+
+// appendExtendLen returns the number of bytes needed to store the result of
+// [k.AppendExtend] with [extensionKey].
+func (k Key) appendExtendLen(extensionKey Key) int {
+	return k.bytesNeeded(k.tokenLength + 1 + extensionKey.tokenLength)
+}
+
+// appendExtendIntoBuffer is [k.AppendExtend] but the returned key is backed by
+// [buffer] rather than a newly allocated []byte, so the key must not be used
+// after [buffer] is modified.
+// Assumes [buffer] is zeroed and has length [k.appendExtendLen(extensionKey)].
+func (k Key) appendExtendIntoBuffer(buffer []byte, token byte, extensionKey Key) Key {
 	appendBytes := k.bytesNeeded(k.tokenLength + 1)
 	totalLength := k.tokenLength + 1 + extensionKey.tokenLength
-	buffer := make([]byte, k.bytesNeeded(totalLength))
 	k.appendIntoBuffer(buffer[:appendBytes], token)
 
 	// the extension path will be shifted based on the number of tokens in the partial byte
@@ -4,6 +4,7 @@
 package merkledb
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -498,6 +499,11 @@ func FuzzKeyAppendExtend(f *testing.F) {
 			for i := 0; i < key2.tokenLength; i++ {
 				require.Equal(key2.Token(i), extendedP.Token(i+1+key1.tokenLength))
 			}
+
+			// Building the key in a reused buffer must give the same key.
+			buffer := bytes.Repeat([]byte{0xFF}, len(extendedP.value))
+			buffer = resizeZeroedBuffer(buffer, key1.appendExtendLen(key2))
+			require.Equal(extendedP, key1.appendExtendIntoBuffer(buffer, token, key2))
 		}
 	})
 }
@@ -28,6 +28,20 @@ type merkleMetrics interface {
 	ViewNodeCacheMiss()
 	ViewValueCacheHit()
 	ViewValueCacheMiss()
+	SetPinnedRoots(count int)
+	ShadowDiverged()
+	ValueFilterSkip()
+	ProofQuotaExceeded()
+	CommitDeadlineExceeded()
+	ValueNodesCompacted()
+	ValueNodeCompactionFailed()
+	SetValueNodeGarbage(bytes uint64)
+	CacheNodePrewarmed()
+	SetCachePrewarmedDepth(depth int)
+	NodeCompressed(uncompressedBytes, compressedBytes int)
+	ViewLeaked()
+	ViewValuesSpilled(count, bytes int)
+	ViewSpillDeletionFailed()
 }
 
 type mockMetrics struct {
@@ -43,6 +57,22 @@ type mockMetrics struct {
 	viewNodeCacheMiss         int64
 	viewValueCacheHit         int64
 	viewValueCacheMiss        int64
+	pinnedRoots               int
+	shadowDivergences         int64
+	valueFilterSkips          int64
+	proofQuotaExceeded        int64
+	commitDeadlineExceeded    int64
+	valueNodeCompactions      int64
+	valueNodeCompactionFails  int64
+	valueNodeGarbage          uint64
+	cacheNodesPrewarmed       int64
+	cachePrewarmedDepth       int
+	nodeUncompressedBytes     int64
+	nodeCompressedBytes       int64
+	viewsLeaked               int64
+	viewValuesSpilled         int64
+	viewValueBytesSpilled     int64
+	viewSpillDeletionFails    int64
 }
 
 func (m *mockMetrics) HashCalculated() {
@@ -122,6 +152,106 @@ func (m *mockMetrics) IntermediateNodeCacheMiss() {
 	m.intermediateNodeCacheMiss++
 }
 
+func (m *mockMetrics) SetPinnedRoots(count int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.pinnedRoots = count
+}
+
+func (m *mockMetrics) ShadowDiverged() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.shadowDivergences++
+}
+
+func (m *mockMetrics) ValueFilterSkip() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.valueFilterSkips++
+}
+
+func (m *mockMetrics) ProofQuotaExceeded() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.proofQuotaExceeded++
+}
+
+func (m *mockMetrics) CommitDeadlineExceeded() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.commitDeadlineExceeded++
+}
+
+func (m *mockMetrics) ValueNodesCompacted() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.valueNodeCompactions++
+}
+
+func (m *mockMetrics) ValueNodeCompactionFailed() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.valueNodeCompactionFails++
+}
+
+func (m *mockMetrics) SetValueNodeGarbage(bytes uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.valueNodeGarbage = bytes
+}
+
+func (m *mockMetrics) CacheNodePrewarmed() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cacheNodesPrewarmed++
+}
+
+func (m *mockMetrics) SetCachePrewarmedDepth(depth int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cachePrewarmedDepth = depth
+}
+
+func (m *mockMetrics) NodeCompressed(uncompressedBytes, compressedBytes int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.nodeUncompressedBytes += int64(uncompressedBytes)
+	m.nodeCompressedBytes += int64(compressedBytes)
+}
+
+func (m *mockMetrics) ViewLeaked() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.viewsLeaked++
+}
+
+func (m *mockMetrics) ViewValuesSpilled(count, bytes int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.viewValuesSpilled += int64(count)
+	m.viewValueBytesSpilled += int64(bytes)
+}
+
+func (m *mockMetrics) ViewSpillDeletionFailed() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.viewSpillDeletionFails++
+}
+
 type metrics struct {
 	ioKeyWrite                prometheus.Counter
 	ioKeyRead                 prometheus.Counter
@@ -134,6 +264,22 @@ type metrics struct {
 	viewNodeCacheMiss         prometheus.Counter
 	viewValueCacheHit         prometheus.Counter
 	viewValueCacheMiss        prometheus.Counter
+	pinnedRoots               prometheus.Gauge
+	shadowDivergences         prometheus.Counter
+	valueFilterSkips          prometheus.Counter
+	proofQuotaExceeded        prometheus.Counter
+	commitDeadlineExceeded    prometheus.Counter
+	valueNodeCompactions      prometheus.Counter
+	valueNodeCompactionFails  prometheus.Counter
+	valueNodeGarbage          prometheus.Gauge
+	cacheNodesPrewarmed       prometheus.Counter
+	cachePrewarmedDepth       prometheus.Gauge
+	nodeUncompressedBytes     prometheus.Counter
+	nodeCompressedBytes       prometheus.Counter
+	viewsLeaked               prometheus.Counter
+	viewValuesSpilled         prometheus.Counter
+	viewValueBytesSpilled     prometheus.Counter
+	viewSpillDeletionFails    prometheus.Counter
 }
 
 func newMetrics(namespace string, reg prometheus.Registerer) (merkleMetrics, error) {
@@ -197,6 +343,86 @@ func newMetrics(namespace string, reg prometheus.Registerer) (merkleMetrics, err
 			Name:      "view_value_cache_miss",
 			Help:      "cumulative amount of misses on the view value cache",
 		}),
+		pinnedRoots: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pinned_roots",
+			Help:      "number of roots whose history is pinned",
+		}),
+		shadowDivergences: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "shadow_divergences",
+			Help:      "cumulative number of times the shadow database diverged from the database",
+		}),
+		valueFilterSkips: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "value_filter_skips",
+			Help:      "cumulative number of value node reads skipped because the key wasn't in the value filter",
+		}),
+		proofQuotaExceeded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "proof_quota_exceeded",
+			Help:      "cumulative number of proofs that failed because their caller exceeded its proof quota",
+		}),
+		commitDeadlineExceeded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commit_deadline_exceeded",
+			Help:      "cumulative number of commits that took longer than the maximum commit duration",
+		}),
+		valueNodeCompactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "value_node_compactions",
+			Help:      "cumulative number of ranges of value nodes compacted to reclaim the space of deleted values",
+		}),
+		valueNodeCompactionFails: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "value_node_compaction_failures",
+			Help:      "cumulative number of failed compactions of ranges of value nodes",
+		}),
+		valueNodeGarbage: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "value_node_garbage_bytes",
+			Help:      "approximate size of the deleted or overwritten value nodes that haven't been compacted",
+		}),
+		cacheNodesPrewarmed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_prewarmed_nodes",
+			Help:      "cumulative number of nodes loaded into the cache by the warm-up after opening",
+		}),
+		cachePrewarmedDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_prewarmed_depth",
+			Help:      "number of levels of the trie below the root loaded into the cache by the warm-up after opening",
+		}),
+		nodeUncompressedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "node_compression_uncompressed_bytes",
+			Help:      "cumulative size of the nodes compressed before they were written to disk",
+		}),
+		nodeCompressedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "node_compression_compressed_bytes",
+			Help:      "cumulative size of the compressed nodes written to disk. The compression ratio is node_compression_uncompressed_bytes / node_compression_compressed_bytes",
+		}),
+		viewsLeaked: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "views_leaked",
+			Help:      "cumulative number of views garbage collected without having been released or committed",
+		}),
+		viewValuesSpilled: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "view_values_spilled",
+			Help:      "cumulative number of value changes of views moved to disk",
+		}),
+		viewValueBytesSpilled: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "view_value_bytes_spilled",
+			Help:      "cumulative number of bytes of value changes of views moved to disk",
+		}),
+		viewSpillDeletionFails: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "view_spill_deletion_failures",
+			Help:      "cumulative number of value changes moved to disk that couldn't be deleted once no longer needed",
+		}),
 	}
 	err := utils.Err(
 		reg.Register(m.ioKeyWrite),
@@ -210,6 +436,22 @@ func newMetrics(namespace string, reg prometheus.Registerer) (merkleMetrics, err
 		reg.Register(m.viewNodeCacheMiss),
 		reg.Register(m.viewValueCacheHit),
 		reg.Register(m.viewValueCacheMiss),
+		reg.Register(m.pinnedRoots),
+		reg.Register(m.shadowDivergences),
+		reg.Register(m.valueFilterSkips),
+		reg.Register(m.proofQuotaExceeded),
+		reg.Register(m.commitDeadlineExceeded),
+		reg.Register(m.valueNodeCompactions),
+		reg.Register(m.valueNodeCompactionFails),
+		reg.Register(m.valueNodeGarbage),
+		reg.Register(m.cacheNodesPrewarmed),
+		reg.Register(m.cachePrewarmedDepth),
+		reg.Register(m.nodeUncompressedBytes),
+		reg.Register(m.nodeCompressedBytes),
+		reg.Register(m.viewsLeaked),
+		reg.Register(m.viewValuesSpilled),
+		reg.Register(m.viewValueBytesSpilled),
+		reg.Register(m.viewSpillDeletionFails),
 	)
 	return &m, err
 }
@@ -257,3 +499,61 @@ func (m *metrics) ValueNodeCacheHit() {
 func (m *metrics) ValueNodeCacheMiss() {
 	m.valueNodeCacheMiss.Inc()
 }
+
+func (m *metrics) SetPinnedRoots(count int) {
+	m.pinnedRoots.Set(float64(count))
+}
+
+func (m *metrics) ShadowDiverged() {
+	m.shadowDivergences.Inc()
+}
+
+func (m *metrics) ValueFilterSkip() {
+	m.valueFilterSkips.Inc()
+}
+
+func (m *metrics) ProofQuotaExceeded() {
+	m.proofQuotaExceeded.Inc()
+}
+
+func (m *metrics) CommitDeadlineExceeded() {
+	m.commitDeadlineExceeded.Inc()
+}
+
+func (m *metrics) ValueNodesCompacted() {
+	m.valueNodeCompactions.Inc()
+}
+
+func (m *metrics) ValueNodeCompactionFailed() {
+	m.valueNodeCompactionFails.Inc()
+}
+
+func (m *metrics) SetValueNodeGarbage(bytes uint64) {
+	m.valueNodeGarbage.Set(float64(bytes))
+}
+
+func (m *metrics) CacheNodePrewarmed() {
+	m.cacheNodesPrewarmed.Inc()
+}
+
+func (m *metrics) SetCachePrewarmedDepth(depth int) {
+	m.cachePrewarmedDepth.Set(float64(depth))
+}
+
+func (m *metrics) NodeCompressed(uncompressedBytes, compressedBytes int) {
+	m.nodeUncompressedBytes.Add(float64(uncompressedBytes))
+	m.nodeCompressedBytes.Add(float64(compressedBytes))
+}
+
+func (m *metrics) ViewLeaked() {
+	m.viewsLeaked.Inc()
+}
+
+func (m *metrics) ViewValuesSpilled(count, bytes int) {
+	m.viewValuesSpilled.Add(float64(count))
+	m.viewValueBytesSpilled.Add(float64(bytes))
+}
+
+func (m *metrics) ViewSpillDeletionFailed() {
+	m.viewSpillDeletionFails.Inc()
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var (
+	errMigrationRootMismatch = errors.New("migrated root doesn't match the reference root")
+	errValueNodeWithoutValue = errors.New("value node has no value")
+)
+
+// MigrateBranchFactor rewrites the trie stored in [db], which must not be
+// open, so that it can be opened with [newFactor] as [Config.BranchFactor].
+// [config] is the config the database is opened with, other than its branch
+// factor. Returns the root of the migrated trie.
+//
+// The key-value pairs are streamed from disk three times, so the migration
+// uses a bounded amount of memory:
+//  1. They're committed to a reference trie with [newFactor], built in
+//     [scratch]. Everything in [scratch] is deleted before and after.
+//  2. Every value node is rewritten in place as a leaf, and the intermediate
+//     nodes are deleted.
+//  3. The trie is rebuilt from the leaves with [newFactor], as it is after an
+//     unclean shutdown.
+//
+// The migrated root must match the reference root.
+// The value of a value node doesn't depend on the branch factor, so if the
+// migration is interrupted, it can be run again.
+func MigrateBranchFactor(
+	ctx context.Context,
+	db database.Database,
+	config Config,
+	newFactor BranchFactor,
+	scratch database.Database,
+) (ids.ID, error) {
+	if err := newFactor.Valid(); err != nil {
+		return ids.Empty, err
+	}
+	config.BranchFactor = newFactor
+	// The migration only opens the database internally.
+	config.Reg = nil
+	config.Shadow = nil
+	config.CacheWarmingSize = 0
+	config.PrewarmCacheOnOpen = false
+
+	compression, err := loadNodeCompression(db, config, &mockMetrics{})
+	if err != nil {
+		return ids.Empty, err
+	}
+	if err := upgradeValueNodeFormat(db, compression); err != nil {
+		return ids.Empty, err
+	}
+	referenceRoot, err := migrationReferenceRoot(ctx, db, compression, config, scratch)
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	// Until the trie is rebuilt, opening the database rebuilds it.
+	if err := db.Put(cleanShutdownKey, didNotHaveCleanShutdown); err != nil {
+		return ids.Empty, err
+	}
+	// The saved state of the old trie may not be valid for the new one.
+	for _, key := range [][]byte{cacheWarmingManifestKey, valueFilterKey, statsKey, compactionKey} {
+		if err := db.Delete(key); err != nil {
+			return ids.Empty, err
+		}
+	}
+	if err := database.ClearPrefix(db, intermediateNodePrefix, rebuildIntermediateDeletionWriteSize); err != nil {
+		return ids.Empty, err
+	}
+	if err := rewriteValueNodesAsLeaves(db, compression, newFactor); err != nil {
+		return ids.Empty, err
+	}
+
+	trieDB, err := newDatabase(ctx, db, config, &mockMetrics{})
+	if err != nil {
+		return ids.Empty, err
+	}
+	root, err := trieDB.GetMerkleRoot(ctx)
+	if err != nil {
+		_ = trieDB.Close()
+		return ids.Empty, err
+	}
+	if err := trieDB.Close(); err != nil {
+		return ids.Empty, err
+	}
+
+	if root != referenceRoot {
+		// Don't trust the migrated trie.
+		if err := db.Put(cleanShutdownKey, didNotHaveCleanShutdown); err != nil {
+			return ids.Empty, err
+		}
+		return ids.Empty, fmt.Errorf("%w: expected %s but got %s", errMigrationRootMismatch, referenceRoot, root)
+	}
+	return root, nil
+}
+
+// migrationReferenceRoot returns the root of the trie with the key-value
+// pairs of [db], whose nodes are compressed with [compression], and
+// [config.BranchFactor], built from nothing in [scratch].
+func migrationReferenceRoot(
+	ctx context.Context,
+	db database.Database,
+	compression nodeCompression,
+	config Config,
+	scratch database.Database,
+) (ids.ID, error) {
+	if err := database.ClearPrefix(scratch, nil, rebuildIntermediateDeletionWriteSize); err != nil {
+		return ids.Empty, err
+	}
+	config.CompactionGarbageRatio = 0
+	referenceDB, err := newDatabase(ctx, scratch, config, &mockMetrics{})
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	var (
+		opsSizeLimit = minRebuildViewSizePerCommit
+		ops          = make([]database.BatchOp, 0, opsSizeLimit)
+	)
+	commit := func() error {
+		view, err := newTrieView(referenceDB, referenceDB, ViewChanges{BatchOps: ops, ConsumeBytes: true})
+		if err != nil {
+			return err
+		}
+		if err := view.commitToDB(ctx); err != nil {
+			return err
+		}
+		ops = make([]database.BatchOp, 0, opsSizeLimit)
+		return nil
+	}
+	err = forEachValue(db, compression, func(key []byte, value []byte) error {
+		ops = append(ops, database.BatchOp{
+			Key:   key,
+			Value: value,
+		})
+		if len(ops) < opsSizeLimit {
+			return nil
+		}
+		return commit()
+	})
+	if err == nil {
+		err = commit()
+	}
+	if err != nil {
+		_ = referenceDB.Close()
+		return ids.Empty, err
+	}
+
+	root, err := referenceDB.GetMerkleRoot(ctx)
+	if err != nil {
+		_ = referenceDB.Close()
+		return ids.Empty, err
+	}
+	if err := referenceDB.Close(); err != nil {
+		return ids.Empty, err
+	}
+	return root, database.ClearPrefix(scratch, nil, rebuildIntermediateDeletionWriteSize)
+}
+
+// rewriteValueNodesAsLeaves replaces every value node of [db] with a node
+// with the same value and no children, which can be parsed with any branch
+// factor. The nodes are compressed with [compression].
+func rewriteValueNodesAsLeaves(db database.Database, compression nodeCompression, branchFactor BranchFactor) error {
+	batch := db.NewBatch()
+	err := forEachValue(db, compression, func(key []byte, value []byte) error {
+		n := newNode(nil, ToKey(key, branchFactor))
+		n.setValue(maybe.Some(value))
+		prefixedKey := make([]byte, 0, len(valueNodePrefix)+len(key))
+		prefixedKey = append(prefixedKey, valueNodePrefix...)
+		prefixedKey = append(prefixedKey, key...)
+		nodeBytes, err := compression.compress(valueNodeBytes(n))
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(prefixedKey, nodeBytes); err != nil {
+			return err
+		}
+		if batch.Size() < rebuildIntermediateDeletionWriteSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// forEachValue calls [f] with every key-value pair stored in the value nodes
+// of [db], whose nodes are compressed with [compression], in order.
+// The value is the first field of an encoded node, so it's read without
+// knowing the branch factor the node was written with.
+func forEachValue(db database.Database, compression nodeCompression, f func(key []byte, value []byte) error) error {
+	it := db.NewIteratorWithPrefix(valueNodePrefix)
+	defer it.Release()
+
+	for it.Next() {
+		nodeBytes, err := compression.decompress(it.Value())
+		if err != nil {
+			return err
+		}
+		if len(nodeBytes) < valueChecksumLen {
+			return io.ErrUnexpectedEOF
+		}
+		value, err := codec.decodeMaybeByteSlice(bytes.NewReader(nodeBytes[:len(nodeBytes)-valueChecksumLen]))
+		if err != nil {
+			return err
+		}
+		if value.IsNothing() {
+			return fmt.Errorf("%w: %x", errValueNodeWithoutValue, it.Key()[valueNodePrefixLen:])
+		}
+		if err := f(slices.Clone(it.Key()[valueNodePrefixLen:]), value.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
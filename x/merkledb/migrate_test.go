@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestMigrateBranchFactor(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	config := newDefaultConfig()
+	config.BranchFactor = BranchFactor16
+	config.Reg = nil
+	baseDB := memdb.New()
+	scratch := memdb.New()
+	db, err := newDB(context.Background(), baseDB, config)
+	require.NoError(err)
+
+	// The empty key has a value, so the root is a value node.
+	values := map[string][]byte{"": {1}}
+	for len(values) < 1000 {
+		key := make([]byte, 1+r.Intn(8))
+		_, _ = r.Read(key)
+		value := make([]byte, 1+r.Intn(64))
+		_, _ = r.Read(value)
+		values[string(key)] = value
+	}
+	ops := make([]database.BatchOp, 0, len(values))
+	for key, value := range values {
+		ops = append(ops, database.BatchOp{
+			Key:   []byte(key),
+			Value: value,
+		})
+	}
+	view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+	oldRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.NoError(db.Close())
+
+	for _, branchFactor := range []BranchFactor{BranchFactor256, BranchFactor4, BranchFactor16} {
+		expectedConfig := newDefaultConfig()
+		expectedConfig.BranchFactor = branchFactor
+		expectedConfig.Reg = nil
+		expectedDB, err := newDB(context.Background(), memdb.New(), expectedConfig)
+		require.NoError(err)
+		view, err := expectedDB.NewView(context.Background(), ViewChanges{BatchOps: ops})
+		require.NoError(err)
+		require.NoError(view.CommitToDB(context.Background()))
+		expectedRoot, err := expectedDB.GetMerkleRoot(context.Background())
+		require.NoError(err)
+
+		root, err := MigrateBranchFactor(context.Background(), baseDB, config, branchFactor, scratch)
+		require.NoError(err)
+		require.Equal(expectedRoot, root)
+
+		db, err := newDB(context.Background(), baseDB, expectedConfig)
+		require.NoError(err)
+		root, err = db.GetMerkleRoot(context.Background())
+		require.NoError(err)
+		require.Equal(expectedRoot, root)
+		for _, op := range ops {
+			value, err := db.Get(op.Key)
+			require.NoError(err)
+			require.Equal(op.Value, value)
+		}
+		require.NoError(db.Close())
+
+		// The reference trie was removed.
+		it := scratch.NewIterator()
+		require.False(it.Next())
+		it.Release()
+	}
+
+	// Migrating back results in the original root.
+	db, err = newDB(context.Background(), baseDB, config)
+	require.NoError(err)
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(oldRoot, root)
+	require.NoError(db.Close())
+
+	_, err = MigrateBranchFactor(context.Background(), baseDB, config, 3, scratch)
+	require.ErrorIs(err, errInvalidBranchFactor)
+}
@@ -9,6 +9,7 @@ package merkledb
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	database "github.com/ava-labs/avalanchego/database"
@@ -40,6 +41,20 @@ func (m *MockMerkleDB) EXPECT() *MockMerkleDBMockRecorder {
 	return m.recorder
 }
 
+// Checkpoint mocks base method.
+func (m *MockMerkleDB) Checkpoint(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockMerkleDBMockRecorder) Checkpoint(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockMerkleDB)(nil).Checkpoint), arg0)
+}
+
 // Close mocks base method.
 func (m *MockMerkleDB) Close() error {
 	m.ctrl.T.Helper()
@@ -110,6 +125,34 @@ func (mr *MockMerkleDBMockRecorder) Delete(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMerkleDB)(nil).Delete), arg0)
 }
 
+// Export mocks base method.
+func (m *MockMerkleDB) Export(arg0 context.Context, arg1 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockMerkleDBMockRecorder) Export(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockMerkleDB)(nil).Export), arg0, arg1)
+}
+
+// ExportRange mocks base method.
+func (m *MockMerkleDB) ExportRange(arg0 context.Context, arg1, arg2 maybe.Maybe[[]uint8], arg3 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportRange indicates an expected call of ExportRange.
+func (mr *MockMerkleDBMockRecorder) ExportRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRange", reflect.TypeOf((*MockMerkleDB)(nil).ExportRange), arg0, arg1, arg2, arg3)
+}
+
 // Get mocks base method.
 func (m *MockMerkleDB) Get(arg0 []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +213,21 @@ func (mr *MockMerkleDBMockRecorder) GetProof(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProof", reflect.TypeOf((*MockMerkleDB)(nil).GetProof), arg0, arg1)
 }
 
+// GetProofs mocks base method.
+func (m *MockMerkleDB) GetProofs(arg0 context.Context, arg1 [][]byte) ([]*Proof, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProofs", arg0, arg1)
+	ret0, _ := ret[0].([]*Proof)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProofs indicates an expected call of GetProofs.
+func (mr *MockMerkleDBMockRecorder) GetProofs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProofs", reflect.TypeOf((*MockMerkleDB)(nil).GetProofs), arg0, arg1)
+}
+
 // GetRangeProof mocks base method.
 func (m *MockMerkleDB) GetRangeProof(arg0 context.Context, arg1, arg2 maybe.Maybe[[]uint8], arg3 int) (*RangeProof, error) {
 	m.ctrl.T.Helper()
@@ -200,6 +258,35 @@ func (mr *MockMerkleDBMockRecorder) GetRangeProofAtRoot(arg0, arg1, arg2, arg3,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRangeProofAtRoot", reflect.TypeOf((*MockMerkleDB)(nil).GetRangeProofAtRoot), arg0, arg1, arg2, arg3, arg4)
 }
 
+// GetTombstone mocks base method.
+func (m *MockMerkleDB) GetTombstone(arg0 context.Context, arg1 []byte) (ids.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTombstone", arg0, arg1)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTombstone indicates an expected call of GetTombstone.
+func (mr *MockMerkleDBMockRecorder) GetTombstone(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTombstone", reflect.TypeOf((*MockMerkleDB)(nil).GetTombstone), arg0, arg1)
+}
+
+// GetTraceLevel mocks base method.
+func (m *MockMerkleDB) GetTraceLevel() TraceLevel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraceLevel")
+	ret0, _ := ret[0].(TraceLevel)
+	return ret0
+}
+
+// GetTraceLevel indicates an expected call of GetTraceLevel.
+func (mr *MockMerkleDBMockRecorder) GetTraceLevel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraceLevel", reflect.TypeOf((*MockMerkleDB)(nil).GetTraceLevel))
+}
+
 // GetValue mocks base method.
 func (m *MockMerkleDB) GetValue(arg0 context.Context, arg1 []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -260,6 +347,34 @@ func (mr *MockMerkleDBMockRecorder) HealthCheck(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockMerkleDB)(nil).HealthCheck), arg0)
 }
 
+// Import mocks base method.
+func (m *MockMerkleDB) Import(arg0 context.Context, arg1 io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockMerkleDBMockRecorder) Import(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockMerkleDB)(nil).Import), arg0, arg1)
+}
+
+// ImportRange mocks base method.
+func (m *MockMerkleDB) ImportRange(arg0 context.Context, arg1 io.Reader, arg2 ids.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportRange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportRange indicates an expected call of ImportRange.
+func (mr *MockMerkleDBMockRecorder) ImportRange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportRange", reflect.TypeOf((*MockMerkleDB)(nil).ImportRange), arg0, arg1, arg2)
+}
+
 // NewBatch mocks base method.
 func (m *MockMerkleDB) NewBatch() database.Batch {
 	m.ctrl.T.Helper()
@@ -288,6 +403,21 @@ func (mr *MockMerkleDBMockRecorder) NewIterator() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewIterator", reflect.TypeOf((*MockMerkleDB)(nil).NewIterator))
 }
 
+// NewIteratorAtRoot mocks base method.
+func (m *MockMerkleDB) NewIteratorAtRoot(arg0 ids.ID, arg1 []byte) (database.Iterator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewIteratorAtRoot", arg0, arg1)
+	ret0, _ := ret[0].(database.Iterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewIteratorAtRoot indicates an expected call of NewIteratorAtRoot.
+func (mr *MockMerkleDBMockRecorder) NewIteratorAtRoot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewIteratorAtRoot", reflect.TypeOf((*MockMerkleDB)(nil).NewIteratorAtRoot), arg0, arg1)
+}
+
 // NewIteratorWithPrefix mocks base method.
 func (m *MockMerkleDB) NewIteratorWithPrefix(arg0 []byte) database.Iterator {
 	m.ctrl.T.Helper()
@@ -330,6 +460,21 @@ func (mr *MockMerkleDBMockRecorder) NewIteratorWithStartAndPrefix(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewIteratorWithStartAndPrefix", reflect.TypeOf((*MockMerkleDB)(nil).NewIteratorWithStartAndPrefix), arg0, arg1)
 }
 
+// NewReadOnlyView mocks base method.
+func (m *MockMerkleDB) NewReadOnlyView(arg0 context.Context, arg1 func()) (ReadOnlyTrie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewReadOnlyView", arg0, arg1)
+	ret0, _ := ret[0].(ReadOnlyTrie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewReadOnlyView indicates an expected call of NewReadOnlyView.
+func (mr *MockMerkleDBMockRecorder) NewReadOnlyView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewReadOnlyView", reflect.TypeOf((*MockMerkleDB)(nil).NewReadOnlyView), arg0, arg1)
+}
+
 // NewView mocks base method.
 func (m *MockMerkleDB) NewView(arg0 context.Context, arg1 ViewChanges) (TrieView, error) {
 	m.ctrl.T.Helper()
@@ -345,6 +490,20 @@ func (mr *MockMerkleDBMockRecorder) NewView(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewView", reflect.TypeOf((*MockMerkleDB)(nil).NewView), arg0, arg1)
 }
 
+// PinRoot mocks base method.
+func (m *MockMerkleDB) PinRoot(arg0 ids.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinRoot", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinRoot indicates an expected call of PinRoot.
+func (mr *MockMerkleDBMockRecorder) PinRoot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinRoot", reflect.TypeOf((*MockMerkleDB)(nil).PinRoot), arg0)
+}
+
 // PrefetchPath mocks base method.
 func (m *MockMerkleDB) PrefetchPath(arg0 []byte) error {
 	m.ctrl.T.Helper()
@@ -387,6 +546,101 @@ func (mr *MockMerkleDBMockRecorder) Put(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockMerkleDB)(nil).Put), arg0, arg1)
 }
 
+// RegisterCommitListener mocks base method.
+func (m *MockMerkleDB) RegisterCommitListener(arg0 CommitListener) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterCommitListener", arg0)
+}
+
+// RegisterCommitListener indicates an expected call of RegisterCommitListener.
+func (mr *MockMerkleDBMockRecorder) RegisterCommitListener(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCommitListener", reflect.TypeOf((*MockMerkleDB)(nil).RegisterCommitListener), arg0)
+}
+
+// ReleaseCheckpoint mocks base method.
+func (m *MockMerkleDB) ReleaseCheckpoint(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseCheckpoint", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseCheckpoint indicates an expected call of ReleaseCheckpoint.
+func (mr *MockMerkleDBMockRecorder) ReleaseCheckpoint(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseCheckpoint", reflect.TypeOf((*MockMerkleDB)(nil).ReleaseCheckpoint), arg0)
+}
+
+// RollbackTo mocks base method.
+func (m *MockMerkleDB) RollbackTo(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackTo", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackTo indicates an expected call of RollbackTo.
+func (mr *MockMerkleDBMockRecorder) RollbackTo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackTo", reflect.TypeOf((*MockMerkleDB)(nil).RollbackTo), arg0)
+}
+
+// SetTraceLevel mocks base method.
+func (m *MockMerkleDB) SetTraceLevel(arg0 TraceLevel) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTraceLevel", arg0)
+}
+
+// SetTraceLevel indicates an expected call of SetTraceLevel.
+func (mr *MockMerkleDBMockRecorder) SetTraceLevel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTraceLevel", reflect.TypeOf((*MockMerkleDB)(nil).SetTraceLevel), arg0)
+}
+
+// Stats mocks base method.
+func (m *MockMerkleDB) Stats(arg0 context.Context) (Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", arg0)
+	ret0, _ := ret[0].(Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockMerkleDBMockRecorder) Stats(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockMerkleDB)(nil).Stats), arg0)
+}
+
+// StreamRangeProof mocks base method.
+func (m *MockMerkleDB) StreamRangeProof(arg0 context.Context, arg1, arg2 maybe.Maybe[[]uint8], arg3 int, arg4 func(*RangeProof) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamRangeProof", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamRangeProof indicates an expected call of StreamRangeProof.
+func (mr *MockMerkleDBMockRecorder) StreamRangeProof(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamRangeProof", reflect.TypeOf((*MockMerkleDB)(nil).StreamRangeProof), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UnpinRoot mocks base method.
+func (m *MockMerkleDB) UnpinRoot(arg0 ids.ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinRoot", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinRoot indicates an expected call of UnpinRoot.
+func (mr *MockMerkleDBMockRecorder) UnpinRoot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinRoot", reflect.TypeOf((*MockMerkleDB)(nil).UnpinRoot), arg0)
+}
+
 // VerifyChangeProof mocks base method.
 func (m *MockMerkleDB) VerifyChangeProof(arg0 context.Context, arg1 *ChangeProof, arg2, arg3 maybe.Maybe[[]uint8], arg4 ids.ID) error {
 	m.ctrl.T.Helper()
@@ -401,6 +655,20 @@ func (mr *MockMerkleDBMockRecorder) VerifyChangeProof(arg0, arg1, arg2, arg3, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChangeProof", reflect.TypeOf((*MockMerkleDB)(nil).VerifyChangeProof), arg0, arg1, arg2, arg3, arg4)
 }
 
+// WriteCheckpoint mocks base method.
+func (m *MockMerkleDB) WriteCheckpoint(arg0 context.Context, arg1 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteCheckpoint indicates an expected call of WriteCheckpoint.
+func (mr *MockMerkleDBMockRecorder) WriteCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteCheckpoint", reflect.TypeOf((*MockMerkleDB)(nil).WriteCheckpoint), arg0, arg1)
+}
+
 // getEditableNode mocks base method.
 func (m *MockMerkleDB) getEditableNode(arg0 Key, arg1 bool) (*node, error) {
 	m.ctrl.T.Helper()
@@ -4,6 +4,9 @@
 package merkledb
 
 import (
+	"bytes"
+	"sync"
+
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
@@ -14,6 +17,14 @@ import (
 
 const HashLength = 32
 
+// The encoded hash values of a node are only needed until its ID is
+// calculated, so the buffers they're encoded into are reused.
+var hashValuesBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
 // the values that go into the node's id
 type hashValues struct {
 	Children map[byte]child
@@ -47,7 +58,8 @@ type node struct {
 func newNode(parent *node, key Key) *node {
 	newNode := &node{
 		dbNode: dbNode{
-			children: make(map[byte]child, key.branchFactor),
+			// Most new nodes are leaves, so the map isn't presized.
+			children: make(map[byte]child),
 		},
 		key: key,
 	}
@@ -101,12 +113,15 @@ func (n *node) calculateID(metrics merkleMetrics) {
 	}
 
 	metrics.HashCalculated()
-	bytes := codec.encodeHashValues(&hashValues{
+	buf := hashValuesBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	codec.encodeHashValues(buf, &hashValues{
 		Children: n.children,
 		Value:    n.valueDigest,
 		Key:      n.key,
 	})
-	n.id = hashing.ComputeHash256Array(bytes)
+	n.id = hashing.ComputeHash256Array(buf.Bytes())
+	hashValuesBufferPool.Put(buf)
 }
 
 // Set [n]'s value to [val].
@@ -0,0 +1,168 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/zstd"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var (
+	_ NodeCompressor = (*zstdNodeCompressor)(nil)
+
+	nodeCompressionDictionaryKey = []byte(string(metadataPrefix) + "nodeCompressionDictionary")
+
+	ErrNodeCompressionMismatch = errors.New("node compression doesn't match the database")
+
+	errNodeCompression   = errors.New("couldn't compress node")
+	errNodeDecompression = errors.New("couldn't decompress node")
+)
+
+// NodeCompressor compresses the nodes that are written to the base database.
+// It must be safe for concurrent use.
+type NodeCompressor interface {
+	Compress(b []byte) ([]byte, error)
+	Decompress(b []byte) ([]byte, error)
+}
+
+// NodeCompressorFactory returns a [NodeCompressor] that compresses with
+// [dictionary], which is empty if no dictionary is used.
+type NodeCompressorFactory func(dictionary []byte) (NodeCompressor, error)
+
+type zstdNodeCompressor struct {
+	// nil if no dictionary is used
+	processor *zstd.BulkProcessor
+}
+
+// NewZstdNodeCompressor returns a [NodeCompressor] that compresses with zstd.
+// If [dictionary] isn't empty, it's a dictionary trained on a sample of the
+// database's nodes, for example with `zstd --train`, or raw content that
+// nodes are likely to share, such as common key prefixes.
+func NewZstdNodeCompressor(dictionary []byte) (NodeCompressor, error) {
+	if len(dictionary) == 0 {
+		return &zstdNodeCompressor{}, nil
+	}
+	processor, err := zstd.NewBulkProcessor(dictionary, zstd.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdNodeCompressor{
+		processor: processor,
+	}, nil
+}
+
+func (c *zstdNodeCompressor) Compress(b []byte) ([]byte, error) {
+	if c.processor == nil {
+		return zstd.Compress(nil, b)
+	}
+	return c.processor.Compress(nil, b)
+}
+
+func (c *zstdNodeCompressor) Decompress(b []byte) ([]byte, error) {
+	if c.processor == nil {
+		return zstd.Decompress(nil, b)
+	}
+	return c.processor.Decompress(nil, b)
+}
+
+// nodeCompression compresses the nodes written to the base database. The zero
+// value doesn't compress them.
+type nodeCompression struct {
+	compressor NodeCompressor
+	metrics    merkleMetrics
+}
+
+// loadNodeCompression returns the compression of the nodes stored in [db].
+//
+// The dictionary that nodes are compressed with is stored in [db] when
+// compression is enabled, so it can't change afterwards. Compression can only
+// be enabled on a database without nodes, because the nodes already on disk
+// aren't compressed.
+func loadNodeCompression(db database.Database, config Config, metrics merkleMetrics) (nodeCompression, error) {
+	dictionary, err := db.Get(nodeCompressionDictionaryKey)
+	switch err {
+	case nil:
+		if config.NodeCompression == nil {
+			return nodeCompression{}, fmt.Errorf("%w: nodes are compressed but NodeCompression is nil", ErrNodeCompressionMismatch)
+		}
+		if len(config.NodeCompressionDictionary) > 0 && !bytes.Equal(dictionary, config.NodeCompressionDictionary) {
+			return nodeCompression{}, fmt.Errorf("%w: nodes are compressed with a different dictionary", ErrNodeCompressionMismatch)
+		}
+	case database.ErrNotFound:
+		if config.NodeCompression == nil {
+			return nodeCompression{}, nil
+		}
+		hasNodes, err := hasStoredNodes(db)
+		if err != nil {
+			return nodeCompression{}, err
+		}
+		if hasNodes {
+			return nodeCompression{}, fmt.Errorf("%w: nodes aren't compressed", ErrNodeCompressionMismatch)
+		}
+		dictionary = config.NodeCompressionDictionary
+		if dictionary == nil {
+			dictionary = []byte{}
+		}
+		if err := db.Put(nodeCompressionDictionaryKey, dictionary); err != nil {
+			return nodeCompression{}, err
+		}
+	default:
+		return nodeCompression{}, err
+	}
+
+	compressor, err := config.NodeCompression(dictionary)
+	if err != nil {
+		return nodeCompression{}, err
+	}
+	return nodeCompression{
+		compressor: compressor,
+		metrics:    metrics,
+	}, nil
+}
+
+// hasStoredNodes returns true if [db] has any value or intermediate nodes.
+func hasStoredNodes(db database.Database) (bool, error) {
+	for _, prefix := range [][]byte{valueNodePrefix, intermediateNodePrefix} {
+		it := db.NewIteratorWithPrefix(prefix)
+		hasNode := it.Next()
+		err := it.Error()
+		it.Release()
+		if err != nil || hasNode {
+			return hasNode, err
+		}
+	}
+	return false, nil
+}
+
+// compress returns the bytes written to the base database for the node
+// encoded as [b].
+func (c nodeCompression) compress(b []byte) ([]byte, error) {
+	if c.compressor == nil {
+		return b, nil
+	}
+	compressed, err := c.compressor.Compress(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errNodeCompression, err)
+	}
+	c.metrics.NodeCompressed(len(b), len(compressed))
+	return compressed, nil
+}
+
+// decompress returns the encoding of the node whose bytes in the base
+// database are [b].
+func (c nodeCompression) decompress(b []byte) ([]byte, error) {
+	if c.compressor == nil {
+		return b, nil
+	}
+	decompressed, err := c.compressor.Decompress(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errNodeDecompression, err)
+	}
+	return decompressed, nil
+}
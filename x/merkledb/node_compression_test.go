@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+const (
+	numNodeCompressionKeys   = 200
+	nodeCompressionKeyPrefix = "accounts/0x0123456789abcdef0123456789abcdef/storage/"
+)
+
+var nodeCompressionDictionary = []byte(nodeCompressionKeyPrefix)
+
+func newNodeCompressionConfig(dictionary []byte) Config {
+	config := newDefaultConfig()
+	config.NodeCompression = NewZstdNodeCompressor
+	config.NodeCompressionDictionary = dictionary
+	// Evict intermediate nodes to disk.
+	config.IntermediateNodeCacheSize = 1024
+	return config
+}
+
+func putNodeCompressionKeys(t *testing.T, db *merkleDB) {
+	for i := 0; i < numNodeCompressionKeys; i++ {
+		key := []byte(nodeCompressionKeyPrefix + strconv.Itoa(i))
+		require.NoError(t, db.Put(key, key))
+	}
+}
+
+func requireNodeCompressionKeys(t *testing.T, db *merkleDB) {
+	require := require.New(t)
+
+	for i := 0; i < numNodeCompressionKeys; i++ {
+		key := []byte(nodeCompressionKeyPrefix + strconv.Itoa(i))
+		value, err := db.Get(key)
+		require.NoError(err)
+		require.Equal(key, value)
+	}
+}
+
+func Test_MerkleDB_NodeCompression(t *testing.T) {
+	for _, dictionary := range [][]byte{nil, nodeCompressionDictionary} {
+		t.Run(strconv.Itoa(len(dictionary)), func(t *testing.T) {
+			require := require.New(t)
+
+			baseDB := memdb.New()
+			metrics := &mockMetrics{}
+			db, err := newDatabase(context.Background(), baseDB, newNodeCompressionConfig(dictionary), metrics)
+			require.NoError(err)
+			putNodeCompressionKeys(t, db)
+			root, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.NoError(db.Close())
+
+			require.Positive(metrics.nodeCompressedBytes)
+			if len(dictionary) > 0 {
+				// Small nodes only shrink with a dictionary.
+				require.Less(metrics.nodeCompressedBytes, metrics.nodeUncompressedBytes)
+			}
+
+			storedDictionary, err := baseDB.Get(nodeCompressionDictionaryKey)
+			require.NoError(err)
+			require.Equal(len(dictionary), len(storedDictionary))
+
+			// The nodes on disk are compressed.
+			compressor, err := NewZstdNodeCompressor(dictionary)
+			require.NoError(err)
+			it := baseDB.NewIteratorWithPrefix(valueNodePrefix)
+			require.True(it.Next())
+			nodeBytes, err := compressor.Decompress(it.Value())
+			require.NoError(err)
+			require.NotEqual(it.Value(), nodeBytes)
+			_, err = parseNode(ToKey(it.Key()[valueNodePrefixLen:], BranchFactor16), nodeBytes[:len(nodeBytes)-valueChecksumLen])
+			require.NoError(err)
+			it.Release()
+
+			// The stored dictionary is used if none is given.
+			db, err = newDatabase(context.Background(), baseDB, newNodeCompressionConfig(nil), &mockMetrics{})
+			require.NoError(err)
+			requireNodeCompressionKeys(t, db)
+			reopenedRoot, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(root, reopenedRoot)
+			require.NoError(db.Close())
+		})
+	}
+}
+
+func Test_MerkleDB_NodeCompression_Mismatch(t *testing.T) {
+	require := require.New(t)
+
+	uncompressedDB := memdb.New()
+	db, err := newDatabase(context.Background(), uncompressedDB, newDefaultConfig(), &mockMetrics{})
+	require.NoError(err)
+	putNodeCompressionKeys(t, db)
+	require.NoError(db.Close())
+
+	// Compression can't be enabled on a database with nodes.
+	_, err = newDatabase(context.Background(), uncompressedDB, newNodeCompressionConfig(nil), &mockMetrics{})
+	require.ErrorIs(err, ErrNodeCompressionMismatch)
+
+	compressedDB := memdb.New()
+	db, err = newDatabase(context.Background(), compressedDB, newNodeCompressionConfig(nodeCompressionDictionary), &mockMetrics{})
+	require.NoError(err)
+	putNodeCompressionKeys(t, db)
+	require.NoError(db.Close())
+
+	// Compression can't be disabled.
+	_, err = newDatabase(context.Background(), compressedDB, newDefaultConfig(), &mockMetrics{})
+	require.ErrorIs(err, ErrNodeCompressionMismatch)
+
+	// The dictionary can't change.
+	_, err = newDatabase(context.Background(), compressedDB, newNodeCompressionConfig([]byte("other dictionary")), &mockMetrics{})
+	require.ErrorIs(err, ErrNodeCompressionMismatch)
+}
+
+func Test_MerkleDB_NodeCompression_MigrateBranchFactor(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	config := newNodeCompressionConfig(nodeCompressionDictionary)
+	db, err := newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+	putNodeCompressionKeys(t, db)
+	require.NoError(db.Close())
+
+	_, err = MigrateBranchFactor(context.Background(), baseDB, config, BranchFactor4, memdb.New())
+	require.NoError(err)
+
+	config.BranchFactor = BranchFactor4
+	db, err = newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+	requireNodeCompressionKeys(t, db)
+	require.NoError(db.Close())
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var ErrInvalidWorkers = errors.New("number of workers must be greater than 0")
+
+// RangeProofToVerify is a range proof along with the arguments to verify it
+// with. See [RangeProof.Verify].
+type RangeProofToVerify struct {
+	Proof          *RangeProof
+	Start          maybe.Maybe[[]byte]
+	End            maybe.Maybe[[]byte]
+	ExpectedRootID ids.ID
+}
+
+// ChangeProofToVerify is a change proof along with the arguments to verify it
+// with. See [ChangeProofer.VerifyChangeProof].
+type ChangeProofToVerify struct {
+	Proof             *ChangeProof
+	Start             maybe.Maybe[[]byte]
+	End               maybe.Maybe[[]byte]
+	ExpectedEndRootID ids.ID
+}
+
+// VerifyProofs verifies each of [proofs] against [expectedRootID] using up to
+// [workers] goroutines.
+// Returns nil iff every proof is valid. Otherwise, returns the error of the
+// first proof found to be invalid, and the remaining proofs aren't verified.
+func VerifyProofs(ctx context.Context, proofs []*Proof, expectedRootID ids.ID, workers int) error {
+	return verifyInParallel(ctx, len(proofs), workers, func(ctx context.Context, i int) error {
+		return proofs[i].Verify(ctx, expectedRootID)
+	})
+}
+
+// VerifyRangeProofs verifies each of [proofs] using up to [workers]
+// goroutines.
+// Returns nil iff every proof is valid. Otherwise, returns the error of the
+// first proof found to be invalid, and the remaining proofs aren't verified.
+func VerifyRangeProofs(ctx context.Context, proofs []RangeProofToVerify, workers int) error {
+	return verifyInParallel(ctx, len(proofs), workers, func(ctx context.Context, i int) error {
+		proof := proofs[i]
+		return proof.Proof.Verify(ctx, proof.Start, proof.End, proof.ExpectedRootID)
+	})
+}
+
+// VerifyChangeProofs verifies each of [proofs] against [db] using up to
+// [workers] goroutines.
+// Returns nil iff every proof is valid. Otherwise, returns the error of the
+// first proof found to be invalid, and the remaining proofs aren't verified.
+func VerifyChangeProofs(ctx context.Context, db ChangeProofer, proofs []ChangeProofToVerify, workers int) error {
+	return verifyInParallel(ctx, len(proofs), workers, func(ctx context.Context, i int) error {
+		proof := proofs[i]
+		return db.VerifyChangeProof(ctx, proof.Proof, proof.Start, proof.End, proof.ExpectedEndRootID)
+	})
+}
+
+// verifyInParallel calls [verify] for each index in [0, numProofs) using up
+// to [workers] goroutines. Once any call fails, [ctx] passed to [verify] is
+// canceled and no further calls are started.
+func verifyInParallel(
+	ctx context.Context,
+	numProofs int,
+	workers int,
+	verify func(ctx context.Context, i int) error,
+) error {
+	if workers <= 0 {
+		return fmt.Errorf("%w but was %d", ErrInvalidWorkers, workers)
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workers)
+	for i := 0; i < numProofs; i++ {
+		// Don't start verifying more proofs once one has failed.
+		if egCtx.Err() != nil {
+			break
+		}
+
+		i := i
+		eg.Go(func() error {
+			if err := egCtx.Err(); err != nil {
+				return err
+			}
+			if err := verify(egCtx, i); err != nil {
+				return fmt.Errorf("proof %d: %w", i, err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	// [egCtx] is canceled by Wait, so the parent context is checked to report
+	// cancellation that happened before any proof failed.
+	return ctx.Err()
+}
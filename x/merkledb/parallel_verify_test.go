@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+func TestVerifyProofsInParallel(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	keys := [][]byte{{0}, {1}, {2}, {3}, {4}}
+	for _, key := range keys {
+		require.NoError(db.Put(key, key))
+	}
+	startRootID, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	proofs := make([]*Proof, len(keys))
+	rangeProofs := make([]RangeProofToVerify, len(keys))
+	for i, key := range keys {
+		proofs[i], err = db.GetProof(context.Background(), key)
+		require.NoError(err)
+
+		rangeProof, err := db.GetRangeProof(context.Background(), maybe.Some(key), maybe.Some(key), 1)
+		require.NoError(err)
+		rangeProofs[i] = RangeProofToVerify{
+			Proof:          rangeProof,
+			Start:          maybe.Some(key),
+			End:            maybe.Some(key),
+			ExpectedRootID: startRootID,
+		}
+	}
+
+	require.NoError(db.Put([]byte{5}, []byte{5}))
+	endRootID, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	changeProof, err := db.GetChangeProof(context.Background(), startRootID, endRootID, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 10)
+	require.NoError(err)
+
+	// Change proofs are verified against a db at the start root.
+	verifierDB, err := getBasicDB()
+	require.NoError(err)
+	for _, key := range keys {
+		require.NoError(verifierDB.Put(key, key))
+	}
+	changeProofs := []ChangeProofToVerify{{
+		Proof:             changeProof,
+		Start:             maybe.Nothing[[]byte](),
+		End:               maybe.Nothing[[]byte](),
+		ExpectedEndRootID: endRootID,
+	}}
+
+	for _, workers := range []int{1, 2, len(keys) + 1} {
+		require.NoError(VerifyProofs(context.Background(), proofs, startRootID, workers))
+		require.NoError(VerifyRangeProofs(context.Background(), rangeProofs, workers))
+		require.NoError(VerifyChangeProofs(context.Background(), verifierDB, changeProofs, workers))
+	}
+
+	err = VerifyProofs(context.Background(), proofs, endRootID, 2)
+	require.ErrorIs(err, ErrInvalidProof)
+
+	rangeProofs[3].ExpectedRootID = ids.GenerateTestID()
+	err = VerifyRangeProofs(context.Background(), rangeProofs, 2)
+	require.ErrorIs(err, ErrInvalidProof)
+	require.ErrorContains(err, "proof 3")
+
+	emptyDB, err := newDB(context.Background(), memdb.New(), newDefaultConfig())
+	require.NoError(err)
+	err = VerifyChangeProofs(context.Background(), emptyDB, changeProofs, 2)
+	require.ErrorIs(err, ErrInvalidProof)
+
+	err = VerifyProofs(context.Background(), proofs, startRootID, 0)
+	require.ErrorIs(err, ErrInvalidWorkers)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = VerifyProofs(ctx, proofs, startRootID, 2)
+	require.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,350 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// The version of the format written by WriteCheckpoint.
+	portableCheckpointVersion = 1
+
+	// The number of bytes of nodes cached while opening a database from a
+	// checkpoint, and the number of bytes of value nodes written at once.
+	checkpointWriteSize = units.MiB
+)
+
+var (
+	ErrCheckpointTargetNotEmpty       = errors.New("database to open from a checkpoint isn't empty")
+	ErrCheckpointCorrupted            = errors.New("checkpoint is corrupted")
+	ErrUnsupportedCheckpointVersion   = errors.New("unsupported checkpoint version")
+	errCheckpointBranchFactorMismatch = errors.New("checkpoint has a different branch factor than the config")
+	errCheckpointUnexpectedNode       = errors.New("checkpoint has a node that isn't a child of a previous node")
+	errCheckpointMissingNodes         = errors.New("checkpoint is missing nodes")
+	errCheckpointNodeCountMismatch    = errors.New("checkpoint has a different number of nodes than it records")
+	errOpenedCheckpointRoot           = errors.New("database opened from a checkpoint has an unexpected root")
+)
+
+// WriteCheckpoint doesn't wait for in-progress commits.
+// The checkpoint is of the root that was committed when WriteCheckpoint was
+// called, even if other commits finish while it's being written. The nodes
+// are streamed to [w], one path of the trie at a time.
+//
+// The checkpoint is:
+//   - A header, which holds the version of the format, the root of the trie
+//     and its branch factor.
+//   - A record for each node of the trie, in increasing order of keys. Each
+//     record is prefixed with its length and holds the node's key and its
+//     encoding, which includes its value and the IDs of its children. Nodes
+//     are written uncompressed and without checksums.
+//   - A zero length, which marks the end of the records.
+//   - The number of nodes and the number of nodes with a value, so that a
+//     checkpoint can be checked without opening it.
+func (db *merkleDB) WriteCheckpoint(ctx context.Context, w io.Writer) error {
+	snapshot, err := db.newSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.release()
+
+	bufWriter := bufio.NewWriter(w)
+	buf := &bytes.Buffer{}
+	codec.encodeUint(buf, portableCheckpointVersion)
+	_, _ = buf.Write(snapshot.rootID[:])
+	codec.encodeUint(buf, uint64(db.rootKey.branchFactor))
+	if _, err := bufWriter.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	var (
+		numNodes  uint64
+		numValues uint64
+		record    = &bytes.Buffer{}
+	)
+	err = snapshot.forEachNode(ctx, func(n *node) error {
+		numNodes++
+		if n.hasValue() {
+			numValues++
+		}
+
+		record.Reset()
+		codec.encodeKey(record, n.key)
+		_, _ = record.Write(n.bytes())
+
+		buf.Reset()
+		codec.encodeByteSlice(buf, record.Bytes())
+		_, err := bufWriter.Write(buf.Bytes())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	buf.Reset()
+	codec.encodeUint(buf, 0)
+	codec.encodeUint(buf, numNodes)
+	codec.encodeUint(buf, numValues)
+	if _, err := bufWriter.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return bufWriter.Flush()
+}
+
+// forEachNode calls [f] with every node of the trie in increasing order of
+// keys. Only the children of the nodes on the path to the current node are
+// held in memory. [f] must not modify the node.
+// Assumes [s.db.lock] isn't held.
+func (s *snapshot) forEachNode(ctx context.Context, f func(n *node) error) error {
+	type nodeRef struct {
+		key      Key
+		hasValue bool
+	}
+	// The nodes left to visit, the next of which is last.
+	stack := []nodeRef{{key: s.db.rootKey}}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ref := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		s.db.lock.RLock()
+		n, err := s.getNode(ref.key, ref.hasValue)
+		s.db.lock.RUnlock()
+		if err != nil {
+			return err
+		}
+		if err := f(n); err != nil {
+			return err
+		}
+
+		// Children are visited in increasing index order, which is increasing
+		// key order, so they're pushed in the reverse order.
+		for index := int(s.db.rootKey.branchFactor) - 1; index >= 0; index-- {
+			entry, ok := n.children[byte(index)]
+			if !ok {
+				continue
+			}
+			stack = append(stack, nodeRef{
+				key:      n.key.AppendExtend(byte(index), entry.compressedKey),
+				hasValue: entry.hasValue,
+			})
+		}
+	}
+	return nil
+}
+
+// OpenFromCheckpoint writes the trie of a checkpoint written by
+// WriteCheckpoint to [db], which must be empty, and opens it with [config].
+// [db] may use a different backend than the database the checkpoint was
+// written from, and the nodes are compressed according to [config].
+//
+// The ID of every node is checked against the ID its parent records as the
+// nodes are read, starting from the checkpoint's root, so a corrupted
+// checkpoint is rejected without recomputing the trie. If it's rejected, or
+// writing fails, [db] may hold part of the trie and should be discarded.
+//
+// Returns [ErrCheckpointTargetNotEmpty] if [db] isn't empty.
+// Returns [ErrCheckpointCorrupted] if the nodes don't match the root.
+func OpenFromCheckpoint(ctx context.Context, db database.Database, config Config, r io.Reader) (MerkleDB, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	isEmpty, err := database.IsEmpty(db)
+	if err != nil {
+		return nil, err
+	}
+	if !isEmpty {
+		return nil, ErrCheckpointTargetNotEmpty
+	}
+
+	// Until every node is written, opening [db] rebuilds the trie from the
+	// value nodes that were written, rather than trusting a partial trie.
+	if err := db.Put(cleanShutdownKey, didNotHaveCleanShutdown); err != nil {
+		return nil, err
+	}
+	root, err := writeCheckpointNodes(ctx, db, config, r)
+	if err != nil {
+		return nil, err
+	}
+	// The database is opened as if it was just created, so the state that
+	// isn't in the checkpoint, like the node statistics, is computed from
+	// the nodes.
+	if err := db.Delete(cleanShutdownKey); err != nil {
+		return nil, err
+	}
+
+	trieDB, err := New(ctx, db, config)
+	if err != nil {
+		return nil, err
+	}
+	openedRoot, err := trieDB.GetMerkleRoot(ctx)
+	if err != nil {
+		_ = trieDB.Close()
+		return nil, err
+	}
+	if openedRoot != root {
+		_ = trieDB.Close()
+		return nil, fmt.Errorf("%w: expected %s but got %s", errOpenedCheckpointRoot, root, openedRoot)
+	}
+	return trieDB, nil
+}
+
+// writeCheckpointNodes writes the nodes of the checkpoint read from [r] to
+// [db] as they're stored by a database opened with [config], and returns the
+// checkpoint's root.
+func writeCheckpointNodes(ctx context.Context, db database.Database, config Config, r io.Reader) (ids.ID, error) {
+	src := bufio.NewReader(r)
+	version, err := readExportUint(src)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if version != portableCheckpointVersion {
+		return ids.Empty, fmt.Errorf("%w: %d", ErrUnsupportedCheckpointVersion, version)
+	}
+	var root ids.ID
+	if _, err := io.ReadFull(src, root[:]); err != nil {
+		return ids.Empty, unexpectedEOF(err)
+	}
+	branchFactor, err := readExportUint(src)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if BranchFactor(branchFactor) != config.BranchFactor {
+		return ids.Empty, fmt.Errorf("%w: expected %d but got %d", errCheckpointBranchFactorMismatch, config.BranchFactor, branchFactor)
+	}
+
+	metrics := &mockMetrics{}
+	compression, err := loadNodeCompression(db, config, metrics)
+	if err != nil {
+		return ids.Empty, err
+	}
+	// The database is empty, so this only records that the value nodes are
+	// written with checksums.
+	if err := upgradeValueNodeFormat(db, compression); err != nil {
+		return ids.Empty, err
+	}
+	bufferPool := &sync.Pool{
+		New: func() interface{} {
+			return make([]byte, 0, defaultBufferLength)
+		},
+	}
+	valueNodeDB := newValueNodeDB(db, bufferPool, metrics, checkpointWriteSize, config.BranchFactor, false)
+	valueNodeDB.compression = compression
+	intermediateNodeDB := newIntermediateNodeDB(db, bufferPool, metrics, checkpointWriteSize, 0, checkpointWriteSize)
+	intermediateNodeDB.compression = compression
+
+	var (
+		rootKey = ToKey(nil, config.BranchFactor)
+		// The IDs of the nodes that are children of nodes that were read, but
+		// weren't read yet.
+		expectedIDs = map[Key]ids.ID{
+			rootKey: root,
+		}
+		numNodes       uint64
+		numValues      uint64
+		valueBatch     = valueNodeDB.NewBatch()
+		valueBatchSize int
+		recordBuf      = &bytes.Buffer{}
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return ids.Empty, err
+		}
+		length, err := readExportUint(src)
+		if err != nil {
+			return ids.Empty, err
+		}
+		if length == 0 {
+			break
+		}
+
+		// Read the record without allocating [length] bytes up front, since
+		// the length may be corrupt.
+		recordBuf.Reset()
+		if _, err := io.CopyN(recordBuf, src, int64(length)); err != nil {
+			return ids.Empty, unexpectedEOF(err)
+		}
+		record := bytes.NewReader(recordBuf.Bytes())
+		key, err := codec.decodeKey(record, config.BranchFactor)
+		if err != nil {
+			return ids.Empty, err
+		}
+		// The node keeps a reference to its bytes.
+		nodeBytes := make([]byte, record.Len())
+		_, _ = record.Read(nodeBytes)
+		n, err := parseNode(key, nodeBytes)
+		if err != nil {
+			return ids.Empty, err
+		}
+
+		expectedID, ok := expectedIDs[key]
+		if !ok {
+			return ids.Empty, fmt.Errorf("%w: %w: %x", ErrCheckpointCorrupted, errCheckpointUnexpectedNode, key.Bytes())
+		}
+		delete(expectedIDs, key)
+		n.calculateID(metrics)
+		if n.id != expectedID {
+			return ids.Empty, fmt.Errorf("%w: node %x has ID %s but %s was expected", ErrCheckpointCorrupted, key.Bytes(), n.id, expectedID)
+		}
+		for index, entry := range n.children {
+			expectedIDs[key.AppendExtend(index, entry.compressedKey)] = entry.id
+		}
+
+		numNodes++
+		if !n.hasValue() {
+			if err := intermediateNodeDB.Put(key, n); err != nil {
+				return ids.Empty, err
+			}
+			continue
+		}
+		numValues++
+		valueBatch.Put(key, n)
+		valueBatchSize += len(nodeBytes)
+		if valueBatchSize >= checkpointWriteSize {
+			if err := valueBatch.Write(); err != nil {
+				return ids.Empty, err
+			}
+			valueBatch = valueNodeDB.NewBatch()
+			valueBatchSize = 0
+		}
+	}
+	if len(expectedIDs) != 0 {
+		return ids.Empty, fmt.Errorf("%w: %w: %d nodes", ErrCheckpointCorrupted, errCheckpointMissingNodes, len(expectedIDs))
+	}
+	expectedNumNodes, err := readExportUint(src)
+	if err != nil {
+		return ids.Empty, err
+	}
+	expectedNumValues, err := readExportUint(src)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if numNodes != expectedNumNodes || numValues != expectedNumValues {
+		return ids.Empty, fmt.Errorf(
+			"%w: %w: read %d nodes and %d values but expected %d and %d",
+			ErrCheckpointCorrupted, errCheckpointNodeCountMismatch,
+			numNodes, numValues, expectedNumNodes, expectedNumValues,
+		)
+	}
+	if _, err := src.ReadByte(); err != io.EOF {
+		return ids.Empty, errExtraSpace
+	}
+
+	if err := valueBatch.Write(); err != nil {
+		return ids.Empty, err
+	}
+	return root, intermediateNodeDB.Flush()
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newCheckpointTestDB(t *testing.T) MerkleDB {
+	keys := []string{""}
+	for i := 0; i < 500; i++ {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	return newExportTestDB(t, keys...)
+}
+
+func TestOpenFromCheckpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		source func(t *testing.T) MerkleDB
+		config func() Config
+	}{
+		{
+			name:   "empty trie",
+			source: func(t *testing.T) MerkleDB { return newExportTestDB(t) },
+			config: newDefaultConfig,
+		},
+		{
+			name:   "same config",
+			source: newCheckpointTestDB,
+			config: newDefaultConfig,
+		},
+		{
+			name:   "compressed nodes",
+			source: newCheckpointTestDB,
+			config: func() Config {
+				config := newDefaultConfig()
+				config.NodeCompression = NewZstdNodeCompressor
+				return config
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			source := test.source(t)
+			checkpoint := &bytes.Buffer{}
+			require.NoError(source.WriteCheckpoint(context.Background(), checkpoint))
+
+			opened, err := OpenFromCheckpoint(context.Background(), memdb.New(), test.config(), checkpoint)
+			require.NoError(err)
+
+			expectedRoot, err := source.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			root, err := opened.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(expectedRoot, root)
+			requireKeyValues(t, source, opened)
+
+			expectedStats, err := source.Stats(context.Background())
+			require.NoError(err)
+			stats, err := opened.Stats(context.Background())
+			require.NoError(err)
+			require.Equal(expectedStats.NodeCount, stats.NodeCount)
+			require.Equal(expectedStats.ValueCount, stats.ValueCount)
+
+			// The opened database can be committed to.
+			require.NoError(opened.Put([]byte("new key"), []byte("new value")))
+			require.NoError(source.Put([]byte("new key"), []byte("new value")))
+			expectedRoot, err = source.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			root, err = opened.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(expectedRoot, root)
+		})
+	}
+}
+
+func TestOpenFromCheckpointInvalid(t *testing.T) {
+	source := newCheckpointTestDB(t)
+	checkpointBuf := &bytes.Buffer{}
+	require.NoError(t, source.WriteCheckpoint(context.Background(), checkpointBuf))
+	checkpoint := checkpointBuf.Bytes()
+
+	// Flips a bit in the value of the last node, which is a leaf, so its
+	// value is followed by a single byte holding its number of children.
+	corrupted := slices.Clone(checkpoint)
+	corrupted[len(corrupted)-checkpointTrailerLen(t, checkpoint)-2] ^= 1
+
+	nonEmpty := memdb.New()
+	require.NoError(t, nonEmpty.Put([]byte("key"), []byte("value")))
+
+	tests := []struct {
+		name        string
+		db          func() *memdb.Database
+		config      func() Config
+		checkpoint  []byte
+		expectedErr error
+	}{
+		{
+			name:        "target isn't empty",
+			db:          func() *memdb.Database { return nonEmpty },
+			config:      newDefaultConfig,
+			checkpoint:  checkpoint,
+			expectedErr: ErrCheckpointTargetNotEmpty,
+		},
+		{
+			name:        "truncated",
+			db:          memdb.New,
+			config:      newDefaultConfig,
+			checkpoint:  checkpoint[:len(checkpoint)/2],
+			expectedErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name:        "corrupted",
+			db:          memdb.New,
+			config:      newDefaultConfig,
+			checkpoint:  corrupted,
+			expectedErr: ErrCheckpointCorrupted,
+		},
+		{
+			name: "different branch factor",
+			db:   memdb.New,
+			config: func() Config {
+				config := newDefaultConfig()
+				config.BranchFactor = BranchFactor256
+				return config
+			},
+			checkpoint:  checkpoint,
+			expectedErr: errCheckpointBranchFactorMismatch,
+		},
+		{
+			name:        "unsupported version",
+			db:          memdb.New,
+			config:      newDefaultConfig,
+			checkpoint:  append([]byte{portableCheckpointVersion + 1}, checkpoint[1:]...),
+			expectedErr: ErrUnsupportedCheckpointVersion,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := OpenFromCheckpoint(context.Background(), test.db(), test.config(), bytes.NewReader(test.checkpoint))
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+// checkpointTrailerLen returns the number of bytes after the last record of
+// [checkpoint].
+func checkpointTrailerLen(t *testing.T, checkpoint []byte) int {
+	trailer := &bytes.Buffer{}
+	codec.encodeUint(trailer, 0)
+	// The number of nodes and values can't be read from the end, so they're
+	// counted by opening the checkpoint.
+	opened, err := OpenFromCheckpoint(context.Background(), memdb.New(), newDefaultConfig(), bytes.NewReader(checkpoint))
+	require.NoError(t, err)
+	stats, err := opened.Stats(context.Background())
+	require.NoError(t, err)
+	codec.encodeUint(trailer, stats.NodeCount)
+	codec.encodeUint(trailer, stats.ValueCount)
+	return trailer.Len()
+}
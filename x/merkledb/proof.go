@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"math"
 
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
@@ -54,6 +57,7 @@ var (
 	ErrNilValue                    = errors.New("value is nil")
 	ErrUnexpectedEndProof          = errors.New("end proof should be empty")
 	ErrInconsistentBranchFactor    = errors.New("all keys in proof nodes should have the same branch factor")
+	ErrProofRegenerationRequired   = errors.New("proof can't be updated and must be regenerated")
 )
 
 type ProofNode struct {
@@ -196,6 +200,80 @@ func (proof *Proof) Verify(ctx context.Context, expectedRootID ids.ID) error {
 	return nil
 }
 
+// Update returns a proof of [proof.Key] in the trie with root [endRootID],
+// given that [proof] is valid for some start root and [changeProof] contains
+// the changes between the start root and [endRootID].
+//
+// Nodes in [proof.Path] are replaced by the nodes in [changeProof]'s boundary
+// proofs with the same key. Nodes that aren't in the boundary proofs are kept
+// if no key in [changeProof.KeyChanges] is below them, except for the node of
+// [proof.Key] if it was a leaf and its value was deleted.
+//
+// Returns [ErrProofRegenerationRequired] if [proof.Key]'s path was affected by
+// changes that [changeProof] doesn't include the new nodes for. In that case
+// a new proof must be requested.
+func (proof *Proof) Update(ctx context.Context, changeProof *ChangeProof, endRootID ids.ID) (*Proof, error) {
+	switch {
+	case changeProof == nil:
+		return nil, ErrNilChangeProof
+	case len(proof.Path) == 0:
+		return nil, ErrNoProof
+	}
+
+	// The nodes in the trie with root [endRootID] that are known to be on
+	// [proof.Key]'s path.
+	endNodes := make(map[Key]ProofNode)
+	for _, nodes := range [][]ProofNode{changeProof.StartProof, changeProof.EndProof} {
+		for _, node := range nodes {
+			if proof.Key.HasPrefix(node.Key) {
+				endNodes[node.Key] = node
+			}
+		}
+	}
+
+	updated := &Proof{
+		Path:  make([]ProofNode, 0, len(proof.Path)+len(endNodes)),
+		Key:   proof.Key,
+		Value: proof.Value,
+	}
+	changedKeys := make([]Key, len(changeProof.KeyChanges))
+	for i, change := range changeProof.KeyChanges {
+		changedKeys[i] = ToKey(change.Key, proof.Key.branchFactor)
+		if changedKeys[i] == proof.Key {
+			updated.Value = change.Value
+		}
+	}
+
+	for _, node := range proof.Path {
+		if _, ok := endNodes[node.Key]; ok {
+			continue
+		}
+		// A leaf whose value was deleted is no longer in the trie.
+		if node.Key == proof.Key && len(node.Children) == 0 && updated.Value.IsNothing() && proof.Value.HasValue() {
+			continue
+		}
+		for _, changedKey := range changedKeys {
+			if changedKey.HasPrefix(node.Key) {
+				return nil, fmt.Errorf("%w: node %x was modified", ErrProofRegenerationRequired, node.Key.Bytes())
+			}
+		}
+		updated.Path = append(updated.Path, node)
+	}
+	for _, node := range endNodes {
+		updated.Path = append(updated.Path, node)
+	}
+	slices.SortFunc(updated.Path, func(a, b ProofNode) bool {
+		return a.Key.tokenLength < b.Key.tokenLength
+	})
+
+	// [changeProof] may not contain every change, so the updated proof must be
+	// verified.
+	if err := updated.Verify(ctx, endRootID); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProofRegenerationRequired, err)
+	}
+	return updated, nil
+}
+
 func (proof *Proof) ToProto() *pb.Proof {
 	value := &pb.MaybeBytes{
 		Value:     proof.Value.Value(),
@@ -282,140 +360,25 @@ type RangeProof struct {
 //
 //	If [start] is Nothing, all keys are considered > [start].
 //	If [end] is Nothing, all keys are considered < [end].
+//
+// The branch factor of the trie is assumed to be the branch factor of the
+// keys of [proof]. See [VerifyRangeProofStateless] to require one.
 func (proof *RangeProof) Verify(
-	ctx context.Context,
+	_ context.Context,
 	start maybe.Maybe[[]byte],
 	end maybe.Maybe[[]byte],
 	expectedRootID ids.ID,
 ) error {
-	switch {
-	case start.HasValue() && end.HasValue() && bytes.Compare(start.Value(), end.Value()) > 0:
-		return ErrStartAfterEnd
-	case len(proof.KeyValues) == 0 && len(proof.StartProof) == 0 && len(proof.EndProof) == 0:
-		return ErrNoMerkleProof
-	case end.IsNothing() && len(proof.KeyValues) == 0 && len(proof.StartProof) > 0 && len(proof.EndProof) != 0:
-		return ErrUnexpectedEndProof
-	case end.IsNothing() && len(proof.KeyValues) == 0 && len(proof.StartProof) == 0 && len(proof.EndProof) != 1:
-		return ErrShouldJustBeRoot
-	case len(proof.EndProof) == 0 && (end.HasValue() || len(proof.KeyValues) > 0):
-		return ErrNoEndProof
-	}
-
-	// determine branch factor based on proof paths
+	// Invariants checked by [VerifyRangeProofStateless] prevent both the start
+	// proof and the end proof from being empty.
 	var branchFactor BranchFactor
-	if len(proof.StartProof) > 0 {
+	switch {
+	case len(proof.StartProof) > 0:
 		branchFactor = proof.StartProof[0].Key.branchFactor
-	} else {
-		// safe because invariants prevent both start proof and end proof from being empty at the same time
+	case len(proof.EndProof) > 0:
 		branchFactor = proof.EndProof[0].Key.branchFactor
 	}
-
-	// Make sure the key-value pairs are sorted and in [start, end].
-	if err := verifyKeyValues(proof.KeyValues, start, end); err != nil {
-		return err
-	}
-
-	// [proof] allegedly provides and proves all key-value
-	// pairs in [smallestProvenPath, largestProvenPath].
-	// If [smallestProvenPath] is Nothing, [proof] should
-	// provide and prove all keys < [largestProvenPath].
-	// If [largestProvenPath] is Nothing, [proof] should
-	// provide and prove all keys > [smallestProvenPath].
-	// If both are Nothing, [proof] should prove the entire trie.
-	smallestProvenPath := maybe.Bind(start, func(b []byte) Key {
-		return ToKey(b, branchFactor)
-	})
-
-	largestProvenPath := maybe.Bind(end, func(b []byte) Key {
-		return ToKey(b, branchFactor)
-	})
-	if len(proof.KeyValues) > 0 {
-		// If [proof] has key-value pairs, we should insert children
-		// greater than [largestProvenPath] to ancestors of the node containing
-		// [largestProvenPath] so that we get the expected root ID.
-		largestProvenPath = maybe.Some(ToKey(proof.KeyValues[len(proof.KeyValues)-1].Key, branchFactor))
-	}
-
-	// The key-value pairs (allegedly) proven by [proof].
-	keyValues := make(map[Key][]byte, len(proof.KeyValues))
-	for _, keyValue := range proof.KeyValues {
-		keyValues[ToKey(keyValue.Key, branchFactor)] = keyValue.Value
-	}
-
-	// Ensure that the start proof is valid and contains values that
-	// match the key/values that were sent.
-	if err := verifyProofPath(proof.StartProof, smallestProvenPath); err != nil {
-		return err
-	}
-	if err := verifyAllRangeProofKeyValuesPresent(
-		proof.StartProof,
-		smallestProvenPath,
-		largestProvenPath,
-		keyValues,
-	); err != nil {
-		return err
-	}
-
-	// Ensure that the end proof is valid and contains values that
-	// match the key/values that were sent.
-	if err := verifyProofPath(proof.EndProof, largestProvenPath); err != nil {
-		return err
-	}
-	if err := verifyAllRangeProofKeyValuesPresent(
-		proof.EndProof,
-		smallestProvenPath,
-		largestProvenPath,
-		keyValues,
-	); err != nil {
-		return err
-	}
-
-	// Insert all key-value pairs into the trie.
-	ops := make([]database.BatchOp, len(proof.KeyValues))
-	for i, kv := range proof.KeyValues {
-		ops[i] = database.BatchOp{
-			Key:   kv.Key,
-			Value: kv.Value,
-		}
-	}
-
-	// Don't need to lock [view] because nobody else has a reference to it.
-	view, err := getStandaloneTrieView(ctx, ops, branchFactor)
-	if err != nil {
-		return err
-	}
-
-	// For all the nodes along the edges of the proof, insert children
-	// < [smallestProvenPath] and > [largestProvenPath]
-	// into the trie so that we get the expected root ID (if this proof is valid).
-	// By inserting all children < [smallestProvenPath], we prove that there are no keys
-	// > [smallestProvenPath] but less than the first key given.
-	// That is, the peer who gave us this proof is not omitting nodes.
-	if err := addPathInfo(
-		view,
-		proof.StartProof,
-		smallestProvenPath,
-		largestProvenPath,
-	); err != nil {
-		return err
-	}
-	if err := addPathInfo(
-		view,
-		proof.EndProof,
-		smallestProvenPath,
-		largestProvenPath,
-	); err != nil {
-		return err
-	}
-
-	calculatedRoot, err := view.GetMerkleRoot(ctx)
-	if err != nil {
-		return err
-	}
-	if expectedRootID != calculatedRoot {
-		return fmt.Errorf("%w:[%s], expected:[%s]", ErrInvalidProof, calculatedRoot, expectedRootID)
-	}
-	return nil
+	return VerifyRangeProofStateless(proof, start, end, expectedRootID, branchFactor)
 }
 
 func (proof *RangeProof) ToProto() *pb.RangeProof {
@@ -814,6 +777,13 @@ func valueOrHashMatches(value maybe.Maybe[[]byte], valueOrHash maybe.Maybe[[]byt
 	}
 }
 
+// pathInserter is a trie that proof nodes can be inserted into.
+type pathInserter interface {
+	// insert sets the value of the node with [key] to [value], creating the
+	// node if needed, and returns it.
+	insert(key Key, value maybe.Maybe[[]byte]) (*node, error)
+}
+
 // Adds each key/value pair in [proofPath] to [t].
 // For each proof node, adds the children that are
 // < [insertChildrenLessThan] or > [insertChildrenGreaterThan].
@@ -821,7 +791,7 @@ func valueOrHashMatches(value maybe.Maybe[[]byte], valueOrHash maybe.Maybe[[]byt
 // If [insertChildrenGreaterThan] is Nothing, no children are > [insertChildrenGreaterThan].
 // Assumes [t.lock] is held.
 func addPathInfo(
-	t *trieView,
+	t pathInserter,
 	proofPath []ProofNode,
 	insertChildrenLessThan maybe.Maybe[Key],
 	insertChildrenGreaterThan maybe.Maybe[Key],
@@ -857,8 +827,16 @@ func addPathInfo(
 
 		// Add [proofNode]'s children which are outside the range
 		// [insertChildrenLessThan, insertChildrenGreaterThan].
-		compressedPath := emptyKey(key.branchFactor)
-		for index, childID := range proofNode.Children {
+		// The children are added in increasing index order so that [n] is
+		// built the same way regardless of map iteration order.
+		childIndices := maps.Keys(proofNode.Children)
+		slices.Sort(childIndices)
+		for _, index := range childIndices {
+			childID := proofNode.Children[index]
+			// A child that isn't in [n] is assumed to have an empty
+			// compressed key, rather than the compressed key of the previous
+			// child.
+			compressedPath := emptyKey(key.branchFactor)
 			if existingChild, ok := n.children[index]; ok {
 				compressedPath = existingChild.compressedKey
 			}
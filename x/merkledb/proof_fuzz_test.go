@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+// The fuzz targets in this file parse proofs from arbitrary bytes and verify
+// them against the root of a known trie. Parsing and verifying must never
+// panic, and a proof that verifies must be a correct proof of the trie.
+// The targets are seeded with correct proofs of the trie, so mutations of
+// them reach the verification logic.
+
+// The key/value pairs of the trie that fuzzed proofs are verified against.
+// Some keys are prefixes of others, so the trie has nodes with values and
+// children.
+var fuzzProofKeyValues = []KeyValue{
+	{Key: []byte{0x00}, Value: []byte{0x00}},
+	{Key: []byte{0x00, 0x01}, Value: []byte{0x01}},
+	{Key: []byte{0x00, 0x01, 0x02}, Value: bytes.Repeat([]byte{0x02}, HashLength)},
+	{Key: []byte{0x10}, Value: []byte{}},
+	{Key: []byte{0x11, 0x22}, Value: []byte{0x11, 0x22}},
+	{Key: []byte{0xf0}, Value: bytes.Repeat([]byte{0xf0}, 2*HashLength)},
+	{Key: []byte{0xf0, 0x0f}, Value: []byte{0xf0, 0x0f}},
+	{Key: []byte{0xff}, Value: []byte{0xff}},
+}
+
+// The keys that fuzz targets are seeded with proofs or ranges of.
+var fuzzProofSeedKeys = [][]byte{
+	nil,
+	{0x00},
+	{0x00, 0x01},
+	{0x01},
+	{0x11, 0x22},
+	{0x11, 0x22, 0x33},
+	{0xf0, 0x0f},
+	{0xff},
+}
+
+// Returns a database holding [fuzzProofKeyValues] and its root.
+func newFuzzProofDB(f *testing.F) (*merkleDB, ids.ID) {
+	require := require.New(f)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	for _, kv := range fuzzProofKeyValues {
+		require.NoError(db.Put(kv.Key, kv.Value))
+	}
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	return db, root
+}
+
+// Returns [b] as a range bound. An empty bound is no bound.
+func fuzzRangeBound(b []byte) maybe.Maybe[[]byte] {
+	if len(b) == 0 {
+		return maybe.Nothing[[]byte]()
+	}
+	return maybe.Some(b)
+}
+
+// Returns the key/value pairs of [db] in the range [start, end].
+func getKeyValuesInRange(
+	t *testing.T,
+	db database.Iteratee,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+) []KeyValue {
+	it := db.NewIteratorWithStart(start.Value())
+	defer it.Release()
+
+	var keyValues []KeyValue
+	for it.Next() {
+		if end.HasValue() && bytes.Compare(it.Key(), end.Value()) > 0 {
+			break
+		}
+		keyValues = append(keyValues, KeyValue{
+			Key:   it.Key(),
+			Value: it.Value(),
+		})
+	}
+	require.NoError(t, it.Error())
+	return keyValues
+}
+
+func FuzzProofUnmarshalVerify(f *testing.F) {
+	db, root := newFuzzProofDB(f)
+	for _, key := range fuzzProofSeedKeys {
+		proof, err := db.GetProof(context.Background(), key)
+		require.NoError(f, err)
+		proofBytes, err := proto.Marshal(proof.ToProto())
+		require.NoError(f, err)
+		f.Add(proofBytes)
+	}
+
+	f.Fuzz(func(t *testing.T, proofBytes []byte) {
+		require := require.New(t)
+
+		var pbProof pb.Proof
+		if err := proto.Unmarshal(proofBytes, &pbProof); err != nil {
+			return
+		}
+		var proof Proof
+		if err := proof.UnmarshalProto(&pbProof, BranchFactor16); err != nil {
+			return
+		}
+		if err := proof.Verify(context.Background(), root); err != nil {
+			return
+		}
+
+		// The proof must be of the value of its key in the trie. Only keys
+		// with a whole number of bytes have values.
+		if proof.Key.hasPartialByte() {
+			require.True(proof.Value.IsNothing())
+			return
+		}
+		value, err := db.GetValue(context.Background(), proof.Key.Bytes())
+		if err == database.ErrNotFound {
+			require.True(proof.Value.IsNothing())
+			return
+		}
+		require.NoError(err)
+		require.True(proof.Value.HasValue())
+		require.Equal(value, proof.Value.Value())
+	})
+}
+
+func FuzzRangeProofUnmarshalVerify(f *testing.F) {
+	db, root := newFuzzProofDB(f)
+	for i, start := range fuzzProofSeedKeys {
+		for _, end := range fuzzProofSeedKeys[i:] {
+			for _, maxLength := range []int{1, 3, len(fuzzProofKeyValues)} {
+				proof, err := db.GetRangeProof(
+					context.Background(),
+					fuzzRangeBound(start),
+					fuzzRangeBound(end),
+					maxLength,
+				)
+				require.NoError(f, err)
+				proofBytes, err := proto.Marshal(proof.ToProto())
+				require.NoError(f, err)
+				f.Add(proofBytes, start, end)
+			}
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, proofBytes []byte, startBytes []byte, endBytes []byte) {
+		require := require.New(t)
+
+		var pbProof pb.RangeProof
+		if err := proto.Unmarshal(proofBytes, &pbProof); err != nil {
+			return
+		}
+		var proof RangeProof
+		if err := proof.UnmarshalProto(&pbProof, BranchFactor16); err != nil {
+			return
+		}
+		start := fuzzRangeBound(startBytes)
+		end := fuzzRangeBound(endBytes)
+		if err := proof.Verify(context.Background(), start, end, root); err != nil {
+			return
+		}
+
+		// The proof must hold every key/value pair of the trie from [start]
+		// to its greatest key, or to [end] if it has no key/value pairs.
+		largestKey := end
+		if len(proof.KeyValues) > 0 {
+			largestKey = maybe.Some(proof.KeyValues[len(proof.KeyValues)-1].Key)
+		}
+		expected := getKeyValuesInRange(t, db, start, largestKey)
+		require.Len(proof.KeyValues, len(expected))
+		for i, kv := range expected {
+			require.Equal(kv.Key, proof.KeyValues[i].Key)
+			require.Equal(kv.Value, proof.KeyValues[i].Value)
+		}
+	})
+}
+
+func FuzzChangeProofUnmarshalVerify(f *testing.F) {
+	db, startRoot := newFuzzProofDB(f)
+	require.NoError(f, db.Put([]byte{0x00, 0x01}, []byte{0x10}))
+	require.NoError(f, db.Delete([]byte{0x11, 0x22}))
+	require.NoError(f, db.Put([]byte{0x80}, []byte{0x80}))
+	endRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(f, err)
+
+	for i, start := range fuzzProofSeedKeys {
+		for _, end := range fuzzProofSeedKeys[i:] {
+			for _, maxLength := range []int{1, 3} {
+				proof, err := db.GetChangeProof(
+					context.Background(),
+					startRoot,
+					endRoot,
+					fuzzRangeBound(start),
+					fuzzRangeBound(end),
+					maxLength,
+				)
+				require.NoError(f, err)
+				proofBytes, err := proto.Marshal(proof.ToProto())
+				require.NoError(f, err)
+				f.Add(proofBytes, start, end)
+			}
+		}
+	}
+
+	// Change proofs are verified against a database at the start root, as
+	// a syncing client would.
+	verifier, _ := newFuzzProofDB(f)
+
+	f.Fuzz(func(t *testing.T, proofBytes []byte, startBytes []byte, endBytes []byte) {
+		var pbProof pb.ChangeProof
+		if err := proto.Unmarshal(proofBytes, &pbProof); err != nil {
+			return
+		}
+		var proof ChangeProof
+		if err := proof.UnmarshalProto(&pbProof, BranchFactor16); err != nil {
+			return
+		}
+		_ = verifier.VerifyChangeProof(
+			context.Background(),
+			&proof,
+			fuzzRangeBound(startBytes),
+			fuzzRangeBound(endBytes),
+			endRoot,
+		)
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var ErrProofQuotaExceeded = errors.New("proof quota exceeded")
+
+// ProofQuota limits the work done to generate proofs on behalf of the callers
+// labelled [Caller] with [WithProofCaller].
+type ProofQuota struct {
+	Caller string
+	// The number of trie nodes and key-value pairs that may be visited per
+	// second, on average, to generate the caller's proofs. Up to one second
+	// of unused work may be spent at once.
+	NodesPerSecond uint
+}
+
+type proofCallerKey struct{}
+
+// WithProofCaller returns a context that labels the proofs generated with it
+// as requested by [caller], so that the work done to generate them counts
+// against the [ProofQuota] of [caller], if it has one.
+// Proofs generated with a context that has no caller, or whose caller has no
+// quota, are never limited.
+func WithProofCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, proofCallerKey{}, caller)
+}
+
+func newProofQuotas(quotas []ProofQuota) map[string]*rate.Limiter {
+	limiters := make(map[string]*rate.Limiter, len(quotas))
+	for _, quota := range quotas {
+		limiters[quota.Caller] = rate.NewLimiter(
+			rate.Limit(quota.NodesPerSecond),
+			int(quota.NodesPerSecond),
+		)
+	}
+	return limiters
+}
+
+// chargeProofWork records that generating a proof for the caller of [ctx]
+// visited [n] trie nodes or key-value pairs.
+// Returns [ErrProofQuotaExceeded] if the caller's quota doesn't allow it.
+// Never blocks, so that a caller that exceeds its quota doesn't hold locks
+// needed by other readers or by commits.
+func (db *merkleDB) chargeProofWork(ctx context.Context, n int) error {
+	if len(db.proofQuotas) == 0 {
+		return nil
+	}
+	caller, ok := ctx.Value(proofCallerKey{}).(string)
+	if !ok {
+		return nil
+	}
+	limiter, ok := db.proofQuotas[caller]
+	if !ok {
+		return nil
+	}
+	if !limiter.AllowN(time.Now(), n) {
+		db.metrics.ProofQuotaExceeded()
+		return fmt.Errorf("%w for caller %q", ErrProofQuotaExceeded, caller)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+func Test_MerkleDB_ProofQuotas(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.ProofQuotas = []ProofQuota{
+		{
+			Caller:         "rpc",
+			NodesPerSecond: 20,
+		},
+	}
+	metrics := &mockMetrics{}
+	db, err := newDatabase(context.Background(), memdb.New(), config, metrics)
+	require.NoError(err)
+
+	startRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	keys := make([][]byte, 100)
+	for i := range keys {
+		keys[i] = []byte(strconv.Itoa(i))
+		require.NoError(db.Put(keys[i], keys[i]))
+	}
+	endRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	var (
+		rpcCtx       = WithProofCaller(context.Background(), "rpc")
+		consensusCtx = WithProofCaller(context.Background(), "consensus")
+	)
+
+	// A proof that visits fewer nodes than the quota succeeds.
+	_, err = db.GetProof(rpcCtx, []byte("0"))
+	require.NoError(err)
+
+	_, err = db.GetRangeProof(rpcCtx, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 100)
+	require.ErrorIs(err, ErrProofQuotaExceeded)
+	require.Equal(int64(1), metrics.proofQuotaExceeded)
+
+	// Proofs that visit more nodes than the quota allows at once always fail.
+	_, err = db.GetChangeProof(rpcCtx, startRoot, endRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 100)
+	require.ErrorIs(err, ErrProofQuotaExceeded)
+	_, err = db.GetProofs(rpcCtx, keys)
+	require.ErrorIs(err, ErrProofQuotaExceeded)
+
+	// Callers without a quota are never limited.
+	for _, ctx := range []context.Context{context.Background(), consensusCtx} {
+		proof, err := db.GetRangeProof(ctx, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 100)
+		require.NoError(err)
+		require.Len(proof.KeyValues, 100)
+
+		changeProof, err := db.GetChangeProof(ctx, startRoot, endRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 100)
+		require.NoError(err)
+		require.Len(changeProof.KeyChanges, 100)
+	}
+	require.Equal(int64(3), metrics.proofQuotaExceeded)
+}
@@ -6,12 +6,16 @@ package merkledb
 import (
 	"bytes"
 	"context"
+	"errors"
 	"math/rand"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/hashing"
@@ -287,6 +291,377 @@ func Test_Proof(t *testing.T) {
 	require.ErrorIs(err, ErrInvalidProof)
 }
 
+func Test_Proof_StackedViews(t *testing.T) {
+	require := require.New(t)
+
+	const numViews = 12
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	for i := 0; i < numViews; i++ {
+		require.NoError(db.Put([]byte{byte(i)}, []byte{byte(i)}))
+	}
+
+	// View [i] modifies key [i], so every key was last modified by a
+	// different ancestor of [view].
+	var view TrieView = db
+	for i := 0; i < numViews; i++ {
+		op := database.BatchOp{Key: []byte{byte(i)}, Value: []byte{byte(i), 1}}
+		if i%3 == 0 {
+			op = database.BatchOp{Key: []byte{byte(i)}, Delete: true}
+		}
+		view, err = view.NewView(context.Background(), ViewChanges{
+			BatchOps: []database.BatchOp{op},
+		})
+		require.NoError(err)
+	}
+
+	root, err := view.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	dbRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.NotEqual(dbRoot, root)
+
+	// Includes a key that was never inserted.
+	for i := 0; i <= numViews; i++ {
+		key := []byte{byte(i)}
+		proof, err := view.GetProof(context.Background(), key)
+		require.NoError(err)
+		require.NoError(proof.Verify(context.Background(), root))
+
+		value, err := view.GetValue(context.Background(), key)
+		if errors.Is(err, database.ErrNotFound) {
+			require.True(proof.Value.IsNothing())
+			continue
+		}
+		require.NoError(err)
+		require.Equal(maybe.Some(value), proof.Value)
+	}
+}
+
+func Test_Proof_GetProofs(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	keys := make([][]byte, 0, 200)
+	ops := make([]database.BatchOp, 0, 100)
+	for i := 0; i < 100; i++ {
+		key := make([]byte, r.Intn(5))
+		_, _ = r.Read(key)
+		keys = append(keys, key)
+		ops = append(ops, database.BatchOp{Key: key, Value: key})
+	}
+	view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+	// Includes keys that are likely not in the trie, and duplicates.
+	for i := 0; i < 100; i++ {
+		key := make([]byte, r.Intn(5))
+		_, _ = r.Read(key)
+		keys = append(keys, key, keys[r.Intn(len(keys))])
+	}
+	r.Shuffle(len(keys), func(i, j int) {
+		keys[i], keys[j] = keys[j], keys[i]
+	})
+
+	view, err = db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: keys[0], Delete: true},
+			{Key: []byte{1, 2, 3, 4, 5}, Value: []byte{1}},
+		},
+	})
+	require.NoError(err)
+	snapshot, err := db.newSnapshot()
+	require.NoError(err)
+	defer snapshot.release()
+
+	for _, trie := range []ReadOnlyTrie{db, view, snapshot} {
+		root, err := trie.GetMerkleRoot(context.Background())
+		require.NoError(err)
+
+		proofs, err := trie.GetProofs(context.Background(), keys)
+		require.NoError(err)
+		require.Len(proofs, len(keys))
+		for i, key := range keys {
+			expectedProof, err := trie.GetProof(context.Background(), key)
+			require.NoError(err)
+			require.Equal(expectedProof, proofs[i])
+			require.NoError(proofs[i].Verify(context.Background(), root))
+		}
+	}
+}
+
+func Test_RangeProof_Stream(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	keys := make([][]byte, 0, 100)
+	ops := make([]database.BatchOp, 0, 100)
+	for i := 0; i < 100; i++ {
+		key := make([]byte, r.Intn(5)+1)
+		_, _ = r.Read(key)
+		keys = append(keys, key)
+		ops = append(ops, database.BatchOp{Key: key, Value: key})
+	}
+	view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+	slices.SortFunc(keys, func(a, b []byte) bool {
+		return bytes.Compare(a, b) < 0
+	})
+
+	view, err = db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: keys[0], Delete: true},
+			{Key: []byte{0xff, 0xff}, Value: []byte{1}},
+		},
+	})
+	require.NoError(err)
+	snapshot, err := db.newSnapshot()
+	require.NoError(err)
+	defer snapshot.release()
+
+	bounds := []struct {
+		start maybe.Maybe[[]byte]
+		end   maybe.Maybe[[]byte]
+	}{
+		{
+			start: maybe.Nothing[[]byte](),
+			end:   maybe.Nothing[[]byte](),
+		},
+		{
+			start: maybe.Some(keys[10]),
+			end:   maybe.Some(keys[80]),
+		},
+		{
+			start: maybe.Some([]byte{0x80}),
+			end:   maybe.Nothing[[]byte](),
+		},
+		{
+			start: maybe.Some([]byte{0xff, 0xff, 0xff}),
+			end:   maybe.Nothing[[]byte](),
+		},
+	}
+	for _, trie := range []ReadOnlyTrie{db, view, snapshot} {
+		root, err := trie.GetMerkleRoot(context.Background())
+		require.NoError(err)
+
+		for _, bound := range bounds {
+			for _, chunkSize := range []int{1, 7, 1000} {
+				var (
+					start     = bound.start
+					keyValues []KeyValue
+				)
+				require.NoError(trie.StreamRangeProof(
+					context.Background(),
+					bound.start,
+					bound.end,
+					chunkSize,
+					func(proof *RangeProof) error {
+						require.LessOrEqual(len(proof.KeyValues), chunkSize)
+						require.NoError(proof.Verify(context.Background(), start, bound.end, root))
+						keyValues = append(keyValues, proof.KeyValues...)
+
+						if len(proof.KeyValues) > 0 {
+							start = maybe.Some(append(slices.Clone(proof.KeyValues[len(proof.KeyValues)-1].Key), 0))
+						}
+						return nil
+					},
+				))
+
+				// The proofs cover every key-value pair in the range.
+				var expectedKeyValues []KeyValue
+				it := trie.NewIteratorWithStart(bound.start.Value())
+				for it.Next() && (bound.end.IsNothing() || bytes.Compare(it.Key(), bound.end.Value()) <= 0) {
+					expectedKeyValues = append(expectedKeyValues, KeyValue{
+						Key:   it.Key(),
+						Value: it.Value(),
+					})
+				}
+				it.Release()
+				require.NoError(it.Error())
+				require.Equal(expectedKeyValues, keyValues)
+			}
+		}
+	}
+}
+
+func Test_RangeProof_Stream_Stop(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	for i := 0; i < 10; i++ {
+		key := []byte{byte(i)}
+		require.NoError(db.Put(key, key))
+	}
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	err = db.StreamRangeProof(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 0, nil)
+	require.ErrorIs(err, ErrInvalidMaxLength)
+
+	// Commits made while streaming don't change the proofs.
+	errStop := errors.New("stop")
+	numProofs := 0
+	err = db.StreamRangeProof(
+		context.Background(),
+		maybe.Nothing[[]byte](),
+		maybe.Nothing[[]byte](),
+		2,
+		func(proof *RangeProof) error {
+			numProofs++
+			start := maybe.Nothing[[]byte]()
+			if numProofs > 1 {
+				start = maybe.Some([]byte{byte(2*numProofs - 3), 0})
+			}
+			require.NoError(proof.Verify(context.Background(), start, maybe.Nothing[[]byte](), root))
+			require.NoError(db.Put([]byte{byte(numProofs)}, nil))
+			if numProofs == 3 {
+				return errStop
+			}
+			return nil
+		},
+	)
+	require.ErrorIs(err, errStop)
+	require.Equal(3, numProofs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	numProofs = 0
+	err = db.StreamRangeProof(
+		ctx,
+		maybe.Nothing[[]byte](),
+		maybe.Nothing[[]byte](),
+		2,
+		func(*RangeProof) error {
+			numProofs++
+			cancel()
+			return nil
+		},
+	)
+	require.ErrorIs(err, context.Canceled)
+	require.Equal(1, numProofs)
+}
+
+func Test_Proof_Update(t *testing.T) {
+	tests := []struct {
+		name        string
+		changes     []database.BatchOp
+		maxLength   int
+		expectedErr error
+	}{
+		{
+			name: "no changes",
+		},
+		{
+			name: "unrelated change",
+			changes: []database.BatchOp{
+				{Key: []byte("b2"), Value: []byte("b2")},
+			},
+		},
+		{
+			name: "key changed",
+			changes: []database.BatchOp{
+				{Key: []byte("a0"), Value: []byte("new")},
+			},
+		},
+		{
+			name: "key deleted",
+			changes: []database.BatchOp{
+				{Key: []byte("a0"), Delete: true},
+			},
+		},
+		{
+			name: "sibling changed",
+			changes: []database.BatchOp{
+				{Key: []byte("a1"), Value: []byte("new")},
+			},
+		},
+		{
+			name: "sibling and unrelated key changed",
+			changes: []database.BatchOp{
+				{Key: []byte("a1"), Value: []byte("new")},
+				{Key: []byte("b2"), Value: []byte("b2")},
+			},
+			expectedErr: ErrProofRegenerationRequired,
+		},
+		{
+			name: "change proof missing changes",
+			changes: []database.BatchOp{
+				{Key: []byte("0"), Value: []byte("0")},
+				{Key: []byte("a1"), Value: []byte("new")},
+			},
+			maxLength:   1,
+			expectedErr: ErrProofRegenerationRequired,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := context.Background()
+
+			db, err := getBasicDB()
+			require.NoError(err)
+
+			batch := db.NewBatch()
+			for _, key := range []string{"a0", "a1", "a2", "b0", "b1"} {
+				require.NoError(batch.Put([]byte(key), []byte(key)))
+			}
+			require.NoError(batch.Write())
+
+			startRoot, err := db.GetMerkleRoot(ctx)
+			require.NoError(err)
+			proof, err := db.GetProof(ctx, []byte("a0"))
+			require.NoError(err)
+
+			batch = db.NewBatch()
+			for _, op := range test.changes {
+				if op.Delete {
+					require.NoError(batch.Delete(op.Key))
+				} else {
+					require.NoError(batch.Put(op.Key, op.Value))
+				}
+			}
+			require.NoError(batch.Write())
+
+			endRoot, err := db.GetMerkleRoot(ctx)
+			require.NoError(err)
+
+			changeProof := &ChangeProof{}
+			if startRoot != endRoot {
+				maxLength := test.maxLength
+				if maxLength == 0 {
+					maxLength = len(test.changes)
+				}
+				changeProof, err = db.GetChangeProof(ctx, startRoot, endRoot, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), maxLength)
+				require.NoError(err)
+			}
+
+			updated, err := proof.Update(ctx, changeProof, endRoot)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+
+			expected, err := db.GetProof(ctx, []byte("a0"))
+			require.NoError(err)
+			require.Equal(expected, updated)
+		})
+	}
+}
+
 func Test_RangeProof_Syntactic_Verify(t *testing.T) {
 	type test struct {
 		name        string
@@ -1225,6 +1600,52 @@ func TestVerifyProofPath(t *testing.T) {
 	}
 }
 
+// Regression test for a child of a proof node that isn't in the trie being
+// placed using the compressed key of the previous child.
+func TestAddPathInfoChildNotInTrie(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	// The root has a child at index 1 with the compressed key 00.
+	require.NoError(db.Put([]byte{0x10, 0x00}, []byte{1}))
+	require.NoError(db.Put([]byte{0x10, 0x01}, []byte{2}))
+
+	childID := ids.GenerateTestID()
+	proofPath := []ProofNode{
+		{
+			Key: db.rootKey,
+			Children: map[byte]ids.ID{
+				1: ids.GenerateTestID(),
+				2: childID,
+			},
+		},
+	}
+
+	// The child at index 2 has the key 2, which isn't greater than 2, so it
+	// must not be added. It would be if it had the compressed key 00 of the
+	// child at index 1.
+	view, err := newTrieView(db, db, ViewChanges{})
+	require.NoError(err)
+	greaterThan := ToKey([]byte{0x20}, BranchFactor16).Take(1)
+	require.NoError(addPathInfo(view, proofPath, maybe.Nothing[Key](), maybe.Some(greaterThan)))
+
+	root := view.changes.nodes[db.rootKey].after
+	require.Contains(root.children, byte(1))
+	require.NotContains(root.children, byte(2))
+
+	// The child at index 2 is added once its key is greater than the bound.
+	view, err = newTrieView(db, db, ViewChanges{})
+	require.NoError(err)
+	greaterThan = ToKey([]byte{0x10}, BranchFactor16).Take(1)
+	require.NoError(addPathInfo(view, proofPath, maybe.Nothing[Key](), maybe.Some(greaterThan)))
+
+	root = view.changes.nodes[db.rootKey].after
+	require.Contains(root.children, byte(2))
+	require.Equal(childID, root.children[2].id)
+	require.Zero(root.children[2].compressedKey.tokenLength)
+}
+
 func TestProofNodeUnmarshalProtoInvalidMaybe(t *testing.T) {
 	now := time.Now().UnixNano()
 	t.Logf("seed: %d", now)
@@ -1882,3 +2303,57 @@ func FuzzChangeProofVerification(f *testing.F) {
 		))
 	})
 }
+
+func Test_VerifyRangeProofStateless(t *testing.T) {
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	for _, branchFactor := range branchFactors {
+		t.Run(strconv.Itoa(int(branchFactor)), func(t *testing.T) {
+			require := require.New(t)
+
+			db, err := getBasicDBWithBranchFactor(branchFactor)
+			require.NoError(err)
+			for i := 0; i < 200; i++ {
+				key := make([]byte, r.Intn(6))
+				_, _ = r.Read(key)
+				value := make([]byte, r.Intn(40))
+				_, _ = r.Read(value)
+				require.NoError(db.Put(key, value))
+			}
+			root, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+
+			for i := 0; i < 20; i++ {
+				start := maybe.Nothing[[]byte]()
+				if r.Intn(4) != 0 {
+					start = maybe.Some([]byte{byte(r.Intn(128))})
+				}
+				end := maybe.Nothing[[]byte]()
+				if r.Intn(4) != 0 {
+					end = maybe.Some([]byte{byte(128 + r.Intn(128))})
+				}
+				proof, err := db.GetRangeProof(context.Background(), start, end, 1+r.Intn(50))
+				require.NoError(err)
+
+				require.NoError(VerifyRangeProofStateless(proof, start, end, root, branchFactor))
+				err = VerifyRangeProofStateless(proof, start, end, ids.GenerateTestID(), branchFactor)
+				require.ErrorIs(err, ErrInvalidProof)
+
+				otherBranchFactor := BranchFactor256
+				if branchFactor == BranchFactor256 {
+					otherBranchFactor = BranchFactor16
+				}
+				err = VerifyRangeProofStateless(proof, start, end, root, otherBranchFactor)
+				require.ErrorIs(err, ErrInconsistentBranchFactor)
+
+				if len(proof.KeyValues) > 0 {
+					proof.KeyValues[0].Value = append(proof.KeyValues[0].Value, 0)
+					err = VerifyRangeProofStateless(proof, start, end, root, branchFactor)
+					require.Error(err)
+				}
+			}
+		})
+	}
+}
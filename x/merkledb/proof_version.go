@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProofVersionUnversioned proofs are encoded as a protobuf message without
+	// a version byte. Peers that predate proof versioning only request and
+	// send unversioned proofs.
+	ProofVersionUnversioned ProofVersion = 0
+	// ProofVersion1 proofs are encoded as a version byte followed by a
+	// protobuf message.
+	ProofVersion1 ProofVersion = 1
+
+	// MaxProofVersion is the highest proof version that can be encoded and
+	// parsed.
+	MaxProofVersion = ProofVersion1
+
+	// The first byte of a protobuf message is the tag of its first field,
+	// which is at least 1<<3 because field numbers start at 1. Version bytes
+	// are less than that so that versioned proofs are never confused with
+	// unversioned ones.
+	maxProofVersionByte = 1<<3 - 1
+)
+
+var ErrUnknownProofVersion = errors.New("unknown proof version")
+
+// ProofVersion is the version of the encoding of a proof sent to a peer.
+type ProofVersion uint8
+
+// NegotiateProofVersion returns the highest proof version that can be encoded
+// and that a peer able to parse proof versions up to [maxVersion] can parse.
+func NegotiateProofVersion(maxVersion uint32) ProofVersion {
+	if maxVersion >= uint32(MaxProofVersion) {
+		return MaxProofVersion
+	}
+	return ProofVersion(maxVersion)
+}
+
+// MarshalProof encodes [msg] as a proof of [version].
+func MarshalProof(version ProofVersion, msg proto.Message) ([]byte, error) {
+	if version > MaxProofVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownProofVersion, version)
+	}
+
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if version == ProofVersionUnversioned {
+		return msgBytes, nil
+	}
+
+	proofBytes := make([]byte, 1+len(msgBytes))
+	proofBytes[0] = byte(version)
+	copy(proofBytes[1:], msgBytes)
+	return proofBytes, nil
+}
+
+// UnmarshalProof parses [proofBytes] into [msg] and returns the version of
+// the proof. Unversioned proofs are always accepted so that proofs from peers
+// that predate proof versioning can be parsed. Returns
+// [ErrUnknownProofVersion] if the proof's version is greater than
+// [maxVersion].
+func UnmarshalProof(proofBytes []byte, maxVersion ProofVersion, msg proto.Message) (ProofVersion, error) {
+	if len(proofBytes) == 0 || proofBytes[0] == 0 || proofBytes[0] > maxProofVersionByte {
+		return ProofVersionUnversioned, proto.Unmarshal(proofBytes, msg)
+	}
+
+	version := ProofVersion(proofBytes[0])
+	if version > maxVersion || version > MaxProofVersion {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownProofVersion, version)
+	}
+	return version, proto.Unmarshal(proofBytes[1:], msg)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+func TestNegotiateProofVersion(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(ProofVersionUnversioned, NegotiateProofVersion(0))
+	require.Equal(ProofVersion1, NegotiateProofVersion(1))
+	require.Equal(MaxProofVersion, NegotiateProofVersion(uint32(MaxProofVersion)+1))
+}
+
+func TestMarshalProof(t *testing.T) {
+	msg := &pb.KeyValue{
+		Key:   []byte{1, 2, 3},
+		Value: []byte{4, 5, 6},
+	}
+	msgBytes, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		version            ProofVersion
+		maxVersion         ProofVersion
+		expectedProofBytes []byte
+		expectedErr        error
+	}{
+		{
+			name:               "unversioned",
+			version:            ProofVersionUnversioned,
+			maxVersion:         MaxProofVersion,
+			expectedProofBytes: msgBytes,
+		},
+		{
+			name:               "unversioned parsed by peer without proof versioning",
+			version:            ProofVersionUnversioned,
+			maxVersion:         ProofVersionUnversioned,
+			expectedProofBytes: msgBytes,
+		},
+		{
+			name:               "version 1",
+			version:            ProofVersion1,
+			maxVersion:         MaxProofVersion,
+			expectedProofBytes: append([]byte{byte(ProofVersion1)}, msgBytes...),
+		},
+		{
+			name:               "version too high",
+			version:            ProofVersion1,
+			maxVersion:         ProofVersionUnversioned,
+			expectedProofBytes: append([]byte{byte(ProofVersion1)}, msgBytes...),
+			expectedErr:        ErrUnknownProofVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			proofBytes, err := MarshalProof(tt.version, msg)
+			require.NoError(err)
+			require.Equal(tt.expectedProofBytes, proofBytes)
+
+			var parsed pb.KeyValue
+			version, err := UnmarshalProof(proofBytes, tt.maxVersion, &parsed)
+			require.ErrorIs(err, tt.expectedErr)
+			if tt.expectedErr != nil {
+				return
+			}
+			require.Equal(tt.version, version)
+			require.True(proto.Equal(msg, &parsed))
+		})
+	}
+}
+
+func TestMarshalProofUnknownVersion(t *testing.T) {
+	_, err := MarshalProof(MaxProofVersion+1, &pb.KeyValue{})
+	require.ErrorIs(t, err, ErrUnknownProofVersion)
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var errViewReadOnly = errors.New("view is read-only")
+
+// NewReadOnlyView returns a view of the committed trie without changes.
+// See [ReadOnlyViewer].
+//
+// Assumes [db.commitLock] and [db.lock] aren't held.
+func (db *merkleDB) NewReadOnlyView(_ context.Context, onInvalidate func()) (ReadOnlyTrie, error) {
+	// ensure the db doesn't change while creating the new view
+	db.commitLock.RLock()
+	defer db.commitLock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+
+	view, err := newTrieView(db, db, ViewChanges{})
+	if err != nil {
+		return nil, err
+	}
+	view.readOnly = true
+	view.onInvalidate = onInvalidate
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.readOnlyViews = append(db.readOnlyViews, view)
+	return view, nil
+}
+
+// invalidateReadOnlyViews invalidates the read-only views, because the root
+// is about to change. Their callbacks are called by
+// [notifyInvalidatedReadOnlyViews] once [db.lock] is released.
+// Assumes [db.commitLock] and [db.lock] are held.
+func (db *merkleDB) invalidateReadOnlyViews() {
+	for _, view := range db.readOnlyViews {
+		view.invalidate()
+	}
+	db.invalidatedReadOnlyViews = append(db.invalidatedReadOnlyViews, db.readOnlyViews...)
+	db.readOnlyViews = nil
+}
+
+// notifyInvalidatedReadOnlyViews calls the callbacks of the read-only views
+// invalidated since it was last called.
+// The callbacks are called asynchronously, because they may create new
+// read-only views, which waits for [db.commitLock] to be released.
+// Assumes [db.commitLock] is held and [db.lock] isn't held.
+func (db *merkleDB) notifyInvalidatedReadOnlyViews() {
+	for _, view := range db.invalidatedReadOnlyViews {
+		if view.onInvalidate != nil {
+			go view.onInvalidate()
+		}
+	}
+	db.invalidatedReadOnlyViews = nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+func TestReadOnlyView(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	require.NoError(db.Put([]byte{0}, []byte{0}))
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	var (
+		invalidated = make(chan struct{}, 2)
+		refreshed   ReadOnlyTrie
+	)
+	view, err := db.NewReadOnlyView(context.Background(), func() {
+		// The commit that invalidated the view is readable, and the view can
+		// be replaced with one of the new root.
+		value, err := db.Get([]byte{1})
+		require.NoError(err)
+		require.Equal([]byte{1}, value)
+		refreshed, err = db.NewReadOnlyView(context.Background(), nil)
+		require.NoError(err)
+
+		invalidated <- struct{}{}
+	})
+	require.NoError(err)
+	// A view without a callback is invalidated too.
+	otherView, err := db.NewReadOnlyView(context.Background(), nil)
+	require.NoError(err)
+
+	value, err := view.GetValue(context.Background(), []byte{0})
+	require.NoError(err)
+	require.Equal([]byte{0}, value)
+	viewRoot, err := view.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(root, viewRoot)
+
+	// A read-only view can't be changed or committed.
+	trieView := view.(*trieView)
+	_, err = trieView.NewView(context.Background(), ViewChanges{})
+	require.ErrorIs(err, errViewReadOnly)
+	require.ErrorIs(trieView.CommitToDB(context.Background()), errViewReadOnly)
+
+	// Commits that don't change the root don't invalidate the view.
+	require.NoError(db.Put([]byte{0}, []byte{0}))
+	require.NoError(db.Delete([]byte{2}))
+	_, err = view.GetValue(context.Background(), []byte{0})
+	require.NoError(err)
+
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+	_, err = view.GetValue(context.Background(), []byte{0})
+	require.ErrorIs(err, ErrInvalid)
+	_, err = otherView.GetValue(context.Background(), []byte{0})
+	require.ErrorIs(err, ErrInvalid)
+
+	<-invalidated
+	value, err = refreshed.GetValue(context.Background(), []byte{1})
+	require.NoError(err)
+	require.Equal([]byte{1}, value)
+
+	// The callback is only called once.
+	require.NoError(db.Put([]byte{2}, []byte{2}))
+	require.Empty(invalidated)
+
+	require.NoError(db.Close())
+	_, err = db.NewReadOnlyView(context.Background(), nil)
+	require.ErrorIs(err, database.ErrClosed)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import "github.com/ava-labs/avalanchego/database"
+
+// The number of times a read of committed state is attempted without holding
+// [merkleDB.lock] before falling back to taking the lock.
+const maxLockFreeReadAttempts = 3
+
+// readState is an immutable description of the committed trie.
+// It allows committed state to be read without holding [merkleDB.lock].
+//
+// A new readState is published when a commit begins writing its changes to
+// the node databases and again once the commit has been written.
+// A read is consistent iff the same readState was published for the
+// entire duration of the read.
+type readState struct {
+	// True iff the db has been closed.
+	closed bool
+
+	// The root of the committed trie.
+	root *node
+
+	// Non-nil iff a commit is being written to the node databases.
+	// The [before] fields of [pending] describe the nodes of the trie
+	// with [root] that the in-progress commit is modifying.
+	// Nodes that aren't in [pending] are unmodified by the commit.
+	pending *changeSummary
+}
+
+// getNode returns the node with the given [key] in the trie described by [s].
+// Editing the returned node affects the database state.
+// Returns database.ErrNotFound if the node doesn't exist.
+func (s *readState) getNode(db *merkleDB, key Key, hasValue bool) (*node, error) {
+	if s.closed {
+		return nil, database.ErrClosed
+	}
+	if s.pending != nil {
+		if nodeChange, ok := s.pending.nodes[key]; ok {
+			if nodeChange.before == nil {
+				return nil, database.ErrNotFound
+			}
+			return nodeChange.before, nil
+		}
+	}
+	switch {
+	case key == db.rootKey:
+		return s.root, nil
+	case hasValue:
+		return db.valueNodeDB.Get(key)
+	}
+	return db.intermediateNodeDB.Get(key)
+}
+
+// getValue returns the value for the given [key] in the trie described by [s].
+// Returns database.ErrNotFound if it doesn't exist.
+func (s *readState) getValue(db *merkleDB, key Key) ([]byte, error) {
+	n, err := s.getNode(db, key, true /* hasValue */)
+	if err != nil {
+		return nil, err
+	}
+	if n.value.IsNothing() {
+		return nil, database.ErrNotFound
+	}
+	return n.value.Value(), nil
+}
+
+// Publishes a new description of the committed trie to readers.
+// Assumes [db.lock] is held.
+func (db *merkleDB) publishReadState(pending *changeSummary) {
+	db.readState.Store(&readState{
+		closed:  db.closed,
+		root:    db.root,
+		pending: pending,
+	})
+}
+
+// readCommitted calls [read] with a consistent description of the committed
+// trie and returns the error returned by [read].
+// [read] may be called multiple times and must not retain any results of a
+// call for which readCommitted doesn't return.
+// Reads don't wait for in-progress commits unless a consistent read couldn't
+// be performed after [maxLockFreeReadAttempts] attempts.
+// Assumes [db.lock] isn't held.
+func (db *merkleDB) readCommitted(read func(*readState) error) error {
+	for i := 0; i < maxLockFreeReadAttempts; i++ {
+		state := db.readState.Load()
+		err := read(state)
+		if db.readState.Load() == state {
+			// No new state was published during the read,
+			// so it didn't observe a partially written commit.
+			return err
+		}
+	}
+
+	// Commits are being published faster than we can read.
+	// Hold the lock so that no new state can be published during the read.
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return read(db.readState.Load())
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var ErrShadowDiverged = errors.New("shadow database diverged")
+
+// ShadowDB is an alternate implementation of a database that is written to
+// alongside a database to validate that both produce the same roots.
+type ShadowDB interface {
+	database.Batcher
+	MerkleRootGetter
+}
+
+type shadow struct {
+	db           ShadowDB
+	onDivergence func(error)
+	// True once [db] diverged from the database. [db] isn't written to
+	// afterwards.
+	diverged bool
+}
+
+// newShadow returns nil if [db] is nil.
+func newShadow(db ShadowDB, onDivergence func(error)) *shadow {
+	if db == nil {
+		return nil
+	}
+	return &shadow{
+		db:           db,
+		onDivergence: onDivergence,
+	}
+}
+
+// writeToShadow writes the key-value pairs in [changes], which were just
+// committed, to the shadow database and compares the resulting root to
+// [changes.rootID]. Divergence is reported rather than returned so that it
+// never affects the database.
+// Assumes [db.commitLock] is held.
+func (db *merkleDB) writeToShadow(ctx context.Context, changes *changeSummary) {
+	s := db.shadow
+	if s == nil || s.diverged || changes.numValueChanges() == 0 {
+		return
+	}
+
+	if err := s.write(ctx, changes); err != nil {
+		s.diverged = true
+		db.metrics.ShadowDiverged()
+		if s.onDivergence != nil {
+			s.onDivergence(fmt.Errorf("%w: %w", ErrShadowDiverged, err))
+		}
+	}
+}
+
+func (s *shadow) write(ctx context.Context, changes *changeSummary) error {
+	batch := s.db.NewBatch()
+	err := changes.forEachValueChange(func(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
+		if valueChange.after.IsNothing() {
+			return batch.Delete(key.Bytes())
+		}
+		return batch.Put(key.Bytes(), valueChange.after.Value())
+	})
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	shadowRoot, err := s.db.GetMerkleRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if shadowRoot != changes.rootID {
+		return fmt.Errorf("root %s but shadow root %s", changes.rootID, shadowRoot)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newShadowedDB(t *testing.T, shadowDB ShadowDB, onDivergence func(error)) (*merkleDB, *mockMetrics) {
+	config := newDefaultConfig()
+	config.Shadow = shadowDB
+	config.OnShadowDivergence = onDivergence
+	metrics := &mockMetrics{}
+	db, err := newDatabase(context.Background(), memdb.New(), config, metrics)
+	require.NoError(t, err)
+	return db, metrics
+}
+
+func TestShadowMatchingRoots(t *testing.T) {
+	require := require.New(t)
+
+	shadowDB, err := getBasicDB()
+	require.NoError(err)
+	db, metrics := newShadowedDB(t, shadowDB, func(err error) {
+		require.FailNow("unexpected divergence", err)
+	})
+
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+	require.NoError(db.Put([]byte{2}, []byte{2}))
+	require.NoError(db.Delete([]byte{1}))
+
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{3}, Value: []byte{3}},
+			{Key: []byte{2}, Delete: true},
+		},
+	})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	shadowRoot, err := shadowDB.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(root, shadowRoot)
+	require.Zero(metrics.shadowDivergences)
+}
+
+func TestShadowDivergence(t *testing.T) {
+	require := require.New(t)
+
+	shadowDB, err := getBasicDB()
+	require.NoError(err)
+	// The shadow doesn't start with the same key-value pairs as the database.
+	require.NoError(shadowDB.Put([]byte{0}, []byte{0}))
+
+	var divergences []error
+	db, metrics := newShadowedDB(t, shadowDB, func(err error) {
+		divergences = append(divergences, err)
+	})
+
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+	require.Len(divergences, 1)
+	require.ErrorIs(divergences[0], ErrShadowDiverged)
+	require.Equal(int64(1), metrics.shadowDivergences)
+
+	// The shadow isn't written to once it diverged.
+	require.NoError(db.Put([]byte{2}, []byte{2}))
+	require.Len(divergences, 1)
+	require.Equal(int64(1), metrics.shadowDivergences)
+
+	_, err = shadowDB.Get([]byte{2})
+	require.ErrorIs(err, database.ErrNotFound)
+
+	value, err := db.Get([]byte{2})
+	require.NoError(err)
+	require.Equal([]byte{2}, value)
+}
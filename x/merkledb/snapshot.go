@@ -0,0 +1,426 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	_ TrieView          = (*snapshot)(nil)
+	_ database.Iterator = (*snapshotIterator)(nil)
+
+	errSnapshotReadOnly = errors.New("snapshot is read-only")
+)
+
+// snapshot is a read-only view of the trie as it was committed when the
+// snapshot was taken.
+//
+// Commits may proceed while a snapshot is open. The changes they make are
+// retained in [merkleDB.history] until the snapshot is released, and reads
+// from the snapshot answer lookups of changed nodes from the nodes' values
+// before the first of those changes.
+//
+// Reads from a snapshot take a read lock on [merkleDB.lock], so they never
+// observe a partially written commit.
+type snapshot struct {
+	db *merkleDB
+
+	// The insert number that the first change committed after the snapshot
+	// was taken is recorded with in [db.history].
+	insertNumber uint64
+
+	// The root ID of the trie when the snapshot was taken.
+	rootID ids.ID
+}
+
+// newSnapshot returns a snapshot of the committed trie.
+// [release] must be called on the returned snapshot once it's no longer used.
+// Assumes [db.lock] isn't held.
+func (db *merkleDB) newSnapshot() (*snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return &snapshot{
+		db:           db,
+		insertNumber: db.history.addSnapshot(),
+		rootID:       db.getMerkleRoot(),
+	}, nil
+}
+
+// newSnapshotAtRoot returns a snapshot of the trie as it was when its root was
+// [rootID].
+// [release] must be called on the returned snapshot once it's no longer used.
+// Returns [ErrInsufficientHistory] if [rootID] isn't the current root and isn't
+// in the history.
+// Assumes [db.lock] isn't held.
+func (db *merkleDB) newSnapshotAtRoot(rootID ids.ID) (*snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+
+	// The current root may not be in the history, for example if no history
+	// is kept.
+	if rootID == db.getMerkleRoot() {
+		return &snapshot{
+			db:           db,
+			insertNumber: db.history.addSnapshot(),
+			rootID:       rootID,
+		}, nil
+	}
+
+	insertNumber, err := db.history.addSnapshotAtRoot(rootID)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		db:           db,
+		insertNumber: insertNumber,
+		rootID:       rootID,
+	}, nil
+}
+
+// release allows the changes committed since the snapshot was taken to be
+// removed from the history. The snapshot must not be used afterwards.
+// Assumes [s.db.lock] isn't held.
+func (s *snapshot) release() {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	s.db.history.removeSnapshot(s.insertNumber)
+}
+
+// getNode returns the node with the given [key] as it was when the snapshot
+// was taken.
+// Editing the returned node affects the database state.
+// Returns database.ErrNotFound if the node doesn't exist.
+// Assumes [s.db.lock] is read locked.
+func (s *snapshot) getNode(key Key, hasValue bool) (*node, error) {
+	if s.db.closed {
+		return nil, database.ErrClosed
+	}
+	if nodeChange, ok := s.db.history.getNodeChangeSince(s.insertNumber, key); ok {
+		if nodeChange.before == nil {
+			return nil, database.ErrNotFound
+		}
+		return nodeChange.before, nil
+	}
+	return s.db.getNode(key, hasValue)
+}
+
+func (s *snapshot) getEditableNode(key Key, hasValue bool) (*node, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	n, err := s.getNode(key, hasValue)
+	if err != nil {
+		return nil, err
+	}
+	return n.clone(), nil
+}
+
+func (s *snapshot) getValue(key Key) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	n, err := s.getNode(key, true /* hasValue */)
+	if err != nil {
+		return nil, err
+	}
+	if n.value.IsNothing() {
+		return nil, database.ErrNotFound
+	}
+	return slices.Clone(n.value.Value()), nil
+}
+
+func (s *snapshot) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	return s.getValue(s.db.toKey(key))
+}
+
+func (s *snapshot) GetValues(_ context.Context, keys [][]byte) ([][]byte, []error) {
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		values[i], errs[i] = s.getValue(s.db.toKey(key))
+	}
+	return values, errs
+}
+
+func (s *snapshot) GetMerkleRoot(context.Context) (ids.ID, error) {
+	return s.rootID, nil
+}
+
+func (s *snapshot) GetProof(ctx context.Context, key []byte) (*Proof, error) {
+	view, err := newTrieView(s.db, s, ViewChanges{})
+	if err != nil {
+		return nil, err
+	}
+	// Don't need to lock [view] because nobody else has a reference to it.
+	return view.getProof(ctx, key)
+}
+
+func (s *snapshot) GetProofs(ctx context.Context, keys [][]byte) ([]*Proof, error) {
+	view, err := newTrieView(s.db, s, ViewChanges{})
+	if err != nil {
+		return nil, err
+	}
+	// Don't need to lock [view] because nobody else has a reference to it.
+	return view.getProofs(ctx, keys)
+}
+
+func (s *snapshot) GetRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	maxLength int,
+) (*RangeProof, error) {
+	// The deadline includes the time spent building [view].
+	deadline := s.db.proofDeadline()
+	view, err := newTrieView(s.db, s, ViewChanges{})
+	if err != nil {
+		return nil, err
+	}
+	return view.getRangeProof(ctx, start, end, maxLength, deadline)
+}
+
+func (s *snapshot) StreamRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	chunkSize int,
+	fn func(*RangeProof) error,
+) error {
+	view, err := newTrieView(s.db, s, ViewChanges{})
+	if err != nil {
+		return err
+	}
+	return view.streamRangeProof(ctx, start, end, chunkSize, fn)
+}
+
+func (*snapshot) NewView(context.Context, ViewChanges) (TrieView, error) {
+	return nil, errSnapshotReadOnly
+}
+
+func (*snapshot) CommitToDB(context.Context) error {
+	return errSnapshotReadOnly
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix returns an iterator over the key-value pairs
+// of the snapshot whose keys are >= [start] and have [prefix].
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &snapshotIterator{
+		snapshot:         s,
+		start:            slices.Clone(start),
+		prefix:           slices.Clone(prefix),
+		dbIter:           s.db.NewIteratorWithStartAndPrefix(start, prefix),
+		nextInsertNumber: s.insertNumber,
+	}
+}
+
+// snapshotIterator merges the key-value pairs of the database with the keys
+// changed since the snapshot was taken. The value of every key is read from
+// the snapshot, so pairs written after the snapshot was taken are skipped and
+// pairs removed after the snapshot was taken are still returned.
+//
+// [dbIter] is only advanced while [merkleDB.lock] is read locked, and the
+// keys changed by every commit recorded before that are added to
+// [changedKeys] first. So a key that [dbIter] skips because it was removed
+// after the snapshot was taken is always returned from [changedKeys].
+type snapshotIterator struct {
+	snapshot *snapshot
+
+	start, prefix []byte
+
+	dbIter database.Iterator
+
+	// Keys greater than [lastKey] whose values were changed since the snapshot
+	// was taken, in increasing order.
+	changedKeys [][]byte
+	// The insert number of the first change whose keys haven't been added to
+	// [changedKeys].
+	nextInsertNumber uint64
+
+	key, value []byte
+	err        error
+
+	// The greatest key returned by the iterator so far.
+	lastKey []byte
+
+	initialized, dbIterExhausted bool
+
+	// If true, [snapshot] is released when the iterator is.
+	ownsSnapshot bool
+}
+
+func (it *snapshotIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.snapshot.db.lock.RLock()
+	defer it.snapshot.db.lock.RUnlock()
+
+	hasNext, err := it.next()
+	if err != nil {
+		it.key = nil
+		it.value = nil
+		it.err = err
+		return false
+	}
+	return hasNext
+}
+
+// next moves the iterator to the next key-value pair of the snapshot.
+// It returns whether the iterator is exhausted.
+// Assumes [it.snapshot.db.lock] is read locked.
+func (it *snapshotIterator) next() (bool, error) {
+	s := it.snapshot
+	if s.db.closed {
+		return false, database.ErrClosed
+	}
+
+	if err := it.addChangedKeys(); err != nil {
+		return false, err
+	}
+	if !it.initialized {
+		it.dbIterExhausted = !it.dbIter.Next()
+		it.initialized = true
+	}
+
+	for {
+		var key []byte
+		switch {
+		case it.dbIterExhausted && len(it.changedKeys) == 0:
+			it.key = nil
+			it.value = nil
+			return false, it.dbIter.Error()
+		case it.dbIterExhausted:
+			key = it.changedKeys[0]
+			it.changedKeys = it.changedKeys[1:]
+		case len(it.changedKeys) == 0:
+			key = slices.Clone(it.dbIter.Key())
+			it.dbIterExhausted = !it.dbIter.Next()
+		default:
+			dbKey := it.dbIter.Key()
+			switch bytes.Compare(it.changedKeys[0], dbKey) {
+			case -1:
+				key = it.changedKeys[0]
+				it.changedKeys = it.changedKeys[1:]
+			case 1:
+				key = slices.Clone(dbKey)
+				it.dbIterExhausted = !it.dbIter.Next()
+			default:
+				key = it.changedKeys[0]
+				it.changedKeys = it.changedKeys[1:]
+				it.dbIterExhausted = !it.dbIter.Next()
+			}
+		}
+
+		n, err := s.getNode(s.db.toKey(key), true /* hasValue */)
+		if err == database.ErrNotFound {
+			// [key] was inserted after the snapshot was taken.
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if n.value.IsNothing() {
+			continue
+		}
+		it.key = key
+		it.value = slices.Clone(n.value.Value())
+		it.lastKey = key
+		return true, nil
+	}
+}
+
+// addChangedKeys adds the keys in range of the iterator that are greater than
+// [it.lastKey] and were changed by commits recorded since the last call.
+// Assumes [it.snapshot.db.lock] is read locked.
+func (it *snapshotIterator) addChangedKeys() error {
+	history := it.snapshot.db.history
+	if it.nextInsertNumber >= history.nextInsertNumber {
+		return nil
+	}
+
+	added := false
+	for _, changes := range history.getChangesSince(it.nextInsertNumber) {
+		err := changes.forEachValueChange(func(key Key, _ *change[maybe.Maybe[[]byte]]) error {
+			keyBytes := key.Bytes()
+			if bytes.Compare(keyBytes, it.start) < 0 ||
+				!bytes.HasPrefix(keyBytes, it.prefix) ||
+				(it.lastKey != nil && bytes.Compare(keyBytes, it.lastKey) <= 0) {
+				return nil
+			}
+			it.changedKeys = append(it.changedKeys, keyBytes)
+			added = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	it.nextInsertNumber = history.nextInsertNumber
+
+	if added {
+		slices.SortFunc(it.changedKeys, func(a, b []byte) bool {
+			return bytes.Compare(a, b) == -1
+		})
+		it.changedKeys = slices.CompactFunc(it.changedKeys, bytes.Equal)
+	}
+	return nil
+}
+
+func (it *snapshotIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.dbIter.Error()
+}
+
+func (it *snapshotIterator) Key() []byte {
+	return it.key
+}
+
+func (it *snapshotIterator) Value() []byte {
+	return it.value
+}
+
+func (it *snapshotIterator) Release() {
+	it.key = nil
+	it.value = nil
+	it.changedKeys = nil
+	it.dbIter.Release()
+
+	if it.ownsSnapshot {
+		it.ownsSnapshot = false
+		it.snapshot.release()
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+func TestSnapshotIgnoresLaterCommits(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	writeBasicBatch(t, db)
+	oldRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	snapshot, err := db.newSnapshot()
+	require.NoError(err)
+
+	batch := db.NewBatch()
+	require.NoError(batch.Put([]byte{0}, []byte{10}))
+	require.NoError(batch.Delete([]byte{1}))
+	require.NoError(batch.Put([]byte{5}, []byte{5}))
+	require.NoError(batch.Write())
+
+	root, err := snapshot.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(oldRoot, root)
+
+	values, errs := snapshot.GetValues(context.Background(), [][]byte{{0}, {1}, {5}})
+	require.NoError(errs[0])
+	require.Equal([]byte{0}, values[0])
+	require.NoError(errs[1])
+	require.Equal([]byte{1}, values[1])
+	require.ErrorIs(errs[2], database.ErrNotFound)
+
+	it := snapshot.NewIterator()
+	for i := byte(0); i < 5; i++ {
+		require.True(it.Next())
+		require.Equal([]byte{i}, it.Key())
+		require.Equal([]byte{i}, it.Value())
+	}
+
+	// A commit made during iteration isn't observed.
+	batch = db.NewBatch()
+	require.NoError(batch.Delete([]byte{4}))
+	require.NoError(batch.Write())
+
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+
+	proof, err := snapshot.GetRangeProof(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 10)
+	require.NoError(err)
+	require.Len(proof.KeyValues, 5)
+	require.NoError(proof.Verify(context.Background(), maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), oldRoot))
+
+	snapshot.release()
+	require.Empty(db.history.snapshots)
+}
+
+func TestSnapshotIteratorReturnsKeysRemovedDuringIteration(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	writeBasicBatch(t, db)
+
+	snapshot, err := db.newSnapshot()
+	require.NoError(err)
+	defer snapshot.release()
+
+	it := snapshot.NewIteratorWithStart([]byte{1})
+	defer it.Release()
+
+	require.True(it.Next())
+	require.Equal([]byte{1}, it.Key())
+
+	batch := db.NewBatch()
+	require.NoError(batch.Delete([]byte{2}))
+	require.NoError(batch.Delete([]byte{3}))
+	require.NoError(batch.Put([]byte{3, 0}, []byte{3}))
+	require.NoError(batch.Write())
+
+	for i := byte(2); i < 5; i++ {
+		require.True(it.Next())
+		require.Equal([]byte{i}, it.Key())
+		require.Equal([]byte{i}, it.Value())
+	}
+	require.False(it.Next())
+	require.NoError(it.Error())
+}
+
+func TestNewIteratorAtRoot(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.HistoryLength = 2
+	db, err := newDB(context.Background(), memdb.New(), config)
+	require.NoError(err)
+
+	writeBasicBatch(t, db)
+	oldRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	_, err = db.NewIteratorAtRoot(ids.GenerateTestID(), nil)
+	require.ErrorIs(err, ErrInsufficientHistory)
+
+	batch := db.NewBatch()
+	require.NoError(batch.Put([]byte{0}, []byte{10}))
+	require.NoError(batch.Delete([]byte{2}))
+	require.NoError(batch.Put([]byte{5}, []byte{5}))
+	require.NoError(batch.Write())
+
+	it, err := db.NewIteratorAtRoot(oldRoot, []byte{1})
+	require.NoError(err)
+	require.True(it.Next())
+	require.Equal([]byte{1}, it.Key())
+	require.Equal([]byte{1}, it.Value())
+
+	// Commits made during iteration aren't observed, and the history needed
+	// by the iterator is retained beyond [config.HistoryLength].
+	for i := byte(0); i < 5; i++ {
+		require.NoError(db.Delete([]byte{i}))
+	}
+	for i := byte(2); i < 5; i++ {
+		require.True(it.Next())
+		require.Equal([]byte{i}, it.Key())
+		require.Equal([]byte{i}, it.Value())
+	}
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+	require.Empty(db.history.snapshots)
+
+	// Once released, the history is pruned by the next commit.
+	require.NoError(db.Put([]byte{6}, []byte{6}))
+	_, err = db.NewIteratorAtRoot(oldRoot, nil)
+	require.ErrorIs(err, ErrInsufficientHistory)
+
+	// The current root is always available.
+	root, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	it, err = db.NewIteratorAtRoot(root, nil)
+	require.NoError(err)
+	require.True(it.Next())
+	require.Equal([]byte{5}, it.Key())
+	require.True(it.Next())
+	require.Equal([]byte{6}, it.Key())
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+	require.Empty(db.history.snapshots)
+}
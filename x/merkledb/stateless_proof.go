@@ -0,0 +1,255 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var _ pathInserter = (*proofTrie)(nil)
+
+// VerifyRangeProofStateless is [RangeProof.Verify] for callers without a
+// database, such as light clients. The trie that the proof is checked against
+// is only held in memory.
+//
+// [branchFactor] is the branch factor of the trie whose root is
+// [expectedRootID]. Node IDs are always SHA-256 hashes, so it's the only
+// parameter of the trie that its root depends on. Returns
+// [ErrInconsistentBranchFactor] if a key of [proof] has another branch factor.
+func VerifyRangeProofStateless(
+	proof *RangeProof,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	expectedRootID ids.ID,
+	branchFactor BranchFactor,
+) error {
+	switch {
+	case start.HasValue() && end.HasValue() && bytes.Compare(start.Value(), end.Value()) > 0:
+		return ErrStartAfterEnd
+	case len(proof.KeyValues) == 0 && len(proof.StartProof) == 0 && len(proof.EndProof) == 0:
+		return ErrNoMerkleProof
+	case end.IsNothing() && len(proof.KeyValues) == 0 && len(proof.StartProof) > 0 && len(proof.EndProof) != 0:
+		return ErrUnexpectedEndProof
+	case end.IsNothing() && len(proof.KeyValues) == 0 && len(proof.StartProof) == 0 && len(proof.EndProof) != 1:
+		return ErrShouldJustBeRoot
+	case len(proof.EndProof) == 0 && (end.HasValue() || len(proof.KeyValues) > 0):
+		return ErrNoEndProof
+	}
+	if err := branchFactor.Valid(); err != nil {
+		return err
+	}
+	for _, proofPath := range [][]ProofNode{proof.StartProof, proof.EndProof} {
+		for _, proofNode := range proofPath {
+			if proofNode.Key.branchFactor != branchFactor {
+				return ErrInconsistentBranchFactor
+			}
+		}
+	}
+
+	// Make sure the key-value pairs are sorted and in [start, end].
+	if err := verifyKeyValues(proof.KeyValues, start, end); err != nil {
+		return err
+	}
+
+	// [proof] allegedly provides and proves all key-value
+	// pairs in [smallestProvenPath, largestProvenPath].
+	// If [smallestProvenPath] is Nothing, [proof] should
+	// provide and prove all keys < [largestProvenPath].
+	// If [largestProvenPath] is Nothing, [proof] should
+	// provide and prove all keys > [smallestProvenPath].
+	// If both are Nothing, [proof] should prove the entire trie.
+	smallestProvenPath := maybe.Bind(start, func(b []byte) Key {
+		return ToKey(b, branchFactor)
+	})
+
+	largestProvenPath := maybe.Bind(end, func(b []byte) Key {
+		return ToKey(b, branchFactor)
+	})
+	if len(proof.KeyValues) > 0 {
+		// If [proof] has key-value pairs, we should insert children
+		// greater than [largestProvenPath] to ancestors of the node containing
+		// [largestProvenPath] so that we get the expected root ID.
+		largestProvenPath = maybe.Some(ToKey(proof.KeyValues[len(proof.KeyValues)-1].Key, branchFactor))
+	}
+
+	// The key-value pairs (allegedly) proven by [proof].
+	keyValues := make(map[Key][]byte, len(proof.KeyValues))
+	for _, keyValue := range proof.KeyValues {
+		keyValues[ToKey(keyValue.Key, branchFactor)] = keyValue.Value
+	}
+
+	// Ensure that the start proof is valid and contains values that
+	// match the key/values that were sent.
+	if err := verifyProofPath(proof.StartProof, smallestProvenPath); err != nil {
+		return err
+	}
+	if err := verifyAllRangeProofKeyValuesPresent(
+		proof.StartProof,
+		smallestProvenPath,
+		largestProvenPath,
+		keyValues,
+	); err != nil {
+		return err
+	}
+
+	// Ensure that the end proof is valid and contains values that
+	// match the key/values that were sent.
+	if err := verifyProofPath(proof.EndProof, largestProvenPath); err != nil {
+		return err
+	}
+	if err := verifyAllRangeProofKeyValuesPresent(
+		proof.EndProof,
+		smallestProvenPath,
+		largestProvenPath,
+		keyValues,
+	); err != nil {
+		return err
+	}
+
+	// For all the nodes along the edges of the proof, insert children
+	// < [smallestProvenPath] and > [largestProvenPath]
+	// into the trie so that we get the expected root ID (if this proof is valid).
+	// By inserting all children < [smallestProvenPath], we prove that there are no keys
+	// > [smallestProvenPath] but less than the first key given.
+	// That is, the peer who gave us this proof is not omitting nodes.
+	trie := newProofTrie(branchFactor)
+	if err := addPathInfo(
+		trie,
+		proof.StartProof,
+		smallestProvenPath,
+		largestProvenPath,
+	); err != nil {
+		return err
+	}
+	if err := addPathInfo(
+		trie,
+		proof.EndProof,
+		smallestProvenPath,
+		largestProvenPath,
+	); err != nil {
+		return err
+	}
+
+	// Insert all key-value pairs into the trie. Like the changes of a view,
+	// they're applied after the proof nodes.
+	for _, keyValue := range proof.KeyValues {
+		if _, err := trie.insert(ToKey(keyValue.Key, branchFactor), maybe.Some(keyValue.Value)); err != nil {
+			return err
+		}
+	}
+
+	calculatedRoot := trie.calculateRootID()
+	if expectedRootID != calculatedRoot {
+		return fmt.Errorf("%w:[%s], expected:[%s]", ErrInvalidProof, calculatedRoot, expectedRootID)
+	}
+	return nil
+}
+
+// proofTrie is a trie held in memory, which is built from a proof to
+// calculate the root the proof is for.
+// The children of the nodes that aren't in [nodes] are only known by their
+// IDs.
+type proofTrie struct {
+	root *node
+	// Key --> The node with that key.
+	nodes map[Key]*node
+}
+
+func newProofTrie(branchFactor BranchFactor) *proofTrie {
+	root := newNode(nil, emptyKey(branchFactor))
+	return &proofTrie{
+		root:  root,
+		nodes: map[Key]*node{root.key: root},
+	}
+}
+
+func (t *proofTrie) insert(key Key, value maybe.Maybe[[]byte]) (*node, error) {
+	n := t.root
+	for n.key != key {
+		// [n.key] is a strict prefix of [key].
+		index := key.Token(n.key.tokenLength)
+		entry, ok := n.children[index]
+		if !ok {
+			newNode := newNode(n, key)
+			newNode.setValue(value)
+			t.nodes[key] = newNode
+			return newNode, nil
+		}
+
+		childKey := n.key.AppendExtend(index, entry.compressedKey)
+		if !key.HasPrefix(childKey) {
+			return t.insertBranch(n, entry, key, value), nil
+		}
+		child, ok := t.nodes[childKey]
+		if !ok {
+			return nil, fmt.Errorf("%w: node %x is only known by its ID", ErrInvalidProof, childKey.Bytes())
+		}
+		n = child
+	}
+	n.setValue(value)
+	return n, nil
+}
+
+// insertBranch inserts [value] at [key], where [entry] is the child of [n] on
+// the path to [key] but isn't a prefix of it. A branch node with the common
+// prefix of the two becomes the parent of both. Returns the node with [key].
+func (t *proofTrie) insertBranch(n *node, entry child, key Key, value maybe.Maybe[[]byte]) *node {
+	commonPrefixLength := getLengthOfCommonPrefix(entry.compressedKey, key, n.key.tokenLength+1)
+	branchNode := newNode(n, key.Take(n.key.tokenLength+1+commonPrefixLength))
+	t.nodes[branchNode.key] = branchNode
+	branchNode.setChildEntry(
+		entry.compressedKey.Token(commonPrefixLength),
+		child{
+			compressedKey: entry.compressedKey.Skip(commonPrefixLength + 1),
+			id:            entry.id,
+			hasValue:      entry.hasValue,
+		},
+	)
+
+	if key == branchNode.key {
+		branchNode.setValue(value)
+		return branchNode
+	}
+	newNode := newNode(branchNode, key)
+	newNode.setValue(value)
+	t.nodes[key] = newNode
+	return newNode
+}
+
+// calculateRootID calculates the IDs of the nodes of the trie, and returns
+// the ID of the root.
+func (t *proofTrie) calculateRootID() ids.ID {
+	t.calculateID(t.root, &mockMetrics{})
+	return t.root.id
+}
+
+// calculateID calculates the IDs of [n] and its descendants in [t.nodes].
+func (t *proofTrie) calculateID(n *node, metrics merkleMetrics) {
+	childIndices := make([]byte, 0, len(n.children))
+	for index := range n.children {
+		childIndices = append(childIndices, index)
+	}
+	slices.Sort(childIndices)
+
+	for _, index := range childIndices {
+		entry := n.children[index]
+		childNode, ok := t.nodes[n.key.AppendExtend(index, entry.compressedKey)]
+		if !ok {
+			continue
+		}
+		t.calculateID(childNode, metrics)
+		n.setChildEntry(index, child{
+			compressedKey: entry.compressedKey,
+			id:            childNode.id,
+			hasValue:      childNode.hasValue(),
+		})
+	}
+	n.calculateID(metrics)
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var statsKey = []byte(string(metadataPrefix) + "stats")
+
+// Stats describes the nodes of the trie at the current root.
+type Stats struct {
+	// The number of nodes in the trie, including the root.
+	NodeCount uint64
+	// The number of nodes with a value, which is the number of key-value pairs.
+	ValueCount uint64
+	// The average number of children of the nodes.
+	AverageChildren float64
+	// The sum of the sizes of the encoded nodes, excluding the keys they're
+	// stored under.
+	SerializedSize uint64
+	// DepthHistogram[i] is the number of nodes whose key is i tokens long.
+	// Since keys are compressed, this is the depth of the node in the
+	// uncompressed trie.
+	DepthHistogram []uint64
+}
+
+// nodeStats are the totals that [Stats] is computed from.
+// They're updated with the nodes changed by each commit.
+type nodeStats struct {
+	nodeCount      uint64
+	valueCount     uint64
+	childCount     uint64
+	serializedSize uint64
+	depthHistogram []uint64
+}
+
+func (s *nodeStats) add(n *node) {
+	s.nodeCount++
+	if n.hasValue() {
+		s.valueCount++
+	}
+	s.childCount += uint64(len(n.children))
+	s.serializedSize += uint64(len(n.bytes()))
+
+	depth := n.key.tokenLength
+	for len(s.depthHistogram) <= depth {
+		s.depthHistogram = append(s.depthHistogram, 0)
+	}
+	s.depthHistogram[depth]++
+}
+
+// remove undoes the [add] of [n].
+func (s *nodeStats) remove(n *node) {
+	s.nodeCount--
+	if n.hasValue() {
+		s.valueCount--
+	}
+	s.childCount -= uint64(len(n.children))
+	s.serializedSize -= uint64(len(n.bytes()))
+	s.depthHistogram[n.key.tokenLength]--
+}
+
+// record updates the stats with the node changes of a commit.
+func (s *nodeStats) record(changes *changeSummary) {
+	for _, nodeChange := range changes.nodes {
+		if nodeChange.before != nil {
+			s.remove(nodeChange.before)
+		}
+		if nodeChange.after != nil {
+			s.add(nodeChange.after)
+		}
+	}
+}
+
+func (s *nodeStats) bytes() []byte {
+	b := &bytes.Buffer{}
+	codec.encodeUint(b, s.nodeCount)
+	codec.encodeUint(b, s.valueCount)
+	codec.encodeUint(b, s.childCount)
+	codec.encodeUint(b, s.serializedSize)
+	codec.encodeUint(b, uint64(len(s.depthHistogram)))
+	for _, count := range s.depthHistogram {
+		codec.encodeUint(b, count)
+	}
+	return b.Bytes()
+}
+
+func parseNodeStats(b []byte) (*nodeStats, error) {
+	var (
+		src    = bytes.NewReader(b)
+		s      = &nodeStats{}
+		fields = []*uint64{&s.nodeCount, &s.valueCount, &s.childCount, &s.serializedSize}
+		err    error
+	)
+	for _, field := range fields {
+		if *field, err = codec.decodeUint(src); err != nil {
+			return nil, err
+		}
+	}
+	depths, err := codec.decodeUint(src)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < depths; i++ {
+		count, err := codec.decodeUint(src)
+		if err != nil {
+			return nil, err
+		}
+		s.depthHistogram = append(s.depthHistogram, count)
+	}
+	if src.Len() != 0 {
+		return nil, errExtraSpace
+	}
+	return s, nil
+}
+
+func (db *merkleDB) Stats(context.Context) (Stats, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return Stats{}, database.ErrClosed
+	}
+
+	histogram := db.stats.depthHistogram
+	for len(histogram) > 0 && histogram[len(histogram)-1] == 0 {
+		histogram = histogram[:len(histogram)-1]
+	}
+	return Stats{
+		NodeCount:       db.stats.nodeCount,
+		ValueCount:      db.stats.valueCount,
+		AverageChildren: float64(db.stats.childCount) / float64(db.stats.nodeCount),
+		SerializedSize:  db.stats.serializedSize,
+		DepthHistogram:  slices.Clone(histogram),
+	}, nil
+}
+
+// writeStats saves the stats so that they can be used on the next startup
+// instead of being recomputed.
+//
+// Must be called once no more nodes will be committed.
+func (db *merkleDB) writeStats() error {
+	return db.baseDB.Put(statsKey, db.stats.bytes())
+}
+
+// loadStats returns the stats saved by the last [writeStats] and deletes them,
+// so that they aren't used again after a later unclean shutdown.
+// Returns nil if no stats were saved.
+func (db *merkleDB) loadStats() (*nodeStats, error) {
+	statsBytes, err := db.baseDB.Get(statsKey)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.baseDB.Delete(statsKey); err != nil {
+		return nil, err
+	}
+	return parseNodeStats(statsBytes)
+}
+
+// initStats sets [db.stats] to [loaded], the stats returned by [loadStats].
+// If no stats were saved, which is the case for databases created before the
+// stats were, they're computed by visiting every node of the trie once.
+//
+// Must be called before the database is used.
+func (db *merkleDB) initStats(loaded *nodeStats) error {
+	if loaded != nil {
+		db.stats = loaded
+		return nil
+	}
+
+	db.stats = &nodeStats{}
+	nodes := []*node{db.root}
+	for len(nodes) > 0 {
+		n := nodes[len(nodes)-1]
+		nodes = nodes[:len(nodes)-1]
+		db.stats.add(n)
+
+		for index, entry := range n.children {
+			child, err := db.getNode(n.key.AppendExtend(index, entry.compressedKey), entry.hasValue)
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, child)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+// requireStatsMatchTrie requires that the stats maintained by [db] are the
+// stats computed by visiting every node of its trie.
+func requireStatsMatchTrie(t *testing.T, db *merkleDB) {
+	require := require.New(t)
+
+	stats, err := db.Stats(context.Background())
+	require.NoError(err)
+
+	maintained := db.stats
+	require.NoError(db.initStats(nil))
+	expected, err := db.Stats(context.Background())
+	require.NoError(err)
+	db.stats = maintained
+
+	require.Equal(expected, stats)
+}
+
+func Test_MerkleDB_Stats(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db, err := newDB(context.Background(), baseDB, newDefaultConfig())
+	require.NoError(err)
+
+	stats, err := db.Stats(context.Background())
+	require.NoError(err)
+	require.Equal(uint64(1), stats.NodeCount)
+	require.Zero(stats.ValueCount)
+	require.Zero(stats.AverageChildren)
+	require.Equal([]uint64{1}, stats.DepthHistogram)
+
+	writeBasicBatch(t, db)
+	stats, err = db.Stats(context.Background())
+	require.NoError(err)
+	// The keys {0}, ..., {4} are 2 tokens long and share their first token,
+	// so they're the children of a node that's the only child of the root.
+	require.Equal(uint64(7), stats.NodeCount)
+	require.Equal(uint64(5), stats.ValueCount)
+	require.Equal(float64(6)/7, stats.AverageChildren)
+	require.Equal([]uint64{1, 1, 5}, stats.DepthHistogram)
+	requireStatsMatchTrie(t, db)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+	for i := 0; i < 20; i++ {
+		ops := make([]database.BatchOp, 0, 50)
+		for j := 0; j < 50; j++ {
+			key := make([]byte, r.Intn(4))
+			_, _ = r.Read(key)
+			ops = append(ops, database.BatchOp{
+				Key:    key,
+				Value:  key,
+				Delete: r.Intn(3) == 0,
+			})
+		}
+		view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+		require.NoError(err)
+		require.NoError(view.CommitToDB(context.Background()))
+		requireStatsMatchTrie(t, db)
+	}
+	stats, err = db.Stats(context.Background())
+	require.NoError(err)
+
+	// The stats are saved on a clean shutdown.
+	require.NoError(db.Close())
+	db, err = newDB(context.Background(), baseDB, newDefaultConfig())
+	require.NoError(err)
+	reopenedStats, err := db.Stats(context.Background())
+	require.NoError(err)
+	require.Equal(stats, reopenedStats)
+
+	// The stats are recomputed after an unclean shutdown.
+	require.NoError(baseDB.Put(cleanShutdownKey, didNotHaveCleanShutdown))
+	db, err = newDB(context.Background(), baseDB, newDefaultConfig())
+	require.NoError(err)
+	reopenedStats, err = db.Stats(context.Background())
+	require.NoError(err)
+	require.Equal(stats, reopenedStats)
+
+	require.NoError(db.Close())
+	_, err = db.Stats(context.Background())
+	require.ErrorIs(err, database.ErrClosed)
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/buffer"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+// tombstone records that [key] was deleted by the commit that resulted in
+// [rootID].
+type tombstone struct {
+	key    Key
+	rootID ids.ID
+	// The number of the commit that deleted [key].
+	commitNumber uint64
+}
+
+// tombstones remembers the keys deleted by the last [window] commits.
+// Not safe for concurrent use.
+type tombstones struct {
+	// If 0, no tombstones are recorded.
+	window int
+
+	// The number of commits recorded so far.
+	commitNumber uint64
+
+	// Key --> the most recent tombstone of that key.
+	byKey map[Key]*tombstone
+	// Tombstones in the order they were recorded. May contain tombstones that
+	// were replaced in [byKey] by a more recent one.
+	ordered buffer.Deque[*tombstone]
+}
+
+func newTombstones(window int) *tombstones {
+	return &tombstones{
+		window:  window,
+		byKey:   make(map[Key]*tombstone),
+		ordered: buffer.NewUnboundedDeque[*tombstone](window),
+	}
+}
+
+// record the keys deleted by [changes] and forget the tombstones of keys that
+// were re-inserted.
+// If the spilled value changes of [changes] can't be read, every tombstone is
+// forgotten, since a re-inserted key may otherwise keep its tombstone.
+func (t *tombstones) record(changes *changeSummary) {
+	if t.window == 0 {
+		return
+	}
+
+	t.commitNumber++
+	err := changes.forEachValueChange(func(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
+		if valueChange.after.HasValue() {
+			delete(t.byKey, key)
+			return nil
+		}
+		if valueChange.before.IsNothing() {
+			// The key didn't exist before this commit.
+			return nil
+		}
+
+		ts := &tombstone{
+			key:          key,
+			rootID:       changes.rootID,
+			commitNumber: t.commitNumber,
+		}
+		t.byKey[key] = ts
+		t.ordered.PushRight(ts)
+		return nil
+	})
+	if err != nil {
+		t.byKey = make(map[Key]*tombstone)
+		t.ordered = buffer.NewUnboundedDeque[*tombstone](t.window)
+		return
+	}
+	t.prune()
+}
+
+// prune removes the tombstones that were recorded more than [window] commits
+// ago.
+func (t *tombstones) prune() {
+	for {
+		oldest, ok := t.ordered.PeekLeft()
+		if !ok || oldest.commitNumber+uint64(t.window) > t.commitNumber {
+			return
+		}
+		_, _ = t.ordered.PopLeft()
+		if t.byKey[oldest.key] == oldest {
+			delete(t.byKey, oldest.key)
+		}
+	}
+}
+
+// get returns the root of the trie once [key] was deleted. Returns false if
+// [key] wasn't deleted within the window or was re-inserted since.
+func (t *tombstones) get(key Key) (ids.ID, bool) {
+	ts, ok := t.byKey[key]
+	if !ok {
+		return ids.Empty, false
+	}
+	return ts.rootID, true
+}
@@ -3,7 +3,18 @@
 
 package merkledb
 
-import "github.com/ava-labs/avalanchego/trace"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils"
+)
 
 const (
 	DebugTrace TraceLevel = iota - 1
@@ -11,11 +22,65 @@ const (
 	NoTrace
 )
 
+var ErrUnknownTraceLevel = errors.New("unknown trace level")
+
 type TraceLevel int
 
-func getTracerIfEnabled(level, minLevel TraceLevel, tracer trace.Tracer) trace.Tracer {
-	if level <= minLevel {
-		return tracer
+func ToTraceLevel(s string) (TraceLevel, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return DebugTrace, nil
+	case "info":
+		return InfoTrace, nil
+	case "off":
+		return NoTrace, nil
+	default:
+		return NoTrace, fmt.Errorf("%w: %q", ErrUnknownTraceLevel, s)
+	}
+}
+
+func (l TraceLevel) String() string {
+	switch l {
+	case DebugTrace:
+		return "debug"
+	case InfoTrace:
+		return "info"
+	case NoTrace:
+		return "off"
+	default:
+		return fmt.Sprintf("TraceLevel(%d)", int(l))
+	}
+}
+
+func (l TraceLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+func (l *TraceLevel) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	var err error
+	*l, err = ToTraceLevel(s)
+	return err
+}
+
+// levelTracer only starts spans with [tracer] while [level] is at most
+// [minLevel]. Otherwise, spans are started with [trace.Noop].
+type levelTracer struct {
+	minLevel TraceLevel
+	level    *utils.Atomic[TraceLevel]
+	tracer   trace.Tracer
+}
+
+func (t *levelTracer) Start(
+	ctx context.Context,
+	spanName string,
+	opts ...oteltrace.SpanStartOption,
+) (context.Context, oteltrace.Span) {
+	if t.level.Get() <= t.minLevel {
+		return t.tracer.Start(ctx, spanName, opts...)
 	}
-	return trace.Noop
+	return trace.Noop.Start(ctx, spanName, opts...)
 }
@@ -18,7 +18,9 @@ type MerkleRootGetter interface {
 
 type ProofGetter interface {
 	// GetProof generates a proof of the value associated with a particular key,
-	// or a proof of its absence from the trie
+	// or a proof of its absence from the trie.
+	// A view's proofs are against the view's root, even if the view hasn't
+	// been committed.
 	GetProof(ctx context.Context, keyBytes []byte) (*Proof, error)
 }
 
@@ -48,12 +50,38 @@ type ReadOnlyTrie interface {
 	// If [end] is Nothing, there's no upper bound on the range.
 	GetRangeProof(ctx context.Context, start maybe.Maybe[[]byte], end maybe.Maybe[[]byte], maxLength int) (*RangeProof, error)
 
+	// StreamRangeProof calls [fn] with consecutive range proofs of up to
+	// [chunkSize] key-value pairs each, which together cover the key range
+	// [start, end]. Each proof starts right after the greatest key of the
+	// previous one. Stops and returns the error if [fn] returns an error.
+	StreamRangeProof(
+		ctx context.Context,
+		start maybe.Maybe[[]byte],
+		end maybe.Maybe[[]byte],
+		chunkSize int,
+		fn func(*RangeProof) error,
+	) error
+
+	// GetProofs returns the proofs of [keys], in the same order. The proofs
+	// are the same as those returned by GetProof, but the nodes on the paths
+	// to multiple keys are only read once.
+	GetProofs(ctx context.Context, keys [][]byte) ([]*Proof, error)
+
 	database.Iteratee
 }
 
 type ViewChanges struct {
+	// BatchOps are applied in order. If their keys are strictly increasing and
+	// there are no MapOps, the view is built without traversing from the root
+	// for every key.
 	BatchOps []database.BatchOp
 	MapOps   map[string]maybe.Maybe[[]byte]
+	// DeletePrefixes are applied before [BatchOps] and [MapOps]. Every key
+	// that starts with one of them is deleted, so [BatchOps] and [MapOps]
+	// can put keys under a prefix that is cleared. The deleted keys are
+	// found by iterating over the parent, and each one is recorded as a
+	// deletion so that it's removed from disk and included in change proofs.
+	DeletePrefixes [][]byte
 	// ConsumeBytes when set to true will skip copying of bytes and assume
 	// ownership of the provided bytes.
 	ConsumeBytes bool
@@ -76,4 +104,30 @@ type TrieView interface {
 	// CommitToDB writes the changes in this view to the database.
 	// Takes the DB commit lock.
 	CommitToDB(ctx context.Context) error
+
+	// Diff returns the changes that turn this trie into [other], sorted by
+	// key. Subtrees that are the same in both tries aren't read, so diffing
+	// views that share a recent ancestor only reads the nodes on the paths to
+	// the keys changed since that ancestor.
+	// Returns [ErrDiffOtherDatabase] if [other] isn't of the same database.
+	Diff(ctx context.Context, other TrieView) ([]KeyChange, error)
+
+	// RebaseOnto returns a new view on top of [newParent] with the value
+	// changes made by this view, so that the work done to build this view
+	// survives the commit of a sibling view, which invalidates this view.
+	// Keys deleted by a prefix deletion of this view are deleted from
+	// [newParent] by the same prefix deletion. This view isn't changed, and
+	// its descendants aren't rebased.
+	// Returns [ErrRebaseOtherDatabase] if [newParent] isn't of the same
+	// database.
+	RebaseOnto(ctx context.Context, newParent TrieView) (TrieView, error)
+
+	// Release indicates that this view won't be used again. Views are tracked
+	// by their parent until the next commit, so that they can be invalidated.
+	// Once a view and all of its descendants are released, they're no longer
+	// tracked, so they can be garbage collected right away.
+	// Releasing a view doesn't affect its descendants, which can still be
+	// read. NewView and CommitToDB on a released view return
+	// [ErrViewReleased].
+	Release()
 }
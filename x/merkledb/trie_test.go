@@ -4,18 +4,26 @@
 package merkledb
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/maybe"
 )
 
 func getNodeValue(t ReadOnlyTrie, key string) ([]byte, error) {
@@ -320,7 +328,7 @@ func Test_Trie_WriteToDB(t *testing.T) {
 	rawBytes, err := dbTrie.baseDB.Get(prefixedKey)
 	require.NoError(err)
 
-	node, err := parseNode(ToKey(key, BranchFactor16), rawBytes)
+	node, err := dbTrie.valueNodeDB.parseValueNode(ToKey(key, BranchFactor16), rawBytes)
 	require.NoError(err)
 	require.Equal([]byte("value"), node.value.Value())
 }
@@ -740,6 +748,68 @@ func Test_Trie_BatchApply(t *testing.T) {
 	require.ErrorIs(err, database.ErrNotFound)
 }
 
+func Test_Trie_SortedBatchApply(t *testing.T) {
+	for _, bf := range branchFactors {
+		t.Run(strconv.Itoa(int(bf)), func(t *testing.T) {
+			require := require.New(t)
+
+			now := time.Now().UnixNano()
+			t.Logf("seed: %d", now)
+			r := rand.New(rand.NewSource(now)) // #nosec G404
+
+			db, err := getBasicDBWithBranchFactor(bf)
+			require.NoError(err)
+
+			existingKeys := make([][]byte, 256)
+			for i := range existingKeys {
+				existingKeys[i] = make([]byte, r.Intn(8)+1)
+				_, _ = r.Read(existingKeys[i])
+				require.NoError(db.Put(existingKeys[i], existingKeys[i]))
+			}
+
+			ops := make([]database.BatchOp, 0, 512)
+			for _, key := range existingKeys[:128] {
+				ops = append(ops, database.BatchOp{
+					Key:    key,
+					Delete: true,
+				})
+			}
+			for i := 0; i < 384; i++ {
+				key := make([]byte, r.Intn(8)+1)
+				_, _ = r.Read(key)
+				ops = append(ops, database.BatchOp{
+					Key:   key,
+					Value: []byte{byte(i)},
+				})
+			}
+			// Keep the last op for each key so that the sorted and unsorted
+			// changes match.
+			opsByKey := make(map[string]database.BatchOp)
+			for _, op := range ops {
+				opsByKey[string(op.Key)] = op
+			}
+			sortedOps := maps.Values(opsByKey)
+			slices.SortFunc(sortedOps, func(a, b database.BatchOp) bool {
+				return bytes.Compare(a.Key, b.Key) < 0
+			})
+
+			sortedView, err := db.NewView(context.Background(), ViewChanges{BatchOps: sortedOps})
+			require.NoError(err)
+			require.NotNil(sortedView.(*trieView).sortedKeys)
+
+			unsortedView, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+			require.NoError(err)
+			require.Nil(unsortedView.(*trieView).sortedKeys)
+
+			expectedRoot, err := unsortedView.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			root, err := sortedView.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(expectedRoot, root)
+		})
+	}
+}
+
 func Test_Trie_ChainDeletion(t *testing.T) {
 	require := require.New(t)
 
@@ -1213,6 +1283,112 @@ func Test_Trie_ConcurrentNewViewAndCommit(t *testing.T) {
 	require.NotNil(newView)
 }
 
+// onGetDB calls [onGet] before each read.
+type onGetDB struct {
+	database.Database
+	onGet func()
+}
+
+func (db *onGetDB) Get(key []byte) ([]byte, error) {
+	if db.onGet != nil {
+		db.onGet()
+	}
+	return db.Database.Get(key)
+}
+
+func Test_Trie_GetProof_During_Commit(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := &onGetDB{Database: memdb.New()}
+	db, err := newDatabase(context.Background(), baseDB, newDefaultConfig(), &mockMetrics{})
+	require.NoError(err)
+	require.NoError(db.Put([]byte("a"), []byte("a")))
+	require.NoError(db.Put([]byte("b"), []byte("b")))
+	require.NoError(db.Close())
+
+	// Reopen the database so that the nodes are read from disk.
+	db, err = newDatabase(context.Background(), baseDB, newDefaultConfig(), &mockMetrics{})
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+	_, err = view.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// The root is in memory, so the node of "a", which is the last node of
+	// its proof, is the second node read after the intermediate node shared
+	// with "b". Commit a change to it before it's read.
+	var numReads int
+	baseDB.onGet = func() {
+		numReads++
+		if numReads < 2 {
+			return
+		}
+		baseDB.onGet = nil
+		require.NoError(db.Put([]byte("a"), []byte("new value")))
+	}
+	_, err = view.GetProof(context.Background(), []byte("a"))
+	require.ErrorIs(err, ErrInvalid)
+	require.Nil(baseDB.onGet)
+}
+
+func Test_Trie_GetValue_During_CalculateNodeIDs(t *testing.T) {
+	require := require.New(t)
+
+	config := newDefaultConfig()
+	config.RootGenConcurrency = 1
+	db, err := newDatabase(
+		context.Background(),
+		memdb.New(),
+		config,
+		&mockMetrics{},
+	)
+	require.NoError(err)
+
+	const numKeys = 256
+	ops := make([]database.BatchOp, numKeys)
+	for i := range ops {
+		key := []byte(strconv.Itoa(i))
+		ops[i] = database.BatchOp{Key: key, Value: key}
+	}
+	view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+
+	// Prevent the node IDs from being hashed.
+	require.NoError(db.calculateNodeIDsSema.Acquire(context.Background(), 1))
+
+	rootCalculated := make(chan struct{})
+	go func() {
+		defer close(rootCalculated)
+		_, err := view.GetMerkleRoot(context.Background())
+		require.NoError(err)
+	}()
+
+	// Reads of the view's values must not wait for the node IDs.
+	for _, op := range ops {
+		value, err := view.GetValue(context.Background(), op.Key)
+		require.NoError(err)
+		require.Equal(op.Value, value)
+	}
+	it := view.NewIterator()
+	var numIterated int
+	for it.Next() {
+		numIterated++
+	}
+	require.NoError(it.Error())
+	it.Release()
+	require.Equal(numKeys, numIterated)
+
+	select {
+	case <-rootCalculated:
+		require.FailNow("node IDs calculated while hashing was blocked")
+	default:
+	}
+
+	db.calculateNodeIDsSema.Release(1)
+	<-rootCalculated
+}
+
 // Returns the path of the only child of this node.
 // Assumes this node has exactly one child.
 func getSingleChildKey(n *node) Key {
@@ -1322,3 +1498,313 @@ func TestTrieCommitToDB(t *testing.T) {
 	r.NoError(err)
 	r.Equal(value3, got)
 }
+
+func Benchmark_TrieView_CalculateNodeIDs(b *testing.B) {
+	const (
+		numInitialKeys = 10_000
+		numChangedKeys = 1_000
+	)
+
+	for _, bf := range branchFactors {
+		b.Run(fmt.Sprintf("branch_factor_%d", bf), func(b *testing.B) {
+			require := require.New(b)
+
+			db, err := getBasicDBWithBranchFactor(bf)
+			require.NoError(err)
+
+			r := rand.New(rand.NewSource(0)) // #nosec G404
+			ops := make([]database.BatchOp, numInitialKeys)
+			for i := range ops {
+				ops[i] = database.BatchOp{
+					Key:   hashing.ComputeHash256(binary.AppendUvarint(nil, uint64(i))),
+					Value: []byte{byte(i)},
+				}
+			}
+			view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+			require.NoError(err)
+			require.NoError(view.CommitToDB(context.Background()))
+
+			ops = make([]database.BatchOp, numChangedKeys)
+			for i := range ops {
+				ops[i] = database.BatchOp{
+					Key:   hashing.ComputeHash256(binary.AppendUvarint(nil, uint64(r.Intn(2*numInitialKeys)))),
+					Value: []byte{byte(i)},
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+				require.NoError(err)
+				_, err = view.GetMerkleRoot(context.Background())
+				require.NoError(err)
+			}
+		})
+	}
+}
+
+func Benchmark_TrieView_GetProofStacked(b *testing.B) {
+	const (
+		numInitialKeys = 10_000
+		numChangedKeys = 100
+	)
+
+	for _, numViews := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("views_%d", numViews), func(b *testing.B) {
+			require := require.New(b)
+
+			db, err := getBasicDB()
+			require.NoError(err)
+
+			keys := make([][]byte, numInitialKeys)
+			ops := make([]database.BatchOp, numInitialKeys)
+			for i := range ops {
+				keys[i] = hashing.ComputeHash256(binary.AppendUvarint(nil, uint64(i)))
+				ops[i] = database.BatchOp{
+					Key:   keys[i],
+					Value: []byte{byte(i)},
+				}
+			}
+			view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+			require.NoError(err)
+			require.NoError(view.CommitToDB(context.Background()))
+
+			r := rand.New(rand.NewSource(0)) // #nosec G404
+			var stacked TrieView = db
+			for i := 0; i < numViews; i++ {
+				ops := make([]database.BatchOp, numChangedKeys)
+				for j := range ops {
+					ops[j] = database.BatchOp{
+						Key:   keys[r.Intn(numInitialKeys)],
+						Value: []byte{byte(i), byte(j)},
+					}
+				}
+				stacked, err = stacked.NewView(context.Background(), ViewChanges{BatchOps: ops})
+				require.NoError(err)
+			}
+			_, err = stacked.GetMerkleRoot(context.Background())
+			require.NoError(err)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := stacked.GetProof(context.Background(), keys[i%numInitialKeys])
+				require.NoError(err)
+			}
+		})
+	}
+}
+
+func Benchmark_TrieView_GetProofs(b *testing.B) {
+	const numKeys = 10_000
+
+	require := require.New(b)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	keys := make([][]byte, numKeys)
+	ops := make([]database.BatchOp, numKeys)
+	for i := range ops {
+		keys[i] = hashing.ComputeHash256(binary.AppendUvarint(nil, uint64(i)))
+		ops[i] = database.BatchOp{
+			Key:   keys[i],
+			Value: []byte{byte(i)},
+		}
+	}
+	view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+
+	view, err = db.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+
+	for _, numProofs := range []int{10, 100, 1_000} {
+		proofKeys := keys[:numProofs]
+
+		b.Run(fmt.Sprintf("GetProof_%d", numProofs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, key := range proofKeys {
+					_, err := view.GetProof(context.Background(), key)
+					require.NoError(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("GetProofs_%d", numProofs), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := view.GetProofs(context.Background(), proofKeys)
+				require.NoError(err)
+			}
+		})
+	}
+}
+
+// Test that the nodes of a view, and the input hashed to calculate their IDs,
+// don't depend on the order in which the changes are given.
+func Test_Trie_DeterministicHashInput(t *testing.T) {
+	for _, bf := range branchFactors {
+		t.Run(strconv.Itoa(int(bf)), func(t *testing.T) {
+			require := require.New(t)
+
+			now := time.Now().UnixNano()
+			t.Logf("seed: %d", now)
+			r := rand.New(rand.NewSource(now)) // #nosec G404
+
+			db, err := getBasicDBWithBranchFactor(bf)
+			require.NoError(err)
+
+			mapOps := make(map[string]maybe.Maybe[[]byte])
+			for i := 0; i < 512; i++ {
+				key := make([]byte, r.Intn(8)+1)
+				_, _ = r.Read(key)
+				mapOps[string(key)] = maybe.Some(key)
+			}
+			ops := make([]database.BatchOp, 0, len(mapOps))
+			for key, value := range mapOps {
+				ops = append(ops, database.BatchOp{
+					Key:   []byte(key),
+					Value: value.Value(),
+				})
+			}
+			r.Shuffle(len(ops), func(i, j int) {
+				ops[i], ops[j] = ops[j], ops[i]
+			})
+			sortedOps := slices.Clone(ops)
+			slices.SortFunc(sortedOps, func(a, b database.BatchOp) bool {
+				return bytes.Compare(a.Key, b.Key) < 0
+			})
+
+			// hashInputs returns the input hashed to calculate the ID of each
+			// node of a view with [changes].
+			hashInputs := func(changes ViewChanges) map[Key][]byte {
+				view, err := db.NewView(context.Background(), changes)
+				require.NoError(err)
+				trie := view.(*trieView)
+				require.NoError(trie.calculateNodeIDs(context.Background()))
+
+				inputs := make(map[Key][]byte, len(trie.changes.nodes))
+				for key, change := range trie.changes.nodes {
+					if change.after == nil {
+						continue
+					}
+					buf := &bytes.Buffer{}
+					codec.encodeHashValues(buf, &hashValues{
+						Children: change.after.children,
+						Value:    change.after.valueDigest,
+						Key:      change.after.key,
+					})
+					require.Equal(ids.ID(hashing.ComputeHash256Array(buf.Bytes())), change.after.id)
+					inputs[key] = buf.Bytes()
+				}
+				return inputs
+			}
+
+			expected := hashInputs(ViewChanges{MapOps: mapOps})
+			for i := 0; i < 3; i++ {
+				require.Equal(expected, hashInputs(ViewChanges{MapOps: mapOps}))
+				require.Equal(expected, hashInputs(ViewChanges{BatchOps: ops}))
+				require.Equal(expected, hashInputs(ViewChanges{BatchOps: sortedOps}))
+			}
+		})
+	}
+}
+
+func Test_TrieView_DeletePrefixes(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	// Keys start with one of a few bytes so that prefixes match many keys.
+	ops := make([]database.BatchOp, 0, 200)
+	for i := 0; i < cap(ops); i++ {
+		key := make([]byte, r.Intn(4)+1)
+		_, _ = r.Read(key)
+		key[0] %= 4
+		ops = append(ops, database.BatchOp{Key: key, Value: key})
+	}
+	view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+	startRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// A key under a deleted prefix can be put back by the same view.
+	newKey := []byte{1, 2, 3, 4, 5}
+	prefixView, err := db.NewView(context.Background(), ViewChanges{
+		DeletePrefixes: [][]byte{{1}, {1, 2}},
+		BatchOps:       []database.BatchOp{{Key: newKey, Value: newKey}},
+	})
+	require.NoError(err)
+
+	var deletedKeys [][]byte
+	it := db.NewIteratorWithPrefix([]byte{1})
+	for it.Next() {
+		deletedKeys = append(deletedKeys, it.Key())
+	}
+	it.Release()
+	require.NoError(it.Error())
+	require.NotEmpty(deletedKeys)
+
+	expectedOps := make([]database.BatchOp, 0, len(deletedKeys)+1)
+	for _, key := range deletedKeys {
+		expectedOps = append(expectedOps, database.BatchOp{Key: key, Delete: true})
+	}
+	expectedOps = append(expectedOps, database.BatchOp{Key: newKey, Value: newKey})
+	expectedView, err := db.NewView(context.Background(), ViewChanges{BatchOps: expectedOps})
+	require.NoError(err)
+
+	expectedRoot, err := expectedView.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	root, err := prefixView.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(expectedRoot, root)
+
+	require.NoError(prefixView.CommitToDB(context.Background()))
+	it = db.NewIteratorWithPrefix([]byte{1})
+	require.True(it.Next())
+	require.Equal(newKey, it.Key())
+	require.False(it.Next())
+	it.Release()
+
+	// The deleted keys are included in change proofs.
+	changeProof, err := db.GetChangeProof(
+		context.Background(),
+		startRoot,
+		root,
+		maybe.Nothing[[]byte](),
+		maybe.Nothing[[]byte](),
+		len(deletedKeys)+1,
+	)
+	require.NoError(err)
+	require.Len(changeProof.KeyChanges, len(deletedKeys)+1)
+
+	// Prefixes also delete keys put by the parent view, and an empty prefix
+	// deletes every key.
+	parentView, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{{Key: []byte{5}, Value: []byte{5}}},
+	})
+	require.NoError(err)
+	emptyView, err := parentView.NewView(context.Background(), ViewChanges{
+		DeletePrefixes: [][]byte{{}},
+	})
+	require.NoError(err)
+	it = emptyView.NewIterator()
+	require.False(it.Next())
+	it.Release()
+
+	emptyDB, err := getBasicDB()
+	require.NoError(err)
+	expectedRoot, err = emptyDB.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	root, err = emptyView.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(expectedRoot, root)
+}
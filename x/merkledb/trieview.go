@@ -9,11 +9,13 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 
 	oteltrace "go.opentelemetry.io/otel/trace"
 
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -92,12 +94,43 @@ type trieView struct {
 	// Changes made to this view.
 	// May include nodes that haven't been updated
 	// but will when their ID is recalculated.
+	//
+	// The value changes of [changes] aren't modified after the view is
+	// created, so reads of values don't need to wait for [calculateNodeIDs],
+	// which only modifies [changes.nodes] and [changes.rootID].
+	// The value changes may have been spilled to disk. See
+	// [Config.ViewMemoryLimit].
 	changes *changeSummary
 
+	// The keys whose value was changed in increasing order if the changes
+	// were provided in increasing order. Nil otherwise.
+	sortedKeys []Key
+
+	// The prefixes whose keys were deleted by this view. The deletions of the
+	// keys are also in [changes]. See [RebaseOnto].
+	deletedPrefixes [][]byte
+
 	db *merkleDB
 
 	// The root of the trie represented by this view.
 	root *node
+
+	// If true, this view can't be changed or committed.
+	// See [ReadOnlyViewer].
+	readOnly bool
+	// Called once this view is invalidated if it's a read-only view.
+	onInvalidate func()
+
+	// If true, [Release] was called, so no views can be created on top of this
+	// view and it can't be committed.
+	// [validityTrackingLock] must be held when reading/writing this field.
+	released bool
+	// Reports this view if it's garbage collected without having been
+	// released or committed. Nil for views created internally.
+	leakDetector *viewLeakDetector
+	// Releases this view's reference to the value changes it spilled to disk
+	// once, when it's committed or discarded. See [releaseSpill].
+	releaseSpillOnce sync.Once
 }
 
 // NewView returns a new view on top of this Trie where the passed changes
@@ -108,9 +141,15 @@ func (t *trieView) NewView(
 	ctx context.Context,
 	changes ViewChanges,
 ) (TrieView, error) {
+	if t.readOnly {
+		return nil, errViewReadOnly
+	}
 	if t.isInvalid() {
 		return nil, ErrInvalid
 	}
+	if t.isReleased() {
+		return nil, ErrViewReleased
+	}
 	t.commitLock.RLock()
 	defer t.commitLock.RUnlock()
 
@@ -118,11 +157,15 @@ func (t *trieView) NewView(
 		return t.getParentTrie().NewView(ctx, changes)
 	}
 
+	if err := t.db.validateValueChanges(changes); err != nil {
+		return nil, err
+	}
+
 	if err := t.calculateNodeIDs(ctx); err != nil {
 		return nil, err
 	}
 
-	newView, err := newTrieView(t.db, t, changes)
+	newView, err := newTrieViewWithMemoryLimit(t.db, t, changes, t.db.viewMemoryLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -131,9 +174,15 @@ func (t *trieView) NewView(
 	defer t.validityTrackingLock.Unlock()
 
 	if t.invalidated {
+		newView.releaseSpill()
 		return nil, ErrInvalid
 	}
+	if t.released {
+		newView.releaseSpill()
+		return nil, ErrViewReleased
+	}
 	t.childViews = append(t.childViews, newView)
+	t.db.detectViewLeak(newView)
 
 	return newView, nil
 }
@@ -144,6 +193,20 @@ func newTrieView(
 	db *merkleDB,
 	parentTrie TrieView,
 	changes ViewChanges,
+) (*trieView, error) {
+	return newTrieViewWithMemoryLimit(db, parentTrie, changes, 0)
+}
+
+// Creates a new view with the given [parentTrie] whose value changes are
+// spilled to disk once they exceed [memoryLimit]. If 0, they're kept in
+// memory. See [Config.ViewMemoryLimit].
+// Only views returned to callers, which release them, spill their changes.
+// Assumes [parentTrie] isn't locked.
+func newTrieViewWithMemoryLimit(
+	db *merkleDB,
+	parentTrie TrieView,
+	changes ViewChanges,
+	memoryLimit int,
 ) (*trieView, error) {
 	root, err := parentTrie.getEditableNode(db.rootKey, false /* hasValue */)
 	if err != nil {
@@ -159,12 +222,43 @@ func newTrieView(
 		parentTrie: parentTrie,
 		changes:    newChangeSummary(len(changes.BatchOps) + len(changes.MapOps)),
 	}
+	newView.changes.valuesLimit = memoryLimit
+	newView.changes.db = db
+
+	if err := newView.recordChanges(changes); err != nil {
+		newView.releaseSpill()
+		return nil, err
+	}
+	return newView, nil
+}
+
+// recordChanges records [changes] in this view, which was just created.
+func (t *trieView) recordChanges(changes ViewChanges) error {
+	for _, prefix := range changes.DeletePrefixes {
+		if !changes.ConsumeBytes {
+			prefix = slices.Clone(prefix)
+		}
+		if err := t.recordPrefixDeletion(prefix); err != nil {
+			return err
+		}
+		t.deletedPrefixes = append(t.deletedPrefixes, prefix)
+	}
 
-	for _, op := range changes.BatchOps {
+	// Keys that arrive in increasing order are inserted in that order so that
+	// each insertion can reuse the path to the previously inserted key.
+	sorted := len(changes.MapOps) == 0 && len(changes.DeletePrefixes) == 0
+	if sorted {
+		t.sortedKeys = make([]Key, 0, len(changes.BatchOps))
+	}
+	for i, op := range changes.BatchOps {
 		key := op.Key
 		if !changes.ConsumeBytes {
 			key = slices.Clone(op.Key)
 		}
+		if sorted && i > 0 && bytes.Compare(changes.BatchOps[i-1].Key, op.Key) >= 0 {
+			sorted = false
+			t.sortedKeys = nil
+		}
 
 		newVal := maybe.Nothing[[]byte]()
 		if !op.Delete {
@@ -173,19 +267,23 @@ func newTrieView(
 				newVal = maybe.Some(slices.Clone(op.Value))
 			}
 		}
-		if err := newView.recordValueChange(db.toKey(key), newVal); err != nil {
-			return nil, err
+		k := t.db.toKey(key)
+		if err := t.recordValueChange(k, newVal); err != nil {
+			return err
+		}
+		if sorted {
+			t.sortedKeys = append(t.sortedKeys, k)
 		}
 	}
 	for key, val := range changes.MapOps {
 		if !changes.ConsumeBytes {
 			val = maybe.Bind(val, slices.Clone[[]byte])
 		}
-		if err := newView.recordValueChange(db.toKey(stringToByteSlice(key)), val); err != nil {
-			return nil, err
+		if err := t.recordValueChange(t.db.toKey(stringToByteSlice(key)), val); err != nil {
+			return err
 		}
 	}
-	return newView, nil
+	return nil
 }
 
 // Creates a view of the db at a historical root using the provided changes
@@ -232,18 +330,19 @@ func (t *trieView) calculateNodeIDs(ctx context.Context) error {
 		_, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.calculateNodeIDs")
 		defer span.End()
 
-		// add all the changed key/values to the nodes of the trie
-		for key, change := range t.changes.values {
-			if change.after.IsNothing() {
-				// Note we're setting [err] defined outside this function.
-				if err = t.remove(key); err != nil {
-					return
-				}
-				// Note we're setting [err] defined outside this function.
-			} else if _, err = t.insert(key, change.after); err != nil {
+		// add all the changed key/values to the nodes of the trie in
+		// increasing key order, so that the changes are applied the same way
+		// regardless of map iteration order.
+		if t.sortedKeys == nil {
+			// Note we're setting [err] defined outside this function.
+			if t.sortedKeys, err = t.changes.sortedValueKeys(); err != nil {
 				return
 			}
 		}
+		// Note we're setting [err] defined outside this function.
+		if err = t.applySortedValueChanges(); err != nil {
+			return
+		}
 
 		_ = t.db.calculateNodeIDsSema.Acquire(context.Background(), 1)
 		t.calculateNodeIDsHelper(t.root)
@@ -259,31 +358,84 @@ func (t *trieView) calculateNodeIDs(ctx context.Context) error {
 	return err
 }
 
+// Applies the value changes in the order of [t.sortedKeys].
+//
+// The resulting trie doesn't depend on the order the changes are applied in,
+// so all removals are applied first. The inserts are then applied in
+// increasing key order, which lets each insert resume from the deepest node
+// on the path to the previous key that is also on the path to the next key,
+// rather than traversing from the root.
+// Must not be called after [calculateNodeIDs] has returned.
+func (t *trieView) applySortedValueChanges() error {
+	for _, key := range t.sortedKeys {
+		valueChange, _, err := t.changes.getValueChange(key)
+		if err != nil {
+			return err
+		}
+		if valueChange.after.IsNothing() {
+			if err := t.remove(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	var path []*node
+	for _, key := range t.sortedKeys {
+		valueChange, _, err := t.changes.getValueChange(key)
+		if err != nil {
+			return err
+		}
+		value := valueChange.after
+		if value.IsNothing() {
+			continue
+		}
+		if path, _, err = t.insertFromPath(path, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Calculates the ID of all descendants of [n] which need to be recalculated,
 // and then calculates the ID of [n] itself.
+//
+// The children of [n] are visited, and their updated entries are set in [n],
+// in increasing index order. So the work done doesn't depend on map iteration
+// order or on the order in which the goroutines finish.
 func (t *trieView) calculateNodeIDsHelper(n *node) {
 	var (
 		// We use [wg] to wait until all descendants of [n] have been updated.
-		wg              sync.WaitGroup
-		updatedChildren = make(chan *node, len(n.children))
+		wg sync.WaitGroup
+		// The changed children of [n], in increasing index order.
+		updatedChildren = make([]*node, 0, len(n.children))
+		childIndices    = maps.Keys(n.children)
+
+		// The child keys are only used to look up changes, so they're all
+		// built in the same pooled buffer.
+		childPathBuffer = t.db.bufferPool.Get().([]byte)
 	)
-
-	for childIndex, child := range n.children {
-		childPath := n.key.AppendExtend(childIndex, child.compressedKey)
+	defer func() {
+		t.db.bufferPool.Put(childPathBuffer)
+	}()
+
+	slices.Sort(childIndices)
+	for _, childIndex := range childIndices {
+		child := n.children[childIndex]
+		childPathBuffer = resizeZeroedBuffer(childPathBuffer, n.key.appendExtendLen(child.compressedKey))
+		childPath := n.key.appendExtendIntoBuffer(childPathBuffer, childIndex, child.compressedKey)
 		childNodeChange, ok := t.changes.nodes[childPath]
 		if !ok {
 			// This child wasn't changed.
 			continue
 		}
 
+		updatedChildren = append(updatedChildren, childNodeChange.after)
+
 		wg.Add(1)
 		calculateChildID := func() {
 			defer wg.Done()
 
 			t.calculateNodeIDsHelper(childNodeChange.after)
-
-			// Note that this will never block
-			updatedChildren <- childNodeChange.after
 		}
 
 		// Try updating the child and its descendants in a goroutine.
@@ -300,10 +452,9 @@ func (t *trieView) calculateNodeIDsHelper(n *node) {
 
 	// Wait until all descendants of [n] have been updated.
 	wg.Wait()
-	close(updatedChildren)
 
 	keyLength := n.key.tokenLength
-	for updatedChild := range updatedChildren {
+	for _, updatedChild := range updatedChildren {
 		index := updatedChild.key.Token(keyLength)
 		n.setChildEntry(index, child{
 			compressedKey: n.children[index].compressedKey,
@@ -317,6 +468,8 @@ func (t *trieView) calculateNodeIDsHelper(n *node) {
 }
 
 // GetProof returns a proof that [bytesPath] is in or not in trie [t].
+// The proof contains node IDs, so this waits for the view's node IDs to be
+// calculated.
 func (t *trieView) GetProof(ctx context.Context, key []byte) (*Proof, error) {
 	_, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.GetProof")
 	defer span.End()
@@ -328,24 +481,111 @@ func (t *trieView) GetProof(ctx context.Context, key []byte) (*Proof, error) {
 	return t.getProof(ctx, key)
 }
 
+// GetProofs returns the proofs of [keys], in the same order.
+func (t *trieView) GetProofs(ctx context.Context, keys [][]byte) ([]*Proof, error) {
+	_, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.GetProofs", oteltrace.WithAttributes(
+		attribute.Int("keyCount", len(keys)),
+	))
+	defer span.End()
+
+	if err := t.calculateNodeIDs(ctx); err != nil {
+		return nil, err
+	}
+
+	return t.getProofs(ctx, keys)
+}
+
 // Returns a proof that [bytesPath] is in or not in trie [t].
 func (t *trieView) getProof(ctx context.Context, key []byte) (*Proof, error) {
 	_, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.getProof")
 	defer span.End()
 
-	proof := &Proof{
-		Key: t.db.toKey(key),
+	proofKey := t.db.toKey(key)
+	var path []*node
+	if err := t.visitPathToKeyFrom(t.root, proofKey, t.getNodeForProof, func(n *node) error {
+		path = append(path, n)
+		return t.db.chargeProofWork(ctx, 1)
+	}); err != nil {
+		return nil, err
+	}
+
+	// A commit to the database invalidates this view before changing any
+	// node, so if this view is still valid, none of the nodes on [path] were
+	// read from a later root.
+	if t.isInvalid() {
+		return nil, ErrInvalid
+	}
+	return t.proofFromPath(proofKey, path)
+}
+
+// Returns proofs that [keys] are in or not in trie [t], in the same order as
+// [keys].
+// The keys are proven in sorted order so that the nodes on the paths to
+// multiple keys are only read once.
+func (t *trieView) getProofs(ctx context.Context, keys [][]byte) ([]*Proof, error) {
+	_, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.getProofs")
+	defer span.End()
+
+	proofKeys := make([]Key, len(keys))
+	order := make([]int, len(keys))
+	for i, key := range keys {
+		proofKeys[i] = t.db.toKey(key)
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) bool {
+		return proofKeys[i].Less(proofKeys[j])
+	})
+
+	var (
+		proofs = make([]*Proof, len(keys))
+		// The nodes on the path to the last proven key.
+		path = []*node{t.root}
+	)
+	for _, i := range order {
+		proofKey := proofKeys[i]
+
+		// The nodes whose keys are prefixes of [proofKey] are on its path.
+		// The root is a prefix of every key.
+		for len(path) > 1 && !proofKey.HasPrefix(path[len(path)-1].key) {
+			path = path[:len(path)-1]
+		}
+
+		// Continue from the deepest shared node, which is visited again.
+		startNode := path[len(path)-1]
+		path = path[:len(path)-1]
+		if err := t.visitPathToKeyFrom(startNode, proofKey, t.getNodeForProof, func(n *node) error {
+			path = append(path, n)
+			return t.db.chargeProofWork(ctx, 1)
+		}); err != nil {
+			return nil, err
+		}
+
+		proof, err := t.proofFromPath(proofKey, path)
+		if err != nil {
+			return nil, err
+		}
+		proofs[i] = proof
+	}
+
+	// See [getProof].
+	if t.isInvalid() {
+		return nil, ErrInvalid
 	}
+	return proofs, nil
+}
 
-	var closestNode *node
-	if err := t.visitPathToKey(proof.Key, func(n *node) error {
-		closestNode = n
+// Returns a proof that [key] is in or not in trie [t], given the nodes on
+// the path from the root to [key].
+func (t *trieView) proofFromPath(key Key, path []*node) (*Proof, error) {
+	proof := &Proof{
+		Key:  key,
+		Path: make([]ProofNode, 0, len(path)+1),
+	}
+	for _, n := range path {
 		proof.Path = append(proof.Path, n.asProofNode())
-		return nil
-	}); err != nil {
-		return nil, err
 	}
 
+	closestNode := path[len(path)-1]
 	if closestNode.key == proof.Key {
 		// There is a node with the given [key].
 		proof.Value = maybe.Bind(closestNode.value, slices.Clone[[]byte])
@@ -361,7 +601,7 @@ func (t *trieView) getProof(ctx context.Context, key []byte) (*Proof, error) {
 		return proof, nil
 	}
 
-	childNode, err := t.getNodeWithID(
+	childNode, err := t.getNodeForProof(
 		child.id,
 		closestNode.key.AppendExtend(nextIndex, child.compressedKey),
 		child.hasValue,
@@ -388,6 +628,77 @@ func (t *trieView) GetRangeProof(
 	ctx, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.GetRangeProof")
 	defer span.End()
 
+	return t.getRangeProof(ctx, start, end, maxLength, t.db.proofDeadline())
+}
+
+// StreamRangeProof calls [fn] with consecutive range proofs that together
+// cover the key range [start, end], each with at most [chunkSize] key-value
+// pairs. The first proof starts at [start], and each following proof starts
+// right after the greatest key of the previous one, so each proof can be
+// verified on its own. The last proof may have no key-value pairs.
+// If [fn] returns an error, no more proofs are generated and the error is
+// returned.
+// [chunkSize] must be > 0.
+func (t *trieView) StreamRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	chunkSize int,
+	fn func(*RangeProof) error,
+) error {
+	ctx, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.StreamRangeProof")
+	defer span.End()
+
+	return t.streamRangeProof(ctx, start, end, chunkSize, fn)
+}
+
+func (t *trieView) streamRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	chunkSize int,
+	fn func(*RangeProof) error,
+) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Each proof holds [chunkSize] key-value pairs unless the range is
+		// exhausted, so it isn't truncated by a deadline.
+		proof, err := t.getRangeProof(ctx, start, end, chunkSize, time.Time{})
+		if err != nil {
+			return err
+		}
+		if err := fn(proof); err != nil {
+			return err
+		}
+
+		if len(proof.KeyValues) < chunkSize {
+			return nil
+		}
+		greatestKey := proof.KeyValues[len(proof.KeyValues)-1].Key
+		if end.HasValue() && bytes.Equal(greatestKey, end.Value()) {
+			return nil
+		}
+		// The smallest key that is greater than [greatestKey].
+		nextStart := make([]byte, len(greatestKey)+1)
+		copy(nextStart, greatestKey)
+		start = maybe.Some(nextStart)
+	}
+}
+
+// getRangeProof is the same as GetRangeProof except that no more key-value
+// pairs are added to the proof once [deadline] has passed. At least one
+// key-value pair is always added, if one exists, so that repeated requests
+// make progress. If [deadline] is the zero time, the proof isn't truncated.
+func (t *trieView) getRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	maxLength int,
+	deadline time.Time,
+) (*RangeProof, error) {
 	if start.HasValue() && end.HasValue() && bytes.Compare(start.Value(), end.Value()) == 1 {
 		return nil, ErrStartAfterEnd
 	}
@@ -405,11 +716,20 @@ func (t *trieView) GetRangeProof(
 	result.KeyValues = make([]KeyValue, 0, initKeyValuesSize)
 	it := t.NewIteratorWithStart(start.Value())
 	for it.Next() && len(result.KeyValues) < maxLength && (end.IsNothing() || bytes.Compare(it.Key(), end.Value()) <= 0) {
+		if err := t.db.chargeProofWork(ctx, 1); err != nil {
+			it.Release()
+			return nil, err
+		}
 		// clone the value to prevent editing of the values stored within the trie
 		result.KeyValues = append(result.KeyValues, KeyValue{
 			Key:   it.Key(),
 			Value: slices.Clone(it.Value()),
 		})
+		// The end proof is generated for the last key-value pair in the
+		// proof, so the truncated proof is still verifiable.
+		if deadlineExceeded(deadline) {
+			break
+		}
 	}
 	it.Release()
 	if err := it.Error(); err != nil {
@@ -475,6 +795,13 @@ func (t *trieView) CommitToDB(ctx context.Context) error {
 	ctx, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.CommitToDB")
 	defer span.End()
 
+	if t.readOnly {
+		return errViewReadOnly
+	}
+	if t.isReleased() {
+		return ErrViewReleased
+	}
+
 	t.db.commitLock.Lock()
 	defer t.db.commitLock.Unlock()
 
@@ -488,23 +815,60 @@ func (t *trieView) commitToDB(ctx context.Context) error {
 	t.commitLock.Lock()
 	defer t.commitLock.Unlock()
 
+	// [Release] may have been called since the caller checked, in which case
+	// the spilled value changes may have been deleted.
+	if t.isReleased() {
+		return ErrViewReleased
+	}
+
 	ctx, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.commitToDB", oteltrace.WithAttributes(
-		attribute.Int("changeCount", len(t.changes.values)),
+		attribute.Int("changeCount", t.changes.numValueChanges()),
 	))
 	defer span.End()
 
+	startTime := time.Now()
+
 	// Call this here instead of in [t.db.commitChanges]
 	// because doing so there would be a deadlock.
 	if err := t.calculateNodeIDs(ctx); err != nil {
 		return err
 	}
 
-	if err := t.db.commitChanges(ctx, t); err != nil {
+	// The changes are gathered before they're committed, since a commit
+	// can't be undone if they can't be read.
+	keyChanges, err := t.db.getCommitListenerChanges(t.changes)
+	if err != nil {
+		return err
+	}
+
+	err = t.db.commitChanges(ctx, t)
+	// The read-only views are invalidated before the changes are written, so
+	// they're notified even if writing the changes failed.
+	t.db.notifyInvalidatedReadOnlyViews()
+	if err != nil {
 		return err
 	}
 
 	t.committed = true
+	if t.leakDetector != nil {
+		t.leakDetector.done.Set(true)
+	}
+	duration := time.Since(startTime)
+
+	// Writing to the shadow isn't included in [duration] so that shadowing
+	// doesn't change which commits overrun.
+	t.db.writeToShadow(ctx, t.changes)
+	t.db.notifyCommitListeners(t.changes.rootID, keyChanges)
+
+	// The history holds its own reference to the spilled value changes if it
+	// recorded them.
+	t.releaseSpill()
 
+	// The changes have already been committed, so an overrun is only
+	// reported.
+	if maxDuration := t.db.maxCommitDuration; maxDuration > 0 && duration > maxDuration {
+		t.db.metrics.CommitDeadlineExceeded()
+	}
 	return nil
 }
 
@@ -530,6 +894,14 @@ func (t *trieView) invalidate() {
 
 	// after invalidating the children, they no longer need to be tracked
 	t.childViews = make([]*trieView, 0, defaultPreallocationSize)
+
+	// A released view waiting for its children to be collected won't be
+	// collected now that they're no longer tracked. An invalidated view that
+	// isn't released keeps its spilled value changes, since it can still be
+	// rebased.
+	if t.released {
+		t.releaseSpill()
+	}
 }
 
 func (t *trieView) updateParent(newParent TrieView) {
@@ -564,6 +936,7 @@ func (t *trieView) GetValues(ctx context.Context, keys [][]byte) ([][]byte, []er
 
 // GetValue returns the value for the given [key].
 // Returns database.ErrNotFound if it doesn't exist.
+// Doesn't wait for the view's node IDs to be calculated.
 func (t *trieView) GetValue(ctx context.Context, key []byte) ([]byte, error) {
 	_, span := t.db.debugTracer.Start(ctx, "MerkleDB.trieview.GetValue")
 	defer span.End()
@@ -586,7 +959,11 @@ func (t *trieView) getValue(key Key) ([]byte, error) {
 		return nil, ErrInvalid
 	}
 
-	if change, ok := t.changes.values[key]; ok {
+	change, ok, err := t.changes.getValueChange(key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
 		t.db.metrics.ViewValueCacheHit()
 		if change.after.IsNothing() {
 			return nil, database.ErrNotFound
@@ -713,9 +1090,21 @@ func (t *trieView) compressNodePath(parent, node *node) error {
 // the [key] if it isn't in the trie.
 // Always returns at least the root node.
 func (t *trieView) visitPathToKey(key Key, visitNode func(*node) error) error {
+	// all node paths start at the root
+	return t.visitPathToKeyFrom(t.root, key, t.getNodeWithID, visitNode)
+}
+
+// Returns the nodes along the path from [startNode] to [key].
+// Nodes other than [startNode] are retrieved with [getNode].
+// Assumes [startNode] is on the path to [key].
+func (t *trieView) visitPathToKeyFrom(
+	startNode *node,
+	key Key,
+	getNode func(id ids.ID, key Key, hasValue bool) (*node, error),
+	visitNode func(*node) error,
+) error {
 	var (
-		// all node paths start at the root
-		currentNode = t.root
+		currentNode = startNode
 		err         error
 	)
 	if err := visitNode(currentNode); err != nil {
@@ -732,7 +1121,7 @@ func (t *trieView) visitPathToKey(key Key, visitNode func(*node) error) error {
 		}
 
 		// grab the next node along the path
-		currentNode, err = t.getNodeWithID(nextChildEntry.id, key.Take(currentNode.key.tokenLength+1+nextChildEntry.compressedKey.tokenLength), nextChildEntry.hasValue)
+		currentNode, err = getNode(nextChildEntry.id, key.Take(currentNode.key.tokenLength+1+nextChildEntry.compressedKey.tokenLength), nextChildEntry.hasValue)
 		if err != nil {
 			return err
 		}
@@ -779,23 +1168,47 @@ func (t *trieView) insert(
 	key Key,
 	value maybe.Maybe[[]byte],
 ) (*node, error) {
+	_, n, err := t.insertFromPath(nil, key, value)
+	return n, err
+}
+
+// insertFromPath inserts a key/value pair into the correct node of the trie.
+// [path] is the path to a previously inserted key, as returned by a prior call
+// to insertFromPath, or nil to start from the root. The traversal resumes from
+// the deepest node in [path] whose key is a prefix of [key].
+// Returns the path to [key] and the node containing [value].
+// Must not be called after [calculateNodeIDs] has returned.
+func (t *trieView) insertFromPath(
+	path []*node,
+	key Key,
+	value maybe.Maybe[[]byte],
+) ([]*node, *node, error) {
 	if t.nodesAlreadyCalculated.Get() {
-		return nil, ErrNodesAlreadyCalculated
+		return nil, nil, ErrNodesAlreadyCalculated
 	}
 
-	var closestNode *node
-	if err := t.visitPathToKey(key, func(n *node) error {
-		closestNode = n
+	for len(path) > 0 && !key.HasPrefix(path[len(path)-1].key) {
+		path = path[:len(path)-1]
+	}
+	startNode := t.root
+	if len(path) > 0 {
+		// [startNode] is visited again below.
+		startNode = path[len(path)-1]
+		path = path[:len(path)-1]
+	}
+	if err := t.visitPathToKeyFrom(startNode, key, t.getNodeWithID, func(n *node) error {
+		path = append(path, n)
 		return t.recordNodeChange(n)
 	}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	closestNode := path[len(path)-1]
 
 	// a node with that exact path already exists so update its value
 	if closestNode.key == key {
 		closestNode.setValue(value)
 		// closestNode was already marked as changed in the ancestry loop above
-		return closestNode, nil
+		return path, closestNode, nil
 	}
 
 	closestNodeKeyLength := closestNode.key.tokenLength
@@ -812,7 +1225,7 @@ func (t *trieView) insert(
 			key,
 		)
 		newNode.setValue(value)
-		return newNode, t.recordNewNode(newNode)
+		return append(path, newNode), newNode, t.recordNewNode(newNode)
 	}
 
 	// if we have reached this point, then the [fullpath] we are trying to insert and
@@ -829,7 +1242,7 @@ func (t *trieView) insert(
 	// If the length of the existing child's compressed path is less than or equal to the branch node's key that implies that the existing child's key matched the key to be inserted.
 	// Since it matched the key to be inserted, it should have been the last node returned by GetPathTo
 	if existingChildEntry.compressedKey.tokenLength <= commonPrefixLength {
-		return nil, ErrGetPathToFailure
+		return nil, nil, ErrGetPathToFailure
 	}
 
 	branchNode := newNode(
@@ -850,7 +1263,7 @@ func (t *trieView) insert(
 		)
 		newNode.setValue(value)
 		if err := t.recordNewNode(newNode); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		nodeWithValue = newNode
 	}
@@ -864,7 +1277,11 @@ func (t *trieView) insert(
 			hasValue:      existingChildEntry.hasValue,
 		})
 
-	return nodeWithValue, t.recordNewNode(branchNode)
+	path = append(path, branchNode)
+	if nodeWithValue != branchNode {
+		path = append(path, nodeWithValue)
+	}
+	return path, nodeWithValue, t.recordNewNode(branchNode)
 }
 
 // Records that a node has been created.
@@ -903,9 +1320,7 @@ func (t *trieView) recordKeyChange(key Key, after *node, hadValue bool, newNode
 	}
 
 	if newNode {
-		t.changes.nodes[key] = &change[*node]{
-			after: after,
-		}
+		t.changes.nodes[key] = t.changes.newNodeChange(nil, after)
 		return nil
 	}
 
@@ -913,10 +1328,7 @@ func (t *trieView) recordKeyChange(key Key, after *node, hadValue bool, newNode
 	if err != nil && err != database.ErrNotFound {
 		return err
 	}
-	t.changes.nodes[key] = &change[*node]{
-		before: before,
-		after:  after,
-	}
+	t.changes.nodes[key] = t.changes.newNodeChange(before, after)
 	return nil
 }
 
@@ -930,9 +1342,8 @@ func (t *trieView) recordValueChange(key Key, value maybe.Maybe[[]byte]) error {
 	}
 
 	// update the existing change if it exists
-	if existing, ok := t.changes.values[key]; ok {
-		existing.after = value
-		return nil
+	if changed, err := t.changes.setValueAfter(key, value); changed || err != nil {
+		return err
 	}
 
 	// grab the before value
@@ -947,11 +1358,37 @@ func (t *trieView) recordValueChange(key Key, value maybe.Maybe[[]byte]) error {
 		return err
 	}
 
-	t.changes.values[key] = &change[maybe.Maybe[[]byte]]{
-		before: beforeMaybe,
-		after:  value,
+	return t.changes.addValueChange(key, beforeMaybe, value)
+}
+
+// Records that every key in the parent trie that starts with [prefix] has
+// been deleted.
+// Must only be called while the view is being created.
+func (t *trieView) recordPrefixDeletion(prefix []byte) error {
+	it := t.getParentTrie().NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		key := t.db.toKey(slices.Clone(it.Key()))
+		changed, err := t.changes.setValueAfter(key, maybe.Nothing[[]byte]())
+		if err != nil {
+			return err
+		}
+		if changed {
+			continue
+		}
+		// The iterator already read the value, so unlike
+		// [recordValueChange], it isn't read from the parent again.
+		err = t.changes.addValueChange(
+			key,
+			maybe.Some(slices.Clone(it.Value())),
+			maybe.Nothing[[]byte](),
+		)
+		if err != nil {
+			return err
+		}
 	}
-	return nil
+	return it.Error()
 }
 
 // Retrieves a node with the given [key].
@@ -983,6 +1420,42 @@ func (t *trieView) getNodeWithID(id ids.ID, key Key, hasValue bool) (*node, erro
 	return parentTrieNode, nil
 }
 
+// Retrieves the node with the given [key] to be included in a proof.
+// The returned node must not be modified.
+//
+// Unlike [getNodeWithID], which copies the node once for every view between
+// [t] and the view that modified it, the ancestors of [t] are searched
+// iteratively and the node is only copied if it's read from the database.
+// This keeps proofs from deep view stacks cheap to generate.
+//
+// [id] is ignored, because the IDs of nodes aren't included in proofs.
+// Assumes [t]'s node IDs have been calculated, so none of the nodes in [t]
+// or its ancestors are modified anymore.
+// Returns database.ErrNotFound if the node doesn't exist.
+func (t *trieView) getNodeForProof(_ ids.ID, key Key, hasValue bool) (*node, error) {
+	view := t
+	for {
+		if view.isInvalid() {
+			return nil, ErrInvalid
+		}
+		if nodeChange, isChanged := view.changes.nodes[key]; isChanged {
+			t.db.metrics.ViewNodeCacheHit()
+			if nodeChange.after == nil {
+				return nil, database.ErrNotFound
+			}
+			return nodeChange.after, nil
+		}
+
+		parentTrie := view.getParentTrie()
+		parentView, ok := parentTrie.(*trieView)
+		if !ok {
+			// [parentTrie] is the database.
+			return parentTrie.getEditableNode(key, hasValue)
+		}
+		view = parentView
+	}
+}
+
 // Get the parent trie of the view
 func (t *trieView) getParentTrie() TrieView {
 	t.validityTrackingLock.RLock()
@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"sync"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	bloomfilter "github.com/holiman/bloomfilter/v2"
+)
+
+// The probability that the value filter reports a key that was never added,
+// while it holds at most [Config.ValueFilterSize] keys.
+const valueFilterFalsePositiveProbability = 0.01
+
+var valueFilterKey = []byte(string(metadataPrefix) + "valueFilter")
+
+// valueFilter is a bloom filter over the keys of the value nodes on disk.
+// If a key isn't in the filter, its value node isn't on disk.
+//
+// Keys are never removed from the filter, so keys that were deleted increase
+// the false positive probability until the filter is repopulated.
+type valueFilter struct {
+	lock   sync.RWMutex
+	filter *bloomfilter.Filter
+}
+
+func newValueFilter(size uint) (*valueFilter, error) {
+	filter, err := bloomfilter.NewOptimal(uint64(size), valueFilterFalsePositiveProbability)
+	if err != nil {
+		return nil, err
+	}
+	return &valueFilter{filter: filter}, nil
+}
+
+func (f *valueFilter) add(key Key) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.filter.AddHash(valueFilterHash(key.Bytes()))
+}
+
+// mayContain returns false if [key] was never added to the filter.
+func (f *valueFilter) mayContain(key Key) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.filter.ContainsHash(valueFilterHash(key.Bytes()))
+}
+
+func (f *valueFilter) bytes() ([]byte, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	return f.filter.MarshalBinary()
+}
+
+func valueFilterHash(key []byte) uint64 {
+	return murmur3.Sum64(key)
+}
+
+// writeValueFilter saves the value filter so that it can be used on the next
+// startup instead of being repopulated.
+//
+// Must be called once no more value nodes will be written.
+func (db *merkleDB) writeValueFilter() error {
+	if db.valueNodeDB.filter == nil {
+		return nil
+	}
+
+	filterBytes, err := db.valueNodeDB.filter.bytes()
+	if err != nil {
+		return err
+	}
+	return db.baseDB.Put(valueFilterKey, filterBytes)
+}
+
+// loadValueFilter returns the filter saved by the last [writeValueFilter] and
+// deletes it, so that it isn't used again after a later unclean shutdown.
+// Returns nil if no filter was saved.
+func (db *merkleDB) loadValueFilter() (*valueFilter, error) {
+	filterBytes, err := db.baseDB.Get(valueFilterKey)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := db.baseDB.Delete(valueFilterKey); err != nil {
+		return nil, err
+	}
+
+	filter := &bloomfilter.Filter{}
+	if err := filter.UnmarshalBinary(filterBytes); err != nil {
+		return nil, err
+	}
+	return &valueFilter{filter: filter}, nil
+}
+
+// initValueFilter sets the value filter of [db.valueNodeDB]. [loaded], the
+// filter returned by [loadValueFilter], is used if it was created with the
+// same [size] and hasn't had more than [size] keys added to it. Otherwise, a
+// new filter is populated with every key on disk.
+//
+// Must be called before the database is used.
+func (db *merkleDB) initValueFilter(size uint, loaded *valueFilter) error {
+	if size == 0 {
+		return nil
+	}
+
+	if loaded != nil &&
+		loaded.filter.M() == bloomfilter.OptimalM(uint64(size), valueFilterFalsePositiveProbability) &&
+		loaded.filter.N() <= uint64(size) {
+		db.valueNodeDB.filter = loaded
+		return nil
+	}
+
+	filter, err := newValueFilter(size)
+	if err != nil {
+		return err
+	}
+
+	it := db.baseDB.NewIteratorWithPrefix(valueNodePrefix)
+	defer it.Release()
+
+	for it.Next() {
+		filter.filter.AddHash(valueFilterHash(it.Key()[valueNodePrefixLen:]))
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	db.valueNodeDB.filter = filter
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+const numValueFilterKeys = 100
+
+func newValueFilterDB(t *testing.T, baseDB database.Database, valueFilterSize uint) (*merkleDB, *mockMetrics) {
+	config := newDefaultConfig()
+	config.ValueFilterSize = valueFilterSize
+	metrics := &mockMetrics{}
+	db, err := newDatabase(context.Background(), baseDB, config, metrics)
+	require.NoError(t, err)
+	return db, metrics
+}
+
+func putValueFilterKeys(t *testing.T, db *merkleDB) {
+	for i := 0; i < numValueFilterKeys; i++ {
+		key := []byte(strconv.Itoa(i))
+		require.NoError(t, db.Put(key, key))
+	}
+}
+
+// requireValueFilterReads requires that every key written by
+// [putValueFilterKeys] can be read from disk and that reads of missing keys
+// are skipped.
+func requireValueFilterReads(t *testing.T, db *merkleDB, metrics *mockMetrics) {
+	require := require.New(t)
+
+	for i := 0; i < numValueFilterKeys; i++ {
+		key := []byte(strconv.Itoa(i))
+		value, err := db.Get(key)
+		require.NoError(err)
+		require.Equal(key, value)
+	}
+	require.Zero(metrics.valueFilterSkips)
+
+	for i := 0; i < numValueFilterKeys; i++ {
+		has, err := db.Has([]byte("missing" + strconv.Itoa(i)))
+		require.NoError(err)
+		require.False(has)
+	}
+	// Some missing keys may be false positives.
+	require.Greater(metrics.valueFilterSkips, int64(numValueFilterKeys/2))
+}
+
+func Test_MerkleDB_ValueFilter(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db, _ := newValueFilterDB(t, baseDB, numValueFilterKeys)
+	putValueFilterKeys(t, db)
+	require.NoError(db.Close())
+
+	has, err := baseDB.Has(valueFilterKey)
+	require.NoError(err)
+	require.True(has)
+
+	db, metrics := newValueFilterDB(t, baseDB, numValueFilterKeys)
+
+	// The saved filter is only used once.
+	has, err = baseDB.Has(valueFilterKey)
+	require.NoError(err)
+	require.False(has)
+
+	requireValueFilterReads(t, db, metrics)
+	require.NoError(db.Close())
+}
+
+func Test_MerkleDB_ValueFilter_Populate(t *testing.T) {
+	tests := []struct {
+		name            string
+		valueFilterSize uint
+	}{
+		{
+			name:            "no saved filter",
+			valueFilterSize: 0,
+		},
+		{
+			name:            "saved filter with a different size",
+			valueFilterSize: 2 * numValueFilterKeys,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			baseDB := memdb.New()
+			db, _ := newValueFilterDB(t, baseDB, test.valueFilterSize)
+			putValueFilterKeys(t, db)
+			require.NoError(db.Close())
+
+			db, metrics := newValueFilterDB(t, baseDB, numValueFilterKeys)
+			requireValueFilterReads(t, db, metrics)
+			require.NoError(db.Close())
+		})
+	}
+}
+
+func Test_MerkleDB_ValueFilter_PopulateDeletedKeys(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db, _ := newValueFilterDB(t, baseDB, numValueFilterKeys)
+	putValueFilterKeys(t, db)
+	for i := 0; i < numValueFilterKeys/2; i++ {
+		require.NoError(db.Delete([]byte(strconv.Itoa(i))))
+	}
+	putValueFilterKeys(t, db)
+	require.NoError(db.Close())
+
+	// Keys were added to the saved filter more than [numValueFilterKeys]
+	// times, so it is repopulated with only the keys on disk.
+	db, _ = newValueFilterDB(t, baseDB, numValueFilterKeys)
+	require.Equal(uint64(numValueFilterKeys), db.valueNodeDB.filter.filter.N())
+	require.NoError(db.Close())
+}
+
+func Test_MerkleDB_ValueFilter_UncleanShutdown(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db, _ := newValueFilterDB(t, baseDB, numValueFilterKeys)
+	putValueFilterKeys(t, db)
+	require.NoError(db.Close())
+
+	// Reopen without closing, so that the saved filter is discarded and the
+	// database is rebuilt on the next startup.
+	db, _ = newValueFilterDB(t, baseDB, numValueFilterKeys)
+	require.NoError(db.Put([]byte("new"), []byte("new")))
+
+	db, metrics := newValueFilterDB(t, baseDB, numValueFilterKeys)
+	requireValueFilterReads(t, db, metrics)
+
+	value, err := db.Get([]byte("new"))
+	require.NoError(err)
+	require.Equal([]byte("new"), value)
+	require.NoError(db.Close())
+}
@@ -4,15 +4,130 @@
 package merkledb
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"sync"
 
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/cache"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// The number of bytes of the checksum stored after each value node.
+	valueChecksumLen = 4
+
+	// The encodings of the value nodes in the base database, which is stored
+	// under [valueNodeFormatKey].
+	// Databases written before the checksums were added have no
+	// [valueNodeFormatKey], and their value nodes aren't followed by a
+	// checksum.
+	valueNodeFormatChecksum byte = 1
+	// The number of value nodes that are upgraded in a batch.
+	valueNodeUpgradeBatchSize = 10_000
+)
+
+var (
+	_ database.Iterator = (*iterator)(nil)
+
+	valueNodeFormatKey = []byte(string(metadataPrefix) + "valueNodeFormat")
+	// The key of the last value node that was upgraded to
+	// [valueNodeFormatChecksum], while an upgrade is in progress.
+	valueNodeUpgradeKey = []byte(string(metadataPrefix) + "valueNodeUpgrade")
+
+	ErrValueChecksumMismatch = errors.New("value checksum mismatch")
+
+	errUnknownValueNodeFormat = errors.New("unknown value node format")
 )
 
-var _ database.Iterator = (*iterator)(nil)
+// upgradeValueNodeFormat ensures that the value nodes of [db], which are
+// compressed with [compression], are followed by a checksum.
+//
+// The value nodes of a database written before the checksums were added are
+// rewritten with a checksum, in batches. The key of the last rewritten node is
+// written with each batch, so an interrupted upgrade resumes after it.
+func upgradeValueNodeFormat(db database.Database, compression nodeCompression) error {
+	format, err := db.Get(valueNodeFormatKey)
+	switch {
+	case err == nil:
+		if len(format) != 1 || format[0] != valueNodeFormatChecksum {
+			return fmt.Errorf("%w: %x", errUnknownValueNodeFormat, format)
+		}
+		return nil
+	case err != database.ErrNotFound:
+		return err
+	}
+
+	start, err := db.Get(valueNodeUpgradeKey)
+	switch {
+	case err == nil:
+		// Resume after the last upgraded node.
+		start = append(start, 0)
+	case err != database.ErrNotFound:
+		return err
+	}
+
+	it := db.NewIteratorWithStartAndPrefix(start, valueNodePrefix)
+	defer it.Release()
+
+	var (
+		batch      = db.NewBatch()
+		batchNodes = 0
+	)
+	for it.Next() {
+		prefixedKey := slices.Clone(it.Key())
+		nodeBytes, err := compression.decompress(it.Value())
+		if err != nil {
+			return err
+		}
+		value, err := codec.decodeMaybeByteSlice(bytes.NewReader(nodeBytes))
+		if err != nil {
+			return err
+		}
+		if value.IsNothing() {
+			return fmt.Errorf("%w: %x", errValueNodeWithoutValue, prefixedKey[valueNodePrefixLen:])
+		}
+
+		checksum := valueChecksum(prefixedKey[valueNodePrefixLen:], value.Value())
+		nodeBytes, err = compression.compress(append(slices.Clip(nodeBytes), checksum...))
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(prefixedKey, nodeBytes); err != nil {
+			return err
+		}
+		batchNodes++
+		if batchNodes < valueNodeUpgradeBatchSize {
+			continue
+		}
+		if err := batch.Put(valueNodeUpgradeKey, prefixedKey); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		batchNodes = 0
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if err := batch.Put(valueNodeFormatKey, []byte{valueNodeFormatChecksum}); err != nil {
+		return err
+	}
+	if err := batch.Delete(valueNodeUpgradeKey); err != nil {
+		return err
+	}
+	return batch.Write()
+}
 
 type valueNodeDB struct {
 	// Holds unused []byte
@@ -29,6 +144,19 @@ type valueNodeDB struct {
 
 	closed       utils.Atomic[bool]
 	branchFactor BranchFactor
+
+	// If true, the checksum stored with each value node is verified when the
+	// node is read from [baseDB].
+	verifyChecksums bool
+
+	// If non-nil, holds every key written to [baseDB]. Keys that aren't in
+	// [filter] aren't read from [baseDB].
+	// Must be set before the database is used.
+	filter *valueFilter
+
+	// Compresses the nodes written to [baseDB].
+	// Must be set before the database is used.
+	compression nodeCompression
 }
 
 func newValueNodeDB(
@@ -37,13 +165,15 @@ func newValueNodeDB(
 	metrics merkleMetrics,
 	cacheSize int,
 	branchFactor BranchFactor,
+	verifyChecksums bool,
 ) *valueNodeDB {
 	return &valueNodeDB{
-		metrics:      metrics,
-		baseDB:       db,
-		bufferPool:   bufferPool,
-		nodeCache:    cache.NewSizedLRU(cacheSize, cacheEntrySize),
-		branchFactor: branchFactor,
+		metrics:         metrics,
+		baseDB:          db,
+		bufferPool:      bufferPool,
+		nodeCache:       cache.NewSizedLRU(cacheSize, cacheEntrySize),
+		branchFactor:    branchFactor,
+		verifyChecksums: verifyChecksums,
 	}
 }
 
@@ -66,7 +196,7 @@ func (db *valueNodeDB) Close() {
 func (db *valueNodeDB) NewBatch() *valueNodeBatch {
 	return &valueNodeBatch{
 		db:  db,
-		ops: make(map[Key]*node, defaultBufferLength),
+		ops: make(map[Key]valueNodeOp, defaultBufferLength),
 	}
 }
 
@@ -80,6 +210,11 @@ func (db *valueNodeDB) Get(key Key) (*node, error) {
 	}
 	db.metrics.ValueNodeCacheMiss()
 
+	if db.filter != nil && !db.filter.mayContain(key) {
+		db.metrics.ValueFilterSkip()
+		return nil, database.ErrNotFound
+	}
+
 	prefixedKey := addPrefixToKey(db.bufferPool, valueNodePrefix, key.Bytes())
 	defer db.bufferPool.Put(prefixedKey)
 
@@ -89,27 +224,97 @@ func (db *valueNodeDB) Get(key Key) (*node, error) {
 		return nil, err
 	}
 
-	return parseNode(key, nodeBytes)
+	return db.parseValueNode(key, nodeBytes)
+}
+
+// encode returns the bytes written to [baseDB] for [n].
+func (db *valueNodeDB) encode(n *node) ([]byte, error) {
+	return db.compression.compress(valueNodeBytes(n))
+}
+
+// parseValueNode parses [b], which was written by [encode], into the node at
+// [key].
+func (db *valueNodeDB) parseValueNode(key Key, b []byte) (*node, error) {
+	b, err := db.compression.decompress(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < valueChecksumLen {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	checksumIndex := len(b) - valueChecksumLen
+	n, err := parseNode(key, b[:checksumIndex])
+	if err != nil {
+		return nil, err
+	}
+	if db.verifyChecksums && !bytes.Equal(b[checksumIndex:], valueChecksum(n.key.Bytes(), n.value.Value())) {
+		return nil, fmt.Errorf("%w for key %x", ErrValueChecksumMismatch, key.Bytes())
+	}
+	return n, nil
+}
+
+// valueNodeBytes returns the bytes of [n] followed by the checksum of its key
+// and value.
+func valueNodeBytes(n *node) []byte {
+	nodeBytes := n.bytes()
+	b := make([]byte, len(nodeBytes)+valueChecksumLen)
+	copy(b, nodeBytes)
+	copy(b[len(nodeBytes):], valueChecksum(n.key.Bytes(), n.value.Value()))
+	return b
+}
+
+// valueChecksum returns a checksum of [key] and [value]. Including the key
+// detects values that were written to the wrong key.
+func valueChecksum(key []byte, value []byte) []byte {
+	b := make([]byte, 0, len(key)+len(value))
+	b = append(b, key...)
+	b = append(b, value...)
+	return hashing.Checksum(b, valueChecksumLen)
 }
 
 // Batch of database operations
 type valueNodeBatch struct {
 	db  *valueNodeDB
-	ops map[Key]*node
+	ops map[Key]valueNodeOp
+}
+
+// valueNodeOp is a put of [node], or a delete if [node] is nil.
+type valueNodeOp struct {
+	node *node
+	// The bytes to write for [node], if they were already encoded by
+	// [valueNodeDB.encode].
+	nodeBytes []byte
 }
 
 func (b *valueNodeBatch) Put(key Key, value *node) {
-	b.ops[key] = value
+	b.putEncoded(key, value, nil)
+}
+
+// putEncoded is the same as Put, except that [valueBytes], if non-nil, is
+// written instead of encoding [value] again. [valueBytes] must be equal to
+// the bytes returned by [valueNodeDB.encode] for [value].
+func (b *valueNodeBatch) putEncoded(key Key, value *node, valueBytes []byte) {
+	b.ops[key] = valueNodeOp{
+		node:      value,
+		nodeBytes: valueBytes,
+	}
 }
 
 func (b *valueNodeBatch) Delete(key Key) {
-	b.ops[key] = nil
+	b.ops[key] = valueNodeOp{}
 }
 
 // Write flushes any accumulated data to the underlying database.
+// Operations are written in key order to improve write locality.
 func (b *valueNodeBatch) Write() error {
+	keys := maps.Keys(b.ops)
+	slices.SortFunc(keys, Key.Less)
+
 	dbBatch := b.db.baseDB.NewBatch()
-	for key, n := range b.ops {
+	for _, key := range keys {
+		op := b.ops[key]
+		n := op.node
 		b.db.metrics.DatabaseNodeWrite()
 		b.db.nodeCache.Put(key, n)
 		prefixedKey := addPrefixToKey(b.db.bufferPool, valueNodePrefix, key.Bytes())
@@ -117,8 +322,23 @@ func (b *valueNodeBatch) Write() error {
 			if err := dbBatch.Delete(prefixedKey); err != nil {
 				return err
 			}
-		} else if err := dbBatch.Put(prefixedKey, n.bytes()); err != nil {
-			return err
+		} else {
+			// The key is added to the filter before it is written so that
+			// readers never skip a value node that is on disk.
+			if b.db.filter != nil {
+				b.db.filter.add(key)
+			}
+			nodeBytes := op.nodeBytes
+			if nodeBytes == nil {
+				var err error
+				nodeBytes, err = b.db.encode(n)
+				if err != nil {
+					return err
+				}
+			}
+			if err := dbBatch.Put(prefixedKey, nodeBytes); err != nil {
+				return err
+			}
 		}
 
 		b.db.bufferPool.Put(prefixedKey)
@@ -170,7 +390,7 @@ func (i *iterator) Next() bool {
 	i.db.metrics.DatabaseNodeRead()
 	key := i.nodeIter.Key()
 	key = key[valueNodePrefixLen:]
-	n, err := parseNode(ToKey(key, i.db.branchFactor), i.nodeIter.Value())
+	n, err := i.db.parseValueNode(ToKey(key, i.db.branchFactor), i.nodeIter.Value())
 	if err != nil {
 		i.err = err
 		return false
@@ -4,11 +4,15 @@
 package merkledb
 
 import (
+	"bytes"
+	"context"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/exp/slices"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/utils/maybe"
@@ -29,6 +33,7 @@ func TestValueNodeDB(t *testing.T) {
 		&mockMetrics{},
 		size,
 		BranchFactor16,
+		true,
 	)
 
 	// Getting a key that doesn't exist should return an error.
@@ -125,6 +130,7 @@ func TestValueNodeDBIterator(t *testing.T) {
 		&mockMetrics{},
 		cacheSize,
 		BranchFactor16,
+		true,
 	)
 
 	// Put key-node pairs.
@@ -220,3 +226,223 @@ func TestValueNodeDBIterator(t *testing.T) {
 	err := it.Error()
 	require.ErrorIs(err, database.ErrClosed)
 }
+
+func TestValueNodeDBChecksum(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	bufferPool := &sync.Pool{
+		New: func() interface{} { return make([]byte, 0) },
+	}
+	db := newValueNodeDB(
+		baseDB,
+		bufferPool,
+		&mockMetrics{},
+		10,
+		BranchFactor16,
+		true,
+	)
+
+	key := ToKey([]byte{0x01}, BranchFactor16)
+	batch := db.NewBatch()
+	batch.Put(key, &node{
+		dbNode: dbNode{
+			value: maybe.Some([]byte{0x01}),
+		},
+		key: key,
+	})
+	require.NoError(batch.Write())
+
+	// Write the value of a different key under [key].
+	otherKey := ToKey([]byte{0x02}, BranchFactor16)
+	prefixedKey := addPrefixToKey(bufferPool, valueNodePrefix, key.Bytes())
+	require.NoError(baseDB.Put(prefixedKey, valueNodeBytes(&node{
+		dbNode: dbNode{
+			value: maybe.Some([]byte{0x01}),
+		},
+		key: otherKey,
+	})))
+
+	db.nodeCache.Flush()
+	_, err := db.Get(key)
+	require.ErrorIs(err, ErrValueChecksumMismatch)
+
+	it := db.newIteratorWithStartAndPrefix(nil, nil)
+	defer it.Release()
+	require.False(it.Next())
+	require.ErrorIs(it.Error(), ErrValueChecksumMismatch)
+
+	// The corruption isn't detected if verification is disabled.
+	db.verifyChecksums = false
+	n, err := db.Get(key)
+	require.NoError(err)
+	require.Equal([]byte{0x01}, n.value.Value())
+}
+
+// removeValueChecksums rewrites the value nodes of [db], which aren't
+// compressed, as they were written before checksums were added.
+func removeValueChecksums(t *testing.T, db database.Database) {
+	require := require.New(t)
+
+	it := db.NewIteratorWithPrefix(valueNodePrefix)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		nodeBytes := it.Value()
+		require.NoError(batch.Put(it.Key(), nodeBytes[:len(nodeBytes)-valueChecksumLen]))
+	}
+	require.NoError(it.Error())
+	require.NoError(batch.Delete(valueNodeFormatKey))
+	require.NoError(batch.Write())
+}
+
+func TestValueNodeFormatUpgrade(t *testing.T) {
+	tests := []struct {
+		name string
+		// The number of value nodes that were upgraded before the upgrade
+		// was interrupted.
+		numUpgraded int
+	}{
+		{
+			name:        "not started",
+			numUpgraded: 0,
+		},
+		{
+			name:        "interrupted",
+			numUpgraded: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			baseDB := memdb.New()
+			db, err := New(context.Background(), baseDB, newDefaultConfig())
+			require.NoError(err)
+			for i := byte(0); i < 10; i++ {
+				require.NoError(db.Put([]byte{i}, []byte{i, i}))
+			}
+			root, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.NoError(db.Close())
+
+			// Simulate a database written before checksums were added, whose
+			// upgrade was interrupted after [numUpgraded] value nodes.
+			upgraded := make(map[string][]byte)
+			it := baseDB.NewIteratorWithPrefix(valueNodePrefix)
+			var lastUpgradedKey []byte
+			for i := 0; i < test.numUpgraded && it.Next(); i++ {
+				lastUpgradedKey = slices.Clone(it.Key())
+				upgraded[string(lastUpgradedKey)] = slices.Clone(it.Value())
+			}
+			require.NoError(it.Error())
+			it.Release()
+			removeValueChecksums(t, baseDB)
+			for key, value := range upgraded {
+				require.NoError(baseDB.Put([]byte(key), value))
+			}
+			if lastUpgradedKey != nil {
+				require.NoError(baseDB.Put(valueNodeUpgradeKey, lastUpgradedKey))
+			}
+
+			config := newDefaultConfig()
+			config.VerifyValueChecksums = true
+			db, err = New(context.Background(), baseDB, config)
+			require.NoError(err)
+
+			gotRoot, err := db.GetMerkleRoot(context.Background())
+			require.NoError(err)
+			require.Equal(root, gotRoot)
+			for i := byte(0); i < 10; i++ {
+				db.(*merkleDB).valueNodeDB.nodeCache.Flush()
+				value, err := db.Get([]byte{i})
+				require.NoError(err)
+				require.Equal([]byte{i, i}, value)
+			}
+			require.NoError(db.Close())
+
+			format, err := baseDB.Get(valueNodeFormatKey)
+			require.NoError(err)
+			require.Equal([]byte{valueNodeFormatChecksum}, format)
+			has, err := baseDB.Has(valueNodeUpgradeKey)
+			require.NoError(err)
+			require.False(has)
+		})
+	}
+}
+
+func TestValueNodeFormatUnknown(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	require.NoError(baseDB.Put(valueNodeFormatKey, []byte{valueNodeFormatChecksum + 1}))
+
+	_, err := New(context.Background(), baseDB, newDefaultConfig())
+	require.ErrorIs(err, errUnknownValueNodeFormat)
+}
+
+// orderedWritesDB records the order in which keys are written in batches.
+type orderedWritesDB struct {
+	database.Database
+	written [][]byte
+}
+
+func (db *orderedWritesDB) NewBatch() database.Batch {
+	return &orderedWritesBatch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+type orderedWritesBatch struct {
+	database.Batch
+	db *orderedWritesDB
+}
+
+func (b *orderedWritesBatch) Put(key, value []byte) error {
+	b.db.written = append(b.db.written, slices.Clone(key))
+	return b.Batch.Put(key, value)
+}
+
+func (b *orderedWritesBatch) Delete(key []byte) error {
+	b.db.written = append(b.db.written, slices.Clone(key))
+	return b.Batch.Delete(key)
+}
+
+func TestValueNodeBatchWritesInKeyOrder(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := &orderedWritesDB{Database: memdb.New()}
+	db := newValueNodeDB(
+		baseDB,
+		&sync.Pool{
+			New: func() interface{} { return make([]byte, 0) },
+		},
+		&mockMetrics{},
+		10,
+		BranchFactor16,
+		true,
+	)
+
+	batch := db.NewBatch()
+	for i := 0; i < 256; i++ {
+		key := ToKey([]byte{byte(i * 7)}, BranchFactor16)
+		if i%3 == 0 {
+			batch.Delete(key)
+			continue
+		}
+		batch.Put(key, &node{
+			dbNode: dbNode{
+				value: maybe.Some([]byte{byte(i)}),
+			},
+			key: key,
+		})
+	}
+	require.NoError(batch.Write())
+
+	require.Len(baseDB.written, 256)
+	require.True(slices.IsSortedFunc(baseDB.written, func(a, b []byte) bool {
+		return bytes.Compare(a, b) < 0
+	}))
+}
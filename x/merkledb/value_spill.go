@@ -0,0 +1,322 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// The estimated memory used by a value change in [changeSummary.values],
+	// other than its key and values.
+	valueChangeOverhead = 128
+
+	spillWriteSize = units.MiB
+)
+
+var errSpillDeleted = errors.New("spilled value changes have been deleted")
+
+// valueSpill holds the value changes of a view that were moved to disk once
+// the view exceeded [Config.ViewMemoryLimit]. They're stored in the base
+// database, under [viewSpillPrefix] followed by an ID unique to the spill.
+//
+// A spill is deleted once neither the view nor the history needs it: the view
+// releases it when it's committed, released or invalidated, and the history
+// releases it when the changes are pruned. Spills left by a previous run, for
+// example by leaked views, are deleted when the database is opened.
+type valueSpill struct {
+	db      database.Database
+	prefix  []byte
+	toKey   func([]byte) Key
+	metrics merkleMetrics
+	// The number of value changes in the spill.
+	count int
+
+	// Held for writing while [refs] or [deleted] are modified, and for
+	// reading while the spill is read or written.
+	lock sync.RWMutex
+	// The number of holders of the spill that haven't released it.
+	refs int
+	// True once the spill was deleted from disk.
+	deleted bool
+}
+
+func (db *merkleDB) newValueSpill() *valueSpill {
+	prefix := make([]byte, len(viewSpillPrefix)+wrappers.LongLen)
+	copy(prefix, viewSpillPrefix)
+	binary.BigEndian.PutUint64(prefix[len(viewSpillPrefix):], db.nextSpillID.Add(1))
+
+	return &valueSpill{
+		db:      db.baseDB,
+		prefix:  prefix,
+		toKey:   db.toKey,
+		metrics: db.metrics,
+		refs:    1,
+	}
+}
+
+// acquire adds a holder of the spill, which must not have been deleted.
+func (s *valueSpill) acquire() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refs++
+}
+
+// release removes a holder of the spill, and deletes the spill from disk if
+// it was the last one. If the database was closed, the spill is deleted when
+// it's reopened.
+func (s *valueSpill) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refs--
+	if s.refs > 0 || s.deleted {
+		return
+	}
+
+	s.deleted = true
+	err := database.ClearPrefix(s.db, s.prefix, spillWriteSize)
+	if err != nil && !errors.Is(err, database.ErrClosed) {
+		s.metrics.ViewSpillDeletionFailed()
+	}
+}
+
+func (s *valueSpill) dbKey(key Key) []byte {
+	return append(slices.Clone(s.prefix), key.Bytes()...)
+}
+
+func (*valueSpill) encode(valueChange *change[maybe.Maybe[[]byte]]) []byte {
+	buf := &bytes.Buffer{}
+	codec.encodeMaybeByteSlice(buf, valueChange.before)
+	codec.encodeMaybeByteSlice(buf, valueChange.after)
+	return buf.Bytes()
+}
+
+func (*valueSpill) decode(b []byte) (*change[maybe.Maybe[[]byte]], error) {
+	src := bytes.NewReader(b)
+	before, err := codec.decodeMaybeByteSlice(src)
+	if err != nil {
+		return nil, err
+	}
+	after, err := codec.decodeMaybeByteSlice(src)
+	if err != nil {
+		return nil, err
+	}
+	return &change[maybe.Maybe[[]byte]]{
+		before: before,
+		after:  after,
+	}, nil
+}
+
+// get returns the spilled change to the value of [key], or false if it
+// wasn't spilled.
+func (s *valueSpill) get(key Key) (*change[maybe.Maybe[[]byte]], bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.deleted {
+		return nil, false, errSpillDeleted
+	}
+
+	b, err := s.db.Get(s.dbKey(key))
+	switch err {
+	case nil:
+		valueChange, err := s.decode(b)
+		return valueChange, err == nil, err
+	case database.ErrNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, err
+	}
+}
+
+// put spills [valueChange] as the change to the value of [key].
+func (s *valueSpill) put(key Key, valueChange *change[maybe.Maybe[[]byte]]) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.deleted {
+		return errSpillDeleted
+	}
+	return s.db.Put(s.dbKey(key), s.encode(valueChange))
+}
+
+// putAll spills [values] and returns the number of bytes written.
+func (s *valueSpill) putAll(values map[Key]*change[maybe.Maybe[[]byte]]) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.deleted {
+		return 0, errSpillDeleted
+	}
+
+	var (
+		batch        = s.db.NewBatch()
+		spilledBytes int
+	)
+	for key, valueChange := range values {
+		valueBytes := s.encode(valueChange)
+		if err := batch.Put(s.dbKey(key), valueBytes); err != nil {
+			return 0, err
+		}
+		if batch.Size() >= spillWriteSize {
+			if err := batch.Write(); err != nil {
+				return 0, err
+			}
+			batch.Reset()
+		}
+		spilledBytes += len(valueBytes)
+	}
+	return spilledBytes, batch.Write()
+}
+
+// forEach calls [fn] with every spilled value change in increasing key order.
+func (s *valueSpill) forEach(fn func(Key, *change[maybe.Maybe[[]byte]]) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.deleted {
+		return errSpillDeleted
+	}
+
+	it := s.db.NewIteratorWithPrefix(s.prefix)
+	defer it.Release()
+
+	for it.Next() {
+		valueChange, err := s.decode(it.Value())
+		if err != nil {
+			return err
+		}
+		key := s.toKey(slices.Clone(it.Key()[len(s.prefix):]))
+		if err := fn(key, valueChange); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// numValueChanges returns the number of keys whose value was changed.
+func (cs *changeSummary) numValueChanges() int {
+	if cs.spill == nil {
+		return len(cs.values)
+	}
+	return len(cs.values) + cs.spill.count
+}
+
+// getValueChange returns the change to the value of [key], or false if it
+// wasn't changed. Changes must not be modified. See [setValueAfter].
+func (cs *changeSummary) getValueChange(key Key) (*change[maybe.Maybe[[]byte]], bool, error) {
+	if valueChange, ok := cs.values[key]; ok {
+		return valueChange, true, nil
+	}
+	if cs.spill == nil {
+		return nil, false, nil
+	}
+	return cs.spill.get(key)
+}
+
+// addValueChange records a change to the value of [key] from [before] to
+// [after]. [key] must not have been changed already.
+// The value changes are spilled if they exceed [cs.valuesLimit].
+func (cs *changeSummary) addValueChange(key Key, before, after maybe.Maybe[[]byte]) error {
+	cs.values[key] = cs.newValueChange(before, after)
+	cs.valuesSize += valueChangeOverhead + len(key.value) + len(before.Value()) + len(after.Value())
+	return cs.spillValuesIfNeeded()
+}
+
+// setValueAfter replaces the change to the value of [key] with one to
+// [after]. The replaced change isn't modified, since it may have been handed
+// out by [getValueChange].
+// Returns false if [key] wasn't changed.
+func (cs *changeSummary) setValueAfter(key Key, after maybe.Maybe[[]byte]) (bool, error) {
+	if valueChange, ok := cs.values[key]; ok {
+		cs.values[key] = cs.newValueChange(valueChange.before, after)
+		cs.valuesSize += len(after.Value()) - len(valueChange.after.Value())
+		return true, cs.spillValuesIfNeeded()
+	}
+	if cs.spill == nil {
+		return false, nil
+	}
+
+	valueChange, ok, err := cs.spill.get(key)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, cs.spill.put(key, &change[maybe.Maybe[[]byte]]{
+		before: valueChange.before,
+		after:  after,
+	})
+}
+
+// forEachValueChange calls [fn] with every value change, in no particular
+// order. Changes must not be modified.
+func (cs *changeSummary) forEachValueChange(fn func(Key, *change[maybe.Maybe[[]byte]]) error) error {
+	for key, valueChange := range cs.values {
+		if err := fn(key, valueChange); err != nil {
+			return err
+		}
+	}
+	if cs.spill == nil {
+		return nil
+	}
+	return cs.spill.forEach(fn)
+}
+
+// sortedValueKeys returns the keys whose value was changed in increasing
+// order.
+func (cs *changeSummary) sortedValueKeys() ([]Key, error) {
+	keys := make([]Key, 0, cs.numValueChanges())
+	err := cs.forEachValueChange(func(key Key, _ *change[maybe.Maybe[[]byte]]) error {
+		keys = append(keys, key)
+		return nil
+	})
+	slices.SortFunc(keys, Key.Less)
+	return keys, err
+}
+
+// spillValuesIfNeeded moves [cs.values] to disk if they exceed
+// [cs.valuesLimit].
+func (cs *changeSummary) spillValuesIfNeeded() error {
+	if cs.valuesLimit == 0 || cs.valuesSize <= cs.valuesLimit {
+		return nil
+	}
+
+	if cs.spill == nil {
+		cs.spill = cs.db.newValueSpill()
+	}
+	spilledBytes, err := cs.spill.putAll(cs.values)
+	if err != nil {
+		return err
+	}
+
+	numSpilled := len(cs.values)
+	cs.spill.count += numSpilled
+	cs.db.metrics.ViewValuesSpilled(numSpilled, spilledBytes)
+
+	// Clearing the map wouldn't release its buckets, and the arena would keep
+	// the spilled changes alive.
+	cs.values = make(map[Key]*change[maybe.Maybe[[]byte]])
+	cs.valueChanges = newArena[change[maybe.Maybe[[]byte]]](defaultPreallocationSize)
+	cs.valuesSize = 0
+	return nil
+}
+
+// releaseSpill releases the holder's reference to the spilled value changes,
+// if any. See [valueSpill.release].
+func (cs *changeSummary) releaseSpill() {
+	if cs.spill != nil {
+		cs.spill.release()
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+func newSpillTestDB(t *testing.T, baseDB database.Database, viewMemoryLimit uint) *merkleDB {
+	config := newDefaultConfig()
+	config.ViewMemoryLimit = viewMemoryLimit
+	db, err := newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(t, err)
+	return db
+}
+
+func numSpilledEntries(t *testing.T, baseDB database.Database) int {
+	it := baseDB.NewIteratorWithPrefix(viewSpillPrefix)
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.NoError(t, it.Error())
+	return count
+}
+
+func TestViewMemoryLimitSpillsValueChanges(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		baseDB  = memdb.New()
+		db      = newSpillTestDB(t, baseDB, 1024)
+		noSpill = newSpillTestDB(t, memdb.New(), 0)
+		changes = ViewChanges{}
+	)
+	for _, trie := range []*merkleDB{db, noSpill} {
+		require.NoError(trie.Put([]byte("prefix/deleted"), []byte("value")))
+		require.NoError(trie.Put([]byte("overwritten"), []byte("value")))
+	}
+	for i := 0; i < 100; i++ {
+		changes.BatchOps = append(changes.BatchOps, database.BatchOp{
+			Key:   []byte(fmt.Sprintf("key%03d", i)),
+			Value: []byte(fmt.Sprintf("value%03d", i)),
+		})
+	}
+	changes.BatchOps = append(changes.BatchOps,
+		database.BatchOp{Key: []byte("overwritten"), Value: []byte("new value")},
+		// Overwrites a change that was spilled.
+		database.BatchOp{Key: []byte("key000"), Value: []byte("new value")},
+	)
+	changes.DeletePrefixes = [][]byte{[]byte("prefix/")}
+
+	startRoot, err := db.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), changes)
+	require.NoError(err)
+	expectedView, err := noSpill.NewView(context.Background(), changes)
+	require.NoError(err)
+
+	metrics := db.metrics.(*mockMetrics)
+	require.Positive(metrics.viewValuesSpilled)
+	require.Positive(metrics.viewValueBytesSpilled)
+	require.Positive(numSpilledEntries(t, baseDB))
+
+	spilledView := view.(*trieView)
+	require.NotNil(spilledView.changes.spill)
+	require.Equal(102, spilledView.changes.numValueChanges())
+
+	// Reads of spilled changes are served from disk.
+	value, err := view.GetValue(context.Background(), []byte("key000"))
+	require.NoError(err)
+	require.Equal([]byte("new value"), value)
+	_, err = view.GetValue(context.Background(), []byte("prefix/deleted"))
+	require.ErrorIs(err, database.ErrNotFound)
+
+	expectedRoot, err := expectedView.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	root, err := view.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(expectedRoot, root)
+
+	it := view.NewIterator()
+	expectedIt := expectedView.NewIterator()
+	for expectedIt.Next() {
+		require.True(it.Next())
+		require.Equal(expectedIt.Key(), it.Key())
+		require.Equal(expectedIt.Value(), it.Value())
+	}
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+	expectedIt.Release()
+
+	// The history of a commit reads the spilled changes from disk.
+	require.NoError(view.CommitToDB(context.Background()))
+	require.NoError(expectedView.CommitToDB(context.Background()))
+	lastChanges, ok := db.history.lastChanges[root]
+	require.True(ok)
+	require.NotNil(lastChanges.spill)
+	require.Equal(102, lastChanges.numValueChanges())
+	require.Positive(numSpilledEntries(t, baseDB))
+	value, err = db.Get([]byte("key000"))
+	require.NoError(err)
+	require.Equal([]byte("new value"), value)
+
+	changeProof, err := db.GetChangeProof(context.Background(), startRoot, root, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 200)
+	require.NoError(err)
+	expectedChangeProof, err := noSpill.GetChangeProof(context.Background(), startRoot, root, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), 200)
+	require.NoError(err)
+	require.Equal(expectedChangeProof.KeyChanges, changeProof.KeyChanges)
+}
+
+func TestViewMemoryLimitReleaseDeletesSpill(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newSpillTestDB(t, baseDB, 1)
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("key"), Value: []byte("value")},
+		},
+	})
+	require.NoError(err)
+	child, err := view.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+	require.Positive(numSpilledEntries(t, baseDB))
+
+	// The child still reads the spilled changes through [view].
+	view.Release()
+	require.Positive(numSpilledEntries(t, baseDB))
+	value, err := child.GetValue(context.Background(), []byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+
+	child.Release()
+	require.Zero(numSpilledEntries(t, baseDB))
+	require.ErrorIs(view.CommitToDB(context.Background()), ErrViewReleased)
+}
+
+func TestViewMemoryLimitPruneDeletesSpill(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	config := newDefaultConfig()
+	config.ViewMemoryLimit = 1
+	config.HistoryLength = 1
+	db, err := newDatabase(context.Background(), baseDB, config, &mockMetrics{})
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("key"), Value: []byte("value")},
+		},
+	})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+
+	// The history still needs the spilled changes.
+	require.Positive(numSpilledEntries(t, baseDB))
+
+	require.NoError(db.Put([]byte("other"), []byte("value")))
+	require.Zero(numSpilledEntries(t, baseDB))
+	require.Zero(db.metrics.(*mockMetrics).viewSpillDeletionFails)
+}
+
+func TestSetValueAfterDoesNotModifyChange(t *testing.T) {
+	require := require.New(t)
+
+	db := newSpillTestDB(t, memdb.New(), 0)
+	cs := newChangeSummary(1)
+	cs.db = db
+	key := db.toKey([]byte("key"))
+	require.NoError(cs.addValueChange(key, maybe.Nothing[[]byte](), maybe.Some([]byte("value"))))
+
+	valueChange, ok, err := cs.getValueChange(key)
+	require.NoError(err)
+	require.True(ok)
+
+	changed, err := cs.setValueAfter(key, maybe.Some([]byte("new value")))
+	require.NoError(err)
+	require.True(changed)
+	require.Equal(maybe.Some([]byte("value")), valueChange.after)
+
+	valueChange, ok, err = cs.getValueChange(key)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(maybe.Nothing[[]byte](), valueChange.before)
+	require.Equal(maybe.Some([]byte("new value")), valueChange.after)
+}
+
+func TestViewMemoryLimitClearsSpillsOnOpen(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db := newSpillTestDB(t, baseDB, 1)
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("key"), Value: []byte("value")},
+		},
+	})
+	require.NoError(err)
+	require.Positive(numSpilledEntries(t, baseDB))
+
+	// The spill of a view that is never released is left on disk.
+	require.NoError(db.Close())
+	require.Positive(numSpilledEntries(t, baseDB))
+	runtime.KeepAlive(view)
+
+	_ = newSpillTestDB(t, baseDB, 1)
+	require.Zero(numSpilledEntries(t, baseDB))
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidValue  = errors.New("invalid value")
+	ErrValueTooLarge = errors.New("value too large")
+)
+
+// ValueValidator returns an error if [value] must not be written to [key].
+type ValueValidator func(key []byte, value []byte) error
+
+// PrefixValidator validates the values written to keys that start with
+// [Prefix].
+type PrefixValidator struct {
+	Prefix   []byte
+	Validate ValueValidator
+}
+
+// MaxValueSize returns a ValueValidator that rejects values longer than
+// [size] bytes with [ErrValueTooLarge].
+func MaxValueSize(size int) ValueValidator {
+	return func(_ []byte, value []byte) error {
+		if len(value) > size {
+			return fmt.Errorf("%w: %d bytes > %d bytes", ErrValueTooLarge, len(value), size)
+		}
+		return nil
+	}
+}
+
+// validateValueChanges returns an error if a value written by [changes] is
+// rejected by the validator of a prefix of its key. The error wraps both
+// [ErrInvalidValue] and the validator's error. Deletes aren't validated.
+func (db *merkleDB) validateValueChanges(changes ViewChanges) error {
+	if len(db.valueValidators) == 0 {
+		return nil
+	}
+
+	for _, op := range changes.BatchOps {
+		if op.Delete {
+			continue
+		}
+		if err := db.validateValue(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	for key, value := range changes.MapOps {
+		if value.IsNothing() {
+			continue
+		}
+		if err := db.validateValue([]byte(key), value.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValue calls, in order, every validator of a prefix of [key].
+func (db *merkleDB) validateValue(key []byte, value []byte) error {
+	for _, validator := range db.valueValidators {
+		if !bytes.HasPrefix(key, validator.Prefix) {
+			continue
+		}
+		if err := validator.Validate(key, value); err != nil {
+			return fmt.Errorf("%w for key 0x%x: %w", ErrInvalidValue, key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var errNotJSON = errors.New("not JSON")
+
+func newValueValidatorsConfig() Config {
+	config := newDefaultConfig()
+	config.ValueValidators = []PrefixValidator{
+		{
+			Prefix:   []byte("a"),
+			Validate: MaxValueSize(4),
+		},
+		{
+			Prefix: []byte("ab"),
+			Validate: func(_ []byte, value []byte) error {
+				if len(value) == 0 || value[0] != '{' {
+					return errNotJSON
+				}
+				return nil
+			},
+		},
+	}
+	return config
+}
+
+func Test_MerkleDB_ValueValidators(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         []byte
+		value       []byte
+		expectedErr error
+	}{
+		{
+			name:  "no validator",
+			key:   []byte("b"),
+			value: []byte("too long"),
+		},
+		{
+			name:  "valid",
+			key:   []byte("ab"),
+			value: []byte("{}"),
+		},
+		{
+			name:        "too large",
+			key:         []byte("a"),
+			value:       []byte("too long"),
+			expectedErr: ErrValueTooLarge,
+		},
+		{
+			name:        "invalid encoding",
+			key:         []byte("abc"),
+			value:       []byte("[]"),
+			expectedErr: errNotJSON,
+		},
+		{
+			name:        "every validator of a prefix is called",
+			key:         []byte("ab"),
+			value:       []byte("{too long}"),
+			expectedErr: ErrValueTooLarge,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			db, err := newDB(context.Background(), memdb.New(), newValueValidatorsConfig())
+			require.NoError(err)
+
+			batchOps := ViewChanges{
+				BatchOps: []database.BatchOp{{Key: test.key, Value: test.value}},
+			}
+			mapOps := ViewChanges{
+				MapOps: map[string]maybe.Maybe[[]byte]{
+					string(test.key): maybe.Some(test.value),
+				},
+			}
+			view, err := db.NewView(context.Background(), ViewChanges{})
+			require.NoError(err)
+
+			// Views are created before the commits, which invalidate [view].
+			writes := []func() error{
+				func() error {
+					_, err := view.NewView(context.Background(), batchOps)
+					return err
+				},
+				func() error {
+					_, err := db.NewView(context.Background(), batchOps)
+					return err
+				},
+				func() error {
+					_, err := db.NewView(context.Background(), mapOps)
+					return err
+				},
+				func() error {
+					return db.Put(test.key, test.value)
+				},
+				func() error {
+					batch := db.NewBatch()
+					require.NoError(batch.Put(test.key, test.value))
+					return batch.Write()
+				},
+			}
+			for _, write := range writes {
+				err := write()
+				require.ErrorIs(err, test.expectedErr)
+				if test.expectedErr != nil {
+					require.ErrorIs(err, ErrInvalidValue)
+				}
+			}
+
+			// Rejected values are never staged.
+			has, err := db.Has(test.key)
+			require.NoError(err)
+			require.Equal(test.expectedErr == nil, has)
+
+			// Deletes aren't validated.
+			require.NoError(db.Delete(test.key))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var (
+	ErrRangeConflict    = errors.New("key range overlaps a locked key range")
+	ErrKeyOutOfRange    = errors.New("key is outside of the locked key range")
+	ErrRangeLockClosed  = errors.New("range lock was already committed or aborted")
+	ErrRangeLocksOpen   = errors.New("range locks haven't been committed or aborted")
+	ErrViewAlreadyBuilt = errors.New("view was already built")
+)
+
+// ConcurrentViewBuilder stages writes from multiple goroutines into a single
+// new view of a trie. Each goroutine locks the key range it writes to, and the
+// locked ranges may not overlap, so the writes of different goroutines never
+// conflict.
+//
+// This is experimental. Locks are checked for overlaps linearly, so it is
+// intended for a modest number of locks per view.
+type ConcurrentViewBuilder struct {
+	parent Trie
+
+	lock  sync.Mutex
+	built bool
+	// Ranges that are locked. Committed locks keep their range locked, so
+	// that later writers can't overwrite the committed changes.
+	locks []*RangeLock
+	// Key --> The change committed to it
+	changes map[string]maybe.Maybe[[]byte]
+}
+
+// NewConcurrentViewBuilder returns a builder of a view on top of [parent].
+func NewConcurrentViewBuilder(parent Trie) *ConcurrentViewBuilder {
+	return &ConcurrentViewBuilder{
+		parent:  parent,
+		changes: make(map[string]maybe.Maybe[[]byte]),
+	}
+}
+
+// LockRange locks the keys in [start, end], so that they can be written to by
+// the returned lock. If [start] is Nothing, the range has no lower bound. If
+// [end] is Nothing, the range has no upper bound.
+//
+// Returns [ErrRangeConflict] if the range overlaps a range that is already
+// locked.
+func (b *ConcurrentViewBuilder) LockRange(start, end maybe.Maybe[[]byte]) (*RangeLock, error) {
+	if start.HasValue() && end.HasValue() && bytes.Compare(start.Value(), end.Value()) > 0 {
+		return nil, ErrStartAfterEnd
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.built {
+		return nil, ErrViewAlreadyBuilt
+	}
+
+	l := &RangeLock{
+		builder: b,
+		start:   maybe.Bind(start, bytes.Clone),
+		end:     maybe.Bind(end, bytes.Clone),
+		changes: make(map[string]maybe.Maybe[[]byte]),
+	}
+	for _, locked := range b.locks {
+		if l.overlaps(locked) {
+			return nil, ErrRangeConflict
+		}
+	}
+	b.locks = append(b.locks, l)
+	return l, nil
+}
+
+// Build returns a view on top of the parent trie with every committed change
+// applied. Every lock must be committed or aborted before the view is built.
+// After Build returns successfully, no more ranges can be locked.
+func (b *ConcurrentViewBuilder) Build(ctx context.Context) (TrieView, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.built {
+		return nil, ErrViewAlreadyBuilt
+	}
+	for _, l := range b.locks {
+		if !l.closed {
+			return nil, ErrRangeLocksOpen
+		}
+	}
+
+	view, err := b.parent.NewView(ctx, ViewChanges{
+		MapOps: b.changes,
+		// The changes were copied when they were staged.
+		ConsumeBytes: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.built = true
+	b.changes = nil
+	return view, nil
+}
+
+// commit adds the changes of [l] to the view being built.
+func (b *ConcurrentViewBuilder) commit(l *RangeLock) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if l.closed {
+		return ErrRangeLockClosed
+	}
+	l.closed = true
+	for key, change := range l.changes {
+		b.changes[key] = change
+	}
+	l.changes = nil
+	return nil
+}
+
+// abort unlocks the range of [l] without adding its changes to the view being
+// built.
+func (b *ConcurrentViewBuilder) abort(l *RangeLock) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if l.closed {
+		return ErrRangeLockClosed
+	}
+	l.closed = true
+	l.changes = nil
+	for i, locked := range b.locks {
+		if locked == l {
+			lastIndex := len(b.locks) - 1
+			b.locks[i] = b.locks[lastIndex]
+			b.locks[lastIndex] = nil
+			b.locks = b.locks[:lastIndex]
+			break
+		}
+	}
+	return nil
+}
+
+// RangeLock stages changes to the keys of a range locked by a
+// [ConcurrentViewBuilder]. The changes are added to the view being built once
+// the lock is committed.
+//
+// A RangeLock isn't safe for concurrent use, but different locks of the same
+// builder may be used concurrently.
+type RangeLock struct {
+	builder *ConcurrentViewBuilder
+	start   maybe.Maybe[[]byte]
+	end     maybe.Maybe[[]byte]
+	// Key --> The change staged to it
+	changes map[string]maybe.Maybe[[]byte]
+	// Set once the lock is committed or aborted.
+	// Only modified while holding [builder.lock].
+	closed bool
+}
+
+// GetValue returns the value of [key], including the changes staged by this
+// lock. Returns [database.ErrNotFound] if the key isn't in the trie or was
+// deleted.
+func (l *RangeLock) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	if err := l.verifyKey(key); err != nil {
+		return nil, err
+	}
+	if change, ok := l.changes[string(key)]; ok {
+		if change.IsNothing() {
+			return nil, database.ErrNotFound
+		}
+		return bytes.Clone(change.Value()), nil
+	}
+	return l.builder.parent.GetValue(ctx, key)
+}
+
+// Put stages [key] to be set to [value].
+func (l *RangeLock) Put(key []byte, value []byte) error {
+	if err := l.verifyKey(key); err != nil {
+		return err
+	}
+	l.changes[string(key)] = maybe.Some(bytes.Clone(value))
+	return nil
+}
+
+// Delete stages [key] to be removed.
+func (l *RangeLock) Delete(key []byte) error {
+	if err := l.verifyKey(key); err != nil {
+		return err
+	}
+	l.changes[string(key)] = maybe.Nothing[[]byte]()
+	return nil
+}
+
+// Commit adds the staged changes to the view being built. The range stays
+// locked until the view is built.
+func (l *RangeLock) Commit() error {
+	return l.builder.commit(l)
+}
+
+// Abort discards the staged changes and unlocks the range, so that it can be
+// locked again.
+func (l *RangeLock) Abort() error {
+	return l.builder.abort(l)
+}
+
+// verifyKey returns nil if [key] can be accessed by this lock.
+func (l *RangeLock) verifyKey(key []byte) error {
+	// [l.closed] is only modified by Commit and Abort, which aren't called
+	// concurrently with the other methods of this lock, so it can be read
+	// without holding [l.builder.lock].
+	switch {
+	case l.closed:
+		return ErrRangeLockClosed
+	case l.start.HasValue() && bytes.Compare(key, l.start.Value()) < 0,
+		l.end.HasValue() && bytes.Compare(key, l.end.Value()) > 0:
+		return ErrKeyOutOfRange
+	default:
+		return nil
+	}
+}
+
+// overlaps returns true if the range of [l] shares any key with the range of
+// [other].
+func (l *RangeLock) overlaps(other *RangeLock) bool {
+	// The ranges overlap unless one of them ends before the other starts.
+	return !endsBefore(l.end, other.start) && !endsBefore(other.end, l.start)
+}
+
+// endsBefore returns true if a range ending at [end] contains no keys at or
+// after [start].
+func endsBefore(end, start maybe.Maybe[[]byte]) bool {
+	if end.IsNothing() || start.IsNothing() {
+		return false
+	}
+	return bytes.Compare(end.Value(), start.Value()) < 0
+}
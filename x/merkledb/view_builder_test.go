@@ -0,0 +1,223 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+func TestConcurrentViewBuilderLockRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		start       maybe.Maybe[[]byte]
+		end         maybe.Maybe[[]byte]
+		expectedErr error
+	}{
+		{
+			name:        "start after end",
+			start:       maybe.Some([]byte{5}),
+			end:         maybe.Some([]byte{4}),
+			expectedErr: ErrStartAfterEnd,
+		},
+		{
+			name:        "overlaps start",
+			start:       maybe.Some([]byte{0}),
+			end:         maybe.Some([]byte{2}),
+			expectedErr: ErrRangeConflict,
+		},
+		{
+			name:        "overlaps end",
+			start:       maybe.Some([]byte{4}),
+			end:         maybe.Some([]byte{6}),
+			expectedErr: ErrRangeConflict,
+		},
+		{
+			name:        "contains locked range",
+			start:       maybe.Nothing[[]byte](),
+			end:         maybe.Nothing[[]byte](),
+			expectedErr: ErrRangeConflict,
+		},
+		{
+			name:        "unbounded below",
+			start:       maybe.Nothing[[]byte](),
+			end:         maybe.Some([]byte{1}),
+			expectedErr: nil,
+		},
+		{
+			name:        "unbounded above",
+			start:       maybe.Some([]byte{4, 0}),
+			end:         maybe.Nothing[[]byte](),
+			expectedErr: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			db, err := getBasicDB()
+			require.NoError(err)
+
+			builder := NewConcurrentViewBuilder(db)
+			_, err = builder.LockRange(maybe.Some([]byte{2}), maybe.Some([]byte{4}))
+			require.NoError(err)
+
+			_, err = builder.LockRange(test.start, test.end)
+			require.ErrorIs(err, test.expectedErr)
+		})
+	}
+}
+
+func TestConcurrentViewBuilderRangeLock(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+	require.NoError(db.Put([]byte{2}, []byte{2}))
+
+	builder := NewConcurrentViewBuilder(db)
+	l, err := builder.LockRange(maybe.Some([]byte{1}), maybe.Some([]byte{2}))
+	require.NoError(err)
+
+	// Keys outside of the range can't be accessed.
+	_, err = l.GetValue(context.Background(), []byte{3})
+	require.ErrorIs(err, ErrKeyOutOfRange)
+	require.ErrorIs(l.Put([]byte{0}, []byte{0}), ErrKeyOutOfRange)
+	require.ErrorIs(l.Delete([]byte{2, 0}), ErrKeyOutOfRange)
+
+	// Staged changes are visible to the lock.
+	require.NoError(l.Put([]byte{1}, []byte{10}))
+	require.NoError(l.Delete([]byte{2}))
+	value, err := l.GetValue(context.Background(), []byte{1})
+	require.NoError(err)
+	require.Equal([]byte{10}, value)
+	_, err = l.GetValue(context.Background(), []byte{2})
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// The view can't be built while the lock is open.
+	_, err = builder.Build(context.Background())
+	require.ErrorIs(err, ErrRangeLocksOpen)
+
+	require.NoError(l.Commit())
+	require.ErrorIs(l.Commit(), ErrRangeLockClosed)
+	require.ErrorIs(l.Abort(), ErrRangeLockClosed)
+	require.ErrorIs(l.Put([]byte{1}, []byte{1}), ErrRangeLockClosed)
+
+	// Committed ranges stay locked.
+	_, err = builder.LockRange(maybe.Some([]byte{2}), maybe.Some([]byte{2}))
+	require.ErrorIs(err, ErrRangeConflict)
+
+	view, err := builder.Build(context.Background())
+	require.NoError(err)
+	value, err = view.GetValue(context.Background(), []byte{1})
+	require.NoError(err)
+	require.Equal([]byte{10}, value)
+	_, err = view.GetValue(context.Background(), []byte{2})
+	require.ErrorIs(err, database.ErrNotFound)
+
+	_, err = builder.Build(context.Background())
+	require.ErrorIs(err, ErrViewAlreadyBuilt)
+	_, err = builder.LockRange(maybe.Some([]byte{3}), maybe.Some([]byte{3}))
+	require.ErrorIs(err, ErrViewAlreadyBuilt)
+}
+
+func TestConcurrentViewBuilderAbort(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	builder := NewConcurrentViewBuilder(db)
+	l, err := builder.LockRange(maybe.Some([]byte{1}), maybe.Some([]byte{2}))
+	require.NoError(err)
+	require.NoError(l.Put([]byte{1}, []byte{1}))
+	require.NoError(l.Abort())
+	require.ErrorIs(l.Commit(), ErrRangeLockClosed)
+
+	// Aborting unlocks the range.
+	l, err = builder.LockRange(maybe.Some([]byte{2}), maybe.Some([]byte{3}))
+	require.NoError(err)
+	require.NoError(l.Put([]byte{3}, []byte{3}))
+	require.NoError(l.Commit())
+
+	view, err := builder.Build(context.Background())
+	require.NoError(err)
+	_, err = view.GetValue(context.Background(), []byte{1})
+	require.ErrorIs(err, database.ErrNotFound)
+	value, err := view.GetValue(context.Background(), []byte{3})
+	require.NoError(err)
+	require.Equal([]byte{3}, value)
+}
+
+func TestConcurrentViewBuilderConcurrentWriters(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	const (
+		numWriters    = 8
+		keysPerWriter = 32
+	)
+	builder := NewConcurrentViewBuilder(db)
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, numWriters)
+	)
+	for i := 0; i < numWriters; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Each writer owns the keys prefixed with its index.
+			l, err := builder.LockRange(maybe.Some([]byte{byte(i)}), maybe.Some([]byte{byte(i), 0xff}))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for j := 0; j < keysPerWriter; j++ {
+				key := []byte{byte(i), byte(j)}
+				if err := l.Put(key, key); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+			errs[i] = l.Commit()
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(err)
+	}
+
+	view, err := builder.Build(context.Background())
+	require.NoError(err)
+	rootID, err := view.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	// The view matches the view with the same changes made sequentially.
+	ops := make([]database.BatchOp, 0, numWriters*keysPerWriter)
+	for i := 0; i < numWriters; i++ {
+		for j := 0; j < keysPerWriter; j++ {
+			key := []byte{byte(i), byte(j)}
+			ops = append(ops, database.BatchOp{
+				Key:   key,
+				Value: key,
+			})
+		}
+	}
+	expectedView, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+	require.NoError(err)
+	expectedRootID, err := expectedView.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(expectedRootID, rootID)
+}
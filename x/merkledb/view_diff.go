@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+var (
+	ErrDiffOtherDatabase = errors.New("can't diff tries of different databases")
+
+	errUnsupportedTrie = errors.New("unsupported trie")
+)
+
+// Diff returns the changes that turn [t] into [other], sorted by key.
+func (t *trieView) Diff(ctx context.Context, other TrieView) ([]KeyChange, error) {
+	ctx, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.Diff")
+	defer span.End()
+
+	return t.db.diff(ctx, t, other)
+}
+
+// Diff returns the changes that turn the committed trie into [other], sorted
+// by key.
+func (db *merkleDB) Diff(ctx context.Context, other TrieView) ([]KeyChange, error) {
+	ctx, span := db.infoTracer.Start(ctx, "MerkleDB.Diff")
+	defer span.End()
+
+	return db.diff(ctx, db, other)
+}
+
+// Diff returns the changes that turn the trie as it was when the snapshot was
+// taken into [other], sorted by key.
+func (s *snapshot) Diff(ctx context.Context, other TrieView) ([]KeyChange, error) {
+	return s.db.diff(ctx, s, other)
+}
+
+// diff returns the changes that turn [from] into [to], sorted by key.
+//
+// The tries are walked together from their roots. A subtree is skipped if
+// its ID is the same in both tries, so for tries that share a recent
+// ancestor, only the nodes on the paths to the keys changed since that
+// ancestor are read.
+//
+// Assumes [db.lock] isn't held.
+func (db *merkleDB) diff(ctx context.Context, from, to TrieView) ([]KeyChange, error) {
+	var changes []KeyChange
+	err := db.readCommitted(func(state *readState) error {
+		if state.closed {
+			return database.ErrClosed
+		}
+
+		fromView, err := db.diffView(from)
+		if err != nil {
+			return err
+		}
+		toView, err := db.diffView(to)
+		if err != nil {
+			return err
+		}
+
+		if err := fromView.calculateNodeIDs(ctx); err != nil {
+			return err
+		}
+		if err := toView.calculateNodeIDs(ctx); err != nil {
+			return err
+		}
+
+		d := trieDiff{
+			ctx:  ctx,
+			from: fromView,
+			to:   toView,
+		}
+		if fromView.root.id != toView.root.id {
+			if err := d.diffNodes(fromView.root, toView.root); err != nil {
+				return err
+			}
+		}
+
+		// ensure no ancestor changes occurred during execution
+		if fromView.isInvalid() || toView.isInvalid() {
+			return ErrInvalid
+		}
+
+		slices.SortFunc(d.changes, func(a, b KeyChange) bool {
+			return bytes.Compare(a.Key, b.Key) < 0
+		})
+		changes = d.changes
+		return nil
+	})
+	return changes, err
+}
+
+// diffView returns a view of [trie] whose nodes can be read while diffing.
+func (db *merkleDB) diffView(trie TrieView) (*trieView, error) {
+	switch trie := trie.(type) {
+	case *trieView:
+		if trie.db != db {
+			return nil, ErrDiffOtherDatabase
+		}
+		return trie, nil
+	case *merkleDB:
+		if trie != db {
+			return nil, ErrDiffOtherDatabase
+		}
+		return newTrieView(db, db, ViewChanges{})
+	case *snapshot:
+		if trie.db != db {
+			return nil, ErrDiffOtherDatabase
+		}
+		return newTrieView(db, trie, ViewChanges{})
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnsupportedTrie, trie)
+	}
+}
+
+// trieDiff accumulates the changes that turn [from] into [to].
+// The node IDs of both views must have been calculated.
+type trieDiff struct {
+	ctx     context.Context
+	from    *trieView
+	to      *trieView
+	changes []KeyChange
+}
+
+// diffNodes records the changes that turn the subtree of [from] in [d.from]
+// into the subtree of [to] in [d.to]. [from] and [to] are the nodes at the
+// same position in their tries, but their keys may differ because the paths
+// to them are compressed differently.
+func (d *trieDiff) diffNodes(from, to *node) error {
+	switch {
+	case from.key == to.key:
+		if !maybe.Equal(from.value, to.value, bytes.Equal) {
+			d.recordChange(from.key, to.value)
+		}
+		for index, fromChild := range from.children {
+			toChild, ok := to.children[index]
+			if ok && fromChild.id == toChild.id {
+				// The subtrees are the same.
+				continue
+			}
+
+			fromChildNode, err := d.getChild(d.from, from, index, fromChild)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				if err := d.recordSubtree(d.from, fromChildNode, false /*added*/); err != nil {
+					return err
+				}
+				continue
+			}
+
+			toChildNode, err := d.getChild(d.to, to, index, toChild)
+			if err != nil {
+				return err
+			}
+			if err := d.diffNodes(fromChildNode, toChildNode); err != nil {
+				return err
+			}
+		}
+		for index, toChild := range to.children {
+			if _, ok := from.children[index]; ok {
+				// Diffed above.
+				continue
+			}
+			toChildNode, err := d.getChild(d.to, to, index, toChild)
+			if err != nil {
+				return err
+			}
+			if err := d.recordSubtree(d.to, toChildNode, true /*added*/); err != nil {
+				return err
+			}
+		}
+		return nil
+	case to.key.HasStrictPrefix(from.key):
+		// [to]'s subtree is at most one branch of [from]'s subtree.
+		return d.diffBranch(d.from, from, to, false /*added*/)
+	case from.key.HasStrictPrefix(to.key):
+		// [from]'s subtree is at most one branch of [to]'s subtree.
+		return d.diffBranch(d.to, to, from, true /*added*/)
+	default:
+		// The subtrees don't have any keys in common.
+		if err := d.recordSubtree(d.from, from, false /*added*/); err != nil {
+			return err
+		}
+		return d.recordSubtree(d.to, to, true /*added*/)
+	}
+}
+
+// diffBranch records the changes between the subtree of [n] in [view] and
+// the subtree of [other], whose key has [n]'s key as a strict prefix.
+// If [added], [view] is [d.to]. Otherwise, [view] is [d.from].
+//
+// The value of [n] and all of its branches except for the one that leads to
+// [other] are only in [view]'s subtree. The branch that leads to [other] is
+// diffed against it.
+func (d *trieDiff) diffBranch(view *trieView, n *node, other *node, added bool) error {
+	if n.hasValue() {
+		d.recordValue(n, added)
+	}
+
+	otherIndex := other.key.Token(n.key.tokenLength)
+	if _, ok := n.children[otherIndex]; !ok {
+		if err := d.recordSubtree(d.otherView(view), other, !added); err != nil {
+			return err
+		}
+	}
+	for index, entry := range n.children {
+		childNode, err := d.getChild(view, n, index, entry)
+		if err != nil {
+			return err
+		}
+		if index != otherIndex {
+			if err := d.recordSubtree(view, childNode, added); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if added {
+			err = d.diffNodes(other, childNode)
+		} else {
+			err = d.diffNodes(childNode, other)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordSubtree records every value in the subtree of [n] in [view] as added
+// if [added] and as removed otherwise.
+func (d *trieDiff) recordSubtree(view *trieView, n *node, added bool) error {
+	if n.hasValue() {
+		d.recordValue(n, added)
+	}
+	for index, entry := range n.children {
+		childNode, err := d.getChild(view, n, index, entry)
+		if err != nil {
+			return err
+		}
+		if err := d.recordSubtree(view, childNode, added); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordValue records the value of [n] as added if [added] and as removed
+// otherwise.
+func (d *trieDiff) recordValue(n *node, added bool) {
+	if added {
+		d.recordChange(n.key, n.value)
+	} else {
+		d.recordChange(n.key, maybe.Nothing[[]byte]())
+	}
+}
+
+func (d *trieDiff) recordChange(key Key, value maybe.Maybe[[]byte]) {
+	d.changes = append(d.changes, KeyChange{
+		Key:   key.Bytes(),
+		Value: maybe.Bind(value, slices.Clone[[]byte]),
+	})
+}
+
+// getChild returns the child of [parent] at [index] in [view].
+func (d *trieDiff) getChild(view *trieView, parent *node, index byte, entry child) (*node, error) {
+	if err := d.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return view.getNodeForProof(
+		entry.id,
+		parent.key.AppendExtend(index, entry.compressedKey),
+		entry.hasValue,
+	)
+}
+
+func (d *trieDiff) otherView(view *trieView) *trieView {
+	if view == d.from {
+		return d.to
+	}
+	return d.from
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+// Returns the changes that turn [from] into [to] by iterating over all of
+// their keys.
+func iteratedDiff(t *testing.T, from, to database.Iteratee) []KeyChange {
+	fromIt := from.NewIterator()
+	defer fromIt.Release()
+	toIt := to.NewIterator()
+	defer toIt.Release()
+
+	var (
+		changes []KeyChange
+		fromOk  = fromIt.Next()
+		toOk    = toIt.Next()
+	)
+	for fromOk || toOk {
+		compare := 0
+		switch {
+		case !fromOk:
+			compare = 1
+		case !toOk:
+			compare = -1
+		default:
+			compare = bytes.Compare(fromIt.Key(), toIt.Key())
+		}
+
+		switch {
+		case compare < 0:
+			changes = append(changes, KeyChange{
+				Key:   fromIt.Key(),
+				Value: maybe.Nothing[[]byte](),
+			})
+			fromOk = fromIt.Next()
+		case compare > 0:
+			changes = append(changes, KeyChange{
+				Key:   toIt.Key(),
+				Value: maybe.Some(toIt.Value()),
+			})
+			toOk = toIt.Next()
+		default:
+			if !bytes.Equal(fromIt.Value(), toIt.Value()) {
+				changes = append(changes, KeyChange{
+					Key:   toIt.Key(),
+					Value: maybe.Some(toIt.Value()),
+				})
+			}
+			fromOk = fromIt.Next()
+			toOk = toIt.Next()
+		}
+	}
+	require.NoError(t, fromIt.Error())
+	require.NoError(t, toIt.Error())
+	return changes
+}
+
+func Test_TrieView_Diff(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	for _, key := range []string{"a", "ab", "abc", "b", "ba", "c"} {
+		require.NoError(db.Put([]byte(key), []byte(key)))
+	}
+
+	from, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("d"), Value: []byte("d")},
+		},
+	})
+	require.NoError(err)
+	to, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("ab"), Delete: true},
+			{Key: []byte("abd"), Value: []byte("abd")},
+			{Key: []byte("b"), Value: []byte("new b")},
+			{Key: []byte("ba"), Delete: true},
+			{Key: []byte("e"), Value: []byte("e")},
+		},
+	})
+	require.NoError(err)
+
+	expected := []KeyChange{
+		{Key: []byte("ab"), Value: maybe.Nothing[[]byte]()},
+		{Key: []byte("abd"), Value: maybe.Some([]byte("abd"))},
+		{Key: []byte("b"), Value: maybe.Some([]byte("new b"))},
+		{Key: []byte("ba"), Value: maybe.Nothing[[]byte]()},
+		{Key: []byte("d"), Value: maybe.Nothing[[]byte]()},
+		{Key: []byte("e"), Value: maybe.Some([]byte("e"))},
+	}
+	changes, err := from.Diff(context.Background(), to)
+	require.NoError(err)
+	require.Equal(expected, changes)
+
+	// A trie has no changes from itself.
+	changes, err = to.Diff(context.Background(), to)
+	require.NoError(err)
+	require.Empty(changes)
+
+	// The database and snapshots of it can be diffed too.
+	changes, err = db.Diff(context.Background(), to)
+	require.NoError(err)
+	require.Equal(iteratedDiff(t, db, to), changes)
+
+	snap, err := db.newSnapshot()
+	require.NoError(err)
+	defer snap.release()
+	changes, err = snap.Diff(context.Background(), from)
+	require.NoError(err)
+	require.Equal([]KeyChange{{Key: []byte("d"), Value: maybe.Some([]byte("d"))}}, changes)
+
+	// [from] is invalidated once [to] is committed, but the snapshot still
+	// has the trie from before the commit.
+	require.NoError(to.CommitToDB(context.Background()))
+	_, err = from.Diff(context.Background(), to)
+	require.ErrorIs(err, ErrInvalid)
+	changes, err = snap.Diff(context.Background(), db)
+	require.NoError(err)
+	require.Equal(
+		[]KeyChange{expected[0], expected[1], expected[2], expected[3], expected[5]},
+		changes,
+	)
+
+	otherDB, err := getBasicDB()
+	require.NoError(err)
+	_, err = to.Diff(context.Background(), otherDB)
+	require.ErrorIs(err, ErrDiffOtherDatabase)
+}
+
+func Test_TrieView_Diff_Random(t *testing.T) {
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+
+	randomOps := func(numOps int) []database.BatchOp {
+		ops := make([]database.BatchOp, numOps)
+		for i := range ops {
+			key := make([]byte, 1+r.Intn(3))
+			_, _ = r.Read(key)
+			ops[i] = database.BatchOp{
+				Key:    key,
+				Delete: r.Intn(4) == 0,
+			}
+			if !ops[i].Delete {
+				ops[i].Value = make([]byte, 1+r.Intn(40))
+				_, _ = r.Read(ops[i].Value)
+			}
+		}
+		return ops
+	}
+
+	require := require.New(t)
+	for _, bf := range branchFactors {
+		db, err := getBasicDBWithBranchFactor(bf)
+		require.NoError(err)
+		view, err := db.NewView(context.Background(), ViewChanges{BatchOps: randomOps(500)})
+		require.NoError(err)
+		require.NoError(view.CommitToDB(context.Background()))
+
+		for i := 0; i < 10; i++ {
+			ancestor, err := db.NewView(context.Background(), ViewChanges{BatchOps: randomOps(50)})
+			require.NoError(err)
+			from, err := ancestor.NewView(context.Background(), ViewChanges{BatchOps: randomOps(r.Intn(50))})
+			require.NoError(err)
+			to, err := ancestor.NewView(context.Background(), ViewChanges{BatchOps: randomOps(r.Intn(50))})
+			require.NoError(err)
+
+			changes, err := from.Diff(context.Background(), to)
+			require.NoError(err)
+			require.Equal(iteratedDiff(t, from, to), changes)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
 
 	"golang.org/x/exp/slices"
 )
@@ -23,21 +24,46 @@ func (t *trieView) NewIteratorWithPrefix(prefix []byte) database.Iterator {
 	return t.NewIteratorWithStartAndPrefix(nil, prefix)
 }
 
+// NewIteratorWithStartAndPrefix returns an iterator over the key-value pairs
+// of this view whose keys are >= [start] and have [prefix].
+//
+// The iterator guarantees that:
+//   - Keys are returned in strictly increasing lexicographic order, each at
+//     most once.
+//   - The returned pairs are exactly those that the database would iterate
+//     over after this view, and all of its ancestors, were committed. That
+//     is, a key changed by several views has the value of the view closest to
+//     this one, and a key deleted by a view is only returned if a descendant
+//     of that view re-inserted it. Keys that are prefixes of each other are
+//     independent.
+//   - The changes of this view are read when the iterator is created. The
+//     state of ancestors is read as the iterator advances, which is safe
+//     because ancestors can't change without invalidating this view.
+//   - If this view is invalidated before iteration finishes, Next returns
+//     false and Error returns [ErrInvalid]. No pair read from a changed
+//     ancestor is returned.
+//   - The returned keys and values may be modified by the caller.
 func (t *trieView) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
 	var (
-		changes   = make([]KeyChange, 0, len(t.changes.values))
+		changes   = make([]KeyChange, 0, t.changes.numValueChanges())
 		startKey  = t.db.toKey(start)
 		prefixKey = t.db.toKey(prefix)
 	)
 
-	for key, change := range t.changes.values {
+	err := t.changes.forEachValueChange(func(key Key, change *change[maybe.Maybe[[]byte]]) error {
 		if len(start) > 0 && startKey.Greater(key) || !key.HasPrefix(prefixKey) {
-			continue
+			return nil
 		}
 		changes = append(changes, KeyChange{
+			// [key.Bytes()] shares memory with [key], so it must be cloned
+			// before it's returned.
 			Key:   key.Bytes(),
 			Value: change.after,
 		})
+		return nil
+	})
+	if err != nil {
+		return &database.IteratorError{Err: err}
 	}
 
 	// sort [changes] so they can be merged with the parent trie's state
@@ -47,7 +73,7 @@ func (t *trieView) NewIteratorWithStartAndPrefix(start, prefix []byte) database.
 
 	return &viewIterator{
 		view:          t,
-		parentIter:    t.parentTrie.NewIteratorWithStartAndPrefix(start, prefix),
+		parentIter:    t.getParentTrie().NewIteratorWithStartAndPrefix(start, prefix),
 		sortedChanges: changes,
 	}
 }
@@ -67,8 +93,7 @@ type viewIterator struct {
 }
 
 // Next moves the iterator to the next key/value pair. It returns whether the
-// iterator is exhausted. We must pay careful attention to set the proper values
-// based on if the in memory changes or the underlying db should be read next
+// iterator is exhausted.
 func (it *viewIterator) Next() bool {
 	switch {
 	case it.view.db.closed:
@@ -77,12 +102,31 @@ func (it *viewIterator) Next() bool {
 		it.value = nil
 		it.err = database.ErrClosed
 		return false
-	case it.view.invalidated:
+	case it.view.isInvalid():
 		it.key = nil
 		it.value = nil
 		it.err = ErrInvalid
 		return false
-	case !it.initialized:
+	}
+
+	hasNext := it.next()
+
+	// An ancestor may have changed while we were reading from it, in which
+	// case the pair we read may not be part of this view.
+	if it.view.isInvalid() {
+		it.key = nil
+		it.value = nil
+		it.err = ErrInvalid
+		return false
+	}
+	return hasNext
+}
+
+// next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted. We must pay careful attention to set the proper values
+// based on if the in memory changes or the underlying db should be read next
+func (it *viewIterator) next() bool {
+	if !it.initialized {
 		it.parentIterExhausted = !it.parentIter.Next()
 		it.initialized = true
 	}
@@ -104,8 +148,8 @@ func (it *viewIterator) Next() bool {
 			// If current change is not a deletion, return it.
 			// Otherwise go to next loop iteration.
 			if !nextKeyValue.Value.IsNothing() {
-				it.key = nextKeyValue.Key
-				it.value = nextKeyValue.Value.Value()
+				it.key = slices.Clone(nextKeyValue.Key)
+				it.value = slices.Clone(nextKeyValue.Value.Value())
 				return true
 			}
 		case len(it.sortedChanges) == 0:
@@ -128,7 +172,7 @@ func (it *viewIterator) Next() bool {
 				// If current change is not a deletion, return it.
 				// Otherwise, go to next loop iteration.
 				if memValue.HasValue() {
-					it.key = memKey
+					it.key = slices.Clone(memKey)
 					it.value = slices.Clone(memValue.Value())
 					return true
 				}
@@ -145,7 +189,7 @@ func (it *viewIterator) Next() bool {
 				it.parentIterExhausted = !it.parentIter.Next()
 
 				if memValue.HasValue() {
-					it.key = memKey
+					it.key = slices.Clone(memKey)
 					it.value = slices.Clone(memValue.Value())
 					return true
 				}
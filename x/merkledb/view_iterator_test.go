@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/utils/maybe"
@@ -271,3 +272,165 @@ func Test_TrieView_Iterator_Random(t *testing.T) {
 	iter.Release()
 	require.NoError(iter.Error())
 }
+
+// Test_TrieView_Iterator_Properties checks the guarantees documented on
+// [trieView.NewIteratorWithStartAndPrefix] by iterating over stacks of views
+// with random deletes, re-inserts, and keys that are prefixes of each other.
+func Test_TrieView_Iterator_Properties(t *testing.T) {
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	rand := rand.New(rand.NewSource(now)) // #nosec G404
+
+	var (
+		numTests      = 25
+		numViews      = 4
+		numOpsPerView = 50
+		maxKeyLen     = 3
+		maxValLen     = 4
+		// Keys are built from few distinct bytes so that views often change
+		// the same keys and keys are often prefixes of each other.
+		keyBytes = []byte{0x00, 0x01, 0x80, 0xFF}
+	)
+
+	randKey := func() []byte {
+		key := make([]byte, rand.Intn(maxKeyLen+1))
+		for i := range key {
+			key[i] = keyBytes[rand.Intn(len(keyBytes))]
+		}
+		return key
+	}
+	randValue := func() []byte {
+		value := make([]byte, 1+rand.Intn(maxValLen))
+		_, _ = rand.Read(value)
+		return value
+	}
+
+	for _, bf := range branchFactors {
+		for i := 0; i < numTests; i++ {
+			require := require.New(t)
+
+			db, err := getBasicDBWithBranchFactor(bf)
+			require.NoError(err)
+
+			// The expected state of the last view.
+			expected := make(map[string][]byte)
+			for j := 0; j < numOpsPerView; j++ {
+				key, value := randKey(), randValue()
+				require.NoError(db.Put(key, value))
+				expected[string(key)] = value
+			}
+
+			var (
+				views           = make([]*trieView, 0, numViews)
+				parent TrieView = db
+			)
+			for j := 0; j < numViews; j++ {
+				ops := make([]database.BatchOp, 0, numOpsPerView)
+				for k := 0; k < numOpsPerView; k++ {
+					key := randKey()
+					if rand.Intn(2) == 0 {
+						ops = append(ops, database.BatchOp{
+							Key:    key,
+							Delete: true,
+						})
+						delete(expected, string(key))
+						continue
+					}
+					value := randValue()
+					ops = append(ops, database.BatchOp{
+						Key:   key,
+						Value: value,
+					})
+					expected[string(key)] = value
+				}
+				parent, err = parent.NewView(context.Background(), ViewChanges{BatchOps: ops})
+				require.NoError(err)
+				views = append(views, parent.(*trieView))
+			}
+			view := views[len(views)-1]
+
+			start, prefix := randKey(), randKey()
+			expectedKeys := make([]string, 0, len(expected))
+			for key := range expected {
+				if key >= string(start) && bytes.HasPrefix([]byte(key), prefix) {
+					expectedKeys = append(expectedKeys, key)
+				}
+			}
+			sort.Strings(expectedKeys)
+
+			iterate := func(iter database.Iterator) []KeyValue {
+				defer iter.Release()
+
+				var kvs []KeyValue
+				for iter.Next() {
+					kvs = append(kvs, KeyValue{
+						Key:   slices.Clone(iter.Key()),
+						Value: slices.Clone(iter.Value()),
+					})
+
+					// Modifying the returned key and value must not change
+					// the view.
+					for k := range iter.Key() {
+						iter.Key()[k] = 0
+					}
+					for k := range iter.Value() {
+						iter.Value()[k] = 0
+					}
+				}
+				require.NoError(iter.Error())
+				return kvs
+			}
+
+			viewKVs := iterate(view.NewIteratorWithStartAndPrefix(start, prefix))
+			require.Len(viewKVs, len(expectedKeys))
+			for j, kv := range viewKVs {
+				require.Equal([]byte(expectedKeys[j]), kv.Key)
+				require.Equal(expected[expectedKeys[j]], kv.Value)
+			}
+
+			// Iterating again must return the same pairs.
+			require.Equal(viewKVs, iterate(view.NewIteratorWithStartAndPrefix(start, prefix)))
+
+			// The database must return the same pairs once the views are
+			// committed.
+			for _, view := range views {
+				require.NoError(view.CommitToDB(context.Background()))
+			}
+			require.Equal(viewKVs, iterate(db.NewIteratorWithStartAndPrefix(start, prefix)))
+		}
+	}
+}
+
+// Test_TrieView_Iterator_Invalidated tests that an iterator stops once its
+// view is invalidated.
+func Test_TrieView_Iterator_Invalidated(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	require.NoError(db.Put([]byte{0}, []byte{0}))
+	require.NoError(db.Put([]byte{1}, []byte{1}))
+
+	view1, err := db.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+	view2, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{2}, Value: []byte{2}},
+		},
+	})
+	require.NoError(err)
+
+	iter := view1.NewIterator()
+	defer iter.Release()
+
+	require.True(iter.Next())
+	require.Equal([]byte{0}, iter.Key())
+
+	// Committing a sibling invalidates [view1].
+	require.NoError(view2.CommitToDB(context.Background()))
+
+	require.False(iter.Next())
+	require.Nil(iter.Key())
+	require.Nil(iter.Value())
+	require.ErrorIs(iter.Error(), ErrInvalid)
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var (
+	ErrUnexpectedParentRoot = errors.New("parent root doesn't match the root the view was built on")
+
+	errNotTrieView = errors.New("not a view created by NewView")
+)
+
+// MarshalTrieView returns the bytes of [view], which must have been returned
+// by NewView and not committed. The bytes hold the root of [view]'s parent
+// and the value changes made by [view], in increasing key order.
+//
+// The node changes aren't included. They're recalculated from the value
+// changes by [UnmarshalTrieView], which produces the same nodes.
+func MarshalTrieView(ctx context.Context, view TrieView) ([]byte, error) {
+	t, ok := view.(*trieView)
+	if !ok {
+		return nil, errNotTrieView
+	}
+
+	t.commitLock.RLock()
+	defer t.commitLock.RUnlock()
+
+	if t.committed {
+		return nil, ErrCommitted
+	}
+
+	parentRoot, err := t.getParentTrie().GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The value changes of [t] aren't modified once [t] is created.
+	keys, err := t.changes.sortedValueKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, _ = buf.Write(parentRoot[:])
+	for _, key := range keys {
+		valueChange, _, err := t.changes.getValueChange(key)
+		if err != nil {
+			return nil, err
+		}
+		codec.encodeByteSlice(buf, key.Bytes())
+		codec.encodeMaybeByteSlice(buf, valueChange.after)
+	}
+
+	if t.isInvalid() {
+		return nil, ErrInvalid
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalTrieView returns a new view on top of [parent] with the value
+// changes of the view that [b] was marshalled from by [MarshalTrieView].
+// Returns [ErrUnexpectedParentRoot] if the root of [parent] isn't the root
+// that the marshalled view was built on.
+func UnmarshalTrieView(ctx context.Context, parent Trie, b []byte) (TrieView, error) {
+	var (
+		src             = bytes.NewReader(b)
+		parentRoot, err = codec.decodeID(src)
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parent.GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if root != parentRoot {
+		return nil, fmt.Errorf("%w: expected %s but got %s", ErrUnexpectedParentRoot, parentRoot, root)
+	}
+
+	var ops []database.BatchOp
+	for src.Len() > 0 {
+		key, err := codec.decodeByteSlice(src)
+		if err != nil {
+			return nil, err
+		}
+		value, err := codec.decodeMaybeByteSlice(src)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, database.BatchOp{
+			Key:    key,
+			Value:  value.Value(),
+			Delete: value.IsNothing(),
+		})
+	}
+	return parent.NewView(ctx, ViewChanges{
+		BatchOps:     ops,
+		ConsumeBytes: true,
+	})
+}
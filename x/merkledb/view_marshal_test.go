@@ -0,0 +1,155 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+)
+
+// randomViewChanges returns [n] random puts and deletes of keys, some of
+// which are in [keys].
+func randomViewChanges(r *rand.Rand, keys [][]byte, n int) ViewChanges {
+	changes := ViewChanges{
+		MapOps: make(map[string]maybe.Maybe[[]byte], n),
+	}
+	for i := 0; i < n; i++ {
+		key := make([]byte, r.Intn(8))
+		_, _ = r.Read(key)
+		if len(keys) > 0 && r.Intn(2) == 0 {
+			key = keys[r.Intn(len(keys))]
+		}
+
+		value := maybe.Nothing[[]byte]()
+		if r.Intn(4) != 0 {
+			value = maybe.Some(key[:r.Intn(len(key)+1)])
+		}
+		changes.MapOps[string(key)] = value
+	}
+	return changes
+}
+
+func Test_TrieView_Marshal(t *testing.T) {
+	for _, bf := range branchFactors {
+		t.Run(strconv.Itoa(int(bf)), func(t *testing.T) {
+			require := require.New(t)
+
+			now := time.Now().UnixNano()
+			t.Logf("seed: %d", now)
+			r := rand.New(rand.NewSource(now)) // #nosec G404
+
+			// Both databases have the same key-value pairs.
+			keys := make([][]byte, 100)
+			ops := make([]database.BatchOp, len(keys))
+			for i := range keys {
+				keys[i] = make([]byte, r.Intn(8)+1)
+				_, _ = r.Read(keys[i])
+				ops[i] = database.BatchOp{Key: keys[i], Value: keys[i]}
+			}
+			dbs := make([]*merkleDB, 2)
+			for i := range dbs {
+				db, err := getBasicDBWithBranchFactor(bf)
+				require.NoError(err)
+				view, err := db.NewView(context.Background(), ViewChanges{BatchOps: ops})
+				require.NoError(err)
+				require.NoError(view.CommitToDB(context.Background()))
+				dbs[i] = db
+			}
+
+			// Stack two views on the first database.
+			view1, err := dbs[0].NewView(context.Background(), randomViewChanges(r, keys, 50))
+			require.NoError(err)
+			view2, err := view1.NewView(context.Background(), randomViewChanges(r, keys, 50))
+			require.NoError(err)
+
+			view1Bytes, err := MarshalTrieView(context.Background(), view1)
+			require.NoError(err)
+			view2Bytes, err := MarshalTrieView(context.Background(), view2)
+			require.NoError(err)
+
+			// Rebuild them on the second database.
+			restoredView1, err := UnmarshalTrieView(context.Background(), dbs[1], view1Bytes)
+			require.NoError(err)
+			restoredView2, err := UnmarshalTrieView(context.Background(), restoredView1, view2Bytes)
+			require.NoError(err)
+
+			for _, views := range [][2]TrieView{{view1, restoredView1}, {view2, restoredView2}} {
+				expectedRoot, err := views[0].GetMerkleRoot(context.Background())
+				require.NoError(err)
+				root, err := views[1].GetMerkleRoot(context.Background())
+				require.NoError(err)
+				require.Equal(expectedRoot, root)
+
+				for _, key := range keys {
+					expectedValue, expectedErr := views[0].GetValue(context.Background(), key)
+					value, err := views[1].GetValue(context.Background(), key)
+					require.Equal(expectedErr, err)
+					// Empty values may be restored as nil.
+					require.True(bytes.Equal(expectedValue, value))
+				}
+			}
+
+			// Marshalling is deterministic.
+			restoredView2Bytes, err := MarshalTrieView(context.Background(), restoredView2)
+			require.NoError(err)
+			require.Equal(view2Bytes, restoredView2Bytes)
+
+			// The views can only be rebuilt on the roots they were built on.
+			_, err = UnmarshalTrieView(context.Background(), dbs[1], view2Bytes)
+			require.ErrorIs(err, ErrUnexpectedParentRoot)
+		})
+	}
+}
+
+func Test_TrieView_Marshal_Errors(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	_, err = MarshalTrieView(context.Background(), db)
+	require.ErrorIs(err, errNotTrieView)
+
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{{Key: []byte("key"), Value: []byte("value")}},
+	})
+	require.NoError(err)
+	viewBytes, err := MarshalTrieView(context.Background(), view)
+	require.NoError(err)
+
+	// The bytes are truncated in the middle of the value.
+	_, err = UnmarshalTrieView(context.Background(), db, viewBytes[:len(viewBytes)-1])
+	require.ErrorIs(err, io.ErrUnexpectedEOF)
+
+	// A sibling view invalidates [view] when it's committed.
+	sibling, err := db.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+	require.NoError(sibling.CommitToDB(context.Background()))
+	_, err = MarshalTrieView(context.Background(), view)
+	require.ErrorIs(err, ErrInvalid)
+
+	view, err = db.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+	require.NoError(view.CommitToDB(context.Background()))
+	_, err = MarshalTrieView(context.Background(), view)
+	require.ErrorIs(err, ErrCommitted)
+
+	// Rebuilding on an unexpected root fails before any changes are made.
+	otherDB, err := newDB(context.Background(), memdb.New(), newDefaultConfig())
+	require.NoError(err)
+	require.NoError(otherDB.Put([]byte("other"), []byte("other")))
+	_, err = UnmarshalTrieView(context.Background(), otherDB, viewBytes)
+	require.ErrorIs(err, ErrUnexpectedParentRoot)
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var ErrRebaseOtherDatabase = errors.New("can't rebase a view onto a trie of a different database")
+
+// RebaseOnto replays the value changes recorded in [t.changes] onto
+// [newParent]. It doesn't read [t]'s parent, so it works even if [t] was
+// invalidated.
+// The value changes and [t.deletedPrefixes] aren't modified after [t] is
+// created, so views can be rebased concurrently.
+func (t *trieView) RebaseOnto(ctx context.Context, newParent TrieView) (TrieView, error) {
+	ctx, span := t.db.infoTracer.Start(ctx, "MerkleDB.trieview.RebaseOnto")
+	defer span.End()
+
+	if t.readOnly {
+		return nil, errViewReadOnly
+	}
+	if t.isReleased() {
+		return nil, ErrViewReleased
+	}
+	if err := t.db.checkRebaseParent(newParent); err != nil {
+		return nil, err
+	}
+
+	// Inserting the keys in increasing order lets each insertion reuse the
+	// path to the previously inserted key.
+	keys, err := t.changes.sortedValueKeys()
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]database.BatchOp, len(keys))
+	for i, key := range keys {
+		valueChange, _, err := t.changes.getValueChange(key)
+		if err != nil {
+			return nil, err
+		}
+		after := valueChange.after
+		ops[i] = database.BatchOp{
+			Key:    key.Bytes(),
+			Value:  after.Value(),
+			Delete: after.IsNothing(),
+		}
+	}
+	return newParent.NewView(ctx, ViewChanges{
+		BatchOps:       ops,
+		DeletePrefixes: t.deletedPrefixes,
+		// The keys and values of [t] are never modified, so they can be shared
+		// with the new view.
+		ConsumeBytes: true,
+	})
+}
+
+// checkRebaseParent returns an error if a view of [db] can't be rebased onto
+// [newParent].
+func (db *merkleDB) checkRebaseParent(newParent TrieView) error {
+	switch newParent := newParent.(type) {
+	case *trieView:
+		if newParent.db != db {
+			return ErrRebaseOtherDatabase
+		}
+	case *merkleDB:
+		if newParent != db {
+			return ErrRebaseOtherDatabase
+		}
+	case *snapshot:
+		if newParent.db != db {
+			return ErrRebaseOtherDatabase
+		}
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedTrie, newParent)
+	}
+	return nil
+}
+
+// RebaseOnto returns a view of [newParent] without changes, since the
+// database has no changes to replay.
+// This exists to satisfy the TrieView interface.
+func (db *merkleDB) RebaseOnto(ctx context.Context, newParent TrieView) (TrieView, error) {
+	if err := db.checkRebaseParent(newParent); err != nil {
+		return nil, err
+	}
+	return newParent.NewView(ctx, ViewChanges{})
+}
+
+func (*snapshot) RebaseOnto(context.Context, TrieView) (TrieView, error) {
+	return nil, errSnapshotReadOnly
+}
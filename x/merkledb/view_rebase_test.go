@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestTrieViewRebaseOnto(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	require.NoError(db.Put([]byte("prefix/existing"), []byte("value")))
+	require.NoError(db.Put([]byte("deleted"), []byte("value")))
+
+	committed, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("committed"), Value: []byte("value")},
+			{Key: []byte("prefix/new"), Value: []byte("value")},
+		},
+	})
+	require.NoError(err)
+	sibling, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte("rebased"), Value: []byte("value")},
+			{Key: []byte("deleted"), Delete: true},
+		},
+		DeletePrefixes: [][]byte{[]byte("prefix/")},
+	})
+	require.NoError(err)
+
+	// Committing [committed] invalidates [sibling].
+	require.NoError(committed.CommitToDB(context.Background()))
+	_, err = sibling.GetValue(context.Background(), []byte("rebased"))
+	require.ErrorIs(err, ErrInvalid)
+
+	rebased, err := sibling.RebaseOnto(context.Background(), db)
+	require.NoError(err)
+	require.NoError(rebased.CommitToDB(context.Background()))
+
+	// The changes of [sibling] are applied on top of [committed], and the
+	// prefix deletion also deletes the key that [committed] added.
+	expected := newExportTestDB(t)
+	require.NoError(expected.Put([]byte("committed"), []byte("value")))
+	require.NoError(expected.Put([]byte("rebased"), []byte("value")))
+	requireKeyValues(t, expected, db)
+}
+
+func TestTrieViewRebaseOntoView(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{1}, Value: []byte{1}},
+		},
+	})
+	require.NoError(err)
+	newParent, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{1}, Value: []byte{2}},
+			{Key: []byte{2}, Value: []byte{2}},
+		},
+	})
+	require.NoError(err)
+
+	rebased, err := view.RebaseOnto(context.Background(), newParent)
+	require.NoError(err)
+
+	// The changes of [view] take precedence over those of [newParent].
+	values, errs := rebased.GetValues(context.Background(), [][]byte{{1}, {2}})
+	require.Equal([]error{nil, nil}, errs)
+	require.Equal([][]byte{{1}, {2}}, values)
+
+	// [view] is unchanged.
+	_, err = view.GetValue(context.Background(), []byte{2})
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestTrieViewRebaseOntoConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{1}, Value: []byte{1}},
+			{Key: []byte{2}, Value: []byte{2}},
+		},
+	})
+	require.NoError(err)
+	require.NoError(db.Put([]byte{3}, []byte{3}))
+
+	var (
+		wg    sync.WaitGroup
+		roots = make([]ids.ID, 8)
+		errs  = make([]error, len(roots))
+	)
+	for i := range roots {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			rebased, err := view.RebaseOnto(context.Background(), db)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			roots[i], errs[i] = rebased.GetMerkleRoot(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range roots {
+		require.NoError(errs[i])
+		require.Equal(roots[0], roots[i])
+	}
+}
+
+func TestTrieViewRebaseOntoInvalid(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+	otherDB, err := getBasicDB()
+	require.NoError(err)
+
+	view, err := db.NewView(context.Background(), ViewChanges{})
+	require.NoError(err)
+
+	_, err = view.RebaseOnto(context.Background(), otherDB)
+	require.ErrorIs(err, ErrRebaseOtherDatabase)
+
+	view.Release()
+	_, err = view.RebaseOnto(context.Background(), db)
+	require.ErrorIs(err, ErrViewReleased)
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+var ErrViewReleased = errors.New("view has been released")
+
+// viewLeakDetector reports the view that holds it if the view is garbage
+// collected without having been released or committed.
+//
+// The finalizer is set on the detector rather than on the view, because views
+// reference their parents and children, and the garbage collector doesn't
+// collect cycles of objects with finalizers.
+type viewLeakDetector struct {
+	// True once the view has been released or committed.
+	done    utils.Atomic[bool]
+	metrics merkleMetrics
+	onLeak  func()
+}
+
+// detectViewLeak reports [view] if it's garbage collected without having been
+// released or committed. See [Config.OnViewLeak].
+func (db *merkleDB) detectViewLeak(view *trieView) {
+	detector := &viewLeakDetector{
+		metrics: db.metrics,
+		onLeak:  db.onViewLeak,
+	}
+	runtime.SetFinalizer(detector, (*viewLeakDetector).finalize)
+	view.leakDetector = detector
+}
+
+func (d *viewLeakDetector) finalize() {
+	if d.done.Get() {
+		return
+	}
+	d.metrics.ViewLeaked()
+	if d.onLeak != nil {
+		d.onLeak()
+	}
+}
+
+// Release implements [TrieView].
+// Assumes [t.validityTrackingLock] isn't held.
+func (t *trieView) Release() {
+	t.validityTrackingLock.Lock()
+	alreadyReleased := t.released
+	t.released = true
+	hasChildren := len(t.childViews) > 0
+	t.validityTrackingLock.Unlock()
+
+	if alreadyReleased {
+		return
+	}
+	if t.leakDetector != nil {
+		t.leakDetector.done.Set(true)
+	}
+	if !hasChildren {
+		t.collect()
+	}
+}
+
+// releaseSpill releases this view's reference to the value changes it
+// spilled to disk. It has no effect after the first call.
+func (t *trieView) releaseSpill() {
+	t.releaseSpillOnce.Do(t.changes.releaseSpill)
+}
+
+// Assumes [t.validityTrackingLock] isn't held.
+func (t *trieView) isReleased() bool {
+	t.validityTrackingLock.RLock()
+	defer t.validityTrackingLock.RUnlock()
+
+	return t.released
+}
+
+// collect stops tracking this view, which is released and has no children,
+// so that it can be garbage collected before the next commit. If this view
+// was the last child of a released parent view, the parent is collected too.
+// Assumes no locks are held.
+func (t *trieView) collect() {
+	// Nothing reads the spilled value changes of this view anymore. Holding
+	// [t.commitLock] ensures that a commit in progress finishes first.
+	t.commitLock.Lock()
+	t.releaseSpill()
+	t.commitLock.Unlock()
+
+	for {
+		parent := t.getParentTrie()
+		var removed bool
+		switch parent := parent.(type) {
+		case *merkleDB:
+			removed = parent.removeChildView(t)
+		case *trieView:
+			var collectParent bool
+			removed, collectParent = parent.removeChildView(t)
+			if collectParent {
+				parent.collect()
+			}
+		default:
+			// Views of snapshots and historical roots aren't tracked.
+			return
+		}
+
+		// If this view wasn't a child of [parent], either it was invalidated,
+		// which already stopped tracking it, or it was moved onto the
+		// database by a commit since its parent was read.
+		if removed || parent == t.getParentTrie() {
+			return
+		}
+	}
+}
+
+// removeChildView stops tracking [child]. Returns false if [child] isn't a
+// child of this view. [collectable] is true if this view is released and
+// [child] was its last child.
+// Assumes [t.validityTrackingLock] isn't held.
+func (t *trieView) removeChildView(child *trieView) (removed bool, collectable bool) {
+	t.validityTrackingLock.Lock()
+	defer t.validityTrackingLock.Unlock()
+
+	t.childViews, removed = removeView(t.childViews, child)
+	return removed, removed && t.released && len(t.childViews) == 0
+}
+
+// removeChildView stops tracking [child]. Returns false if [child] isn't a
+// child of the database.
+// Assumes [db.lock] isn't held.
+func (db *merkleDB) removeChildView(child *trieView) bool {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var removed bool
+	db.childViews, removed = removeView(db.childViews, child)
+	return removed
+}
+
+// Release is a no-op for db since it is never tracked.
+// This exists to satisfy the TrieView interface.
+func (*merkleDB) Release() {}
+
+// Release is a no-op for snapshots, which are released by whoever took them.
+// This exists to satisfy the TrieView interface.
+func (*snapshot) Release() {}
+
+// removeView returns [views] without [view], and true if [view] was in
+// [views]. The order of [views] isn't preserved.
+func removeView(views []*trieView, view *trieView) ([]*trieView, bool) {
+	for i, v := range views {
+		if v != view {
+			continue
+		}
+		last := len(views) - 1
+		views[i] = views[last]
+		// Don't keep [view] alive through the backing array.
+		views[last] = nil
+		return views[:last], true
+	}
+	return views, false
+}
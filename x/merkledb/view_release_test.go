@@ -0,0 +1,139 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package merkledb
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func newReleaseTestView(t *testing.T, parent TrieView, key byte) *trieView {
+	view, err := parent.NewView(context.Background(), ViewChanges{
+		BatchOps: []database.BatchOp{
+			{Key: []byte{key}, Value: []byte{key}},
+		},
+	})
+	require.NoError(t, err)
+	return view.(*trieView)
+}
+
+func TestTrieViewRelease(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	abandoned := newReleaseTestView(t, db, 1)
+	sibling := newReleaseTestView(t, db, 2)
+	require.Len(db.childViews, 2)
+
+	// A released view without children is no longer tracked.
+	abandoned.Release()
+	require.Equal([]*trieView{sibling}, db.childViews)
+
+	// Releasing twice is a no-op.
+	abandoned.Release()
+	require.Equal([]*trieView{sibling}, db.childViews)
+
+	_, err = abandoned.NewView(context.Background(), ViewChanges{})
+	require.ErrorIs(err, ErrViewReleased)
+	require.ErrorIs(abandoned.CommitToDB(context.Background()), ErrViewReleased)
+}
+
+func TestTrieViewReleaseWithChildren(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	parent := newReleaseTestView(t, db, 1)
+	child := newReleaseTestView(t, parent, 2)
+	grandchild := newReleaseTestView(t, child, 3)
+
+	// [parent] is still tracked, because its child isn't released.
+	parent.Release()
+	require.Equal([]*trieView{parent}, db.childViews)
+
+	// The descendants of a released view can still be read.
+	value, err := grandchild.GetValue(context.Background(), []byte{1})
+	require.NoError(err)
+	require.Equal([]byte{1}, value)
+
+	// Releasing [grandchild] doesn't collect [child], which isn't released.
+	grandchild.Release()
+	require.Empty(child.childViews)
+	require.Equal([]*trieView{child}, parent.childViews)
+
+	// Releasing [child] collects the whole subtree.
+	child.Release()
+	require.Empty(parent.childViews)
+	require.Empty(db.childViews)
+}
+
+func TestTrieViewReleaseAfterReparent(t *testing.T) {
+	require := require.New(t)
+
+	db, err := getBasicDB()
+	require.NoError(err)
+
+	parent := newReleaseTestView(t, db, 1)
+	child := newReleaseTestView(t, parent, 2)
+	abandoned := newReleaseTestView(t, db, 3)
+
+	// Committing [parent] moves [child] onto the database and invalidates
+	// [abandoned].
+	require.NoError(parent.CommitToDB(context.Background()))
+	require.ElementsMatch([]*trieView{parent, child}, db.childViews)
+
+	child.Release()
+	parent.Release()
+	require.Empty(db.childViews)
+
+	// Releasing an invalidated view is a no-op.
+	abandoned.Release()
+	require.Empty(db.childViews)
+}
+
+func TestTrieViewLeak(t *testing.T) {
+	require := require.New(t)
+
+	metrics := &mockMetrics{}
+	leaks := make(chan struct{}, 1)
+	config := newDefaultConfig()
+	config.OnViewLeak = func() {
+		leaks <- struct{}{}
+	}
+	db, err := newDatabase(context.Background(), memdb.New(), config, metrics)
+	require.NoError(err)
+
+	// Released and committed views aren't leaked.
+	newReleaseTestView(t, db, 1).Release()
+	require.NoError(newReleaseTestView(t, db, 2).CommitToDB(context.Background()))
+
+	// This view is dropped without being released, so it's leaked once the
+	// next commit invalidates it.
+	_ = newReleaseTestView(t, db, 3)
+	require.NoError(db.Put([]byte{4}, []byte{4}))
+
+	require.Eventually(func() bool {
+		runtime.GC()
+		select {
+		case <-leaks:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	require.Equal(int64(1), metrics.viewsLeaked)
+}
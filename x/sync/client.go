@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/validate"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 
@@ -35,6 +36,9 @@ const (
 var (
 	_ Client = (*client)(nil)
 
+	ErrNoNetworkClientProvided = errors.New("network client is a required field of the sync client config")
+	ErrNoMetricsProvided       = errors.New("metrics is a required field of the sync client config")
+
 	errInvalidRangeProof             = errors.New("failed to verify range proof")
 	errTooManyKeys                   = errors.New("response contains more than requested keys")
 	errTooManyBytes                  = errors.New("response contains more than requested bytes")
@@ -85,8 +89,18 @@ type ClientConfig struct {
 	BranchFactor        merkledb.BranchFactor
 }
 
+// Verify returns an error describing every invalid field of the config.
+func (c *ClientConfig) Verify() error {
+	v := validate.New("sync client")
+	v.Check(c.NetworkClient != nil, ErrNoNetworkClientProvided)
+	v.Check(c.Log != nil, ErrNoLogProvided)
+	v.Check(c.Metrics != nil, ErrNoMetricsProvided)
+	v.Add(c.BranchFactor.Valid())
+	return v.Err()
+}
+
 func NewClient(config *ClientConfig) (Client, error) {
-	if err := config.BranchFactor.Valid(); err != nil {
+	if err := config.Verify(); err != nil {
 		return nil, err
 	}
 	return &client{
@@ -113,7 +127,8 @@ func (c *client) GetChangeProof(
 		}
 
 		var changeProofResp pb.SyncGetChangeProofResponse
-		if err := proto.Unmarshal(responseBytes, &changeProofResp); err != nil {
+		maxProofVersion := merkledb.NegotiateProofVersion(req.MaxProofVersion)
+		if _, err := merkledb.UnmarshalProof(responseBytes, maxProofVersion, &changeProofResp); err != nil {
 			return nil, err
 		}
 
@@ -249,7 +264,8 @@ func (c *client) GetRangeProof(
 		}
 
 		var rangeProofProto pb.RangeProof
-		if err := proto.Unmarshal(responseBytes, &rangeProofProto); err != nil {
+		maxProofVersion := merkledb.NegotiateProofVersion(req.MaxProofVersion)
+		if _, err := merkledb.UnmarshalProof(responseBytes, maxProofVersion, &rangeProofProto); err != nil {
 			return nil, err
 		}
 
@@ -310,6 +326,10 @@ func getAndParse[T any](
 			if response, err = parseFn(ctx, responseBytes); err == nil {
 				return response, nil
 			}
+			// Don't penalize the peer if verification was interrupted.
+			if ctx.Err() == nil {
+				client.networkClient.TrackInvalidProof(nodeID)
+			}
 		}
 
 		if errors.Is(err, errAppSendFailed) {
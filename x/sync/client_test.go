@@ -68,7 +68,7 @@ func sendRangeProofRequest(
 		sender = common.NewMockSender(ctrl)
 
 		// Serves the range proof.
-		server = NewNetworkServer(sender, serverDB, logging.NoLog{})
+		server = NewNetworkServer(sender, serverDB, logging.NoLog{}, NotificationConfig{})
 
 		clientNodeID, serverNodeID = ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
 
@@ -125,6 +125,9 @@ func sendRangeProofRequest(
 	// Handle bandwidth tracking calls from client.
 	networkClient.EXPECT().TrackBandwidth(gomock.Any(), gomock.Any()).AnyTimes()
 
+	// Handle invalid proof tracking calls from client.
+	networkClient.EXPECT().TrackInvalidProof(serverNodeID).AnyTimes()
+
 	// The server should expect to "send" a response to the client.
 	sender.EXPECT().SendAppResponse(
 		gomock.Any(), // ctx
@@ -387,7 +390,7 @@ func sendChangeProofRequest(
 		sender = common.NewMockSender(ctrl)
 
 		// Serves the change proof.
-		server = NewNetworkServer(sender, serverDB, logging.NoLog{})
+		server = NewNetworkServer(sender, serverDB, logging.NoLog{}, NotificationConfig{})
 
 		clientNodeID, serverNodeID = ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
 
@@ -441,6 +444,9 @@ func sendChangeProofRequest(
 		},
 	).AnyTimes()
 
+	// Handle invalid proof tracking calls from client.
+	networkClient.EXPECT().TrackInvalidProof(serverNodeID).AnyTimes()
+
 	// Expect server (serverDB) to send app response to client (clientDB)
 	sender.EXPECT().SendAppResponse(
 		gomock.Any(), // ctx
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/exp/slices"
@@ -16,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/validate"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 
 	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
@@ -102,7 +105,13 @@ type Manager struct {
 	cancelCtx context.CancelFunc
 
 	// Set to true when StartSyncing is called.
-	syncing      bool
+	syncing bool
+	// Set when StartSyncing is called.
+	// [workLock] must be held when accessing [startTime].
+	startTime time.Time
+	// The total size of the keys and values in the proofs that were applied.
+	bytesVerified atomic.Uint64
+
 	closeOnce    sync.Once
 	branchFactor merkledb.BranchFactor
 }
@@ -114,20 +123,24 @@ type ManagerConfig struct {
 	Log                   logging.Logger
 	TargetRoot            ids.ID
 	BranchFactor          merkledb.BranchFactor
+	// If non-nil, the progress of the sync includes the peers of
+	// [NetworkClient]. Should be the network client used by [Client].
+	NetworkClient NetworkClient
+}
+
+// Verify returns an error describing every invalid field of the config.
+func (c *ManagerConfig) Verify() error {
+	v := validate.New("sync manager")
+	v.Check(c.Client != nil, ErrNoClientProvided)
+	v.Check(c.DB != nil, ErrNoDatabaseProvided)
+	v.Check(c.Log != nil, ErrNoLogProvided)
+	v.Check(c.SimultaneousWorkLimit != 0, ErrZeroWorkLimit)
+	v.Add(c.BranchFactor.Valid())
+	return v.Err()
 }
 
 func NewManager(config ManagerConfig) (*Manager, error) {
-	switch {
-	case config.Client == nil:
-		return nil, ErrNoClientProvided
-	case config.DB == nil:
-		return nil, ErrNoDatabaseProvided
-	case config.Log == nil:
-		return nil, ErrNoLogProvided
-	case config.SimultaneousWorkLimit == 0:
-		return nil, ErrZeroWorkLimit
-	}
-	if err := config.BranchFactor.Valid(); err != nil {
+	if err := config.Verify(); err != nil {
 		return nil, err
 	}
 
@@ -158,6 +171,7 @@ func (m *Manager) Start(ctx context.Context) error {
 	m.unprocessedWork.Insert(newWorkItem(ids.Empty, maybe.Nothing[[]byte](), maybe.Nothing[[]byte](), lowPriority))
 
 	m.syncing = true
+	m.startTime = time.Now()
 	ctx, m.cancelCtx = context.WithCancel(ctx)
 
 	go m.sync(ctx)
@@ -276,8 +290,9 @@ func (m *Manager) getAndApplyChangeProof(ctx context.Context, work *workItem) {
 				Value:     work.end.Value(),
 				IsNothing: work.end.IsNothing(),
 			},
-			KeyLimit:   defaultRequestKeyLimit,
-			BytesLimit: defaultRequestByteSizeLimit,
+			KeyLimit:        defaultRequestKeyLimit,
+			BytesLimit:      defaultRequestByteSizeLimit,
+			MaxProofVersion: uint32(merkledb.MaxProofVersion),
 		},
 		m.config.DB,
 	)
@@ -303,6 +318,7 @@ func (m *Manager) getAndApplyChangeProof(ctx context.Context, work *workItem) {
 				m.setError(err)
 				return
 			}
+			m.bytesVerified.Add(changeProofSize(changeProof))
 			largestHandledKey = maybe.Some(changeProof.KeyChanges[len(changeProof.KeyChanges)-1].Key)
 		}
 
@@ -319,6 +335,7 @@ func (m *Manager) getAndApplyChangeProof(ctx context.Context, work *workItem) {
 			m.setError(err)
 			return
 		}
+		m.bytesVerified.Add(rangeProofSize(rangeProof))
 		largestHandledKey = maybe.Some(rangeProof.KeyValues[len(rangeProof.KeyValues)-1].Key)
 	}
 
@@ -340,8 +357,9 @@ func (m *Manager) getAndApplyRangeProof(ctx context.Context, work *workItem) {
 				Value:     work.end.Value(),
 				IsNothing: work.end.IsNothing(),
 			},
-			KeyLimit:   defaultRequestKeyLimit,
-			BytesLimit: defaultRequestByteSizeLimit,
+			KeyLimit:        defaultRequestKeyLimit,
+			BytesLimit:      defaultRequestByteSizeLimit,
+			MaxProofVersion: uint32(merkledb.MaxProofVersion),
 		},
 	)
 	if err != nil {
@@ -363,6 +381,7 @@ func (m *Manager) getAndApplyRangeProof(ctx context.Context, work *workItem) {
 		m.setError(err)
 		return
 	}
+	m.bytesVerified.Add(rangeProofSize(proof))
 
 	if len(proof.KeyValues) > 0 {
 		largestHandledKey = maybe.Some(proof.KeyValues[len(proof.KeyValues)-1].Key)
@@ -577,6 +596,9 @@ func (m *Manager) UpdateSyncTarget(syncTargetRoot ids.ID) error {
 
 	m.config.Log.Debug("updated sync target", zap.Stringer("target", syncTargetRoot))
 	m.config.TargetRoot = syncTargetRoot
+	// No ranges are synced to the new target, so the progress is measured from
+	// now.
+	m.startTime = time.Now()
 
 	// move all completed ranges into the work heap with high priority
 	shouldSignal := m.processedWork.Len() > 0
@@ -36,6 +36,20 @@ func (m *MockNetworkClient) EXPECT() *MockNetworkClientMockRecorder {
 	return m.recorder
 }
 
+// AppGossip mocks base method.
+func (m *MockNetworkClient) AppGossip(arg0 context.Context, arg1 ids.NodeID, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppGossip", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppGossip indicates an expected call of AppGossip.
+func (mr *MockNetworkClientMockRecorder) AppGossip(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppGossip", reflect.TypeOf((*MockNetworkClient)(nil).AppGossip), arg0, arg1, arg2)
+}
+
 // AppRequestFailed mocks base method.
 func (m *MockNetworkClient) AppRequestFailed(arg0 context.Context, arg1 ids.NodeID, arg2 uint32) error {
 	m.ctrl.T.Helper()
@@ -92,6 +106,20 @@ func (mr *MockNetworkClientMockRecorder) Disconnected(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnected", reflect.TypeOf((*MockNetworkClient)(nil).Disconnected), arg0, arg1)
 }
 
+// PeerStats mocks base method.
+func (m *MockNetworkClient) PeerStats() PeerStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PeerStats")
+	ret0, _ := ret[0].(PeerStats)
+	return ret0
+}
+
+// PeerStats indicates an expected call of PeerStats.
+func (mr *MockNetworkClientMockRecorder) PeerStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerStats", reflect.TypeOf((*MockNetworkClient)(nil).PeerStats))
+}
+
 // Request mocks base method.
 func (m *MockNetworkClient) Request(ctx context.Context, nodeID ids.NodeID, request []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +151,20 @@ func (mr *MockNetworkClientMockRecorder) RequestAny(ctx, minVersion, request int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestAny", reflect.TypeOf((*MockNetworkClient)(nil).RequestAny), ctx, minVersion, request)
 }
 
+// Subscribe mocks base method.
+func (m *MockNetworkClient) Subscribe(ctx context.Context, nodeID ids.NodeID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockNetworkClientMockRecorder) Subscribe(ctx, nodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockNetworkClient)(nil).Subscribe), ctx, nodeID)
+}
+
 // TrackBandwidth mocks base method.
 func (m *MockNetworkClient) TrackBandwidth(nodeID ids.NodeID, bandwidth float64) {
 	m.ctrl.T.Helper()
@@ -134,3 +176,29 @@ func (mr *MockNetworkClientMockRecorder) TrackBandwidth(nodeID, bandwidth interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackBandwidth", reflect.TypeOf((*MockNetworkClient)(nil).TrackBandwidth), nodeID, bandwidth)
 }
+
+// TrackInvalidProof mocks base method.
+func (m *MockNetworkClient) TrackInvalidProof(nodeID ids.NodeID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackInvalidProof", nodeID)
+}
+
+// TrackInvalidProof indicates an expected call of TrackInvalidProof.
+func (mr *MockNetworkClientMockRecorder) TrackInvalidProof(nodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackInvalidProof", reflect.TypeOf((*MockNetworkClient)(nil).TrackInvalidProof), nodeID)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockNetworkClient) Unsubscribe(ctx context.Context, nodeID ids.NodeID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockNetworkClientMockRecorder) Unsubscribe(ctx, nodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockNetworkClient)(nil).Unsubscribe), ctx, nodeID)
+}
@@ -16,11 +16,17 @@ import (
 
 	"golang.org/x/sync/semaphore"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/version"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
 )
 
 // Minimum amount of time to handle a request
@@ -32,6 +38,8 @@ var (
 	errAcquiringSemaphore = errors.New("error acquiring semaphore")
 	errRequestFailed      = errors.New("request failed")
 	errAppSendFailed      = errors.New("failed to send app message")
+
+	peerScorePrefix = []byte("peerScore")
 )
 
 // NetworkClient defines ability to send request / response through the Network
@@ -55,6 +63,23 @@ type NetworkClient interface {
 		request []byte,
 	) ([]byte, error)
 
+	// TrackInvalidProof records that [nodeID] responded with a proof that
+	// failed to parse or verify, so that the peer is less likely to be
+	// sent future requests.
+	TrackInvalidProof(nodeID ids.NodeID)
+
+	// PeerStats returns a summary of the peers that requests can be sent to.
+	PeerStats() PeerStats
+
+	// Subscribe asks [nodeID] to push a notification to this node when the
+	// root of its database advances.
+	// Subscriptions expire, so Subscribe should be called again while
+	// notifications are wanted.
+	Subscribe(ctx context.Context, nodeID ids.NodeID) error
+
+	// Unsubscribe asks [nodeID] to stop pushing notifications to this node.
+	Unsubscribe(ctx context.Context, nodeID ids.NodeID) error
+
 	// The following declarations allow this interface to be embedded in the VM
 	// to handle incoming responses from peers.
 
@@ -66,12 +91,19 @@ type NetworkClient interface {
 	// returned from this function as fatal.
 	AppRequestFailed(context.Context, ids.NodeID, uint32) error
 
+	// Passes the root advanced notifications of subscribed peers to the
+	// handler of the client.
+	// Always returns nil because the engine considers errors
+	// returned from this function as fatal.
+	AppGossip(context.Context, ids.NodeID, []byte) error
+
 	// Adds the given [nodeID] to the peer
 	// list so that it can receive messages.
 	// If [nodeID] is this node's ID, this is a no-op.
 	Connected(context.Context, ids.NodeID, *version.Application) error
 
-	// Removes given [nodeID] from the peer list.
+	// Removes given [nodeID] from the peer list and from the subscribed
+	// peers.
 	Disconnected(context.Context, ids.NodeID) error
 }
 
@@ -90,17 +122,33 @@ type networkClient struct {
 	peers *peerTracker
 	// For sending messages to peers
 	appSender common.AppSender
+	// Peers that this node subscribed to
+	subscribed set.Set[ids.NodeID]
+	// Called with the notifications of [subscribed] peers. May be nil.
+	onRootAdvanced RootAdvancedHandler
 }
 
+// NewNetworkClient returns a NetworkClient that persists the performance of
+// its peers under a prefix of [db], so that previously good peers are
+// preferred after a restart.
+// [onRootAdvanced] is called with the notifications of the peers subscribed
+// to, and may be nil.
 func NewNetworkClient(
 	appSender common.AppSender,
 	myNodeID ids.NodeID,
 	maxActiveRequests int64,
+	db database.Database,
 	log logging.Logger,
+	onRootAdvanced RootAdvancedHandler,
 	metricsNamespace string,
 	registerer prometheus.Registerer,
 ) (NetworkClient, error) {
-	peerTracker, err := newPeerTracker(log, metricsNamespace, registerer)
+	peerTracker, err := newPeerTracker(
+		prefixdb.New(peerScorePrefix, db),
+		log,
+		metricsNamespace,
+		registerer,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create peer tracker: %w", err)
 	}
@@ -112,6 +160,8 @@ func NewNetworkClient(
 		activeRequests:             semaphore.NewWeighted(maxActiveRequests),
 		peers:                      peerTracker,
 		log:                        log,
+		subscribed:                 set.Set[ids.NodeID]{},
+		onRootAdvanced:             onRootAdvanced,
 	}, nil
 }
 
@@ -281,15 +331,16 @@ func (c *networkClient) request(
 		c.peers.TrackBandwidth(nodeID, 0)
 		return nil, ctx.Err()
 	case response = <-handler.responseChan:
-		elapsedSeconds := time.Since(startTime).Seconds()
-		bandwidth := float64(len(response))/elapsedSeconds + epsilon
-		c.peers.TrackBandwidth(nodeID, bandwidth)
 	}
 	if handler.failed {
 		c.peers.TrackBandwidth(nodeID, 0)
 		return nil, errRequestFailed
 	}
 
+	elapsedSeconds := time.Since(startTime).Seconds()
+	bandwidth := float64(len(response))/elapsedSeconds + epsilon
+	c.peers.TrackBandwidth(nodeID, bandwidth)
+
 	c.log.Debug("received response from peer",
 		zap.Stringer("nodeID", nodeID),
 		zap.Uint32("requestID", requestID),
@@ -298,6 +349,14 @@ func (c *networkClient) request(
 	return response, nil
 }
 
+func (c *networkClient) TrackInvalidProof(nodeID ids.NodeID) {
+	c.peers.TrackInvalidProof(nodeID)
+}
+
+func (c *networkClient) PeerStats() PeerStats {
+	return c.peers.Stats()
+}
+
 func (c *networkClient) Connected(
 	_ context.Context,
 	nodeID ids.NodeID,
@@ -321,5 +380,118 @@ func (c *networkClient) Disconnected(_ context.Context, nodeID ids.NodeID) error
 
 	c.log.Debug("disconnecting peer", zap.Stringer("nodeID", nodeID))
 	c.peers.Disconnected(nodeID)
+
+	c.lock.Lock()
+	c.subscribed.Remove(nodeID)
+	c.lock.Unlock()
+	return nil
+}
+
+// If [errAppSendFailed] is returned this should be considered fatal.
+func (c *networkClient) Subscribe(ctx context.Context, nodeID ids.NodeID) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.sendSubscribeRequest(ctx, nodeID, false); err != nil {
+		return err
+	}
+	c.subscribed.Add(nodeID)
+	return nil
+}
+
+// If [errAppSendFailed] is returned this should be considered fatal.
+func (c *networkClient) Unsubscribe(ctx context.Context, nodeID ids.NodeID) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.subscribed.Remove(nodeID)
+	return c.sendSubscribeRequest(ctx, nodeID, true)
+}
+
+// Assumes [c.lock] is held.
+func (c *networkClient) sendSubscribeRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	unsubscribe bool,
+) error {
+	msg, err := proto.Marshal(&pb.Gossip{
+		Message: &pb.Gossip_SubscribeRequest{
+			SubscribeRequest: &pb.SyncSubscribeRequest{
+				Unsubscribe: unsubscribe,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.appSender.SendAppGossipSpecific(ctx, set.Of(nodeID), msg); err != nil {
+		c.log.Fatal(
+			"failed to send subscribe request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Bool("unsubscribe", unsubscribe),
+			zap.Error(err),
+		)
+		return fmt.Errorf("%w: %w", errAppSendFailed, err)
+	}
+	return nil
+}
+
+func (c *networkClient) AppGossip(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	msg []byte,
+) error {
+	var gossip pb.Gossip
+	if err := proto.Unmarshal(msg, &gossip); err != nil {
+		c.log.Debug(
+			"failed to unmarshal AppGossip",
+			zap.Stringer("nodeID", nodeID),
+			zap.Int("msgLen", len(msg)),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	notification, ok := gossip.GetMessage().(*pb.Gossip_RootAdvanced)
+	if !ok {
+		c.log.Debug(
+			"unexpected AppGossip type",
+			zap.Stringer("nodeID", nodeID),
+			zap.Int("msgLen", len(msg)),
+			zap.String("msgType", fmt.Sprintf("%T", gossip.GetMessage())),
+		)
+		return nil
+	}
+
+	c.lock.Lock()
+	subscribed := c.subscribed.Contains(nodeID)
+	c.lock.Unlock()
+	if !subscribed {
+		c.log.Debug(
+			"dropping root advanced notification",
+			zap.Stringer("nodeID", nodeID),
+			zap.String("reason", "not subscribed"),
+		)
+		return nil
+	}
+
+	root, err := ids.ToID(notification.RootAdvanced.GetRootHash())
+	if err != nil {
+		c.log.Debug(
+			"dropping root advanced notification",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	if c.onRootAdvanced != nil {
+		c.onRootAdvanced(ctx, nodeID, RootAdvanced{
+			Root:          root,
+			Height:        notification.RootAdvanced.GetHeight(),
+			NumKeyChanges: notification.RootAdvanced.GetNumKeyChanges(),
+		})
+	}
 	return nil
 }
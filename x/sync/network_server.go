@@ -59,13 +59,24 @@ type NetworkServer struct {
 	appSender common.AppSender // Used to respond to peer requests via AppResponse.
 	db        DB
 	log       logging.Logger
+	// Peers to push root advanced notifications to.
+	subscriptions *subscriptions
 }
 
-func NewNetworkServer(appSender common.AppSender, db DB, log logging.Logger) *NetworkServer {
+// NewNetworkServer returns a NetworkServer that serves proofs of [db] and
+// pushes root advanced notifications to subscribed peers as configured by
+// [notifications].
+func NewNetworkServer(
+	appSender common.AppSender,
+	db DB,
+	log logging.Logger,
+	notifications NotificationConfig,
+) *NetworkServer {
 	return &NetworkServer{
-		appSender: appSender,
-		db:        db,
-		log:       log,
+		appSender:     appSender,
+		db:            db,
+		log:           log,
+		subscriptions: newSubscriptions(notifications),
 	}
 }
 
@@ -138,7 +149,7 @@ func (s *NetworkServer) AppRequest(
 			return err
 		}
 
-		if !isTimeout(err) {
+		if !isContextError(err) {
 			// log unexpected errors instead of returning them, since they are fatal.
 			s.log.Warn(
 				"unexpected error handling AppRequest",
@@ -151,6 +162,94 @@ func (s *NetworkServer) AppRequest(
 	return nil
 }
 
+// AppGossip is called by avalanchego -> VM when there is an incoming AppGossip
+// from a peer. Subscription requests are handled and other messages are
+// dropped.
+// Always returns nil because the engine considers errors
+// returned from this function as fatal.
+func (s *NetworkServer) AppGossip(
+	_ context.Context,
+	nodeID ids.NodeID,
+	msg []byte,
+) error {
+	var gossip pb.Gossip
+	if err := proto.Unmarshal(msg, &gossip); err != nil {
+		s.log.Debug(
+			"failed to unmarshal AppGossip",
+			zap.Stringer("nodeID", nodeID),
+			zap.Int("msgLen", len(msg)),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	req, ok := gossip.GetMessage().(*pb.Gossip_SubscribeRequest)
+	if !ok {
+		s.log.Debug(
+			"unexpected AppGossip type",
+			zap.Stringer("nodeID", nodeID),
+			zap.Int("msgLen", len(msg)),
+			zap.String("msgType", fmt.Sprintf("%T", gossip.GetMessage())),
+		)
+		return nil
+	}
+
+	if req.SubscribeRequest.GetUnsubscribe() {
+		s.subscriptions.unsubscribe(nodeID)
+		return nil
+	}
+	if !s.subscriptions.subscribe(nodeID) {
+		s.log.Debug(
+			"dropping subscription request",
+			zap.Stringer("nodeID", nodeID),
+			zap.String("reason", "too many subscribers"),
+		)
+	}
+	return nil
+}
+
+// Disconnected unsubscribes [nodeID] from root advanced notifications.
+func (s *NetworkServer) Disconnected(_ context.Context, nodeID ids.NodeID) error {
+	s.subscriptions.unsubscribe(nodeID)
+	return nil
+}
+
+// NotifyRootAdvanced pushes [notification] to the subscribed peers, except
+// those that were sent a notification less than
+// [NotificationConfig.MinNotificationInterval] ago.
+// Notifications are only a hint, so peers that miss one still learn about the
+// new root with the next one.
+// If [errAppSendFailed] is returned, this should be considered fatal.
+func (s *NetworkServer) NotifyRootAdvanced(ctx context.Context, notification RootAdvanced) error {
+	nodeIDs := s.subscriptions.notify()
+	if nodeIDs.Len() == 0 {
+		return nil
+	}
+
+	msg, err := proto.Marshal(&pb.Gossip{
+		Message: &pb.Gossip_RootAdvanced{
+			RootAdvanced: &pb.SyncRootAdvanced{
+				RootHash:      notification.Root[:],
+				Height:        notification.Height,
+				NumKeyChanges: notification.NumKeyChanges,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.appSender.SendAppGossipSpecific(ctx, nodeIDs, msg); err != nil {
+		s.log.Fatal(
+			"failed to send root advanced notification",
+			zap.Int("numPeers", nodeIDs.Len()),
+			zap.Error(err),
+		)
+		return fmt.Errorf("%w: %w", errAppSendFailed, err)
+	}
+	return nil
+}
+
 func maybeBytesToMaybe(mb *pb.MaybeBytes) maybe.Maybe[[]byte] {
 	if mb != nil && !mb.IsNothing {
 		return maybe.Some(mb.Value)
@@ -195,14 +294,16 @@ func (s *NetworkServer) HandleChangeProofRequest(
 		return err
 	}
 
+	proofVersion := merkledb.NegotiateProofVersion(req.MaxProofVersion)
 	for keyLimit > 0 {
 		changeProof, err := s.db.GetChangeProof(ctx, startRoot, endRoot, start, end, int(keyLimit))
 		if err != nil {
-			if !errors.Is(err, merkledb.ErrInsufficientHistory) {
+			if !errors.Is(err, merkledb.ErrInsufficientHistory) && !errors.Is(err, merkledb.ErrProofDeadlineExceeded) {
 				return err
 			}
 
-			// [s.db] doesn't have sufficient history to generate change proof.
+			// [s.db] doesn't have sufficient history to generate change proof,
+			// or generating it took too long.
 			// Generate a range proof for the end root ID instead.
 			proofBytes, err := getRangeProof(
 				ctx,
@@ -215,7 +316,7 @@ func (s *NetworkServer) HandleChangeProofRequest(
 					BytesLimit: req.BytesLimit,
 				},
 				func(rangeProof *merkledb.RangeProof) ([]byte, error) {
-					return proto.Marshal(&pb.SyncGetChangeProofResponse{
+					return merkledb.MarshalProof(proofVersion, &pb.SyncGetChangeProofResponse{
 						Response: &pb.SyncGetChangeProofResponse_RangeProof{
 							RangeProof: rangeProof.ToProto(),
 						},
@@ -240,7 +341,7 @@ func (s *NetworkServer) HandleChangeProofRequest(
 		}
 
 		// We generated a change proof. See if it's small enough.
-		proofBytes, err := proto.Marshal(&pb.SyncGetChangeProofResponse{
+		proofBytes, err := merkledb.MarshalProof(proofVersion, &pb.SyncGetChangeProofResponse{
 			Response: &pb.SyncGetChangeProofResponse_ChangeProof{
 				ChangeProof: changeProof.ToProto(),
 			},
@@ -292,12 +393,13 @@ func (s *NetworkServer) HandleRangeProofRequest(
 	req.KeyLimit = math.Min(req.KeyLimit, maxKeyValuesLimit)
 	req.BytesLimit = math.Min(req.BytesLimit, maxByteSizeLimit)
 
+	proofVersion := merkledb.NegotiateProofVersion(req.MaxProofVersion)
 	proofBytes, err := getRangeProof(
 		ctx,
 		s.db,
 		req,
 		func(rangeProof *merkledb.RangeProof) ([]byte, error) {
-			return proto.Marshal(rangeProof.ToProto())
+			return merkledb.MarshalProof(proofVersion, rangeProof.ToProto())
 		},
 	)
 	if err != nil {
@@ -367,17 +469,18 @@ func getRangeProof(
 	return nil, ErrMinProofSizeIsTooLarge
 }
 
-// isTimeout returns true if err is a timeout from a context cancellation
-// or a context cancellation over grpc.
-func isTimeout(err error) bool {
-	// handle grpc wrapped DeadlineExceeded
+// isContextError returns true if err is from a context that timed out or was
+// cancelled, such as when the node is shutting down, directly or over grpc.
+func isContextError(err error) bool {
+	// handle grpc wrapped DeadlineExceeded and Canceled
 	if e, ok := status.FromError(err); ok {
-		if e.Code() == codes.DeadlineExceeded {
+		if code := e.Code(); code == codes.DeadlineExceeded || code == codes.Canceled {
 			return true
 		}
 	}
-	// otherwise, check for context.DeadlineExceeded directly
-	return errors.Is(err, context.DeadlineExceeded)
+	// otherwise, check for context.DeadlineExceeded and context.Canceled
+	// directly
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
 }
 
 // Returns nil iff [req] is well-formed.
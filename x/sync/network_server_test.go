@@ -39,6 +39,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 		expectedErr              error
 		expectedResponseLen      int
 		expectedMaxResponseBytes int
+		expectedProofVersion     merkledb.ProofVersion
 		nodeID                   ids.NodeID
 		proofNil                 bool
 	}{
@@ -93,6 +94,24 @@ func Test_Server_GetRangeProof(t *testing.T) {
 			},
 			expectedMaxResponseBytes: defaultRequestByteSizeLimit,
 		},
+		"versioned proof": {
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash:        smallTrieRoot[:],
+				KeyLimit:        defaultRequestKeyLimit,
+				BytesLimit:      defaultRequestByteSizeLimit,
+				MaxProofVersion: uint32(merkledb.MaxProofVersion),
+			},
+			expectedProofVersion: merkledb.MaxProofVersion,
+		},
+		"unknown proof version": {
+			request: &pb.SyncGetRangeProofRequest{
+				RootHash:        smallTrieRoot[:],
+				KeyLimit:        defaultRequestKeyLimit,
+				BytesLimit:      defaultRequestByteSizeLimit,
+				MaxProofVersion: uint32(merkledb.MaxProofVersion) + 1,
+			},
+			expectedProofVersion: merkledb.MaxProofVersion,
+		},
 	}
 
 	for name, test := range tests {
@@ -100,7 +119,10 @@ func Test_Server_GetRangeProof(t *testing.T) {
 			require := require.New(t)
 			ctrl := gomock.NewController(t)
 			sender := common.NewMockSender(ctrl)
-			var proof *merkledb.RangeProof
+			var (
+				proof        *merkledb.RangeProof
+				proofVersion merkledb.ProofVersion
+			)
 			sender.EXPECT().SendAppResponse(
 				gomock.Any(), // ctx
 				gomock.Any(), // nodeID
@@ -110,8 +132,12 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				func(_ context.Context, _ ids.NodeID, requestID uint32, responseBytes []byte) error {
 					// grab a copy of the proof so we can inspect it later
 					if !test.proofNil {
-						var proofProto pb.RangeProof
-						require.NoError(proto.Unmarshal(responseBytes, &proofProto))
+						var (
+							proofProto pb.RangeProof
+							err        error
+						)
+						proofVersion, err = merkledb.UnmarshalProof(responseBytes, merkledb.MaxProofVersion, &proofProto)
+						require.NoError(err)
 
 						var p merkledb.RangeProof
 						require.NoError(p.UnmarshalProto(&proofProto, merkledb.BranchFactor16))
@@ -120,7 +146,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 					return nil
 				},
 			).AnyTimes()
-			handler := NewNetworkServer(sender, smallTrieDB, logging.NoLog{})
+			handler := NewNetworkServer(sender, smallTrieDB, logging.NoLog{}, NotificationConfig{})
 			err := handler.HandleRangeProofRequest(context.Background(), test.nodeID, 0, test.request)
 			require.ErrorIs(err, test.expectedErr)
 			if test.expectedErr != nil {
@@ -131,6 +157,7 @@ func Test_Server_GetRangeProof(t *testing.T) {
 				return
 			}
 			require.NotNil(proof)
+			require.Equal(test.expectedProofVersion, proofVersion)
 			if test.expectedResponseLen > 0 {
 				require.LessOrEqual(len(proof.KeyValues), test.expectedResponseLen)
 			}
@@ -305,7 +332,7 @@ func Test_Server_GetChangeProof(t *testing.T) {
 				},
 			).AnyTimes()
 
-			handler := NewNetworkServer(sender, trieDB, logging.NoLog{})
+			handler := NewNetworkServer(sender, trieDB, logging.NoLog{}, NotificationConfig{})
 			err := handler.HandleChangeProofRequest(context.Background(), test.nodeID, 0, test.request)
 			require.ErrorIs(err, test.expectedErr)
 			if test.expectedErr != nil {
@@ -388,7 +415,7 @@ func TestAppRequestErrAppSendFailed(t *testing.T) {
 					gomock.Any(),
 				).Return(&merkledb.ChangeProof{}, nil).Times(1)
 
-				return NewNetworkServer(sender, db, logging.NoLog{})
+				return NewNetworkServer(sender, db, logging.NoLog{}, NotificationConfig{})
 			},
 			expectedErr: errAppSendFailed,
 		},
@@ -423,7 +450,7 @@ func TestAppRequestErrAppSendFailed(t *testing.T) {
 					gomock.Any(),
 				).Return(&merkledb.RangeProof{}, nil).Times(1)
 
-				return NewNetworkServer(sender, db, logging.NoLog{})
+				return NewNetworkServer(sender, db, logging.NoLog{}, NotificationConfig{})
 			},
 			expectedErr: errAppSendFailed,
 		},
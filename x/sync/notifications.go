@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// NotificationConfig configures the root advanced notifications that a
+// NetworkServer pushes to subscribed peers.
+type NotificationConfig struct {
+	// The maximum number of subscribed peers.
+	// If 0, subscription requests are dropped and no notifications are sent.
+	MaxSubscribers int
+	// How long a subscription lasts unless the peer renews it.
+	SubscriptionTTL time.Duration
+	// The minimum time between two notifications sent to the same peer.
+	// A root that advances sooner isn't pushed to the peer, which learns about
+	// the change with the next notification it's sent.
+	MinNotificationInterval time.Duration
+}
+
+// RootAdvanced is pushed by a server to the peers subscribed to it when the
+// root of its database advances.
+type RootAdvanced struct {
+	Root   ids.ID
+	Height uint64
+	// The number of keys that changed since the previous root.
+	NumKeyChanges uint64
+}
+
+// RootAdvancedHandler is called with the notifications pushed by the peers
+// that this node subscribed to.
+type RootAdvancedHandler func(ctx context.Context, nodeID ids.NodeID, notification RootAdvanced)
+
+type subscription struct {
+	expiry       time.Time
+	lastNotified time.Time
+}
+
+// subscriptions tracks the peers subscribed to a server's notifications.
+type subscriptions struct {
+	config NotificationConfig
+	clock  mockable.Clock
+
+	lock sync.Mutex
+	// nodeID -> subscription of the peer
+	subscribers map[ids.NodeID]*subscription
+}
+
+func newSubscriptions(config NotificationConfig) *subscriptions {
+	return &subscriptions{
+		config:      config,
+		subscribers: make(map[ids.NodeID]*subscription),
+	}
+}
+
+// subscribe subscribes [nodeID] or renews its subscription.
+// Returns false if [nodeID] isn't subscribed because the maximum number of
+// peers already are.
+func (s *subscriptions) subscribe(nodeID ids.NodeID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	sub, ok := s.subscribers[nodeID]
+	if !ok {
+		if len(s.subscribers) >= s.config.MaxSubscribers {
+			s.removeExpired(now)
+		}
+		if len(s.subscribers) >= s.config.MaxSubscribers {
+			return false
+		}
+		sub = &subscription{}
+		s.subscribers[nodeID] = sub
+	}
+	sub.expiry = now.Add(s.config.SubscriptionTTL)
+	return true
+}
+
+func (s *subscriptions) unsubscribe(nodeID ids.NodeID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.subscribers, nodeID)
+}
+
+// notify returns the subscribed peers that may be sent a notification now and
+// records that they were.
+func (s *subscriptions) notify() set.Set[ids.NodeID] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Time()
+	s.removeExpired(now)
+
+	nodeIDs := set.NewSet[ids.NodeID](len(s.subscribers))
+	for nodeID, sub := range s.subscribers {
+		if !sub.lastNotified.IsZero() && now.Sub(sub.lastNotified) < s.config.MinNotificationInterval {
+			continue
+		}
+		sub.lastNotified = now
+		nodeIDs.Add(nodeID)
+	}
+	return nodeIDs
+}
+
+// Assumes [s.lock] is held.
+func (s *subscriptions) removeExpired(now time.Time) {
+	for nodeID, sub := range s.subscribers {
+		if !now.Before(sub.expiry) {
+			delete(s.subscribers, nodeID)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/mock/gomock"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+func subscribeRequestBytes(t *testing.T, unsubscribe bool) []byte {
+	msg, err := proto.Marshal(&pb.Gossip{
+		Message: &pb.Gossip_SubscribeRequest{
+			SubscribeRequest: &pb.SyncSubscribeRequest{
+				Unsubscribe: unsubscribe,
+			},
+		},
+	})
+	require.NoError(t, err)
+	return msg
+}
+
+func rootAdvancedBytes(t *testing.T, notification RootAdvanced) []byte {
+	msg, err := proto.Marshal(&pb.Gossip{
+		Message: &pb.Gossip_RootAdvanced{
+			RootAdvanced: &pb.SyncRootAdvanced{
+				RootHash:      notification.Root[:],
+				Height:        notification.Height,
+				NumKeyChanges: notification.NumKeyChanges,
+			},
+		},
+	})
+	require.NoError(t, err)
+	return msg
+}
+
+func TestNetworkServerNotifications(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	sender := common.NewMockSender(ctrl)
+	server := NewNetworkServer(
+		sender,
+		merkledb.NewMockMerkleDB(ctrl),
+		logging.NoLog{},
+		NotificationConfig{
+			MaxSubscribers:          2,
+			SubscriptionTTL:         10 * time.Second,
+			MinNotificationInterval: time.Second,
+		},
+	)
+	now := time.Now()
+	server.subscriptions.clock.Set(now)
+
+	notification := RootAdvanced{
+		Root:          ids.GenerateTestID(),
+		Height:        1,
+		NumKeyChanges: 2,
+	}
+	expectNotify := func(expected set.Set[ids.NodeID]) {
+		sender.EXPECT().SendAppGossipSpecific(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, nodeIDs set.Set[ids.NodeID], msg []byte) error {
+				require.Equal(expected, nodeIDs)
+				require.Equal(rootAdvancedBytes(t, notification), msg)
+				return nil
+			},
+		)
+		require.NoError(server.NotifyRootAdvanced(context.Background(), notification))
+	}
+
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+	require.NoError(server.AppGossip(context.Background(), nodeID0, subscribeRequestBytes(t, false)))
+	require.NoError(server.AppGossip(context.Background(), nodeID1, subscribeRequestBytes(t, false)))
+
+	// There are already [MaxSubscribers] subscribers.
+	require.NoError(server.AppGossip(context.Background(), nodeID2, subscribeRequestBytes(t, false)))
+	expectNotify(set.Of(nodeID0, nodeID1))
+
+	// Peers aren't notified again within [MinNotificationInterval].
+	require.NoError(server.NotifyRootAdvanced(context.Background(), notification))
+
+	now = now.Add(time.Second)
+	server.subscriptions.clock.Set(now)
+	require.NoError(server.AppGossip(context.Background(), nodeID1, subscribeRequestBytes(t, true)))
+	expectNotify(set.Of(nodeID0))
+
+	// Once the subscription of [nodeID0] expires, [nodeID2] can subscribe.
+	now = now.Add(9 * time.Second)
+	server.subscriptions.clock.Set(now)
+	require.NoError(server.AppGossip(context.Background(), nodeID2, subscribeRequestBytes(t, false)))
+	expectNotify(set.Of(nodeID2))
+
+	now = now.Add(time.Second)
+	server.subscriptions.clock.Set(now)
+	require.NoError(server.Disconnected(context.Background(), nodeID2))
+	require.NoError(server.NotifyRootAdvanced(context.Background(), notification))
+}
+
+func TestNetworkClientNotifications(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	var received []RootAdvanced
+	sender := common.NewMockSender(ctrl)
+	client, err := NewNetworkClient(
+		sender,
+		ids.GenerateTestNodeID(),
+		1,
+		memdb.New(),
+		logging.NoLog{},
+		func(_ context.Context, _ ids.NodeID, notification RootAdvanced) {
+			received = append(received, notification)
+		},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	nodeID := ids.GenerateTestNodeID()
+	notification := RootAdvanced{
+		Root:          ids.GenerateTestID(),
+		Height:        1,
+		NumKeyChanges: 2,
+	}
+
+	// Notifications of peers that weren't subscribed to are dropped.
+	require.NoError(client.AppGossip(context.Background(), nodeID, rootAdvancedBytes(t, notification)))
+	require.Empty(received)
+
+	sender.EXPECT().SendAppGossipSpecific(gomock.Any(), set.Of(nodeID), subscribeRequestBytes(t, false))
+	require.NoError(client.Subscribe(context.Background(), nodeID))
+	require.NoError(client.AppGossip(context.Background(), nodeID, rootAdvancedBytes(t, notification)))
+	require.Equal([]RootAdvanced{notification}, received)
+
+	sender.EXPECT().SendAppGossipSpecific(gomock.Any(), set.Of(nodeID), subscribeRequestBytes(t, true))
+	require.NoError(client.Unsubscribe(context.Background(), nodeID))
+	require.NoError(client.AppGossip(context.Background(), nodeID, rootAdvancedBytes(t, notification)))
+	require.Len(received, 1)
+}
@@ -4,6 +4,8 @@
 package sync
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/rand"
 	"sync"
@@ -13,11 +15,16 @@ import (
 
 	"go.uber.org/zap"
 
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/heap"
+	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
@@ -34,22 +41,99 @@ const (
 	// The probability that, when we select a peer, we select randomly rather
 	// than based on their performance.
 	randomPeerProbability = 0.2
+
+	// bandwidth + numRequests + numFailures + numInvalidProofs
+	peerScoreLen = 4 * wrappers.LongLen
+
+	// The maximum number of peer scores that are kept. Once exceeded, the
+	// score that was least recently updated is dropped.
+	maxPeerScores = 1024
+
+	// How often updated peer scores are written to disk.
+	peerScoreFlushFrequency = time.Minute
 )
 
+var errInvalidPeerScore = errors.New("invalid peer score")
+
 // information we track on a given peer
 type peerInfo struct {
 	version   *version.Application
 	bandwidth safemath.Averager
 }
 
+// peerScore is the performance of a peer that is persisted across restarts.
+type peerScore struct {
+	// The most recent average bandwidth of the peer's responses.
+	bandwidth float64
+	// The number of requests the peer was sent.
+	numRequests uint64
+	// The number of requests the peer didn't respond to.
+	numFailures uint64
+	// The number of responses that failed to parse or verify.
+	numInvalidProofs uint64
+}
+
+// restoredBandwidth returns the bandwidth to assume for the peer when it
+// connects, discounted by the fraction of requests that the peer didn't
+// serve correctly.
+func (s *peerScore) restoredBandwidth() float64 {
+	numBad := s.numFailures + s.numInvalidProofs
+	if numBad >= s.numRequests {
+		return 0
+	}
+	return s.bandwidth * float64(s.numRequests-numBad) / float64(s.numRequests)
+}
+
+func (s *peerScore) Bytes() []byte {
+	p := wrappers.Packer{
+		Bytes: make([]byte, peerScoreLen),
+	}
+	p.PackLong(math.Float64bits(s.bandwidth))
+	p.PackLong(s.numRequests)
+	p.PackLong(s.numFailures)
+	p.PackLong(s.numInvalidProofs)
+	return p.Bytes
+}
+
+func parsePeerScore(b []byte) (*peerScore, error) {
+	if len(b) != peerScoreLen {
+		return nil, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidPeerScore, peerScoreLen, len(b))
+	}
+	p := wrappers.Packer{Bytes: b}
+	s := &peerScore{
+		bandwidth:        math.Float64frombits(p.UnpackLong()),
+		numRequests:      p.UnpackLong(),
+		numFailures:      p.UnpackLong(),
+		numInvalidProofs: p.UnpackLong(),
+	}
+	if math.IsNaN(s.bandwidth) || math.IsInf(s.bandwidth, 0) || s.bandwidth < 0 {
+		return nil, fmt.Errorf("%w: bandwidth %f", errInvalidPeerScore, s.bandwidth)
+	}
+	return s, p.Err
+}
+
 // Tracks the bandwidth of responses coming from peers,
 // preferring to contact peers with known good bandwidth, connecting
 // to new peers with an exponentially decaying probability.
+//
+// The performance of every peer is persisted so that, after a restart,
+// previously good peers are preferred as soon as they connect.
 type peerTracker struct {
 	// Lock to protect concurrent access to the peer tracker
 	lock sync.Mutex
 	// All peers we are connected to
 	peers map[ids.NodeID]*peerInfo
+	// Stores [scores]
+	db database.Database
+	// The persisted performance of the peers we have sent a request to,
+	// including peers we aren't currently connected to, from the least to
+	// the most recently updated. Holds at most [maxPeerScores] scores.
+	scores linkedhashmap.LinkedHashmap[ids.NodeID, *peerScore]
+	// Scores that were updated or dropped since they were last written to
+	// [db].
+	dirtyScores   set.Set[ids.NodeID]
+	droppedScores set.Set[ids.NodeID]
+	lastFlush     time.Time
 	// Peers that we're connected to that we've sent a request to
 	// since we most recently connected to them.
 	trackedPeers set.Set[ids.NodeID]
@@ -65,12 +149,21 @@ type peerTracker struct {
 }
 
 func newPeerTracker(
+	db database.Database,
 	log logging.Logger,
 	metricsNamespace string,
 	registerer prometheus.Registerer,
 ) (*peerTracker, error) {
+	scores, err := loadPeerScores(db, log)
+	if err != nil {
+		return nil, err
+	}
+
 	t := &peerTracker{
 		peers:           make(map[ids.NodeID]*peerInfo),
+		db:              db,
+		scores:          scores,
+		lastFlush:       time.Now(),
 		trackedPeers:    make(set.Set[ids.NodeID]),
 		responsivePeers: make(set.Set[ids.NodeID]),
 		bandwidthHeap: heap.NewMap[ids.NodeID, safemath.Averager](func(a, b safemath.Averager) bool {
@@ -101,7 +194,7 @@ func newPeerTracker(
 		),
 	}
 
-	err := utils.Err(
+	err = utils.Err(
 		registerer.Register(t.numTrackedPeers),
 		registerer.Register(t.numResponsivePeers),
 		registerer.Register(t.averageBandwidthMetric),
@@ -109,6 +202,130 @@ func newPeerTracker(
 	return t, err
 }
 
+// loadPeerScores returns the peer scores persisted in [db].
+// Scores that can't be parsed are deleted, as are the scores past the first
+// [maxPeerScores].
+func loadPeerScores(db database.Database, log logging.Logger) (linkedhashmap.LinkedHashmap[ids.NodeID, *peerScore], error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	var (
+		scores      = linkedhashmap.New[ids.NodeID, *peerScore]()
+		invalidKeys [][]byte
+	)
+	for it.Next() {
+		key := slices.Clone(it.Key())
+		if scores.Len() >= maxPeerScores {
+			invalidKeys = append(invalidKeys, key)
+			continue
+		}
+
+		nodeID, err := ids.ToNodeID(key)
+		if err != nil {
+			log.Warn("dropping peer score with invalid node ID",
+				zap.Binary("key", key),
+				zap.Error(err),
+			)
+			invalidKeys = append(invalidKeys, key)
+			continue
+		}
+		score, err := parsePeerScore(it.Value())
+		if err != nil {
+			log.Warn("dropping invalid peer score",
+				zap.Stringer("nodeID", nodeID),
+				zap.Error(err),
+			)
+			invalidKeys = append(invalidKeys, key)
+			continue
+		}
+		scores.Put(nodeID, score)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range invalidKeys {
+		if err := db.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+	return scores, nil
+}
+
+// Records that the score of [nodeID] was updated, dropping the least
+// recently updated score if there are more than [maxPeerScores], and writes
+// the updated scores to disk if they weren't written recently.
+// Assumes p.lock is held.
+func (p *peerTracker) updateScore(nodeID ids.NodeID, score *peerScore, now time.Time) {
+	p.scores.Put(nodeID, score)
+	p.dirtyScores.Add(nodeID)
+	p.droppedScores.Remove(nodeID)
+	if p.scores.Len() > maxPeerScores {
+		oldestNodeID, _, _ := p.scores.Oldest()
+		p.scores.Delete(oldestNodeID)
+		p.dirtyScores.Remove(oldestNodeID)
+		p.droppedScores.Add(oldestNodeID)
+	}
+
+	if now.Sub(p.lastFlush) >= peerScoreFlushFrequency {
+		p.flush(now)
+	}
+}
+
+// Writes the scores that were updated or dropped since the last flush to
+// disk in a single batch.
+// Failing to persist scores only loses information, so errors are logged
+// rather than returned.
+// Assumes p.lock is held.
+func (p *peerTracker) flush(now time.Time) {
+	p.lastFlush = now
+	if p.dirtyScores.Len() == 0 && p.droppedScores.Len() == 0 {
+		return
+	}
+
+	batch := p.db.NewBatch()
+	for nodeID := range p.dirtyScores {
+		score, _ := p.scores.Get(nodeID)
+		if err := batch.Put(nodeID.Bytes(), score.Bytes()); err != nil {
+			p.log.Warn("failed to persist peer scores", zap.Error(err))
+			return
+		}
+	}
+	for nodeID := range p.droppedScores {
+		if err := batch.Delete(nodeID.Bytes()); err != nil {
+			p.log.Warn("failed to persist peer scores", zap.Error(err))
+			return
+		}
+	}
+	if err := batch.Write(); err != nil {
+		p.log.Warn("failed to persist peer scores", zap.Error(err))
+		return
+	}
+	p.dirtyScores.Clear()
+	p.droppedScores.Clear()
+}
+
+// Returns the score of [nodeID], creating it if it doesn't exist.
+// Assumes p.lock is held.
+func (p *peerTracker) getScore(nodeID ids.NodeID) *peerScore {
+	score, ok := p.scores.Get(nodeID)
+	if !ok {
+		score = &peerScore{}
+	}
+	return score
+}
+
+// Marks whether [nodeID] responded to the last request it was sent.
+// Assumes p.lock is held.
+func (p *peerTracker) setResponsive(nodeID ids.NodeID, responsive bool) {
+	if responsive {
+		p.responsivePeers.Add(nodeID)
+	} else {
+		p.responsivePeers.Remove(nodeID)
+	}
+	p.numResponsivePeers.Set(float64(p.responsivePeers.Len()))
+}
+
 // Returns true if we're not connected to enough peers.
 // Otherwise returns true probabilistically based on the number of tracked peers.
 // Assumes p.lock is held.
@@ -209,23 +426,57 @@ func (p *peerTracker) TrackBandwidth(nodeID ids.NodeID, bandwidth float64) {
 	}
 
 	now := time.Now()
-	if peer.bandwidth == nil {
-		peer.bandwidth = safemath.NewAverager(bandwidth, bandwidthHalflife, now)
-	} else {
-		peer.bandwidth.Observe(bandwidth, now)
-	}
-	p.bandwidthHeap.Push(nodeID, peer.bandwidth)
+	p.observeBandwidth(nodeID, peer, bandwidth, now)
 
+	score := p.getScore(nodeID)
+	score.bandwidth = peer.bandwidth.Read()
+	score.numRequests++
 	if bandwidth == 0 {
-		p.responsivePeers.Remove(nodeID)
-	} else {
-		p.responsivePeers.Add(nodeID)
+		score.numFailures++
+	}
+	p.updateScore(nodeID, score, now)
+
+	if bandwidth != 0 {
 		// TODO danlaine: shouldn't we add the observation of 0
 		// to the average bandwidth in the if statement?
 		p.averageBandwidth.Observe(bandwidth, now)
 		p.averageBandwidthMetric.Set(p.averageBandwidth.Read())
 	}
-	p.numResponsivePeers.Set(float64(p.responsivePeers.Len()))
+}
+
+// Record that [nodeID] responded with a proof that failed to parse or verify.
+// The response is treated as if the peer hadn't responded.
+func (p *peerTracker) TrackInvalidProof(nodeID ids.NodeID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	peer := p.peers[nodeID]
+	if peer == nil {
+		// we're not connected to this peer, nothing to do here
+		p.log.Debug("tracking invalid proof for untracked peer", zap.Stringer("nodeID", nodeID))
+		return
+	}
+
+	now := time.Now()
+	p.observeBandwidth(nodeID, peer, 0, now)
+
+	score := p.getScore(nodeID)
+	score.bandwidth = peer.bandwidth.Read()
+	score.numInvalidProofs++
+	p.updateScore(nodeID, score, now)
+}
+
+// Adds [bandwidth] to [peer]'s bandwidth averager and updates its position in
+// the bandwidth heap.
+// Assumes p.lock is held.
+func (p *peerTracker) observeBandwidth(nodeID ids.NodeID, peer *peerInfo, bandwidth float64, now time.Time) {
+	if peer.bandwidth == nil {
+		peer.bandwidth = safemath.NewAverager(bandwidth, bandwidthHalflife, now)
+	} else {
+		peer.bandwidth.Observe(bandwidth, now)
+	}
+	p.bandwidthHeap.Push(nodeID, peer.bandwidth)
+	p.setResponsive(nodeID, bandwidth != 0)
 }
 
 // Connected should be called when [nodeID] connects to this node
@@ -235,9 +486,11 @@ func (p *peerTracker) Connected(nodeID ids.NodeID, nodeVersion *version.Applicat
 
 	peer := p.peers[nodeID]
 	if peer == nil {
-		p.peers[nodeID] = &peerInfo{
+		peer = &peerInfo{
 			version: nodeVersion,
 		}
+		p.peers[nodeID] = peer
+		p.restoreScore(nodeID, peer)
 		return
 	}
 
@@ -263,6 +516,30 @@ func (p *peerTracker) Connected(nodeID ids.NodeID, nodeVersion *version.Applicat
 	}
 }
 
+// Seeds the bandwidth of [peer] with its persisted score, if any, so that a
+// peer with a known good score is preferred without first being re-tracked.
+// Assumes p.lock is held.
+func (p *peerTracker) restoreScore(nodeID ids.NodeID, peer *peerInfo) {
+	score, ok := p.scores.Get(nodeID)
+	if !ok {
+		return
+	}
+
+	bandwidth := score.restoredBandwidth()
+	peer.bandwidth = safemath.NewAverager(bandwidth, bandwidthHalflife, time.Now())
+	p.bandwidthHeap.Push(nodeID, peer.bandwidth)
+	p.trackedPeers.Add(nodeID)
+	p.numTrackedPeers.Set(float64(p.trackedPeers.Len()))
+	if bandwidth != 0 {
+		p.setResponsive(nodeID, true)
+	}
+
+	p.log.Debug("restored peer score",
+		zap.Stringer("nodeID", nodeID),
+		zap.Float64("bandwidth", bandwidth),
+	)
+}
+
 // Disconnected should be called when [nodeID] disconnects from this node
 func (p *peerTracker) Disconnected(nodeID ids.NodeID) {
 	p.lock.Lock()
@@ -271,9 +548,12 @@ func (p *peerTracker) Disconnected(nodeID ids.NodeID) {
 	p.bandwidthHeap.Remove(nodeID)
 	p.trackedPeers.Remove(nodeID)
 	p.numTrackedPeers.Set(float64(p.trackedPeers.Len()))
-	p.responsivePeers.Remove(nodeID)
-	p.numResponsivePeers.Set(float64(p.responsivePeers.Len()))
+	p.setResponsive(nodeID, false)
 	delete(p.peers, nodeID)
+
+	// Peers disconnect rarely, so this is a good time to persist the scores
+	// that changed since the last flush.
+	p.flush(time.Now())
 }
 
 // Returns the number of peers the node is connected to.
@@ -283,3 +563,16 @@ func (p *peerTracker) Size() int {
 
 	return len(p.peers)
 }
+
+// Stats returns a summary of the peers the node is connected to.
+func (p *peerTracker) Stats() PeerStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return PeerStats{
+		Connected:        len(p.peers),
+		Tracked:          p.trackedPeers.Len(),
+		Responsive:       p.responsivePeers.Len(),
+		AverageBandwidth: p.averageBandwidth.Read(),
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sync
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func newTestPeerTracker(t *testing.T, db database.Database) *peerTracker {
+	tracker, err := newPeerTracker(db, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	return tracker
+}
+
+func TestPeerScoreBytes(t *testing.T) {
+	require := require.New(t)
+
+	score := &peerScore{
+		bandwidth:        1.5,
+		numRequests:      10,
+		numFailures:      2,
+		numInvalidProofs: 1,
+	}
+	parsed, err := parsePeerScore(score.Bytes())
+	require.NoError(err)
+	require.Equal(score, parsed)
+
+	_, err = parsePeerScore(score.Bytes()[1:])
+	require.ErrorIs(err, errInvalidPeerScore)
+
+	score.bandwidth = math.NaN()
+	_, err = parsePeerScore(score.Bytes())
+	require.ErrorIs(err, errInvalidPeerScore)
+}
+
+func TestPeerScoreRestoredBandwidth(t *testing.T) {
+	tests := []struct {
+		name              string
+		score             peerScore
+		expectedBandwidth float64
+	}{
+		{
+			name:              "no requests",
+			score:             peerScore{bandwidth: 10},
+			expectedBandwidth: 0,
+		},
+		{
+			name: "no failures",
+			score: peerScore{
+				bandwidth:   10,
+				numRequests: 4,
+			},
+			expectedBandwidth: 10,
+		},
+		{
+			name: "failures and invalid proofs",
+			score: peerScore{
+				bandwidth:        10,
+				numRequests:      4,
+				numFailures:      1,
+				numInvalidProofs: 1,
+			},
+			expectedBandwidth: 5,
+		},
+		{
+			name: "only invalid proofs",
+			score: peerScore{
+				bandwidth:        10,
+				numRequests:      2,
+				numInvalidProofs: 2,
+			},
+			expectedBandwidth: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expectedBandwidth, test.score.restoredBandwidth())
+		})
+	}
+}
+
+func TestPeerTrackerPersistsScores(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		db          = memdb.New()
+		goodNodeID  = ids.GenerateTestNodeID()
+		badNodeID   = ids.GenerateTestNodeID()
+		nodeVersion = version.CurrentApp
+	)
+
+	tracker := newTestPeerTracker(t, db)
+	tracker.Connected(goodNodeID, nodeVersion)
+	tracker.Connected(badNodeID, nodeVersion)
+	tracker.TrackPeer(goodNodeID)
+	tracker.TrackBandwidth(goodNodeID, 100)
+	tracker.TrackPeer(badNodeID)
+	tracker.TrackBandwidth(badNodeID, 100)
+	tracker.TrackInvalidProof(badNodeID)
+
+	// Nothing is written until the scores are flushed.
+	it := db.NewIterator()
+	require.False(it.Next())
+	it.Release()
+	tracker.flush(time.Now())
+
+	// Simulate a restart.
+	tracker = newTestPeerTracker(t, db)
+	require.Equal(2, tracker.scores.Len())
+	goodScore, ok := tracker.scores.Get(goodNodeID)
+	require.True(ok)
+	require.Equal(&peerScore{
+		bandwidth:   100,
+		numRequests: 1,
+	}, goodScore)
+	badScore, ok := tracker.scores.Get(badNodeID)
+	require.True(ok)
+	require.Equal(uint64(1), badScore.numInvalidProofs)
+
+	tracker.Connected(goodNodeID, nodeVersion)
+	tracker.Connected(badNodeID, nodeVersion)
+
+	// Both peers are already known, so neither is tracked as a new peer.
+	require.True(tracker.trackedPeers.Contains(goodNodeID))
+	require.True(tracker.trackedPeers.Contains(badNodeID))
+	require.True(tracker.responsivePeers.Contains(goodNodeID))
+	require.False(tracker.responsivePeers.Contains(badNodeID))
+
+	// The previously good peer is preferred.
+	nodeID, _, ok := tracker.bandwidthHeap.Peek()
+	require.True(ok)
+	require.Equal(goodNodeID, nodeID)
+}
+
+func TestPeerTrackerCorruptScore(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		db           = memdb.New()
+		goodNodeID   = ids.GenerateTestNodeID()
+		corruptKey   = ids.GenerateTestNodeID().Bytes()
+		invalidKey   = []byte{0x01}
+		validScore   = &peerScore{bandwidth: 1, numRequests: 1}
+		corruptScore = []byte{0x00}
+	)
+	require.NoError(db.Put(goodNodeID.Bytes(), validScore.Bytes()))
+	require.NoError(db.Put(corruptKey, corruptScore))
+	require.NoError(db.Put(invalidKey, validScore.Bytes()))
+
+	// Invalid entries are skipped rather than failing to start the tracker.
+	tracker := newTestPeerTracker(t, db)
+	require.Equal(1, tracker.scores.Len())
+	score, ok := tracker.scores.Get(goodNodeID)
+	require.True(ok)
+	require.Equal(validScore, score)
+
+	// Invalid entries are removed from disk.
+	has, err := db.Has(corruptKey)
+	require.NoError(err)
+	require.False(has)
+	has, err = db.Has(invalidKey)
+	require.NoError(err)
+	require.False(has)
+}
+
+func TestPeerTrackerMaxScores(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		db          = memdb.New()
+		nodeVersion = version.CurrentApp
+		tracker     = newTestPeerTracker(t, db)
+		nodeIDs     = make([]ids.NodeID, maxPeerScores+1)
+	)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.GenerateTestNodeID()
+		tracker.Connected(nodeIDs[i], nodeVersion)
+		tracker.TrackPeer(nodeIDs[i])
+		tracker.TrackBandwidth(nodeIDs[i], 1)
+		if i == 0 {
+			tracker.flush(time.Now())
+		}
+	}
+	tracker.flush(time.Now())
+
+	// The least recently updated score is dropped, both in memory and on
+	// disk.
+	require.Equal(maxPeerScores, tracker.scores.Len())
+	_, ok := tracker.scores.Get(nodeIDs[0])
+	require.False(ok)
+	has, err := db.Has(nodeIDs[0].Bytes())
+	require.NoError(err)
+	require.False(has)
+
+	for _, nodeID := range nodeIDs[1:] {
+		has, err := db.Has(nodeID.Bytes())
+		require.NoError(err)
+		require.True(has)
+	}
+}
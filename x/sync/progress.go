@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sync
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+var _ health.Checker = (*Manager)(nil)
+
+// PeerStats summarizes the peers that sync requests can be sent to.
+type PeerStats struct {
+	// The number of peers the node is connected to.
+	Connected int `json:"connected"`
+	// The number of connected peers that were sent a request since they
+	// connected.
+	Tracked int `json:"tracked"`
+	// The number of connected peers that responded to the last request they
+	// were sent.
+	Responsive int `json:"responsive"`
+	// The average bandwidth of responses, in bytes per second.
+	AverageBandwidth float64 `json:"averageBandwidth"`
+}
+
+// Progress of a sync.
+type Progress struct {
+	// The root being synced to.
+	TargetRoot ids.ID `json:"targetRoot"`
+	// The number of key ranges that are synced to [TargetRoot].
+	RangesCompleted int `json:"rangesCompleted"`
+	// The number of key ranges that are synced, being synced, or waiting to
+	// be synced.
+	RangesTotal int `json:"rangesTotal"`
+	// The total size of the keys and values in the proofs that were verified
+	// and applied.
+	BytesVerified uint64 `json:"bytesVerified"`
+	// The estimated time until the sync finishes, based on the fraction of
+	// the key space that is synced to [TargetRoot]. 0 if the sync finished or
+	// no key ranges are synced yet.
+	ETA time.Duration `json:"eta"`
+	// True if the sync finished, successfully or not.
+	Done bool `json:"done"`
+	// Nil if [ManagerConfig.NetworkClient] isn't set.
+	Peers *PeerStats `json:"peers,omitempty"`
+}
+
+// Progress returns the progress of the sync.
+func (m *Manager) Progress() Progress {
+	m.syncTargetLock.RLock()
+	defer m.syncTargetLock.RUnlock()
+
+	m.workLock.Lock()
+	defer m.workLock.Unlock()
+
+	progress := Progress{
+		TargetRoot:      m.config.TargetRoot,
+		RangesCompleted: m.processedWork.Len(),
+		RangesTotal:     m.processedWork.Len() + m.unprocessedWork.Len() + m.processingWorkItems,
+		BytesVerified:   m.bytesVerified.Load(),
+	}
+
+	select {
+	case <-m.doneChan:
+		progress.Done = true
+	default:
+		var synced uint64
+		m.processedWork.sortedItems.Ascend(func(item *workItem) bool {
+			synced += keySpaceSize(item.start, item.end)
+			return true
+		})
+		if synced > 0 {
+			progress.ETA = timer.EstimateETA(m.startTime, synced, math.MaxUint64)
+		}
+	}
+
+	if m.config.NetworkClient != nil {
+		peers := m.config.NetworkClient.PeerStats()
+		progress.Peers = &peers
+	}
+	return progress
+}
+
+// HealthCheck returns the progress of the sync. Returns an error if the sync
+// fatally errored.
+func (m *Manager) HealthCheck(context.Context) (interface{}, error) {
+	return m.Progress(), m.Error()
+}
+
+// keySpaceSize returns the size of the range [start, end] when every key is
+// positioned in [0, math.MaxUint64] by its first 8 bytes.
+func keySpaceSize(start, end maybe.Maybe[[]byte]) uint64 {
+	startPosition := timer.ProgressFromHash(start.Value())
+	endPosition := uint64(math.MaxUint64)
+	if end.HasValue() {
+		endPosition = timer.ProgressFromHash(end.Value())
+	}
+	if endPosition < startPosition {
+		return 0
+	}
+	return endPosition - startPosition
+}
+
+// rangeProofSize returns the total size of the keys and values in [proof].
+func rangeProofSize(proof *merkledb.RangeProof) uint64 {
+	var size uint64
+	for _, kv := range proof.KeyValues {
+		size += uint64(len(kv.Key) + len(kv.Value))
+	}
+	return size
+}
+
+// changeProofSize returns the total size of the keys and values in [proof].
+func changeProofSize(proof *merkledb.ChangeProof) uint64 {
+	var size uint64
+	for _, change := range proof.KeyChanges {
+		size += uint64(len(change.Key) + len(change.Value.Value()))
+	}
+	return size
+}
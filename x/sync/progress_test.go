@@ -0,0 +1,172 @@
+// Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+)
+
+func TestProgress(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	now := time.Now().UnixNano()
+	t.Logf("seed: %d", now)
+	r := rand.New(rand.NewSource(now)) // #nosec G404
+	dbToSync, err := generateTrie(t, r, 1000)
+	require.NoError(err)
+	syncRoot, err := dbToSync.GetMerkleRoot(context.Background())
+	require.NoError(err)
+
+	db, err := merkledb.New(
+		context.Background(),
+		memdb.New(),
+		newDefaultDBConfig(),
+	)
+	require.NoError(err)
+
+	peers := PeerStats{
+		Connected:        3,
+		Tracked:          2,
+		Responsive:       1,
+		AverageBandwidth: 1024,
+	}
+	networkClient := NewMockNetworkClient(ctrl)
+	networkClient.EXPECT().PeerStats().Return(peers).AnyTimes()
+
+	syncer, err := NewManager(ManagerConfig{
+		DB:                    db,
+		Client:                newCallthroughSyncClient(ctrl, dbToSync),
+		TargetRoot:            syncRoot,
+		SimultaneousWorkLimit: 5,
+		Log:                   logging.NoLog{},
+		BranchFactor:          merkledb.BranchFactor16,
+		NetworkClient:         networkClient,
+	})
+	require.NoError(err)
+
+	progress := syncer.Progress()
+	require.Equal(syncRoot, progress.TargetRoot)
+	require.Zero(progress.RangesTotal)
+	require.Zero(progress.BytesVerified)
+	require.Zero(progress.ETA)
+	require.False(progress.Done)
+	require.Equal(&peers, progress.Peers)
+
+	require.NoError(syncer.Start(context.Background()))
+	require.NoError(syncer.Wait(context.Background()))
+
+	var size uint64
+	it := dbToSync.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		size += uint64(len(it.Key()) + len(it.Value()))
+	}
+	require.NoError(it.Error())
+
+	result, err := syncer.HealthCheck(context.Background())
+	require.NoError(err)
+	progress = result.(Progress)
+	require.Equal(syncRoot, progress.TargetRoot)
+	require.Positive(progress.RangesCompleted)
+	require.Equal(progress.RangesCompleted, progress.RangesTotal)
+	require.GreaterOrEqual(progress.BytesVerified, size)
+	require.Zero(progress.ETA)
+	require.True(progress.Done)
+	require.Equal(&peers, progress.Peers)
+}
+
+func TestProgressError(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	errFailed := errors.New("failed")
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRangeProof(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, *pb.SyncGetRangeProofRequest) (*merkledb.RangeProof, error) {
+			return nil, errFailed
+		},
+	).AnyTimes()
+
+	db, err := merkledb.New(
+		context.Background(),
+		memdb.New(),
+		newDefaultDBConfig(),
+	)
+	require.NoError(err)
+	syncer, err := NewManager(ManagerConfig{
+		DB:                    db,
+		Client:                client,
+		SimultaneousWorkLimit: 1,
+		Log:                   logging.NoLog{},
+		BranchFactor:          merkledb.BranchFactor16,
+	})
+	require.NoError(err)
+
+	require.NoError(syncer.Start(context.Background()))
+	err = syncer.Wait(context.Background())
+	require.ErrorIs(err, errFailed)
+
+	result, err := syncer.HealthCheck(context.Background())
+	require.ErrorIs(err, errFailed)
+	progress := result.(Progress)
+	require.True(progress.Done)
+	require.Zero(progress.BytesVerified)
+	require.Nil(progress.Peers)
+}
+
+func TestKeySpaceSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    maybe.Maybe[[]byte]
+		end      maybe.Maybe[[]byte]
+		expected uint64
+	}{
+		{
+			name:     "entire key space",
+			start:    maybe.Nothing[[]byte](),
+			end:      maybe.Nothing[[]byte](),
+			expected: math.MaxUint64,
+		},
+		{
+			name:     "first half",
+			start:    maybe.Nothing[[]byte](),
+			end:      maybe.Some([]byte{0x80}),
+			expected: 1 << 63,
+		},
+		{
+			name:     "second half",
+			start:    maybe.Some([]byte{0x80}),
+			end:      maybe.Nothing[[]byte](),
+			expected: 1<<63 - 1,
+		},
+		{
+			name:     "keys with the same first 8 bytes",
+			start:    maybe.Some([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9}),
+			end:      maybe.Some([]byte{1, 2, 3, 4, 5, 6, 7, 8, 10}),
+			expected: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, keySpaceSize(test.start, test.end))
+		})
+	}
+}
@@ -91,6 +91,20 @@ func Test_Creation(t *testing.T) {
 	require.NotNil(syncer)
 }
 
+func Test_Creation_InvalidConfig(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewManager(ManagerConfig{
+		TargetRoot:   ids.Empty,
+		Log:          logging.NoLog{},
+		BranchFactor: merkledb.BranchFactor16,
+	})
+	// Every problem with the config is reported.
+	require.ErrorIs(err, ErrNoClientProvided)
+	require.ErrorIs(err, ErrNoDatabaseProvided)
+	require.ErrorIs(err, ErrZeroWorkLimit)
+}
+
 func Test_Completion(t *testing.T) {
 	require := require.New(t)
 	ctrl := gomock.NewController(t)